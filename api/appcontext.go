@@ -2,6 +2,8 @@ package api
 
 import (
 	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // AppContext holds immutable application metadata.
@@ -17,6 +19,20 @@ type AppContext struct {
 	Namespace string // default installation namespace
 	Short     string // short description for CLI
 	Long      string // long description for CLI
+
+	ProtectedConfig  []string                            // configuration fields managed by the application
+	SensitiveConfig  []string                            // configuration fields stored in a secret
+	ConfigBackend    string                              // configuration storage, "configmap" or "crd"
+	Executor         string                              // deploy executor, "helm", "helm-binary" or "flux"
+	Settings         []Setting                           // configuration settings known by the application
+	ConfigMigrations map[int]func(root *yaml.Node) error // configuration upgrades, by source version
+	CapacityRules    []CapacityRule                      // configuration recommendations per cluster capacity
+	DiscoveryRules   []DiscoveryRule                     // configuration defaults per cluster attributes
+	ConfigTransforms []ConfigTransformFn                 // configuration invariants, applied on load and save
+	ConfigDefaults   ConfigDefaults                      // configuration defaults, layered on the embedded configuration
+	ConfigEnvAllow   []string                            // environment variables configuration files may expand, patterns
+	ValueReferences  []string                            // cluster Secrets and ConfigMaps the values template may reference, patterns
+	Checkers         []CheckerModule                     // product verification checks
 }
 
 // ContextOption is a functional option for configuring AppContext.
@@ -57,6 +73,117 @@ func WithLongDescription(long string) ContextOption {
 	}
 }
 
+// WithProtectedConfig marks configuration fields as managed by the application,
+// the "config" subcommand and the MCP tools reject changes on them. Fields are
+// dot separated paths relative to the configuration root key, for instance
+// "settings.crc", "products.Foundation.enabled" or "products.Foundation".
+// Product names containing dots are selected by name, as on the configuration
+// paths, "products[name=Hub 1.2].enabled".
+func WithProtectedConfig(fields ...string) ContextOption {
+	return func(a *AppContext) {
+		a.ProtectedConfig = append(a.ProtectedConfig, fields...)
+	}
+}
+
+// WithSensitiveConfig marks configuration fields as sensitive, like passwords,
+// their values are stored in a Secret companion to the configuration ConfigMap
+// and redacted when the configuration is displayed. Fields are dot separated
+// paths relative to the configuration root key, for instance
+// "settings.smtp.password" or "products.Developer Hub.properties.smtpPassword".
+func WithSensitiveConfig(fields ...string) ContextOption {
+	return func(a *AppContext) {
+		a.SensitiveConfig = append(a.SensitiveConfig, fields...)
+	}
+}
+
+// WithSettings registers the configuration settings known by the application,
+// with their type, default, allowed values and description. The "config
+// settings" subcommand and the MCP tools only accept the registered settings,
+// and every configuration update is checked against them. For instance:
+//
+//	api.WithSettings(api.Setting{
+//		Key:         "crc",
+//		Type:        api.SettingBool,
+//		Default:     false,
+//		Description: "Deploy on a CodeReady Containers cluster",
+//	})
+func WithSettings(settings ...Setting) ContextOption {
+	return func(a *AppContext) {
+		a.Settings = append(a.Settings, settings...)
+	}
+}
+
+// WithConfigMigration registers the function upgrading the configuration from
+// the informed version to the next, for instance from version 1 to 2. The
+// function receives the configuration root key node and changes it in place.
+// Configuration stored in the cluster with an older version is migrated when
+// loaded, and the upgraded document persisted. Payloads without "version" are
+// considered version 1.
+func WithConfigMigration(from int, fn func(root *yaml.Node) error) ContextOption {
+	return func(a *AppContext) {
+		if a.ConfigMigrations == nil {
+			a.ConfigMigrations = map[int]func(root *yaml.Node) error{}
+		}
+		a.ConfigMigrations[from] = fn
+	}
+}
+
+// WithCapacityRules registers the rules recommending configuration changes for
+// the cluster capacity. While the cluster awaits configuration, the MCP status
+// tool inspects the cluster nodes and reports the recommendations of every
+// rule, for the assistant to present before creating the configuration. For
+// instance:
+//
+//	api.WithCapacityRules(func(c api.ClusterCapacity) string {
+//		if c.CPUCores() < 16 {
+//			return `disable "Product D" on clusters with less than 16 vCPU`
+//		}
+//		return ""
+//	})
+func WithCapacityRules(rules ...CapacityRule) ContextOption {
+	return func(a *AppContext) {
+		a.CapacityRules = append(a.CapacityRules, rules...)
+	}
+}
+
+// WithDiscoveryRules registers the rules pre-populating the configuration for
+// the cluster attributes: capacity, GPUs, storage classes, OpenShift Data
+// Foundation and ingress domain. When the configuration is created with cluster
+// discovery, "config --create --discover" or the MCP init tool, the defaults
+// returned are layered with the application defaults, the user's configuration
+// still prevails. For instance:
+//
+//	api.WithDiscoveryRules(func(d api.ClusterDiscovery) api.ConfigDefaults {
+//		if d.DefaultStorageClass == "" {
+//			return nil
+//		}
+//		return api.ConfigDefaults{
+//			"products[name=Product B].properties.storageClass": d.DefaultStorageClass,
+//		}
+//	})
+func WithDiscoveryRules(rules ...DiscoveryRule) ContextOption {
+	return func(a *AppContext) {
+		a.DiscoveryRules = append(a.DiscoveryRules, rules...)
+	}
+}
+
+// WithCheckers registers the product specific verification checks, run by the
+// "verify" subcommand and, once the installation is complete, reported by the
+// MCP status tool. The end-to-end test checkers can be registered as well. For
+// instance:
+//
+//	api.WithCheckers(api.CheckerModule{
+//		Name: "product-a-api",
+//		Init: func(kube k8s.Interface, cfg *config.Config) api.Checker {
+//			return NewProductAChecker(kube, cfg)
+//		},
+//	})
+func WithCheckers(checkers ...CheckerModule) ContextOption {
+	return func(a *AppContext) {
+		a.Checkers = append(a.Checkers, checkers...)
+	}
+}
+
 // IdentifierName returns the application name suitable for programmatic
 // identifiers, replacing hyphens with underscores.
 func (a *AppContext) IdentifierName() string {
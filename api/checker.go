@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+)
+
+// Checker validates a product specific aspect of the installation on the
+// cluster, the same contract as the end-to-end test checkers. Checkers are run
+// by the "verify" subcommand and the MCP status tool, see WithCheckers.
+type Checker interface {
+	Check(ctx context.Context) CheckResult
+}
+
+// CheckResult the outcome of a checker validation.
+type CheckResult struct {
+	Passed  bool   // true if validation succeeded
+	Message string // descriptive message (error details if Passed=false)
+}
+
+// CheckerModule registers a checker under a name, the checker is instantiated
+// on every run with the installer Kubernetes client and the cluster
+// configuration.
+type CheckerModule struct {
+	// Name identifies the check on the reports, e.g. "product-a-api".
+	Name string
+
+	// Init creates the checker instance.
+	Init func(k8s.Interface, *config.Config) Checker
+}
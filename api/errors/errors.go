@@ -0,0 +1,103 @@
+// Package errors defines the failure classes of the installer, exported for
+// downstream applications to tell the failures apart with "errors.Is", instead
+// of matching error messages. For instance:
+//
+//	if errors.Is(err, helmeterrors.ErrConfigNotFound) {
+//		// create the cluster configuration first
+//	}
+//
+// Every sentinel error declared by the framework belongs to one of the classes,
+// the specific sentinel and its class are both matched by "errors.Is". Errors
+// are wrapped with "%w" as they're returned by the subcommands and MCP tools,
+// thus the class is kept regardless of the context added.
+package errors
+
+import (
+	"errors"
+)
+
+// Failure classes.
+var (
+	// ErrInvalidUsage the command arguments or flags are invalid.
+	ErrInvalidUsage = errors.New("invalid usage")
+	// ErrConfigNotFound the cluster configuration is not created yet.
+	ErrConfigNotFound = errors.New("configuration not found")
+	// ErrInvalidConfig the configuration, stored or informed, is invalid, or the
+	// change to it is not allowed.
+	ErrInvalidConfig = errors.New("invalid configuration")
+	// ErrConfigDrift the cluster configuration differs from the expected.
+	ErrConfigDrift = errors.New("configuration drift")
+	// ErrConflict the resource changed concurrently, or already exists.
+	ErrConflict = errors.New("conflict")
+	// ErrTopologyUnresolved the dependency topology can't be resolved from the
+	// charts and the configuration.
+	ErrTopologyUnresolved = errors.New("topology unresolved")
+	// ErrPrerequisitesMissing the integrations required by the dependencies are
+	// not configured.
+	ErrPrerequisitesMissing = errors.New("prerequisites missing")
+	// ErrInvalidIntegration the integration credentials or settings are invalid.
+	ErrInvalidIntegration = errors.New("invalid integration")
+	// ErrDeployFailed a dependency failed to render, install or verify.
+	ErrDeployFailed = errors.New("deploy failed")
+	// ErrClusterUnreachable the Kubernetes API is not reachable, or the
+	// workloads didn't report back in time.
+	ErrClusterUnreachable = errors.New("cluster unreachable")
+	// ErrCancelled the operation was cancelled by the user.
+	ErrCancelled = errors.New("cancelled")
+)
+
+// classes the failure classes, in precedence order, see Class.
+var classes = []error{
+	ErrInvalidUsage,
+	ErrConfigNotFound,
+	ErrInvalidConfig,
+	ErrConfigDrift,
+	ErrConflict,
+	ErrTopologyUnresolved,
+	ErrPrerequisitesMissing,
+	ErrInvalidIntegration,
+	ErrDeployFailed,
+	ErrClusterUnreachable,
+	ErrCancelled,
+}
+
+// classified an error belonging to a failure class, its message is the error's.
+type classified struct {
+	err   error // original error
+	class error // failure class
+}
+
+// Error returns the original error message.
+func (c *classified) Error() string {
+	return c.err.Error()
+}
+
+// Unwrap exposes the original error and the failure class to "errors.Is".
+func (c *classified) Unwrap() []error {
+	return []error{c.err, c.class}
+}
+
+// New returns a sentinel error of the failure class, with the message as
+// informed.
+func New(class error, message string) error {
+	return &classified{err: errors.New(message), class: class}
+}
+
+// Wrap classifies the error, keeping its message. Nil and errors already
+// classified are returned as is.
+func Wrap(class, err error) error {
+	if err == nil || Class(err) != nil {
+		return err
+	}
+	return &classified{err: err, class: class}
+}
+
+// Class returns the failure class of the error, nil when it's not classified.
+func Class(err error) error {
+	for _, class := range classes {
+		if errors.Is(err, class) {
+			return class
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestErrors(t *testing.T) {
+	errNotFound := New(ErrConfigNotFound, "cluster configmap not found")
+
+	t.Run("New", func(t *testing.T) {
+		g := o.NewWithT(t)
+		err := fmt.Errorf("%w: using label selector", errNotFound)
+		g.Expect(err.Error()).To(o.Equal(
+			"cluster configmap not found: using label selector"))
+		g.Expect(errors.Is(err, errNotFound)).To(o.BeTrue())
+		g.Expect(errors.Is(err, ErrConfigNotFound)).To(o.BeTrue())
+		g.Expect(errors.Is(err, ErrInvalidConfig)).To(o.BeFalse())
+		g.Expect(Class(err)).To(o.Equal(ErrConfigNotFound))
+	})
+
+	t.Run("Wrap", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(Wrap(ErrCancelled, nil)).To(o.Succeed())
+
+		err := Wrap(ErrCancelled, errors.New("upgrade cancelled"))
+		g.Expect(err.Error()).To(o.Equal("upgrade cancelled"))
+		g.Expect(errors.Is(err, ErrCancelled)).To(o.BeTrue())
+
+		// Errors already classified keep their class.
+		err = Wrap(ErrInvalidUsage, errNotFound)
+		g.Expect(err).To(o.Equal(errNotFound))
+		g.Expect(errors.Is(err, ErrInvalidUsage)).To(o.BeFalse())
+	})
+
+	t.Run("Class", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(Class(errors.New("unknown"))).To(o.Succeed())
+		g.Expect(Class(nil)).To(o.Succeed())
+	})
+}
@@ -14,6 +14,26 @@ type IntegrationContext interface {
 	GetProductNamespace(productName string) (string, error)
 }
 
+// ExternalDomainContext is implemented by the IntegrationContext supplied by
+// the framework, kept apart so existing IntegrationContext implementations
+// remain valid. Providers should use ExternalDomain instead of asserting it.
+type ExternalDomainContext interface {
+	// GetExternalDomain returns the domain reachable by external services, like
+	// GitHub webhooks: the "externalURLs.domain" setting when informed, for
+	// clusters behind a reverse proxy, otherwise the OpenShift ingress domain.
+	GetExternalDomain(ctx context.Context) (string, error)
+}
+
+// ExternalDomain returns the domain reachable by external services, see
+// ExternalDomainContext, or the OpenShift ingress domain when the context
+// doesn't implement it.
+func ExternalDomain(ctx context.Context, ic IntegrationContext) (string, error) {
+	if edc, ok := ic.(ExternalDomainContext); ok {
+		return edc.GetExternalDomain(ctx)
+	}
+	return ic.GetOpenShiftIngressDomain(ctx)
+}
+
 // URLProvider supplies URLs (callback for authentication, homepage, webhook).
 // Used with framework.WithURLProvider. Implementations receive an
 // IntegrationContext to derive URLs from cluster/config without importing internal.
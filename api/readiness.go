@@ -0,0 +1,87 @@
+package api
+
+import "context"
+
+// Phase the installation phase computed from the cluster state.
+type Phase string
+
+const (
+	// PhaseAwaitingConfiguration the cluster is not configured yet.
+	PhaseAwaitingConfiguration Phase = "AWAITING_CONFIGURATION"
+	// PhaseAwaitingIntegrations the cluster doesn't have the required
+	// integrations configured yet.
+	PhaseAwaitingIntegrations Phase = "AWAITING_INTEGRATIONS"
+	// PhaseReadyToDeploy the cluster is configured and has all required
+	// integrations in place.
+	PhaseReadyToDeploy Phase = "READY_TO_DEPLOY"
+	// PhaseDeploying the installer job is deploying the dependencies, or the
+	// last deployment attempt failed.
+	PhaseDeploying Phase = "DEPLOYING"
+	// PhaseCompleted the installation is complete, and the cluster is ready.
+	PhaseCompleted Phase = "COMPLETED"
+	// PhaseInstallerError the cluster configuration, or the installer state,
+	// can't be determined.
+	PhaseInstallerError Phase = "INSTALLER_ERROR"
+)
+
+// ConditionStatus the status of a readiness condition.
+type ConditionStatus string
+
+const (
+	// ConditionTrue the condition is met.
+	ConditionTrue ConditionStatus = "True"
+	// ConditionFalse the condition is not met.
+	ConditionFalse ConditionStatus = "False"
+	// ConditionUnknown the condition wasn't evaluated, a previous one is not met.
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// Readiness condition types, evaluated in order.
+const (
+	// ConditionConfigured the cluster configuration exists.
+	ConditionConfigured = "Configured"
+	// ConditionIntegrationsReady the topology resolves, all required
+	// integrations are in place.
+	ConditionIntegrationsReady = "IntegrationsReady"
+	// ConditionDeployed the installer job completed successfully.
+	ConditionDeployed = "Deployed"
+)
+
+// Condition a single aspect of the installation readiness.
+type Condition struct {
+	Type    string          // condition type
+	Status  ConditionStatus // condition status
+	Reason  string          // machine readable reason, CamelCase
+	Message string          // human readable details
+}
+
+// ReadinessStatus the installation phase and the conditions it derives from.
+type ReadinessStatus struct {
+	Phase      Phase       // current installation phase
+	Conditions []Condition // conditions, in evaluation order
+}
+
+// Ready returns whether the installation is complete.
+func (r *ReadinessStatus) Ready() bool {
+	return r.Phase == PhaseCompleted
+}
+
+// Condition returns the condition by type, or nil when absent.
+func (r *ReadinessStatus) Condition(conditionType string) *Condition {
+	for i := range r.Conditions {
+		if r.Conditions[i].Type == conditionType {
+			return &r.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// Readiness computes the installation readiness from the cluster state, using
+// the same logic as the "status" MCP tool. Applications embedding the installer
+// in services or operators use it to gate their own behavior.
+type Readiness interface {
+	// Status inspects the cluster and returns the current readiness. The error
+	// is only returned when the cluster can't be inspected, unmet conditions
+	// are reported on the status.
+	Status(context.Context) (*ReadinessStatus, error)
+}
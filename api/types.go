@@ -1,13 +1,64 @@
 package api
 
 import (
+	"context"
 	"log/slog"
 
+	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/integration"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
 )
 
+// ValuesContextFn computes additional values for the values template rendering
+// context, available as ".Context" in the template. It's invoked once per
+// command execution, with the cluster configuration.
+type ValuesContextFn func(
+	context.Context,
+	*runcontext.RunContext,
+	*config.Config,
+) (map[string]any, error)
+
+// Setting describes a configuration setting known by the application, see
+// WithSettings.
+type Setting = config.Setting
+
+// SettingType the type of a setting value.
+type SettingType = config.SettingType
+
+// Setting value types.
+const (
+	SettingBool   = config.SettingBool
+	SettingString = config.SettingString
+	SettingInt    = config.SettingInt
+	SettingNumber = config.SettingNumber
+)
+
+// ConfigTransformFn enforces application invariants on the configuration,
+// changing it in place, see framework.WithConfigTransform.
+type ConfigTransformFn = config.Transform
+
+// ConfigDefaults the application configuration defaults, values by
+// configuration path, layered between the installer's embedded configuration and
+// the user's, see framework.WithConfigDefaults.
+type ConfigDefaults = config.Defaults
+
+// ClusterCapacity the cluster size and capabilities, informed to the capacity
+// rules, see WithCapacityRules.
+type ClusterCapacity = k8s.ClusterCapacity
+
+// CapacityRule inspects the cluster capacity and returns a recommendation for
+// the configuration, or empty when it doesn't apply.
+type CapacityRule func(ClusterCapacity) string
+
+// ClusterDiscovery the cluster attributes informed to the discovery rules, see
+// WithDiscoveryRules.
+type ClusterDiscovery = k8s.ClusterDiscovery
+
+// DiscoveryRule returns the configuration defaults for the cluster attributes,
+// empty when none apply.
+type DiscoveryRule = config.DiscoveryRule
+
 // IntegrationModule defines the contract for a pluggable integration.
 // It encapsulates both the integration business logic (integration.Interface) and
 // the CLI representation (SubCommand).
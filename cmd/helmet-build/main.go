@@ -0,0 +1,40 @@
+// Command helmet-build assembles the installer tarball from the installer
+// directory, meant to be used with "go:generate", for instance:
+//
+//	//go:generate go run github.com/redhat-appstudio/helmet/cmd/helmet-build -app-name helmet-ex -dir . -output installer.tar
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/framework/build"
+)
+
+func main() {
+	appName := flag.String("app-name", "", "Application name (required)")
+	dir := flag.String("dir", ".", "Installer directory")
+	output := flag.String("output", "installer.tar", "Tarball file path")
+	exclude := flag.String("exclude", "",
+		"Comma-separated file name patterns to exclude from the tarball")
+	flag.Parse()
+
+	if *appName == "" {
+		fmt.Fprintln(os.Stderr, "Error: -app-name is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := []build.Option{}
+	if *exclude != "" {
+		opts = append(opts, build.WithExclude(strings.Split(*exclude, ",")...))
+	}
+	b := build.NewBuilder(api.NewAppContext(*appName), *dir, opts...)
+	if err := b.WriteFile(*output); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
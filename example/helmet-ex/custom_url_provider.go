@@ -5,10 +5,12 @@ import (
 	"fmt"
 
 	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/api/integrations"
 )
 
 // CustomURLProvider implements integrations.URLProvider by building
-// GitHub App URLs from the cluster's OpenShift ingress domain.
+// GitHub App URLs from the cluster's externally visible domain, the OpenShift
+// ingress domain unless the "externalURLs.domain" setting is informed.
 type CustomURLProvider struct{}
 
 // GetCallbackURL is not used in this example.
@@ -16,9 +18,9 @@ func (CustomURLProvider) GetCallbackURL(_ context.Context, _ api.IntegrationCont
 	return "", nil
 }
 
-// GetHomepageURL showcases how to derive the homepage URL from the product configuration and the ingress domain.
+// GetHomepageURL showcases how to derive the homepage URL from the product configuration and the external domain.
 func (CustomURLProvider) GetHomepageURL(ctx context.Context, ic api.IntegrationContext) (string, error) {
-	ingressDomain, err := ic.GetOpenShiftIngressDomain(ctx)
+	ingressDomain, err := integrations.ExternalDomain(ctx, ic)
 	if err != nil {
 		return "", fmt.Errorf("ingress domain unavailable (non-OpenShift cluster); "+
 			"provide --homepage-url explicitly: %w", err)
@@ -30,9 +32,9 @@ func (CustomURLProvider) GetHomepageURL(ctx context.Context, ic api.IntegrationC
 	return fmt.Sprintf("https://ui-%s.%s", namespace, ingressDomain), nil
 }
 
-// GetWebhookURL showcases how to derive the webhook URL from the ingress domain.
+// GetWebhookURL showcases how to derive the webhook URL from the external domain.
 func (CustomURLProvider) GetWebhookURL(ctx context.Context, ic api.IntegrationContext) (string, error) {
-	ingressDomain, err := ic.GetOpenShiftIngressDomain(ctx)
+	ingressDomain, err := integrations.ExternalDomain(ctx, ic)
 	if err != nil {
 		return "", fmt.Errorf("ingress domain unavailable (non-OpenShift cluster); "+
 			"provide --webhook-url explicitly: %w", err)
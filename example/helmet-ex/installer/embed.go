@@ -9,6 +9,7 @@ import _ "embed"
 // The tarball contains:
 //   - config.yaml: Default configuration schema
 //   - values.yaml.tpl: Go template for Helm values rendering
+//   - platforms.yaml: Supported Kubernetes and OpenShift versions
 //   - charts/: All Helm charts demonstrating the framework topology
 //
 //nolint:typecheck,nolintlint // installer.tar is generated at build time
@@ -60,6 +60,42 @@ func createAppContext() *api.AppContext {
 		api.WithCommitID(commitID),
 		api.WithNamespace("helmet-ex-system"),
 		api.WithShortDescription("Helmet Framework Example Application"),
+		api.WithSettings(api.Setting{
+			Key:         "crc",
+			Type:        api.SettingBool,
+			Default:     false,
+			Description: "Deploy on a CodeReady Containers (OpenShift Local) cluster",
+		}, api.Setting{
+			Key:         "ci.debug",
+			Type:        api.SettingBool,
+			Default:     false,
+			Description: "Enable debug logging on the continuous integration",
+		}),
+		api.WithCapacityRules(func(c api.ClusterCapacity) string {
+			if c.CRC {
+				return `CodeReady Containers detected, set the "crc" setting to true`
+			}
+			return ""
+		}, func(c api.ClusterCapacity) string {
+			if c.CPUCores() < 16 {
+				return `disable "Product D" on clusters with less than 16 vCPU`
+			}
+			return ""
+		}),
+		api.WithDiscoveryRules(func(d api.ClusterDiscovery) api.ConfigDefaults {
+			return api.ConfigDefaults{"settings.crc": d.CRC}
+		}, func(d api.ClusterDiscovery) api.ConfigDefaults {
+			if d.DefaultStorageClass == "" {
+				return nil
+			}
+			return api.ConfigDefaults{
+				"products[name=Product B].properties.storageClass": d.DefaultStorageClass,
+			}
+		}),
+		api.WithCheckers(api.CheckerModule{
+			Name: "product-namespaces",
+			Init: NewNamespacesChecker,
+		}),
 		api.WithLongDescription(`A comprehensive example demonstrating all Helmet framework features.
 
 This example application showcases:
@@ -67,6 +103,7 @@ This example application showcases:
 - Embedded tarball filesystem with overlay support for local development
 - Standard integration modules (GitHub, GitLab, Quay, ACS, etc.)
 - MCP server with AI assistant instructions
+- Product verification checks, run by "verify"
 - Configuration management via test/config.yaml
 - Template rendering via test/values.yaml.tpl
 - Helm chart dependency resolution and deployment
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespacesChecker showcases a product verification check, asserting the
+// namespaces of the enabled products exist on the cluster.
+type NamespacesChecker struct {
+	kube k8s.Interface  // kubernetes client
+	cfg  *config.Config // cluster configuration
+}
+
+var _ api.Checker = &NamespacesChecker{}
+
+// Check looks up the namespace of every enabled product.
+func (n *NamespacesChecker) Check(ctx context.Context) api.CheckResult {
+	missing := []string{}
+	for _, product := range n.cfg.GetEnabledProducts() {
+		namespace := product.GetNamespace()
+		if namespace == "" {
+			continue
+		}
+		client, err := n.kube.CoreV1ClientSet(namespace)
+		if err != nil {
+			return api.CheckResult{Message: err.Error()}
+		}
+		if _, err = client.Namespaces().Get(
+			ctx, namespace, metav1.GetOptions{},
+		); err != nil {
+			missing = append(missing, namespace)
+		}
+	}
+	if len(missing) > 0 {
+		return api.CheckResult{Message: fmt.Sprintf(
+			"product namespaces not found: %s", strings.Join(missing, ", "))}
+	}
+	return api.CheckResult{Passed: true, Message: "product namespaces found"}
+}
+
+// NewNamespacesChecker instantiates the checker, registered with
+// api.WithCheckers.
+func NewNamespacesChecker(kube k8s.Interface, cfg *config.Config) api.Checker {
+	return &NamespacesChecker{kube: kube, cfg: cfg}
+}
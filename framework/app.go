@@ -2,14 +2,23 @@ package framework
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/buildinfo"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/deployer"
 	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/installer"
 	"github.com/redhat-appstudio/helmet/internal/integrations"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 	"github.com/redhat-appstudio/helmet/internal/mcptools"
+	"github.com/redhat-appstudio/helmet/internal/policy"
+	"github.com/redhat-appstudio/helmet/internal/readiness"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
 	"github.com/redhat-appstudio/helmet/internal/subcmd"
 
@@ -30,8 +39,11 @@ type App struct {
 	kube               *k8s.Kube               // kubernetes client
 
 	mcpToolsBuilder  mcptools.MCPToolsBuilder // tools builder
+	mcpToolFilter    mcptools.ToolFilter      // exposed tools filter
+	policy           *policy.Policy           // operations policy
 	mcpImage         string                   // installer image
 	installerTarball []byte                   // embedded installer tarball
+	valuesContextFn  api.ValuesContextFn      // values template context
 }
 
 // Command exposes the Cobra command.
@@ -44,6 +56,63 @@ func (a *App) Run() error {
 	return a.rootCmd.Execute()
 }
 
+// Readiness returns the installation readiness for the embedding application,
+// computing the same phase and conditions reported by the "status" tool. It
+// allows services and operators embedding the installer to gate their own
+// behavior on the installation state.
+func (a *App) Readiness() (api.Readiness, error) {
+	tb, err := resolver.NewTopologyBuilder(
+		a.AppCtx, a.flags.GetLogger(io.Discard), a.ChartFS, a.integrationManager,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create topology builder: %w", err)
+	}
+	cm := config.NewConfigMapManager(a.kube, a.AppCtx.Name)
+	cm.SetBackend(a.AppCtx.ConfigBackend)
+	cm.SetMigrations(a.AppCtx.ConfigMigrations)
+	cm.SetTransforms(config.Transforms(a.AppCtx.ConfigTransforms))
+	return readiness.NewReadiness(
+		cm,
+		tb,
+		installer.NewJob(a.AppCtx, a.kube),
+	), nil
+}
+
+// enforcePolicy loads the operations policy and asserts the command is allowed,
+// the help and shell completion commands are always allowed.
+func (a *App) enforcePolicy(
+	cmd *cobra.Command,
+	runCtx *runcontext.RunContext,
+) error {
+	command := strings.TrimSpace(
+		strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()))
+	first, _, _ := strings.Cut(command, " ")
+	switch first {
+	case "", "help", "completion", cobra.ShellCompRequestCmd,
+		cobra.ShellCompNoDescRequestCmd:
+		return nil
+	}
+	var err error
+	if a.policy, err = subcmd.LoadPolicy(
+		cmd.Context(), a.AppCtx, runCtx,
+	); err != nil {
+		return err
+	}
+	return a.policy.Allow(command, policy.InJob())
+}
+
+// toolFilter hides the MCP tools denied by the operations policy, and applies
+// the application filter on the others, see WithMCPToolFilter.
+func (a *App) toolFilter(name string) string {
+	if a.policy != nil && !a.policy.AllowTool(name) {
+		return ""
+	}
+	if a.mcpToolFilter != nil {
+		return a.mcpToolFilter(name)
+	}
+	return name
+}
+
 // setupRootCmd instantiates the Cobra Root command with subcommand, description,
 // Kubernetes API client instance and more.
 func (a *App) setupRootCmd() error {
@@ -65,6 +134,13 @@ func (a *App) setupRootCmd() error {
 	// Handle version flag and help.
 	a.rootCmd.RunE = func(cmd *cobra.Command, _ []string) error {
 		if a.flags.Version {
+			// With verbose output, the build provenance support asks for.
+			if a.flags.Verbose {
+				return buildinfo.Collect(
+					a.AppCtx.Name, a.AppCtx.Version, a.AppCtx.CommitID,
+					a.ChartFS, a.mcpImage,
+				).Print(cmd.OutOrStdout())
+			}
 			a.flags.ShowVersion(
 				a.AppCtx.Name, a.AppCtx.Version, a.AppCtx.CommitID)
 			return nil
@@ -75,6 +151,17 @@ func (a *App) setupRootCmd() error {
 	logger := a.flags.GetLogger(os.Stdout)
 	runCtx := runcontext.NewRunContext(a.kube, a.ChartFS, logger)
 
+	// Flags not informed on the command line are set from their environment
+	// variables, for every subcommand, before its Complete. Then the operations
+	// policy is enforced on the subcommand.
+	binder := flags.NewBinder(a.AppCtx.Name)
+	a.rootCmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		if err := binder.Bind(cmd); err != nil {
+			return err
+		}
+		return a.enforcePolicy(cmd, runCtx)
+	}
+
 	// Loading informed integrations into the manager.
 	a.integrationManager = integrations.NewManager()
 	if err := a.integrationManager.LoadModules(
@@ -87,6 +174,20 @@ func (a *App) setupRootCmd() error {
 	a.rootCmd.AddCommand(subcmd.NewIntegration(
 		a.AppCtx, runCtx, a.integrationManager, a.flags,
 	))
+	a.rootCmd.AddCommand(subcmd.NewCEL(
+		a.AppCtx, runCtx, a.flags, a.integrationManager,
+	))
+	a.rootCmd.AddCommand(subcmd.NewScaffold(a.AppCtx, runCtx, a.flags))
+	a.rootCmd.AddCommand(subcmd.NewSBOM(
+		a.AppCtx, runCtx, a.flags, a.installerTarball, a.valuesContextFn,
+	))
+	a.rootCmd.AddCommand(subcmd.NewSchema(a.AppCtx, runCtx, a.flags))
+	a.rootCmd.AddCommand(subcmd.NewSnapshot(
+		a.AppCtx, runCtx, a.flags, a.integrationManager,
+	))
+	a.rootCmd.AddCommand(subcmd.NewValues(
+		a.AppCtx, runCtx, a.flags, a.installerTarball, a.valuesContextFn,
+	))
 
 	// Use default builder if none provided.
 	mcpBuilder := a.mcpToolsBuilder
@@ -102,12 +203,19 @@ func (a *App) setupRootCmd() error {
 
 	// Other subcommands via api.Runner.
 	subs := []api.SubCommand{
+		subcmd.NewCleanup(a.AppCtx, runCtx, a.flags),
 		subcmd.NewConfig(a.AppCtx, runCtx, a.flags),
-		subcmd.NewDeploy(a.AppCtx, runCtx, a.flags, a.integrationManager, a.installerTarball),
+		subcmd.NewDeploy(a.AppCtx, runCtx, a.flags, a.integrationManager, a.installerTarball, a.valuesContextFn),
 		subcmd.NewInstaller(a.AppCtx, runCtx, a.flags, a.installerTarball),
-		subcmd.NewMCPServer(a.AppCtx, runCtx, a.flags, a.integrationManager, mcpBuilder, a.mcpImage),
-		subcmd.NewTemplate(a.AppCtx, runCtx, a.flags, a.installerTarball),
+		subcmd.NewLogs(a.AppCtx, runCtx, a.flags),
+		subcmd.NewMCPServer(a.AppCtx, runCtx, a.flags, a.integrationManager, mcpBuilder, a.toolFilter, a.mcpImage, a.installerTarball, a.valuesContextFn),
+		subcmd.NewRepair(a.AppCtx, runCtx, a.flags, a.integrationManager, a.installerTarball, a.valuesContextFn),
+		subcmd.NewReplicate(a.AppCtx, runCtx, a.flags),
+		subcmd.NewScan(a.AppCtx, runCtx, a.flags, a.installerTarball, a.valuesContextFn),
+		subcmd.NewStatus(a.AppCtx, runCtx, a.flags),
+		subcmd.NewTemplate(a.AppCtx, runCtx, a.flags, a.installerTarball, a.valuesContextFn),
 		subcmd.NewTopology(a.AppCtx, runCtx),
+		subcmd.NewVerify(a.AppCtx, runCtx, a.flags),
 	}
 	for _, sub := range subs {
 		a.rootCmd.AddCommand(api.NewRunner(sub).Cmd())
@@ -136,6 +244,34 @@ func NewApp(
 		opt(app)
 	}
 
+	protected := config.ProtectedFields(appCtx.ProtectedConfig)
+	if err := protected.Validate(); err != nil {
+		return nil, err
+	}
+	sensitive := config.SensitiveFields(appCtx.SensitiveConfig)
+	if err := sensitive.Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.Migrations(appCtx.ConfigMigrations).Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.ValidateBackend(appCtx.ConfigBackend); err != nil {
+		return nil, err
+	}
+	if err := deployer.ValidateExecutor(appCtx.Executor); err != nil {
+		return nil, err
+	}
+	if err := config.SettingRegistry(appCtx.Settings).Validate(); err != nil {
+		return nil, err
+	}
+	if err := config.Defaults(appCtx.ConfigDefaults).Validate(); err != nil {
+		return nil, err
+	}
+	allowlist := config.ValueRefAllowlist(appCtx.ValueReferences)
+	if err := allowlist.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Initialize Kube client with flags
 	app.kube = k8s.NewKube(app.flags)
 
@@ -0,0 +1,301 @@
+// Package build assembles the installer tarball, embedded by the applications
+// built with the framework, from a local installer directory.
+package build
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/constants"
+	"github.com/redhat-appstudio/helmet/internal/platform"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+)
+
+// IntegrityFilename is the integrity manifest added to the tarball, it contains
+// the SHA-256 checksum of every other file in the tarball.
+const IntegrityFilename = "integrity.sha256"
+
+// DefaultExclude file name patterns excluded from the tarball by default.
+var DefaultExclude = []string{"*.go", "*.tar", IntegrityFilename}
+
+var (
+	// ErrInvalidInstaller the installer directory is invalid.
+	ErrInvalidInstaller = errors.New("invalid installer directory")
+	// ErrIntegrity the tarball contents don't match the integrity manifest.
+	ErrIntegrity = errors.New("integrity verification failed")
+)
+
+// Builder assembles the installer tarball from the installer directory, with
+// the configuration, values template and Helm charts.
+type Builder struct {
+	appCtx  *api.AppContext // application context
+	dir     string          // installer directory
+	exclude []string        // file name patterns to exclude
+}
+
+// Option represents a functional option for the Builder.
+type Option func(*Builder)
+
+// WithExclude appends file name patterns to exclude from the tarball, patterns
+// follow "path.Match" syntax and are matched against the file base name.
+func WithExclude(patterns ...string) Option {
+	return func(b *Builder) {
+		b.exclude = append(b.exclude, patterns...)
+	}
+}
+
+// excluded checks whether the file name matches the exclude patterns.
+func (b *Builder) excluded(name string) bool {
+	for _, pattern := range b.exclude {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// walk lists the regular files in the directory recursively, following symbolic
+// links. The file paths are relative to the installer directory, using forward
+// slashes. A directory linked more than once is listed under every path, only
+// the links back to an ancestor directory are skipped, preventing loops.
+func (b *Builder) walk(dir string, ancestors map[string]bool) ([]string, error) {
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	// Preventing symbolic link loops.
+	if ancestors[realDir] {
+		return nil, nil
+	}
+	ancestors[realDir] = true
+	defer delete(ancestors, realDir)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for _, entry := range entries {
+		p := filepath.Join(dir, entry.Name())
+		// Following symbolic links, using the target file information.
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			children, err := b.walk(p, ancestors)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, children...)
+			continue
+		}
+		if !info.Mode().IsRegular() || b.excluded(entry.Name()) {
+			continue
+		}
+		rel, err := filepath.Rel(b.dir, p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, filepath.ToSlash(rel))
+	}
+	return files, nil
+}
+
+// Files returns the sorted list of files included in the tarball.
+func (b *Builder) Files() ([]string, error) {
+	files, err := b.walk(b.dir, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// Validate asserts the installer directory contains the framework's required
+// files, the configuration is valid, the Helm charts annotations are valid and
+// so are the supported platforms, when declared.
+func (b *Builder) Validate() error {
+	for _, name := range []string{
+		constants.ConfigFilename,
+		constants.ValuesFilename,
+	} {
+		if _, err := os.Stat(filepath.Join(b.dir, name)); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidInstaller, err)
+		}
+	}
+
+	cfs := chartfs.New(os.DirFS(b.dir))
+	_, err := config.NewConfigFromFile(
+		cfs,
+		constants.ConfigFilename,
+		b.appCtx.Namespace,
+		b.appCtx.IdentifierName(),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidInstaller, err)
+	}
+	charts, err := cfs.GetAllCharts()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidInstaller, err)
+	}
+	if _, err = resolver.NewCollection(b.appCtx, charts); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidInstaller, err)
+	}
+	if _, err = platform.Load(cfs); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidInstaller, err)
+	}
+	return nil
+}
+
+// writeFile writes a regular file entry on the tarball. The modification time
+// is fixed, so the tarball is reproducible.
+func writeFile(tw *tar.Writer, name string, mode fs.FileMode, payload []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     int64(mode.Perm()),
+		Size:     int64(len(payload)),
+		ModTime:  time.Unix(0, 0),
+		Format:   tar.FormatPAX,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(payload)
+	return err
+}
+
+// Write validates the installer directory and writes the tarball, including the
+// integrity manifest, on the informed writer.
+func (b *Builder) Write(w io.Writer) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	files, err := b.Files()
+	if err != nil {
+		return err
+	}
+
+	tw := tar.NewWriter(w)
+	var manifest bytes.Buffer
+	for _, name := range files {
+		p := filepath.Join(b.dir, filepath.FromSlash(name))
+		info, err := os.Stat(p)
+		if err != nil {
+			return err
+		}
+		payload, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if err = writeFile(tw, name, info.Mode(), payload); err != nil {
+			return fmt.Errorf("writing %q: %w", name, err)
+		}
+		sum := sha256.Sum256(payload)
+		fmt.Fprintf(&manifest, "%s  %s\n", hex.EncodeToString(sum[:]), name)
+	}
+	err = writeFile(tw, IntegrityFilename, 0o644, manifest.Bytes())
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// WriteFile writes the tarball on the informed file path, the file is only
+// replaced when the tarball is successfully generated.
+func (b *Builder) WriteFile(name string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".installer-*.tar")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = b.Write(tmp); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// Verify checks the tarball files against its integrity manifest.
+func Verify(tarball []byte) error {
+	sums := map[string]string{}
+	var manifest []byte
+	tr := tar.NewReader(bytes.NewReader(tarball))
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		payload, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimPrefix(path.Clean(header.Name), "./")
+		if name == IntegrityFilename {
+			manifest = payload
+			continue
+		}
+		sum := sha256.Sum256(payload)
+		sums[name] = hex.EncodeToString(sum[:])
+	}
+	if manifest == nil {
+		return fmt.Errorf("%w: %q not found", ErrIntegrity, IntegrityFilename)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(manifest)), "\n") {
+		sum, name, found := strings.Cut(line, "  ")
+		if !found {
+			return fmt.Errorf("%w: invalid manifest line %q", ErrIntegrity, line)
+		}
+		actual, exists := sums[name]
+		if !exists {
+			return fmt.Errorf("%w: %q is missing", ErrIntegrity, name)
+		}
+		if actual != sum {
+			return fmt.Errorf("%w: %q checksum mismatch", ErrIntegrity, name)
+		}
+		delete(sums, name)
+	}
+	for name := range sums {
+		return fmt.Errorf("%w: %q is not in the manifest", ErrIntegrity, name)
+	}
+	return nil
+}
+
+// NewBuilder instantiates a Builder for the installer directory.
+func NewBuilder(appCtx *api.AppContext, dir string, opts ...Option) *Builder {
+	b := &Builder{
+		appCtx:  appCtx,
+		dir:     dir,
+		exclude: slices.Clone(DefaultExclude),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
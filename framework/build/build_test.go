@@ -0,0 +1,98 @@
+package build
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/framework"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestBuilder(t *testing.T) {
+	g := o.NewWithT(t)
+
+	appCtx := api.NewAppContext("helmet-ex")
+	b := NewBuilder(appCtx, "../../test", WithExclude("*.sh"))
+
+	t.Run("Files", func(t *testing.T) {
+		files, err := b.Files()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(files).To(o.ContainElements(
+			"config.yaml",
+			"values.yaml.tpl",
+			"platforms.yaml",
+			"charts/helmet-product-a/Chart.yaml",
+		))
+		for _, f := range files {
+			g.Expect(f).NotTo(o.HaveSuffix(".go"))
+			g.Expect(f).NotTo(o.HaveSuffix(".sh"))
+		}
+	})
+
+	var tarball bytes.Buffer
+	g.Expect(b.Write(&tarball)).To(o.Succeed())
+
+	t.Run("Reproducible", func(t *testing.T) {
+		var again bytes.Buffer
+		g.Expect(b.Write(&again)).To(o.Succeed())
+		g.Expect(again.Bytes()).To(o.Equal(tarball.Bytes()))
+	})
+
+	t.Run("TarFS", func(t *testing.T) {
+		tfs, err := framework.NewTarFS(tarball.Bytes())
+		g.Expect(err).To(o.Succeed())
+		cfs := chartfs.New(tfs)
+		_, err = cfs.ReadFile("config.yaml")
+		g.Expect(err).To(o.Succeed())
+		charts, err := cfs.GetAllCharts()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(charts).NotTo(o.BeEmpty())
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		g.Expect(Verify(tarball.Bytes())).To(o.Succeed())
+
+		// Tampering with the tarball contents, replacing a known value.
+		tampered := bytes.Replace(
+			tarball.Bytes(), []byte("helmet_ex:"), []byte("helmet_xx:"), 1)
+		g.Expect(tampered).NotTo(o.Equal(tarball.Bytes()))
+		g.Expect(Verify(tampered)).To(o.MatchError(ErrIntegrity))
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		invalid := NewBuilder(api.NewAppContext("unknown"), "../../test")
+		g.Expect(invalid.Validate()).To(o.MatchError(ErrInvalidInstaller))
+	})
+}
+
+func TestBuilderSymlinks(t *testing.T) {
+	g := o.NewWithT(t)
+
+	// A library chart shared by two products, and a link back to the root.
+	dir := t.TempDir()
+	library := filepath.Join(dir, "library")
+	g.Expect(os.MkdirAll(library, 0o755)).To(o.Succeed())
+	g.Expect(os.WriteFile(filepath.Join(library, "Chart.yaml"), nil, 0o600)).
+		To(o.Succeed())
+	installer := filepath.Join(dir, "installer")
+	for _, product := range []string{"product-a", "product-b"} {
+		charts := filepath.Join(installer, "charts", product, "charts")
+		g.Expect(os.MkdirAll(charts, 0o755)).To(o.Succeed())
+		g.Expect(os.Symlink(library, filepath.Join(charts, "library"))).
+			To(o.Succeed())
+	}
+	g.Expect(os.Symlink(installer, filepath.Join(installer, "loop"))).
+		To(o.Succeed())
+
+	files, err := NewBuilder(api.NewAppContext("helmet-ex"), installer).Files()
+	g.Expect(err).To(o.Succeed())
+	g.Expect(files).To(o.Equal([]string{
+		"charts/product-a/charts/library/Chart.yaml",
+		"charts/product-b/charts/library/Chart.yaml",
+	}))
+}
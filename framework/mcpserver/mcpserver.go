@@ -1,14 +1,25 @@
 package mcpserver
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/mcptools"
 
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// InstructionsFn generates the server instructions when a client initializes
+// the session, it receives the server to inspect the registered tools.
+type InstructionsFn func(context.Context, *server.MCPServer) string
+
 type MCPServer struct {
-	s *server.MCPServer // mcp server instance
+	s     *server.MCPServer // mcp server instance
+	hooks *server.Hooks     // mcp server hooks
 }
 
 func (m *MCPServer) AddTools(tools ...mcptools.Interface) {
@@ -17,17 +28,99 @@ func (m *MCPServer) AddTools(tools ...mcptools.Interface) {
 	}
 }
 
+// ErrToolNameConflict the tool filter renames a tool to an existing name.
+var ErrToolNameConflict = errors.New("tool name conflict")
+
+// FilterTools applies the filter on the registered tools, hiding or renaming
+// them before the server starts.
+func (m *MCPServer) FilterTools(filter mcptools.ToolFilter) error {
+	registered := m.s.ListTools()
+	names := make([]string, 0, len(registered))
+	for name := range registered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	exposed := map[string]string{}
+	tools := []server.ServerTool{}
+	for _, name := range names {
+		newName := filter(name)
+		if newName == "" {
+			continue
+		}
+		if original, ok := exposed[newName]; ok {
+			return fmt.Errorf("%w: %q and %q exposed as %q",
+				ErrToolNameConflict, original, name, newName)
+		}
+		exposed[newName] = name
+
+		tool := *registered[name]
+		tool.Tool.Name = newName
+		tools = append(tools, tool)
+	}
+	m.s.SetTools(tools...)
+	return nil
+}
+
+// ErrToolNotFound the informed tool is not registered.
+var ErrToolNotFound = errors.New("tool not found")
+
+// InjectFailures replaces the handler of the informed tools, by name, with one
+// failing deterministically with the informed error message. Meant for testing
+// the error paths, tools are looked up by their exposed name.
+func (m *MCPServer) InjectFailures(failures map[string]string) error {
+	names := make([]string, 0, len(failures))
+	for name := range failures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	registered := m.s.ListTools()
+	for _, name := range names {
+		tool, ok := registered[name]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrToolNotFound, name)
+		}
+		message := failures[name]
+		m.s.AddTool(tool.Tool, func(
+			context.Context,
+			mcp.CallToolRequest,
+		) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError(message), nil
+		})
+	}
+	return nil
+}
+
+// SetInstructionsFn replaces the static instructions by the informed function
+// output, generated for every client session.
+func (m *MCPServer) SetInstructionsFn(fn InstructionsFn) {
+	m.hooks.AddAfterInitialize(func(
+		ctx context.Context,
+		_ any,
+		_ *mcp.InitializeRequest,
+		result *mcp.InitializeResult,
+	) {
+		result.Instructions = fn(ctx, m.s)
+	})
+}
+
 func (m *MCPServer) Start() error {
 	return server.ServeStdio(m.s)
 }
 
 func NewMCPServer(appCtx *api.AppContext, instructions string) *MCPServer {
-	return &MCPServer{s: server.NewMCPServer(
-		appCtx.Name,
-		appCtx.Version,
-		server.WithToolCapabilities(true),
-		server.WithPromptCapabilities(true),
-		server.WithLogging(),
-		server.WithInstructions(instructions),
-	)}
+	hooks := &server.Hooks{}
+	return &MCPServer{
+		s: server.NewMCPServer(
+			appCtx.Name,
+			appCtx.Version,
+			server.WithToolCapabilities(true),
+			server.WithPromptCapabilities(true),
+			server.WithLogging(),
+			server.WithInstructions(instructions),
+			server.WithHooks(hooks),
+		),
+		hooks: hooks,
+	}
 }
@@ -0,0 +1,107 @@
+package mcpserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	o "github.com/onsi/gomega"
+)
+
+// fakeTools registers a no-op tool for each name.
+type fakeTools []string
+
+func (f fakeTools) Init(s *server.MCPServer) {
+	for _, name := range f {
+		s.AddTool(mcp.NewTool(name), func(
+			context.Context,
+			mcp.CallToolRequest,
+		) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText(name), nil
+		})
+	}
+}
+
+func TestFilterTools(t *testing.T) {
+	g := o.NewWithT(t)
+
+	newServer := func() *MCPServer {
+		m := NewMCPServer(api.NewAppContext("helmet-ex"), "")
+		m.AddTools(fakeTools{
+			"helmet-ex_config_get",
+			"helmet-ex_deploy",
+			"helmet-ex_status",
+		})
+		return m
+	}
+
+	t.Run("HideAndRename", func(t *testing.T) {
+		m := newServer()
+		err := m.FilterTools(func(name string) string {
+			switch name {
+			case "helmet-ex_deploy":
+				return ""
+			case "helmet-ex_status":
+				return "installation_status"
+			}
+			return name
+		})
+		g.Expect(err).To(o.Succeed())
+
+		tools := m.s.ListTools()
+		g.Expect(tools).To(o.HaveLen(2))
+		g.Expect(tools).To(o.HaveKey("helmet-ex_config_get"))
+		g.Expect(tools).To(o.HaveKey("installation_status"))
+		g.Expect(tools["installation_status"].Tool.Name).
+			To(o.Equal("installation_status"))
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		m := newServer()
+		// Dropping the application prefix is fine, unless two tools end up
+		// sharing the same name.
+		err := m.FilterTools(func(name string) string {
+			return strings.TrimPrefix(name, "helmet-ex_")
+		})
+		g.Expect(err).To(o.Succeed())
+
+		err = m.FilterTools(func(name string) string {
+			if name == "deploy" {
+				return "status"
+			}
+			return name
+		})
+		g.Expect(err).To(o.MatchError(ErrToolNameConflict))
+	})
+}
+
+func TestInjectFailures(t *testing.T) {
+	g := o.NewWithT(t)
+
+	m := NewMCPServer(api.NewAppContext("helmet-ex"), "")
+	m.AddTools(fakeTools{"helmet-ex_deploy", "helmet-ex_status"})
+
+	err := m.InjectFailures(map[string]string{"helmet-ex_unknown": "boom"})
+	g.Expect(err).To(o.MatchError(ErrToolNotFound))
+
+	err = m.InjectFailures(map[string]string{
+		"helmet-ex_deploy": "simulated deploy failure",
+	})
+	g.Expect(err).To(o.Succeed())
+
+	call := func(name string) *mcp.CallToolResult {
+		result, err := m.s.GetTool(name).Handler(
+			context.Background(), mcp.CallToolRequest{})
+		g.Expect(err).To(o.Succeed())
+		return result
+	}
+	result := call("helmet-ex_deploy")
+	g.Expect(result.IsError).To(o.BeTrue())
+	g.Expect(result.Content).To(o.ConsistOf(
+		mcp.NewTextContent("simulated deploy failure")))
+	g.Expect(call("helmet-ex_status").IsError).To(o.BeFalse())
+}
@@ -1,7 +1,11 @@
 package framework
 
 import (
+	"maps"
+
 	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/deployer"
 	"github.com/redhat-appstudio/helmet/internal/mcptools"
 )
 
@@ -31,6 +35,118 @@ func WithMCPToolsBuilder(builder mcptools.MCPToolsBuilder) Option {
 	}
 }
 
+// WithMCPToolFilter sets the filter deciding which MCP tools are exposed, and
+// under which name. The filter receives every registered tool name, including
+// the application prefix (e.g. "helmet-ex_deploy"), and returns the name to
+// expose the tool with, or an empty string to hide it.
+func WithMCPToolFilter(filter mcptools.ToolFilter) Option {
+	return func(a *App) {
+		a.mcpToolFilter = filter
+	}
+}
+
+// WithValuesContext sets the function to inject application computed values, for
+// instance cloud metadata or licensing information, into the values template
+// rendering context as ".Context".
+func WithValuesContext(fn api.ValuesContextFn) Option {
+	return func(a *App) {
+		a.valuesContextFn = fn
+	}
+}
+
+// Configuration backends, the cluster resource storing the installer
+// configuration, see WithConfigBackend.
+const (
+	// ConfigMapBackend stores the configuration in a ConfigMap, the default.
+	ConfigMapBackend = config.BackendConfigMap
+	// CustomResourceBackend stores the configuration in a HelmetInstallation
+	// custom resource, installing its definition when the configuration is
+	// created. The resource offers typed status, RBAC rules of its own and
+	// watches unrelated to other ConfigMaps.
+	CustomResourceBackend = config.BackendCRD
+)
+
+// WithConfigBackend sets where the installer configuration is stored in the
+// cluster, ConfigMapBackend or CustomResourceBackend.
+func WithConfigBackend(backend string) Option {
+	return func(a *App) {
+		a.AppCtx.ConfigBackend = backend
+	}
+}
+
+// Deploy executors, the engine deploying the dependency releases, see
+// WithExecutor.
+const (
+	// HelmExecutor deploys with the Helm SDK, in-process, the default.
+	HelmExecutor = deployer.ExecutorHelm
+	// HelmBinaryExecutor deploys with the external "helm" binary, for the
+	// environments mandating the Helm CLI; the binary must be available on the
+	// installer image for the MCP deployments.
+	HelmBinaryExecutor = deployer.ExecutorHelmBinary
+	// FluxExecutor emits Flux HelmRelease manifests instead of deploying, for
+	// the GitOps controller to reconcile them.
+	FluxExecutor = deployer.ExecutorFlux
+)
+
+// WithExecutor sets the default deploy executor, HelmExecutor,
+// HelmBinaryExecutor or FluxExecutor, overridden by "deploy --executor".
+func WithExecutor(executor string) Option {
+	return func(a *App) {
+		a.AppCtx.Executor = executor
+	}
+}
+
+// WithConfigTransform registers a function enforcing application invariants on
+// the installer configuration, for instance enabling the foundation products or
+// normalizing the namespaces. Transforms run in registration order after the
+// configuration is loaded, from the cluster or a local file, and before it's
+// saved, so every subcommand and MCP tool observes them. They must be
+// idempotent, and the transformed configuration must remain valid.
+func WithConfigTransform(fn api.ConfigTransformFn) Option {
+	return func(a *App) {
+		a.AppCtx.ConfigTransforms = append(a.AppCtx.ConfigTransforms, fn)
+	}
+}
+
+// WithConfigDefaults sets application defaults for the installer configuration,
+// values by configuration path, as used with "config set", for instance
+// "settings.crc" or "products[name=Product B].properties.replicas". The
+// defaults are layered on top of the configuration embedded on the installer
+// tarball, and the user's configuration file is merged on top of them; use
+// "config explain" to find out where a value comes from. Subsequent calls add
+// to the defaults.
+func WithConfigDefaults(defaults api.ConfigDefaults) Option {
+	return func(a *App) {
+		if a.AppCtx.ConfigDefaults == nil {
+			a.AppCtx.ConfigDefaults = api.ConfigDefaults{}
+		}
+		maps.Copy(a.AppCtx.ConfigDefaults, defaults)
+	}
+}
+
+// WithConfigEnvAllowlist restricts the environment variables expanded on the
+// configuration files, with "--expand-env", to the names matching the informed
+// shell patterns, for instance "HELMET_*", so a configuration file can't read
+// unrelated variables, like cloud credentials, into the cluster configuration.
+// Variables informed with "--set-env" are always expanded. Without an
+// allowlist every variable may be expanded.
+func WithConfigEnvAllowlist(patterns ...string) Option {
+	return func(a *App) {
+		a.AppCtx.ConfigEnvAllow = append(a.AppCtx.ConfigEnvAllow, patterns...)
+	}
+}
+
+// WithValueReferences allowlists the cluster Secrets and ConfigMaps the values
+// template may reference with "secretRef:" and "configMapRef:" placeholders, as
+// "secret:<namespace>/<name>" or "configMap:<namespace>/<name>", shell globs
+// accepted, for instance "configMap:openshift-config/*". The "valueReferences"
+// setting may only narrow the allowlist. Without it no reference is allowed.
+func WithValueReferences(entries ...string) Option {
+	return func(a *App) {
+		a.AppCtx.ValueReferences = append(a.AppCtx.ValueReferences, entries...)
+	}
+}
+
 // WithInstallerTarball sets the embedded installer tarball for the application.
 func WithInstallerTarball(tarball []byte) Option {
 	return func(a *App) {
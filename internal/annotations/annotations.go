@@ -7,11 +7,72 @@ const RepoURI = "helmet.redhat-appstudio.github.com"
 // Annotation keys for Helm chart metadata.
 const (
 	ProductName          = RepoURI + "/product-name"
+	ProductRequires      = RepoURI + "/product-requires"
+	ProductConflicts     = RepoURI + "/product-conflicts"
 	DependsOn            = RepoURI + "/depends-on"
 	Weight               = RepoURI + "/weight"
 	UseProductNamespace  = RepoURI + "/use-product-namespace"
 	IntegrationsProvided = RepoURI + "/integrations-provided"
 	IntegrationsRequired = RepoURI + "/integrations-required"
 	PostDeploy           = RepoURI + "/post-deploy"
+	NoHooks              = RepoURI + "/no-hooks"
+	HooksTimeout         = RepoURI + "/hooks-timeout"
+	HookDeletePolicy     = RepoURI + "/hook-delete-policy"
+	NamespacePolicy      = RepoURI + "/namespace-policy"
 	Config               = RepoURI + "/config"
+	ReleaseNotes         = RepoURI + "/release-notes"
+	BreakingChanges      = RepoURI + "/breaking-changes"
+	PropertiesSchema     = RepoURI + "/properties-schema"
+	Downscale            = RepoURI + "/downscale"
+	License              = RepoURI + "/license"
+)
+
+// Ownership labels and annotations applied to the resources created by the
+// deploy engine.
+const (
+	// ManagedBy standard label carrying the application name, Helm replaces it
+	// by "Helm" on the release resources.
+	ManagedBy = "app.kubernetes.io/managed-by"
+	// Application label carrying the application name, kept by Helm.
+	Application = RepoURI + "/application"
+	// Chart label carrying the Helm chart name.
+	Chart = RepoURI + "/chart"
+)
+
+// Integration secrets replication, the source secret lists the namespaces to
+// keep a copy in, the replicas track the source secret and payload hash.
+const (
+	// ReplicateTo annotation on the source secret, comma separated namespaces.
+	ReplicateTo = RepoURI + "/replicate-to"
+	// ReplicaOf label on the replicated secret, naming the source secret.
+	ReplicaOf = RepoURI + "/replica-of"
+	// ReplicaSource annotation on the replicated secret, the source secret
+	// namespaced name.
+	ReplicaSource = RepoURI + "/replica-source"
+	// SourceHash annotation on the replicated secret, the source payload hash.
+	SourceHash = RepoURI + "/source-hash"
+)
+
+// ExpiresAt annotation on the integration secret, the expiry of its token or
+// credential, formatted as RFC 3339.
+const ExpiresAt = RepoURI + "/expires-at"
+
+// Metrics endpoints, annotated on the chart Services and workloads, exported to
+// the cluster monitoring stack. The port names the container or Service port,
+// the path defaults to "/metrics".
+const (
+	// MetricsPort annotation naming the port serving the metrics.
+	MetricsPort = RepoURI + "/metrics-port"
+	// MetricsPath annotation with the HTTP path of the metrics endpoint.
+	MetricsPath = RepoURI + "/metrics-path"
+)
+
+// Configuration mutations, annotated on the cluster configuration by every
+// write, so the actors changing it concurrently detect each other's changes.
+const (
+	// ConfigSequence annotation, the number of mutations applied on the
+	// configuration.
+	ConfigSequence = RepoURI + "/config-sequence"
+	// ConfigMutatedBy annotation, the actor applying the last mutation.
+	ConfigMutatedBy = RepoURI + "/config-mutated-by"
 )
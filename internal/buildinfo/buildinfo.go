@@ -0,0 +1,186 @@
+package buildinfo
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+
+	"github.com/goreleaser/quill/quill/macho"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// Modules the Go modules reported, the ones shaping the installer behavior.
+var Modules = []string{
+	"github.com/redhat-appstudio/helmet",
+	"helm.sh/helm/v3",
+	"k8s.io/client-go",
+	"github.com/mark3labs/mcp-go",
+}
+
+// Signature status of the running binary.
+const (
+	// SignatureSigned the binary carries a code signature with a certificate.
+	SignatureSigned = "signed"
+	// SignatureAdHoc the binary carries an ad-hoc code signature, without a
+	// certificate, as the Go linker produces for macOS.
+	SignatureAdHoc = "ad-hoc"
+	// SignatureUnsigned the binary carries no code signature.
+	SignatureUnsigned = "unsigned"
+	// SignatureNotApplicable the binary format carries no embedded signature,
+	// the release artifacts are signed separately instead.
+	SignatureNotApplicable = "not applicable"
+)
+
+// Diagnostics the build provenance and runtime details of the installer
+// binary, the data asked for when troubleshooting an installation.
+type Diagnostics struct {
+	Name      string            // application name
+	Version   string            // application version
+	CommitID  string            // application commit
+	GoVersion string            // Go toolchain building the binary
+	Platform  string            // operating system and architecture
+	VCS       map[string]string // version control settings, by name
+	Modules   map[string]string // versions of the Modules, by path
+	Catalog   string            // chart catalog digest
+	Charts    int               // number of charts in the catalog
+	Signature string            // code signature status of the binary
+	MCPImage  string            // MCP server container image
+}
+
+// CatalogDigest returns the SHA-256 digest of the chart catalog, the embedded
+// charts and the local overrides, over every chart file. Charts are hashed in
+// name order, and their files in path order.
+func CatalogDigest(charts []chart.Chart) string {
+	slices.SortFunc(charts, func(a, b chart.Chart) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	h := sha256.New()
+	write := func(b []byte) {
+		_ = binary.Write(h, binary.BigEndian, uint64(len(b)))
+		_, _ = h.Write(b)
+	}
+	for _, c := range charts {
+		write([]byte(c.Name()))
+		files := slices.Clone(c.Raw)
+		slices.SortFunc(files, func(a, b *chart.File) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		for _, f := range files {
+			write([]byte(f.Name))
+			write(f.Data)
+		}
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// Signature returns the code signature status of the binary on the path. Only
+// Mach-O binaries, macOS, embed the signature.
+func Signature(path string) (string, error) {
+	isMacho, err := macho.IsMachoFile(path)
+	if !isMacho {
+		// Failing to parse as Mach-O tells the binary is on another format.
+		if _, statErr := os.Stat(path); statErr != nil {
+			return "", statErr
+		}
+		return SignatureNotApplicable, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	f, err := macho.NewReadOnlyFile(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if !f.HasCodeSigningCmd() {
+		return SignatureUnsigned, nil
+	}
+	if _, err = f.CMSBlobBytes(macho.SigningOrder); err != nil {
+		return SignatureAdHoc, nil
+	}
+	return SignatureSigned, nil
+}
+
+// Collect collects the binary diagnostics. The chart catalog and the binary
+// signature are reported as unknown when they can't be inspected.
+func Collect(
+	name, version, commitID string,
+	cfs *chartfs.ChartFS,
+	mcpImage string,
+) *Diagnostics {
+	d := &Diagnostics{
+		Name:      name,
+		Version:   version,
+		CommitID:  commitID,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		VCS:       map[string]string{},
+		Modules:   map[string]string{},
+		Catalog:   "unknown",
+		Signature: "unknown",
+		MCPImage:  mcpImage,
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		modules := append([]*debug.Module{&info.Main}, info.Deps...)
+		for _, m := range modules {
+			if !slices.Contains(Modules, m.Path) {
+				continue
+			}
+			d.Modules[m.Path] = m.Version
+			if m.Replace != nil {
+				d.Modules[m.Path] = fmt.Sprintf("%s => %s %s",
+					m.Version, m.Replace.Path, m.Replace.Version)
+			}
+		}
+		for _, s := range info.Settings {
+			if strings.HasPrefix(s.Key, "vcs.") || s.Key == "CGO_ENABLED" {
+				d.VCS[s.Key] = s.Value
+			}
+		}
+	}
+	if charts, err := cfs.GetAllCharts(); err == nil {
+		d.Catalog = CatalogDigest(charts)
+		d.Charts = len(charts)
+	}
+	if exe, err := os.Executable(); err == nil {
+		if signature, err := Signature(exe); err == nil {
+			d.Signature = signature
+		}
+	}
+	return d
+}
+
+// Print prints the diagnostics, one detail per line.
+func (d *Diagnostics) Print(w io.Writer) error {
+	t := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(t, "%s Version:\t%s\n", d.Name, d.Version)
+	fmt.Fprintf(t, "Commit:\t%s\n", d.CommitID)
+	fmt.Fprintf(t, "Go Version:\t%s\n", d.GoVersion)
+	fmt.Fprintf(t, "Platform:\t%s\n", d.Platform)
+	for _, key := range []string{"vcs.revision", "vcs.time", "vcs.modified",
+		"CGO_ENABLED"} {
+		if value, ok := d.VCS[key]; ok {
+			fmt.Fprintf(t, "Build %s:\t%s\n", strings.TrimPrefix(key, "vcs."), value)
+		}
+	}
+	fmt.Fprintf(t, "Chart Catalog:\t%s (%d charts)\n", d.Catalog, d.Charts)
+	fmt.Fprintf(t, "Signature:\t%s\n", d.Signature)
+	fmt.Fprintf(t, "MCP Image:\t%s\n", d.MCPImage)
+	fmt.Fprintln(t, "Modules:")
+	for _, path := range Modules {
+		if version, ok := d.Modules[path]; ok {
+			fmt.Fprintf(t, "  %s\t%s\n", path, version)
+		}
+	}
+	return t.Flush()
+}
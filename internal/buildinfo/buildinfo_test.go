@@ -0,0 +1,64 @@
+package buildinfo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+
+	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestCatalogDigest(t *testing.T) {
+	g := o.NewWithT(t)
+	newChart := func(name string, files ...*chart.File) chart.Chart {
+		return chart.Chart{Metadata: &chart.Metadata{Name: name}, Raw: files}
+	}
+	values := &chart.File{Name: "values.yaml", Data: []byte("key: value\n")}
+	tmpl := &chart.File{Name: "templates/cm.yaml", Data: []byte("kind: ConfigMap\n")}
+
+	digest := CatalogDigest([]chart.Chart{
+		newChart("a", values, tmpl), newChart("b", values)})
+	g.Expect(digest).To(o.HavePrefix("sha256:"))
+
+	// The digest doesn't depend on the charts and files order.
+	g.Expect(CatalogDigest([]chart.Chart{
+		newChart("b", values), newChart("a", tmpl, values)})).To(o.Equal(digest))
+
+	// Any change on the files changes it.
+	changed := &chart.File{Name: "values.yaml", Data: []byte("key: other\n")}
+	g.Expect(CatalogDigest([]chart.Chart{
+		newChart("a", changed, tmpl), newChart("b", values)})).
+		NotTo(o.Equal(digest))
+	g.Expect(CatalogDigest([]chart.Chart{newChart("a", values, tmpl)})).
+		NotTo(o.Equal(digest))
+}
+
+func TestSignature(t *testing.T) {
+	g := o.NewWithT(t)
+	path := filepath.Join(t.TempDir(), "binary")
+	g.Expect(os.WriteFile(path, []byte("\x7fELF"), 0o600)).To(o.Succeed())
+	signature, err := Signature(path)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(signature).To(o.Equal(SignatureNotApplicable))
+
+	_, err = Signature(filepath.Join(t.TempDir(), "missing"))
+	g.Expect(err).To(o.HaveOccurred())
+}
+
+func TestCollect(t *testing.T) {
+	g := o.NewWithT(t)
+	cfs := chartfs.New(os.DirFS("../../test"))
+	d := Collect("helmet-ex", "v1.0.0", "abc123", cfs, "quay.io/helmet-ex:v1")
+	g.Expect(d.Charts).To(o.BeNumerically(">", 0))
+	g.Expect(d.Catalog).To(o.HavePrefix("sha256:"))
+
+	var b strings.Builder
+	g.Expect(d.Print(&b)).To(o.Succeed())
+	g.Expect(b.String()).To(o.ContainSubstring("helmet-ex Version: v1.0.0"))
+	g.Expect(b.String()).To(o.MatchRegexp(`MCP Image: +quay.io/helmet-ex:v1`))
+	g.Expect(b.String()).To(o.MatchRegexp(`Platform: +\w+/\w+`))
+}
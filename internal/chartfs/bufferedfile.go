@@ -2,7 +2,6 @@ package chartfs
 
 import (
 	"io/fs"
-	"path/filepath"
 
 	"helm.sh/helm/v3/pkg/chart/loader"
 )
@@ -38,14 +37,11 @@ func (b *BufferedFiles) Walk(filePath string, d fs.DirEntry, err error) error {
 	}
 
 	// The file path is relative to the base directory, therefore paths like
-	// "templates/file.tpl" are kept as is.
-	relPath, err := filepath.Rel(b.baseDir, filePath)
-	if err != nil {
-		return err
-	}
+	// "templates/file.tpl" are kept as is, slash separated on every OS.
+	name := relPath(b.baseDir, filePath)
 	// Appending the file data using only the base name as reference. The
 	// collected files represent a single Helm chart payload.
-	b.files = append(b.files, &loader.BufferedFile{Name: relPath, Data: data})
+	b.files = append(b.files, &loader.BufferedFile{Name: name, Data: data})
 	return nil
 }
 
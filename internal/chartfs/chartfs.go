@@ -3,7 +3,10 @@ package chartfs
 import (
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"runtime"
+	"sync"
 
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
@@ -14,13 +17,23 @@ import (
 // payload, and as well the "values.yaml.tpl" file. It uses an underlying fs.FS
 // as data source.
 type ChartFS struct {
-	fsys fs.FS // overlay filesystem
+	fsys  fs.FS       // overlay filesystem
+	cache *chartCache // loaded charts
 }
 
+// chartCache memoizes the charts loaded, by path, shared by concurrent loads.
+type chartCache struct {
+	mu     sync.Mutex              // guards charts
+	charts map[string]*chart.Chart // loaded charts, by path
+}
+
+// loadWorkers the maximum number of charts loaded concurrently.
+var loadWorkers = runtime.GOMAXPROCS(0)
+
 // ReadFile reads the file from the file system.
 // It supports absolute paths (read from the OS filesystem), relative paths
 // that exist in the OS filesystem (converted to absolute), and relative paths
-// from the embedded filesystem.
+// from the embedded filesystem, using either separator, see "Path".
 func (c *ChartFS) ReadFile(name string) ([]byte, error) {
 	// Absolute paths are always read from the OS filesystem
 	// For relative paths, try to convert to absolute
@@ -32,12 +45,20 @@ func (c *ChartFS) ReadFile(name string) ([]byte, error) {
 		}
 	}
 	// Fallback to embedded filesystem
-	return fs.ReadFile(c.fsys, name)
+	fsName, err := Path(name)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(c.fsys, fsName)
 }
 
 // Open opens the named file. Implements "fs.FS" interface.
 func (c *ChartFS) Open(name string) (fs.File, error) {
-	return c.fsys.Open(name)
+	fsName, err := Path(name)
+	if err != nil {
+		return nil, err
+	}
+	return c.fsys.Open(fsName)
 }
 
 // walkChartDir walks through the chart directory, and loads the chart files.
@@ -49,9 +70,26 @@ func (c *ChartFS) walkChartDir(fsys fs.FS, chartPath string) (*chart.Chart, erro
 	return loader.LoadFiles(bf.Files())
 }
 
-// GetChartFiles returns the informed Helm chart path instantiated files.
+// GetChartFiles returns the informed Helm chart path instantiated files. Charts
+// are loaded once, the instance returned is shared and must not be modified.
 func (c *ChartFS) GetChartFiles(chartPath string) (*chart.Chart, error) {
-	return c.walkChartDir(c.fsys, chartPath)
+	fsChartPath, err := Path(chartPath)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.mu.Lock()
+	hc, ok := c.cache.charts[fsChartPath]
+	c.cache.mu.Unlock()
+	if ok {
+		return hc, nil
+	}
+	if hc, err = c.walkChartDir(c.fsys, fsChartPath); err != nil {
+		return nil, err
+	}
+	c.cache.mu.Lock()
+	c.cache.charts[fsChartPath] = hc
+	c.cache.mu.Unlock()
+	return hc, nil
 }
 
 // walkAndFindChartDirs walks through the filesystem and finds all directories
@@ -70,7 +108,8 @@ func (c *ChartFS) walkAndFindChartDirs(
 			return nil
 		}
 		// Check if the "Chart.yaml" exists in this directory.
-		chartYamlPath := filepath.Join(name, chartutil.ChartfileName)
+		// The "fs.FS" names are always slash separated, regardless of the OS.
+		chartYamlPath := path.Join(name, chartutil.ChartfileName)
 		if _, err := fs.Stat(fsys, chartYamlPath); err == nil {
 			chartDirs = append(chartDirs, name)
 		}
@@ -82,33 +121,58 @@ func (c *ChartFS) walkAndFindChartDirs(
 	return chartDirs, nil
 }
 
-// GetAllCharts retrieves all Helm charts from the filesystem.
+// GetAllCharts retrieves all Helm charts from the filesystem, in the directory
+// walk order. The charts are loaded concurrently, by a pool of workers.
 func (c *ChartFS) GetAllCharts() ([]chart.Chart, error) {
-	charts := []chart.Chart{}
 	chartDirs, err := c.walkAndFindChartDirs(c.fsys, ".")
 	if err != nil {
 		return nil, err
 	}
-	for _, chartDir := range chartDirs {
-		chart, err := c.GetChartFiles(chartDir)
-		if err != nil {
-			return nil, err
+
+	loaded := make([]*chart.Chart, len(chartDirs))
+	errs := make([]error, len(chartDirs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(loadWorkers, len(chartDirs)) {
+		wg.Go(func() {
+			for i := range jobs {
+				loaded[i], errs[i] = c.GetChartFiles(chartDirs[i])
+			}
+		})
+	}
+	for i := range chartDirs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	charts := make([]chart.Chart, 0, len(loaded))
+	for i, hc := range loaded {
+		if errs[i] != nil {
+			return nil, errs[i]
 		}
-		charts = append(charts, *chart)
+		charts = append(charts, *hc)
 	}
 	return charts, nil
 }
 
 // WithBaseDir returns a new ChartFS that is rooted at the given base directory.
 func (c *ChartFS) WithBaseDir(baseDir string) (*ChartFS, error) {
-	sub, err := fs.Sub(c.fsys, baseDir)
+	fsBaseDir, err := Path(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := fs.Sub(c.fsys, fsBaseDir)
 	if err != nil {
 		return nil, err
 	}
-	return &ChartFS{fsys: sub}, nil
+	return New(sub), nil
 }
 
 // New creates a ChartFS from any filesystem.
 func New(filesystem fs.FS) *ChartFS {
-	return &ChartFS{fsys: filesystem}
+	return &ChartFS{
+		fsys:  filesystem,
+		cache: &chartCache{charts: map[string]*chart.Chart{}},
+	}
 }
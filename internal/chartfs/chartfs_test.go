@@ -43,5 +43,24 @@ func TestNewChartFS(t *testing.T) {
 		g.Expect(err).To(o.Succeed())
 		g.Expect(charts).ToNot(o.BeNil())
 		g.Expect(len(charts)).To(o.BeNumerically(">", 1))
+
+		// Charts loaded concurrently keep the serial, directory walk, order.
+		workers := loadWorkers
+		t.Cleanup(func() { loadWorkers = workers })
+		loadWorkers = 1
+		serial, err := New(os.DirFS("../../test")).GetAllCharts()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(len(serial)).To(o.Equal(len(charts)))
+		for i := range serial {
+			g.Expect(serial[i].Name()).To(o.Equal(charts[i].Name()))
+		}
+	})
+
+	t.Run("Memoized", func(t *testing.T) {
+		first, err := c.GetChartFiles("charts/helmet-product-a")
+		g.Expect(err).To(o.Succeed())
+		again, err := c.GetChartFiles("./charts/helmet-product-a/")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(again).To(o.BeIdenticalTo(first))
 	})
 }
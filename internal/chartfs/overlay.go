@@ -22,8 +22,12 @@ func NewOverlayFS(embedded, local fs.FS) *OverlayFS {
 }
 
 // Open opens the named file, when it doesn't exist in the embedded FS it falls
-// back to the local.
+// back to the local. Local paths are translated, see "Path".
 func (o *OverlayFS) Open(name string) (fs.File, error) {
+	name, err := Path(name)
+	if err != nil {
+		return nil, err
+	}
 	f, err := o.Embedded.Open(name)
 	if err == nil {
 		return f, nil
@@ -38,6 +42,10 @@ func (o *OverlayFS) Open(name string) (fs.File, error) {
 // rooted at the given base directory, while keeping the local filesystem
 // unchanged.
 func (o *OverlayFS) WithEmbeddedBaseDir(baseDir string) (*OverlayFS, error) {
+	baseDir, err := Path(baseDir)
+	if err != nil {
+		return nil, err
+	}
 	sub, err := fs.Sub(o.Embedded, baseDir)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,39 @@
+package chartfs
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Path translates a local path, using the operating system separator, into a
+// name valid for "fs.FS", slash separated and unrooted. An empty name, or the
+// current directory, translates to ".". Absolute paths, drive letters included,
+// and names escaping the filesystem root are rejected.
+func Path(name string) (string, error) {
+	slashed := filepath.ToSlash(name)
+	if filepath.VolumeName(name) != "" || strings.HasPrefix(slashed, "/") {
+		return "", &fs.PathError{Op: "translate", Path: name, Err: fs.ErrInvalid}
+	}
+	cleaned := path.Clean(slashed)
+	if !fs.ValidPath(cleaned) {
+		return "", &fs.PathError{Op: "translate", Path: name, Err: fs.ErrInvalid}
+	}
+	return cleaned, nil
+}
+
+// LocalPath translates a "fs.FS" name back into a local path, using the
+// operating system separator.
+func LocalPath(name string) string {
+	return filepath.FromSlash(name)
+}
+
+// relPath returns the name relative to the base directory, both slash
+// separated, as Helm expects for the chart files, i.e. "templates/file.tpl".
+func relPath(baseDir, name string) string {
+	if baseDir == "." {
+		return name
+	}
+	return strings.TrimPrefix(name, baseDir+"/")
+}
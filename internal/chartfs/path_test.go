@@ -0,0 +1,57 @@
+package chartfs
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestPath(t *testing.T) {
+	g := o.NewWithT(t)
+
+	for name, expected := range map[string]string{
+		"":                          ".",
+		".":                         ".",
+		"./values.yaml.tpl":         "values.yaml.tpl",
+		"charts//helmet-product-a/": "charts/helmet-product-a",
+		"charts/../config.yaml":     "config.yaml",
+	} {
+		fsName, err := Path(name)
+		g.Expect(err).To(o.Succeed(), name)
+		g.Expect(fsName).To(o.Equal(expected), name)
+	}
+
+	for _, name := range []string{"/etc/passwd", "../config.yaml"} {
+		_, err := Path(name)
+		g.Expect(err).To(o.MatchError(fs.ErrInvalid), name)
+	}
+}
+
+func TestOverlayFSPath(t *testing.T) {
+	ofs := NewOverlayFS(os.DirFS("../../test/charts"), os.DirFS("../../test"))
+	c := New(ofs)
+
+	t.Run("ReadFile", func(t *testing.T) {
+		g := o.NewWithT(t)
+		// Resolved by the local filesystem, the embedded doesn't have it.
+		_, err := fs.ReadFile(c, "./values.yaml.tpl")
+		g.Expect(err).To(o.Succeed())
+	})
+
+	t.Run("GetChartFiles", func(t *testing.T) {
+		g := o.NewWithT(t)
+		chart, err := c.GetChartFiles("./helmet-product-a/")
+		g.Expect(err).To(o.Succeed())
+		for _, tmpl := range chart.Templates {
+			g.Expect(tmpl.Name).NotTo(o.ContainSubstring(`\`))
+		}
+	})
+
+	t.Run("WithEmbeddedBaseDir", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := ofs.WithEmbeddedBaseDir("../charts")
+		g.Expect(err).To(o.MatchError(fs.ErrInvalid))
+	})
+}
@@ -0,0 +1,63 @@
+//go:build windows
+
+package chartfs
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestPathWindows(t *testing.T) {
+	g := o.NewWithT(t)
+
+	for name, expected := range map[string]string{
+		`.\values.yaml.tpl`:                 "values.yaml.tpl",
+		`charts\helmet-product-a\`:          "charts/helmet-product-a",
+		`charts/helmet-product-a\templates`: "charts/helmet-product-a/templates",
+	} {
+		fsName, err := Path(name)
+		g.Expect(err).To(o.Succeed(), name)
+		g.Expect(fsName).To(o.Equal(expected), name)
+		g.Expect(LocalPath(fsName)).NotTo(o.ContainSubstring("/"), name)
+	}
+
+	for _, name := range []string{`C:\installer`, `\\host\share\installer`, `..\config.yaml`} {
+		_, err := Path(name)
+		g.Expect(err).To(o.MatchError(fs.ErrInvalid), name)
+	}
+}
+
+func TestChartFSWindows(t *testing.T) {
+	ofs := NewOverlayFS(os.DirFS(`..\..\test\charts`), os.DirFS(`..\..\test`))
+	c := New(ofs)
+
+	t.Run("ReadFile", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := c.ReadFile(`charts\helmet-product-a\Chart.yaml`)
+		g.Expect(err).To(o.Succeed())
+	})
+
+	t.Run("GetChartFiles", func(t *testing.T) {
+		g := o.NewWithT(t)
+		chart, err := c.GetChartFiles(`helmet-product-a\`)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(chart.Name()).To(o.Equal("helmet-product-a"))
+		names := []string{}
+		for _, tmpl := range chart.Templates {
+			names = append(names, tmpl.Name)
+		}
+		g.Expect(names).To(o.ContainElement("templates/hooks/deploy-order.yaml"))
+	})
+
+	t.Run("WithBaseDir", func(t *testing.T) {
+		g := o.NewWithT(t)
+		sub, err := New(os.DirFS(`..\..`)).WithBaseDir(`test\charts`)
+		g.Expect(err).To(o.Succeed())
+		charts, err := sub.GetAllCharts()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(charts).NotTo(o.BeEmpty())
+	})
+}
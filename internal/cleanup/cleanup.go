@@ -0,0 +1,324 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Kind the kind of leftover resource.
+type Kind string
+
+const (
+	// KindReleaseLock a Helm release revision stuck on a pending state, it
+	// blocks further installs and upgrades of the release.
+	KindReleaseLock Kind = "ReleaseLock"
+	// KindInstallerJob a finished installer Job.
+	KindInstallerJob Kind = "InstallerJob"
+	// KindClusterRoleBinding a temporary ClusterRoleBinding.
+	KindClusterRoleBinding Kind = "ClusterRoleBinding"
+	// KindClusterRole a temporary ClusterRole.
+	KindClusterRole Kind = "ClusterRole"
+	// KindRoleBinding a temporary RoleBinding.
+	KindRoleBinding Kind = "RoleBinding"
+	// KindRole a temporary Role.
+	KindRole Kind = "Role"
+	// KindServiceAccount a temporary ServiceAccount.
+	KindServiceAccount Kind = "ServiceAccount"
+)
+
+// helmPendingStatuses Helm release statuses holding the release lock.
+var helmPendingStatuses = []string{
+	"pending-install",
+	"pending-upgrade",
+	"pending-rollback",
+}
+
+// Candidate a leftover resource eligible for removal.
+type Candidate struct {
+	Kind      Kind          // resource kind
+	Namespace string        // resource namespace, empty for cluster scoped
+	Name      string        // resource name
+	Age       time.Duration // time since the resource was last modified
+	Reason    string        // why the resource is considered stale
+}
+
+// Cleanup finds and removes the resources left behind by crashed, or
+// interrupted, deployments. Only resources older than the threshold are
+// considered, so concurrent deployments are not disturbed.
+type Cleanup struct {
+	logger      *slog.Logger  // application logger
+	kube        k8s.Interface // kubernetes client
+	releases    []string      // Helm release names managed by the installer
+	jobSelector string        // installer job label selector
+	olderThan   time.Duration // age threshold
+	now         func() time.Time
+}
+
+// SetNow overwrites the clock used to compute the resources age.
+func (c *Cleanup) SetNow(now func() time.Time) {
+	c.now = now
+}
+
+// age returns the time elapsed since the informed timestamp.
+func (c *Cleanup) age(t metav1.Time) time.Duration {
+	return c.now().Sub(t.Time).Round(time.Second)
+}
+
+// releaseLocks finds the Helm release revisions stuck on a pending state. Helm
+// stores each revision as a Secret, the "modifiedAt" label records the last
+// state transition.
+func (c *Cleanup) releaseLocks(ctx context.Context) ([]Candidate, error) {
+	coreClient, err := c.kube.CoreV1ClientSet("")
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := coreClient.Secrets("").List(ctx, metav1.ListOptions{
+		LabelSelector: "owner=helm",
+	})
+	if err != nil {
+		return nil, err
+	}
+	candidates := []Candidate{}
+	for _, s := range secrets.Items {
+		labels := s.GetLabels()
+		if !slices.Contains(c.releases, labels["name"]) ||
+			!slices.Contains(helmPendingStatuses, labels["status"]) {
+			continue
+		}
+		modified := s.GetCreationTimestamp()
+		if ts, err := strconv.ParseInt(labels["modifiedAt"], 10, 64); err == nil {
+			modified = metav1.Unix(ts, 0)
+		}
+		candidates = append(candidates, Candidate{
+			Kind:      KindReleaseLock,
+			Namespace: s.GetNamespace(),
+			Name:      s.GetName(),
+			Age:       c.age(modified),
+			Reason: fmt.Sprintf("release %q revision %s is %s",
+				labels["name"], labels["version"], labels["status"]),
+		})
+	}
+	return candidates, nil
+}
+
+// installerJobs finds the finished installer Jobs.
+func (c *Cleanup) installerJobs(ctx context.Context) ([]Candidate, error) {
+	bc, err := c.kube.BatchV1ClientSet("")
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := bc.Jobs("").List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("type=%s", c.jobSelector),
+	})
+	if err != nil {
+		return nil, err
+	}
+	candidates := []Candidate{}
+	for _, job := range jobs.Items {
+		if job.Status.Active > 0 {
+			continue
+		}
+		reason := "installer job finished"
+		switch {
+		case job.Status.Failed > 0:
+			reason = "installer job failed"
+		case job.Status.Succeeded > 0:
+			reason = "installer job succeeded"
+		}
+		finished := job.GetCreationTimestamp()
+		if job.Status.CompletionTime != nil {
+			finished = *job.Status.CompletionTime
+		}
+		candidates = append(candidates, Candidate{
+			Kind:      KindInstallerJob,
+			Namespace: job.GetNamespace(),
+			Name:      job.GetName(),
+			Age:       c.age(finished),
+			Reason:    reason,
+		})
+	}
+	return candidates, nil
+}
+
+// temporaryResources finds the temporary RBAC resources and ServiceAccounts
+// which should have been removed at the end of the deployment.
+func (c *Cleanup) temporaryResources(
+	ctx context.Context,
+) ([]Candidate, error) {
+	opts := metav1.ListOptions{LabelSelector: k8s.TemporaryResourcesSelector}
+	candidates := []Candidate{}
+	add := func(kind Kind, obj metav1.Object) {
+		candidates = append(candidates, Candidate{
+			Kind:      kind,
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+			Age:       c.age(obj.GetCreationTimestamp()),
+			Reason:    "temporary deployment resource",
+		})
+	}
+
+	rbacClient, err := c.kube.RBACV1ClientSet("")
+	if err != nil {
+		return nil, err
+	}
+	crbs, err := rbacClient.ClusterRoleBindings().List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range crbs.Items {
+		add(KindClusterRoleBinding, &crbs.Items[i])
+	}
+	crs, err := rbacClient.ClusterRoles().List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range crs.Items {
+		add(KindClusterRole, &crs.Items[i])
+	}
+	rbs, err := rbacClient.RoleBindings("").List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rbs.Items {
+		add(KindRoleBinding, &rbs.Items[i])
+	}
+	roles, err := rbacClient.Roles("").List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles.Items {
+		add(KindRole, &roles.Items[i])
+	}
+
+	coreClient, err := c.kube.CoreV1ClientSet("")
+	if err != nil {
+		return nil, err
+	}
+	sas, err := coreClient.ServiceAccounts("").List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sas.Items {
+		add(KindServiceAccount, &sas.Items[i])
+	}
+	return candidates, nil
+}
+
+// Find returns the leftover resources older than the threshold.
+func (c *Cleanup) Find(ctx context.Context) ([]Candidate, error) {
+	stale := []Candidate{}
+	for _, fn := range []func(context.Context) ([]Candidate, error){
+		c.releaseLocks,
+		c.installerJobs,
+		c.temporaryResources,
+	} {
+		candidates, err := fn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range candidates {
+			if candidate.Age < c.olderThan {
+				c.logger.Debug("Skipping recent resource",
+					"kind", candidate.Kind,
+					"namespace", candidate.Namespace,
+					"name", candidate.Name,
+					"age", candidate.Age,
+				)
+				continue
+			}
+			stale = append(stale, candidate)
+		}
+	}
+	return stale, nil
+}
+
+// Delete removes the leftover resource from the cluster.
+func (c *Cleanup) Delete(ctx context.Context, candidate Candidate) error {
+	opts := metav1.DeleteOptions{}
+	ns, name := candidate.Namespace, candidate.Name
+	switch candidate.Kind {
+	case KindReleaseLock:
+		coreClient, err := c.kube.CoreV1ClientSet(ns)
+		if err != nil {
+			return err
+		}
+		return coreClient.Secrets(ns).Delete(ctx, name, opts)
+	case KindInstallerJob:
+		bc, err := c.kube.BatchV1ClientSet(ns)
+		if err != nil {
+			return err
+		}
+		// Removing the job pods as well.
+		propagation := metav1.DeletePropagationBackground
+		opts.PropagationPolicy = &propagation
+		return bc.Jobs(ns).Delete(ctx, name, opts)
+	case KindServiceAccount:
+		coreClient, err := c.kube.CoreV1ClientSet(ns)
+		if err != nil {
+			return err
+		}
+		return coreClient.ServiceAccounts(ns).Delete(ctx, name, opts)
+	}
+
+	rbacClient, err := c.kube.RBACV1ClientSet(ns)
+	if err != nil {
+		return err
+	}
+	switch candidate.Kind {
+	case KindClusterRoleBinding:
+		return rbacClient.ClusterRoleBindings().Delete(ctx, name, opts)
+	case KindClusterRole:
+		return rbacClient.ClusterRoles().Delete(ctx, name, opts)
+	case KindRoleBinding:
+		return rbacClient.RoleBindings(ns).Delete(ctx, name, opts)
+	case KindRole:
+		return rbacClient.Roles(ns).Delete(ctx, name, opts)
+	}
+	return fmt.Errorf("unknown resource kind %q", candidate.Kind)
+}
+
+// Print prints the candidates table, with the action taken on each of them.
+func Print(w io.Writer, candidates []Candidate, action string) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(a ...any) {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n", a...)
+	}
+	row("Kind", "Namespace", "Name", "Age", "Reason", "Action")
+	for _, c := range candidates {
+		ns := c.Namespace
+		if ns == "" {
+			ns = "-"
+		}
+		row(string(c.Kind), ns, c.Name, c.Age.String(), c.Reason, action)
+	}
+	table.Flush()
+}
+
+// NewCleanup instantiates the cleanup for the Helm releases managed by the
+// installer, and its installer jobs.
+func NewCleanup(
+	logger *slog.Logger,
+	kube k8s.Interface,
+	releases []string,
+	jobSelector string,
+	olderThan time.Duration,
+) *Cleanup {
+	return &Cleanup{
+		logger:      logger,
+		kube:        kube,
+		releases:    releases,
+		jobSelector: jobSelector,
+		olderThan:   olderThan,
+		now:         time.Now,
+	}
+}
@@ -0,0 +1,118 @@
+package cleanup
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCleanup(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) metav1.Time {
+		return metav1.NewTime(now.Add(-d))
+	}
+	meta := func(ns, name string, age time.Duration) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Namespace:         ns,
+			Name:              name,
+			CreationTimestamp: ago(age),
+		}
+	}
+	helmSecret := func(release, status string, age time.Duration) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: meta(
+			"helmet", "sh.helm.release.v1."+release+".v2", 48*time.Hour)}
+		s.Labels = map[string]string{
+			"owner":      "helm",
+			"name":       release,
+			"status":     status,
+			"version":    "2",
+			"modifiedAt": strconv.FormatInt(now.Add(-age).Unix(), 10),
+		}
+		return s
+	}
+	temporary := map[string]string{annotations.PostDeploy: "delete"}
+
+	jobSelector := "installer-job.test"
+	finishedJob := &batchv1.Job{
+		ObjectMeta: meta("helmet", "helmet-ex-installer", 3*time.Hour),
+		Status: batchv1.JobStatus{
+			Failed:         1,
+			CompletionTime: &metav1.Time{Time: now.Add(-2 * time.Hour)},
+		},
+	}
+	finishedJob.Labels = map[string]string{"type": jobSelector}
+	activeJob := &batchv1.Job{
+		ObjectMeta: meta("other", "helmet-ex-installer", 3*time.Hour),
+		Status:     batchv1.JobStatus{Active: 1},
+	}
+	activeJob.Labels = map[string]string{"type": jobSelector}
+
+	oldRole := &rbacv1.Role{ObjectMeta: meta("helmet", "old-role", 2*time.Hour)}
+	oldRole.Labels = temporary
+	newSA := &corev1.ServiceAccount{
+		ObjectMeta: meta("helmet", "new-sa", time.Minute),
+	}
+	newSA.Labels = temporary
+
+	kube := k8s.NewFakeKube(
+		helmSecret("helmet-foundation", "pending-install", 2*time.Hour),
+		helmSecret("helmet-operators", "pending-upgrade", time.Minute),
+		helmSecret("helmet-networking", "deployed", 2*time.Hour),
+		helmSecret("unrelated", "pending-install", 2*time.Hour),
+		finishedJob,
+		activeJob,
+		oldRole,
+		newSA,
+	)
+
+	c := NewCleanup(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		kube,
+		[]string{"helmet-foundation", "helmet-operators", "helmet-networking"},
+		jobSelector,
+		time.Hour,
+	)
+	c.SetNow(func() time.Time { return now })
+
+	candidates, err := c.Find(ctx)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(candidates).To(o.HaveLen(3))
+
+	g.Expect(candidates[0].Kind).To(o.Equal(KindReleaseLock))
+	g.Expect(candidates[0].Name).
+		To(o.Equal("sh.helm.release.v1.helmet-foundation.v2"))
+	g.Expect(candidates[0].Age).To(o.Equal(2 * time.Hour))
+	g.Expect(candidates[0].Reason).To(o.ContainSubstring("pending-install"))
+
+	g.Expect(candidates[1].Kind).To(o.Equal(KindInstallerJob))
+	g.Expect(candidates[1].Namespace).To(o.Equal("helmet"))
+	g.Expect(candidates[1].Reason).To(o.Equal("installer job failed"))
+
+	g.Expect(candidates[2].Kind).To(o.Equal(KindRole))
+	g.Expect(candidates[2].Name).To(o.Equal("old-role"))
+
+	for _, candidate := range candidates {
+		g.Expect(c.Delete(ctx, candidate)).To(o.Succeed())
+	}
+
+	var buf bytes.Buffer
+	Print(&buf, candidates, "would delete")
+	g.Expect(buf.String()).To(o.ContainSubstring("ReleaseLock"))
+	g.Expect(buf.String()).To(o.ContainSubstring("would delete"))
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrInvalidBackup the backup file can't be restored.
+var ErrInvalidBackup = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+	"invalid configuration backup")
+
+// Backup a copy of the configuration ConfigMap, as stored in the cluster.
+type Backup struct {
+	// CreatedAt when the backup was taken.
+	CreatedAt time.Time `json:"createdAt"`
+	// ResourceVersion the ConfigMap version when the backup was taken.
+	ResourceVersion string `json:"resourceVersion"`
+	// ConfigMap the configuration ConfigMap.
+	ConfigMap *corev1.ConfigMap `json:"configMap"`
+}
+
+// Config returns the configuration stored on the backup.
+func (b *Backup) Config(appName string) (*Config, error) {
+	if b.ConfigMap == nil {
+		return nil, fmt.Errorf("%w: ConfigMap is missing", ErrInvalidBackup)
+	}
+	payload, err := ConfigMapPayload(b.ConfigMap)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+	}
+	cfg, err := NewConfigFromBytes(payload, b.ConfigMap.GetNamespace(), appName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the backup as YAML on the informed path.
+func (b *Backup) Save(path string) error {
+	payload, err := yaml.Marshal(b)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, payload, 0o600)
+}
+
+// LoadBackup reads the backup YAML from the informed path.
+func LoadBackup(path string) (*Backup, error) {
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := &Backup{}
+	if err = yaml.Unmarshal(payload, b); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+	}
+	return b, nil
+}
+
+// Backup copies the configuration ConfigMap stored in the cluster.
+func (m *ConfigMapManager) Backup(ctx context.Context) (*Backup, error) {
+	cm, err := m.GetConfigMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cm = cm.DeepCopy()
+	cm.APIVersion = "v1"
+	cm.Kind = "ConfigMap"
+	cm.ManagedFields = nil
+	return &Backup{
+		CreatedAt:       time.Now().UTC(),
+		ResourceVersion: cm.GetResourceVersion(),
+		ConfigMap:       cm,
+	}, nil
+}
+
+// Restore applies the configuration stored on the backup, creating the
+// ConfigMap or, when overwrite is enabled, replacing the existing one.
+// Configuration backed up before versioning keeps the initial version, thus
+// it's migrated when loaded.
+func (m *ConfigMapManager) Restore(
+	ctx context.Context,
+	b *Backup,
+	overwrite bool,
+) error {
+	cfg, err := b.Config(m.appName)
+	if err != nil {
+		return err
+	}
+	if cfg.Installer.Version == 0 {
+		if err = cfg.SetVersion(InitialVersion); err != nil {
+			return err
+		}
+	}
+	err = m.Create(ctx, cfg)
+	if apierrors.IsAlreadyExists(err) {
+		if !overwrite {
+			return fmt.Errorf(
+				"the configuration already exists, use --force to replace it")
+		}
+		return m.Update(ctx, cfg)
+	}
+	return err
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// FieldChange a configuration field changed on the cluster, nil when the field
+// is absent. The path follows the same notation as Change.
+type FieldChange struct {
+	Path string `json:"path"`
+	From any    `json:"from"`
+	To   any    `json:"to"`
+}
+
+// ChangeEvent describes a change to the configuration stored in the cluster:
+// who changed it, when, and the configuration fields changed. The actor is the
+// field manager, from the resource's managedFields, which last wrote the
+// configuration payload.
+type ChangeEvent struct {
+	Time            time.Time       `json:"time"`
+	Type            watch.EventType `json:"type"`
+	Namespace       string          `json:"namespace"`
+	Name            string          `json:"name"`
+	ResourceVersion string          `json:"resourceVersion"`
+	Manager         string          `json:"manager,omitempty"`
+	Operation       string          `json:"operation,omitempty"`
+	Changes         []FieldChange   `json:"changes"`
+}
+
+// ChangeTracker follows the configuration resource watch events, comparing each
+// configuration with the previously observed, to describe the changes.
+type ChangeTracker struct {
+	appName  string  // config root key
+	previous *Config // last configuration observed
+}
+
+// payloadFields the managed fields entries touching the configuration payload,
+// on the ConfigMap and on the custom resource specification.
+var payloadFields = [][]byte{[]byte(`"f:data"`), []byte(`"f:binaryData"`)}
+
+// lastWriter returns the managed fields entry which last wrote the configuration
+// payload, or the latest entry when none is found.
+func lastWriter(entries []metav1.ManagedFieldsEntry) *metav1.ManagedFieldsEntry {
+	var latest, writer *metav1.ManagedFieldsEntry
+	newer := func(candidate, current *metav1.ManagedFieldsEntry) bool {
+		if current == nil {
+			return true
+		}
+		if candidate.Time == nil {
+			return false
+		}
+		return current.Time == nil || !candidate.Time.Before(current.Time)
+	}
+	for i := range entries {
+		entry := &entries[i]
+		if newer(entry, latest) {
+			latest = entry
+		}
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		for _, field := range payloadFields {
+			if bytes.Contains(entry.FieldsV1.Raw, field) && newer(entry, writer) {
+				writer = entry
+				break
+			}
+		}
+	}
+	if writer != nil {
+		return writer
+	}
+	return latest
+}
+
+// eventConfigMap returns the event object as a ConfigMap, translating the
+// custom resource when the configuration is stored as HelmetInstallation.
+func eventConfigMap(obj any) (*corev1.ConfigMap, error) {
+	switch o := obj.(type) {
+	case *corev1.ConfigMap:
+		return o, nil
+	case *unstructured.Unstructured:
+		cm, err := toConfigMap(o)
+		if err != nil {
+			return nil, err
+		}
+		cm.ManagedFields = o.GetManagedFields()
+		return cm, nil
+	default:
+		return nil, fmt.Errorf("unexpected watch event object %T", obj)
+	}
+}
+
+// Track describes the watch event. The first configuration observed, and the
+// deleted, don't carry field changes. Watch errors are returned as such.
+func (t *ChangeTracker) Track(event watch.Event) (*ChangeEvent, error) {
+	if event.Type == watch.Error {
+		return nil, fmt.Errorf("watching the configuration: %v", event.Object)
+	}
+	cm, err := eventConfigMap(event.Object)
+	if err != nil {
+		return nil, err
+	}
+	change := &ChangeEvent{
+		Time:            time.Now(),
+		Type:            event.Type,
+		Namespace:       cm.GetNamespace(),
+		Name:            cm.GetName(),
+		ResourceVersion: cm.GetResourceVersion(),
+		Changes:         []FieldChange{},
+	}
+	if writer := lastWriter(cm.GetManagedFields()); writer != nil {
+		change.Manager = writer.Manager
+		change.Operation = string(writer.Operation)
+		if writer.Time != nil && event.Type != watch.Deleted {
+			change.Time = writer.Time.Time
+		}
+	}
+	if event.Type == watch.Deleted {
+		t.previous = nil
+		return change, nil
+	}
+
+	payload, err := ConfigMapPayload(cm)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := NewConfigFromBytes(payload, cm.GetNamespace(), t.appName)
+	if err != nil {
+		return nil, err
+	}
+	previous := t.previous
+	t.previous = cfg
+	if previous == nil {
+		return change, nil
+	}
+	drift, err := Diff(cfg, previous)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range drift.Changes {
+		change.Changes = append(change.Changes, FieldChange{
+			Path: c.Path,
+			From: c.Cluster,
+			To:   c.Local,
+		})
+	}
+	return change, nil
+}
+
+// ChangeTracker instantiates a tracker for the configuration events, see Watch.
+func (m *ConfigMapManager) ChangeTracker() *ChangeTracker {
+	return &ChangeTracker{appName: m.appName}
+}
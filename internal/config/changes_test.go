@@ -0,0 +1,117 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func TestChangeTracker(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := NewConfigFromFile(
+		cfs, "config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+	m := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex")
+
+	earlier := metav1.NewTime(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(earlier.Add(time.Minute))
+	managedFields := []metav1.ManagedFieldsEntry{{
+		Manager:   "helmet-ex",
+		Operation: metav1.ManagedFieldsOperationUpdate,
+		Time:      &earlier,
+		FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:data":{}}`)},
+	}, {
+		Manager:   "kubectl-label",
+		Operation: metav1.ManagedFieldsOperationUpdate,
+		Time:      &later,
+		FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{}}`)},
+	}}
+
+	tracker := m.ChangeTracker()
+
+	t.Run("Added", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cm, err := m.configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+		cm.ManagedFields = managedFields
+
+		event, err := tracker.Track(watch.Event{Type: watch.Added, Object: cm})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(event.Type).To(o.Equal(watch.Added))
+		g.Expect(event.Namespace).To(o.Equal("test-namespace"))
+		g.Expect(event.Name).To(o.Equal("helmet-ex-config"))
+		// The payload writer is reported, not the latest manager.
+		g.Expect(event.Manager).To(o.Equal("helmet-ex"))
+		g.Expect(event.Time).To(o.Equal(earlier.Time))
+		g.Expect(event.Changes).To(o.BeEmpty())
+	})
+
+	t.Run("Modified", func(t *testing.T) {
+		g := o.NewWithT(t)
+		edited, err := NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(edited.SetPath("settings.crc", true)).To(o.Succeed())
+		cm, err := m.configMapForConfig(edited)
+		g.Expect(err).To(o.Succeed())
+		cm.ManagedFields = append(managedFields, metav1.ManagedFieldsEntry{
+			Manager:   "kubectl-edit",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			Time:      &later,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(`{"f:data":{}}`)},
+		})
+
+		event, err := tracker.Track(watch.Event{Type: watch.Modified, Object: cm})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(event.Manager).To(o.Equal("kubectl-edit"))
+		g.Expect(event.Operation).To(o.Equal("Update"))
+		g.Expect(event.Changes).To(o.Equal([]FieldChange{
+			{Path: "settings.crc", From: false, To: true},
+		}))
+	})
+
+	t.Run("CustomResource", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cm, err := m.configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+		u := fromConfigMap(cm)
+		u.SetManagedFields(managedFields)
+
+		event, err := tracker.Track(watch.Event{Type: watch.Modified, Object: u})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(event.Manager).To(o.Equal("helmet-ex"))
+		g.Expect(event.Changes).To(o.Equal([]FieldChange{
+			{Path: "settings.crc", From: true, To: false},
+		}))
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cm, err := m.configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+
+		event, err := tracker.Track(watch.Event{Type: watch.Deleted, Object: cm})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(event.Type).To(o.Equal(watch.Deleted))
+		g.Expect(event.Changes).To(o.BeEmpty())
+		g.Expect(tracker.previous).To(o.BeNil())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := tracker.Track(watch.Event{
+			Type:   watch.Error,
+			Object: &metav1.Status{Message: "expired"},
+		})
+		g.Expect(err).To(o.HaveOccurred())
+	})
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ClusterKey the document key naming the cluster, the kubeconfig context, each
+// document of a multi-document configuration targets.
+const ClusterKey = "cluster"
+
+var (
+	// ErrClusterNotFound the configuration doesn't target the selected cluster.
+	ErrClusterNotFound = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+		"cluster not found on the configuration")
+	// ErrClusterRequired the configuration targets multiple clusters, and none
+	// is selected.
+	ErrClusterRequired = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+		"configuration targets multiple clusters")
+)
+
+// WithCluster selects the document targeting the named cluster, on
+// multi-document configurations. The cluster name is the document "cluster"
+// key, documents without it apply to any cluster.
+func WithCluster(name string) Option {
+	return func(c *Config) {
+		c.cluster = name
+	}
+}
+
+// documentCluster returns the cluster targeted by the document, empty when the
+// document doesn't declare it.
+func documentCluster(doc *yaml.Node) string {
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return ""
+	}
+	m := doc.Content[0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == ClusterKey {
+			return m.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// removeCluster removes the cluster key from the document, the cluster holds a
+// single configuration document.
+func removeCluster(doc *yaml.Node) {
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return
+	}
+	m := doc.Content[0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == ClusterKey {
+			m.Content = slices.Delete(m.Content, i, i+2)
+			return
+		}
+	}
+}
+
+// selectDocument decodes the YAML documents of the payload and returns the one
+// targeting the selected cluster. The document naming the cluster is preferred,
+// otherwise the single document without a cluster is used. Without a cluster
+// selected, the payload must carry a single document.
+func (c *Config) selectDocument(payload []byte) (yaml.Node, error) {
+	docs := []*yaml.Node{}
+	decoder := yaml.NewDecoder(bytes.NewReader(payload))
+	for {
+		doc := &yaml.Node{}
+		err := decoder.Decode(doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return yaml.Node{}, fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+		}
+		docs = append(docs, doc)
+	}
+	if len(docs) == 0 {
+		return yaml.Node{}, nil
+	}
+
+	var selected, fallback *yaml.Node
+	clusters := []string{}
+	for _, doc := range docs {
+		name := documentCluster(doc)
+		if name == "" {
+			if fallback != nil {
+				return yaml.Node{}, fmt.Errorf(
+					"%w: multiple documents without %q", ErrInvalidConfig,
+					ClusterKey)
+			}
+			fallback = doc
+			continue
+		}
+		if slices.Contains(clusters, name) {
+			return yaml.Node{}, fmt.Errorf("%w: cluster %q declared twice",
+				ErrInvalidConfig, name)
+		}
+		clusters = append(clusters, name)
+		if name == c.cluster {
+			selected = doc
+		}
+	}
+
+	switch {
+	case selected != nil:
+	case c.cluster == "" && len(docs) == 1:
+		selected = docs[0]
+	case c.cluster == "":
+		return yaml.Node{}, fmt.Errorf(
+			"%w: select one of the clusters %v", ErrClusterRequired, clusters)
+	case fallback != nil:
+		selected = fallback
+	default:
+		return yaml.Node{}, fmt.Errorf("%w: %q, available clusters %v",
+			ErrClusterNotFound, c.cluster, clusters)
+	}
+	removeCluster(selected)
+	return *selected, nil
+}
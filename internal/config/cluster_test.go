@@ -0,0 +1,92 @@
+package config
+
+import (
+	"slices"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestConfigCluster(t *testing.T) {
+	payload := []byte(`---
+cluster: hub
+tssc:
+  settings:
+    crc: false
+  products:
+    - name: Product A
+      enabled: true
+      namespace: hub-products
+---
+cluster: spoke
+tssc:
+  settings:
+    crc: false
+  products:
+    - name: Product A
+      enabled: false
+      namespace: spoke-products
+`)
+	withDefault := slices.Concat([]byte(`---
+tssc:
+  settings:
+    crc: false
+  products:
+    - name: Product A
+      enabled: true
+      namespace: default-products
+`), payload)
+
+	tests := []struct {
+		name      string
+		payload   []byte
+		cluster   string
+		namespace string
+		err       error
+	}{{
+		name:      "cluster document",
+		payload:   payload,
+		cluster:   "spoke",
+		namespace: "spoke-products",
+	}, {
+		name:    "cluster required",
+		payload: payload,
+		err:     ErrClusterRequired,
+	}, {
+		name:    "cluster not found",
+		payload: payload,
+		cluster: "edge",
+		err:     ErrClusterNotFound,
+	}, {
+		name:      "document without cluster",
+		payload:   withDefault,
+		cluster:   "edge",
+		namespace: "default-products",
+	}, {
+		name:      "cluster document preferred",
+		payload:   withDefault,
+		cluster:   "hub",
+		namespace: "hub-products",
+	}, {
+		name:    "duplicated cluster",
+		payload: slices.Concat(payload, payload),
+		cluster: "hub",
+		err:     ErrInvalidConfig,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			cfg, err := NewConfigFromBytes(
+				tt.payload, "helmet", "tssc", WithCluster(tt.cluster))
+			if tt.err != nil {
+				g.Expect(err).To(o.MatchError(tt.err))
+				return
+			}
+			g.Expect(err).To(o.Succeed())
+			product, err := cfg.GetProduct("Product A")
+			g.Expect(err).To(o.Succeed())
+			g.Expect(product.GetNamespace()).To(o.Equal(tt.namespace))
+			g.Expect(cfg.String()).NotTo(o.ContainSubstring(ClusterKey + ":"))
+		})
+	}
+}
@@ -2,10 +2,13 @@ package config
 
 import (
 	"bytes"
-	"errors"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strconv"
 	"strings"
 
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
 	"github.com/redhat-appstudio/helmet/internal/constants"
 
@@ -15,34 +18,73 @@ import (
 // Settings represents a map of configuration settings.
 type Settings map[string]interface{}
 
+// AsMap returns the value as a map, nested maps are decoded with the type of
+// the enclosing map, like Settings.
+func AsMap(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, true
+	case Settings:
+		return m, true
+	default:
+		return nil, false
+	}
+}
+
 // ProductSpec represents a map of product name and specification.
 type Products []Product
 
 // Spec contains all configuration sections.
 type Spec struct {
+	// Version configuration schema version, see Migrations.
+	Version int `yaml:"version,omitempty"`
 	// Settings contains the configuration for the installer settings.
 	Settings Settings `yaml:"settings"`
 	// Products contains the configuration for the installer products.
 	Products Products `yaml:"products"`
+	// Webhooks contains the HTTP endpoints notified about deploy events.
+	Webhooks []Webhook `yaml:"webhooks,omitempty"`
+	// Environments contains the overlays applied per environment.
+	Environments map[string]Overlay `yaml:"environments,omitempty"`
 }
 
 // Config root configuration structure.
 type Config struct {
-	cfs       *chartfs.ChartFS // embedded filesystem
-	root      yaml.Node        // yaml data representation
-	namespace string           // installer's namespace
-	appName   string           // dynamic root key name
+	cfs        *chartfs.ChartFS  // embedded filesystem
+	root       yaml.Node         // yaml data representation
+	namespace  string            // installer's namespace
+	appName    string            // dynamic root key name
+	expandEnv  bool              // expand environment variables on load
+	envVars    map[string]string // variables informed, expanded first
+	envAllow   []string          // environment variables allowed, patterns
+	cluster    string            // cluster document to load
+	transforms Transforms        // application transforms, applied on load
+	layered    bool              // merge the configuration layers on load
+	defaults   Defaults          // application defaults layer
+
+	// resourceVersion the cluster resource version the configuration was read
+	// from, guarding updates against concurrent changes.
+	resourceVersion string
+	// sequence the mutation sequence number of the cluster configuration, and
+	// mutatedBy the actor applying it, see ConfigMapSequence.
+	sequence  int64
+	mutatedBy string
+
+	layers map[string]map[string]any // fields defined by each layer
 
 	Installer Spec `yaml:"-"` // root configuration for the installer
 }
 
 var (
 	// ErrInvalidConfig indicates the configuration content is invalid.
-	ErrInvalidConfig = errors.New("invalid configuration")
+	ErrInvalidConfig = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+		"invalid configuration")
 	// ErrEmptyConfig indicates the configuration file is empty.
-	ErrEmptyConfig = errors.New("empty configuration")
+	ErrEmptyConfig = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+		"empty configuration")
 	// ErrUnmarshalConfig indicates the configuration file structure is invalid.
-	ErrUnmarshalConfig = errors.New("failed to unmarshal configuration")
+	ErrUnmarshalConfig = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+		"failed to unmarshal configuration")
 )
 
 // DefaultRelativeConfigPath default relative path to YAML configuration file.
@@ -94,38 +136,108 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("%w: missing settings", ErrInvalidConfig)
 	}
 
+	// The global sizing profile must be known.
+	if _, err := c.GlobalSizing(); err != nil {
+		return err
+	}
+
 	// Validating the products, making sure every product entry is valid.
 	for _, product := range root.Products {
 		if err := product.Validate(); err != nil {
 			return err
 		}
 	}
+	// Enabled products must satisfy their requires and conflicts constraints.
+	for _, product := range root.Products {
+		if err := c.CheckConstraints(
+			product.Name, product.Requires, product.Conflicts); err != nil {
+			return err
+		}
+	}
+
+	// Validating the webhooks, names must be unique.
+	names := map[string]bool{}
+	for _, webhook := range root.Webhooks {
+		if err := webhook.Validate(); err != nil {
+			return err
+		}
+		if names[webhook.Name] {
+			return fmt.Errorf("%w: webhook %q: duplicated name",
+				ErrInvalidConfig, webhook.Name)
+		}
+		names[webhook.Name] = true
+	}
+
+	// Validating the environment overlays, products must exist.
+	for name, overlay := range root.Environments {
+		if err := overlay.validate(name, root.Products); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// DecodeNode returns a struct converted from *yaml.Node.
-func (c *Config) DecodeNode() error {
+// appNode returns the application root node of the configuration.
+func (c *Config) appNode() (*yaml.Node, error) {
 	if len(c.root.Content) == 0 {
-		return fmt.Errorf("invalid configuration: content is empty")
+		return nil, fmt.Errorf("invalid configuration: content is empty")
 	}
 	doc := c.root.Content[0]
 	if doc.Kind != yaml.MappingNode || len(doc.Content) < 2 {
-		return fmt.Errorf("invalid configuration: root must be a mapping")
+		return nil, fmt.Errorf("invalid configuration: root must be a mapping")
 	}
-	var appNode *yaml.Node
 	for i := 0; i+1 < len(doc.Content); i += 2 {
 		if doc.Content[i].Value == c.appName {
-			appNode = doc.Content[i+1]
-			break
+			return doc.Content[i+1], nil
 		}
 	}
-	if appNode == nil {
-		return fmt.Errorf("invalid configuration: missing '%s' key", c.appName)
+	return nil, fmt.Errorf("invalid configuration: missing '%s' key", c.appName)
+}
+
+// DecodeNode returns a struct converted from *yaml.Node.
+func (c *Config) DecodeNode() error {
+	appNode, err := c.appNode()
+	if err != nil {
+		return err
 	}
-	if err := appNode.Decode(&c.Installer); err != nil {
+	c.Installer = Spec{}
+	return appNode.Decode(&c.Installer)
+}
+
+// Version returns the configuration schema version, payloads without the
+// "version" field are considered InitialVersion.
+func (c *Config) Version() int {
+	if c.Installer.Version == 0 {
+		return InitialVersion
+	}
+	return c.Installer.Version
+}
+
+// SetVersion sets the configuration schema version, adding the "version" field
+// on top of the application root node when missing.
+func (c *Config) SetVersion(version int) error {
+	appNode, err := c.appNode()
+	if err != nil {
 		return err
 	}
-	return nil
+	if appNode.Kind != yaml.MappingNode {
+		return fmt.Errorf("invalid configuration: '%s' must be a mapping",
+			c.appName)
+	}
+	value := &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   "!!int",
+		Value: strconv.Itoa(version),
+	}
+	for i := 0; i+1 < len(appNode.Content); i += 2 {
+		if appNode.Content[i].Value == "version" {
+			appNode.Content[i+1] = value
+			return c.DecodeNode()
+		}
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	appNode.Content = append([]*yaml.Node{key, value}, appNode.Content...)
+	return c.DecodeNode()
 }
 
 // Set returns new configuration with updates.
@@ -145,13 +257,10 @@ func (c *Config) Set(key string, configData any) error {
 	return c.DecodeNode()
 }
 
-// SetProduct updates an existing product specification in the configuration. It
-// searches for a product by its name and, if found, replaces its specification
-// with the provided `spec`. The configuration is then re-decoded to reflect the
-// changes.
-func (c *Config) SetProduct(name string, spec Product) error {
+// productsNode returns the products sequence node of the configuration.
+func (c *Config) productsNode() (*yaml.Node, error) {
 	if len(c.root.Content) == 0 {
-		return fmt.Errorf("invalid configuration: content is empty")
+		return nil, fmt.Errorf("invalid configuration: content is empty")
 	}
 	doc := c.root.Content[0]
 
@@ -163,7 +272,8 @@ func (c *Config) SetProduct(name string, spec Product) error {
 		}
 	}
 	if appNode == nil {
-		return fmt.Errorf("invalid configuration: missing '%s' key", c.appName)
+		return nil, fmt.Errorf(
+			"invalid configuration: missing '%s' key", c.appName)
 	}
 
 	var productsNode *yaml.Node
@@ -174,11 +284,46 @@ func (c *Config) SetProduct(name string, spec Product) error {
 		}
 	}
 	if productsNode == nil {
-		return fmt.Errorf("invalid configuration: missing 'products' key")
+		return nil, fmt.Errorf("invalid configuration: missing 'products' key")
 	}
 
 	if productsNode.Kind != yaml.SequenceNode {
-		return fmt.Errorf("'products' is not a sequence")
+		return nil, fmt.Errorf("'products' is not a sequence")
+	}
+	return productsNode, nil
+}
+
+// AddProduct appends a new product to the configuration, the product name must
+// be unique.
+func (c *Config) AddProduct(spec Product) error {
+	if _, err := c.GetProduct(spec.Name); err == nil {
+		return fmt.Errorf("%w: product %q already exists",
+			ErrInvalidConfig, spec.Name)
+	}
+	if err := spec.Validate(); err != nil {
+		return err
+	}
+	productsNode, err := c.productsNode()
+	if err != nil {
+		return err
+	}
+
+	var productNode yaml.Node
+	if err = productNode.Encode(spec); err != nil {
+		return fmt.Errorf("failed to encode product spec: %w", err)
+	}
+	productsNode.Content = append(productsNode.Content, &productNode)
+	return c.DecodeNode()
+}
+
+// SetProduct updates an existing product specification in the configuration. It
+// searches for a product by its name and, if found, replaces its specification
+// with the provided `spec`. The configuration is then re-decoded to reflect the
+// changes.
+func (c *Config) SetProduct(name string, spec Product) error {
+	productsNode, err := c.productsNode()
+	if err != nil {
+		return err
 	}
 
 	for i, productNode := range productsNode.Content {
@@ -249,20 +394,66 @@ func (c *Config) MarshalYAML() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// Hash returns the SHA-256 of the configuration payload, hex encoded, telling
+// apart the configurations used by the deployments.
+func (c *Config) Hash() (string, error) {
+	payload, err := c.MarshalYAML()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // UnmarshalYAML Un-marshals the YAML payload into the Config struct, checking the
 // validity of the configuration.
 func (c *Config) UnmarshalYAML(payload []byte) error {
 	if len(payload) == 0 {
 		return ErrEmptyConfig
 	}
-	if err := yaml.Unmarshal(payload, &c.root); err != nil {
-		return fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	var err error
+	if c.root, err = c.selectDocument(payload); err != nil {
+		return err
+	}
+	if c.expandEnv {
+		if err = c.expandEnvNode(&c.root); err != nil {
+			return err
+		}
 	}
+	return c.decode()
+}
+
+// decode decodes the YAML node tree, applies the defaults and the transforms,
+// checking the validity of the configuration.
+func (c *Config) decode() error {
 	if err := c.DecodeNode(); err != nil {
 		return fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
 	}
 	c.ApplyDefaults()
-	return c.Validate()
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	return c.transforms.Apply(c)
+}
+
+// DeepCopy returns an independent copy of the configuration.
+func (c *Config) DeepCopy() (*Config, error) {
+	payload, err := c.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	cp := &Config{
+		cfs:             c.cfs,
+		namespace:       c.namespace,
+		appName:         c.appName,
+		resourceVersion: c.resourceVersion,
+		sequence:        c.sequence,
+		mutatedBy:       c.mutatedBy,
+	}
+	if err = cp.UnmarshalYAML(payload); err != nil {
+		return nil, err
+	}
+	return cp, nil
 }
 
 // String returns this configuration as string, indented with two spaces.
@@ -274,15 +465,27 @@ func (c *Config) String() string {
 	return string(data)
 }
 
+// Option represents a functional option for loading the configuration.
+type Option func(*Config)
+
 // NewConfigFromFile returns a new Config instance based on the informed file.
 func NewConfigFromFile(
 	cfs *chartfs.ChartFS,
 	configPath string,
 	namespace string,
 	appName string,
+	opts ...Option,
 ) (*Config, error) {
 	c := &Config{cfs: cfs, namespace: namespace, appName: appName}
-	var err error
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.layered {
+		if err := c.loadLayers(configPath); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
 	payload, err := c.cfs.ReadFile(configPath)
 	if err != nil {
 		return nil, err
@@ -298,8 +501,12 @@ func NewConfigFromBytes(
 	payload []byte,
 	namespace string,
 	appName string,
+	opts ...Option,
 ) (*Config, error) {
 	c := &Config{namespace: namespace, appName: appName}
+	for _, opt := range opts {
+		opt(c)
+	}
 	if err := c.UnmarshalYAML(payload); err != nil {
 		return nil, err
 	}
@@ -312,6 +519,8 @@ func NewConfigDefault(
 	cfs *chartfs.ChartFS,
 	namespace string,
 	appName string,
+	opts ...Option,
 ) (*Config, error) {
-	return NewConfigFromFile(cfs, DefaultRelativeConfigPath, namespace, appName)
+	return NewConfigFromFile(
+		cfs, DefaultRelativeConfigPath, namespace, appName, opts...)
 }
@@ -25,6 +25,88 @@ func TestNewConfigFromFile(t *testing.T) {
 		g.Expect(err).To(o.Succeed())
 	})
 
+	t.Run("Hash", func(t *testing.T) {
+		g := o.NewWithT(t)
+		hash, err := cfg.Hash()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(hash).To(o.HaveLen(64))
+
+		copied, err := cfg.DeepCopy()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(copied.Hash()).To(o.Equal(hash))
+		g.Expect(copied.SetVersion(copied.Version() + 1)).To(o.Succeed())
+		g.Expect(copied.Hash()).NotTo(o.Equal(hash))
+	})
+
+	t.Run("ValidateWebhooks", func(t *testing.T) {
+		webhook := Webhook{
+			Name:   "cmdb",
+			URL:    "https://cmdb.example.com/hooks",
+			Events: []string{WebhookEventCompleted, WebhookEventFailed},
+		}
+		g.Expect(webhook.Validate()).To(o.Succeed())
+		g.Expect(webhook.Subscribed(WebhookEventStarted)).To(o.BeFalse())
+		g.Expect(webhook.Subscribed(WebhookEventFailed)).To(o.BeTrue())
+
+		for _, invalid := range []Webhook{
+			{URL: webhook.URL},
+			{Name: "cmdb", URL: "cmdb.example.com"},
+			{Name: "cmdb", URL: webhook.URL, Events: []string{"deploy"}},
+			{Name: "cmdb", URL: webhook.URL, SecretRef: &WebhookSecretRef{}},
+		} {
+			g.Expect(invalid.Validate()).To(o.MatchError(ErrInvalidConfig))
+		}
+	})
+
+	t.Run("ValidateConstraints", func(t *testing.T) {
+		g := o.NewWithT(t)
+		constrained := func(products string) error {
+			_, err := NewConfigFromBytes([]byte(`
+helmet_ex:
+  settings: {}
+  products:
+`+products), "test-namespace", "helmet_ex")
+			return err
+		}
+		g.Expect(constrained(`
+    - name: A
+      enabled: true
+      requires: [B]
+      conflicts: [C, Unknown]
+    - name: B
+      enabled: true
+    - name: C
+      enabled: false
+      requires: [Unknown]
+`)).To(o.Succeed())
+		g.Expect(constrained(`
+    - name: A
+      enabled: true
+      requires: [B]
+    - name: B
+      enabled: false
+`)).To(o.MatchError(o.ContainSubstring(
+			`product "A" requires product "B", which is disabled`)))
+		g.Expect(constrained(`
+    - name: A
+      enabled: true
+      conflicts: [C]
+    - name: C
+      enabled: true
+`)).To(o.MatchError(o.ContainSubstring(
+			`product "A" conflicts with product "C"`)))
+		g.Expect(constrained(`
+    - name: A
+      enabled: true
+      requires: [Unknown]
+`)).To(o.MatchError(ErrInvalidConfig))
+		g.Expect(constrained(`
+    - name: A
+      enabled: true
+      requires: [A]
+`)).To(o.MatchError(ErrInvalidConfig))
+	})
+
 	t.Run("GetEnabledProducts", func(t *testing.T) {
 		products := cfg.GetEnabledProducts()
 		g.Expect(products).NotTo(o.BeEmpty())
@@ -154,4 +236,23 @@ func TestNewConfigFromFile(t *testing.T) {
 		g.Expect(err.Error()).To(o.ContainSubstring(
 			"product \"NonExistentProduct\" not found"))
 	})
+
+	t.Run("AddProduct", func(t *testing.T) {
+		namespace := "product-x"
+		err := cfg.AddProduct(Product{
+			Name:      "Product X",
+			Enabled:   true,
+			Namespace: &namespace,
+		})
+		g.Expect(err).To(o.Succeed())
+
+		product, err := cfg.GetProduct("Product X")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.GetNamespace()).To(o.Equal(namespace))
+		g.Expect(cfg.String()).To(o.ContainSubstring("name: Product X"))
+
+		// Product names must be unique.
+		err = cfg.AddProduct(Product{Name: "Product X"})
+		g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+	})
 }
@@ -0,0 +1,355 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// InstallationKind the configuration custom resource kind.
+	InstallationKind = "HelmetInstallation"
+	// InstallationVersion the configuration custom resource API version.
+	InstallationVersion = "v1alpha1"
+)
+
+// InstallationResource the configuration custom resource.
+var InstallationResource = schema.GroupVersionResource{
+	Group:    annotations.RepoURI,
+	Version:  InstallationVersion,
+	Resource: "helmetinstallations",
+}
+
+// crdResource the CustomResourceDefinition resource.
+var crdResource = apiextensionsv1.SchemeGroupVersion.WithResource(
+	"customresourcedefinitions")
+
+// establishTimeout how long to wait for the definition to be served, after it's
+// installed.
+const establishTimeout = 30 * time.Second
+
+// InstallationDefinition returns the HelmetInstallation CustomResourceDefinition.
+// The spec mirrors the ConfigMap data and binaryData, the status records the
+// generation stored by the installer.
+func InstallationDefinition() *apiextensionsv1.CustomResourceDefinition {
+	stringMap := apiextensionsv1.JSONSchemaProps{
+		Type: "object",
+		AdditionalProperties: &apiextensionsv1.JSONSchemaPropsOrBool{
+			Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"},
+		},
+	}
+	return &apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("%s.%s",
+				InstallationResource.Resource, InstallationResource.Group),
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: InstallationResource.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:     InstallationKind,
+				ListKind: InstallationKind + "List",
+				Plural:   InstallationResource.Resource,
+				Singular: "helmetinstallation",
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    InstallationVersion,
+				Served:  true,
+				Storage: true,
+				Subresources: &apiextensionsv1.CustomResourceSubresources{
+					Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+				},
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]apiextensionsv1.JSONSchemaProps{
+							"spec": {
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"data":       stringMap,
+									"binaryData": stringMap,
+								},
+							},
+							"status": {
+								Type: "object",
+								Properties: map[string]apiextensionsv1.JSONSchemaProps{
+									"observedGeneration": {
+										Type:   "integer",
+										Format: "int64",
+									},
+								},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+// crdStore stores the configuration as a HelmetInstallation custom resource.
+type crdStore struct {
+	kube   k8s.Interface     // kubernetes client
+	client dynamic.Interface // dynamic client, instantiated on demand
+}
+
+var _ store = &crdStore{}
+
+// dynamicClient returns the dynamic client.
+func (s *crdStore) dynamicClient() (dynamic.Interface, error) {
+	if s.client != nil {
+		return s.client, nil
+	}
+	client, err := s.kube.DynamicClient("")
+	if err != nil {
+		return nil, err
+	}
+	s.client = client
+	return client, nil
+}
+
+// ensureDefinition installs the HelmetInstallation definition when missing,
+// waiting until it's established.
+func (s *crdStore) ensureDefinition(ctx context.Context) error {
+	client, err := s.dynamicClient()
+	if err != nil {
+		return err
+	}
+	crds := client.Resource(crdResource)
+	crd := InstallationDefinition()
+	_, err = crds.Get(ctx, crd.GetName(), metav1.GetOptions{})
+	if err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+	if err != nil {
+		return err
+	}
+	_, err = crds.Create(ctx, &unstructured.Unstructured{Object: obj},
+		metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("installing %s definition: %w", InstallationKind, err)
+	}
+	return wait.PollUntilContextTimeout(ctx, time.Second, establishTimeout, true,
+		func(ctx context.Context) (bool, error) {
+			u, err := crds.Get(ctx, crd.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return false, nil
+			}
+			current := &apiextensionsv1.CustomResourceDefinition{}
+			err = runtime.DefaultUnstructuredConverter.
+				FromUnstructured(u.Object, current)
+			if err != nil {
+				return false, err
+			}
+			for _, c := range current.Status.Conditions {
+				if c.Type == apiextensionsv1.Established &&
+					c.Status == apiextensionsv1.ConditionTrue {
+					return true, nil
+				}
+			}
+			return false, nil
+		})
+}
+
+// toConfigMap translates the custom resource into a ConfigMap.
+func toConfigMap(u *unstructured.Unstructured) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            u.GetName(),
+			Namespace:       u.GetNamespace(),
+			Labels:          u.GetLabels(),
+			Annotations:     u.GetAnnotations(),
+			ResourceVersion: u.GetResourceVersion(),
+		},
+	}
+	data, _, err := unstructured.NestedStringMap(u.Object, "spec", "data")
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > 0 {
+		cm.Data = data
+	}
+	binaryData, _, err := unstructured.NestedStringMap(
+		u.Object, "spec", "binaryData")
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range binaryData {
+		decoded, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s/%s: invalid binaryData %q: %w",
+				InstallationKind, u.GetNamespace(), u.GetName(), k, err)
+		}
+		if cm.BinaryData == nil {
+			cm.BinaryData = map[string][]byte{}
+		}
+		cm.BinaryData[k] = decoded
+	}
+	return cm, nil
+}
+
+// fromConfigMap translates the ConfigMap into the custom resource.
+func fromConfigMap(cm *corev1.ConfigMap) *unstructured.Unstructured {
+	spec := map[string]any{}
+	if len(cm.Data) > 0 {
+		data := map[string]any{}
+		for k, v := range cm.Data {
+			data[k] = v
+		}
+		spec["data"] = data
+	}
+	if len(cm.BinaryData) > 0 {
+		binaryData := map[string]any{}
+		for k, v := range cm.BinaryData {
+			binaryData[k] = base64.StdEncoding.EncodeToString(v)
+		}
+		spec["binaryData"] = binaryData
+	}
+	u := &unstructured.Unstructured{Object: map[string]any{"spec": spec}}
+	u.SetAPIVersion(InstallationResource.GroupVersion().String())
+	u.SetKind(InstallationKind)
+	u.SetName(cm.GetName())
+	u.SetNamespace(cm.GetNamespace())
+	u.SetLabels(cm.GetLabels())
+	u.SetAnnotations(cm.GetAnnotations())
+	return u
+}
+
+// list lists the custom resources matching Selector.
+func (s *crdStore) list(ctx context.Context) ([]corev1.ConfigMap, error) {
+	client, err := s.dynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.Resource(InstallationResource).Namespace("").
+		List(ctx, metav1.ListOptions{LabelSelector: Selector})
+	if err != nil {
+		// Without the definition installed there's no configuration.
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	items := make([]corev1.ConfigMap, 0, len(list.Items))
+	for i := range list.Items {
+		cm, err := toConfigMap(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *cm)
+	}
+	return items, nil
+}
+
+// observe records the stored generation on the custom resource status, and
+// returns the resulting resource version.
+func (s *crdStore) observe(
+	ctx context.Context,
+	resource dynamic.ResourceInterface,
+	u *unstructured.Unstructured,
+) (string, error) {
+	err := unstructured.SetNestedField(
+		u.Object, u.GetGeneration(), "status", "observedGeneration")
+	if err != nil {
+		return "", err
+	}
+	observed, err := resource.UpdateStatus(ctx, u, metav1.UpdateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return observed.GetResourceVersion(), nil
+}
+
+// create installs the definition, when missing, and creates the custom
+// resource.
+func (s *crdStore) create(ctx context.Context, cm *corev1.ConfigMap) error {
+	if err := s.ensureDefinition(ctx); err != nil {
+		return err
+	}
+	client, err := s.dynamicClient()
+	if err != nil {
+		return err
+	}
+	resource := client.Resource(InstallationResource).
+		Namespace(cm.GetNamespace())
+	created, err := resource.Create(ctx, fromConfigMap(cm),
+		metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	_, err = s.observe(ctx, resource, created)
+	return err
+}
+
+// update replaces the custom resource spec, on the ConfigMap resource version
+// when informed, otherwise on the current one. The ConfigMap is stamped with the
+// resulting resource version.
+func (s *crdStore) update(ctx context.Context, cm *corev1.ConfigMap) error {
+	client, err := s.dynamicClient()
+	if err != nil {
+		return err
+	}
+	resource := client.Resource(InstallationResource).
+		Namespace(cm.GetNamespace())
+	u := fromConfigMap(cm)
+	if cm.GetResourceVersion() == "" {
+		current, err := resource.Get(ctx, cm.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		u.SetResourceVersion(current.GetResourceVersion())
+	} else {
+		u.SetResourceVersion(cm.GetResourceVersion())
+	}
+	updated, err := resource.Update(ctx, u, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	resourceVersion, err := s.observe(ctx, resource, updated)
+	if err != nil {
+		return err
+	}
+	cm.SetResourceVersion(resourceVersion)
+	return nil
+}
+
+// delete deletes the custom resource, the definition is kept.
+func (s *crdStore) delete(ctx context.Context, namespace, name string) error {
+	client, err := s.dynamicClient()
+	if err != nil {
+		return err
+	}
+	return client.Resource(InstallationResource).Namespace(namespace).
+		Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// watch watches the custom resources matching Selector.
+func (s *crdStore) watch(ctx context.Context) (watch.Interface, error) {
+	client, err := s.dynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.Resource(InstallationResource).Namespace("").
+		Watch(ctx, metav1.ListOptions{LabelSelector: Selector})
+}
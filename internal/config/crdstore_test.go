@@ -0,0 +1,110 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestCRDStore(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := NewConfigFromFile(
+		cfs, "config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+
+	// The definition is established, as the API server would report.
+	crd := InstallationDefinition()
+	crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{{
+		Type:   apiextensionsv1.Established,
+		Status: apiextensionsv1.ConditionTrue,
+	}}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(crd)
+	g.Expect(err).To(o.Succeed())
+
+	// newManager instantiates the manager using the custom resource backend,
+	// the dynamic client keeps the state between calls.
+	newManager := func() *ConfigMapManager {
+		client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+			runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{
+				InstallationResource: InstallationKind + "List",
+				crdResource:          "CustomResourceDefinitionList",
+			},
+			&unstructured.Unstructured{Object: obj},
+		)
+		m := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex")
+		m.SetBackend(BackendCRD)
+		m.store.(*crdStore).client = client
+		return m
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(ValidateBackend("")).To(o.Succeed())
+		g.Expect(ValidateBackend(BackendConfigMap)).To(o.Succeed())
+		g.Expect(ValidateBackend(BackendCRD)).To(o.Succeed())
+		g.Expect(ValidateBackend("etcd")).ToNot(o.Succeed())
+	})
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		g := o.NewWithT(t)
+		m := newManager()
+
+		_, err := m.GetConfig(ctx)
+		g.Expect(err).To(o.MatchError(ErrConfigMapNotFound))
+
+		g.Expect(m.Create(ctx, cfg)).To(o.Succeed())
+		stored, err := m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(stored.String()).To(o.Equal(cfg.String()))
+		g.Expect(stored.Namespace()).To(o.Equal("test-namespace"))
+
+		// The custom resource carries the payload and the observed generation.
+		client := m.store.(*crdStore).client
+		u, err := client.Resource(InstallationResource).
+			Namespace("test-namespace").
+			Get(ctx, "helmet-ex-config", metav1.GetOptions{})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(u.GetKind()).To(o.Equal(InstallationKind))
+		g.Expect(u.GetLabels()).
+			To(o.HaveKeyWithValue(annotations.Config, "true"))
+		_, found, err := unstructured.NestedInt64(
+			u.Object, "status", "observedGeneration")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(found).To(o.BeTrue())
+
+		// Compressed payloads survive the binaryData encoding.
+		m.SetCompressionThreshold(1)
+		g.Expect(stored.SetPath("settings.crc", true)).To(o.Succeed())
+		g.Expect(m.Update(ctx, stored)).To(o.Succeed())
+		cm, err := m.GetConfigMap(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cm.Data[FormatKey]).To(o.Equal(FormatGzip))
+		updated, err := m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(updated.String()).To(o.Equal(stored.String()))
+		g.Expect(updated.Sequence()).To(o.Equal(int64(2)))
+
+		g.Expect(m.Delete(ctx)).To(o.Succeed())
+		_, err = client.Resource(InstallationResource).
+			Namespace("test-namespace").
+			Get(ctx, "helmet-ex-config", metav1.GetOptions{})
+		g.Expect(apierrors.IsNotFound(err)).To(o.BeTrue())
+	})
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrConfigDrift the configurations compared are different.
+var ErrConfigDrift = helmeterrors.New(helmeterrors.ErrConfigDrift,
+	"configuration drift detected")
+
+// Change a configuration field with different values, nil when the field is
+// absent. The path is relative to the application root key, products are
+// identified by name, for instance "products.Product A.enabled".
+type Change struct {
+	Path    string `json:"path"`
+	Local   any    `json:"local"`
+	Cluster any    `json:"cluster"`
+}
+
+// Drift the differences between a local configuration and the one stored in the
+// cluster.
+type Drift struct {
+	Changes []Change // fields with different values, sorted by path
+	Unified string   // unified diff of the configuration payloads
+}
+
+// flatten returns the configuration fields as a flat map, keyed by path.
+func flatten(cfg *Config) (map[string]any, error) {
+	root, err := cfg.appNode()
+	if err != nil {
+		return nil, err
+	}
+	return flattenNode(root)
+}
+
+// flattenNode returns the fields of the application root node as a flat map,
+// keyed by path.
+func flattenNode(root *yaml.Node) (map[string]any, error) {
+	var data map[string]any
+	if err := root.Decode(&data); err != nil {
+		return nil, err
+	}
+	// Products are identified by name, regardless of the position on the list.
+	if products, ok := data["products"].([]any); ok {
+		byName := map[string]any{}
+		for i, p := range products {
+			product, ok := p.(map[string]any)
+			if !ok {
+				byName[fmt.Sprint(i)] = p
+				continue
+			}
+			byName[fmt.Sprint(product["name"])] = product
+		}
+		data["products"] = byName
+	}
+	fields := map[string]any{}
+	FlattenMapRecursive(data, "", fields)
+	return fields, nil
+}
+
+// Diff compares the local configuration with the one stored in the cluster.
+func Diff(local, cluster *Config) (*Drift, error) {
+	localFields, err := flatten(local)
+	if err != nil {
+		return nil, err
+	}
+	clusterFields, err := flatten(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for path := range localFields {
+		paths = append(paths, path)
+	}
+	for path := range clusterFields {
+		if _, ok := localFields[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	drift := &Drift{Changes: []Change{}}
+	for _, path := range paths {
+		if !reflect.DeepEqual(localFields[path], clusterFields[path]) {
+			drift.Changes = append(drift.Changes, Change{
+				Path:    path,
+				Local:   localFields[path],
+				Cluster: clusterFields[path],
+			})
+		}
+	}
+	if len(drift.Changes) == 0 {
+		return drift, nil
+	}
+
+	localPayload, err := local.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	clusterPayload, err := cluster.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	drift.Unified, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(clusterPayload)),
+		B:        difflib.SplitLines(string(localPayload)),
+		FromFile: "cluster",
+		ToFile:   "local",
+		Context:  3,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
+
+// Err returns ErrConfigDrift when there are differences.
+func (d *Drift) Err() error {
+	if len(d.Changes) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d field(s) differ", ErrConfigDrift, len(d.Changes))
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestDiff(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	newConfig := func() *Config {
+		cfg, err := NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		return cfg
+	}
+	cluster := newConfig()
+
+	t.Run("Identical", func(t *testing.T) {
+		drift, err := Diff(newConfig(), cluster)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(drift.Changes).To(o.BeEmpty())
+		g.Expect(drift.Unified).To(o.BeEmpty())
+		g.Expect(drift.Err()).To(o.Succeed())
+	})
+
+	t.Run("Drift", func(t *testing.T) {
+		local := newConfig()
+		g.Expect(local.Set("helmet_ex.settings.crc", true)).To(o.Succeed())
+		product, err := local.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		product.Enabled = false
+		g.Expect(local.SetProduct("Product A", *product)).To(o.Succeed())
+
+		drift, err := Diff(local, cluster)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(drift.Changes).To(o.Equal([]Change{
+			{Path: "products.Product A.enabled", Local: false, Cluster: true},
+			{Path: "settings.crc", Local: true, Cluster: false},
+		}))
+		g.Expect(drift.Unified).To(o.ContainSubstring("--- cluster"))
+		g.Expect(drift.Unified).To(o.ContainSubstring("+++ local"))
+		g.Expect(drift.Unified).To(o.ContainSubstring("-    crc: false"))
+		g.Expect(drift.Unified).To(o.ContainSubstring("+    crc: true"))
+		g.Expect(drift.Err()).To(o.MatchError(ErrConfigDrift))
+	})
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"context"
+	"maps"
+
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+)
+
+// DiscoveryRule returns the configuration defaults for the cluster attributes,
+// empty when none apply.
+type DiscoveryRule func(k8s.ClusterDiscovery) Defaults
+
+// DiscoverDefaults inspects the cluster and returns the defaults of every rule,
+// later rules prevailing on the same path, together with the cluster attributes
+// found.
+func DiscoverDefaults(
+	ctx context.Context,
+	kube k8s.Interface,
+	rules []DiscoveryRule,
+) (k8s.ClusterDiscovery, Defaults, error) {
+	d, err := k8s.DiscoverCluster(ctx, kube)
+	if err != nil {
+		return d, nil, err
+	}
+	defaults := Defaults{}
+	for _, rule := range rules {
+		maps.Copy(defaults, rule(d))
+	}
+	return d, defaults, defaults.Validate()
+}
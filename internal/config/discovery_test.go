@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiscoverDefaults(t *testing.T) {
+	ctx := context.Background()
+	kube := k8s.NewFakeKube(&storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "standard",
+			Annotations: map[string]string{
+				"storageclass.kubernetes.io/is-default-class": "true",
+			},
+		},
+	})
+	storageClass := func(d k8s.ClusterDiscovery) Defaults {
+		return Defaults{
+			"settings.storageClass": d.DefaultStorageClass,
+			"settings.crc":          d.CRC,
+		}
+	}
+
+	t.Run("rules", func(t *testing.T) {
+		g := o.NewWithT(t)
+		d, defaults, err := DiscoverDefaults(ctx, kube, []DiscoveryRule{
+			storageClass,
+			func(k8s.ClusterDiscovery) Defaults { return nil },
+			func(k8s.ClusterDiscovery) Defaults {
+				return Defaults{"settings.crc": true}
+			},
+		})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(d.DefaultStorageClass).To(o.Equal("standard"))
+		// The later rules prevail.
+		g.Expect(defaults).To(o.Equal(Defaults{
+			"settings.storageClass": "standard",
+			"settings.crc":          true,
+		}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, _, err := DiscoverDefaults(ctx, kube, []DiscoveryRule{
+			func(k8s.ClusterDiscovery) Defaults {
+				return Defaults{"products[name=": true}
+			},
+		})
+		g.Expect(err).NotTo(o.Succeed())
+	})
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay patches the settings and product properties of the configuration for
+// a named environment, like "dev", "stage" or "prod".
+type Overlay struct {
+	// Settings merged on top of the installer settings.
+	Settings Settings `yaml:"settings,omitempty"`
+	// Products properties merged on top of the existing products.
+	Products []ProductOverlay `yaml:"products,omitempty"`
+}
+
+// ProductOverlay patches the properties of an existing product.
+type ProductOverlay struct {
+	// Name the product name.
+	Name string `yaml:"name"`
+	// Properties merged on top of the product properties.
+	Properties map[string]interface{} `yaml:"properties,omitempty"`
+}
+
+// validate checks the overlay only refers to existing products.
+func (o *Overlay) validate(name string, products Products) error {
+	for _, p := range o.Products {
+		if p.Name == "" {
+			return fmt.Errorf("%w: environment %q: missing product name",
+				ErrInvalidConfig, name)
+		}
+		if !slices.ContainsFunc(products, func(product Product) bool {
+			return product.Name == p.Name
+		}) {
+			return fmt.Errorf("%w: environment %q: product %q not found",
+				ErrInvalidConfig, name, p.Name)
+		}
+	}
+	return nil
+}
+
+// DeepMerge returns the base map patched by the overlay, neither is modified.
+// Maps are merged recursively, any other overlay value, lists included, replaces
+// the base value, and a null overlay value removes the key.
+func DeepMerge(base, overlay map[string]any) map[string]any {
+	merged := make(map[string]any, len(base))
+	for k, v := range base {
+		if m, ok := AsMap(v); ok {
+			v = DeepMerge(m, nil)
+		}
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		overlayMap, overlayIsMap := AsMap(v)
+		baseMap, baseIsMap := AsMap(merged[k])
+		switch {
+		case overlayIsMap && baseIsMap:
+			merged[k] = DeepMerge(baseMap, overlayMap)
+		case overlayIsMap:
+			merged[k] = DeepMerge(nil, overlayMap)
+		default:
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// setMappingKey sets the key on the mapping node, appending it when missing.
+func setMappingKey(node *yaml.Node, key string, value any) error {
+	valueNode := &yaml.Node{}
+	if err := valueNode.Encode(value); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = valueNode
+			return nil
+		}
+	}
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+	node.Content = append(node.Content, keyNode, valueNode)
+	return nil
+}
+
+// EnvironmentNames returns the environment overlays names, sorted.
+func (c *Config) EnvironmentNames() []string {
+	return slices.Sorted(maps.Keys(c.Installer.Environments))
+}
+
+// ApplyEnvironment merges the environment overlay on the settings and product
+// properties, see DeepMerge. The "environments" section is removed afterwards,
+// thus the configuration holds the settings for a single environment.
+func (c *Config) ApplyEnvironment(name string) error {
+	overlay, ok := c.Installer.Environments[name]
+	if !ok {
+		return fmt.Errorf("%w: environment %q not found, available: %v",
+			ErrInvalidConfig, name, c.EnvironmentNames())
+	}
+	appNode, err := c.appNode()
+	if err != nil {
+		return err
+	}
+	if err = setMappingKey(appNode, "settings", DeepMerge(
+		c.Installer.Settings, overlay.Settings,
+	)); err != nil {
+		return err
+	}
+
+	productsNode, err := c.productsNode()
+	if err != nil {
+		return err
+	}
+	for _, p := range overlay.Products {
+		// Products are validated, the overlay refers to existing products only.
+		i := slices.IndexFunc(c.Installer.Products, func(product Product) bool {
+			return product.Name == p.Name
+		})
+		if err = setMappingKey(productsNode.Content[i], "properties", DeepMerge(
+			c.Installer.Products[i].Properties, p.Properties,
+		)); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i+1 < len(appNode.Content); i += 2 {
+		if appNode.Content[i].Value == "environments" {
+			appNode.Content = slices.Delete(appNode.Content, i, i+2)
+			break
+		}
+	}
+	if err = c.DecodeNode(); err != nil {
+		return err
+	}
+	c.ApplyDefaults()
+	return c.Validate()
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestDeepMerge(t *testing.T) {
+	base := map[string]any{
+		"crc": false,
+		"ci": map[string]any{
+			"debug": false,
+			"tags":  []any{"a", "b"},
+		},
+		"removed": "value",
+	}
+	tests := []struct {
+		name    string
+		overlay map[string]any
+		want    map[string]any
+	}{{
+		name:    "empty overlay",
+		overlay: nil,
+		want:    base,
+	}, {
+		name: "nested maps are merged",
+		overlay: map[string]any{
+			"ci": map[string]any{"debug": true},
+		},
+		want: map[string]any{
+			"crc": false,
+			"ci": map[string]any{
+				"debug": true,
+				"tags":  []any{"a", "b"},
+			},
+			"removed": "value",
+		},
+	}, {
+		name: "lists and scalars are replaced",
+		overlay: map[string]any{
+			"crc": true,
+			"ci":  map[string]any{"tags": []any{"c"}},
+			"new": map[string]any{"key": "value"},
+		},
+		want: map[string]any{
+			"crc": true,
+			"ci": map[string]any{
+				"debug": false,
+				"tags":  []any{"c"},
+			},
+			"new":     map[string]any{"key": "value"},
+			"removed": "value",
+		},
+	}, {
+		name: "decoded settings are merged",
+		overlay: map[string]any{
+			"ci": Settings{"debug": true},
+		},
+		want: map[string]any{
+			"crc": false,
+			"ci": map[string]any{
+				"debug": true,
+				"tags":  []any{"a", "b"},
+			},
+			"removed": "value",
+		},
+	}, {
+		name: "null removes the key",
+		overlay: map[string]any{
+			"removed": nil,
+			"ci":      "disabled",
+		},
+		want: map[string]any{
+			"crc": false,
+			"ci":  "disabled",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			g.Expect(DeepMerge(base, tt.overlay)).To(o.Equal(tt.want))
+			// The base map is never modified.
+			g.Expect(base["ci"]).To(o.HaveKeyWithValue("debug", false))
+			g.Expect(base).To(o.HaveKey("removed"))
+		})
+	}
+}
+
+func TestApplyEnvironment(t *testing.T) {
+	payload := []byte(`
+helmet_ex:
+  settings:
+    crc: false
+    ci:
+      debug: false
+  products:
+    - name: Product A
+      enabled: true
+    - name: Product B
+      enabled: true
+      properties:
+        storageClass: standard
+        replicas: 1
+  environments:
+    dev:
+      settings:
+        crc: true
+      products:
+        - name: Product A
+          properties:
+            debug: true
+    prod:
+      settings:
+        ci:
+          debug: true
+      products:
+        - name: Product B
+          properties:
+            replicas: 3
+`)
+	newConfig := func(t *testing.T) *Config {
+		g := o.NewWithT(t)
+		cfg, err := NewConfigFromBytes(payload, "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		return cfg
+	}
+
+	t.Run("EnvironmentNames", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(newConfig(t).EnvironmentNames()).
+			To(o.Equal([]string{"dev", "prod"}))
+	})
+
+	t.Run("Dev", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg := newConfig(t)
+		g.Expect(cfg.ApplyEnvironment("dev")).To(o.Succeed())
+		g.Expect(cfg.Installer.Settings).To(o.Equal(Settings{
+			"crc": true,
+			"ci":  Settings{"debug": false},
+		}))
+		product, err := cfg.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Properties).To(o.Equal(map[string]any{"debug": true}))
+		g.Expect(cfg.Installer.Environments).To(o.BeEmpty())
+		g.Expect(cfg.String()).NotTo(o.ContainSubstring("environments"))
+	})
+
+	t.Run("Prod", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg := newConfig(t)
+		g.Expect(cfg.ApplyEnvironment("prod")).To(o.Succeed())
+		g.Expect(cfg.Installer.Settings).To(o.Equal(Settings{
+			"crc": false,
+			"ci":  Settings{"debug": true},
+		}))
+		product, err := cfg.GetProduct("Product B")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Properties).To(o.Equal(map[string]any{
+			"storageClass": "standard",
+			"replicas":     3,
+		}))
+		// The result is deterministic, applying it twice yields the same payload.
+		again := newConfig(t)
+		g.Expect(again.ApplyEnvironment("prod")).To(o.Succeed())
+		g.Expect(again.String()).To(o.Equal(cfg.String()))
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		g := o.NewWithT(t)
+		err := newConfig(t).ApplyEnvironment("stage")
+		g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+		g.Expect(err.Error()).To(o.ContainSubstring("[dev prod]"))
+	})
+
+	t.Run("InvalidProduct", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := NewConfigFromBytes([]byte(`
+helmet_ex:
+  settings: {}
+  products:
+    - name: Product A
+  environments:
+    dev:
+      products:
+        - name: Product Z
+`), "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+	})
+}
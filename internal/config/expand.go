@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WithEnvExpansion expands environment variables on the configuration values,
+// referred as "${VAR}", or "${VAR:-fallback}" for a fallback when the variable
+// is unset or empty. Use "$${VAR}" for a literal "${VAR}".
+func WithEnvExpansion() Option {
+	return func(c *Config) {
+		c.expandEnv = true
+	}
+}
+
+// WithEnvVars expands environment variables, see WithEnvExpansion, resolving the
+// informed variables before the process environment. Being informed explicitly,
+// they're not subject to the allowlist, see WithEnvAllowlist.
+func WithEnvVars(vars map[string]string) Option {
+	return func(c *Config) {
+		c.expandEnv = true
+		if c.envVars == nil {
+			c.envVars = map[string]string{}
+		}
+		maps.Copy(c.envVars, vars)
+	}
+}
+
+// WithEnvAllowlist restricts the process environment variables expanded to the
+// names matching the informed shell patterns, like "HELMET_*", so configuration
+// files can't read unrelated variables, like credentials. Without patterns
+// every variable is allowed.
+func WithEnvAllowlist(patterns ...string) Option {
+	return func(c *Config) {
+		c.envAllow = append(c.envAllow, patterns...)
+	}
+}
+
+// envVarRE matches "${VAR}" and "${VAR:-fallback}" references, including the
+// escaped form "$${VAR}".
+var envVarRE = regexp.MustCompile(
+	`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// lookupEnv looks up the variable, on the informed variables first, then on the
+// process environment when the allowlist permits.
+func (c *Config) lookupEnv(name string) (string, bool, error) {
+	if value, ok := c.envVars[name]; ok {
+		return value, true, nil
+	}
+	allowed := len(c.envAllow) == 0
+	for _, pattern := range c.envAllow {
+		if matched, _ := path.Match(pattern, name); matched {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return "", false, fmt.Errorf(
+			"environment variable %q is not allowed, allowed: %s",
+			name, strings.Join(c.envAllow, ", "))
+	}
+	value, ok := os.LookupEnv(name)
+	return value, ok, nil
+}
+
+// expandEnvString expands the environment variables referred on the informed
+// string, unset variables without fallback are an error, as are variables the
+// allowlist refuses.
+func (c *Config) expandEnvString(s string) (string, error) {
+	var err error
+	expanded := envVarRE.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref[1] == '$' {
+			return ref[1:]
+		}
+		m := envVarRE.FindStringSubmatch(ref)
+		value, ok, lookupErr := c.lookupEnv(m[1])
+		if lookupErr != nil {
+			if err == nil {
+				err = lookupErr
+			}
+			return ref
+		}
+		if ok && (value != "" || m[2] == "") {
+			return value
+		}
+		if m[2] != "" {
+			return m[3]
+		}
+		if err == nil {
+			err = fmt.Errorf("environment variable %q is not set", m[1])
+		}
+		return ref
+	})
+	return expanded, err
+}
+
+// expandEnvNode expands the environment variables on the scalar values of the
+// node tree, mapping keys are kept as is. Plain scalars are typed again after
+// expansion, so "replicas: ${REPLICAS}" becomes an integer, while quoted scalars
+// remain strings.
+func (c *Config) expandEnvNode(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		value, err := c.expandEnvString(node.Value)
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %w", ErrInvalidConfig, node.Line, err)
+		}
+		if value != node.Value {
+			node.Value = value
+			if node.Style == 0 {
+				node.Tag = ""
+			}
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := c.expandEnvNode(node.Content[i]); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, n := range node.Content {
+			if err := c.expandEnvNode(n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
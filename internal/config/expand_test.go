@@ -0,0 +1,138 @@
+package config
+
+import (
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestExpandEnvString(t *testing.T) {
+	t.Setenv("HELMET_NAMESPACE", "helmet-ci")
+	t.Setenv("HELMET_EMPTY", "")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{{
+		name:  "variable",
+		value: "${HELMET_NAMESPACE}-products",
+		want:  "helmet-ci-products",
+	}, {
+		name:  "fallback for unset variable",
+		value: "${HELMET_UNSET:-default}",
+		want:  "default",
+	}, {
+		name:  "fallback for empty variable",
+		value: "${HELMET_EMPTY:-default}",
+		want:  "default",
+	}, {
+		name:  "empty variable without fallback",
+		value: "${HELMET_EMPTY}",
+		want:  "",
+	}, {
+		name:  "escaped reference",
+		value: "$${HELMET_NAMESPACE} $HELMET_NAMESPACE",
+		want:  "${HELMET_NAMESPACE} $HELMET_NAMESPACE",
+	}, {
+		name:    "unset variable",
+		value:   "${HELMET_UNSET}",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			got, err := (&Config{}).expandEnvString(tt.value)
+			if tt.wantErr {
+				g.Expect(err).To(o.HaveOccurred())
+				return
+			}
+			g.Expect(err).To(o.Succeed())
+			g.Expect(got).To(o.Equal(tt.want))
+		})
+	}
+}
+
+func TestNewConfigFromBytesWithEnvExpansion(t *testing.T) {
+	payload := []byte(`---
+tssc:
+  settings:
+    crc: ${HELMET_CRC:-false}
+  products:
+    - name: Product A
+      enabled: true
+      namespace: ${HELMET_NAMESPACE}
+      properties:
+        replicas: ${HELMET_REPLICAS}
+        version: "${HELMET_REPLICAS}"
+`)
+
+	t.Run("disabled", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := NewConfigFromBytes(payload, "helmet", "tssc")
+		g.Expect(err).To(o.Succeed())
+		product, err := cfg.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.GetNamespace()).To(o.Equal("${HELMET_NAMESPACE}"))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		g := o.NewWithT(t)
+		t.Setenv("HELMET_NAMESPACE", "helmet-ci")
+		t.Setenv("HELMET_REPLICAS", "3")
+		cfg, err := NewConfigFromBytes(
+			payload, "helmet", "tssc", WithEnvExpansion())
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cfg.Installer.Settings).To(o.HaveKeyWithValue("crc", false))
+		product, err := cfg.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.GetNamespace()).To(o.Equal("helmet-ci"))
+		g.Expect(product.Properties).To(o.HaveKeyWithValue("replicas", 3))
+		g.Expect(product.Properties).To(o.HaveKeyWithValue("version", "3"))
+		g.Expect(cfg.String()).To(o.ContainSubstring("namespace: helmet-ci"))
+	})
+
+	t.Run("unset variable", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := NewConfigFromBytes(
+			payload, "helmet", "tssc", WithEnvExpansion())
+		g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+		g.Expect(err.Error()).To(o.ContainSubstring(`"HELMET_NAMESPACE"`))
+	})
+	t.Run("informed variables", func(t *testing.T) {
+		g := o.NewWithT(t)
+		t.Setenv("HELMET_NAMESPACE", "helmet-ci")
+		cfg, err := NewConfigFromBytes(payload, "helmet", "tssc",
+			WithEnvVars(map[string]string{
+				"HELMET_NAMESPACE": "helmet-stage",
+				"HELMET_REPLICAS":  "2",
+			}))
+		g.Expect(err).To(o.Succeed())
+		product, err := cfg.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.GetNamespace()).To(o.Equal("helmet-stage"))
+		g.Expect(product.Properties).To(o.HaveKeyWithValue("replicas", 2))
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		g := o.NewWithT(t)
+		t.Setenv("HELMET_NAMESPACE", "helmet-ci")
+		t.Setenv("HELMET_REPLICAS", "3")
+		_, err := NewConfigFromBytes(payload, "helmet", "tssc",
+			WithEnvExpansion(), WithEnvAllowlist("HELMET_NAMESPACE"))
+		g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+		g.Expect(err.Error()).To(o.ContainSubstring(
+			`"HELMET_CRC" is not allowed`))
+
+		// Informed variables aren't subject to the allowlist.
+		cfg, err := NewConfigFromBytes(payload, "helmet", "tssc",
+			WithEnvAllowlist("HELMET_NAME*", "HELMET_CRC"),
+			WithEnvVars(map[string]string{"HELMET_REPLICAS": "2"}))
+		g.Expect(err).To(o.Succeed())
+		product, err := cfg.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.GetNamespace()).To(o.Equal("helmet-ci"))
+		g.Expect(product.Properties).To(o.HaveKeyWithValue("replicas", 2))
+	})
+}
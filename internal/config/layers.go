@@ -0,0 +1,270 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Configuration layers, merged in this order, each one on top of the previous.
+const (
+	// LayerFramework the default configuration embedded on the installer
+	// tarball.
+	LayerFramework = "framework"
+	// LayerApplication the defaults set programmatically by the application.
+	LayerApplication = "application"
+	// LayerUser the configuration file informed by the user.
+	LayerUser = "user"
+)
+
+// Layers the configuration layers, in merge order.
+var Layers = []string{LayerFramework, LayerApplication, LayerUser}
+
+// Defaults the application configuration defaults, the values by configuration
+// path, relative to the application root key, for instance
+// "products[name=Product B].properties.replicas", see SetPath.
+type Defaults map[string]any
+
+// Validate asserts the paths are valid.
+func (d Defaults) Validate() error {
+	for path := range d {
+		if _, err := parsePath(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FieldOrigin a configuration field value and the layer it comes from, the last
+// defining it, together with the value defined on each layer.
+type FieldOrigin struct {
+	Path   string         `json:"path"`
+	Value  any            `json:"value"`
+	Layer  string         `json:"layer"`
+	Layers map[string]any `json:"layers"`
+}
+
+// WithDefaults layers the configuration: the embedded default configuration is
+// patched by the application defaults, and the configuration file informed, when
+// other than the default, is merged on top. See Explain.
+func WithDefaults(defaults Defaults) Option {
+	return func(c *Config) {
+		c.layered = true
+		c.defaults = defaults
+	}
+}
+
+// itemName returns the "name" of the mapping node, empty when absent.
+func itemName(node *yaml.Node) string {
+	if node.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "name" {
+			return node.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// mergeNode merges the overlay node on top of the base, modifying the base.
+// Mappings are merged recursively and a null overlay value removes the key.
+// Lists of named objects, like products, are merged by name, new items are
+// appended. Any other overlay value replaces the base value.
+func mergeNode(base, overlay *yaml.Node) *yaml.Node {
+	switch {
+	case base.Kind == yaml.MappingNode && overlay.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(overlay.Content); i += 2 {
+			key, value := overlay.Content[i], overlay.Content[i+1]
+			idx := -1
+			for j := 0; j+1 < len(base.Content); j += 2 {
+				if base.Content[j].Value == key.Value {
+					idx = j
+					break
+				}
+			}
+			switch {
+			case value.Tag == "!!null" && idx >= 0:
+				base.Content = slices.Delete(base.Content, idx, idx+2)
+			case value.Tag == "!!null":
+			case idx >= 0:
+				base.Content[idx+1] = mergeNode(base.Content[idx+1], value)
+			default:
+				base.Content = append(base.Content, key, value)
+			}
+		}
+		return base
+	case base.Kind == yaml.SequenceNode && overlay.Kind == yaml.SequenceNode:
+		for _, item := range overlay.Content {
+			if itemName(item) == "" {
+				return overlay
+			}
+		}
+		for _, item := range overlay.Content {
+			idx := slices.IndexFunc(base.Content, func(n *yaml.Node) bool {
+				return itemName(n) == itemName(item)
+			})
+			if idx < 0 {
+				base.Content = append(base.Content, item)
+				continue
+			}
+			base.Content[idx] = mergeNode(base.Content[idx], item)
+		}
+		return base
+	default:
+		return overlay
+	}
+}
+
+// readLayer reads the configuration file and returns its application root node,
+// selecting the cluster document and expanding the environment variables as
+// configured.
+func (c *Config) readLayer(configPath string) (*yaml.Node, error) {
+	payload, err := c.cfs.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return c.parseLayer(configPath, payload)
+}
+
+// parseLayer parses the partial configuration payload, read from the informed
+// source, and returns its application root node. The informed options apply to
+// the payload, on top of the configuration environment expansion.
+func (c *Config) parseLayer(
+	source string,
+	payload []byte,
+	opts ...Option,
+) (*yaml.Node, error) {
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyConfig, source)
+	}
+	var err error
+	layer := &Config{
+		appName:   c.appName,
+		cluster:   c.cluster,
+		expandEnv: c.expandEnv,
+		envVars:   c.envVars,
+		envAllow:  c.envAllow,
+	}
+	for _, opt := range opts {
+		opt(layer)
+	}
+	if layer.root, err = layer.selectDocument(payload); err != nil {
+		return nil, err
+	}
+	if layer.expandEnv {
+		if err = layer.expandEnvNode(&layer.root); err != nil {
+			return nil, err
+		}
+	}
+	node, err := layer.appNode()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrUnmarshalConfig, source, err)
+	}
+	return node, nil
+}
+
+// loadLayers loads the embedded default configuration, patched by the
+// application defaults, and merges the informed configuration file on top,
+// recording the fields defined by each layer.
+func (c *Config) loadLayers(configPath string) error {
+	payload, err := c.cfs.ReadFile(DefaultRelativeConfigPath)
+	if err != nil {
+		return err
+	}
+	if len(payload) == 0 {
+		return ErrEmptyConfig
+	}
+	if c.root, err = c.selectDocument(payload); err != nil {
+		return err
+	}
+	if c.expandEnv {
+		if err = c.expandEnvNode(&c.root); err != nil {
+			return err
+		}
+	}
+	c.layers = map[string]map[string]any{}
+	if c.layers[LayerFramework], err = flatten(c); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+
+	// The application defaults, in path order for deterministic results.
+	paths := slices.Sorted(maps.Keys(c.defaults))
+	for _, path := range paths {
+		if err = c.setNode(path, c.defaults[path]); err != nil {
+			return fmt.Errorf("%w: application defaults: %w",
+				ErrInvalidConfig, err)
+		}
+	}
+	fields, err := flatten(c)
+	if err != nil {
+		return err
+	}
+	c.layers[LayerApplication] = map[string]any{}
+	for path, value := range fields {
+		framework, ok := c.layers[LayerFramework][path]
+		if !ok || !reflect.DeepEqual(framework, value) {
+			c.layers[LayerApplication][path] = value
+		}
+	}
+
+	c.layers[LayerUser] = map[string]any{}
+	if configPath != DefaultRelativeConfigPath {
+		user, err := c.readLayer(configPath)
+		if err != nil {
+			return err
+		}
+		if c.layers[LayerUser], err = flattenNode(user); err != nil {
+			return fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+		}
+		root, err := c.appNode()
+		if err != nil {
+			return err
+		}
+		merged := mergeNode(root, user)
+		*root = *merged
+	}
+	return c.decode()
+}
+
+// Explain describes where the value of the configuration fields matching the
+// path, the field itself or the fields below it, come from. The path follows
+// the Change notation, for instance "settings.crc" or "products.Product A".
+// Only layered configurations, see WithDefaults, are explained.
+func (c *Config) Explain(path string) ([]FieldOrigin, error) {
+	if c.layers == nil {
+		return nil, fmt.Errorf("%w: the configuration is not layered",
+			ErrInvalidConfig)
+	}
+	fields, err := flatten(c)
+	if err != nil {
+		return nil, err
+	}
+	origins := []FieldOrigin{}
+	for _, field := range slices.Sorted(maps.Keys(fields)) {
+		if field != path && !strings.HasPrefix(field, path+".") {
+			continue
+		}
+		origin := FieldOrigin{
+			Path:   field,
+			Value:  fields[field],
+			Layer:  LayerFramework,
+			Layers: map[string]any{},
+		}
+		for _, layer := range Layers {
+			if value, ok := c.layers[layer][field]; ok {
+				origin.Layer = layer
+				origin.Layers[layer] = value
+			}
+		}
+		origins = append(origins, origin)
+	}
+	if len(origins) == 0 {
+		return nil, fmt.Errorf("%w: %q not found", ErrInvalidPath, path)
+	}
+	return origins, nil
+}
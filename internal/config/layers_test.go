@@ -0,0 +1,128 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestLayers(t *testing.T) {
+	g := o.NewWithT(t)
+
+	defaultPayload, err := os.ReadFile("../../test/config.yaml")
+	g.Expect(err).To(o.Succeed())
+	cfs := chartfs.New(fstest.MapFS{
+		"config.yaml": {Data: defaultPayload},
+		"user.yaml": {Data: []byte(`---
+helmet_ex:
+  settings:
+    ci:
+      debug: true
+  products:
+    - name: Product B
+      properties:
+        storageClass: fast
+    - name: Product A
+      enabled: false
+`)},
+		"invalid.yaml": {Data: []byte(`---
+other: {}
+`)},
+	})
+	defaults := Defaults{
+		"settings.crc": true,
+		"products[name=Product B].properties.storageClass": "gp3",
+	}
+	load := func(path string) (*Config, error) {
+		return NewConfigFromFile(cfs, path, "test-namespace", "helmet_ex",
+			WithDefaults(defaults))
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := load(DefaultRelativeConfigPath)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cfg.Installer.Settings["crc"]).To(o.BeTrue())
+
+		origins, err := cfg.Explain("settings")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(origins).To(o.Equal([]FieldOrigin{{
+			Path:  "settings.ci.debug",
+			Value: false,
+			Layer: LayerFramework,
+			Layers: map[string]any{
+				LayerFramework: false,
+			},
+		}, {
+			Path:  "settings.crc",
+			Value: true,
+			Layer: LayerApplication,
+			Layers: map[string]any{
+				LayerFramework:   false,
+				LayerApplication: true,
+			},
+		}}))
+	})
+
+	t.Run("User", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := load("user.yaml")
+		g.Expect(err).To(o.Succeed())
+
+		// Products are merged by name, the ones absent are kept.
+		g.Expect(cfg.Installer.Products).To(o.HaveLen(4))
+		productA, err := cfg.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(productA.Enabled).To(o.BeFalse())
+		g.Expect(productA.GetNamespace()).To(o.Equal("helmet-product-a"))
+
+		origins, err := cfg.Explain("products.Product B.properties.storageClass")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(origins).To(o.Equal([]FieldOrigin{{
+			Path:  "products.Product B.properties.storageClass",
+			Value: "fast",
+			Layer: LayerUser,
+			Layers: map[string]any{
+				LayerFramework:   "standard",
+				LayerApplication: "gp3",
+				LayerUser:        "fast",
+			},
+		}}))
+
+		origins, err = cfg.Explain("settings.ci.debug")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(origins[0].Layer).To(o.Equal(LayerUser))
+		g.Expect(origins[0].Value).To(o.BeTrue())
+
+		_, err = cfg.Explain("settings.unknown")
+		g.Expect(err).To(o.MatchError(ErrInvalidPath))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := load("invalid.yaml")
+		g.Expect(err).To(o.MatchError(ErrUnmarshalConfig))
+
+		_, err = NewConfigFromFile(cfs, DefaultRelativeConfigPath,
+			"test-namespace", "helmet_ex", WithDefaults(Defaults{
+				"products[name=Product Z].enabled": true,
+			}))
+		g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+
+		g.Expect(Defaults{"products[name=X": true}.Validate()).
+			To(o.MatchError(ErrInvalidPath))
+	})
+
+	t.Run("NotLayered", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := NewConfigFromFile(cfs, DefaultRelativeConfigPath,
+			"test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		_, err = cfg.Explain("settings")
+		g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+	})
+}
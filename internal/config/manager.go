@@ -2,16 +2,17 @@ package config
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"strings"
 
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 	"github.com/redhat-appstudio/helmet/internal/annotations"
-	"github.com/redhat-appstudio/helmet/internal/constants"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
 )
 
 // ConfigMapManager the actor responsible for managing installer configuration in
@@ -19,9 +20,18 @@ import (
 //
 //nolint:revive
 type ConfigMapManager struct {
-	kube    k8s.Interface // kubernetes client
-	name    string        // configmap name
-	appName string        // config root key
+	kube      k8s.Interface // kubernetes client
+	store     store         // configuration resource store
+	name      string        // configmap name
+	appName   string        // config root key
+	threshold int           // payload size to store compressed
+	actor     string        // actor annotated on the mutations
+
+	protected  ProtectedFields // fields locked for changes
+	sensitive  SensitiveFields // fields stored in the companion secret
+	settings   SettingRegistry // settings known by the application
+	migrations Migrations      // configuration upgrade functions
+	transforms Transforms      // application invariants enforcement
 }
 
 // Selector label selector for installer configuration.
@@ -34,34 +44,35 @@ func (m *ConfigMapManager) Name() string {
 
 var (
 	// ErrConfigMapNotFound when the configmap isn't created in the cluster.
-	ErrConfigMapNotFound = errors.New("cluster configmap not found")
+	ErrConfigMapNotFound = helmeterrors.New(helmeterrors.ErrConfigNotFound,
+		"cluster configmap not found")
 	// ErrMultipleConfigMapFound when the label selector find multiple resources.
-	ErrMultipleConfigMapFound = errors.New("multiple cluster configmaps found")
+	ErrMultipleConfigMapFound = helmeterrors.New(helmeterrors.ErrConflict,
+		"multiple cluster configmaps found")
 	// ErrIncompleteConfigMap when the ConfigMap exists, but doesn't contain the
 	// expected payload.
-	ErrIncompleteConfigMap = errors.New("invalid configmap found in the cluster")
+	ErrIncompleteConfigMap = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+		"invalid configmap found in the cluster")
+	// ErrConfigConflict when the configuration changed in the cluster since it
+	// was retrieved, modified concurrently by another actor.
+	ErrConfigConflict = helmeterrors.New(helmeterrors.ErrConflict,
+		"configuration modified concurrently by another actor")
 )
 
 // GetConfigMap retrieves the ConfigMap from the cluster, checking if a single
-// resource is present.
+// resource is present. With the custom resource backend the HelmetInstallation
+// is returned as a ConfigMap.
 func (m *ConfigMapManager) GetConfigMap(
 	ctx context.Context,
 ) (*corev1.ConfigMap, error) {
-	coreClient, err := m.kube.CoreV1ClientSet("")
-	if err != nil {
-		return nil, err
-	}
-
 	// Listing all ConfigMaps matching the label selector.
-	configMapList, err := coreClient.ConfigMaps("").List(ctx, metav1.ListOptions{
-		LabelSelector: Selector,
-	})
+	items, err := m.store.list(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	// When no ConfigMaps matching criteria is found in the cluster.
-	if len(configMapList.Items) == 0 {
+	if len(items) == 0 {
 		return nil, fmt.Errorf(
 			"%w: using label selector %q",
 			ErrConfigMapNotFound,
@@ -71,9 +82,9 @@ func (m *ConfigMapManager) GetConfigMap(
 	// Also, important to error out when multiple ConfigMaps are present in the
 	// cluster. Collecting and printing out the resources found by the label
 	// selector.
-	if len(configMapList.Items) > 1 {
+	if len(items) > 1 {
 		configMaps := []string{}
-		for _, cm := range configMapList.Items {
+		for _, cm := range items {
 			configMaps = append(
 				configMaps,
 				fmt.Sprintf("%s/%s", cm.GetNamespace(), cm.GetName()),
@@ -85,36 +96,91 @@ func (m *ConfigMapManager) GetConfigMap(
 			configMaps,
 		)
 	}
-	return &configMapList.Items[0], nil
+	return &items[0], nil
 }
 
-// GetConfig retrieves configuration from a cluster's ConfigMap.
+// GetConfig retrieves configuration from a cluster's ConfigMap. Configuration
+// stored with an older version is migrated in memory, the upgraded document is
+// only persisted by the next write, Update or Mutate, or by GetConfigMigrated.
+// Sensitive fields are resolved from the companion Secret.
 func (m *ConfigMapManager) GetConfig(ctx context.Context) (*Config, error) {
+	cfg, _, err := m.GetConfigVersion(ctx)
+	return cfg, err
+}
+
+// GetConfigVersion retrieves the configuration, see GetConfig, and the resource
+// version it was read from, for UpdateVersion.
+func (m *ConfigMapManager) GetConfigVersion(
+	ctx context.Context,
+) (*Config, string, error) {
+	cfg, _, err := m.getConfig(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return cfg, cfg.resourceVersion, nil
+}
+
+// GetConfigMigrated retrieves the configuration, see GetConfig, persisting the
+// upgraded document when the stored one is migrated. Meant for the commands
+// changing the cluster anyway, like "deploy", the reads stay side-effect free.
+func (m *ConfigMapManager) GetConfigMigrated(
+	ctx context.Context,
+) (*Config, error) {
+	cfg, migrated, err := m.getConfig(ctx)
+	if err != nil || !migrated {
+		return cfg, err
+	}
+	if err = m.write(ctx, cfg, cfg.resourceVersion); err != nil {
+		return nil, fmt.Errorf("persisting migrated configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// getConfig retrieves the configuration, see GetConfig, and whether the stored
+// document was migrated.
+func (m *ConfigMapManager) getConfig(ctx context.Context) (*Config, bool, error) {
 	configMap, err := m.GetConfigMap(ctx)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	payload, ok := configMap.Data[constants.ConfigFilename]
-	if !ok || len(payload) == 0 {
-		return nil, fmt.Errorf(
-			"%w: key %q not found in ConfigMap %s/%s",
-			ErrIncompleteConfigMap,
-			constants.ConfigFilename,
-			configMap.GetNamespace(),
-			configMap.GetName(),
-		)
+	payload, err := ConfigMapPayload(configMap)
+	if err != nil {
+		return nil, false, err
 	}
 
-	return NewConfigFromBytes(
-		[]byte(payload),
+	cfg, err := NewConfigFromBytes(
+		payload,
 		configMap.GetNamespace(),
 		m.appName,
 	)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(m.sensitive) > 0 {
+		values, err := m.secretValues(ctx, configMap.GetNamespace())
+		if err != nil {
+			return nil, false, err
+		}
+		if err = m.sensitive.Resolve(cfg, values); err != nil {
+			return nil, false, err
+		}
+	}
+	migrated, err := m.migrations.Migrate(cfg)
+	if err != nil {
+		return nil, false, err
+	}
+	if err = m.transforms.Apply(cfg); err != nil {
+		return nil, false, err
+	}
+	cfg.stamp(configMap)
+	return cfg, migrated, nil
 }
 
 // configMapForConfig generate a ConfigMap resource based on informed Config.
-func (m *ConfigMapManager) configMapForConfig(cfg *Config) *corev1.ConfigMap {
-	return &corev1.ConfigMap{
+func (m *ConfigMapManager) configMapForConfig(
+	cfg *Config,
+) (*corev1.ConfigMap, error) {
+	cm := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      m.name,
 			Namespace: cfg.Namespace(),
@@ -122,52 +188,210 @@ func (m *ConfigMapManager) configMapForConfig(cfg *Config) *corev1.ConfigMap {
 				annotations.Config: "true",
 			},
 		},
-		Data: map[string]string{
-			constants.ConfigFilename: cfg.String(),
-		},
 	}
+	if err := setConfigMapPayload(cm, []byte(cfg.String()), m.threshold); err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
+// SetCompressionThreshold sets the payload size, in bytes, above which the
+// configuration is stored gzip compressed in the ConfigMap binaryData.
+func (m *ConfigMapManager) SetCompressionThreshold(threshold int) {
+	m.threshold = threshold
+}
+
+// SetProtectedFields sets the configuration fields managed by the application,
+// updates changing them are rejected.
+func (m *ConfigMapManager) SetProtectedFields(fields ProtectedFields) {
+	m.protected = fields
+}
+
+// SetSensitiveFields sets the configuration fields holding sensitive values,
+// stored in a Secret named after the ConfigMap rather than in plain text.
+func (m *ConfigMapManager) SetSensitiveFields(fields SensitiveFields) {
+	m.sensitive = fields
+}
+
+// SetBackend sets the resource storing the configuration in the cluster, either
+// BackendConfigMap, the default, or BackendCRD.
+func (m *ConfigMapManager) SetBackend(backend string) {
+	m.store = newStore(m.kube, backend)
+}
+
+// SetSettings sets the settings known by the application, configuration with
+// registered settings not matching their type or allowed values is rejected.
+func (m *ConfigMapManager) SetSettings(settings SettingRegistry) {
+	m.settings = settings
+}
+
+// SetMigrations sets the configuration upgrade functions, applied when the
+// configuration is loaded from the cluster.
+func (m *ConfigMapManager) SetMigrations(migrations Migrations) {
+	m.migrations = migrations
+}
+
+// SetTransforms sets the application transforms, applied after the
+// configuration is loaded from the cluster, and before it's saved.
+func (m *ConfigMapManager) SetTransforms(transforms Transforms) {
+	m.transforms = transforms
 }
 
-// Create Bootstrap a ConfigMap with the provided configuration.
+// SetActor sets the actor annotated on the configuration by the writes, by
+// default ActorCLI.
+func (m *ConfigMapManager) SetActor(actor string) {
+	m.actor = actor
+}
+
+// Create Bootstrap a ConfigMap with the provided configuration. Configuration
+// without version is stamped with the latest version, when the application
+// registers migrations. It returns ErrInvalidSetting when a registered setting
+// is invalid.
 func (m *ConfigMapManager) Create(ctx context.Context, cfg *Config) error {
-	cm := m.configMapForConfig(cfg)
-	coreClient, err := m.kube.CoreV1ClientSet(cfg.Namespace())
+	if err := m.transforms.Apply(cfg); err != nil {
+		return err
+	}
+	if err := m.settings.Check(cfg); err != nil {
+		return err
+	}
+	if cfg.Installer.Version == 0 && len(m.migrations) > 0 {
+		if err := cfg.SetVersion(m.migrations.Latest()); err != nil {
+			return err
+		}
+	}
+	stored, values, err := m.splitSensitive(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	cm, err := m.configMapForConfig(stored)
 	if err != nil {
 		return err
 	}
-	_, err = coreClient.
-		ConfigMaps(cfg.Namespace()).
-		Create(ctx, cm, metav1.CreateOptions{})
-	return err
+	m.annotate(cm, 1)
+	if err = m.store.create(ctx, cm); err != nil {
+		return err
+	}
+	cfg.sequence, cfg.mutatedBy = 1, m.actor
+	if len(m.sensitive) == 0 {
+		return nil
+	}
+	return m.writeSecret(ctx, cfg.Namespace(), values)
 }
 
-// Update updates a ConfigMap with informed configuration.
+// Update updates a ConfigMap with informed configuration. Configuration read
+// from the cluster is only written when the ConfigMap is still on the resource
+// version it was read from, otherwise it returns ErrConfigConflict, see Mutate
+// to retry on conflicts. It returns ErrProtectedField when the update changes a
+// protected field, and ErrInvalidSetting when a registered setting is invalid.
 func (m *ConfigMapManager) Update(ctx context.Context, cfg *Config) error {
-	cm := m.configMapForConfig(cfg)
-	coreClient, err := m.kube.CoreV1ClientSet(cfg.Namespace())
+	return m.UpdateVersion(ctx, cfg, cfg.resourceVersion)
+}
+
+// Mutate applies the mutation on the current cluster configuration, and updates
+// it. When another actor changes the configuration meanwhile, the configuration
+// is read again and the mutation applied on it, up to a few attempts, before
+// returning ErrConfigConflict. The mutation must not have side effects, errors
+// returned by it are returned as is. Returns the updated configuration.
+func (m *ConfigMapManager) Mutate(
+	ctx context.Context,
+	mutate func(*Config) error,
+) (*Config, error) {
+	var cfg *Config
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var err error
+		if cfg, err = m.GetConfig(ctx); err != nil {
+			return err
+		}
+		if err = mutate(cfg); err != nil {
+			return err
+		}
+		return m.Update(ctx, cfg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// UpdateVersion updates the ConfigMap, see Update, only when it's still on the
+// informed resource version, as returned by GetConfigVersion. It returns
+// ErrConfigConflict when the configuration changed meanwhile. An empty
+// resource version updates unconditionally.
+func (m *ConfigMapManager) UpdateVersion(
+	ctx context.Context,
+	cfg *Config,
+	resourceVersion string,
+) error {
+	if err := m.transforms.Apply(cfg); err != nil {
+		return err
+	}
+	if err := m.settings.Check(cfg); err != nil {
+		return err
+	}
+	if len(m.protected) > 0 {
+		current, err := m.GetConfig(ctx)
+		if err != nil {
+			return err
+		}
+		if err = m.protected.Check(current, cfg); err != nil {
+			return err
+		}
+	}
+	return m.write(ctx, cfg, resourceVersion)
+}
+
+// write updates the ConfigMap, and the sensitive fields Secret, with informed
+// configuration. When informed, the resource version must match the stored. The
+// ConfigMap is annotated with the mutation sequence number following the stored,
+// and the configuration stamped with the resulting resource version and
+// sequence number.
+func (m *ConfigMapManager) write(
+	ctx context.Context,
+	cfg *Config,
+	resourceVersion string,
+) error {
+	stored, values, err := m.splitSensitive(ctx, cfg)
 	if err != nil {
 		return err
 	}
-	_, err = coreClient.
-		ConfigMaps(cfg.Namespace()).
-		Update(ctx, cm, metav1.UpdateOptions{})
-	return err
+	cm, err := m.configMapForConfig(stored)
+	if err != nil {
+		return err
+	}
+	// A concurrent mutation after reading the stored sequence number fails the
+	// update, guarded by the resource version.
+	current, err := m.GetConfigMap(ctx)
+	if err != nil {
+		return err
+	}
+	m.annotate(cm, ConfigMapSequence(current)+1)
+	cm.SetResourceVersion(resourceVersion)
+	if err = m.store.update(ctx, cm); err != nil {
+		if apierrors.IsConflict(err) {
+			return fmt.Errorf("%w: %w", ErrConfigConflict, err)
+		}
+		return err
+	}
+	cfg.stamp(cm)
+	if len(m.sensitive) == 0 {
+		return nil
+	}
+	return m.writeSecret(ctx, cfg.Namespace(), values)
 }
 
-// Delete find and delete the ConfigMap from the cluster.
+// Delete find and delete the ConfigMap from the cluster, and the sensitive
+// fields Secret.
 func (m *ConfigMapManager) Delete(ctx context.Context) error {
 	cm, err := m.GetConfigMap(ctx)
 	if err != nil {
 		return err
 	}
 
-	coreClient, err := m.kube.CoreV1ClientSet(cm.GetNamespace())
-	if err != nil {
+	err = m.store.delete(ctx, cm.GetNamespace(), cm.GetName())
+	if err != nil || len(m.sensitive) == 0 {
 		return err
 	}
-
-	return coreClient.ConfigMaps(cm.GetNamespace()).
-		Delete(ctx, cm.GetName(), metav1.DeleteOptions{})
+	return m.writeSecret(ctx, cm.GetNamespace(), nil)
 }
 
 // NewConfigMapManager instantiates the ConfigMapManager.
@@ -176,8 +400,11 @@ func (m *ConfigMapManager) Delete(ctx context.Context) error {
 // decoding.
 func NewConfigMapManager(kube k8s.Interface, appName string) *ConfigMapManager {
 	return &ConfigMapManager{
-		kube:    kube,
-		name:    fmt.Sprintf("%s-config", appName),
-		appName: strings.ReplaceAll(appName, "-", "_"),
+		kube:      kube,
+		store:     newStore(kube, BackendConfigMap),
+		name:      fmt.Sprintf("%s-config", appName),
+		appName:   strings.ReplaceAll(appName, "-", "_"),
+		threshold: DefaultCompressionThreshold,
+		actor:     ActorCLI,
 	}
 }
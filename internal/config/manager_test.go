@@ -0,0 +1,464 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/constants"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestConfigMapManager(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := NewConfigFromFile(
+		cfs, "config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+
+	// getConfig reads the configuration back from a cluster containing the
+	// informed ConfigMap.
+	getConfig := func(cm *corev1.ConfigMap) (*Config, error) {
+		return NewConfigMapManager(k8s.NewFakeKube(cm), "helmet-ex").
+			GetConfig(ctx)
+	}
+
+	t.Run("Plain", func(t *testing.T) {
+		m := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex")
+		cm, err := m.configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cm.Data[FormatKey]).To(o.Equal(FormatPlain))
+		g.Expect(cm.Data[constants.ConfigFilename]).To(o.Equal(cfg.String()))
+		g.Expect(cm.BinaryData).To(o.BeEmpty())
+
+		stored, err := getConfig(cm)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(stored.String()).To(o.Equal(cfg.String()))
+	})
+
+	t.Run("Compressed", func(t *testing.T) {
+		m := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex")
+		m.SetCompressionThreshold(0)
+		cm, err := m.configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cm.Data).To(o.Equal(map[string]string{FormatKey: FormatGzip}))
+		g.Expect(cm.BinaryData).To(o.HaveKey(constants.ConfigFilename))
+		g.Expect(len(cm.BinaryData[constants.ConfigFilename])).
+			To(o.BeNumerically("<", len(cfg.String())))
+
+		stored, err := getConfig(cm)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(stored.String()).To(o.Equal(cfg.String()))
+
+		// The compressed payload is deterministic, unchanged configuration
+		// produces the same ConfigMap.
+		again, err := m.configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(again.BinaryData).To(o.Equal(cm.BinaryData))
+	})
+
+	t.Run("Legacy", func(t *testing.T) {
+		// ConfigMaps created before the format marker are plain text.
+		stored, err := getConfig(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "helmet-ex-config",
+				Namespace: "test-namespace",
+				Labels:    map[string]string{annotations.Config: "true"},
+			},
+			Data: map[string]string{constants.ConfigFilename: cfg.String()},
+		})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(stored.String()).To(o.Equal(cfg.String()))
+	})
+
+	t.Run("Incomplete", func(t *testing.T) {
+		_, err := ConfigMapPayload(&corev1.ConfigMap{
+			Data: map[string]string{FormatKey: FormatGzip},
+		})
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("binaryData")))
+
+		_, err = ConfigMapPayload(&corev1.ConfigMap{
+			Data: map[string]string{FormatKey: "zstd"},
+		})
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("unknown format")))
+	})
+
+	t.Run("ProtectedFields", func(t *testing.T) {
+		m := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex")
+		cm, err := m.configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+
+		m = NewConfigMapManager(k8s.NewFakeKube(cm), "helmet-ex")
+		m.SetProtectedFields(ProtectedFields{
+			"settings.crc",
+			"products.Product A.enabled",
+			"products[name=Product B].properties",
+		})
+
+		// Changing fields not protected is allowed.
+		updated, err := m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(updated.Set("helmet_ex.settings.ci.debug", true)).
+			To(o.Succeed())
+		g.Expect(m.Update(ctx, updated)).To(o.Succeed())
+
+		updated, err = m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(updated.Set("helmet_ex.settings.crc", true)).To(o.Succeed())
+		err = m.Update(ctx, updated)
+		g.Expect(err).To(o.MatchError(ErrProtectedField))
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("settings.crc")))
+
+		updated, err = m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(updated.SetProduct("Product A", Product{Enabled: false})).
+			To(o.Succeed())
+		g.Expect(m.Update(ctx, updated)).To(o.MatchError(ErrProtectedField))
+
+		// Selected by name, as the configuration paths.
+		updated, err = m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(updated.SetPath(
+			"products[name=Product B].properties.replicas", 3,
+		)).To(o.Succeed())
+		g.Expect(m.Update(ctx, updated)).To(o.MatchError(ErrProtectedField))
+
+		// Product names with dots are only expressed selected by name.
+		f, err := parseField("products[name=Developer Hub 1.2].enabled")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(f).To(o.Equal(&configField{
+			product: "Developer Hub 1.2",
+			keys:    []string{"enabled"},
+		}))
+		g.Expect(f.path()).
+			To(o.Equal("products[name=Developer Hub 1.2].enabled"))
+		f, err = parseField("products.Product A")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(f).To(o.Equal(&configField{product: "Product A"}))
+
+		for _, invalid := range []string{
+			"crc",
+			"products.",
+			"products",
+			"settings",
+			"settings[0].crc",
+			"products[0].enabled",
+			"products[name=].enabled",
+			"products.Product A.properties[0]",
+			"other.crc",
+		} {
+			g.Expect(ProtectedFields{invalid}.Validate()).
+				ToNot(o.Succeed(), invalid)
+		}
+	})
+
+	t.Run("SensitiveFields", func(t *testing.T) {
+		field := "products.Product B.properties.smtpPassword"
+		sensitive := SensitiveFields{field, "settings.smtp.password"}
+		g.Expect(sensitive.Validate()).To(o.Succeed())
+		g.Expect(SensitiveFields{"products.Product B"}.Validate()).
+			ToNot(o.Succeed())
+
+		plain, err := cfg.DeepCopy()
+		g.Expect(err).To(o.Succeed())
+		path, err := fieldPath(field)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(plain.SetPath(path, "s3cr3t")).To(o.Succeed())
+
+		// The ConfigMap payload carries the placeholder, the value is kept
+		// apart, absent fields are skipped.
+		redacted, values, err := sensitive.Redact(plain)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(values).To(o.Equal(map[string]any{field: "s3cr3t"}))
+		g.Expect(redacted.String()).ToNot(o.ContainSubstring("s3cr3t"))
+		g.Expect(redacted.String()).To(o.ContainSubstring(SensitivePlaceholder))
+		g.Expect(plain.String()).To(o.ContainSubstring("s3cr3t"))
+
+		cm, err := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex").
+			configMapForConfig(redacted)
+		g.Expect(err).To(o.Succeed())
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "helmet-ex-config",
+				Namespace: "test-namespace",
+			},
+			Data: map[string][]byte{
+				SensitiveKey: []byte(field + ": s3cr3t\n"),
+			},
+		}
+
+		// Loading the configuration resolves the values from the Secret.
+		m := NewConfigMapManager(k8s.NewFakeKube(cm, secret), "helmet-ex")
+		m.SetSensitiveFields(sensitive)
+		resolved, err := m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		product, err := resolved.GetProduct("Product B")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Properties).
+			To(o.HaveKeyWithValue("smtpPassword", "s3cr3t"))
+
+		display, err := m.Redact(resolved)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(display.String()).ToNot(o.ContainSubstring("s3cr3t"))
+
+		// Updates carrying the placeholder keep the stored value.
+		_, values, err = m.splitSensitive(ctx, redacted)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(values).To(o.Equal(map[string]any{field: "s3cr3t"}))
+		m = NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex")
+		m.SetSensitiveFields(sensitive)
+		// Without a stored value the placeholder can't be persisted.
+		_, _, err = m.splitSensitive(ctx, redacted)
+		g.Expect(err).To(o.MatchError(ErrSensitiveNotStored))
+		g.Expect(m.Create(ctx, redacted)).To(o.MatchError(ErrSensitiveNotStored))
+	})
+
+	t.Run("Migrations", func(t *testing.T) {
+		cm, err := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex").
+			configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+
+		m := NewConfigMapManager(k8s.NewFakeKube(cm), "helmet-ex")
+		m.SetMigrations(Migrations{1: func(root *yaml.Node) error {
+			return UpdateNestedValue(
+				root, []string{"settings", "ci", "debug"}, true)
+		}})
+
+		versioned := &versionedStore{store: m.store}
+		m.store = versioned
+
+		// Stored configuration without version is migrated on load, in
+		// memory, and persisted by the next update.
+		migrated, err := m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(migrated.Version()).To(o.Equal(2))
+		g.Expect(migrated.Installer.Settings["ci"]).
+			To(o.HaveKeyWithValue("debug", true))
+		g.Expect(versioned.updated).To(o.BeNil())
+
+		// Loading for a change, as "deploy" does, persists the upgraded
+		// document right away.
+		migrated, err = m.GetConfigMigrated(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(migrated.Version()).To(o.Equal(2))
+		g.Expect(versioned.updated).NotTo(o.BeNil())
+		payload, err := ConfigMapPayload(versioned.updated)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(payload)).To(o.ContainSubstring("version: 2"))
+		versioned.updated = nil
+
+		g.Expect(m.Update(ctx, migrated)).To(o.Succeed())
+		g.Expect(versioned.updated).NotTo(o.BeNil())
+		payload, err = ConfigMapPayload(versioned.updated)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(payload)).To(o.ContainSubstring("version: 2"))
+
+		// New configuration is stamped with the latest version.
+		fresh, err := NewConfigFromBytes(
+			[]byte(cfg.String()), "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		m = NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex")
+		m.SetMigrations(Migrations{1: func(*yaml.Node) error { return nil }})
+		g.Expect(m.Create(ctx, fresh)).To(o.Succeed())
+		g.Expect(fresh.Version()).To(o.Equal(2))
+
+		// Without migrations the configuration isn't versioned.
+		fresh, err = NewConfigFromBytes(
+			[]byte(cfg.String()), "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		m = NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex")
+		g.Expect(m.Create(ctx, fresh)).To(o.Succeed())
+		g.Expect(fresh.Installer.Version).To(o.BeZero())
+	})
+
+	t.Run("UpdateVersion", func(t *testing.T) {
+		cm, err := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex").
+			configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+		cm.ResourceVersion = "42"
+
+		m := NewConfigMapManager(k8s.NewFakeKube(cm), "helmet-ex")
+		stored, version, err := m.GetConfigVersion(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(version).To(o.Equal("42"))
+
+		// The store rejects updates on a stale resource version, as the API
+		// server does.
+		versioned := &versionedStore{store: m.store, version: "43"}
+		m.store = versioned
+		err = m.UpdateVersion(ctx, stored, version)
+		g.Expect(err).To(o.MatchError(ErrConfigConflict))
+		g.Expect(versioned.updated).To(o.BeNil())
+
+		g.Expect(m.UpdateVersion(ctx, stored, "43")).To(o.Succeed())
+		g.Expect(versioned.updated.GetResourceVersion()).To(o.Equal("43"))
+		g.Expect(m.UpdateVersion(ctx, stored, "")).To(o.Succeed())
+		g.Expect(versioned.updated.GetResourceVersion()).To(o.BeEmpty())
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		cm, err := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex").
+			configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+		cm.ResourceVersion = "42"
+
+		m := NewConfigMapManager(k8s.NewFakeKube(cm), "helmet-ex")
+		stored, err := m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+
+		// The configuration read from the cluster is updated on the resource
+		// version it was read from.
+		versioned := &versionedStore{store: m.store, version: "43"}
+		m.store = versioned
+		g.Expect(m.Update(ctx, stored)).To(o.MatchError(ErrConfigConflict))
+		g.Expect(versioned.updated).To(o.BeNil())
+
+		// Configuration not read from the cluster is updated unconditionally.
+		local, err := cfg.DeepCopy()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(m.Update(ctx, local)).To(o.Succeed())
+		g.Expect(versioned.updated.GetResourceVersion()).To(o.BeEmpty())
+	})
+
+	t.Run("Mutate", func(t *testing.T) {
+		cm, err := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex").
+			configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+		m := NewConfigMapManager(k8s.NewFakeKube(cm), "helmet-ex")
+
+		// Conflicts are retried, applying the mutation on the configuration
+		// read again.
+		versioned := &versionedStore{store: m.store, conflicts: 2}
+		m.store = versioned
+		attempts := 0
+		updated, err := m.Mutate(ctx, func(c *Config) error {
+			attempts++
+			return c.SetPath("settings.crc", true)
+		})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(attempts).To(o.Equal(3))
+		g.Expect(updated.Installer.Settings).To(o.HaveKeyWithValue("crc", true))
+
+		// Mutation errors are not retried.
+		attempts = 0
+		_, err = m.Mutate(ctx, func(*Config) error {
+			attempts++
+			return ErrInvalidPath
+		})
+		g.Expect(err).To(o.MatchError(ErrInvalidPath))
+		g.Expect(attempts).To(o.Equal(1))
+
+		// Persistent conflicts are returned.
+		versioned.conflicts = 10
+		_, err = m.Mutate(ctx, func(*Config) error { return nil })
+		g.Expect(err).To(o.MatchError(ErrConfigConflict))
+	})
+
+	t.Run("Sequence", func(t *testing.T) {
+		local, err := cfg.DeepCopy()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex").
+			Create(ctx, local)).To(o.Succeed())
+		g.Expect(local.Sequence()).To(o.Equal(int64(1)))
+
+		cm, err := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex").
+			configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+		cm.SetAnnotations(map[string]string{
+			annotations.ConfigSequence:  "7",
+			annotations.ConfigMutatedBy: ActorCLI,
+		})
+		m := NewConfigMapManager(k8s.NewFakeKube(cm), "helmet-ex")
+		stored, err := m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(stored.Sequence()).To(o.Equal(int64(7)))
+		g.Expect(stored.MutatedBy()).To(o.Equal(ActorCLI))
+
+		// Writes follow the stored sequence number, including the unconditional
+		// ones, annotating the actor.
+		m.SetActor(ActorMCP)
+		versioned := &versionedStore{store: m.store}
+		m.store = versioned
+		g.Expect(m.Update(ctx, stored)).To(o.Succeed())
+		g.Expect(stored.Sequence()).To(o.Equal(int64(8)))
+		g.Expect(stored.MutatedBy()).To(o.Equal(ActorMCP))
+		g.Expect(ConfigMapSequence(versioned.updated)).To(o.Equal(int64(8)))
+		g.Expect(m.Update(ctx, local)).To(o.Succeed())
+		g.Expect(local.Sequence()).To(o.Equal(int64(8)))
+
+		// Configuration written before the sequence was tracked.
+		cm.SetAnnotations(map[string]string{annotations.ConfigSequence: "x"})
+		g.Expect(ConfigMapSequence(cm)).To(o.BeZero())
+	})
+
+	t.Run("BackupRestore", func(t *testing.T) {
+		cm, err := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex").
+			configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+		cm.ResourceVersion = "42"
+
+		backup, err := NewConfigMapManager(k8s.NewFakeKube(cm), "helmet-ex").
+			Backup(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(backup.ResourceVersion).To(o.Equal("42"))
+
+		path := filepath.Join(t.TempDir(), "backup.yaml")
+		g.Expect(backup.Save(path)).To(o.Succeed())
+		loaded, err := LoadBackup(path)
+		g.Expect(err).To(o.Succeed())
+		restored, err := loaded.Config("helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(restored.String()).To(o.Equal(cfg.String()))
+
+		// Restoring on a cluster without configuration creates it.
+		g.Expect(NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex").
+			Restore(ctx, loaded, false)).To(o.Succeed())
+
+		// The existing configuration is only replaced when informed.
+		m := NewConfigMapManager(k8s.NewFakeKube(cm), "helmet-ex")
+		g.Expect(m.Restore(ctx, loaded, false)).
+			To(o.MatchError(o.ContainSubstring("already exists")))
+		g.Expect(m.Restore(ctx, loaded, true)).To(o.Succeed())
+
+		_, err = (&Backup{}).Config("helmet_ex")
+		g.Expect(err).To(o.MatchError(ErrInvalidBackup))
+	})
+}
+
+// versionedStore rejects updates informing a resource version other than the
+// current one, and the first updates while simulating conflicts.
+type versionedStore struct {
+	store
+	version   string            // current resource version
+	conflicts int               // updates to reject as conflicting
+	updated   *corev1.ConfigMap // last updated ConfigMap
+}
+
+func (s *versionedStore) update(_ context.Context, cm *corev1.ConfigMap) error {
+	if s.conflicts > 0 {
+		s.conflicts--
+		return apierrors.NewConflict(
+			schema.GroupResource{Resource: "configmaps"}, cm.GetName(),
+			errors.New("the object has been modified"))
+	}
+	if v := cm.GetResourceVersion(); v != "" && s.version != "" && v != s.version {
+		return apierrors.NewConflict(
+			schema.GroupResource{Resource: "configmaps"}, cm.GetName(),
+			errors.New("the object has been modified"))
+	}
+	s.updated = cm
+	return nil
+}
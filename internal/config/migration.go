@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InitialVersion the configuration version assumed for payloads without the
+// "version" field, i.e. created before versioning was introduced.
+const InitialVersion = 1
+
+// ErrMigration the configuration could not be migrated to the latest version.
+var ErrMigration = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+	"configuration migration failed")
+
+// Migrations registry of configuration upgrade functions, indexed by the
+// version they migrate from, i.e. the function registered for version 1
+// upgrades a v1 payload to v2. Each function receives the application root node
+// ("settings", "products", etc.) and changes it in place.
+type Migrations map[int]func(root *yaml.Node) error
+
+// Validate asserts the migrations form a contiguous chain starting on the
+// InitialVersion.
+func (m Migrations) Validate() error {
+	for _, from := range m.versions() {
+		if from < InitialVersion {
+			return fmt.Errorf("invalid migration from version %d", from)
+		}
+		if m[from] == nil {
+			return fmt.Errorf("migration from version %d is nil", from)
+		}
+	}
+	for from := InitialVersion; from < m.Latest(); from++ {
+		if _, ok := m[from]; !ok {
+			return fmt.Errorf("missing migration from version %d", from)
+		}
+	}
+	return nil
+}
+
+// versions returns the registered versions sorted.
+func (m Migrations) versions() []int {
+	versions := make([]int, 0, len(m))
+	for from := range m {
+		versions = append(versions, from)
+	}
+	slices.Sort(versions)
+	return versions
+}
+
+// Latest returns the configuration version after all migrations are applied.
+func (m Migrations) Latest() int {
+	latest := InitialVersion
+	for from := range m {
+		if from+1 > latest {
+			latest = from + 1
+		}
+	}
+	return latest
+}
+
+// Migrate upgrades the configuration to the latest version, one version at a
+// time. It returns true when the configuration has been changed.
+func (m Migrations) Migrate(cfg *Config) (bool, error) {
+	version := cfg.Version()
+	latest := m.Latest()
+	if version >= latest {
+		return false, nil
+	}
+	root, err := cfg.appNode()
+	if err != nil {
+		return false, err
+	}
+	for ; version < latest; version++ {
+		fn, ok := m[version]
+		if !ok || fn == nil {
+			return false, fmt.Errorf(
+				"%w: missing migration from version %d", ErrMigration, version)
+		}
+		if err = fn(root); err != nil {
+			return false, fmt.Errorf("%w: from version %d: %w",
+				ErrMigration, version, err)
+		}
+	}
+	if err = cfg.SetVersion(latest); err != nil {
+		return false, err
+	}
+	cfg.ApplyDefaults()
+	if err = cfg.Validate(); err != nil {
+		return false, fmt.Errorf("%w: version %d: %w", ErrMigration, latest, err)
+	}
+	return true, nil
+}
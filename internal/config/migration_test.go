@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+
+	o "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrations(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	newConfig := func() *Config {
+		cfg, err := NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		return cfg
+	}
+
+	// renameCRC renames the "settings.crc" key to "settings.localCluster".
+	renameCRC := func(root *yaml.Node) error {
+		settings, err := FindNode(root, "settings")
+		if err != nil {
+			return err
+		}
+		for i := 0; i+1 < len(settings.Content); i += 2 {
+			if settings.Content[i].Value == "crc" {
+				settings.Content[i].Value = "localCluster"
+			}
+		}
+		return nil
+	}
+	// enableCI sets "settings.ci.debug" to true.
+	enableCI := func(root *yaml.Node) error {
+		return UpdateNestedValue(root, []string{"settings", "ci", "debug"}, true)
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		g.Expect(Migrations{}.Validate()).To(o.Succeed())
+		g.Expect(Migrations{}.Latest()).To(o.Equal(InitialVersion))
+		g.Expect(Migrations{1: renameCRC, 2: enableCI}.Validate()).
+			To(o.Succeed())
+		g.Expect(Migrations{2: enableCI}.Validate()).
+			To(o.MatchError(o.ContainSubstring("missing migration from version 1")))
+		g.Expect(Migrations{0: enableCI}.Validate()).To(o.HaveOccurred())
+		g.Expect(Migrations{1: nil}.Validate()).To(o.HaveOccurred())
+	})
+
+	t.Run("Migrate", func(t *testing.T) {
+		cfg := newConfig()
+		g.Expect(cfg.Version()).To(o.Equal(InitialVersion))
+
+		migrations := Migrations{1: renameCRC, 2: enableCI}
+		migrated, err := migrations.Migrate(cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(migrated).To(o.BeTrue())
+		g.Expect(cfg.Version()).To(o.Equal(3))
+		g.Expect(cfg.Installer.Settings).ToNot(o.HaveKey("crc"))
+		g.Expect(cfg.Installer.Settings).To(o.HaveKeyWithValue("localCluster", false))
+		g.Expect(cfg.Installer.Settings["ci"]).
+			To(o.HaveKeyWithValue("debug", true))
+		g.Expect(cfg.Installer.Products).ToNot(o.BeEmpty())
+
+		// The upgraded document carries the version and loads as current.
+		reloaded, err := NewConfigFromBytes(
+			[]byte(cfg.String()), "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(reloaded.Version()).To(o.Equal(3))
+
+		migrated, err = migrations.Migrate(reloaded)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(migrated).To(o.BeFalse())
+	})
+
+	t.Run("Partial", func(t *testing.T) {
+		cfg := newConfig()
+		g.Expect(cfg.SetVersion(2)).To(o.Succeed())
+
+		// Only the migrations newer than the stored version are applied.
+		migrated, err := Migrations{1: renameCRC, 2: enableCI}.Migrate(cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(migrated).To(o.BeTrue())
+		g.Expect(cfg.Installer.Settings).To(o.HaveKey("crc"))
+		g.Expect(cfg.Installer.Settings["ci"]).
+			To(o.HaveKeyWithValue("debug", true))
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		cfg := newConfig()
+		_, err := Migrations{1: func(*yaml.Node) error {
+			return errors.New("boom")
+		}}.Migrate(cfg)
+		g.Expect(err).To(o.MatchError(ErrMigration))
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("boom")))
+	})
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"errors"
+)
+
+// Patch merges the partial configuration document, read from the informed
+// source, on top of the configuration: only the keys present on the document
+// change. The document carries the application root key, like a configuration
+// file, and is merged as the user's configuration layer is, see WithDefaults:
+// mappings are merged recursively, a null value removes the key, and products
+// are merged by name. The configuration is validated afterwards, and left
+// unchanged when invalid. The options apply to the document, like
+// WithEnvExpansion.
+func (c *Config) Patch(source string, payload []byte, opts ...Option) error {
+	patch, err := c.parseLayer(source, payload, opts...)
+	if err != nil {
+		return err
+	}
+	original, err := c.MarshalYAML()
+	if err != nil {
+		return err
+	}
+	root, err := c.appNode()
+	if err != nil {
+		return err
+	}
+	merged := mergeNode(root, patch)
+	*root = *merged
+	if err = c.decode(); err != nil {
+		// Restoring the original configuration, known to be valid.
+		if restoreErr := c.UnmarshalYAML(original); restoreErr != nil {
+			return errors.Join(err, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestPatch(t *testing.T) {
+	cfs := chartfs.New(os.DirFS("../../test"))
+
+	t.Run("Merge", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := NewConfigFromFile(cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+
+		g.Expect(cfg.Patch("patch.yaml", []byte(`
+helmet_ex:
+  settings:
+    crc: true
+    ci: null
+  products:
+    - name: Product B
+      properties:
+        replicas: 3
+`))).To(o.Succeed())
+
+		g.Expect(cfg.Installer.Settings).To(o.Equal(Settings{"crc": true}))
+		b, err := cfg.GetProduct("Product B")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(b.Enabled).To(o.BeTrue())
+		g.Expect(b.Properties).To(o.Equal(map[string]any{
+			"storageClass": "standard",
+			"replicas":     3,
+		}))
+		// Only the keys present on the patch change.
+		g.Expect(cfg.Installer.Products).To(o.HaveLen(4))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := NewConfigFromFile(cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		original := cfg.String()
+
+		g.Expect(cfg.Patch("patch.yaml", nil)).To(o.MatchError(ErrEmptyConfig))
+		g.Expect(cfg.Patch("patch.yaml", []byte("other:\n  settings: {}\n"))).
+			To(o.MatchError(ErrUnmarshalConfig))
+		g.Expect(cfg.Patch("patch.yaml", []byte(`
+helmet_ex:
+  products:
+    - name: Product A
+      requires: [Product A]
+`))).NotTo(o.Succeed())
+		g.Expect(cfg.String()).To(o.Equal(original))
+	})
+	t.Run("EnvExpansion", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := NewConfigFromFile(cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+
+		patch := []byte(`
+helmet_ex:
+  products:
+    - name: Product B
+      properties:
+        storageClass: ${STORAGE_CLASS}
+`)
+		g.Expect(cfg.Patch("patch.yaml", patch,
+			WithEnvVars(map[string]string{"STORAGE_CLASS": "gp3"}))).
+			To(o.Succeed())
+		b, err := cfg.GetProduct("Product B")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(b.Properties).To(o.HaveKeyWithValue("storageClass", "gp3"))
+
+		// Without the option the reference is kept as is.
+		g.Expect(cfg.Patch("patch.yaml", patch)).To(o.Succeed())
+		b, err = cfg.GetProduct("Product B")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(b.Properties).To(
+			o.HaveKeyWithValue("storageClass", "${STORAGE_CLASS}"))
+	})
+}
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidPath the configuration path is malformed, or doesn't match the
+// configuration.
+var ErrInvalidPath = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+	"invalid configuration path")
+
+// pathSegment a configuration path element, a mapping key optionally followed
+// by a sequence item selector, either "<key>=<value>" or the item index.
+type pathSegment struct {
+	key      string // mapping key
+	selector string // sequence item selector
+	selected bool   // whether the selector is informed
+}
+
+// parsePath parses the dotted configuration path, relative to the application
+// root key, for instance "products[name=Product B].properties.replicas". The
+// selector value may contain dots.
+func parsePath(path string) ([]pathSegment, error) {
+	segments := []pathSegment{}
+	var seg pathSegment
+	var buf strings.Builder
+	inSelector, closed := false, false
+	flush := func() error {
+		if inSelector {
+			return fmt.Errorf("%w: %q: unterminated selector", ErrInvalidPath, path)
+		}
+		if !closed {
+			seg.key = buf.String()
+		}
+		if seg.key == "" {
+			return fmt.Errorf("%w: %q: empty key", ErrInvalidPath, path)
+		}
+		segments = append(segments, seg)
+		seg, closed = pathSegment{}, false
+		buf.Reset()
+		return nil
+	}
+	for _, r := range path {
+		switch {
+		case inSelector && r == ']':
+			seg.selector, seg.selected = buf.String(), true
+			if seg.selector == "" {
+				return nil, fmt.Errorf("%w: %q: empty selector", ErrInvalidPath, path)
+			}
+			buf.Reset()
+			inSelector, closed = false, true
+		case inSelector:
+			buf.WriteRune(r)
+		case r == '.':
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case closed:
+			return nil, fmt.Errorf("%w: %q: unexpected %q after selector",
+				ErrInvalidPath, path, r)
+		case r == '[':
+			seg.key = buf.String()
+			buf.Reset()
+			inSelector = true
+		case r == ']':
+			return nil, fmt.Errorf("%w: %q: unexpected ']'", ErrInvalidPath, path)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
+
+// SplitAssignment splits "<path>=<value>" on the first equal sign outside the
+// path selectors, the value may contain equal signs. Returns false when there's
+// no assignment.
+func SplitAssignment(s string) (string, string, bool) {
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '=':
+			if depth == 0 {
+				return s[:i], s[i+1:], true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// CoerceValue converts the informed text into a typed value, following YAML
+// rules: "3" is an integer, "true" a boolean, "null" is nil, removing the value
+// on SetPath, and "[a, b]" or "{k: v}" are lists and objects. Quote to keep a
+// string, "'3'".
+func CoerceValue(raw string) (any, error) {
+	if strings.TrimSpace(raw) == "" {
+		return raw, nil
+	}
+	var value any
+	if err := yaml.Unmarshal([]byte(raw), &value); err != nil {
+		return nil, fmt.Errorf("%w: invalid value %q: %w",
+			ErrInvalidConfig, raw, err)
+	}
+	return value, nil
+}
+
+// selectItem returns the index of the sequence item matching the selector.
+func selectItem(seq *yaml.Node, selector string) (int, error) {
+	key, value, byKey := strings.Cut(selector, "=")
+	if !byKey {
+		i, err := strconv.Atoi(selector)
+		if err != nil || i < 0 || i >= len(seq.Content) {
+			return -1, fmt.Errorf("%w: index %q out of range", ErrInvalidPath, selector)
+		}
+		return i, nil
+	}
+	for i, item := range seq.Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			if item.Content[j].Value == key && item.Content[j+1].Value == value {
+				return i, nil
+			}
+		}
+	}
+	return -1, fmt.Errorf("%w: no item matches [%s]", ErrInvalidPath, selector)
+}
+
+// SetPath sets the value on the configuration path, relative to the application
+// root key, see parsePath. Missing mapping keys are created, and a nil value
+// removes the key, or the selected list item. The configuration is validated
+// afterwards, and left unchanged when invalid.
+func (c *Config) SetPath(path string, value any) error {
+	original, err := c.MarshalYAML()
+	if err != nil {
+		return err
+	}
+	if err = c.setNode(path, value); err != nil {
+		return err
+	}
+	if err = c.DecodeNode(); err == nil {
+		c.ApplyDefaults()
+		err = c.Validate()
+	}
+	if err != nil {
+		// Restoring the original configuration, known to be valid.
+		if restoreErr := c.UnmarshalYAML(original); restoreErr != nil {
+			return errors.Join(err, restoreErr)
+		}
+		return fmt.Errorf("%w: %q: %w", ErrInvalidConfig, path, err)
+	}
+	return nil
+}
+
+// setNode sets the value on the configuration path of the YAML node tree, the
+// decoded configuration is left as is.
+func (c *Config) setNode(path string, value any) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	node, err := c.appNode()
+	if err != nil {
+		return err
+	}
+	valueNode := &yaml.Node{}
+	if err = valueNode.Encode(value); err != nil {
+		return err
+	}
+
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%w: %q: %q is not an object",
+				ErrInvalidPath, path, seg.key)
+		}
+		idx := -1
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == seg.key {
+				idx = j + 1
+				break
+			}
+		}
+		if idx < 0 && value == nil {
+			// Nothing to remove.
+			return nil
+		}
+		if idx < 0 {
+			if seg.selected {
+				return fmt.Errorf("%w: %q: %q not found",
+					ErrInvalidPath, path, seg.key)
+			}
+			child := valueNode
+			if !last {
+				child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: seg.key},
+				child)
+			node = child
+			continue
+		}
+		slot := &node.Content[idx]
+		if seg.selected {
+			if (*slot).Kind != yaml.SequenceNode {
+				return fmt.Errorf("%w: %q: %q is not a list",
+					ErrInvalidPath, path, seg.key)
+			}
+			item, err := selectItem(*slot, seg.selector)
+			if err != nil {
+				return fmt.Errorf("%q: %w", path, err)
+			}
+			if last && value == nil {
+				(*slot).Content = slices.Delete((*slot).Content, item, item+1)
+				return nil
+			}
+			slot = &(*slot).Content[item]
+		}
+		if last && value == nil {
+			node.Content = slices.Delete(node.Content, idx-1, idx+1)
+			return nil
+		}
+		if last {
+			*slot = valueNode
+		}
+		node = *slot
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestParsePath(t *testing.T) {
+	g := o.NewWithT(t)
+
+	segments, err := parsePath("products[name=Product B].properties.replicas")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(segments).To(o.Equal([]pathSegment{
+		{key: "products", selector: "name=Product B", selected: true},
+		{key: "properties"},
+		{key: "replicas"},
+	}))
+
+	segments, err = parsePath("products[namespace=a.b].enabled")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(segments[0].selector).To(o.Equal("namespace=a.b"))
+
+	for _, invalid := range []string{
+		"", "settings.", ".settings", "products[", "products[]",
+		"products[0]x", "settings]",
+	} {
+		_, err = parsePath(invalid)
+		g.Expect(err).To(o.MatchError(ErrInvalidPath), invalid)
+	}
+}
+
+func TestSplitAssignment(t *testing.T) {
+	g := o.NewWithT(t)
+
+	path, value, ok := SplitAssignment(
+		"products[name=Product B].properties.replicas=3")
+	g.Expect(ok).To(o.BeTrue())
+	g.Expect(path).To(o.Equal("products[name=Product B].properties.replicas"))
+	g.Expect(value).To(o.Equal("3"))
+
+	_, _, ok = SplitAssignment("products[name=Product B].enabled")
+	g.Expect(ok).To(o.BeFalse())
+
+	// The value keeps its equal signs.
+	for assignment, want := range map[string][2]string{
+		"settings.proxy=http://p.example.com/?a=b": {
+			"settings.proxy", "http://p.example.com/?a=b"},
+		"settings.token=dGVzdA==":  {"settings.token", "dGVzdA=="},
+		"products[name=A].key=a=b": {"products[name=A].key", "a=b"},
+		"settings.empty=":          {"settings.empty", ""},
+	} {
+		path, value, ok = SplitAssignment(assignment)
+		g.Expect(ok).To(o.BeTrue(), assignment)
+		g.Expect([2]string{path, value}).To(o.Equal(want), assignment)
+	}
+}
+
+func TestCoerceValue(t *testing.T) {
+	g := o.NewWithT(t)
+
+	for raw, want := range map[string]any{
+		"3":        3,
+		"true":     true,
+		"standard": "standard",
+		"'3'":      "3",
+		"[a, b]":   []any{"a", "b"},
+		"{k: v}":   map[string]any{"k": "v"},
+		"":         "",
+	} {
+		value, err := CoerceValue(raw)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(value).To(o.Equal(want), raw)
+	}
+	value, err := CoerceValue("null")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(value).To(o.BeNil())
+	_, err = CoerceValue("[a")
+	g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+}
+
+func TestSetPath(t *testing.T) {
+	cfs := chartfs.New(os.DirFS("../../test"))
+	newConfig := func(t *testing.T) *Config {
+		g := o.NewWithT(t)
+		cfg, err := NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		return cfg
+	}
+
+	t.Run("ProductProperty", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg := newConfig(t)
+		g.Expect(cfg.SetPath(
+			"products[name=Product B].properties.replicas", 3,
+		)).To(o.Succeed())
+		product, err := cfg.GetProduct("Product B")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Properties).To(o.Equal(map[string]any{
+			"storageClass": "standard",
+			"replicas":     3,
+		}))
+	})
+
+	t.Run("MissingKeys", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg := newConfig(t)
+		g.Expect(cfg.SetPath(
+			"products[name=Product A].properties.database.size", "10Gi",
+		)).To(o.Succeed())
+		product, err := cfg.GetProduct("Product A")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Properties).To(o.Equal(map[string]any{
+			"database": map[string]any{"size": "10Gi"},
+		}))
+	})
+
+	t.Run("SettingAndIndex", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg := newConfig(t)
+		g.Expect(cfg.SetPath("settings.ci.debug", true)).To(o.Succeed())
+		g.Expect(cfg.Installer.Settings["ci"]).To(
+			o.HaveKeyWithValue("debug", true))
+		g.Expect(cfg.SetPath("products[0].enabled", false)).To(o.Succeed())
+		g.Expect(cfg.Installer.Products[0].Enabled).To(o.BeFalse())
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg := newConfig(t)
+		g.Expect(cfg.SetPath(
+			"products[name=Product B].properties.storageClass", nil,
+		)).To(o.Succeed())
+		product, err := cfg.GetProduct("Product B")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(product.Properties).To(o.BeEmpty())
+		g.Expect(cfg.String()).ToNot(o.ContainSubstring("storageClass"))
+
+		// Absent keys are left alone, missing keys aren't created.
+		before := cfg.String()
+		g.Expect(cfg.SetPath("settings.missing.value", nil)).To(o.Succeed())
+		g.Expect(cfg.String()).To(o.Equal(before))
+
+		products := len(cfg.Installer.Products)
+		g.Expect(cfg.SetPath("products[name=Product B]", nil)).To(o.Succeed())
+		g.Expect(cfg.Installer.Products).To(o.HaveLen(products - 1))
+		_, err = cfg.GetProduct("Product B")
+		g.Expect(err).To(o.HaveOccurred())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg := newConfig(t)
+		before := cfg.String()
+
+		g.Expect(cfg.SetPath("products[name=Product Z].enabled", true)).
+			To(o.MatchError(ErrInvalidPath))
+		g.Expect(cfg.SetPath("settings.crc.value", true)).
+			To(o.MatchError(ErrInvalidPath))
+		// Wrong type, "enabled" is a boolean.
+		g.Expect(cfg.SetPath("products[name=Product A].enabled", "maybe")).
+			To(o.MatchError(ErrInvalidConfig))
+		// Enabled products must have a namespace.
+		g.Expect(cfg.SetPath("products[name=Product A].namespace", "")).
+			To(o.MatchError(ErrInvalidConfig))
+		g.Expect(cfg.String()).To(o.Equal(before))
+	})
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/redhat-appstudio/helmet/internal/constants"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// FormatKey ConfigMap data key marking how the configuration payload is
+	// stored, when absent the payload is plain text.
+	FormatKey = "format"
+	// FormatPlain the payload is stored as plain text in data.
+	FormatPlain = "plain"
+	// FormatGzip the payload is gzip compressed and stored in binaryData.
+	FormatGzip = "gzip"
+
+	// DefaultCompressionThreshold payload size, in bytes, above which the
+	// configuration is stored compressed.
+	DefaultCompressionThreshold = 256 * 1024
+)
+
+// compress gzip compresses the payload, the output is deterministic since the
+// gzip header carries no modification time.
+func compress(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(payload); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress reads the gzip compressed payload.
+func decompress(payload []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// ConfigMapPayload extracts the configuration payload from the ConfigMap,
+// honoring the format marker, compressed and plain storage are supported.
+func ConfigMapPayload(cm *corev1.ConfigMap) ([]byte, error) {
+	incompleteErr := func(location string) error {
+		return fmt.Errorf(
+			"%w: key %q not found in ConfigMap %s %s/%s",
+			ErrIncompleteConfigMap,
+			constants.ConfigFilename,
+			location,
+			cm.GetNamespace(),
+			cm.GetName(),
+		)
+	}
+
+	switch format := cm.Data[FormatKey]; format {
+	case "", FormatPlain:
+		payload, ok := cm.Data[constants.ConfigFilename]
+		if !ok || len(payload) == 0 {
+			return nil, incompleteErr("data")
+		}
+		return []byte(payload), nil
+	case FormatGzip:
+		payload, ok := cm.BinaryData[constants.ConfigFilename]
+		if !ok || len(payload) == 0 {
+			return nil, incompleteErr("binaryData")
+		}
+		decompressed, err := decompress(payload)
+		if err != nil {
+			return nil, fmt.Errorf("%w: decompressing ConfigMap %s/%s: %w",
+				ErrIncompleteConfigMap, cm.GetNamespace(), cm.GetName(), err)
+		}
+		return decompressed, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q in ConfigMap %s/%s",
+			ErrIncompleteConfigMap, format, cm.GetNamespace(), cm.GetName())
+	}
+}
+
+// setConfigMapPayload stores the configuration payload in the ConfigMap,
+// payloads larger than the threshold are compressed into binaryData.
+func setConfigMapPayload(
+	cm *corev1.ConfigMap,
+	payload []byte,
+	threshold int,
+) error {
+	if len(payload) <= threshold {
+		cm.Data = map[string]string{
+			FormatKey:                FormatPlain,
+			constants.ConfigFilename: string(payload),
+		}
+		cm.BinaryData = nil
+		return nil
+	}
+	compressed, err := compress(payload)
+	if err != nil {
+		return err
+	}
+	cm.Data = map[string]string{FormatKey: FormatGzip}
+	cm.BinaryData = map[string][]byte{constants.ConfigFilename: compressed}
+	return nil
+}
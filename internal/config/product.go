@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -17,6 +18,14 @@ type Product struct {
 	Namespace *string `yaml:"namespace,omitempty"`
 	// Properties contains the product specific configuration.
 	Properties map[string]interface{} `yaml:"properties"`
+	// Requires names the products that must be enabled with this product.
+	Requires []string `yaml:"requires,omitempty"`
+	// Conflicts names the products that can't be enabled with this product.
+	Conflicts []string `yaml:"conflicts,omitempty"`
+	// Sizing the product sizing profile, overriding the global "sizing"
+	// setting, see SizingProfiles. Omitted from the template variables when
+	// empty.
+	Sizing string `yaml:"sizing,omitempty" json:"Sizing,omitempty"`
 }
 
 // KeyName returns a sanitized key name for the product.
@@ -54,5 +63,52 @@ func (p *Product) Validate() error {
 		return fmt.Errorf("%w: product %q: missing namespace",
 			ErrInvalidConfig, p.Name)
 	}
+	if slices.Contains(p.Requires, p.Name) || slices.Contains(p.Conflicts, p.Name) {
+		return fmt.Errorf("%w: product %q: can't require or conflict with itself",
+			ErrInvalidConfig, p.Name)
+	}
+	if err := validateSizing(p.Sizing); err != nil {
+		return fmt.Errorf("%w: product %q: %w", ErrInvalidConfig, p.Name, err)
+	}
+	if err := validateValueFrom(p.Properties); err != nil {
+		return fmt.Errorf("product %q: properties: %w", p.Name, err)
+	}
+	return nil
+}
+
+// CheckConstraints asserts the products required by the named product are
+// enabled, and the conflicting products are not, when the named product is
+// enabled. Constraints are declared on the product configuration, and by the
+// product chart annotations, informed as requires and conflicts.
+func (c *Config) CheckConstraints(name string, requires, conflicts []string) error {
+	product, err := c.GetProduct(name)
+	if err != nil {
+		return err
+	}
+	if !product.Enabled {
+		return nil
+	}
+	for _, required := range requires {
+		p, err := c.GetProduct(required)
+		if err != nil {
+			return fmt.Errorf("%w: product %q requires unknown product %q",
+				ErrInvalidConfig, name, required)
+		}
+		if !p.Enabled {
+			return fmt.Errorf("%w: product %q requires product %q, which is disabled",
+				ErrInvalidConfig, name, required)
+		}
+	}
+	for _, conflict := range conflicts {
+		p, err := c.GetProduct(conflict)
+		if err != nil {
+			// Conflicting with a product not shipped is always satisfied.
+			continue
+		}
+		if p.Enabled {
+			return fmt.Errorf("%w: product %q conflicts with product %q, disable one of them",
+				ErrInvalidConfig, name, conflict)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrInvalidProperty the product property doesn't match the schema declared by
+// the product chart, or the schema itself is invalid.
+var ErrInvalidProperty = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+	"invalid product property")
+
+// PropertySchema describes a product property, declared by the product chart.
+type PropertySchema struct {
+	// Type the value type, the same types of the settings.
+	Type SettingType `yaml:"type" json:"type"`
+	// Required the property must be informed, or have a default.
+	Required bool `yaml:"required,omitempty" json:"required,omitempty"`
+	// Default the value assumed when the property is absent, optional.
+	Default any `yaml:"default,omitempty" json:"default,omitempty"`
+	// Allowed the values accepted, any value of the type when empty.
+	Allowed []any `yaml:"allowed,omitempty" json:"allowed,omitempty"`
+	// Description describes the property for the users and the AI assistant.
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// PropertiesSchema the schema of the product "properties" block, by property
+// name. Properties not declared are accepted as is.
+type PropertiesSchema map[string]PropertySchema
+
+// ParsePropertiesSchema parses the YAML schema payload, as declared on the chart
+// annotation, and asserts it's valid.
+func ParsePropertiesSchema(payload string) (PropertiesSchema, error) {
+	s := PropertiesSchema{}
+	if err := yaml.Unmarshal([]byte(payload), &s); err != nil {
+		return nil, fmt.Errorf("%w: schema: %w", ErrInvalidProperty, err)
+	}
+	return s, s.Validate()
+}
+
+// Validate asserts the schema is well formed: known types, and default and
+// allowed values matching the type.
+func (s PropertiesSchema) Validate() error {
+	for _, name := range slices.Sorted(maps.Keys(s)) {
+		property := s[name]
+		switch property.Type {
+		case SettingBool, SettingString, SettingInt, SettingNumber:
+		default:
+			return fmt.Errorf("%w: schema: %q: unknown type %q",
+				ErrInvalidProperty, name, property.Type)
+		}
+		for _, allowed := range property.Allowed {
+			if _, err := coerce(
+				ErrInvalidProperty, name, property.Type, nil, allowed); err != nil {
+				return fmt.Errorf("schema: %w", err)
+			}
+		}
+		if property.Default != nil {
+			if _, err := coerce(ErrInvalidProperty, name, property.Type,
+				property.Allowed, property.Default); err != nil {
+				return fmt.Errorf("schema: default: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Apply asserts the properties match the schema, and returns a copy of them
+// normalized, with the defaults of the absent properties filled in. Value
+// references, see ValueFromKey, are not checked. All properties are checked, the
+// errors are joined.
+func (s PropertiesSchema) Apply(properties map[string]any) (map[string]any, error) {
+	applied := maps.Clone(properties)
+	if applied == nil {
+		applied = map[string]any{}
+	}
+	errs := []error{}
+	for _, name := range slices.Sorted(maps.Keys(s)) {
+		property := s[name]
+		value, ok := applied[name]
+		if !ok || value == nil {
+			switch {
+			case property.Default != nil:
+				applied[name] = property.Default
+			case property.Required:
+				errs = append(errs, fmt.Errorf("%w: %q is required",
+					ErrInvalidProperty, name))
+			}
+			continue
+		}
+		// Value references are resolved on deployment, as strings.
+		if IsValueFrom(value) {
+			continue
+		}
+		coerced, err := coerce(
+			ErrInvalidProperty, name, property.Type, property.Allowed, value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		applied[name] = coerced
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"testing"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestPropertiesSchema(t *testing.T) {
+	schema, err := ParsePropertiesSchema(`
+storageClass:
+  type: string
+  required: true
+  allowed: [standard, fast]
+replicas:
+  type: int
+  default: 1
+debug:
+  type: bool
+`)
+	o.NewWithT(t).Expect(err).To(o.Succeed())
+
+	t.Run("Apply", func(t *testing.T) {
+		g := o.NewWithT(t)
+		properties := map[string]any{
+			"storageClass": "fast",
+			"replicas":     float64(3),
+			"catalogURL":   "https://example.com",
+		}
+		applied, err := schema.Apply(properties)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(applied).To(o.Equal(map[string]any{
+			"storageClass": "fast",
+			"replicas":     3,
+			"catalogURL":   "https://example.com",
+		}))
+		// The informed properties are not modified.
+		g.Expect(properties["replicas"]).To(o.Equal(float64(3)))
+
+		applied, err = schema.Apply(map[string]any{"storageClass": "standard"})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(applied).To(o.HaveKeyWithValue("replicas", 1))
+		g.Expect(applied).NotTo(o.HaveKey("debug"))
+
+		// Value references are resolved on deployment, not checked.
+		reference := map[string]any{ValueFromKey: map[string]any{
+			"configMapKeyRef": map[string]any{"name": "storage", "key": "class"},
+		}}
+		applied, err = schema.Apply(map[string]any{"storageClass": reference})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(applied).To(o.HaveKeyWithValue("storageClass", reference))
+	})
+
+	t.Run("Apply/invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := schema.Apply(nil)
+		g.Expect(err).To(o.MatchError(ErrInvalidProperty))
+		g.Expect(err).To(o.MatchError(helmeterrors.ErrInvalidConfig))
+		g.Expect(err).To(o.MatchError(o.ContainSubstring(
+			`"storageClass" is required`)))
+
+		_, err = schema.Apply(map[string]any{
+			"storageClass": "slow",
+			"replicas":     "three",
+			"debug":        "yes",
+		})
+		g.Expect(err).To(o.MatchError(o.And(
+			o.ContainSubstring(`"storageClass": slow is not one of [standard fast]`),
+			o.ContainSubstring(`"replicas": three is not an integer`),
+			o.ContainSubstring(`"debug": yes is not a boolean`),
+		)))
+	})
+
+	t.Run("Parse/invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		for _, payload := range []string{
+			"replicas: [1]",
+			"replicas: {type: list}",
+			"replicas: {type: int, default: one}",
+			"replicas: {type: int, allowed: [1, two]}",
+			"replicas: {type: int, default: 3, allowed: [1, 2]}",
+		} {
+			_, err := ParsePropertiesSchema(payload)
+			g.Expect(err).To(o.MatchError(ErrInvalidProperty), payload)
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrProtectedField the configuration change touches a field managed by the
+// application.
+var ErrProtectedField = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+	"protected configuration field")
+
+// ProtectedFields configuration fields managed by the application, thus locked
+// for changes. Each field is a dot separated path relative to the application
+// root key, either "settings.<key>[.<key>...]" or
+// "products.<name>[.<field>...]", for instance "settings.crc",
+// "products.Foundation.enabled" or "products.Foundation" for the whole product.
+// Product names containing dots are selected as on the configuration paths,
+// "products[name=<name>][.<field>...]", see parsePath.
+type ProtectedFields []string
+
+// configField a protected, or sensitive, configuration field.
+type configField struct {
+	product string   // product name, empty for settings
+	keys    []string // keys relative to the settings, or to the product
+}
+
+// path returns the configuration path of the field, see parsePath.
+func (f *configField) path() string {
+	root := "settings"
+	if f.product != "" {
+		root = fmt.Sprintf("products[name=%s]", f.product)
+	}
+	return strings.Join(append([]string{root}, f.keys...), ".")
+}
+
+// parseField parses the protected, or sensitive, field, see ProtectedFields.
+func parseField(field string) (*configField, error) {
+	invalid := fmt.Errorf("invalid protected field %q, expected %q, %q or %q",
+		field, "settings.<key>", "products.<name>[.<field>]",
+		"products[name=<name>][.<field>]")
+	segments, err := parsePath(field)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", invalid, err)
+	}
+	root, rest := segments[0], segments[1:]
+	f := &configField{}
+	switch {
+	case root.key == "settings" && !root.selected:
+	case root.key == "products" && root.selected:
+		key, name, _ := strings.Cut(root.selector, "=")
+		if key != "name" || name == "" {
+			return nil, invalid
+		}
+		f.product = name
+	case root.key == "products" && len(rest) > 0:
+		f.product, rest = rest[0].key, rest[1:]
+		if segments[1].selected {
+			return nil, invalid
+		}
+	default:
+		return nil, invalid
+	}
+	if f.product == "" && len(rest) == 0 {
+		return nil, invalid
+	}
+	for _, seg := range rest {
+		if seg.selected {
+			return nil, invalid
+		}
+		f.keys = append(f.keys, seg.key)
+	}
+	return f, nil
+}
+
+// Validate asserts the protected fields are well formed.
+func (p ProtectedFields) Validate() error {
+	for _, field := range p {
+		if _, err := parseField(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookup walks the nested maps following the keys.
+func lookup(data any, keys []string) any {
+	for _, key := range keys {
+		m, ok := data.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if data, ok = m[key]; !ok {
+			return nil
+		}
+	}
+	return data
+}
+
+// fieldValue returns the protected field value on the configuration, nil when
+// absent.
+func fieldValue(cfg *Config, field string) (any, error) {
+	f, err := parseField(field)
+	if err != nil {
+		return nil, err
+	}
+	var data any = map[string]any(cfg.Installer.Settings)
+	if f.product != "" {
+		product, err := cfg.GetProduct(f.product)
+		if err != nil {
+			return nil, nil
+		}
+		data = product
+	}
+
+	// Normalizing the data through YAML, so values are compared regardless of
+	// how the configuration was built.
+	payload, err := yaml.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]any
+	if err = yaml.Unmarshal(payload, &normalized); err != nil {
+		return nil, err
+	}
+	return lookup(normalized, f.keys), nil
+}
+
+// Check compares the current and the updated configuration, returning
+// ErrProtectedField when any protected field is changed.
+func (p ProtectedFields) Check(current, updated *Config) error {
+	for _, field := range p {
+		before, err := fieldValue(current, field)
+		if err != nil {
+			return err
+		}
+		after, err := fieldValue(updated, field)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(before, after) {
+			return fmt.Errorf(
+				"%w: %q is managed by the application and cannot be changed",
+				ErrProtectedField, field)
+		}
+	}
+	return nil
+}
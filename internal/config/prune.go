@@ -0,0 +1,150 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrOrphanedConfig the configuration carries products or settings unknown to
+// the installer.
+var ErrOrphanedConfig = helmeterrors.New(helmeterrors.ErrConfigDrift,
+	"orphaned configuration found")
+
+// Orphan kinds.
+const (
+	// OrphanProduct a product no chart declares.
+	OrphanProduct = "product"
+	// OrphanSetting a setting not registered by the application.
+	OrphanSetting = "setting"
+)
+
+// Orphan a configuration entry unknown to the installer. The path is relative to
+// the application root key, products are identified by name, as on Change.
+type Orphan struct {
+	Path   string `json:"path"`
+	Kind   string `json:"kind"`
+	Reason string `json:"reason"`
+}
+
+// settingKeys returns the dot separated keys of the settings leaf values.
+func settingKeys(prefix string, settings map[string]any) []string {
+	keys := []string{}
+	for k, v := range settings {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch m := v.(type) {
+		case Settings:
+			keys = append(keys, settingKeys(key, m)...)
+		case map[string]any:
+			keys = append(keys, settingKeys(key, m)...)
+		default:
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Orphans returns the configuration products not in the known products, the
+// product names declared by the charts, and the settings not registered. The
+// settings are only inspected when the application registers them.
+func (c *Config) Orphans(products []string, settings SettingRegistry) []Orphan {
+	orphans := []Orphan{}
+	for _, p := range c.Installer.Products {
+		if slices.Contains(products, p.Name) {
+			continue
+		}
+		orphans = append(orphans, Orphan{
+			Path:   "products." + p.Name,
+			Kind:   OrphanProduct,
+			Reason: "no chart declares the product",
+		})
+	}
+	if len(settings) > 0 {
+		for _, key := range settingKeys("", c.Installer.Settings) {
+			if _, ok := settings.Lookup(key); ok {
+				continue
+			}
+			orphans = append(orphans, Orphan{
+				Path:   "settings." + key,
+				Kind:   OrphanSetting,
+				Reason: "the setting is not registered",
+			})
+		}
+	}
+	slices.SortFunc(orphans, func(a, b Orphan) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return orphans
+}
+
+// deleteKey removes the nested key from the mapping node, and the mappings left
+// empty by it. Returns whether the node is left empty.
+func deleteKey(node *yaml.Node, keys []string) bool {
+	if node.Kind != yaml.MappingNode || len(keys) == 0 {
+		return false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != keys[0] {
+			continue
+		}
+		if len(keys) == 1 || deleteKey(node.Content[i+1], keys[1:]) {
+			node.Content = slices.Delete(node.Content, i, i+2)
+		}
+		break
+	}
+	return len(node.Content) == 0
+}
+
+// Prune removes the orphaned entries from the configuration, see Orphans. The
+// configuration is validated afterwards, and left unchanged when invalid.
+func (c *Config) Prune(orphans []Orphan) error {
+	original, err := c.MarshalYAML()
+	if err != nil {
+		return err
+	}
+	root, err := c.appNode()
+	if err != nil {
+		return err
+	}
+	productsNode, err := c.productsNode()
+	if err != nil {
+		return err
+	}
+	settingsNode, err := FindNode(root, "settings")
+	if err != nil {
+		return err
+	}
+	for _, orphan := range orphans {
+		switch orphan.Kind {
+		case OrphanProduct:
+			name := strings.TrimPrefix(orphan.Path, "products.")
+			productsNode.Content = slices.DeleteFunc(productsNode.Content,
+				func(n *yaml.Node) bool { return itemName(n) == name })
+		case OrphanSetting:
+			keys := strings.Split(strings.TrimPrefix(orphan.Path, "settings."), ".")
+			deleteKey(settingsNode, keys)
+		default:
+			err = fmt.Errorf("%w: %q: unknown orphan kind %q",
+				ErrInvalidConfig, orphan.Path, orphan.Kind)
+		}
+	}
+	if err == nil {
+		err = c.decode()
+	}
+	if err != nil {
+		// Restoring the original configuration, known to be valid.
+		if restoreErr := c.UnmarshalYAML(original); restoreErr != nil {
+			return errors.Join(err, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestPrune(t *testing.T) {
+	cfs := chartfs.New(os.DirFS("../../test"))
+	known := []string{"Product A", "Product B", "Product D"}
+	registry := SettingRegistry{{Key: "crc", Type: SettingBool}}
+
+	t.Run("Orphans", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := NewConfigFromFile(cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+
+		g.Expect(cfg.Orphans(known, registry)).To(o.Equal([]Orphan{{
+			Path:   "products.Product C",
+			Kind:   OrphanProduct,
+			Reason: "no chart declares the product",
+		}, {
+			Path:   "settings.ci.debug",
+			Kind:   OrphanSetting,
+			Reason: "the setting is not registered",
+		}}))
+
+		// Settings are only inspected when registered.
+		g.Expect(cfg.Orphans(known, nil)).To(o.HaveLen(1))
+		g.Expect(cfg.Orphans(append(known, "Product C"), nil)).To(o.BeEmpty())
+	})
+
+	t.Run("Prune", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := NewConfigFromFile(cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+
+		g.Expect(cfg.Prune(cfg.Orphans(known, registry))).To(o.Succeed())
+		g.Expect(cfg.Installer.Products).To(o.HaveLen(3))
+		_, err = cfg.GetProduct("Product C")
+		g.Expect(err).NotTo(o.Succeed())
+		// The mappings left empty are removed.
+		g.Expect(cfg.Installer.Settings).To(o.Equal(Settings{"crc": false}))
+		g.Expect(cfg.Orphans(known, registry)).To(o.BeEmpty())
+
+		// The pruned configuration is persisted from the YAML node tree.
+		payload, err := cfg.MarshalYAML()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(payload)).NotTo(o.ContainSubstring("Product C"))
+		g.Expect(string(payload)).NotTo(o.ContainSubstring("debug"))
+	})
+
+	t.Run("Prune/invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := NewConfigFromFile(cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+
+		err = cfg.Prune([]Orphan{
+			{Path: "products.Product C", Kind: OrphanProduct},
+			{Path: "integrations.quay", Kind: "integration"},
+		})
+		g.Expect(err).To(o.MatchError(ErrInvalidConfig))
+		// The configuration is left unchanged.
+		g.Expect(cfg.Installer.Products).To(o.HaveLen(4))
+	})
+}
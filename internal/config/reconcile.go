@@ -0,0 +1,55 @@
+package config
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Drift compares the expected configuration with the one stored in the cluster.
+// Sensitive fields are compared redacted, and the expected configuration without
+// version assumes the cluster's.
+func (m *ConfigMapManager) Drift(
+	ctx context.Context,
+	expected *Config,
+) (*Drift, error) {
+	cluster, err := m.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if expected.Installer.Version == 0 && cluster.Installer.Version != 0 {
+		if err = expected.SetVersion(cluster.Installer.Version); err != nil {
+			return nil, err
+		}
+	}
+	if expected, err = m.Redact(expected); err != nil {
+		return nil, err
+	}
+	if cluster, err = m.Redact(cluster); err != nil {
+		return nil, err
+	}
+	return Diff(expected, cluster)
+}
+
+// Reconcile compares the expected configuration with the one stored in the
+// cluster and, when they differ, updates the cluster with the expected
+// configuration. The drift found, before the update, is returned.
+func (m *ConfigMapManager) Reconcile(
+	ctx context.Context,
+	expected *Config,
+) (*Drift, error) {
+	drift, err := m.Drift(ctx, expected)
+	if err != nil {
+		return nil, err
+	}
+	if len(drift.Changes) == 0 {
+		return drift, nil
+	}
+	return drift, m.Update(ctx, expected)
+}
+
+// Watch watches the configuration resource, on every namespace, using the label
+// selector.
+func (m *ConfigMapManager) Watch(ctx context.Context) (watch.Interface, error) {
+	return m.store.watch(ctx)
+}
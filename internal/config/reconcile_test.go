@@ -0,0 +1,66 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestReconcile(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	newConfig := func() *Config {
+		cfg, err := NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		return cfg
+	}
+
+	// The cluster configuration is versioned, and edited out-of-band.
+	edited := newConfig()
+	g.Expect(edited.SetVersion(2)).To(o.Succeed())
+	g.Expect(edited.SetPath("settings.crc", true)).To(o.Succeed())
+	cm, err := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex").
+		configMapForConfig(edited)
+	g.Expect(err).To(o.Succeed())
+	m := NewConfigMapManager(k8s.NewFakeKube(cm), "helmet-ex")
+
+	t.Run("Drift", func(t *testing.T) {
+		g := o.NewWithT(t)
+		drift, err := m.Drift(ctx, newConfig())
+		g.Expect(err).To(o.Succeed())
+		// The expected configuration without version assumes the cluster's.
+		g.Expect(drift.Changes).To(o.Equal([]Change{{
+			Path:    "settings.crc",
+			Local:   false,
+			Cluster: true,
+		}}))
+	})
+
+	t.Run("Reconcile", func(t *testing.T) {
+		g := o.NewWithT(t)
+		drift, err := m.Reconcile(ctx, newConfig())
+		g.Expect(err).To(o.Succeed())
+		g.Expect(drift.Err()).To(o.MatchError(ErrConfigDrift))
+
+		drift, err = m.Reconcile(ctx, edited)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(drift.Changes).To(o.BeEmpty())
+	})
+
+	t.Run("SensitiveFields", func(t *testing.T) {
+		g := o.NewWithT(t)
+		m := NewConfigMapManager(k8s.NewFakeKube(cm), "helmet-ex")
+		m.SetSensitiveFields(SensitiveFields{"settings.crc"})
+		drift, err := m.Drift(ctx, newConfig())
+		g.Expect(err).To(o.Succeed())
+		g.Expect(drift.Changes).To(o.BeEmpty())
+	})
+}
@@ -0,0 +1,303 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// SchemaDialect the JSON Schema dialect of the configuration schema.
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONType returns the JSON Schema type of the setting type.
+func (t SettingType) JSONType() string {
+	switch t {
+	case SettingBool:
+		return "boolean"
+	case SettingInt:
+		return "integer"
+	case SettingNumber:
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+// valueSchema returns the JSON Schema of a value of the type, the allowed
+// values and default, when informed.
+func valueSchema(
+	t SettingType,
+	allowed []any,
+	value any,
+	description string,
+) map[string]any {
+	schema := map[string]any{"type": t.JSONType()}
+	if description != "" {
+		schema["description"] = description
+	}
+	if len(allowed) > 0 {
+		schema["enum"] = allowed
+	}
+	if value != nil {
+		schema["default"] = value
+	}
+	return schema
+}
+
+// JSONSchema returns the JSON Schema of the setting value.
+func (s Setting) JSONSchema() map[string]any {
+	return valueSchema(s.Type, s.Allowed, s.Default, s.Description)
+}
+
+// valueFromSchema the JSON Schema of a value reference, see ValueFromKey.
+func valueFromSchema() map[string]any {
+	keyRef := map[string]any{
+		"type":     "object",
+		"required": []string{"name", "key"},
+		"properties": map[string]any{
+			"name":      map[string]any{"type": "string"},
+			"key":       map[string]any{"type": "string"},
+			"namespace": map[string]any{"type": "string"},
+		},
+	}
+	return map[string]any{
+		"type":     "object",
+		"required": []string{ValueFromKey},
+		"properties": map[string]any{
+			ValueFromKey: map[string]any{
+				"type":          "object",
+				"minProperties": 1,
+				"maxProperties": 1,
+				"properties": map[string]any{
+					"secretKeyRef":    keyRef,
+					"configMapKeyRef": keyRef,
+				},
+			},
+		},
+	}
+}
+
+// JSONSchema returns the JSON Schema of the product "properties" block. The
+// declared properties accept value references as well, and the properties not
+// declared are accepted as is.
+func (s PropertiesSchema) JSONSchema() map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	for name, property := range s {
+		properties[name] = map[string]any{
+			"anyOf": []any{
+				valueSchema(property.Type, property.Allowed,
+					property.Default, property.Description),
+				valueFromSchema(),
+			},
+		}
+		if property.Required && property.Default == nil {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		slices.Sort(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// settingsSchema returns the JSON Schema of the "settings" block, describing
+// the settings registered, nested on the dot separated keys. Settings not
+// registered are accepted as is.
+func settingsSchema(settings SettingRegistry) map[string]any {
+	root := map[string]any{"type": "object", "properties": map[string]any{}}
+	if _, ok := settings.Lookup(SizingSetting); !ok {
+		settings = append(slices.Clone(settings), Setting{
+			Key:         SizingSetting,
+			Type:        SettingString,
+			Allowed:     sizingProfiles(),
+			Description: "Sizing profile of every dependency.",
+		})
+	}
+	for _, s := range settings {
+		parent := root
+		keys := strings.Split(s.Key, ".")
+		for _, key := range keys[:len(keys)-1] {
+			properties := parent["properties"].(map[string]any)
+			child, ok := properties[key].(map[string]any)
+			if !ok {
+				child = map[string]any{
+					"type":       "object",
+					"properties": map[string]any{},
+				}
+				properties[key] = child
+			}
+			parent = child
+		}
+		parent["properties"].(map[string]any)[keys[len(keys)-1]] = s.JSONSchema()
+	}
+	return root
+}
+
+// sizingProfiles returns the sizing profiles as JSON Schema enum.
+func sizingProfiles() []any {
+	profiles := make([]any, 0, len(SizingProfiles))
+	for _, p := range SizingProfiles {
+		profiles = append(profiles, p)
+	}
+	return profiles
+}
+
+// productNamesSchema returns the JSON Schema of a product name, one of the
+// informed, any string without products.
+func productNamesSchema(names []string) map[string]any {
+	schema := map[string]any{"type": "string"}
+	if len(names) > 0 {
+		schema["enum"] = names
+	}
+	return schema
+}
+
+// productsSchema returns the JSON Schema of the "products" list, the product
+// properties described by the product's schema, when declared.
+func productsSchema(
+	names []string,
+	products map[string]PropertiesSchema,
+) map[string]any {
+	namesList := map[string]any{
+		"type":  "array",
+		"items": productNamesSchema(names),
+	}
+	item := map[string]any{
+		"type":     "object",
+		"required": []string{"name", "enabled"},
+		"properties": map[string]any{
+			"name":    productNamesSchema(names),
+			"enabled": map[string]any{"type": "boolean"},
+			"namespace": map[string]any{
+				"type":        "string",
+				"description": "Target namespace, the installer's by default.",
+			},
+			"properties": map[string]any{"type": "object"},
+			"requires":   namesList,
+			"conflicts":  namesList,
+			"sizing": map[string]any{
+				"type":        "string",
+				"enum":        sizingProfiles(),
+				"description": "Sizing profile, overrides the sizing setting.",
+			},
+		},
+	}
+	conditions := []any{}
+	for _, name := range names {
+		if products[name] == nil {
+			continue
+		}
+		conditions = append(conditions, map[string]any{
+			"if": map[string]any{
+				"required": []string{"name"},
+				"properties": map[string]any{
+					"name": map[string]any{"const": name},
+				},
+			},
+			"then": map[string]any{
+				"properties": map[string]any{
+					"properties": products[name].JSONSchema(),
+				},
+			},
+		})
+	}
+	if len(conditions) > 0 {
+		item["allOf"] = conditions
+	}
+	return map[string]any{"type": "array", "items": item}
+}
+
+// webhooksSchema returns the JSON Schema of the "webhooks" list.
+func webhooksSchema() map[string]any {
+	events := make([]any, 0, len(WebhookEvents))
+	for _, e := range WebhookEvents {
+		events = append(events, e)
+	}
+	return map[string]any{
+		"type": "array",
+		"items": map[string]any{
+			"type":     "object",
+			"required": []string{"name", "url"},
+			"properties": map[string]any{
+				"name": map[string]any{"type": "string"},
+				"url":  map[string]any{"type": "string", "format": "uri"},
+				"events": map[string]any{
+					"type":  "array",
+					"items": map[string]any{"type": "string", "enum": events},
+				},
+				"secretRef": map[string]any{
+					"type":     "object",
+					"required": []string{"name"},
+					"properties": map[string]any{
+						"name": map[string]any{"type": "string"},
+						"key": map[string]any{
+							"type":    "string",
+							"default": DefaultWebhookSecretKey,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// JSONSchema returns the JSON Schema of the application configuration file,
+// under the appName root key, describing the registered settings, and the
+// products with the properties schema declared by their charts, by product name.
+// Editors use it to validate and complete the configuration files maintained
+// outside of the cluster.
+func JSONSchema(
+	appName string,
+	settings SettingRegistry,
+	products map[string]PropertiesSchema,
+) map[string]any {
+	names := slices.Sorted(maps.Keys(products))
+	settingsBlock := settingsSchema(settings)
+	return map[string]any{
+		"$schema":  SchemaDialect,
+		"title":    appName + " configuration",
+		"type":     "object",
+		"required": []string{appName},
+		"properties": map[string]any{
+			appName: map[string]any{
+				"type":     "object",
+				"required": []string{"settings", "products"},
+				"properties": map[string]any{
+					"version": map[string]any{
+						"type":        "integer",
+						"minimum":     0,
+						"description": "Configuration schema version.",
+					},
+					"settings": settingsBlock,
+					"products": productsSchema(names, products),
+					"webhooks": webhooksSchema(),
+					"environments": map[string]any{
+						"type": "object",
+						"additionalProperties": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"settings": settingsBlock,
+								"products": map[string]any{
+									"type": "array",
+									"items": map[string]any{
+										"type":     "object",
+										"required": []string{"name"},
+										"properties": map[string]any{
+											"name": productNamesSchema(names),
+											"properties": map[string]any{
+												"type": "object",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
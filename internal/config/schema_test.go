@@ -0,0 +1,93 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"sigs.k8s.io/yaml"
+)
+
+func TestJSONSchema(t *testing.T) {
+	g := o.NewWithT(t)
+
+	registry := SettingRegistry{
+		{Key: "crc", Type: SettingBool, Default: false},
+		{Key: "ci.debug", Type: SettingBool},
+		{Key: "tier", Type: SettingString, Allowed: []any{"dev", "prod"}},
+	}
+	products := map[string]PropertiesSchema{
+		"Product A": nil,
+		"Product B": {
+			"storageClass": {Type: SettingString, Required: true},
+			"replicas":     {Type: SettingInt, Default: 1},
+		},
+		"Product C": nil,
+		"Product D": nil,
+	}
+	payload, err := json.Marshal(JSONSchema("helmet_ex", registry, products))
+	g.Expect(err).To(o.Succeed())
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(payload))
+	g.Expect(err).To(o.Succeed())
+	compiler := jsonschema.NewCompiler()
+	g.Expect(compiler.AddResource("config.schema.json", doc)).To(o.Succeed())
+	schema, err := compiler.Compile("config.schema.json")
+	g.Expect(err).To(o.Succeed())
+
+	fixture, err := os.ReadFile("../../test/config.yaml")
+	g.Expect(err).To(o.Succeed())
+	// validate validates the configuration fixture, with the informed
+	// replacements.
+	validate := func(replacements ...string) error {
+		cfg := string(fixture)
+		for i := 0; i < len(replacements); i += 2 {
+			replaced := bytes.Replace([]byte(cfg),
+				[]byte(replacements[i]), []byte(replacements[i+1]), 1)
+			g.Expect(string(replaced)).NotTo(o.Equal(cfg))
+			cfg = string(replaced)
+		}
+		instance, err := yaml.YAMLToJSON([]byte(cfg))
+		g.Expect(err).To(o.Succeed())
+		value, err := jsonschema.UnmarshalJSON(bytes.NewReader(instance))
+		g.Expect(err).To(o.Succeed())
+		return schema.Validate(value)
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(validate()).To(o.Succeed())
+		g.Expect(validate("debug: false", "debug: false\n    tier: prod")).
+			To(o.Succeed())
+		// Properties accept value references.
+		g.Expect(validate("storageClass: standard", `storageClass:
+          valueFrom:
+            secretKeyRef:
+              name: storage
+              key: class`)).To(o.Succeed())
+	})
+
+	t.Run("Settings", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(validate("crc: false", "crc: maybe")).NotTo(o.Succeed())
+		g.Expect(validate("debug: false", "debug: 1")).NotTo(o.Succeed())
+		g.Expect(validate("debug: false", "debug: false\n    tier: qa")).
+			NotTo(o.Succeed())
+		g.Expect(validate("crc: false", "crc: false\n    sizing: huge")).
+			NotTo(o.Succeed())
+	})
+
+	t.Run("Products", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(validate("name: Product A", "name: Product Z")).
+			NotTo(o.Succeed())
+		g.Expect(validate("storageClass: standard", "storageClass: 1")).
+			NotTo(o.Succeed())
+		g.Expect(validate("storageClass: standard", "replicas: 2")).
+			NotTo(o.Succeed())
+		g.Expect(validate("storageClass: standard",
+			"storageClass: standard\n        replicas: two")).NotTo(o.Succeed())
+	})
+}
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SensitivePlaceholder replaces the sensitive field values on the ConfigMap, and
+// on the configuration displayed to the user. Updates carrying the placeholder
+// keep the value stored in the Secret.
+const SensitivePlaceholder = "<sensitive>"
+
+// ErrSensitiveNotStored the configuration carries SensitivePlaceholder on a field
+// without a value stored in the Secret, there's no value to keep.
+var ErrSensitiveNotStored = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+	"sensitive value not stored")
+
+// SensitiveKey the Secret data key holding the sensitive field values, as a YAML
+// mapping of the field path to its value.
+const SensitiveKey = "sensitive.yaml"
+
+// SensitiveFields configuration fields holding sensitive values, like passwords,
+// stored in a Secret companion to the configuration ConfigMap. Each field is a
+// dot separated path relative to the application root key, either
+// "settings.<key>[.<key>...]" or "products.<name>.<field>[.<key>...]", for
+// instance "products.Developer Hub.properties.smtpPassword".
+type SensitiveFields []string
+
+// Validate asserts the sensitive fields are well formed, and point to a value
+// rather than a whole product.
+func (s SensitiveFields) Validate() error {
+	for _, field := range s {
+		f, err := parseField(field)
+		if err != nil {
+			return err
+		}
+		if f.product != "" && len(f.keys) == 0 {
+			return fmt.Errorf("invalid sensitive field %q, expected %q",
+				field, "products.<name>.<field>")
+		}
+	}
+	return nil
+}
+
+// fieldPath translates the field into a configuration path, see parsePath.
+func fieldPath(field string) (string, error) {
+	f, err := parseField(field)
+	if err != nil {
+		return "", err
+	}
+	return f.path(), nil
+}
+
+// Redact returns a copy of the configuration with the sensitive field values
+// replaced by SensitivePlaceholder, and the values replaced, by field.
+func (s SensitiveFields) Redact(cfg *Config) (*Config, map[string]any, error) {
+	redacted, err := cfg.DeepCopy()
+	if err != nil {
+		return nil, nil, err
+	}
+	values := map[string]any{}
+	for _, field := range s {
+		value, err := fieldValue(cfg, field)
+		if err != nil {
+			return nil, nil, err
+		}
+		if value == nil {
+			continue
+		}
+		values[field] = value
+		path, err := fieldPath(field)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = redacted.SetPath(path, SensitivePlaceholder); err != nil {
+			return nil, nil, err
+		}
+	}
+	return redacted, values, nil
+}
+
+// Resolve sets the sensitive field values on the configuration, in place. Fields
+// absent on the configuration are skipped.
+func (s SensitiveFields) Resolve(cfg *Config, values map[string]any) error {
+	for _, field := range s {
+		value, ok := values[field]
+		if !ok {
+			continue
+		}
+		current, err := fieldValue(cfg, field)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			continue
+		}
+		path, err := fieldPath(field)
+		if err != nil {
+			return err
+		}
+		if err = cfg.SetPath(path, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// secretValues reads the sensitive field values stored in the companion Secret,
+// empty when the Secret doesn't exist.
+func (m *ConfigMapManager) secretValues(
+	ctx context.Context,
+	namespace string,
+) (map[string]any, error) {
+	coreClient, err := m.kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := coreClient.Secrets(namespace).
+		Get(ctx, m.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return map[string]any{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]any{}
+	if err = yaml.Unmarshal(secret.Data[SensitiveKey], &values); err != nil {
+		return nil, fmt.Errorf("secret %s/%s: invalid %q: %w",
+			namespace, m.name, SensitiveKey, err)
+	}
+	return values, nil
+}
+
+// writeSecret creates or updates the companion Secret with the sensitive field
+// values, deleting it when there are none.
+func (m *ConfigMapManager) writeSecret(
+	ctx context.Context,
+	namespace string,
+	values map[string]any,
+) error {
+	coreClient, err := m.kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return err
+	}
+	secrets := coreClient.Secrets(namespace)
+	if len(values) == 0 {
+		err = secrets.Delete(ctx, m.name, metav1.DeleteOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	payload, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      m.name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				annotations.Config: "true",
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{SensitiveKey: payload},
+	}
+	_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+	}
+	return err
+}
+
+// splitSensitive returns the configuration to store on the ConfigMap, with the
+// sensitive values redacted, and the values to store on the Secret. Fields
+// informed with SensitivePlaceholder keep the value already stored, and fail
+// with ErrSensitiveNotStored when there's none.
+func (m *ConfigMapManager) splitSensitive(
+	ctx context.Context,
+	cfg *Config,
+) (*Config, map[string]any, error) {
+	if len(m.sensitive) == 0 {
+		return cfg, nil, nil
+	}
+	redacted, values, err := m.sensitive.Redact(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	stored, err := m.secretValues(ctx, cfg.Namespace())
+	if err != nil {
+		return nil, nil, err
+	}
+	for field, value := range values {
+		if value != SensitivePlaceholder {
+			continue
+		}
+		previous, ok := stored[field]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: field %q carries %q, inform "+
+				"its value", ErrSensitiveNotStored, field, SensitivePlaceholder)
+		}
+		values[field] = previous
+	}
+	return redacted, values, nil
+}
+
+// Redact returns a copy of the configuration with the sensitive field values
+// replaced by SensitivePlaceholder, suitable for displaying.
+func (m *ConfigMapManager) Redact(cfg *Config) (*Config, error) {
+	if len(m.sensitive) == 0 {
+		return cfg, nil
+	}
+	redacted, _, err := m.sensitive.Redact(cfg)
+	return redacted, err
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"strconv"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Actors mutating the cluster configuration, annotated on it by every write.
+const (
+	// ActorCLI the configuration is changed by the command line.
+	ActorCLI = "cli"
+	// ActorMCP the configuration is changed by the MCP tools.
+	ActorMCP = "mcp"
+)
+
+// ErrStaleConfig when the configuration was mutated after the actor last read
+// it, the change is based on a stale view and must be reconsidered.
+var ErrStaleConfig = helmeterrors.New(helmeterrors.ErrConflict,
+	"configuration mutated since it was last read")
+
+// ConfigMapSequence returns the mutation sequence number annotated on the
+// ConfigMap, zero for configuration written before it was tracked.
+func ConfigMapSequence(cm *corev1.ConfigMap) int64 {
+	sequence, err := strconv.ParseInt(
+		cm.GetAnnotations()[annotations.ConfigSequence], 10, 64)
+	if err != nil || sequence < 0 {
+		return 0
+	}
+	return sequence
+}
+
+// ConfigMapMutatedBy returns the actor applying the last mutation annotated on
+// the ConfigMap, empty when unknown.
+func ConfigMapMutatedBy(cm *corev1.ConfigMap) string {
+	return cm.GetAnnotations()[annotations.ConfigMutatedBy]
+}
+
+// Sequence returns the mutation sequence number of the cluster configuration
+// when it was read, or written, zero for configuration not from the cluster.
+func (c *Config) Sequence() int64 {
+	return c.sequence
+}
+
+// MutatedBy returns the actor applying the last mutation on the cluster
+// configuration, when it was read, empty when unknown.
+func (c *Config) MutatedBy() string {
+	return c.mutatedBy
+}
+
+// annotate annotates the ConfigMap with the mutation sequence number, and the
+// actor applying it.
+func (m *ConfigMapManager) annotate(cm *corev1.ConfigMap, sequence int64) {
+	cm.SetAnnotations(map[string]string{
+		annotations.ConfigSequence:  strconv.FormatInt(sequence, 10),
+		annotations.ConfigMutatedBy: m.actor,
+	})
+}
+
+// stamp records the mutation sequence number, and the actor, written on the
+// cluster configuration.
+func (c *Config) stamp(cm *corev1.ConfigMap) {
+	c.resourceVersion = cm.GetResourceVersion()
+	c.sequence = ConfigMapSequence(cm)
+	c.mutatedBy = ConfigMapMutatedBy(cm)
+}
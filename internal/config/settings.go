@@ -0,0 +1,295 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+)
+
+// ErrInvalidSetting the setting value doesn't match its registered type or
+// allowed values, or the setting is unknown.
+var ErrInvalidSetting = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+	"invalid configuration setting")
+
+// SettingType the type of a setting value.
+type SettingType string
+
+const (
+	// SettingBool boolean setting, "true" or "false".
+	SettingBool SettingType = "bool"
+	// SettingString string setting.
+	SettingString SettingType = "string"
+	// SettingInt integer setting.
+	SettingInt SettingType = "int"
+	// SettingNumber numeric setting, integer or decimal.
+	SettingNumber SettingType = "number"
+)
+
+// Setting describes a setting known by the application, under the configuration
+// "settings" key.
+type Setting struct {
+	// Key dot separated path relative to "settings", for instance "crc" or
+	// "ci.debug".
+	Key string
+	// Type the value type.
+	Type SettingType
+	// Default the value assumed when the setting is absent, optional.
+	Default any
+	// Allowed the values accepted, any value of the type when empty.
+	Allowed []any
+	// Description describes the setting for the users and the AI assistant.
+	Description string
+}
+
+// Path returns the setting configuration path, see Config.SetPath.
+func (s Setting) Path() string {
+	return "settings." + s.Key
+}
+
+// Coerce asserts the value matches the setting type and allowed values, and
+// returns it normalized, integral numbers decoded from JSON become int.
+func (s Setting) Coerce(value any) (any, error) {
+	return coerce(ErrInvalidSetting, s.Key, s.Type, s.Allowed, value)
+}
+
+// coerce asserts the value matches the type and allowed values, and returns it
+// normalized, failures are reported with the informed sentinel error and key.
+func coerce(
+	sentinel error,
+	key string,
+	valueType SettingType,
+	allowed []any,
+	value any,
+) (any, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %q: %v %s", sentinel, key, value, reason)
+	}
+	var coerced any
+	switch valueType {
+	case SettingBool:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, invalid("is not a boolean")
+		}
+		coerced = b
+	case SettingString:
+		str, ok := value.(string)
+		if !ok {
+			return nil, invalid("is not a string")
+		}
+		coerced = str
+	case SettingInt:
+		f, ok := number(value)
+		if !ok || f != math.Trunc(f) {
+			return nil, invalid("is not an integer")
+		}
+		coerced = int(f)
+	case SettingNumber:
+		f, ok := number(value)
+		if !ok {
+			return nil, invalid("is not a number")
+		}
+		coerced = value
+		if f == math.Trunc(f) {
+			coerced = int(f)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q: unknown type %q",
+			sentinel, key, valueType)
+	}
+	if len(allowed) == 0 {
+		return coerced, nil
+	}
+	for _, a := range allowed {
+		if equalValues(a, coerced) {
+			return coerced, nil
+		}
+	}
+	return nil, invalid(fmt.Sprintf("is not one of %v", allowed))
+}
+
+// number returns the numeric value as float64.
+func number(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// equalValues compares scalar values, numbers regardless of their Go type.
+func equalValues(a, b any) bool {
+	fa, aok := number(a)
+	fb, bok := number(b)
+	if aok && bok {
+		return fa == fb
+	}
+	return a == b
+}
+
+// SettingRegistry the settings registered by the application.
+type SettingRegistry []Setting
+
+// Validate asserts the settings are well formed: unique keys, known types, and
+// default and allowed values matching the type.
+func (s SettingRegistry) Validate() error {
+	seen := map[string]bool{}
+	for _, setting := range s {
+		if setting.Key == "" || slices.Contains(strings.Split(setting.Key, "."), "") {
+			return fmt.Errorf("%w: invalid key %q", ErrInvalidSetting, setting.Key)
+		}
+		if seen[setting.Key] {
+			return fmt.Errorf("%w: %q registered twice", ErrInvalidSetting, setting.Key)
+		}
+		seen[setting.Key] = true
+		switch setting.Type {
+		case SettingBool, SettingString, SettingInt, SettingNumber:
+		default:
+			return fmt.Errorf("%w: %q: unknown type %q",
+				ErrInvalidSetting, setting.Key, setting.Type)
+		}
+		for _, allowed := range setting.Allowed {
+			if _, err := (Setting{Key: setting.Key, Type: setting.Type}).
+				Coerce(allowed); err != nil {
+				return err
+			}
+		}
+		if setting.Default != nil {
+			if _, err := setting.Coerce(setting.Default); err != nil {
+				return fmt.Errorf("default: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Lookup returns the setting registered with the key.
+func (s SettingRegistry) Lookup(key string) (*Setting, bool) {
+	for i := range s {
+		if s[i].Key == key {
+			return &s[i], true
+		}
+	}
+	return nil, false
+}
+
+// Keys returns the registered setting keys, in registration order.
+func (s SettingRegistry) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for _, setting := range s {
+		keys = append(keys, setting.Key)
+	}
+	return keys
+}
+
+// Resolve returns the setting registered with the key, or ErrInvalidSetting
+// listing the known keys. Any key is accepted when no setting is registered.
+func (s SettingRegistry) Resolve(key string) (*Setting, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	setting, ok := s.Lookup(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown setting %q, known settings are %v",
+			ErrInvalidSetting, key, s.Keys())
+	}
+	return setting, nil
+}
+
+// settingValue returns the setting value on the configuration, and whether it's
+// set.
+func (c *Config) settingValue(key string) (any, bool) {
+	var current any = c.Installer.Settings
+	for _, k := range strings.Split(key, ".") {
+		var m map[string]any
+		switch v := current.(type) {
+		case Settings:
+			m = v
+		case map[string]any:
+			m = v
+		default:
+			return nil, false
+		}
+		var ok bool
+		if current, ok = m[k]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// Check asserts the registered settings informed on the configuration match their
+// type and allowed values. Settings not registered are not checked.
+func (s SettingRegistry) Check(cfg *Config) error {
+	errs := []error{}
+	for _, setting := range s {
+		value, ok := cfg.settingValue(setting.Key)
+		if !ok {
+			continue
+		}
+		if _, err := setting.Coerce(value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SettingStatus a setting and its value on the configuration.
+type SettingStatus struct {
+	Key         string      `json:"key"`
+	Type        SettingType `json:"type,omitempty"`
+	Default     any         `json:"default,omitempty"`
+	Allowed     []any       `json:"allowed,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Value       any         `json:"value"`
+	Set         bool        `json:"set"`
+}
+
+// Status returns the registered settings and their value on the configuration,
+// the default when absent. Without registered settings, the top level settings on
+// the configuration are returned, sorted by key.
+func (s SettingRegistry) Status(cfg *Config) []SettingStatus {
+	status := []SettingStatus{}
+	if len(s) == 0 {
+		for _, key := range slices.Sorted(maps.Keys(cfg.Installer.Settings)) {
+			status = append(status, SettingStatus{
+				Key:   key,
+				Value: cfg.Installer.Settings[key],
+				Set:   true,
+			})
+		}
+		return status
+	}
+	for _, setting := range s {
+		value, set := cfg.settingValue(setting.Key)
+		if !set {
+			value = setting.Default
+		}
+		status = append(status, SettingStatus{
+			Key:         setting.Key,
+			Type:        setting.Type,
+			Default:     setting.Default,
+			Allowed:     setting.Allowed,
+			Description: setting.Description,
+			Value:       value,
+			Set:         set,
+		})
+	}
+	return status
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestSettingRegistry(t *testing.T) {
+	g := o.NewWithT(t)
+
+	registry := SettingRegistry{{
+		Key:     "crc",
+		Type:    SettingBool,
+		Default: false,
+	}, {
+		Key:     "ci.debug",
+		Type:    SettingBool,
+		Default: false,
+	}, {
+		Key:     "mode",
+		Type:    SettingString,
+		Default: "fast",
+		Allowed: []any{"fast", "safe"},
+	}, {
+		Key:     "replicas",
+		Type:    SettingInt,
+		Allowed: []any{1, 3},
+	}, {
+		Key:  "ratio",
+		Type: SettingNumber,
+	}}
+	g.Expect(registry.Validate()).To(o.Succeed())
+
+	t.Run("Validate", func(t *testing.T) {
+		g := o.NewWithT(t)
+		for _, invalid := range []SettingRegistry{
+			{{Key: "", Type: SettingBool}},
+			{{Key: "ci..debug", Type: SettingBool}},
+			{{Key: "crc", Type: SettingBool}, {Key: "crc", Type: SettingBool}},
+			{{Key: "crc", Type: "date"}},
+			{{Key: "crc", Type: SettingBool, Default: "no"}},
+			{{Key: "mode", Type: SettingString, Allowed: []any{1}}},
+		} {
+			g.Expect(invalid.Validate()).To(o.MatchError(ErrInvalidSetting))
+		}
+	})
+
+	t.Run("Coerce", func(t *testing.T) {
+		g := o.NewWithT(t)
+		replicas, _ := registry.Lookup("replicas")
+		// JSON numbers are decoded as float64.
+		value, err := replicas.Coerce(float64(3))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(value).To(o.Equal(3))
+		_, err = replicas.Coerce(2)
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("is not one of [1 3]")))
+		_, err = replicas.Coerce(1.5)
+		g.Expect(err).To(o.MatchError(ErrInvalidSetting))
+
+		ratio, _ := registry.Lookup("ratio")
+		value, err = ratio.Coerce(0.5)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(value).To(o.Equal(0.5))
+
+		mode, _ := registry.Lookup("mode")
+		_, err = mode.Coerce(true)
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("is not a string")))
+
+		_, err = registry.Resolve("unknown")
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("known settings are")))
+		setting, err := SettingRegistry{}.Resolve("unknown")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(setting).To(o.BeNil())
+	})
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := NewConfigFromFile(
+		cfs, "config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+
+	t.Run("Status", func(t *testing.T) {
+		g := o.NewWithT(t)
+		status := registry.Status(cfg)
+		g.Expect(status).To(o.HaveLen(len(registry)))
+		g.Expect(status[1]).To(o.Equal(SettingStatus{
+			Key:     "ci.debug",
+			Type:    SettingBool,
+			Default: false,
+			Value:   false,
+			Set:     true,
+		}))
+		g.Expect(status[2].Value).To(o.Equal("fast"))
+		g.Expect(status[2].Set).To(o.BeFalse())
+
+		// Without registry the configuration settings are listed.
+		status = SettingRegistry{}.Status(cfg)
+		g.Expect(status).ToNot(o.BeEmpty())
+		g.Expect(status[0].Key).To(o.Equal("ci"))
+	})
+
+	t.Run("Check", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(registry.Check(cfg)).To(o.Succeed())
+
+		invalid, err := cfg.DeepCopy()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(invalid.SetPath("settings.crc", "yes")).To(o.Succeed())
+		g.Expect(registry.Check(invalid)).To(o.MatchError(ErrInvalidSetting))
+
+		m := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex")
+		m.SetSettings(registry)
+		g.Expect(m.Create(context.Background(), invalid)).
+			To(o.MatchError(ErrInvalidSetting))
+	})
+}
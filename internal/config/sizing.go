@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// SizingSetting the installer setting selecting the sizing profile of every
+// dependency, products may select their own with the "sizing" field.
+const SizingSetting = "sizing"
+
+// Sizing profiles, the T-shirt sized values presets the charts may declare.
+const (
+	SizingSmall  = "small"
+	SizingMedium = "medium"
+	SizingLarge  = "large"
+)
+
+// SizingProfiles the sizing profiles, from the smallest to the largest.
+var SizingProfiles = []string{SizingSmall, SizingMedium, SizingLarge}
+
+// validateSizing asserts the sizing profile is known, empty means none.
+func validateSizing(profile string) error {
+	if profile == "" || slices.Contains(SizingProfiles, profile) {
+		return nil
+	}
+	return fmt.Errorf("invalid sizing profile %q, expected one of: %v",
+		profile, SizingProfiles)
+}
+
+// GlobalSizing returns the sizing profile selected on the settings, empty when
+// none is selected.
+func (c *Config) GlobalSizing() (string, error) {
+	setting, ok := c.Installer.Settings[SizingSetting]
+	if !ok || setting == nil {
+		return "", nil
+	}
+	profile, ok := setting.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: setting %q must be a string",
+			ErrInvalidConfig, SizingSetting)
+	}
+	if err := validateSizing(profile); err != nil {
+		return "", fmt.Errorf("%w: setting %q: %w",
+			ErrInvalidConfig, SizingSetting, err)
+	}
+	return profile, nil
+}
+
+// Sizing returns the sizing profile for the product, its own or the global
+// profile. Without product name, for dependencies not belonging to a product,
+// the global profile is returned. Empty when no profile is selected.
+func (c *Config) Sizing(product string) (string, error) {
+	if product != "" {
+		p, err := c.GetProduct(product)
+		if err != nil {
+			return "", err
+		}
+		if p.Sizing != "" {
+			return p.Sizing, nil
+		}
+	}
+	return c.GlobalSizing()
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	// BackendConfigMap stores the configuration in a ConfigMap, the default.
+	BackendConfigMap = "configmap"
+	// BackendCRD stores the configuration in a HelmetInstallation custom
+	// resource, the definition is installed on demand.
+	BackendCRD = "crd"
+)
+
+// ValidateBackend asserts the configuration backend is supported, empty selects
+// the default.
+func ValidateBackend(backend string) error {
+	switch backend {
+	case "", BackendConfigMap, BackendCRD:
+		return nil
+	default:
+		return fmt.Errorf("invalid configuration backend %q, use %q or %q",
+			backend, BackendConfigMap, BackendCRD)
+	}
+}
+
+// store persists the configuration in the cluster. Regardless of the resource
+// kind, the configuration is exchanged as a ConfigMap, the manager's native
+// representation.
+type store interface {
+	// list lists the stored configurations, matching Selector, on every
+	// namespace.
+	list(context.Context) ([]corev1.ConfigMap, error)
+	// create creates the configuration resource.
+	create(context.Context, *corev1.ConfigMap) error
+	// update replaces the configuration resource, stamping the ConfigMap with
+	// the resulting resource version.
+	update(context.Context, *corev1.ConfigMap) error
+	// delete deletes the configuration resource.
+	delete(ctx context.Context, namespace, name string) error
+	// watch watches the configuration resources, on every namespace.
+	watch(context.Context) (watch.Interface, error)
+}
+
+// configMapStore stores the configuration as a ConfigMap.
+type configMapStore struct {
+	kube k8s.Interface // kubernetes client
+}
+
+var _ store = &configMapStore{}
+
+// list lists the ConfigMaps matching Selector.
+func (s *configMapStore) list(ctx context.Context) ([]corev1.ConfigMap, error) {
+	coreClient, err := s.kube.CoreV1ClientSet("")
+	if err != nil {
+		return nil, err
+	}
+	configMapList, err := coreClient.ConfigMaps("").List(ctx, metav1.ListOptions{
+		LabelSelector: Selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return configMapList.Items, nil
+}
+
+// create creates the ConfigMap.
+func (s *configMapStore) create(ctx context.Context, cm *corev1.ConfigMap) error {
+	coreClient, err := s.kube.CoreV1ClientSet(cm.GetNamespace())
+	if err != nil {
+		return err
+	}
+	_, err = coreClient.ConfigMaps(cm.GetNamespace()).
+		Create(ctx, cm, metav1.CreateOptions{})
+	return err
+}
+
+// update updates the ConfigMap.
+func (s *configMapStore) update(ctx context.Context, cm *corev1.ConfigMap) error {
+	coreClient, err := s.kube.CoreV1ClientSet(cm.GetNamespace())
+	if err != nil {
+		return err
+	}
+	updated, err := coreClient.ConfigMaps(cm.GetNamespace()).
+		Update(ctx, cm, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	cm.SetResourceVersion(updated.GetResourceVersion())
+	return nil
+}
+
+// delete deletes the ConfigMap.
+func (s *configMapStore) delete(ctx context.Context, namespace, name string) error {
+	coreClient, err := s.kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return err
+	}
+	return coreClient.ConfigMaps(namespace).
+		Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// watch watches the ConfigMaps matching Selector.
+func (s *configMapStore) watch(ctx context.Context) (watch.Interface, error) {
+	coreClient, err := s.kube.CoreV1ClientSet("")
+	if err != nil {
+		return nil, err
+	}
+	return coreClient.ConfigMaps("").Watch(ctx, metav1.ListOptions{
+		LabelSelector: Selector,
+	})
+}
+
+// newStore instantiates the store for the backend, the ConfigMap by default.
+func newStore(kube k8s.Interface, backend string) store {
+	if backend == BackendCRD {
+		return &crdStore{kube: kube}
+	}
+	return &configMapStore{kube: kube}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+)
+
+// ErrTransform the application configuration transform failed.
+var ErrTransform = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+	"configuration transform failed")
+
+// Transform enforces application invariants on the configuration, changing it
+// in place, for instance enabling the foundation products or normalizing the
+// namespaces. Transforms must be idempotent, they run whenever the
+// configuration is loaded or saved.
+type Transform func(cfg *Config) error
+
+// Transforms the application configuration transforms, applied in order.
+type Transforms []Transform
+
+// Apply applies the transforms on the configuration, the transformed
+// configuration must remain valid.
+func (t Transforms) Apply(cfg *Config) error {
+	if len(t) == 0 {
+		return nil
+	}
+	for _, transform := range t {
+		if err := transform(cfg); err != nil {
+			return fmt.Errorf("%w: %w", ErrTransform, err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrTransform, err)
+	}
+	return nil
+}
+
+// WithTransforms applies the transforms on the configuration after it's loaded.
+func WithTransforms(transforms Transforms) Option {
+	return func(c *Config) {
+		c.transforms = transforms
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestTransforms(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	// enableFoundation enables "Product C", the foundation product.
+	enableFoundation := func(cfg *Config) error {
+		product, err := cfg.GetProduct("Product C")
+		if err != nil {
+			return err
+		}
+		spec := *product
+		spec.Enabled = true
+		return cfg.SetProduct(spec.Name, spec)
+	}
+	transforms := Transforms{enableFoundation}
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := NewConfigFromFile(
+		cfs, "config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+	disableFoundation := func(cfg *Config) {
+		product, err := cfg.GetProduct("Product C")
+		g.Expect(err).To(o.Succeed())
+		spec := *product
+		spec.Enabled = false
+		g.Expect(cfg.SetProduct(spec.Name, spec)).To(o.Succeed())
+	}
+	disableFoundation(cfg)
+
+	enabled := func(cfg *Config) bool {
+		product, err := cfg.GetProduct("Product C")
+		g.Expect(err).To(o.Succeed())
+		return product.Enabled
+	}
+
+	t.Run("Load", func(t *testing.T) {
+		loaded, err := NewConfigFromBytes([]byte(cfg.String()),
+			"test-namespace", "helmet_ex", WithTransforms(transforms))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(enabled(loaded)).To(o.BeTrue())
+	})
+
+	t.Run("Manager", func(t *testing.T) {
+		m := NewConfigMapManager(k8s.NewFakeKube(), "helmet-ex")
+		cm, err := m.configMapForConfig(cfg)
+		g.Expect(err).To(o.Succeed())
+
+		m = NewConfigMapManager(k8s.NewFakeKube(cm), "helmet-ex")
+		m.SetTransforms(transforms)
+		stored, err := m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(enabled(stored)).To(o.BeTrue())
+
+		disableFoundation(stored)
+		g.Expect(m.Update(ctx, stored)).To(o.Succeed())
+		g.Expect(enabled(stored)).To(o.BeTrue())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		failing := Transforms{func(*Config) error {
+			return errors.New("product C is mandatory")
+		}}
+		_, err := NewConfigFromBytes([]byte(cfg.String()),
+			"test-namespace", "helmet_ex", WithTransforms(failing))
+		g.Expect(err).To(o.MatchError(ErrTransform))
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("product C is mandatory")))
+
+		invalid := Transforms{func(cfg *Config) error {
+			cfg.Installer.Settings = nil
+			return nil
+		}}
+		g.Expect(invalid.Apply(cfg)).To(o.MatchError(ErrInvalidConfig))
+	})
+}
@@ -0,0 +1,280 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ValueFromKey the product property object key referencing a value stored on a
+// cluster Secret or ConfigMap, instead of informing it in the configuration. For
+// instance:
+//
+//	properties:
+//	  dbPassword:
+//	    valueFrom:
+//	      secretKeyRef:
+//	        name: database
+//	        key: password
+const ValueFromKey = "valueFrom"
+
+var (
+	// ErrInvalidValueFrom the value reference is malformed.
+	ErrInvalidValueFrom = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+		"invalid value reference")
+	// ErrValueFromNotFound the referenced Secret, ConfigMap or key doesn't exist
+	// in the cluster.
+	ErrValueFromNotFound = helmeterrors.New(
+		helmeterrors.ErrPrerequisitesMissing, "referenced value not found")
+)
+
+// KeyRef references a key of a Secret or ConfigMap, on the product namespace
+// unless informed.
+type KeyRef struct {
+	Name      string `yaml:"name"`
+	Key       string `yaml:"key"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// ValueFrom the value reference, either to a Secret or to a ConfigMap key.
+type ValueFrom struct {
+	SecretKeyRef    *KeyRef `yaml:"secretKeyRef,omitempty"`
+	ConfigMapKeyRef *KeyRef `yaml:"configMapKeyRef,omitempty"`
+}
+
+// IsValueFrom checks whether the property value is a value reference, an object
+// with the single ValueFromKey key.
+func IsValueFrom(value any) bool {
+	m, ok := value.(map[string]any)
+	if !ok || len(m) != 1 {
+		return false
+	}
+	_, ok = m[ValueFromKey]
+	return ok
+}
+
+// parseValueFrom parses the value reference, asserting it references a single
+// object key.
+func parseValueFrom(value any) (*ValueFrom, error) {
+	payload, err := yaml.Marshal(value.(map[string]any)[ValueFromKey])
+	if err != nil {
+		return nil, err
+	}
+	ref := &ValueFrom{}
+	if err = yaml.Unmarshal(payload, ref); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidValueFrom, err)
+	}
+	keyRef := ref.SecretKeyRef
+	switch {
+	case ref.SecretKeyRef != nil && ref.ConfigMapKeyRef != nil:
+		return nil, fmt.Errorf("%w: inform either secretKeyRef or "+
+			"configMapKeyRef", ErrInvalidValueFrom)
+	case ref.SecretKeyRef == nil && ref.ConfigMapKeyRef == nil:
+		return nil, fmt.Errorf("%w: secretKeyRef or configMapKeyRef is "+
+			"required", ErrInvalidValueFrom)
+	case keyRef == nil:
+		keyRef = ref.ConfigMapKeyRef
+	}
+	if keyRef.Name == "" || keyRef.Key == "" {
+		return nil, fmt.Errorf("%w: name and key are required",
+			ErrInvalidValueFrom)
+	}
+	return ref, nil
+}
+
+// walkValueFrom visits the value references on the properties, nested objects
+// and lists included, replacing them by the value returned by fn. Returns a copy
+// of the properties, the informed ones are not modified.
+func walkValueFrom(
+	value any,
+	path string,
+	fn func(path string, ref *ValueFrom) (any, error),
+) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		if IsValueFrom(v) {
+			ref, err := parseValueFrom(v)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", path, err)
+			}
+			return fn(path, ref)
+		}
+		walked := make(map[string]any, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			w, err := walkValueFrom(v[k], joinPath(path, k), fn)
+			if err != nil {
+				return nil, err
+			}
+			walked[k] = w
+		}
+		return walked, nil
+	case []any:
+		walked := make([]any, len(v))
+		for i, item := range v {
+			w, err := walkValueFrom(item, fmt.Sprintf("%s[%d]", path, i), fn)
+			if err != nil {
+				return nil, err
+			}
+			walked[i] = w
+		}
+		return walked, nil
+	default:
+		return value, nil
+	}
+}
+
+// joinPath joins the property path with the key.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// validateValueFrom asserts the value references on the properties are well
+// formed.
+func validateValueFrom(properties map[string]any) error {
+	_, err := walkValueFrom(properties, "", func(string, *ValueFrom) (any, error) {
+		return nil, nil
+	})
+	return err
+}
+
+// configMapData returns the ConfigMap data and binary data keys.
+func configMapData(cm *corev1.ConfigMap) map[string][]byte {
+	data := maps.Clone(cm.BinaryData)
+	if data == nil {
+		data = map[string][]byte{}
+	}
+	for k, v := range cm.Data {
+		data[k] = []byte(v)
+	}
+	return data
+}
+
+// resolveKeyRef reads the referenced Secret, or ConfigMap, key.
+func resolveKeyRef(
+	ctx context.Context,
+	kube k8s.Interface,
+	namespace string,
+	ref *ValueFrom,
+) (string, error) {
+	keyRef, kind := ref.SecretKeyRef, "Secret"
+	if keyRef == nil {
+		keyRef, kind = ref.ConfigMapKeyRef, "ConfigMap"
+	}
+	if keyRef.Namespace != "" {
+		namespace = keyRef.Namespace
+	}
+	coreClient, err := kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return "", err
+	}
+	var data map[string][]byte
+	if ref.SecretKeyRef != nil {
+		var secret *corev1.Secret
+		secret, err = coreClient.Secrets(namespace).
+			Get(ctx, keyRef.Name, metav1.GetOptions{})
+		if err == nil {
+			data = secret.Data
+		}
+	} else {
+		var cm *corev1.ConfigMap
+		cm, err = coreClient.ConfigMaps(namespace).
+			Get(ctx, keyRef.Name, metav1.GetOptions{})
+		if err == nil {
+			data = configMapData(cm)
+		}
+	}
+	if apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("%w: %s %s/%s", ErrValueFromNotFound,
+			kind, namespace, keyRef.Name)
+	}
+	if err != nil {
+		return "", err
+	}
+	value, ok := data[keyRef.Key]
+	if !ok {
+		return "", fmt.Errorf("%w: %s %s/%s: key %q", ErrValueFromNotFound,
+			kind, namespace, keyRef.Name, keyRef.Key)
+	}
+	return string(value), nil
+}
+
+// ResolveValueFrom returns a copy of the properties with the value references
+// replaced by the referenced Secret or ConfigMap values, as strings. References
+// without namespace use the informed namespace.
+func ResolveValueFrom(
+	ctx context.Context,
+	kube k8s.Interface,
+	namespace string,
+	properties map[string]any,
+) (map[string]any, error) {
+	if properties == nil {
+		return nil, nil
+	}
+	resolved, err := walkValueFrom(properties, "",
+		func(path string, ref *ValueFrom) (any, error) {
+			value, err := resolveKeyRef(ctx, kube, namespace, ref)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", path, err)
+			}
+			return value, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(map[string]any), nil
+}
+
+// ResolveProducts returns a copy of the products with the value references on
+// the enabled products properties resolved, see ResolveValueFrom. References
+// default to the product namespace.
+func (c *Config) ResolveProducts(
+	ctx context.Context,
+	kube k8s.Interface,
+) ([]Product, error) {
+	products := slices.Clone(c.Installer.Products)
+	for i, p := range products {
+		if !p.Enabled {
+			continue
+		}
+		namespace := p.GetNamespace()
+		if namespace == "" {
+			namespace = c.Namespace()
+		}
+		properties, err := ResolveValueFrom(ctx, kube, namespace, p.Properties)
+		if err != nil {
+			return nil, fmt.Errorf("product %q: properties: %w", p.Name, err)
+		}
+		products[i].Properties = properties
+	}
+	return products, nil
+}
+
+// ProductValueFromPaths returns the value references on the enabled products
+// properties, as "<product>: <path>", for the consumers which can't resolve
+// them.
+func (c *Config) ProductValueFromPaths() []string {
+	paths := []string{}
+	for _, p := range c.Installer.Products {
+		if !p.Enabled {
+			continue
+		}
+		_, _ = walkValueFrom(p.Properties, "",
+			func(path string, _ *ValueFrom) (any, error) {
+				paths = append(paths, p.Name+": "+path)
+				return nil, nil
+			})
+	}
+	return paths
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValueFrom(t *testing.T) {
+	ctx := context.Background()
+	kube := k8s.NewFakeKube(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "product-b"},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	}, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "endpoints", Namespace: "shared"},
+		Data:       map[string]string{"host": "db.example.com"},
+	})
+
+	secretRef := func(name, key string) map[string]any {
+		return map[string]any{ValueFromKey: map[string]any{
+			"secretKeyRef": map[string]any{"name": name, "key": key},
+		}}
+	}
+
+	t.Run("IsValueFrom", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(IsValueFrom(secretRef("database", "password"))).To(o.BeTrue())
+		g.Expect(IsValueFrom(map[string]any{
+			ValueFromKey: map[string]any{}, "other": true,
+		})).To(o.BeFalse())
+		g.Expect(IsValueFrom("valueFrom")).To(o.BeFalse())
+	})
+
+	t.Run("ResolveValueFrom", func(t *testing.T) {
+		g := o.NewWithT(t)
+		properties := map[string]any{
+			"replicas": 3,
+			"database": map[string]any{
+				"password": secretRef("database", "password"),
+				"host": map[string]any{ValueFromKey: map[string]any{
+					"configMapKeyRef": map[string]any{
+						"name": "endpoints", "key": "host", "namespace": "shared",
+					},
+				}},
+			},
+			"hosts": []any{secretRef("database", "password")},
+		}
+		resolved, err := ResolveValueFrom(ctx, kube, "product-b", properties)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(resolved).To(o.Equal(map[string]any{
+			"replicas": 3,
+			"database": map[string]any{
+				"password": "s3cr3t",
+				"host":     "db.example.com",
+			},
+			"hosts": []any{"s3cr3t"},
+		}))
+		// The informed properties are not modified.
+		g.Expect(IsValueFrom(properties["database"].(map[string]any)["password"])).
+			To(o.BeTrue())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := ResolveValueFrom(ctx, kube, "product-b", map[string]any{
+			"password": secretRef("missing", "password"),
+		})
+		g.Expect(err).To(o.MatchError(ErrValueFromNotFound))
+		g.Expect(err.Error()).To(o.ContainSubstring(`"password"`))
+
+		_, err = ResolveValueFrom(ctx, kube, "product-b", map[string]any{
+			"password": secretRef("database", "missing"),
+		})
+		g.Expect(err).To(o.MatchError(ErrValueFromNotFound))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(validateValueFrom(map[string]any{
+			"password": secretRef("database", ""),
+		})).To(o.MatchError(ErrInvalidValueFrom))
+		g.Expect(validateValueFrom(map[string]any{
+			"password": map[string]any{ValueFromKey: map[string]any{}},
+		})).To(o.MatchError(ErrInvalidValueFrom))
+		g.Expect(validateValueFrom(map[string]any{
+			"password": map[string]any{ValueFromKey: map[string]any{
+				"secretKeyRef":    map[string]any{"name": "a", "key": "b"},
+				"configMapKeyRef": map[string]any{"name": "a", "key": "b"},
+			}},
+		})).To(o.MatchError(ErrInvalidValueFrom))
+	})
+
+	t.Run("ResolveProducts", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := NewConfigFromFile(chartfs.New(os.DirFS("../../test")),
+			"config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cfg.SetPath("products[name=Product B].properties.password",
+			secretRef("database", "password"))).To(o.Succeed())
+		g.Expect(cfg.SetPath("products[name=Product B].namespace",
+			"product-b")).To(o.Succeed())
+
+		products, err := cfg.ResolveProducts(ctx, kube)
+		g.Expect(err).To(o.Succeed())
+		idx := slices.IndexFunc(products, func(p Product) bool {
+			return p.Name == "Product B"
+		})
+		g.Expect(idx).NotTo(o.Equal(-1))
+		g.Expect(products[idx].Properties).
+			To(o.HaveKeyWithValue("password", "s3cr3t"))
+		// The configuration keeps the reference.
+		b, err := cfg.GetProduct("Product B")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(IsValueFrom(b.Properties["password"])).To(o.BeTrue())
+	})
+
+	t.Run("ProductValueFromPaths", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := NewConfigFromFile(chartfs.New(os.DirFS("../../test")),
+			"config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cfg.ProductValueFromPaths()).To(o.BeEmpty())
+
+		g.Expect(cfg.SetPath("products[name=Product B].properties.password",
+			secretRef("database", "password"))).To(o.Succeed())
+		g.Expect(cfg.ProductValueFromPaths()).
+			To(o.Equal([]string{"Product B: password"}))
+	})
+}
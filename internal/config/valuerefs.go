@@ -0,0 +1,297 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+)
+
+// ValueReferencesSetting the installer setting narrowing the value references
+// allowlisted by the application to a subset, see ValueRefAllowlist. The
+// setting can't allow references the application doesn't, for instance:
+//
+//	settings:
+//	  valueReferences:
+//	    - secret:mail/smtp-credentials
+const ValueReferencesSetting = "valueReferences"
+
+const (
+	// SecretRefPrefix prefixes the rendered values referencing a Secret key, as
+	// "secretRef:<namespace>/<name>/<key>".
+	SecretRefPrefix = "secretRef:"
+	// ConfigMapRefPrefix prefixes the rendered values referencing a ConfigMap
+	// key, as "configMapRef:<namespace>/<name>/<key>".
+	ConfigMapRefPrefix = "configMapRef:"
+)
+
+// ErrValueRefDenied the value reference isn't allowlisted by the application,
+// or the ValueReferencesSetting narrows it out.
+var ErrValueRefDenied = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+	"value reference not allowed")
+
+// ValueRefAllowlist the cluster Secrets and ConfigMaps the values templates may
+// reference, as "secret:<ns>/<name>" or "configMap:<ns>/<name>"; the name and
+// namespace accept shell globs. For instance "secret:mail/smtp-credentials" or
+// "configMap:openshift-config/*".
+type ValueRefAllowlist []string
+
+// Validate asserts the allowlist entries are well formed.
+func (a ValueRefAllowlist) Validate() error {
+	for _, entry := range a {
+		kind, pattern, _ := strings.Cut(entry, ":")
+		namespace, name, found := strings.Cut(pattern, "/")
+		_, err := path.Match(pattern, "")
+		if (kind != "secret" && kind != "configMap") || !found ||
+			namespace == "" || name == "" || err != nil {
+			return fmt.Errorf("%w: invalid value reference entry %q, "+
+				"expecting \"secret:<namespace>/<name>\" or "+
+				"\"configMap:<namespace>/<name>\"", ErrInvalidConfig, entry)
+		}
+	}
+	return nil
+}
+
+// Allows returns whether the Secret, or ConfigMap, is allowlisted.
+func (a ValueRefAllowlist) Allows(ref *ValueFrom) bool {
+	keyRef, kind := ref.SecretKeyRef, "secret"
+	if keyRef == nil {
+		keyRef, kind = ref.ConfigMapKeyRef, "configMap"
+	}
+	target := keyRef.Namespace + "/" + keyRef.Name
+	for _, entry := range a {
+		entryKind, pattern, _ := strings.Cut(entry, ":")
+		if entryKind != kind {
+			continue
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ValueRefAllowlist returns the value references narrowing the application
+// allowlist, nil when the setting is absent, thus the application allowlist
+// applies as is.
+func (c *Config) ValueRefAllowlist() (ValueRefAllowlist, error) {
+	setting, ok := c.Installer.Settings[ValueReferencesSetting]
+	if !ok || setting == nil {
+		return nil, nil
+	}
+	items, ok := setting.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: setting %q must be a list",
+			ErrInvalidConfig, ValueReferencesSetting)
+	}
+	allowlist := make(ValueRefAllowlist, 0, len(items))
+	for _, item := range items {
+		allowlist = append(allowlist, fmt.Sprint(item))
+	}
+	if err := allowlist.Validate(); err != nil {
+		return nil, fmt.Errorf("setting %q: %w", ValueReferencesSetting, err)
+	}
+	return allowlist, nil
+}
+
+// parseValueRef parses the rendered value placeholder, returns nil when the
+// value isn't a reference.
+func parseValueRef(value string) (*ValueFrom, error) {
+	var spec string
+	ref := &ValueFrom{}
+	keyRef := &KeyRef{}
+	switch {
+	case strings.HasPrefix(value, SecretRefPrefix):
+		spec = strings.TrimPrefix(value, SecretRefPrefix)
+		ref.SecretKeyRef = keyRef
+	case strings.HasPrefix(value, ConfigMapRefPrefix):
+		spec = strings.TrimPrefix(value, ConfigMapRefPrefix)
+		ref.ConfigMapKeyRef = keyRef
+	default:
+		return nil, nil
+	}
+	parts := strings.Split(spec, "/")
+	if len(parts) != 3 || slices.Contains(parts, "") {
+		return nil, fmt.Errorf("%w: %q, expecting <namespace>/<name>/<key>",
+			ErrInvalidValueFrom, value)
+	}
+	keyRef.Namespace, keyRef.Name, keyRef.Key = parts[0], parts[1], parts[2]
+	return ref, nil
+}
+
+// walkValueRefs visits the string values, nested objects and lists included,
+// replacing the references by the value returned by fn. Returns a copy of the
+// values, the informed ones are not modified.
+func walkValueRefs(
+	value any,
+	path string,
+	fn func(path string, ref *ValueFrom) (any, error),
+) (any, error) {
+	switch v := value.(type) {
+	case string:
+		ref, err := parseValueRef(v)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", path, err)
+		}
+		if ref == nil {
+			return v, nil
+		}
+		return fn(path, ref)
+	case map[string]any:
+		walked := make(map[string]any, len(v))
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			w, err := walkValueRefs(v[k], joinPath(path, k), fn)
+			if err != nil {
+				return nil, err
+			}
+			walked[k] = w
+		}
+		return walked, nil
+	case []any:
+		walked := make([]any, len(v))
+		for i, item := range v {
+			w, err := walkValueRefs(item, fmt.Sprintf("%s[%d]", path, i), fn)
+			if err != nil {
+				return nil, err
+			}
+			walked[i] = w
+		}
+		return walked, nil
+	default:
+		return value, nil
+	}
+}
+
+// allowValueRef asserts the reference is allowed by the application allowlist,
+// and by the narrowing one when not nil.
+func allowValueRef(
+	allowlist ValueRefAllowlist,
+	narrowed ValueRefAllowlist,
+	path string,
+	ref *ValueFrom,
+) error {
+	if !allowlist.Allows(ref) {
+		return fmt.Errorf("%q: %w by the application", path, ErrValueRefDenied)
+	}
+	if narrowed != nil && !narrowed.Allows(ref) {
+		return fmt.Errorf("%q: %w by the %q setting",
+			path, ErrValueRefDenied, ValueReferencesSetting)
+	}
+	return nil
+}
+
+// ResolveValueRefs returns a copy of the rendered values with the "secretRef:"
+// and "configMapRef:" placeholders replaced by the referenced keys, as strings.
+// The references must be allowed by the application allowlist, and by the
+// narrowing one when not nil; the others fail before the cluster is reached.
+func ResolveValueRefs(
+	ctx context.Context,
+	kube k8s.Interface,
+	allowlist ValueRefAllowlist,
+	narrowed ValueRefAllowlist,
+	values map[string]any,
+) (map[string]any, error) {
+	if values == nil {
+		return nil, nil
+	}
+	resolved, err := walkValueRefs(values, "",
+		func(path string, ref *ValueFrom) (any, error) {
+			if err := allowValueRef(allowlist, narrowed, path, ref); err != nil {
+				return nil, err
+			}
+			value, err := resolveKeyRef(ctx, kube, "", ref)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", path, err)
+			}
+			return value, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(map[string]any), nil
+}
+
+// ValueRefTarget a value reference on the rendered values, and the path of the
+// value, as Helm "--set" keys: dot separated, dots on the keys escaped.
+type ValueRefTarget struct {
+	Path string     // value path
+	Ref  *ValueFrom // referenced Secret, or ConfigMap, key
+}
+
+// ExtractValueRefs returns a copy of the rendered values without the value
+// references, and the references removed, allowed as ResolveValueRefs does. The
+// cluster isn't read, resolving the references is left to the consumer, like
+// the Flux HelmRelease "valuesFrom". References on list items can't be told
+// apart by path, they are not supported.
+func ExtractValueRefs(
+	allowlist ValueRefAllowlist,
+	narrowed ValueRefAllowlist,
+	values map[string]any,
+) (map[string]any, []ValueRefTarget, error) {
+	if values == nil {
+		return nil, nil, nil
+	}
+	targets := []ValueRefTarget{}
+	extracted, err := extractValueRefs(values, "", "",
+		func(path, setPath string, ref *ValueFrom) error {
+			if err := allowValueRef(allowlist, narrowed, path, ref); err != nil {
+				return err
+			}
+			targets = append(targets, ValueRefTarget{Path: setPath, Ref: ref})
+			return nil
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+	return extracted, targets, nil
+}
+
+// extractValueRefs copies the values without the references, informing fn of
+// each one, with its path and Helm "--set" path.
+func extractValueRefs(
+	values map[string]any,
+	path string,
+	setPath string,
+	fn func(path, setPath string, ref *ValueFrom) error,
+) (map[string]any, error) {
+	extracted := make(map[string]any, len(values))
+	for _, k := range slices.Sorted(maps.Keys(values)) {
+		p := joinPath(path, k)
+		sp := joinPath(setPath, strings.ReplaceAll(k, ".", `\.`))
+		switch v := values[k].(type) {
+		case string:
+			ref, err := parseValueRef(v)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", p, err)
+			}
+			if ref != nil {
+				if err = fn(p, sp, ref); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		case map[string]any:
+			nested, err := extractValueRefs(v, p, sp, fn)
+			if err != nil {
+				return nil, err
+			}
+			extracted[k] = nested
+			continue
+		case []any:
+			_, err := walkValueRefs(v, p, func(path string, _ *ValueFrom) (any, error) {
+				return nil, fmt.Errorf("%q: %w: references on list items "+
+					"are not supported", path, ErrInvalidValueFrom)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		extracted[k] = values[k]
+	}
+	return extracted, nil
+}
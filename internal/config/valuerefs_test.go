@@ -0,0 +1,167 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValueRefs(t *testing.T) {
+	ctx := context.Background()
+	kube := k8s.NewFakeKube(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "smtp-credentials", Namespace: "mail"},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	}, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxy", Namespace: "openshift-config"},
+		Data:       map[string]string{"http": "http://proxy.example.com:3128"},
+	})
+	allowlist := ValueRefAllowlist{
+		"secret:mail/smtp-credentials",
+		"configMap:openshift-config/*",
+	}
+
+	t.Run("ValueRefAllowlist", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg := &Config{}
+		g.Expect(cfg.ValueRefAllowlist()).To(o.BeNil())
+
+		cfg.Installer.Settings = map[string]any{
+			ValueReferencesSetting: []any{"secret:mail/smtp-credentials"},
+		}
+		g.Expect(cfg.ValueRefAllowlist()).
+			To(o.Equal(ValueRefAllowlist{"secret:mail/smtp-credentials"}))
+
+		for _, invalid := range []any{
+			"secret:mail/smtp-credentials",
+			[]any{"mail/smtp-credentials"},
+			[]any{"pod:mail/smtp-credentials"},
+			[]any{"secret:smtp-credentials"},
+			[]any{"secret:mail/["},
+		} {
+			cfg.Installer.Settings[ValueReferencesSetting] = invalid
+			_, err := cfg.ValueRefAllowlist()
+			g.Expect(err).To(o.MatchError(ErrInvalidConfig), "%v", invalid)
+		}
+	})
+
+	t.Run("ResolveValueRefs", func(t *testing.T) {
+		g := o.NewWithT(t)
+		values := map[string]any{
+			"smtp": map[string]any{
+				"host":     "smtp.example.com",
+				"password": "secretRef:mail/smtp-credentials/password",
+			},
+			"proxies":  []any{"configMapRef:openshift-config/proxy/http"},
+			"replicas": 2,
+		}
+		resolved, err := ResolveValueRefs(ctx, kube, allowlist, nil, values)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(resolved).To(o.Equal(map[string]any{
+			"smtp": map[string]any{
+				"host":     "smtp.example.com",
+				"password": "s3cr3t",
+			},
+			"proxies":  []any{"http://proxy.example.com:3128"},
+			"replicas": 2,
+		}))
+		// The informed values are not modified.
+		g.Expect(values["smtp"].(map[string]any)["password"]).
+			To(o.Equal("secretRef:mail/smtp-credentials/password"))
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := ResolveValueRefs(ctx, kube, allowlist, nil, map[string]any{
+			"token": "secretRef:openshift-config/proxy/http",
+		})
+		g.Expect(err).To(o.MatchError(ErrValueRefDenied))
+		g.Expect(err.Error()).To(o.ContainSubstring(`"token"`))
+
+		_, err = ResolveValueRefs(ctx, kube, nil, nil, map[string]any{
+			"password": "secretRef:mail/smtp-credentials/password",
+		})
+		g.Expect(err).To(o.MatchError(ErrValueRefDenied))
+		g.Expect(err.Error()).To(o.ContainSubstring("by the application"))
+	})
+
+	t.Run("Narrowed", func(t *testing.T) {
+		g := o.NewWithT(t)
+		narrowed := ValueRefAllowlist{"configMap:openshift-config/proxy"}
+		values := map[string]any{
+			"proxy": "configMapRef:openshift-config/proxy/http",
+		}
+		_, err := ResolveValueRefs(ctx, kube, allowlist, narrowed, values)
+		g.Expect(err).To(o.Succeed())
+
+		// The setting can't widen the application allowlist.
+		_, err = ResolveValueRefs(ctx, kube, nil, narrowed, values)
+		g.Expect(err).To(o.MatchError(ErrValueRefDenied))
+		_, err = ResolveValueRefs(ctx, kube, allowlist, narrowed,
+			map[string]any{
+				"password": "secretRef:mail/smtp-credentials/password",
+			})
+		g.Expect(err).To(o.MatchError(ErrValueRefDenied))
+		g.Expect(err.Error()).To(o.ContainSubstring(ValueReferencesSetting))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := ResolveValueRefs(ctx, kube, allowlist, nil, map[string]any{
+			"password": "secretRef:mail/smtp-credentials",
+		})
+		g.Expect(err).To(o.MatchError(ErrInvalidValueFrom))
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := ResolveValueRefs(ctx, kube, allowlist, nil, map[string]any{
+			"password": "secretRef:mail/smtp-credentials/missing",
+		})
+		g.Expect(err).To(o.MatchError(ErrValueFromNotFound))
+	})
+
+	t.Run("ExtractValueRefs", func(t *testing.T) {
+		g := o.NewWithT(t)
+		values := map[string]any{
+			"smtp": map[string]any{
+				"host":     "smtp.example.com",
+				"password": "secretRef:mail/smtp-credentials/password",
+			},
+			"proxy.io": map[string]any{
+				"http": "configMapRef:openshift-config/proxy/http",
+			},
+			"replicas": 2,
+		}
+		extracted, targets, err := ExtractValueRefs(allowlist, nil, values)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(extracted).To(o.Equal(map[string]any{
+			"smtp":     map[string]any{"host": "smtp.example.com"},
+			"proxy.io": map[string]any{},
+			"replicas": 2,
+		}))
+		g.Expect(targets).To(o.HaveLen(2))
+		g.Expect(targets[0].Path).To(o.Equal(`proxy\.io.http`))
+		g.Expect(targets[0].Ref.ConfigMapKeyRef).To(o.Equal(&KeyRef{
+			Namespace: "openshift-config", Name: "proxy", Key: "http",
+		}))
+		g.Expect(targets[1].Path).To(o.Equal("smtp.password"))
+		g.Expect(targets[1].Ref.SecretKeyRef).To(o.Equal(&KeyRef{
+			Namespace: "mail", Name: "smtp-credentials", Key: "password",
+		}))
+		// The informed values are not modified.
+		g.Expect(values["smtp"].(map[string]any)).To(o.HaveKey("password"))
+
+		_, _, err = ExtractValueRefs(nil, nil, values)
+		g.Expect(err).To(o.MatchError(ErrValueRefDenied))
+		// References on list items have no path to target.
+		_, _, err = ExtractValueRefs(allowlist, nil, map[string]any{
+			"proxies": []any{"configMapRef:openshift-config/proxy/http"},
+		})
+		g.Expect(err).To(o.MatchError(ErrInvalidValueFrom))
+	})
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Document returns the configuration, under the application root key, as
+// generic data, suitable for JSON and YAML encoding.
+func (c *Config) Document() (map[string]any, error) {
+	node, err := c.appNode()
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]any{}
+	if err = node.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnmarshalConfig, err)
+	}
+	return doc, nil
+}
+
+// Items returns the configuration document as a single item, or only the named
+// products, in the informed order, as generic data.
+func (c *Config) Items(products []string) ([]any, error) {
+	doc, err := c.Document()
+	if err != nil {
+		return nil, err
+	}
+	if len(products) == 0 {
+		return []any{doc}, nil
+	}
+	entries, _ := doc["products"].([]any)
+	items := make([]any, 0, len(products))
+	for _, name := range products {
+		found := false
+		for _, entry := range entries {
+			if p, ok := entry.(map[string]any); ok && p["name"] == name {
+				items = append(items, p)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("product %q not found", name)
+		}
+	}
+	return items, nil
+}
+
+// PrintTable prints the installer namespace and a table of the products, or only
+// the named products.
+func (c *Config) PrintTable(w io.Writer, products []string) error {
+	selected := Products{}
+	if len(products) == 0 {
+		selected = c.Installer.Products
+	}
+	for _, name := range products {
+		p, err := c.GetProduct(name)
+		if err != nil {
+			return err
+		}
+		selected = append(selected, *p)
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "Namespace:\t%s\n", c.Namespace())
+	fmt.Fprintf(table, "Settings:\t%d\n\n", len(c.Installer.Settings))
+	fmt.Fprintln(table, "NAME\tENABLED\tNAMESPACE\tPROPERTIES")
+	for _, p := range selected {
+		namespace := p.GetNamespace()
+		if namespace == "" {
+			namespace = "-"
+		}
+		fmt.Fprintf(table, "%s\t%t\t%s\t%d\n",
+			p.Name, p.Enabled, namespace, len(p.Properties))
+	}
+	return table.Flush()
+}
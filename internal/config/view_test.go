@@ -0,0 +1,55 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestConfigView(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := NewConfigFromFile(
+		cfs, "config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+
+	t.Run("Document", func(t *testing.T) {
+		g := o.NewWithT(t)
+		doc, err := cfg.Document()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(doc).To(o.HaveKey("settings"))
+		g.Expect(doc).To(o.HaveKey("products"))
+	})
+
+	t.Run("Items", func(t *testing.T) {
+		g := o.NewWithT(t)
+		items, err := cfg.Items(nil)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(items).To(o.HaveLen(1))
+
+		items, err = cfg.Items([]string{"Product B", "Product A"})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(items).To(o.HaveLen(2))
+		g.Expect(items[0]).To(o.HaveKeyWithValue("name", "Product B"))
+		g.Expect(items[1]).To(o.HaveKeyWithValue("name", "Product A"))
+
+		_, err = cfg.Items([]string{"Product Z"})
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("Product Z")))
+	})
+
+	t.Run("PrintTable", func(t *testing.T) {
+		g := o.NewWithT(t)
+		var b bytes.Buffer
+		g.Expect(cfg.PrintTable(&b, []string{"Product A"})).To(o.Succeed())
+		g.Expect(b.String()).To(o.ContainSubstring("NAME"))
+		g.Expect(b.String()).To(o.ContainSubstring("helmet-product-a"))
+		g.Expect(b.String()).NotTo(o.ContainSubstring("Product C"))
+
+		g.Expect(cfg.PrintTable(&b, []string{"Product Z"})).NotTo(o.Succeed())
+	})
+}
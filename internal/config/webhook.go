@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+)
+
+// Deploy events delivered to the webhooks.
+const (
+	// WebhookEventStarted the deployment started.
+	WebhookEventStarted = "deploy.started"
+	// WebhookEventCompleted the deployment completed successfully.
+	WebhookEventCompleted = "deploy.completed"
+	// WebhookEventFailed one or more dependencies failed to deploy.
+	WebhookEventFailed = "deploy.failed"
+)
+
+// WebhookEvents all deploy events, in the order they are fired.
+var WebhookEvents = []string{
+	WebhookEventStarted,
+	WebhookEventCompleted,
+	WebhookEventFailed,
+}
+
+// DefaultWebhookSecretKey default Secret data key holding the HMAC secret.
+const DefaultWebhookSecretKey = "secret"
+
+// WebhookSecretRef references the Kubernetes Secret, in the installer's
+// namespace, holding the HMAC secret used to sign the webhook payload.
+type WebhookSecretRef struct {
+	// Name of the Secret.
+	Name string `yaml:"name"`
+	// Key of the Secret data entry, defaults to "secret".
+	Key string `yaml:"key,omitempty"`
+}
+
+// GetKey returns the Secret data key, or the default when not set.
+func (s *WebhookSecretRef) GetKey() string {
+	if s.Key == "" {
+		return DefaultWebhookSecretKey
+	}
+	return s.Key
+}
+
+// Webhook an HTTP endpoint notified about deploy events.
+type Webhook struct {
+	// Name of the webhook, used on logging.
+	Name string `yaml:"name"`
+	// URL the HTTP(S) endpoint receiving the JSON payload.
+	URL string `yaml:"url"`
+	// Events the webhook subscribes to, all events when empty.
+	Events []string `yaml:"events,omitempty"`
+	// SecretRef the HMAC secret used to sign the payload, unsigned when empty.
+	SecretRef *WebhookSecretRef `yaml:"secretRef,omitempty"`
+}
+
+// Subscribed returns whether the webhook subscribes to the event.
+func (w *Webhook) Subscribed(event string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+// Validate validates the webhook configuration.
+func (w *Webhook) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("%w: webhook: missing name", ErrInvalidConfig)
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+		u.Host == "" {
+		return fmt.Errorf("%w: webhook %q: invalid url %q",
+			ErrInvalidConfig, w.Name, w.URL)
+	}
+	for _, event := range w.Events {
+		if !slices.Contains(WebhookEvents, event) {
+			return fmt.Errorf("%w: webhook %q: unknown event %q, expected %v",
+				ErrInvalidConfig, w.Name, event, WebhookEvents)
+		}
+	}
+	if w.SecretRef != nil && w.SecretRef.Name == "" {
+		return fmt.Errorf("%w: webhook %q: missing secretRef name",
+			ErrInvalidConfig, w.Name)
+	}
+	return nil
+}
@@ -12,4 +12,8 @@ const (
 	// InstructionsFilename is the MCP instructions file (framework convention).
 	// This file provides instructions for the Model Context Protocol server.
 	InstructionsFilename = "instructions.md"
+
+	// PlatformsFilename is the supported platforms file (framework convention).
+	// This file declares the Kubernetes and OpenShift version ranges supported.
+	PlatformsFilename = "platforms.yaml"
 )
@@ -0,0 +1,184 @@
+package deployer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// ErrResourceConflict the rendered manifests would overwrite resources existing
+// on the cluster, not owned by the release.
+var ErrResourceConflict = helmeterrors.New(helmeterrors.ErrConflict,
+	"resources not owned by the release")
+
+// Helm ownership metadata, the resources carrying it are adopted by the release
+// on install or upgrade.
+const (
+	helmManagedByLabel             = "app.kubernetes.io/managed-by"
+	helmManagedBy                  = "Helm"
+	helmReleaseNameAnnotation      = "meta.helm.sh/release-name"
+	helmReleaseNamespaceAnnotation = "meta.helm.sh/release-namespace"
+)
+
+// Conflict a resource on the rendered manifests which already exists on the
+// cluster, not owned by the release.
+type Conflict struct {
+	Resource  string `json:"resource"`            // "Kind/name"
+	Namespace string `json:"namespace,omitempty"` // empty for cluster-scoped
+	Owner     string `json:"owner"`               // current owner description
+
+	ref *corev1.ObjectReference // resource reference, for adoption
+}
+
+// String describes the conflict in a single line.
+func (c Conflict) String() string {
+	resource := c.Resource
+	if c.Namespace != "" {
+		resource = c.Namespace + "/" + resource
+	}
+	return fmt.Sprintf("%s: %s", resource, c.Owner)
+}
+
+// owner describes the owner of the existing resource, empty when it's owned by
+// the release.
+func (h *Helm) owner(obj *unstructured.Unstructured) string {
+	annotations := obj.GetAnnotations()
+	name := annotations[helmReleaseNameAnnotation]
+	namespace := annotations[helmReleaseNamespaceAnnotation]
+	switch {
+	case name == h.chart.Name() && namespace == h.namespace:
+		return ""
+	case name != "":
+		return fmt.Sprintf("owned by the Helm release %s/%s", namespace, name)
+	case obj.GetLabels()[helmManagedByLabel] != "":
+		return fmt.Sprintf("managed by %q, not by Helm",
+			obj.GetLabels()[helmManagedByLabel])
+	default:
+		return "not managed by Helm"
+	}
+}
+
+// resourceClient returns the dynamic client for the resource, nil when the
+// cluster doesn't serve its kind, thus it can't exist.
+func (h *Helm) resourceClient(
+	ref *corev1.ObjectReference,
+) (dynamic.ResourceInterface, error) {
+	client, err := h.kube.GetDynamicClientForObjectRef(ref)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return client, err
+}
+
+// Conflicts renders the chart manifests, hooks excluded, and returns the ones
+// existing on the cluster not owned by the release: created by hand, by another
+// tool or by another release. Helm refuses to install them halfway, unless
+// adopted, see Adopt.
+func (h *Helm) Conflicts(
+	ctx context.Context,
+	vals chartutil.Values,
+) ([]Conflict, error) {
+	rel, err := h.render(ctx, vals, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	conflicts := []Conflict{}
+	dec := yaml.NewDecoder(strings.NewReader(rel.Manifest))
+	for {
+		obj := map[string]any{}
+		err := dec.Decode(&obj)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing rendered manifests: %w", err)
+		}
+		u := &unstructured.Unstructured{Object: obj}
+		if u.GetKind() == "" || u.GetName() == "" {
+			continue
+		}
+		ref := &corev1.ObjectReference{
+			APIVersion: u.GetAPIVersion(),
+			Kind:       u.GetKind(),
+			Namespace:  u.GetNamespace(),
+			Name:       u.GetName(),
+		}
+		if ref.Namespace == "" {
+			ref.Namespace = h.namespace
+		}
+		client, err := h.resourceClient(ref)
+		if err != nil {
+			return nil, err
+		}
+		if client == nil {
+			continue
+		}
+		existing, err := client.Get(ctx, ref.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		owner := h.owner(existing)
+		if owner == "" {
+			continue
+		}
+		conflicts = append(conflicts, Conflict{
+			Resource:  ref.Kind + "/" + ref.Name,
+			Namespace: existing.GetNamespace(),
+			Owner:     owner,
+			ref:       ref,
+		})
+	}
+	return conflicts, nil
+}
+
+// Adopt marks the conflicting resources as owned by the release, with the Helm
+// ownership metadata, thus the next install or upgrade takes them over.
+func (h *Helm) Adopt(ctx context.Context, conflicts []Conflict) error {
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]string{
+				helmManagedByLabel: helmManagedBy,
+			},
+			"annotations": map[string]string{
+				helmReleaseNameAnnotation:      h.chart.Name(),
+				helmReleaseNamespaceAnnotation: h.namespace,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range conflicts {
+		h.logger.Info("Adopting resource", "resource", c.String())
+		client, err := h.resourceClient(c.ref)
+		if err != nil {
+			return err
+		}
+		if client == nil {
+			continue
+		}
+		if _, err = client.Patch(
+			ctx, c.ref.Name, types.MergePatchType, patch, metav1.PatchOptions{},
+		); err != nil {
+			return fmt.Errorf("adopting %s: %w", c.String(), err)
+		}
+	}
+	return nil
+}
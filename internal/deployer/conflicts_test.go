@@ -0,0 +1,62 @@
+package deployer
+
+import (
+	"testing"
+
+	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestHelmOwner(t *testing.T) {
+	h := &Helm{
+		chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "helmet-product-a"}},
+		namespace: "product-a",
+	}
+
+	existing := func(labels, annotations map[string]string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetLabels(labels)
+		u.SetAnnotations(annotations)
+		return u
+	}
+
+	t.Run("owned by the release", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(h.owner(existing(nil, map[string]string{
+			helmReleaseNameAnnotation:      "helmet-product-a",
+			helmReleaseNamespaceAnnotation: "product-a",
+		}))).To(o.BeEmpty())
+	})
+
+	t.Run("owned by another release", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(h.owner(existing(nil, map[string]string{
+			helmReleaseNameAnnotation:      "other",
+			helmReleaseNamespaceAnnotation: "product-a",
+		}))).To(o.Equal("owned by the Helm release product-a/other"))
+	})
+
+	t.Run("managed by another tool", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(h.owner(existing(map[string]string{
+			helmManagedByLabel: "argocd",
+		}, nil))).To(o.Equal(`managed by "argocd", not by Helm`))
+	})
+
+	t.Run("created by hand", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(h.owner(existing(nil, nil))).To(o.Equal("not managed by Helm"))
+	})
+}
+
+func TestConflictString(t *testing.T) {
+	g := o.NewWithT(t)
+	g.Expect(Conflict{
+		Resource: "ClusterRole/test", Owner: "not managed by Helm",
+	}.String()).To(o.Equal("ClusterRole/test: not managed by Helm"))
+	g.Expect(Conflict{
+		Resource: "ConfigMap/test", Namespace: "product-a",
+		Owner: "not managed by Helm",
+	}.String()).To(o.Equal("product-a/ConfigMap/test: not managed by Helm"))
+}
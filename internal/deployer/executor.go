@@ -0,0 +1,79 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/monitor"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// Deploy executors, the engine deploying the dependency releases.
+const (
+	// ExecutorHelm deploys with the Helm SDK, in-process, the default.
+	ExecutorHelm = "helm"
+	// ExecutorHelmBinary deploys with the external "helm" binary, for the
+	// environments mandating the Helm CLI.
+	ExecutorHelmBinary = "helm-binary"
+	// ExecutorFlux emits the Flux HelmRelease manifests instead of deploying,
+	// for the GitOps controller to reconcile them.
+	ExecutorFlux = "flux"
+)
+
+// Executors the deploy executors supported.
+var Executors = []string{ExecutorHelm, ExecutorHelmBinary, ExecutorFlux}
+
+// ErrInvalidExecutor the deploy executor informed is not supported.
+var ErrInvalidExecutor = helmeterrors.New(helmeterrors.ErrInvalidUsage,
+	"invalid executor")
+
+// Executor deploys the Helm chart release of a single dependency, the rendering,
+// conflicts and health checks remain on the Helm SDK regardless.
+type Executor interface {
+	// Deploy installs, or upgrades, the release with the values.
+	Deploy(ctx context.Context, vals chartutil.Values) error
+	// VerifyWithRetry runs the release tests, retrying on failure.
+	VerifyWithRetry() error
+	// VisitReleaseResources collects the release resources on the monitor.
+	VisitReleaseResources(ctx context.Context, m monitor.Interface) error
+}
+
+var _ Executor = &Helm{}
+
+// ExecutorOptions the deploy executor selection and its settings.
+type ExecutorOptions struct {
+	Name       string // executor name, ExecutorHelm by default
+	HelmBinary string // path to the "helm" binary, on the PATH by default
+	OutputDir  string // directory receiving the emitted manifests
+	FluxSource string // Flux chart source, "<kind>/<namespace>/<name>"
+	ChartsDir  string // charts directory on the Flux GitRepository source
+}
+
+// ValidateExecutor asserts the executor name is supported, empty stands for the
+// default executor.
+func ValidateExecutor(name string) error {
+	if name != "" && !slices.Contains(Executors, name) {
+		return fmt.Errorf("%w: %q, expected one of %s", ErrInvalidExecutor,
+			name, strings.Join(Executors, ", "))
+	}
+	return nil
+}
+
+// NewExecutor instantiates the executor deploying the release of the Helm
+// client, which keeps rendering the chart and inspecting the cluster.
+func NewExecutor(h *Helm, opts ExecutorOptions) (Executor, error) {
+	switch opts.Name {
+	case "", ExecutorHelm:
+		return h, nil
+	case ExecutorHelmBinary:
+		return NewHelmBinary(h, opts.HelmBinary), nil
+	case ExecutorFlux:
+		return NewFlux(h, opts.FluxSource, opts.ChartsDir, opts.OutputDir)
+	default:
+		return nil, ValidateExecutor(opts.Name)
+	}
+}
@@ -0,0 +1,199 @@
+package deployer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/flags"
+
+	o "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestNewExecutor(t *testing.T) {
+	g := o.NewWithT(t)
+	h := &Helm{}
+
+	e, err := NewExecutor(h, ExecutorOptions{})
+	g.Expect(err).To(o.Succeed())
+	g.Expect(e).To(o.BeIdenticalTo(h))
+
+	e, err = NewExecutor(h, ExecutorOptions{Name: ExecutorHelmBinary})
+	g.Expect(err).To(o.Succeed())
+	g.Expect(e).To(o.BeAssignableToTypeOf(&HelmBinary{}))
+	g.Expect(e.(*HelmBinary).binary).To(o.Equal(DefaultHelmBinary))
+
+	_, err = NewExecutor(h, ExecutorOptions{Name: ExecutorFlux})
+	g.Expect(err).To(o.MatchError(ErrInvalidExecutor))
+	e, err = NewExecutor(h, ExecutorOptions{
+		Name:       ExecutorFlux,
+		FluxSource: "GitRepository/flux-system/helmet-ex",
+	})
+	g.Expect(err).To(o.Succeed())
+	g.Expect(e.(*Flux).chartsDir).To(o.Equal(DefaultChartsDir))
+
+	_, err = NewExecutor(h, ExecutorOptions{Name: "argo"})
+	g.Expect(err).To(o.MatchError(ErrInvalidExecutor))
+	g.Expect(ValidateExecutor("")).To(o.Succeed())
+}
+
+func TestParseFluxSource(t *testing.T) {
+	g := o.NewWithT(t)
+
+	s, err := ParseFluxSource("HelmRepository/flux-system/charts")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(*s).To(o.Equal(FluxSource{
+		Kind: "HelmRepository", Namespace: "flux-system", Name: "charts",
+	}))
+
+	for _, invalid := range []string{
+		"", "GitRepository/helmet-ex", "GitRepository//helmet-ex",
+		"OCIRepository/flux-system/helmet-ex",
+	} {
+		_, err = ParseFluxSource(invalid)
+		g.Expect(err).To(o.MatchError(ErrInvalidExecutor), invalid)
+	}
+}
+
+func TestFlux(t *testing.T) {
+	f := flags.NewFlags()
+	f.Timeout = 10 * time.Minute
+	h := &Helm{
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		flags:  f,
+		chart: &chart.Chart{Metadata: &chart.Metadata{
+			Name: "helmet-product-a", Version: "0.1.0",
+		}},
+		namespace: "product-a",
+		hooks:     HookOptions{Disabled: true},
+		ownership: Ownership{
+			Labels:      map[string]string{"app.kubernetes.io/managed-by": "helmet-ex"},
+			Annotations: map[string]string{"product": "Product A"},
+		},
+	}
+	vals := chartutil.Values{"replicas": 2}
+
+	type helmRelease struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+		Metadata   struct {
+			Name      string            `yaml:"name"`
+			Namespace string            `yaml:"namespace"`
+			Labels    map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Spec struct {
+			ReleaseName     string `yaml:"releaseName"`
+			TargetNamespace string `yaml:"targetNamespace"`
+			Timeout         string `yaml:"timeout"`
+			Chart           struct {
+				Spec struct {
+					Chart     string            `yaml:"chart"`
+					Version   string            `yaml:"version"`
+					SourceRef map[string]string `yaml:"sourceRef"`
+				} `yaml:"spec"`
+			} `yaml:"chart"`
+			Install struct {
+				DisableHooks bool `yaml:"disableHooks"`
+			} `yaml:"install"`
+			CommonMetadata struct {
+				Annotations map[string]string `yaml:"annotations"`
+			} `yaml:"commonMetadata"`
+			DependsOn  []map[string]string `yaml:"dependsOn"`
+			ValuesFrom []map[string]string `yaml:"valuesFrom"`
+			Values     map[string]any      `yaml:"values"`
+		} `yaml:"spec"`
+	}
+
+	t.Run("GitRepository", func(t *testing.T) {
+		g := o.NewWithT(t)
+		dir := t.TempDir()
+		flux, err := NewFlux(h, "GitRepository/flux-system/helmet-ex", "", dir)
+		g.Expect(err).To(o.Succeed())
+		flux.SetDependsOn([]string{"helmet-foundation"})
+		g.Expect(flux.Deploy(context.Background(), vals)).To(o.Succeed())
+
+		manifest, err := os.ReadFile(filepath.Join(dir, "helmet-product-a.yaml"))
+		g.Expect(err).To(o.Succeed())
+		var hr helmRelease
+		g.Expect(yaml.Unmarshal(manifest, &hr)).To(o.Succeed())
+		g.Expect(hr.APIVersion).To(o.Equal(FluxAPIVersion))
+		g.Expect(hr.Kind).To(o.Equal("HelmRelease"))
+		g.Expect(hr.Metadata.Name).To(o.Equal("helmet-product-a"))
+		g.Expect(hr.Metadata.Namespace).To(o.Equal("flux-system"))
+		g.Expect(hr.Metadata.Labels).To(o.Equal(h.ownership.Labels))
+		g.Expect(hr.Spec.ReleaseName).To(o.Equal("helmet-product-a"))
+		g.Expect(hr.Spec.TargetNamespace).To(o.Equal("product-a"))
+		g.Expect(hr.Spec.Timeout).To(o.Equal("10m0s"))
+		g.Expect(hr.Spec.Chart.Spec.Chart).To(o.Equal("charts/helmet-product-a"))
+		g.Expect(hr.Spec.Chart.Spec.Version).To(o.BeEmpty())
+		g.Expect(hr.Spec.Chart.Spec.SourceRef).To(o.Equal(map[string]string{
+			"kind": "GitRepository", "namespace": "flux-system", "name": "helmet-ex",
+		}))
+		g.Expect(hr.Spec.Install.DisableHooks).To(o.BeTrue())
+		g.Expect(hr.Spec.CommonMetadata.Annotations).
+			To(o.Equal(h.ownership.Annotations))
+		g.Expect(hr.Spec.DependsOn).To(o.Equal(
+			[]map[string]string{{"name": "helmet-foundation"}}))
+		g.Expect(hr.Spec.ValuesFrom).To(o.BeEmpty())
+		g.Expect(hr.Spec.Values).To(o.Equal(map[string]any{"replicas": 2}))
+
+		info, err := os.Stat(filepath.Join(dir, "helmet-product-a.yaml"))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(info.Mode().Perm()).To(o.Equal(os.FileMode(0o600)))
+
+		g.Expect(flux.VerifyWithRetry()).To(o.Succeed())
+		g.Expect(flux.VisitReleaseResources(context.Background(), nil)).
+			To(o.Succeed())
+	})
+
+	t.Run("HelmRepository", func(t *testing.T) {
+		g := o.NewWithT(t)
+		flux, err := NewFlux(h, "HelmRepository/flux-system/charts", "", "")
+		g.Expect(err).To(o.Succeed())
+		manifest, err := flux.HelmRelease(vals)
+		g.Expect(err).To(o.Succeed())
+		var hr helmRelease
+		g.Expect(yaml.Unmarshal(manifest, &hr)).To(o.Succeed())
+		g.Expect(hr.Spec.Chart.Spec.Chart).To(o.Equal("helmet-product-a"))
+		g.Expect(hr.Spec.Chart.Spec.Version).To(o.Equal("0.1.0"))
+		g.Expect(hr.Spec.DependsOn).To(o.BeEmpty())
+	})
+
+	t.Run("ValuesFrom", func(t *testing.T) {
+		g := o.NewWithT(t)
+		flux, err := NewFlux(h, "GitRepository/flux-system/helmet-ex", "", "")
+		g.Expect(err).To(o.Succeed())
+		flux.SetValuesFrom([]ValuesFrom{{
+			Kind:       "Secret",
+			Namespace:  "flux-system",
+			Name:       "product-a",
+			Key:        "token",
+			TargetPath: `auth.token`,
+		}})
+		manifest, err := flux.HelmRelease(vals)
+		g.Expect(err).To(o.Succeed())
+		var hr helmRelease
+		g.Expect(yaml.Unmarshal(manifest, &hr)).To(o.Succeed())
+		g.Expect(hr.Spec.ValuesFrom).To(o.Equal([]map[string]string{{
+			"kind":       "Secret",
+			"name":       "product-a",
+			"valuesKey":  "token",
+			"targetPath": "auth.token",
+		}}))
+
+		// Flux only reads the objects on the HelmRelease namespace.
+		flux.SetValuesFrom([]ValuesFrom{{
+			Kind: "ConfigMap", Namespace: "product-a", Name: "settings",
+			Key: "url", TargetPath: "url",
+		}})
+		_, err = flux.HelmRelease(vals)
+		g.Expect(err).To(o.MatchError(ErrInvalidExecutor))
+	})
+}
@@ -0,0 +1,244 @@
+package deployer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/monitor"
+
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+const (
+	// FluxAPIVersion the API version of the HelmRelease emitted.
+	FluxAPIVersion = "helm.toolkit.fluxcd.io/v2"
+	// FluxInterval the HelmRelease reconciliation interval.
+	FluxInterval = "10m"
+	// DefaultChartsDir the charts directory on the GitRepository source, as
+	// laid out on the installer tarball.
+	DefaultChartsDir = "charts"
+)
+
+// FluxSourceKinds the Flux source kinds serving the charts.
+var FluxSourceKinds = []string{"GitRepository", "HelmRepository", "Bucket"}
+
+// FluxSource the Flux source serving the charts, the GitRepository of the
+// installer, or a HelmRepository where they are published.
+type FluxSource struct {
+	Kind      string // source kind
+	Namespace string // source namespace, where the HelmReleases are created
+	Name      string // source name
+}
+
+// ParseFluxSource parses the Flux source informed as "<kind>/<namespace>/<name>".
+func ParseFluxSource(s string) (*FluxSource, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 3 || slices.Contains(parts, "") {
+		return nil, fmt.Errorf("%w: flux source %q, expected "+
+			"<kind>/<namespace>/<name>", ErrInvalidExecutor, s)
+	}
+	if !slices.Contains(FluxSourceKinds, parts[0]) {
+		return nil, fmt.Errorf("%w: flux source kind %q, expected one of %s",
+			ErrInvalidExecutor, parts[0], strings.Join(FluxSourceKinds, ", "))
+	}
+	return &FluxSource{Kind: parts[0], Namespace: parts[1], Name: parts[2]}, nil
+}
+
+// ValuesFrom a release value the GitOps controller reads from a Secret, or
+// ConfigMap, key, instead of the manifest carrying it.
+type ValuesFrom struct {
+	Kind       string // "Secret" or "ConfigMap"
+	Namespace  string // object namespace, the HelmRelease's
+	Name       string // object name
+	Key        string // object data key
+	TargetPath string // value path, as Helm "--set" keys
+}
+
+// Flux emits the release as a Flux HelmRelease, instead of deploying it, for
+// the GitOps controller to reconcile. The cluster isn't touched, the manifests
+// are written to the output directory, committed to the GitOps repository
+// afterwards.
+type Flux struct {
+	*Helm
+
+	source     *FluxSource  // charts source
+	chartsDir  string       // charts directory on the GitRepository source
+	outputDir  string       // directory receiving the HelmRelease manifests
+	dependsOn  []string     // releases installed beforehand
+	valuesFrom []ValuesFrom // values read by the controller
+}
+
+var _ Executor = &Flux{}
+
+// SetDependsOn sets the releases the HelmRelease depends on, reconciled first.
+func (f *Flux) SetDependsOn(releases []string) {
+	f.dependsOn = releases
+}
+
+// SetValuesFrom sets the values the GitOps controller reads from Secrets and
+// ConfigMaps, on the HelmRelease namespace, the manifest doesn't carry them.
+func (f *Flux) SetValuesFrom(valuesFrom []ValuesFrom) {
+	f.valuesFrom = valuesFrom
+}
+
+// valuesFromSpec returns the HelmRelease "valuesFrom", the objects must be on
+// the HelmRelease namespace, the only one Flux reads them from.
+func (f *Flux) valuesFromSpec() ([]any, error) {
+	spec := make([]any, 0, len(f.valuesFrom))
+	for _, v := range f.valuesFrom {
+		if v.Namespace != f.source.Namespace {
+			return nil, fmt.Errorf("%w: %s %s/%s: the Flux HelmRelease only "+
+				"reads values from its own namespace, %q", ErrInvalidExecutor,
+				v.Kind, v.Namespace, v.Name, f.source.Namespace)
+		}
+		spec = append(spec, map[string]any{
+			"kind":       v.Kind,
+			"name":       v.Name,
+			"valuesKey":  v.Key,
+			"targetPath": v.TargetPath,
+		})
+	}
+	return spec, nil
+}
+
+// chartSpec returns the HelmRelease chart template, the chart is looked up by
+// path on the GitRepository and Bucket sources, and by name and version on the
+// HelmRepository.
+func (f *Flux) chartSpec() map[string]any {
+	spec := map[string]any{
+		"chart": path.Join(f.chartsDir, f.chart.Name()),
+		"sourceRef": map[string]any{
+			"kind":      f.source.Kind,
+			"name":      f.source.Name,
+			"namespace": f.source.Namespace,
+		},
+	}
+	if f.source.Kind == "HelmRepository" {
+		spec["chart"] = f.chart.Name()
+		spec["version"] = f.chart.Metadata.Version
+	}
+	return spec
+}
+
+// HelmRelease returns the HelmRelease manifest of the release, with the
+// values, hooks settings and ownership of the Helm client.
+func (f *Flux) HelmRelease(vals chartutil.Values) ([]byte, error) {
+	valuesFrom, err := f.valuesFromSpec()
+	if err != nil {
+		return nil, err
+	}
+	name := f.chart.Name()
+	spec := map[string]any{
+		"releaseName":      name,
+		"targetNamespace":  f.namespace,
+		"storageNamespace": f.namespace,
+		"interval":         FluxInterval,
+		"timeout":          f.timeout().String(),
+		"chart":            map[string]any{"spec": f.chartSpec()},
+		"install": map[string]any{
+			"createNamespace": true,
+			"disableHooks":    f.hooks.Disabled,
+		},
+		"upgrade": map[string]any{
+			"disableHooks": f.hooks.Disabled,
+		},
+		"values": map[string]any(vals),
+	}
+	metadata := map[string]any{
+		"name":      name,
+		"namespace": f.source.Namespace,
+	}
+	if len(f.ownership.Labels) > 0 || len(f.ownership.Annotations) > 0 {
+		common := map[string]any{}
+		if len(f.ownership.Labels) > 0 {
+			metadata["labels"] = f.ownership.Labels
+			common["labels"] = f.ownership.Labels
+		}
+		if len(f.ownership.Annotations) > 0 {
+			common["annotations"] = f.ownership.Annotations
+		}
+		spec["commonMetadata"] = common
+	}
+	if len(valuesFrom) > 0 {
+		spec["valuesFrom"] = valuesFrom
+	}
+	if len(f.dependsOn) > 0 {
+		dependsOn := make([]any, 0, len(f.dependsOn))
+		for _, release := range f.dependsOn {
+			dependsOn = append(dependsOn, map[string]any{"name": release})
+		}
+		spec["dependsOn"] = dependsOn
+	}
+
+	var b bytes.Buffer
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(map[string]any{
+		"apiVersion": FluxAPIVersion,
+		"kind":       "HelmRelease",
+		"metadata":   metadata,
+		"spec":       spec,
+	}); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Deploy writes the HelmRelease manifest to the output directory, named after
+// the release, readable only by the owner. On dry-run, or without output
+// directory, it's printed instead.
+func (f *Flux) Deploy(_ context.Context, vals chartutil.Values) error {
+	manifest, err := f.HelmRelease(vals)
+	if err != nil {
+		return err
+	}
+	if f.flags.DryRun || f.outputDir == "" {
+		fmt.Printf("---\n%s", manifest)
+		return nil
+	}
+	if err = os.MkdirAll(f.outputDir, 0o755); err != nil {
+		return err
+	}
+	manifestPath := filepath.Join(f.outputDir, f.chart.Name()+".yaml")
+	f.logger.Info("Writing the Flux HelmRelease...", "path", manifestPath)
+	return os.WriteFile(manifestPath, manifest, 0o600)
+}
+
+// VerifyWithRetry the release tests are up to the GitOps controller.
+func (f *Flux) VerifyWithRetry() error {
+	return nil
+}
+
+// VisitReleaseResources the release resources are reconciled by the GitOps
+// controller, nothing is collected.
+func (f *Flux) VisitReleaseResources(context.Context, monitor.Interface) error {
+	return nil
+}
+
+// NewFlux instantiates the executor emitting the release of the Helm client as a
+// Flux HelmRelease, referencing the charts on the informed source.
+func NewFlux(h *Helm, source, chartsDir, outputDir string) (*Flux, error) {
+	s, err := ParseFluxSource(source)
+	if err != nil {
+		return nil, err
+	}
+	if chartsDir == "" {
+		chartsDir = DefaultChartsDir
+	}
+	return &Flux{
+		Helm:      h,
+		source:    s,
+		chartsDir: chartsDir,
+		outputDir: outputDir,
+	}, nil
+}
@@ -0,0 +1,165 @@
+package deployer
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// ErrForeignRelease a release with the expected name already exists, but it
+// wasn't deployed by the installer.
+var ErrForeignRelease = helmeterrors.New(helmeterrors.ErrConflict,
+	"release not deployed by the installer")
+
+// ForeignRelease an existing Helm release, named after the dependency chart, not
+// deployed by the installer.
+type ForeignRelease struct {
+	Name      string // release name
+	Namespace string // release namespace
+	Chart     string // "name-version" of the release chart
+	Revision  int    // latest release revision
+	Owner     string // current owner description
+}
+
+// String describes the release in a single line.
+func (f ForeignRelease) String() string {
+	return fmt.Sprintf("%s/%s (%s, revision %d): %s",
+		f.Namespace, f.Name, f.Chart, f.Revision, f.Owner)
+}
+
+// managedBy returns the application name on the ownership labels, empty when
+// the ownership is not set.
+func (h *Helm) managedBy() string {
+	return h.ownership.Labels[annotations.Application]
+}
+
+// labelsOwner returns the application name on the labels, the standard
+// managed-by label is considered for the releases labeled before the
+// application label, other than "Helm" itself.
+func labelsOwner(labels map[string]string) string {
+	if app := labels[annotations.Application]; app != "" {
+		return app
+	}
+	if app := labels[helmManagedByLabel]; app != helmManagedBy {
+		return app
+	}
+	return ""
+}
+
+// manifestManagedBy checks whether any resource on the release manifest carries
+// the ownership labels of the application, the releases deployed before the
+// installer started labeling them. The manifest keeps the post-rendered labels,
+// Helm only replaces the managed-by label on the cluster resources.
+func manifestManagedBy(manifest, managedBy string) bool {
+	for _, doc := range releaseutil.SplitManifests(manifest) {
+		var obj struct {
+			Metadata struct {
+				Labels map[string]string `yaml:"labels"`
+			} `yaml:"metadata"`
+		}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			continue
+		}
+		if labelsOwner(obj.Metadata.Labels) == managedBy {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseOwner describes the owner of the existing release, empty when it was
+// deployed by the installer. Without ownership every release is considered
+// deployed by the installer.
+func (h *Helm) releaseOwner(rel *release.Release) string {
+	managedBy := h.managedBy()
+	if managedBy == "" {
+		return ""
+	}
+	owner := labelsOwner(rel.Labels)
+	if owner == managedBy || manifestManagedBy(rel.Manifest, managedBy) {
+		return ""
+	}
+	if owner != "" {
+		return fmt.Sprintf("managed by %q", owner)
+	}
+	if h.installerChart(rel) {
+		return ""
+	}
+	return fmt.Sprintf("not deployed by %q", managedBy)
+}
+
+// chartAnnotations returns the installer annotations on the chart metadata.
+func chartAnnotations(c *chart.Chart) map[string]string {
+	found := map[string]string{}
+	if c == nil || c.Metadata == nil {
+		return found
+	}
+	for k, v := range c.Metadata.Annotations {
+		if strings.HasPrefix(k, annotations.RepoURI+"/") {
+			found[k] = v
+		}
+	}
+	return found
+}
+
+// installerChart checks whether the unlabeled release deploys the dependency
+// chart as the installer does, the releases deployed before the installer
+// labeled them: the chart carries the same installer annotations as the
+// dependency chart, the chart version may differ.
+func (h *Helm) installerChart(rel *release.Release) bool {
+	if rel.Chart == nil || rel.Chart.Metadata == nil ||
+		rel.Chart.Metadata.Name != h.chart.Name() {
+		return false
+	}
+	expected := chartAnnotations(h.chart)
+	return len(expected) > 0 && maps.Equal(chartAnnotations(rel.Chart), expected)
+}
+
+// ForeignRelease looks up the existing release named after the chart, returning
+// it when not deployed by the installer, nil when absent or owned. The chart
+// identity is verified, a release of another chart can't be adopted.
+func (h *Helm) ForeignRelease() (*ForeignRelease, error) {
+	c := action.NewGet(h.actionCfg)
+	c.Version = 0
+
+	h.logger.Debug("Checking the existing release ownership")
+	rel, err := c.Run(h.chart.Name())
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	owner := h.releaseOwner(rel)
+	if owner == "" {
+		return nil, nil
+	}
+	foreign := &ForeignRelease{
+		Name:      rel.Name,
+		Namespace: rel.Namespace,
+		Revision:  rel.Version,
+		Owner:     owner,
+	}
+	if rel.Chart == nil || rel.Chart.Metadata == nil {
+		return nil, fmt.Errorf("%w: %s, the release chart is unknown",
+			ErrForeignRelease, foreign)
+	}
+	foreign.Chart = fmt.Sprintf("%s-%s",
+		rel.Chart.Metadata.Name, rel.Chart.Metadata.Version)
+	if rel.Chart.Metadata.Name != h.chart.Name() {
+		return nil, fmt.Errorf("%w: %s, deploys chart %q instead of %q",
+			ErrForeignRelease, foreign, rel.Chart.Metadata.Name, h.chart.Name())
+	}
+	return foreign, nil
+}
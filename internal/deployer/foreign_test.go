@@ -0,0 +1,85 @@
+package deployer
+
+import (
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+
+	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+)
+
+func TestHelmReleaseOwner(t *testing.T) {
+	h := &Helm{
+		chart: &chart.Chart{Metadata: &chart.Metadata{Name: "helmet-product-a"}},
+		ownership: Ownership{Labels: map[string]string{
+			annotations.Application: "helmet-ex",
+		}},
+	}
+
+	t.Run("labeled by the installer", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(h.releaseOwner(&release.Release{
+			Labels: map[string]string{annotations.Application: "helmet-ex"},
+		})).To(o.BeEmpty())
+		// Labeled before the application label.
+		g.Expect(h.releaseOwner(&release.Release{
+			Labels: map[string]string{helmManagedByLabel: "helmet-ex"},
+		})).To(o.BeEmpty())
+	})
+
+	t.Run("resources labeled by the installer", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(h.releaseOwner(&release.Release{
+			Manifest: `---
+# Source: helmet-product-a/templates/cm.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: product-a
+  labels:
+    app.kubernetes.io/managed-by: helmet-ex
+`,
+		})).To(o.BeEmpty())
+	})
+
+	t.Run("deployed by hand", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(h.releaseOwner(&release.Release{
+			Manifest: `---
+# Source: helmet-product-a/templates/cm.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: product-a
+  labels:
+    app.kubernetes.io/managed-by: Helm
+`,
+		})).To(o.Equal(`not deployed by "helmet-ex"`))
+	})
+
+	t.Run("managed by another application", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(h.releaseOwner(&release.Release{
+			Labels: map[string]string{annotations.Application: "other"},
+		})).To(o.Equal(`managed by "other"`))
+	})
+
+	t.Run("without ownership", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect((&Helm{}).releaseOwner(&release.Release{})).To(o.BeEmpty())
+	})
+}
+
+func TestForeignReleaseString(t *testing.T) {
+	g := o.NewWithT(t)
+	g.Expect(ForeignRelease{
+		Name:      "helmet-product-a",
+		Namespace: "product-a",
+		Chart:     "helmet-product-a-1.0.0",
+		Revision:  2,
+		Owner:     `not deployed by "helmet-ex"`,
+	}.String()).To(o.Equal(`product-a/helmet-product-a ` +
+		`(helmet-product-a-1.0.0, revision 2): not deployed by "helmet-ex"`))
+}
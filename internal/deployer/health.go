@@ -0,0 +1,267 @@
+package deployer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// workloadKinds the kinds checked for presence on the release health, the ones
+// running the product pods.
+var workloadKinds = []string{"Deployment", "StatefulSet", "DaemonSet"}
+
+// parseManifest decodes the multi-document manifest, indexing the resources by
+// "Kind/namespace/name". Resources without namespace are on the release one.
+func (h *Helm) parseManifest(
+	manifest string,
+) (map[string]*unstructured.Unstructured, error) {
+	resources := map[string]*unstructured.Unstructured{}
+	dec := yaml.NewDecoder(strings.NewReader(manifest))
+	for {
+		obj := map[string]any{}
+		err := dec.Decode(&obj)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing manifests: %w", err)
+		}
+		u := &unstructured.Unstructured{Object: obj}
+		if u.GetKind() == "" || u.GetName() == "" {
+			continue
+		}
+		if u.GetNamespace() == "" {
+			u.SetNamespace(h.namespace)
+		}
+		resources[u.GetKind()+"/"+u.GetNamespace()+"/"+u.GetName()] = u
+	}
+	return resources, nil
+}
+
+// capabilities returns the cluster capabilities, the Kubernetes version and the
+// API versions, as Helm discovers them on install and upgrade.
+func (h *Helm) capabilities() (*chartutil.Capabilities, error) {
+	if h.actionCfg.Capabilities != nil {
+		return h.actionCfg.Capabilities, nil
+	}
+	dc, err := h.actionCfg.RESTClientGetter.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	dc.Invalidate()
+	version, err := dc.ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("discovering the cluster version: %w", err)
+	}
+	apiVersions, err := action.GetVersionSet(dc)
+	if err != nil {
+		return nil, err
+	}
+	return &chartutil.Capabilities{
+		KubeVersion: chartutil.KubeVersion{
+			Version: version.GitVersion,
+			Major:   version.Major,
+			Minor:   version.Minor,
+		},
+		APIVersions: apiVersions,
+		HelmVersion: chartutil.DefaultCapabilities.HelmVersion,
+	}, nil
+}
+
+// driftIgnored returns whether the label, or annotation, is left out of the
+// drift comparison: the ones the installer and Helm add to the resources, they
+// change across installer versions without the chart changing.
+func driftIgnored(key string) bool {
+	return key == helmManagedByLabel ||
+		strings.HasPrefix(key, annotations.RepoURI+"/")
+}
+
+// withoutIgnored returns the metadata without the ignored keys, nil when none
+// is left, resources without labels compare equal to the ones only labeled by
+// the installer.
+func withoutIgnored(metadata map[string]string) map[string]string {
+	maps.DeleteFunc(metadata, func(k, _ string) bool { return driftIgnored(k) })
+	if len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
+
+// withoutOwnership returns the resources without the ignored labels and
+// annotations, see driftIgnored.
+func withoutOwnership(
+	resources map[string]*unstructured.Unstructured,
+) map[string]*unstructured.Unstructured {
+	stripped := make(map[string]*unstructured.Unstructured, len(resources))
+	for key, u := range resources {
+		u = u.DeepCopy()
+		u.SetLabels(withoutIgnored(u.GetLabels()))
+		u.SetAnnotations(withoutIgnored(u.GetAnnotations()))
+		stripped[key] = u
+	}
+	return stripped
+}
+
+// Health inspects the deployed release, returning the reasons it's unhealthy:
+// missing, not on "deployed" status, drifted from the manifests rendered with
+// the informed values, or with workloads missing from the cluster. Healthy
+// releases have no reasons.
+//
+// The manifests are rendered with the cluster capabilities, as an upgrade when
+// the release has more than one revision, and compared without the ownership
+// labels and annotations. Templates generating values, like "randAlphaNum",
+// "genCA" or "now", or reading the cluster with "lookup", render differently
+// every time, thus their releases are always reported as drifted.
+func (h *Helm) Health(ctx context.Context, vals chartutil.Values) ([]string, error) {
+	c := action.NewGet(h.actionCfg)
+	c.Version = 0
+
+	rel, err := c.Run(h.chart.Name())
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return []string{"release not installed"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	reasons := []string{}
+	if rel.Info != nil && rel.Info.Status != release.StatusDeployed {
+		reasons = append(reasons,
+			fmt.Sprintf("release status %q", rel.Info.Status))
+	}
+
+	deployed, err := h.parseManifest(rel.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	caps, err := h.capabilities()
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := h.render(ctx, vals, caps, rel.Version > 1)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := h.parseManifest(rendered.Manifest)
+	if err != nil {
+		return nil, err
+	}
+	if !reflect.DeepEqual(withoutOwnership(deployed), withoutOwnership(expected)) {
+		reasons = append(reasons, "drifted from the rendered manifests")
+	}
+
+	missing, err := h.missingWorkloads(ctx, deployed)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range missing {
+		reasons = append(reasons, fmt.Sprintf("workload %s missing", m))
+	}
+	return reasons, nil
+}
+
+// missingWorkloads returns the release workloads absent from the cluster, as
+// "Kind/name", sorted.
+func (h *Helm) missingWorkloads(
+	ctx context.Context,
+	resources map[string]*unstructured.Unstructured,
+) ([]string, error) {
+	missing := []string{}
+	for _, u := range resources {
+		if !slices.Contains(workloadKinds, u.GetKind()) {
+			continue
+		}
+		client, err := h.resourceClient(&corev1.ObjectReference{
+			APIVersion: u.GetAPIVersion(),
+			Kind:       u.GetKind(),
+			Namespace:  u.GetNamespace(),
+			Name:       u.GetName(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if client != nil {
+			_, err = client.Get(ctx, u.GetName(), metav1.GetOptions{})
+			if err == nil {
+				continue
+			}
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+		}
+		missing = append(missing, u.GetKind()+"/"+u.GetName())
+	}
+	slices.Sort(missing)
+	return missing, nil
+}
+
+// Workload a release workload, running the product pods.
+type Workload struct {
+	Kind      string            // workload kind
+	Namespace string            // workload namespace
+	Name      string            // workload name
+	Selector  map[string]string // pods label selector
+}
+
+// String describes the workload as "Kind/name".
+func (w Workload) String() string {
+	return w.Kind + "/" + w.Name
+}
+
+// Workloads returns the workloads of the deployed release, see workloads.
+func (h *Helm) Workloads() ([]Workload, error) {
+	manifest, err := h.GetManifest()
+	if err != nil {
+		return nil, err
+	}
+	return h.workloads(manifest)
+}
+
+// workloads returns the workloads on the manifest, with their pods label
+// selector, sorted by kind and name. Workloads selecting the pods by
+// expressions only are skipped.
+func (h *Helm) workloads(manifest string) ([]Workload, error) {
+	resources, err := h.parseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	workloads := []Workload{}
+	for _, u := range resources {
+		if !slices.Contains(workloadKinds, u.GetKind()) {
+			continue
+		}
+		selector, _, err := unstructured.NestedStringMap(
+			u.Object, "spec", "selector", "matchLabels")
+		if err != nil || len(selector) == 0 {
+			h.logger.Debug("Skipping workload without label selector",
+				"workload", u.GetKind()+"/"+u.GetName())
+			continue
+		}
+		workloads = append(workloads, Workload{
+			Kind:      u.GetKind(),
+			Namespace: u.GetNamespace(),
+			Name:      u.GetName(),
+			Selector:  selector,
+		})
+	}
+	slices.SortFunc(workloads, func(a, b Workload) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return workloads, nil
+}
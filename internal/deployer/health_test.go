@@ -0,0 +1,91 @@
+package deployer
+
+import (
+	"log/slog"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestHelmParseManifest(t *testing.T) {
+	g := o.NewWithT(t)
+	h := &Helm{namespace: "product-a"}
+
+	resources, err := h.parseManifest(`---
+# Source: product-a/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: product-a
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: product-a
+  namespace: other
+---
+# Empty document.
+`)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(resources).To(o.HaveLen(2))
+	g.Expect(resources).To(o.HaveKey("Deployment/product-a/product-a"))
+	g.Expect(resources).To(o.HaveKey("ClusterRole/other/product-a"))
+
+	_, err = h.parseManifest("kind: [")
+	g.Expect(err).ToNot(o.Succeed())
+}
+
+func TestHelmWorkloads(t *testing.T) {
+	g := o.NewWithT(t)
+	h := &Helm{namespace: "product-a", logger: slog.Default()}
+
+	workloads, err := h.workloads(`---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: product-a-db
+spec:
+  selector:
+    matchLabels:
+      app: product-a-db
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: product-a
+spec:
+  selector:
+    matchLabels:
+      app: product-a
+---
+apiVersion: apps/v1
+kind: DaemonSet
+metadata:
+  name: product-a-agent
+spec:
+  selector:
+    matchExpressions:
+      - key: app
+        operator: Exists
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: product-a
+spec:
+  selector:
+    app: product-a
+`)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(workloads).To(o.Equal([]Workload{{
+		Kind:      "Deployment",
+		Namespace: "product-a",
+		Name:      "product-a",
+		Selector:  map[string]string{"app": "product-a"},
+	}, {
+		Kind:      "StatefulSet",
+		Namespace: "product-a",
+		Name:      "product-a-db",
+		Selector:  map[string]string{"app": "product-a-db"},
+	}}))
+}
@@ -6,47 +6,74 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"time"
 
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 	"github.com/redhat-appstudio/helmet/internal/flags"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 	"github.com/redhat-appstudio/helmet/internal/monitor"
 	"github.com/redhat-appstudio/helmet/internal/printer"
 
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/postrender"
 	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/cli-runtime/pkg/resource"
 )
 
 // Helm represents the Helm support for the installer. It's responsible for
 // running the Helm related actions.
 type Helm struct {
-	logger *slog.Logger // application logger
-	flags  *flags.Flags // global flags
+	logger *slog.Logger  // application logger
+	flags  *flags.Flags  // global flags
+	kube   k8s.Interface // kubernetes client
 
 	chart     *chart.Chart          // helm chart instance
 	namespace string                // kubernetes namespace
 	actionCfg *action.Configuration // helm action configuration
+	hooks     HookOptions           // helm hooks options
+	ownership Ownership             // labels and annotations to apply
+	mapper    meta.RESTMapper       // kinds scope, for namespaced only releases
+	printed   chartutil.Values      // values printed instead of the release's
 
 	release *release.Release // helm chart release
 }
 
+// HookOptions represents the Helm hooks settings for a single chart.
+type HookOptions struct {
+	Disabled       bool          // skip running hooks, "--no-hooks"
+	Timeout        time.Duration // raises the release timeout for slow hooks
+	DeletePolicies []string      // default deletion policies for hooks
+}
+
 // ErrInstallFailed when the Helm chart installation fails.
-var ErrInstallFailed = errors.New("install failed")
+var ErrInstallFailed = helmeterrors.New(helmeterrors.ErrDeployFailed,
+	"install failed")
 
 // ErrUpgradeFailed when the Helm chart upgrade fails.
-var ErrUpgradeFailed = errors.New("upgrade failed")
+var ErrUpgradeFailed = helmeterrors.New(helmeterrors.ErrDeployFailed,
+	"upgrade failed")
+
+// ErrVerifyFailed when the Helm chart tests fail.
+var ErrVerifyFailed = helmeterrors.New(helmeterrors.ErrDeployFailed,
+	"verify failed")
 
 // printRelease prints the Helm release information.
 func (h *Helm) printRelease(rel *release.Release) {
 	// In verbose mode, print the configuration values using key-value pairs.
 	if !h.flags.DryRun && h.flags.Verbose {
-		printer.ValuesPrinter("Config", rel.Config)
+		config := rel.Config
+		if h.printed != nil {
+			config = h.printed
+		}
+		printer.ValuesPrinter("Config", config)
 	}
 	printer.HelmReleasePrinter(rel)
 	// Print extended release information only in dry-run or verbose mode. This
@@ -58,6 +85,98 @@ func (h *Helm) printRelease(rel *release.Release) {
 	printer.HelmReleaseNotesPrinter(rel)
 }
 
+// timeout returns the Helm client timeout, covering the resources and hooks of
+// the release. The hooks timeout only raises the global timeout flag, it never
+// shortens the release timeout.
+func (h *Helm) timeout() time.Duration {
+	return max(h.hooks.Timeout, h.flags.Timeout)
+}
+
+// declaresDeletePolicy checks whether the hook manifest declares its own deletion
+// policy. Helm fills "before-hook-creation" on the hooks without one, thus the
+// release hook policies can't tell them apart. Unparsable manifests are left
+// to Helm.
+func declaresDeletePolicy(manifest string) bool {
+	var obj struct {
+		Metadata struct {
+			Annotations map[string]string `yaml:"annotations"`
+		} `yaml:"metadata"`
+	}
+	if err := yaml.Unmarshal([]byte(manifest), &obj); err != nil {
+		return true
+	}
+	_, ok := obj.Metadata.Annotations[release.HookDeleteAnnotation]
+	return ok
+}
+
+// deleteHooksByPolicy deletes the hook resources, which don't declare their own
+// deletion policy, according to the default policies configured. Helm already
+// handles "before-hook-creation" as its own default.
+func (h *Helm) deleteHooksByPolicy(rel *release.Release) {
+	if rel == nil || h.flags.DryRun || len(h.hooks.DeletePolicies) == 0 {
+		return
+	}
+	for _, hook := range rel.Hooks {
+		if declaresDeletePolicy(hook.Manifest) {
+			continue
+		}
+		var policy string
+		switch hook.LastRun.Phase {
+		case release.HookPhaseSucceeded:
+			policy = string(release.HookSucceeded)
+		case release.HookPhaseFailed:
+			policy = string(release.HookFailed)
+		default:
+			continue
+		}
+		if !slices.Contains(h.hooks.DeletePolicies, policy) {
+			continue
+		}
+		logger := h.logger.With("hook", hook.Name, "policy", policy)
+		logger.Debug("Deleting hook resources by policy")
+		resources, err := h.actionCfg.KubeClient.Build(
+			bytes.NewBufferString(hook.Manifest), false)
+		if err != nil {
+			logger.Warn("Unable to build hook resources", "err", err)
+			continue
+		}
+		if _, errs := h.actionCfg.KubeClient.Delete(resources); len(errs) > 0 {
+			logger.Warn("Unable to delete hook resources", "errs", errs)
+		}
+	}
+}
+
+// SetHookOptions sets the Helm hooks options for the chart.
+func (h *Helm) SetHookOptions(hooks HookOptions) {
+	h.hooks = hooks
+}
+
+// SetPrintedValues sets the values printed in verbose mode instead of the release
+// values, keeping the value references unresolved.
+func (h *Helm) SetPrintedValues(vals chartutil.Values) {
+	h.printed = vals
+}
+
+// SetOwnership sets the labels and annotations applied to every resource of the
+// release, hooks are not included.
+func (h *Helm) SetOwnership(ownership Ownership) {
+	h.ownership = ownership
+}
+
+// postRenderer returns the post-renderer decorating the release resources, and
+// dropping the cluster-scoped ones for namespaced only releases, or nil when
+// there's nothing to apply.
+func (h *Helm) postRenderer() postrender.PostRenderer {
+	var pr postrender.PostRenderer
+	if len(h.ownership.Labels) > 0 || len(h.ownership.Annotations) > 0 {
+		pr = &ownershipPostRenderer{ownership: h.ownership}
+	}
+	if h.mapper != nil {
+		pr = &namespacedPostRenderer{mapper: h.mapper, next: pr}
+	}
+	return pr
+}
+
 // helmInstall equivalent to "helm install" command.
 func (h *Helm) helmInstall(
 	ctx context.Context,
@@ -67,7 +186,11 @@ func (h *Helm) helmInstall(
 	c.GenerateName = false
 	c.Namespace = h.namespace
 	c.ReleaseName = h.chart.Name()
-	c.Timeout = h.flags.Timeout
+	c.Timeout = h.timeout()
+	c.DisableHooks = h.hooks.Disabled
+	c.PostRenderer = h.postRenderer()
+	c.SkipCRDs = h.mapper != nil
+	c.Labels = h.ownership.Labels
 
 	c.DryRun = h.flags.DryRun
 	c.ClientOnly = h.flags.DryRun
@@ -76,8 +199,9 @@ func (h *Helm) helmInstall(
 	}
 
 	rel, err := c.RunWithContext(ctx, h.chart, vals)
+	h.deleteHooksByPolicy(rel)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrInstallFailed, err.Error())
+		return nil, fmt.Errorf("%w: %w", ErrInstallFailed, err)
 	}
 	return rel, nil
 }
@@ -89,7 +213,11 @@ func (h *Helm) helmUpgrade(
 ) (*release.Release, error) {
 	c := action.NewUpgrade(h.actionCfg)
 	c.Namespace = h.namespace
-	c.Timeout = h.flags.Timeout
+	c.Timeout = h.timeout()
+	c.DisableHooks = h.hooks.Disabled
+	c.PostRenderer = h.postRenderer()
+	c.SkipCRDs = h.mapper != nil
+	c.Labels = h.ownership.Labels
 
 	c.DryRun = h.flags.DryRun
 	if h.flags.DryRun {
@@ -97,21 +225,79 @@ func (h *Helm) helmUpgrade(
 	}
 
 	rel, err := c.RunWithContext(ctx, h.chart.Name(), h.chart, vals)
+	h.deleteHooksByPolicy(rel)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrUpgradeFailed, err.Error())
+		return nil, fmt.Errorf("%w: %w", ErrUpgradeFailed, err)
 	}
 	return rel, err
 }
 
-// Deploy deploys the Helm chart (Dependency) on the cluster. It checks if the
-// release is already installed in order to use the proper helm-client (action).
-func (h *Helm) Deploy(ctx context.Context, vals chartutil.Values) error {
+// render renders the release client-side, without touching it. The informed
+// capabilities, when not nil, replace the Helm defaults, and isUpgrade renders
+// as an upgrade of the release.
+func (h *Helm) render(
+	ctx context.Context,
+	vals chartutil.Values,
+	caps *chartutil.Capabilities,
+	isUpgrade bool,
+) (*release.Release, error) {
+	// The client-only install replaces the Kubernetes client and the release
+	// storage, thus it acts on a copy of the action configuration.
+	actionCfg := *h.actionCfg
+	c := action.NewInstall(&actionCfg)
+	c.Namespace = h.namespace
+	c.ReleaseName = h.chart.Name()
+	c.DisableHooks = h.hooks.Disabled
+	c.PostRenderer = h.postRenderer()
+	c.DryRun = true
+	c.ClientOnly = true
+	c.Replace = true
+	c.IsUpgrade = isUpgrade
+	if caps != nil {
+		c.KubeVersion = &caps.KubeVersion
+		c.APIVersions = caps.APIVersions
+	}
+
+	rel, err := c.RunWithContext(ctx, h.chart, vals)
+	if err != nil {
+		return nil, fmt.Errorf("rendering manifests: %w", err)
+	}
+	return rel, nil
+}
+
+// Render renders the chart manifests, hooks included, client-side and without
+// touching the release, equivalent to "helm template".
+func (h *Helm) Render(
+	ctx context.Context,
+	vals chartutil.Values,
+) (string, error) {
+	rel, err := h.render(ctx, vals, nil, false)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	b.WriteString(rel.Manifest)
+	for _, hook := range rel.Hooks {
+		fmt.Fprintf(&b, "\n---\n# Source: %s\n%s", hook.Path, hook.Manifest)
+	}
+	return b.String(), nil
+}
+
+// releaseExists checks whether the release is already installed on the cluster.
+func (h *Helm) releaseExists() bool {
 	c := action.NewHistory(h.actionCfg)
 	c.Max = 1
 
 	h.logger.Debug("Checking if release exists on the cluster")
+	_, err := c.Run(h.chart.Name())
+	return !errors.Is(err, driver.ErrReleaseNotFound)
+}
+
+// Deploy deploys the Helm chart (Dependency) on the cluster. It checks if the
+// release is already installed in order to use the proper helm-client (action).
+func (h *Helm) Deploy(ctx context.Context, vals chartutil.Values) error {
 	var err error
-	if _, err = c.Run(h.chart.Name()); errors.Is(err, driver.ErrReleaseNotFound) {
+	if !h.releaseExists() {
 		h.logger.Info("Installing Helm Chart...")
 		h.release, err = h.helmInstall(ctx, vals)
 	} else {
@@ -139,19 +325,19 @@ func (h *Helm) Verify() error {
 
 	_, err := c.Run(h.chart.Name())
 	if err != nil {
-		return err
+		return fmt.Errorf("%w: %w", ErrVerifyFailed, err)
 	}
 	h.logger.Info("Release verified!")
 	return nil
 }
 
-// VerifyWithRetry attempts to verify the Helm deployment multiple times with a
-// delay between retries.
-func (h *Helm) VerifyWithRetry() error {
+// verifyWithRetry attempts the verification multiple times with a delay between
+// retries.
+func verifyWithRetry(verify func() error) error {
 	var err error
 	retries := 3
 	for i := 1; i <= retries; i++ {
-		err = h.Verify()
+		err = verify()
 		if err == nil || i == retries {
 			break
 		}
@@ -160,6 +346,12 @@ func (h *Helm) VerifyWithRetry() error {
 	return err
 }
 
+// VerifyWithRetry attempts to verify the Helm deployment multiple times with a
+// delay between retries.
+func (h *Helm) VerifyWithRetry() error {
+	return verifyWithRetry(h.Verify)
+}
+
 // VisitReleaseResources collects the resources created by the Helm chart release.
 func (h *Helm) VisitReleaseResources(
 	ctx context.Context,
@@ -191,6 +383,45 @@ func (h *Helm) GetNotes() (string, error) {
 	return res.Info.Notes, nil
 }
 
+// DeployedRelease retrieves the latest release (version 0) of the Helm chart,
+// nil when the chart is not installed.
+func (h *Helm) DeployedRelease() (*release.Release, error) {
+	c := action.NewGet(h.actionCfg)
+	c.Version = 0
+
+	res, err := c.Run(h.chart.Name())
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil, nil
+	}
+	return res, err
+}
+
+// DeployedVersion retrieves the latest release (version 0) of the Helm chart,
+// returning its chart version. Empty when the chart is not installed.
+func (h *Helm) DeployedVersion() (string, error) {
+	res, err := h.DeployedRelease()
+	if err != nil || res == nil {
+		return "", err
+	}
+	if res.Chart == nil || res.Chart.Metadata == nil {
+		return "", nil
+	}
+	return res.Chart.Metadata.Version, nil
+}
+
+// GetManifest retrieves the latest release (version 0) of the Helm chart,
+// returning the rendered manifest.
+func (h *Helm) GetManifest() (string, error) {
+	c := action.NewGet(h.actionCfg)
+	c.Version = 0
+
+	res, err := c.Run(h.chart.Name())
+	if err != nil {
+		return "", err
+	}
+	return res.Manifest, nil
+}
+
 // NewHelm creates a new Helm instance, setting up the Helm action configuration
 // to be used on subsequent interactions. The Helm instance is bound to a single
 // Helm Chart.
@@ -226,6 +457,7 @@ func NewHelm(
 			"namespace", namespace,
 		),
 		flags:     f,
+		kube:      kube,
 		chart:     chart,
 		namespace: namespace,
 		actionCfg: actionCfg,
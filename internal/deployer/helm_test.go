@@ -0,0 +1,347 @@
+package deployer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+
+	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/kube"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+	"sigs.k8s.io/yaml"
+)
+
+// recordingKubeClient a Helm kube client building the manifest resources, as
+// absent from the cluster, and recording the resources Helm applies.
+type recordingKubeClient struct {
+	kubefake.PrintingKubeClient
+
+	namespace string                       // default resources namespace
+	applied   []*unstructured.Unstructured // resources created or updated
+	deleted   []string                     // names of the resources deleted
+}
+
+var _ kube.Interface = &recordingKubeClient{}
+
+// Build decodes the manifest resources on the default namespace, the REST
+// client answers not found.
+func (r *recordingKubeClient) Build(
+	manifest io.Reader,
+	_ bool,
+) (kube.ResourceList, error) {
+	payload, err := io.ReadAll(manifest)
+	if err != nil {
+		return nil, err
+	}
+	client := &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Client: restfake.CreateHTTPClient(
+			func(*http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader("")),
+				}, nil
+			}),
+	}
+	resources := kube.ResourceList{}
+	for _, doc := range releaseutil.SplitManifests(string(payload)) {
+		u := &unstructured.Unstructured{}
+		if err = yaml.Unmarshal([]byte(doc), &u.Object); err != nil {
+			return nil, err
+		}
+		if len(u.Object) == 0 {
+			continue
+		}
+		if u.GetNamespace() == "" {
+			u.SetNamespace(r.namespace)
+		}
+		gvk := u.GroupVersionKind()
+		resources.Append(&resource.Info{
+			Name:      u.GetName(),
+			Namespace: u.GetNamespace(),
+			Object:    u,
+			Client:    client,
+			Mapping: &meta.RESTMapping{
+				Resource: schema.GroupVersionResource{
+					Group:    gvk.Group,
+					Version:  gvk.Version,
+					Resource: strings.ToLower(gvk.Kind) + "s",
+				},
+				GroupVersionKind: gvk,
+				Scope:            meta.RESTScopeNamespace,
+			},
+		})
+	}
+	return resources, nil
+}
+
+// record keeps the resources applied.
+func (r *recordingKubeClient) record(resources kube.ResourceList) {
+	for _, info := range resources {
+		r.applied = append(r.applied, info.Object.(*unstructured.Unstructured))
+	}
+}
+
+// Create records the resources created.
+func (r *recordingKubeClient) Create(
+	resources kube.ResourceList,
+) (*kube.Result, error) {
+	r.record(resources)
+	return &kube.Result{Created: resources}, nil
+}
+
+// Update records the target resources.
+func (r *recordingKubeClient) Update(
+	_, target kube.ResourceList,
+	_ bool,
+) (*kube.Result, error) {
+	r.record(target)
+	return &kube.Result{Updated: target}, nil
+}
+
+// Delete records the names of the resources deleted.
+func (r *recordingKubeClient) Delete(
+	resources kube.ResourceList,
+) (*kube.Result, []error) {
+	for _, info := range resources {
+		r.deleted = append(r.deleted, info.Name)
+	}
+	return &kube.Result{Deleted: resources}, nil
+}
+
+// newTestHelm returns a Helm client for the chart templates, backed by the
+// recording kube client and an in-memory release storage.
+func newTestHelm(templates map[string]string) (*Helm, *recordingKubeClient) {
+	c := &chart.Chart{Metadata: &chart.Metadata{
+		APIVersion: chart.APIVersionV2,
+		Name:       "helmet-product-a",
+		Version:    "1.0.0",
+	}}
+	for name, data := range templates {
+		c.Templates = append(c.Templates,
+			&chart.File{Name: "templates/" + name, Data: []byte(data)})
+	}
+	kubeClient := &recordingKubeClient{
+		PrintingKubeClient: kubefake.PrintingKubeClient{Out: io.Discard},
+		namespace:          "product-a",
+	}
+	return &Helm{
+		logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		flags:     &flags.Flags{Timeout: time.Minute},
+		chart:     c,
+		namespace: kubeClient.namespace,
+		actionCfg: &action.Configuration{
+			Releases:     storage.Init(driver.NewMemory()),
+			KubeClient:   kubeClient,
+			Capabilities: chartutil.DefaultCapabilities,
+			Log:          func(string, ...interface{}) {},
+		},
+	}, kubeClient
+}
+
+func TestHelmOwnership(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	h, kubeClient := newTestHelm(map[string]string{"cm.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: product-a
+  labels:
+    app.kubernetes.io/managed-by: chart
+data:
+  key: value
+`})
+	h.SetOwnership(Ownership{
+		Labels: map[string]string{
+			annotations.Application: "helmet-ex",
+			annotations.Chart:       "helmet-product-a",
+		},
+		Annotations: map[string]string{
+			annotations.ProductName: "Product A",
+		},
+	})
+
+	rel, err := h.helmInstall(ctx, chartutil.Values{})
+	g.Expect(err).To(o.Succeed())
+	_, err = h.helmUpgrade(ctx, chartutil.Values{})
+	g.Expect(err).To(o.Succeed())
+
+	// Helm forces its own managed-by label on install and upgrade, the
+	// application label is kept.
+	g.Expect(kubeClient.applied).To(o.HaveLen(2))
+	for _, u := range kubeClient.applied {
+		g.Expect(u.GetLabels()).To(o.Equal(map[string]string{
+			helmManagedByLabel:      helmManagedBy,
+			annotations.Application: "helmet-ex",
+			annotations.Chart:       "helmet-product-a",
+		}))
+		g.Expect(u.GetAnnotations()).
+			To(o.HaveKeyWithValue(annotations.ProductName, "Product A"))
+	}
+
+	g.Expect(rel.Labels).
+		To(o.HaveKeyWithValue(annotations.Application, "helmet-ex"))
+	g.Expect(h.releaseOwner(rel)).To(o.BeEmpty())
+	foreign, err := h.ForeignRelease()
+	g.Expect(err).To(o.Succeed())
+	g.Expect(foreign).To(o.BeNil())
+}
+
+func TestHelmForeignRelease(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	h, _ := newTestHelm(map[string]string{"cm.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: product-a
+`})
+	h.chart.Metadata.Annotations = map[string]string{
+		annotations.ProductName: "Product A",
+	}
+
+	// Deployed by the installer before it labeled the releases.
+	_, err := h.helmInstall(ctx, chartutil.Values{})
+	g.Expect(err).To(o.Succeed())
+	h.SetOwnership(Ownership{Labels: map[string]string{
+		annotations.Application: "helmet-ex",
+	}})
+	upgrade := func(productName string) {
+		h.chart = &chart.Chart{Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       "helmet-product-a",
+			Version:    "1.1.0",
+			Annotations: map[string]string{
+				annotations.ProductName: productName,
+			},
+		}}
+	}
+	upgrade("Product A")
+	foreign, err := h.ForeignRelease()
+	g.Expect(err).To(o.Succeed())
+	g.Expect(foreign).To(o.BeNil())
+
+	// The chart annotations differ, the release wasn't deployed from the
+	// dependency chart.
+	upgrade("Product B")
+	foreign, err = h.ForeignRelease()
+	g.Expect(err).To(o.Succeed())
+	g.Expect(foreign).NotTo(o.BeNil())
+	g.Expect(foreign.Chart).To(o.Equal("helmet-product-a-1.0.0"))
+	g.Expect(foreign.Owner).To(o.Equal(`not deployed by "helmet-ex"`))
+}
+
+func TestHelmDeleteHooksByPolicy(t *testing.T) {
+	g := o.NewWithT(t)
+
+	h, kubeClient := newTestHelm(map[string]string{
+		"setup.yaml": `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: setup
+  annotations:
+    helm.sh/hook: post-install
+`,
+		"migrate.yaml": `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    helm.sh/hook: post-install
+    helm.sh/hook-delete-policy: before-hook-creation
+`,
+	})
+	rel, err := h.helmInstall(context.Background(), chartutil.Values{})
+	g.Expect(err).To(o.Succeed())
+	// Helm fills its default policy on the hooks without their own.
+	for _, hook := range rel.Hooks {
+		g.Expect(hook.DeletePolicies).To(o.HaveLen(1))
+	}
+
+	kubeClient.deleted = nil
+	h.SetHookOptions(HookOptions{DeletePolicies: []string{"hook-succeeded"}})
+	h.deleteHooksByPolicy(rel)
+	g.Expect(kubeClient.deleted).To(o.Equal([]string{"setup"}))
+}
+
+func TestHelmHealth(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	h, _ := newTestHelm(map[string]string{"cm.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: product-a
+data:
+  kubeVersion: {{ .Capabilities.KubeVersion.Version }}
+  monitoring: {{ .Capabilities.APIVersions.Has "monitoring.coreos.com/v1" | quote }}
+  upgrade: {{ .Release.IsUpgrade | quote }}
+  replicas: {{ .Values.replicas | quote }}
+`})
+	caps := chartutil.DefaultCapabilities.Copy()
+	caps.KubeVersion = chartutil.KubeVersion{
+		Version: "v1.33.0",
+		Major:   "1",
+		Minor:   "33",
+	}
+	caps.APIVersions = append(caps.APIVersions, "monitoring.coreos.com/v1")
+	h.actionCfg.Capabilities = caps
+	vals := chartutil.Values{"replicas": 1}
+
+	_, err := h.helmInstall(ctx, vals)
+	g.Expect(err).To(o.Succeed())
+	_, err = h.helmUpgrade(ctx, vals)
+	g.Expect(err).To(o.Succeed())
+
+	// Rendered as the cluster upgrade, a newer installer labeling the resources
+	// doesn't drift the release.
+	h.SetOwnership(Ownership{Labels: map[string]string{
+		annotations.Application: "helmet-ex",
+	}})
+	reasons, err := h.Health(ctx, vals)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(reasons).To(o.BeEmpty())
+
+	reasons, err = h.Health(ctx, chartutil.Values{"replicas": 2})
+	g.Expect(err).To(o.Succeed())
+	g.Expect(reasons).
+		To(o.Equal([]string{"drifted from the rendered manifests"}))
+}
+
+func TestHelmTimeout(t *testing.T) {
+	g := o.NewWithT(t)
+
+	h, _ := newTestHelm(nil)
+	g.Expect(h.timeout()).To(o.Equal(time.Minute))
+	// The hooks timeout raises the release timeout, never shortens it.
+	h.SetHookOptions(HookOptions{Timeout: 30 * time.Minute})
+	g.Expect(h.timeout()).To(o.Equal(30 * time.Minute))
+	h.SetHookOptions(HookOptions{Timeout: 10 * time.Second})
+	g.Expect(h.timeout()).To(o.Equal(time.Minute))
+}
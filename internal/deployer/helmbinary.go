@@ -0,0 +1,153 @@
+package deployer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// DefaultHelmBinary the "helm" binary looked up on the PATH.
+const DefaultHelmBinary = "helm"
+
+// HelmBinary deploys the release with the external "helm" binary, for the
+// environments mandating the Helm CLI. The Helm client keeps rendering and
+// inspecting the release, the binary shares its release storage. The ownership
+// labels are applied by the Helm SDK post-renderer, thus they aren't applied
+// to the resources by the binary, only to the release.
+type HelmBinary struct {
+	*Helm
+
+	binary string // path to the helm binary
+}
+
+var _ Executor = &HelmBinary{}
+
+// command runs the helm binary against the release namespace, on the cluster
+// targeted by the global flags, returning its combined output.
+func (b *HelmBinary) command(ctx context.Context, args ...string) ([]byte, error) {
+	args = append(args, "--namespace", b.namespace)
+	if b.flags.KubeConfigPath != "" {
+		args = append(args, "--kubeconfig", b.flags.KubeConfigPath)
+	}
+	if b.flags.KubeContext != "" {
+		args = append(args, "--kube-context", b.flags.KubeContext)
+	}
+	b.logger.Debug("Running the helm binary", "binary", b.binary, "args", args)
+	out, err := exec.CommandContext(ctx, b.binary, args...).CombinedOutput()
+	if err != nil {
+		return out, fmt.Errorf("%s %s: %w: %s",
+			b.binary, args[0], err, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+// Deploy equivalent to "helm upgrade --install", the chart and the values are
+// written to a temporary directory, removed afterwards.
+func (b *HelmBinary) Deploy(ctx context.Context, vals chartutil.Values) error {
+	dir, err := os.MkdirTemp("", "helmet-"+b.chart.Name()+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err = chartutil.SaveDir(b.chart, dir); err != nil {
+		return fmt.Errorf("saving chart %q: %w", b.chart.Name(), err)
+	}
+	valuesYAML, err := vals.YAML()
+	if err != nil {
+		return err
+	}
+	valuesPath := filepath.Join(dir, "values.yaml")
+	if err = os.WriteFile(valuesPath, []byte(valuesYAML), 0o600); err != nil {
+		return err
+	}
+
+	args := []string{
+		"upgrade", b.chart.Name(), filepath.Join(dir, b.chart.Name()),
+		"--install",
+		"--values", valuesPath,
+		"--timeout", b.timeout().String(),
+	}
+	if b.hooks.Disabled {
+		args = append(args, "--no-hooks")
+	}
+	if len(b.ownership.Labels) > 0 {
+		labels := make([]string, 0, len(b.ownership.Labels))
+		for k, v := range b.ownership.Labels {
+			labels = append(labels, k+"="+v)
+		}
+		slices.Sort(labels)
+		args = append(args, "--labels", strings.Join(labels, ","))
+	}
+	if b.flags.DryRun {
+		args = append(args, "--dry-run=server")
+	}
+	failure := ErrUpgradeFailed
+	if b.releaseExists() {
+		b.logger.Info("Upgrading Helm Chart with the helm binary...")
+	} else {
+		b.logger.Info("Installing Helm Chart with the helm binary...")
+		failure = ErrInstallFailed
+	}
+	out, err := b.command(ctx, args...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", failure, err)
+	}
+	if b.flags.DryRun || b.flags.Verbose {
+		fmt.Print(string(out))
+	}
+	if b.flags.DryRun {
+		return nil
+	}
+
+	// Loading the release stored by the binary, its resources are monitored
+	// afterwards.
+	c := action.NewGet(b.actionCfg)
+	c.Version = 0
+	if b.release, err = c.Run(b.chart.Name()); err != nil {
+		return err
+	}
+	b.printRelease(b.release)
+	return nil
+}
+
+// Verify equivalent to "helm test", using the helm binary.
+func (b *HelmBinary) Verify() error {
+	if b.flags.DryRun {
+		b.logger.Debug("Dry-run mode enabled, skipping verification")
+		return nil
+	}
+
+	b.logger.Debug("Verifying the release with the helm binary...")
+	if _, err := b.command(
+		context.Background(),
+		"test", b.chart.Name(),
+		"--timeout", b.timeout().String(),
+	); err != nil {
+		return fmt.Errorf("%w: %w", ErrVerifyFailed, err)
+	}
+	b.logger.Info("Release verified!")
+	return nil
+}
+
+// VerifyWithRetry attempts to verify the release with the helm binary multiple
+// times with a delay between retries.
+func (b *HelmBinary) VerifyWithRetry() error {
+	return verifyWithRetry(b.Verify)
+}
+
+// NewHelmBinary instantiates the executor deploying the release of the Helm
+// client with the informed helm binary, DefaultHelmBinary when empty.
+func NewHelmBinary(h *Helm, binary string) *HelmBinary {
+	if binary == "" {
+		binary = DefaultHelmBinary
+	}
+	return &HelmBinary{Helm: h, binary: binary}
+}
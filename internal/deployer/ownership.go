@@ -0,0 +1,94 @@
+package deployer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/postrender"
+)
+
+// Ownership represents the labels and annotations applied to every resource
+// created by the deploy engine.
+type Ownership struct {
+	Labels      map[string]string // ownership labels
+	Annotations map[string]string // ownership annotations
+}
+
+// ownershipPostRenderer decorates the rendered manifests with the ownership
+// labels and annotations.
+type ownershipPostRenderer struct {
+	ownership Ownership // labels and annotations to apply
+}
+
+var _ postrender.PostRenderer = &ownershipPostRenderer{}
+
+// mappingValue returns the value node for the key in the mapping node, creating
+// an empty mapping when the key is absent or null.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != key {
+			continue
+		}
+		value := node.Content[i+1]
+		if value.Kind != yaml.MappingNode {
+			*value = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		return value
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value,
+	)
+	return value
+}
+
+// setEntries sets the entries in the mapping node, in a stable order.
+func setEntries(node *yaml.Node, entries map[string]string) {
+	for _, k := range slices.Sorted(maps.Keys(entries)) {
+		value := mappingValue(node, k)
+		*value = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: entries[k]}
+	}
+}
+
+// Run decorates each resource in the rendered manifests.
+func (o *ownershipPostRenderer) Run(rendered *bytes.Buffer) (*bytes.Buffer, error) {
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+
+	dec := yaml.NewDecoder(rendered)
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing rendered manifests: %w", err)
+		}
+		// Skipping empty documents, and documents which aren't resources.
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+		metadata := mappingValue(doc.Content[0], "metadata")
+		if len(o.ownership.Labels) > 0 {
+			setEntries(mappingValue(metadata, "labels"), o.ownership.Labels)
+		}
+		if len(o.ownership.Annotations) > 0 {
+			setEntries(mappingValue(metadata, "annotations"), o.ownership.Annotations)
+		}
+		if err = enc.Encode(&doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
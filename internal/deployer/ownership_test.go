@@ -0,0 +1,69 @@
+package deployer
+
+import (
+	"bytes"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+func TestOwnershipPostRenderer(t *testing.T) {
+	g := o.NewWithT(t)
+
+	manifests := bytes.NewBufferString(`---
+# Source: test/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  labels:
+    app: test
+data:
+  key: value
+---
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: test
+  annotations:
+spec:
+  ports:
+    - port: 80
+`)
+	r := &ownershipPostRenderer{ownership: Ownership{
+		Labels:      map[string]string{"app.kubernetes.io/managed-by": "helmet-ex"},
+		Annotations: map[string]string{"example.com/product": "Product A"},
+	}}
+	out, err := r.Run(manifests)
+	g.Expect(err).To(o.Succeed())
+
+	type resource struct {
+		Kind     string `yaml:"kind"`
+		Metadata struct {
+			Labels      map[string]string `yaml:"labels"`
+			Annotations map[string]string `yaml:"annotations"`
+		} `yaml:"metadata"`
+	}
+	var resources []resource
+	dec := yaml.NewDecoder(out)
+	for {
+		var r resource
+		if dec.Decode(&r) != nil {
+			break
+		}
+		resources = append(resources, r)
+	}
+
+	g.Expect(resources).To(o.HaveLen(2))
+	g.Expect(resources[0].Kind).To(o.Equal("ConfigMap"))
+	g.Expect(resources[0].Metadata.Labels).To(o.Equal(map[string]string{
+		"app":                          "test",
+		"app.kubernetes.io/managed-by": "helmet-ex",
+	}))
+	g.Expect(resources[1].Kind).To(o.Equal("Service"))
+	g.Expect(resources[1].Metadata.Annotations).To(o.Equal(map[string]string{
+		"example.com/product": "Product A",
+	}))
+}
@@ -0,0 +1,100 @@
+package deployer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/postrender"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// namespacedPostRenderer drops the cluster-scoped resources from the rendered
+// manifests, used on rehearsals to keep the cluster-wide state untouched.
+// Kinds unknown to the cluster are kept, the installation reports them.
+type namespacedPostRenderer struct {
+	mapper meta.RESTMapper         // cluster kinds scope
+	next   postrender.PostRenderer // renderer applied afterwards, optional
+}
+
+var _ postrender.PostRenderer = &namespacedPostRenderer{}
+
+// clusterScoped returns whether the resource document is cluster-scoped.
+func (n *namespacedPostRenderer) clusterScoped(doc *yaml.Node) bool {
+	var typeMeta struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+	}
+	if err := doc.Decode(&typeMeta); err != nil || typeMeta.Kind == "" {
+		return false
+	}
+	gv, err := schema.ParseGroupVersion(typeMeta.APIVersion)
+	if err != nil {
+		return false
+	}
+	mapping, err := n.mapper.RESTMapping(
+		schema.GroupKind{Group: gv.Group, Kind: typeMeta.Kind}, gv.Version)
+	if err != nil {
+		return false
+	}
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot
+}
+
+// Run removes the cluster-scoped resources, then applies the next renderer.
+func (n *namespacedPostRenderer) Run(rendered *bytes.Buffer) (*bytes.Buffer, error) {
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+
+	dec := yaml.NewDecoder(rendered)
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing rendered manifests: %w", err)
+		}
+		if len(doc.Content) == 0 || n.clusterScoped(&doc) {
+			continue
+		}
+		if err = enc.Encode(&doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	if n.next == nil {
+		return &out, nil
+	}
+	return n.next.Run(&out)
+}
+
+// SetNamespacedOnly restricts the release to namespaced resources, the
+// cluster-scoped ones, CRDs included, are not installed. The mapper tells the
+// kinds scope.
+func (h *Helm) SetNamespacedOnly(mapper meta.RESTMapper) {
+	h.mapper = mapper
+}
+
+// Uninstall removes the release and its resources, equivalent to "helm
+// uninstall". Releases not installed are ignored.
+func (h *Helm) Uninstall(_ context.Context) error {
+	c := action.NewUninstall(h.actionCfg)
+	c.Timeout = h.timeout()
+	c.DisableHooks = h.hooks.Disabled
+	c.Wait = true
+	_, err := c.Run(h.chart.Name())
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return nil
+	}
+	return err
+}
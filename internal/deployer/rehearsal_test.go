@@ -0,0 +1,73 @@
+package deployer
+
+import (
+	"bytes"
+	"testing"
+
+	o "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestNamespacedPostRenderer(t *testing.T) {
+	g := o.NewWithT(t)
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+		meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Namespace"},
+		meta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{
+		Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "ClusterRole",
+	}, meta.RESTScopeRoot)
+
+	manifests := bytes.NewBufferString(`---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: test
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: test
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: test
+`)
+	r := &namespacedPostRenderer{
+		mapper: mapper,
+		next: &ownershipPostRenderer{ownership: Ownership{
+			Labels: map[string]string{"app.kubernetes.io/managed-by": "helmet-ex"},
+		}},
+	}
+	out, err := r.Run(manifests)
+	g.Expect(err).To(o.Succeed())
+
+	type resource struct {
+		Kind     string `yaml:"kind"`
+		Metadata struct {
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+	}
+	kinds := []string{}
+	dec := yaml.NewDecoder(out)
+	for {
+		var r resource
+		if dec.Decode(&r) != nil {
+			break
+		}
+		g.Expect(r.Metadata.Labels).To(o.HaveKey("app.kubernetes.io/managed-by"))
+		kinds = append(kinds, r.Kind)
+	}
+	// Cluster-scoped kinds are dropped, unknown kinds kept.
+	g.Expect(kinds).To(o.Equal([]string{"ConfigMap", "Widget"}))
+}
@@ -76,3 +76,18 @@ func TestEngine_Render(t *testing.T) {
 	g.Expect(err).To(o.Succeed())
 	g.Expect(root["catalogURL"]).To(o.Equal(product.Properties["catalogURL"]))
 }
+
+func TestEngine_RenderContext(t *testing.T) {
+	g := o.NewWithT(t)
+
+	variables := NewVariables()
+	err := variables.SetContext(map[string]any{
+		"cloud": map[string]any{"region": "us-east-1"},
+	})
+	g.Expect(err).To(o.Succeed())
+
+	e := NewEngine(nil, `region: {{ .Context.cloud.region }}`)
+	payload, err := e.Render(variables)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(string(payload)).To(o.Equal("region: us-east-1"))
+}
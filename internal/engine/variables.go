@@ -15,6 +15,7 @@ import (
 type Variables struct {
 	Installer chartutil.Values // .Installer
 	OpenShift chartutil.Values // .OpenShift
+	Context   chartutil.Values // .Context
 }
 
 // SetInstaller sets the installer configuration.
@@ -25,14 +26,34 @@ func (v *Variables) SetInstaller(cfg *config.Config) error {
 		return err
 	}
 	v.Installer["Settings"] = settings.AsMap()
-	products := map[string]interface{}{}
-	for _, product := range cfg.Installer.Products {
-		products[product.KeyName()] = product
+	return v.SetProducts(cfg.Installer.Products)
+}
+
+// SetProducts sets the installer products, replacing the configuration's, for
+// instance with the property value references resolved.
+func (v *Variables) SetProducts(products []config.Product) error {
+	byKey := map[string]interface{}{}
+	for _, product := range products {
+		byKey[product.KeyName()] = product
 	}
-	v.Installer["Products"], err = UnstructuredType(products)
+	var err error
+	v.Installer["Products"], err = UnstructuredType(byKey)
 	return err
 }
 
+// SetContext sets the application provided context values.
+func (v *Variables) SetContext(values map[string]any) error {
+	if values == nil {
+		return nil
+	}
+	u, err := UnstructuredType(values)
+	if err != nil {
+		return err
+	}
+	v.Context = u
+	return nil
+}
+
 func getMinorVersion(version string) (string, error) {
 	parts := strings.Split(version, ".")
 	if len(parts) < 2 {
@@ -92,5 +113,6 @@ func NewVariables() *Variables {
 	return &Variables{
 		Installer: chartutil.Values{},
 		OpenShift: chartutil.Values{},
+		Context:   chartutil.Values{},
 	}
 }
@@ -0,0 +1,169 @@
+package flags
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// EnvVar an environment variable recognized by the application.
+type EnvVar struct {
+	Name        string `json:"name"`           // variable name
+	Command     string `json:"command"`        // command path defining the flag
+	Flag        string `json:"flag,omitempty"` // flag bound to the variable
+	Description string `json:"description"`    // flag usage, or variable purpose
+	Set         bool   `json:"set"`            // whether the variable is set
+}
+
+// ExternalEnv the environment variables read by the installer not bound to a
+// flag, following their upstream conventions.
+var ExternalEnv = []EnvVar{{
+	Name:        "KUBECONFIG",
+	Flag:        "kube-config",
+	Description: "Path to the 'kubeconfig' file, when the flag and its variable are unset",
+}, {
+	Name:        "EDITOR",
+	Command:     "config edit",
+	Description: "Editor command for the cluster configuration",
+}, {
+	Name:        "HELM_DRIVER",
+	Description: "Helm release storage driver, defaults to Secrets",
+}}
+
+// nonAlphanumeric matches the characters replaced on variable names.
+var nonAlphanumeric = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// Binder binds the command flags to environment variables, the precedence is
+// the flag informed on the command line, then the variable, then the flag
+// default, which for many flags means the cluster configuration. Variables are
+// named after the application, the command defining the flag and the flag, for
+// instance "HELMET_EX_DEPLOY_ADOPT" for "helmet-ex deploy --adopt"; the global
+// flags only carry the application prefix, "HELMET_EX_LOG_LEVEL".
+type Binder struct {
+	prefix string // variable name prefix, the application name
+}
+
+// envName turns the name segments into an environment variable name.
+func envName(segments ...string) string {
+	name := strings.ToUpper(strings.Join(segments, "_"))
+	return strings.Trim(nonAlphanumeric.ReplaceAllString(name, "_"), "_")
+}
+
+// bindable returns whether the flag is bound to a variable, help, version and
+// hidden flags are not.
+func bindable(f *pflag.Flag) bool {
+	return !f.Hidden && f.Name != "help" && f.Name != "version"
+}
+
+// owner returns the command defining the flag, the top-most ancestor sharing it
+// as a persistent flag, or the command itself for local flags.
+func owner(cmd *cobra.Command, name string) *cobra.Command {
+	found := cmd
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.PersistentFlags().Lookup(name) != nil {
+			found = c
+		}
+	}
+	return found
+}
+
+// commandPath returns the command path without the root command name.
+func commandPath(cmd *cobra.Command) []string {
+	path := []string{}
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		path = append([]string{c.Name()}, path...)
+	}
+	return path
+}
+
+// Prefix returns the variables name prefix, after the application name.
+func (b *Binder) Prefix() string {
+	return envName(b.prefix)
+}
+
+// EnvName returns the environment variable bound to the command flag.
+func (b *Binder) EnvName(cmd *cobra.Command, flag string) string {
+	segments := append([]string{b.prefix}, commandPath(owner(cmd, flag))...)
+	return envName(append(segments, flag)...)
+}
+
+// Bind sets the command flags not informed on the command line from their
+// environment variables, meant for the root command "PersistentPreRunE", thus
+// every subcommand is bound before its Complete. A value the flag can't parse
+// is a usage error naming the variable.
+func (b *Binder) Bind(cmd *cobra.Command) error {
+	values := map[string]string{}
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed || !bindable(f) {
+			return
+		}
+		if value, ok := os.LookupEnv(b.EnvName(cmd, f.Name)); ok {
+			values[f.Name] = value
+		}
+	})
+	for name, value := range values {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return fmt.Errorf("%w: %s: %w", helmeterrors.ErrInvalidUsage,
+				b.EnvName(cmd, name), err)
+		}
+	}
+	return nil
+}
+
+// Variables returns the environment variables recognized by every command on
+// the tree, hidden commands excluded, sorted by name, followed by the external ones, see ExternalEnv.
+func (b *Binder) Variables(root *cobra.Command) []EnvVar {
+	seen := map[string]bool{}
+	vars := []EnvVar{}
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		visit := func(f *pflag.Flag) {
+			if !bindable(f) {
+				return
+			}
+			name := b.EnvName(cmd, f.Name)
+			if seen[name] {
+				return
+			}
+			seen[name] = true
+			_, set := os.LookupEnv(name)
+			usage, _, _ := strings.Cut(strings.TrimSpace(f.Usage), "\n")
+			vars = append(vars, EnvVar{
+				Name:        name,
+				Command:     strings.Join(commandPath(owner(cmd, f.Name)), " "),
+				Flag:        f.Name,
+				Description: usage,
+				Set:         set,
+			})
+		}
+		cmd.LocalFlags().VisitAll(visit)
+		cmd.PersistentFlags().VisitAll(visit)
+		for _, sub := range cmd.Commands() {
+			// Shell completion is generated by cobra, not an installer command.
+			if !sub.Hidden && sub.Name() != "completion" {
+				walk(sub)
+			}
+		}
+	}
+	walk(root)
+	slices.SortFunc(vars, func(a, b EnvVar) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, v := range ExternalEnv {
+		_, v.Set = os.LookupEnv(v.Name)
+		vars = append(vars, v)
+	}
+	return vars
+}
+
+// NewBinder instantiates the binder for the application name.
+func NewBinder(appName string) *Binder {
+	return &Binder{prefix: appName}
+}
@@ -0,0 +1,131 @@
+package flags
+
+import (
+	"errors"
+	"testing"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"github.com/spf13/cobra"
+)
+
+// newCommandTree returns a root command with global flags and a "deploy"
+// subcommand with a local flag.
+func newCommandTree() (*cobra.Command, *cobra.Command, *Flags, *string) {
+	f := NewFlags()
+	root := &cobra.Command{Use: "helmet-ex"}
+	f.PersistentFlags(root.PersistentFlags())
+	var mode string
+	deploy := &cobra.Command{Use: "deploy", RunE: func(*cobra.Command, []string) error {
+		return nil
+	}}
+	deploy.PersistentFlags().StringVar(&mode, "security-scan", "", "mode")
+	root.AddCommand(deploy)
+	return root, deploy, f, &mode
+}
+
+func TestBinder_EnvName(t *testing.T) {
+	root, deploy, _, _ := newCommandTree()
+	b := NewBinder("helmet-ex")
+
+	tests := []struct {
+		cmd  *cobra.Command
+		flag string
+		want string
+	}{
+		{root, "log-level", "HELMET_EX_LOG_LEVEL"},
+		{deploy, "log-level", "HELMET_EX_LOG_LEVEL"},
+		{deploy, "security-scan", "HELMET_EX_DEPLOY_SECURITY_SCAN"},
+	}
+	for _, tt := range tests {
+		if got := b.EnvName(tt.cmd, tt.flag); got != tt.want {
+			t.Errorf("EnvName(%q): got %q, want %q", tt.flag, got, tt.want)
+		}
+	}
+	if got := b.Prefix(); got != "HELMET_EX" {
+		t.Errorf("Prefix: got %q, want %q", got, "HELMET_EX")
+	}
+}
+
+func TestBinder_Bind(t *testing.T) {
+	t.Setenv("HELMET_EX_DRY_RUN", "true")
+	t.Setenv("HELMET_EX_DEPLOY_SECURITY_SCAN", "warn")
+
+	t.Run("environment", func(t *testing.T) {
+		root, deploy, f, mode := newCommandTree()
+		b := NewBinder("helmet-ex")
+		root.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+			return b.Bind(cmd)
+		}
+		root.SetArgs([]string{"deploy"})
+		if err := root.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !f.DryRun {
+			t.Error("DryRun: expected the environment variable to apply")
+		}
+		if *mode != "warn" {
+			t.Errorf("mode: got %q, want %q", *mode, "warn")
+		}
+		if !deploy.Flags().Changed("security-scan") {
+			t.Error("expected the flag marked as changed")
+		}
+	})
+
+	t.Run("flag precedence", func(t *testing.T) {
+		root, _, _, mode := newCommandTree()
+		b := NewBinder("helmet-ex")
+		root.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+			return b.Bind(cmd)
+		}
+		root.SetArgs([]string{"deploy", "--security-scan=enforce"})
+		if err := root.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *mode != "enforce" {
+			t.Errorf("mode: got %q, want %q", *mode, "enforce")
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		t.Setenv("HELMET_EX_KUBE_BURST", "many")
+		root, _, _, _ := newCommandTree()
+		b := NewBinder("helmet-ex")
+		root.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+			return b.Bind(cmd)
+		}
+		root.SilenceErrors = true
+		root.SetArgs([]string{"deploy"})
+		err := root.Execute()
+		if !errors.Is(err, helmeterrors.ErrInvalidUsage) {
+			t.Errorf("expected an invalid usage error, got %v", err)
+		}
+	})
+}
+
+func TestBinder_Variables(t *testing.T) {
+	t.Setenv("HELMET_EX_DEPLOY_SECURITY_SCAN", "warn")
+	root, _, _, _ := newCommandTree()
+	vars := NewBinder("helmet-ex").Variables(root)
+
+	byName := map[string]EnvVar{}
+	for _, v := range vars {
+		byName[v.Name] = v
+	}
+	scan, ok := byName["HELMET_EX_DEPLOY_SECURITY_SCAN"]
+	if !ok {
+		t.Fatal("expected the deploy flag variable")
+	}
+	if !scan.Set || scan.Command != "deploy" || scan.Flag != "security-scan" {
+		t.Errorf("unexpected variable: %+v", scan)
+	}
+	if _, ok := byName["HELMET_EX_LOG_LEVEL"]; !ok {
+		t.Error("expected the global flag variable")
+	}
+	if _, ok := byName["HELMET_EX_VERSION"]; ok {
+		t.Error("unexpected variable for the version flag")
+	}
+	if vars[len(vars)-len(ExternalEnv)].Name != "KUBECONFIG" {
+		t.Error("expected the external variables listed last")
+	}
+}
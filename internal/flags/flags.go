@@ -18,9 +18,12 @@ type Flags struct {
 	DryRun         bool          // dry-run mode
 	Verbose        bool          // verbose output
 	KubeConfigPath string        // path to the kubeconfig file
+	KubeContext    string        // kubeconfig context, the target cluster
 	LogLevel       *slog.Level   // log verbosity level
 	Timeout        time.Duration // helm client timeout
 	Version        bool          // show version
+	KubeQPS        float32       // kubernetes API requests per second
+	KubeBurst      int           // kubernetes API requests burst
 }
 
 // PersistentFlags sets up the global flags.
@@ -48,6 +51,20 @@ func (f *Flags) PersistentFlags(p *pflag.FlagSet) {
 			strings.ToLower(f.LogLevel.String()),
 		),
 	)
+	p.Float32Var(
+		&f.KubeQPS,
+		"kube-qps",
+		f.KubeQPS,
+		"Maximum Kubernetes API requests per second, shared by all clients, "+
+			"zero uses the client defaults",
+	)
+	p.IntVar(
+		&f.KubeBurst,
+		"kube-burst",
+		f.KubeBurst,
+		"Kubernetes API requests burst above --kube-qps, defaults to twice "+
+			"the QPS",
+	)
 	p.Var(
 		NewDurationValue(&f.Timeout),
 		"timeout",
@@ -1,6 +1,12 @@
 package flags
 
 import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
 	"github.com/redhat-appstudio/helmet/internal/constants"
 
 	"github.com/spf13/pflag"
@@ -18,3 +24,90 @@ func SetValuesTmplFlag(p *pflag.FlagSet, v *string) {
 		"Path to the values template file",
 	)
 }
+
+// OutputFlag the output format flag name.
+const OutputFlag = "output"
+
+// SetOutputFlag sets up the output flag, "-o", to the informed pointer.
+func SetOutputFlag(p *pflag.FlagSet, v *string) {
+	p.StringVarP(
+		v,
+		OutputFlag,
+		"o",
+		"",
+		"Output format: table (default), json, yaml, jsonpath=<template>, "+
+			"go-template=<template> or custom-columns=<header>:<jsonpath>,...",
+	)
+}
+
+// ExpandEnvFlag the flag name to expand environment variables on the
+// configuration file.
+const ExpandEnvFlag = "expand-env"
+
+// SetExpandEnvFlag sets up the expand-env flag to the informed pointer.
+func SetExpandEnvFlag(p *pflag.FlagSet, v *bool) {
+	p.BoolVar(
+		v,
+		ExpandEnvFlag,
+		false,
+		`Expand "${VAR}" and "${VAR:-fallback}" environment variables on the `+
+			"configuration file",
+	)
+}
+
+// SetEnvFlag the flag name to inform configuration file variables.
+const SetEnvFlag = "set-env"
+
+// EnvVars the configuration file variables informed with "--set-env", as
+// "KEY=VALUE" assignments, repeating the flag for each variable.
+type EnvVars map[string]string
+
+var _ pflag.Value = EnvVars{}
+
+// envVarNameRE matches the valid variable names.
+var envVarNameRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// String returns the variable names informed, the values may be sensitive.
+func (e EnvVars) String() string {
+	return strings.Join(slices.Sorted(maps.Keys(e)), ",")
+}
+
+// Set parses the "KEY=VALUE" assignment, the value may be empty.
+func (e EnvVars) Set(s string) error {
+	name, value, found := strings.Cut(s, "=")
+	if !found || !envVarNameRE.MatchString(name) {
+		return fmt.Errorf("invalid assignment %q, KEY=VALUE expected", s)
+	}
+	e[name] = value
+	return nil
+}
+
+// Type returns the flag value type, shown on the usage.
+func (e EnvVars) Type() string {
+	return "KEY=VALUE"
+}
+
+// SetSetEnvFlag sets up the set-env flag to the informed variables.
+func SetSetEnvFlag(p *pflag.FlagSet, v EnvVars) {
+	p.Var(
+		v,
+		SetEnvFlag,
+		`Set a "${VAR}" variable on the configuration file, taking precedence `+
+			"over the environment, implies --"+ExpandEnvFlag,
+	)
+}
+
+// ClusterFlag the flag name to select the target cluster.
+const ClusterFlag = "cluster"
+
+// SetClusterFlag sets up the cluster flag, selecting the kubeconfig context and
+// the configuration document targeting it, to the informed pointer.
+func SetClusterFlag(p *pflag.FlagSet, v *string) {
+	p.StringVar(
+		v,
+		ClusterFlag,
+		"",
+		"Target cluster, the kubeconfig context and the configuration "+
+			"document declaring it",
+	)
+}
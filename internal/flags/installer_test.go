@@ -0,0 +1,65 @@
+package flags
+
+import (
+	"testing"
+)
+
+func TestEnvVars_Set(t *testing.T) {
+	tests := []struct {
+		name       string
+		assignment string
+		wantName   string
+		wantValue  string
+		wantErr    bool
+	}{
+		{
+			name:       "assignment",
+			assignment: "DOMAIN=apps.stage.example.com",
+			wantName:   "DOMAIN",
+			wantValue:  "apps.stage.example.com",
+		},
+		{
+			name:       "value with equals and commas",
+			assignment: "LABELS=a=b,c=d",
+			wantName:   "LABELS",
+			wantValue:  "a=b,c=d",
+		},
+		{
+			name:       "empty value",
+			assignment: "STORAGE_CLASS=",
+			wantName:   "STORAGE_CLASS",
+		},
+		{
+			name:       "missing value",
+			assignment: "DOMAIN",
+			wantErr:    true,
+		},
+		{
+			name:       "invalid name",
+			assignment: "1DOMAIN=example.com",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := EnvVars{}
+			err := e.Set(tt.assignment)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EnvVars.Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if value, ok := e[tt.wantName]; !ok || value != tt.wantValue {
+				t.Errorf("EnvVars.Set() %s = %q, expected = %q",
+					tt.wantName, value, tt.wantValue)
+			}
+			// Only the names are shown, the values may be sensitive.
+			if e.String() != tt.wantName {
+				t.Errorf("EnvVars.String() = %q, expected = %q",
+					e.String(), tt.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+)
+
+// ErrRateLimited the GitHub API rate limit resets later than the client waits.
+var ErrRateLimited = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"github rate limit exceeded")
+
+// ErrInvalidCABundle the CA bundle file can't be read, or holds no certificate.
+var ErrInvalidCABundle = helmeterrors.New(helmeterrors.ErrInvalidUsage,
+	"invalid CA bundle")
+
+const (
+	// DefaultRetries the attempts after the first, for the transient failures
+	// and the rate limits.
+	DefaultRetries = 3
+	// DefaultMaxRateLimitWait the longest wait for a rate limit to reset, the
+	// request fails with ErrRateLimited beyond it.
+	DefaultMaxRateLimitWait = 2 * time.Minute
+
+	// secondaryRateLimitWait the wait for the secondary rate limit when GitHub
+	// doesn't inform "Retry-After", as its documentation advises.
+	secondaryRateLimitWait = time.Minute
+	// retryBaseWait the first backoff wait, doubled on each attempt.
+	retryBaseWait = time.Second
+	// etagCacheSize the responses cached by their ETag.
+	etagCacheSize = 256
+)
+
+// ClientOptions the settings of the shared GitHub API HTTP client.
+type ClientOptions struct {
+	CABundle         string        // PEM CA bundle file, trusted on top of the system's
+	Retries          int           // attempts after the first
+	MaxRateLimitWait time.Duration // longest wait for a rate limit to reset
+}
+
+// cached a GitHub API response cached by its ETag, replayed when GitHub answers
+// "304 Not Modified", which doesn't count against the rate limit.
+type cached struct {
+	etag   string      // response ETag
+	status int         // response status code
+	header http.Header // response headers
+	body   []byte      // response body
+}
+
+// Transport the GitHub API round tripper shared by the features reaching
+// GitHub. It retries the transient failures of the idempotent requests with
+// exponential backoff, waits for the primary and secondary rate limits to reset,
+// and sends the cached ETag on repeated GET requests, replaying the cached
+// response when not modified.
+type Transport struct {
+	logger  *slog.Logger      // application logger
+	base    http.RoundTripper // underlying transport
+	retries int               // attempts after the first
+	maxWait time.Duration     // longest wait for a rate limit to reset
+
+	// sleep waits for the duration, or until the context is done.
+	sleep func(context.Context, time.Duration) error
+
+	mu    sync.Mutex         // guards the cache
+	cache map[string]*cached // responses by method, URL and credentials
+}
+
+var _ http.RoundTripper = &Transport{}
+
+// sleep waits for the duration, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// idempotent asserts the request can be repeated without side effects.
+func idempotent(req *http.Request) bool {
+	return req.Method == http.MethodGet || req.Method == http.MethodHead
+}
+
+// cacheKey returns the request cache key, empty for the requests not cached.
+// The credentials are part of the key, hashed, so responses are never shared
+// between tokens.
+func cacheKey(req *http.Request) string {
+	if req.Method != http.MethodGet {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return req.URL.String() + "#" + hex.EncodeToString(sum[:])
+}
+
+// lookup returns the cached response of the key, if any.
+func (t *Transport) lookup(key string) *cached {
+	if key == "" {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cache[key]
+}
+
+// store caches the response body by its ETag, the response is returned with
+// its body rewound.
+func (t *Transport) store(key string, res *http.Response) (*http.Response, error) {
+	etag := res.Header.Get("ETag")
+	if key == "" || etag == "" || res.StatusCode != http.StatusOK {
+		return res, nil
+	}
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, found := t.cache[key]; !found && len(t.cache) >= etagCacheSize {
+		// Evicting an arbitrary entry, the cache is a best effort.
+		for k := range t.cache {
+			delete(t.cache, k)
+			break
+		}
+	}
+	t.cache[key] = &cached{
+		etag:   etag,
+		status: res.StatusCode,
+		header: res.Header.Clone(),
+		body:   body,
+	}
+	return res, nil
+}
+
+// replay returns the cached response in place of the "304 Not Modified" one,
+// keeping the latest rate limit headers.
+func replay(entry *cached, res *http.Response) *http.Response {
+	_ = res.Body.Close()
+	header := entry.header.Clone()
+	for k, v := range res.Header {
+		if strings.HasPrefix(k, "X-Ratelimit-") {
+			header[k] = v
+		}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.status, http.StatusText(entry.status)),
+		StatusCode:    entry.status,
+		Proto:         res.Proto,
+		ProtoMajor:    res.ProtoMajor,
+		ProtoMinor:    res.ProtoMinor,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.body)),
+		ContentLength: int64(len(entry.body)),
+		Request:       res.Request,
+	}
+}
+
+// rateLimitWait returns the wait for the rate limit to reset, when the response
+// is rate limited: the secondary limit informs "Retry-After", the primary its
+// reset time once no request remains.
+func rateLimitWait(res *http.Response, now time.Time) (time.Duration, bool) {
+	if res.StatusCode != http.StatusForbidden &&
+		res.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if v := res.Header.Get("Retry-After"); v != "" {
+		if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		return secondaryRateLimitWait, true
+	}
+	if res.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if err != nil {
+			return secondaryRateLimitWait, true
+		}
+		return max(time.Unix(reset, 0).Sub(now), 0) + time.Second, true
+	}
+	if res.StatusCode == http.StatusTooManyRequests {
+		return secondaryRateLimitWait, true
+	}
+	return 0, false
+}
+
+// transient asserts the failure is worth retrying, only for the idempotent
+// requests, the others may have been processed.
+func transient(req *http.Request, res *http.Response, err error) bool {
+	if !idempotent(req) {
+		return false
+	}
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	switch res.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// prepare returns the request attempt, with its body rewound and the cached
+// ETag informed.
+func prepare(req *http.Request, entry *cached, attempt int) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if attempt > 0 && req.Body != nil && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	if entry != nil {
+		r.Header.Set("If-None-Match", entry.etag)
+	}
+	return r, nil
+}
+
+// RoundTrip executes the request, retrying and caching as described on the
+// Transport.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := cacheKey(req)
+	entry := t.lookup(key)
+	// Requests with a body which can't be rewound are attempted only once.
+	retries := t.retries
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		retries = 0
+	}
+	for attempt := 0; ; attempt++ {
+		r, err := prepare(req, entry, attempt)
+		if err != nil {
+			return nil, err
+		}
+		res, err := t.base.RoundTrip(r)
+
+		var wait time.Duration
+		limited := false
+		if err == nil {
+			wait, limited = rateLimitWait(res, time.Now())
+		}
+		if !limited && !transient(req, res, err) || attempt >= retries {
+			if err != nil {
+				return nil, err
+			}
+			if entry != nil && res.StatusCode == http.StatusNotModified {
+				t.logger.Debug("GitHub API response not modified, replaying",
+					"method", req.Method, "url", req.URL.Redacted())
+				return replay(entry, res), nil
+			}
+			return t.store(key, res)
+		}
+		if res != nil {
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+		if limited && wait > t.maxWait {
+			return nil, fmt.Errorf("%w: %s %s: resets in %s, beyond the %s wait",
+				ErrRateLimited, req.Method, req.URL.Redacted(),
+				wait.Round(time.Second), t.maxWait)
+		}
+		if !limited {
+			wait = retryBaseWait << attempt
+		}
+		t.logger.Debug("Retrying the GitHub API request",
+			"method", req.Method,
+			"url", req.URL.Redacted(),
+			"attempt", attempt+1,
+			"rate-limited", limited,
+			"wait", wait,
+			"err", err,
+		)
+		if err = t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// NewTransport instantiates the GitHub API round tripper over the base one.
+func NewTransport(
+	logger *slog.Logger,
+	base http.RoundTripper,
+	opts ClientOptions,
+) *Transport {
+	t := &Transport{
+		logger:  logger,
+		base:    base,
+		retries: opts.Retries,
+		maxWait: opts.MaxRateLimitWait,
+		sleep:   sleep,
+		cache:   map[string]*cached{},
+	}
+	if t.retries <= 0 {
+		t.retries = DefaultRetries
+	}
+	if t.maxWait <= 0 {
+		t.maxWait = DefaultMaxRateLimitWait
+	}
+	return t
+}
+
+// NewHTTPClient instantiates the HTTP client shared by the features reaching
+// the GitHub API, on the Transport. The proxy is read from the environment,
+// "HTTPS_PROXY" and "NO_PROXY", and the CA bundle, when informed, is trusted
+// on top of the system certificates, as GitHub Enterprise often requires.
+func NewHTTPClient(logger *slog.Logger, opts ClientOptions) (*http.Client, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.Proxy = http.ProxyFromEnvironment
+	base.ResponseHeaderTimeout = 30 * time.Second
+	if opts.CABundle != "" {
+		payload, err := os.ReadFile(opts.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCABundle, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(payload) {
+			return nil, fmt.Errorf("%w: %q holds no PEM certificate",
+				ErrInvalidCABundle, opts.CABundle)
+		}
+		base.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	return &http.Client{Transport: NewTransport(logger, base, opts)}, nil
+}
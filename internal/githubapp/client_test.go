@@ -0,0 +1,235 @@
+package githubapp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	o "github.com/onsi/gomega"
+)
+
+func TestTransport(t *testing.T) {
+	ctx := context.Background()
+
+	// newTransport returns the transport on the default one, recording the
+	// waits instead of sleeping.
+	newTransport := func(waits *[]time.Duration) *Transport {
+		tr := NewTransport(slog.Default(), http.DefaultTransport, ClientOptions{})
+		tr.sleep = func(_ context.Context, d time.Duration) error {
+			*waits = append(*waits, d)
+			return nil
+		}
+		return tr
+	}
+	get := func(tr *Transport, method, url string) (*http.Response, string, error) {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return nil, "", err
+		}
+		res, err := tr.RoundTrip(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		return res, string(body), err
+	}
+
+	t.Run("transient", func(t *testing.T) {
+		g := o.NewWithT(t)
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				if calls++; calls < 3 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				_, _ = w.Write([]byte("ok"))
+			}))
+		defer srv.Close()
+
+		waits := []time.Duration{}
+		res, body, err := get(newTransport(&waits), http.MethodGet, srv.URL)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(res.StatusCode).To(o.Equal(http.StatusOK))
+		g.Expect(body).To(o.Equal("ok"))
+		g.Expect(waits).To(o.Equal([]time.Duration{time.Second, 2 * time.Second}))
+
+		// Not idempotent, the request may have been processed.
+		calls, waits = 0, []time.Duration{}
+		res, _, err = get(newTransport(&waits), http.MethodPost, srv.URL)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(res.StatusCode).To(o.Equal(http.StatusBadGateway))
+		g.Expect(waits).To(o.BeEmpty())
+	})
+
+	t.Run("rate limits", func(t *testing.T) {
+		g := o.NewWithT(t)
+		reset := time.Now().Add(time.Hour)
+		calls := 0
+		srv := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				switch {
+				case r.URL.Path == "/secondary" && calls == 1:
+					w.Header().Set("Retry-After", "7")
+					w.WriteHeader(http.StatusForbidden)
+				case r.URL.Path == "/primary":
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset",
+						strconv.FormatInt(reset.Unix(), 10))
+					w.WriteHeader(http.StatusForbidden)
+				case r.URL.Path == "/forbidden":
+					w.WriteHeader(http.StatusForbidden)
+				default:
+					_, _ = w.Write([]byte("ok"))
+				}
+			}))
+		defer srv.Close()
+
+		// The secondary rate limit is waited, regardless of the method.
+		waits := []time.Duration{}
+		res, _, err := get(newTransport(&waits), http.MethodPost, srv.URL+"/secondary")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(res.StatusCode).To(o.Equal(http.StatusOK))
+		g.Expect(waits).To(o.Equal([]time.Duration{7 * time.Second}))
+
+		// The primary rate limit resets beyond the wait.
+		waits = []time.Duration{}
+		_, _, err = get(newTransport(&waits), http.MethodGet, srv.URL+"/primary")
+		g.Expect(err).To(o.MatchError(ErrRateLimited))
+		g.Expect(waits).To(o.BeEmpty())
+
+		// Forbidden without rate limit headers is final.
+		res, _, err = get(newTransport(&waits), http.MethodGet, srv.URL+"/forbidden")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(res.StatusCode).To(o.Equal(http.StatusForbidden))
+		g.Expect(waits).To(o.BeEmpty())
+	})
+
+	t.Run("etag", func(t *testing.T) {
+		g := o.NewWithT(t)
+		conditional := 0
+		srv := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-RateLimit-Remaining", "4999")
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					conditional++
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("ETag", `"v1"`)
+				_, _ = w.Write([]byte(`{"login":"octocat"}`))
+			}))
+		defer srv.Close()
+
+		waits := []time.Duration{}
+		tr := newTransport(&waits)
+		for range 3 {
+			res, body, err := get(tr, http.MethodGet, srv.URL+"/user")
+			g.Expect(err).To(o.Succeed())
+			g.Expect(res.StatusCode).To(o.Equal(http.StatusOK))
+			g.Expect(body).To(o.Equal(`{"login":"octocat"}`))
+			g.Expect(res.Header.Get("ETag")).To(o.Equal(`"v1"`))
+		}
+		g.Expect(conditional).To(o.Equal(2))
+	})
+
+	t.Run("ca bundle", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := NewHTTPClient(slog.Default(), ClientOptions{
+			CABundle: filepath.Join(t.TempDir(), "missing.pem"),
+		})
+		g.Expect(err).To(o.MatchError(ErrInvalidCABundle))
+
+		file := filepath.Join(t.TempDir(), "ca.pem")
+		g.Expect(os.WriteFile(file, []byte("not PEM"), 0o600)).To(o.Succeed())
+		_, err = NewHTTPClient(slog.Default(), ClientOptions{CABundle: file})
+		g.Expect(err).To(o.MatchError(ErrInvalidCABundle))
+
+		client, err := NewHTTPClient(slog.Default(), ClientOptions{})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(client.Transport).To(o.BeAssignableToTypeOf(&Transport{}))
+	})
+}
+
+func TestGitHubAppVerifyToken(t *testing.T) {
+	g := o.NewWithT(t)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v3/user" ||
+				r.Header.Get("Authorization") != "Bearer ghp_valid" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"login":"octocat"}`))
+		}))
+	defer srv.Close()
+
+	app := NewGitHubApp(slog.Default())
+	app.gitHubURL = srv.URL
+	g.Expect(app.Validate()).To(o.Succeed())
+
+	login, err := app.VerifyToken(context.Background(), "ghp_valid")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(login).To(o.Equal("octocat"))
+
+	_, err = app.VerifyToken(context.Background(), "ghp_invalid")
+	g.Expect(err).To(o.MatchError(ErrInvalidToken))
+}
+
+func TestGitHubAppVerifyApp(t *testing.T) {
+	g := o.NewWithT(t)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	g.Expect(err).To(o.Succeed())
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			token, err := jwt.ParseWithClaims(
+				strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+				&jwt.RegisteredClaims{},
+				func(*jwt.Token) (any, error) { return &key.PublicKey, nil },
+				jwt.WithIssuer("12345"),
+			)
+			if r.URL.Path != "/api/v3/app" || err != nil || !token.Valid {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"slug":"helmet-app"}`))
+		}))
+	defer srv.Close()
+
+	app := NewGitHubApp(slog.Default())
+	g.Expect(app.ForHost("github.com").gitHubURL).
+		To(o.Equal(defaultPublicGitHubURL))
+	g.Expect(app.ForHost("github.example.com").gitHubURL).
+		To(o.Equal("https://github.example.com"))
+	app.gitHubURL = srv.URL
+
+	slug, err := app.VerifyApp(context.Background(), "12345", keyPEM)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(slug).To(o.Equal("helmet-app"))
+
+	_, err = app.VerifyApp(context.Background(), "54321", keyPEM)
+	g.Expect(err).To(o.MatchError(ErrInvalidAppKey))
+
+	_, err = app.VerifyApp(context.Background(), "12345", []byte("typo"))
+	g.Expect(err).To(o.MatchError(ErrInvalidAppKey))
+}
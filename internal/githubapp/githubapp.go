@@ -11,6 +11,9 @@ import (
 	"path/filepath"
 	"time"
 
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/go-github/scrape"
 	"github.com/google/go-github/v75/github"
 	"github.com/spf13/cobra"
@@ -21,10 +24,12 @@ import (
 // web token, thus the oAuth2 workflow uses the (primary) browser to interact with
 // 2FA and other GitHub security measures.
 type GitHubApp struct {
-	logger *slog.Logger // application logger
+	logger     *slog.Logger // application logger
+	httpClient *http.Client // shared GitHub API http client
 
 	gitHubURL     string // GitHub API URL
 	gitHubOrgName string // GitHub organization name
+	caBundle      string // GitHub API CA bundle file
 	webServerAddr string // local webserver address
 	webServerPort int    // local webserver port
 }
@@ -35,6 +40,14 @@ type AppConfigResult struct {
 	err       error
 }
 
+// ErrInvalidToken the GitHub personal access token doesn't authenticate.
+var ErrInvalidToken = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"invalid github token")
+
+// ErrInvalidAppKey the GitHub App private key doesn't authenticate the App.
+var ErrInvalidAppKey = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"invalid github app private key")
+
 // defaultPublicGitHubURL is the default URL for public GitHub.
 const defaultPublicGitHubURL = "https://github.com"
 
@@ -46,6 +59,8 @@ func (g *GitHubApp) PersistentFlags(cmd *cobra.Command) {
 		"GitHub URL")
 	p.StringVar(&g.gitHubOrgName, "org", g.gitHubOrgName,
 		"GitHub organization name")
+	p.StringVar(&g.caBundle, "ca-bundle", g.caBundle,
+		"PEM CA bundle file trusted for the GitHub API, e.g. GitHub Enterprise's")
 	p.StringVar(&g.webServerAddr, "webserver-addr", g.webServerAddr,
 		"Callback webserver listen address")
 	p.IntVar(&g.webServerPort, "webserver-port", g.webServerPort,
@@ -56,9 +71,25 @@ func (g *GitHubApp) PersistentFlags(cmd *cobra.Command) {
 	}
 }
 
-// Validate validates the GitHub App configuration.
+// Validate validates the GitHub App configuration, the CA bundle is read on
+// instantiating the GitHub API http client.
 func (g *GitHubApp) Validate() error {
-	return nil
+	var err error
+	g.httpClient, err = NewHTTPClient(g.logger, ClientOptions{CABundle: g.caBundle})
+	return err
+}
+
+// HTTPClient returns the shared GitHub API http client, see NewHTTPClient.
+func (g *GitHubApp) HTTPClient() (*http.Client, error) {
+	if g.httpClient != nil {
+		return g.httpClient, nil
+	}
+	client, err := NewHTTPClient(g.logger, ClientOptions{CABundle: g.caBundle})
+	if err != nil {
+		return nil, err
+	}
+	g.httpClient = client
+	return client, nil
 }
 
 // log logger with contextual information.
@@ -66,6 +97,7 @@ func (g *GitHubApp) log() *slog.Logger {
 	return g.logger.With(
 		"github-url", g.gitHubURL,
 		"github-org", g.gitHubOrgName,
+		"ca-bundle", g.caBundle,
 		"webserver-port", g.webServerPort,
 	)
 }
@@ -73,14 +105,82 @@ func (g *GitHubApp) log() *slog.Logger {
 // getGitHubClient returns a GitHub client, either for public GitHub or GitHub
 // enterprise.
 func (g *GitHubApp) getGitHubClient() (*github.Client, error) {
+	httpClient, err := g.HTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	client := github.NewClient(httpClient)
 	if g.gitHubURL == defaultPublicGitHubURL {
 		g.log().Debug("using public GitHub API")
-		return github.NewClient(nil), nil
+		return client, nil
 	}
 	g.log().Debug("using GitHub Enterprise API")
-	client := github.NewClient(nil)
-	client, err := client.WithEnterpriseURLs(g.gitHubURL, g.gitHubURL)
-	return client, err
+	return client.WithEnterpriseURLs(g.gitHubURL, g.gitHubURL)
+}
+
+// VerifyToken asserts the personal access token authenticates on the GitHub
+// API, returning the token's user login.
+func (g *GitHubApp) VerifyToken(ctx context.Context, token string) (string, error) {
+	client, err := g.getGitHubClient()
+	if err != nil {
+		return "", err
+	}
+	user, res, err := client.WithAuthToken(token).Users.Get(ctx, "")
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusUnauthorized {
+			return "", fmt.Errorf("%w: the token is not valid", ErrInvalidToken)
+		}
+		return "", err
+	}
+	return user.GetLogin(), nil
+}
+
+// ForHost returns a copy of the GitHub App for the informed host, public GitHub
+// for "github.com", GitHub Enterprise otherwise. The CA bundle is kept.
+func (g *GitHubApp) ForHost(host string) *GitHubApp {
+	app := *g
+	app.httpClient = nil
+	app.gitHubURL = defaultPublicGitHubURL
+	if host != "" && host != "github.com" {
+		app.gitHubURL = "https://" + host
+	}
+	return &app
+}
+
+// VerifyApp asserts the private key authenticates as the GitHub App, with the
+// App JWT, returning the App slug.
+func (g *GitHubApp) VerifyApp(
+	ctx context.Context,
+	id string,
+	pem []byte,
+) (string, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidAppKey, err)
+	}
+	// Backdating the issue time, allowing for clock drift.
+	now := time.Now()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    id,
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(5 * time.Minute)),
+	}).SignedString(key)
+	if err != nil {
+		return "", err
+	}
+	client, err := g.getGitHubClient()
+	if err != nil {
+		return "", err
+	}
+	app, res, err := client.WithAuthToken(token).Apps.Get(ctx, "")
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusUnauthorized {
+			return "", fmt.Errorf("%w: app %q: the key is not valid",
+				ErrInvalidAppKey, id)
+		}
+		return "", err
+	}
+	return app.GetSlug(), nil
 }
 
 // oAuth2Workflow starts the oAuth2 workflow to create a new GitHub App. The user
@@ -0,0 +1,159 @@
+package installer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Violation a resource rejected by an admission webhook, like Gatekeeper or
+// Kyverno, or by a ValidatingAdmissionPolicy.
+type Violation struct {
+	Dependency string `json:"dependency,omitempty"` // dependency deployed
+	Namespace  string `json:"namespace,omitempty"`  // dependency namespace
+	Resource   string `json:"resource,omitempty"`   // "Kind/name", when known
+	Webhook    string `json:"webhook,omitempty"`    // admission webhook name
+	Policy     string `json:"policy,omitempty"`     // policy violated, when known
+	Message    string `json:"message"`              // rejection message
+}
+
+// String describes the violation in a single line.
+func (v Violation) String() string {
+	var b strings.Builder
+	if v.Resource != "" {
+		fmt.Fprintf(&b, "%s: ", v.Resource)
+	}
+	if v.Policy != "" {
+		fmt.Fprintf(&b, "[%s] ", v.Policy)
+	}
+	b.WriteString(v.Message)
+	if v.Webhook != "" {
+		fmt.Fprintf(&b, " (webhook %q)", v.Webhook)
+	}
+	return b.String()
+}
+
+var (
+	// webhookRe a denial by an admission webhook, the message follows.
+	webhookRe = regexp.MustCompile(
+		`admission webhook "([^"]+)" denied the request:`)
+	// policyRe a denial by a ValidatingAdmissionPolicy.
+	policyRe = regexp.MustCompile(
+		`ValidatingAdmissionPolicy '([^']+)' with binding '[^']*' denied request: ([^\n]*)`)
+	// patchRe the resource Helm failed to patch, preceding the denial.
+	patchRe = regexp.MustCompile(`cannot patch "([^"]+)" with kind (\w+)`)
+	// forbiddenRe the resource forbidden by the API server.
+	forbiddenRe = regexp.MustCompile(`(\w+)(?:\.[\w.-]+)? "([^"]+)" is forbidden`)
+	// kyvernoResourceRe the resource blocked by Kyverno policies.
+	kyvernoResourceRe = regexp.MustCompile(
+		`resource (\S+) was blocked due to the following policies`)
+	// kyvernoPolicyRe a Kyverno policy name, its failed rules follow.
+	kyvernoPolicyRe = regexp.MustCompile(`^([^\s:]+):\s*$`)
+	// kyvernoRuleRe a Kyverno rule failure message.
+	kyvernoRuleRe = regexp.MustCompile(`^\s+([^\s:]+):\s*(.*)$`)
+	// gatekeeperRe a Gatekeeper constraint violation, "[constraint] message".
+	gatekeeperRe = regexp.MustCompile(`^\[([^\]]+)\]\s*(.*)$`)
+)
+
+// isAdmissionDenied checks whether the error message carries an admission
+// denial.
+func isAdmissionDenied(msg string) bool {
+	return webhookRe.MatchString(msg) || policyRe.MatchString(msg)
+}
+
+// resourceOf returns the "Kind/name" of the resource mentioned on the message,
+// the last mention is the closest to the denial.
+func resourceOf(msg string) string {
+	if m := patchRe.FindAllStringSubmatch(msg, -1); len(m) > 0 {
+		last := m[len(m)-1]
+		return last[2] + "/" + last[1]
+	}
+	if m := forbiddenRe.FindAllStringSubmatch(msg, -1); len(m) > 0 {
+		last := m[len(m)-1]
+		return last[1] + "/" + last[2]
+	}
+	return ""
+}
+
+// parseDenial parses the admission webhook message, Kyverno and Gatekeeper
+// report one violation per failed rule or constraint.
+func parseDenial(webhook, resource, body string) []Violation {
+	violations := []Violation{}
+	if m := kyvernoResourceRe.FindStringSubmatch(body); m != nil {
+		resource = m[1]
+		policy := ""
+		for _, line := range strings.Split(body, "\n") {
+			if m := kyvernoPolicyRe.FindStringSubmatch(line); m != nil {
+				policy = m[1]
+				continue
+			}
+			if m := kyvernoRuleRe.FindStringSubmatch(line); m != nil && policy != "" {
+				violations = append(violations, Violation{
+					Resource: resource,
+					Webhook:  webhook,
+					Policy:   policy,
+					Message:  m[1] + ": " + strings.Trim(m[2], `'"`),
+				})
+			}
+		}
+	} else {
+		for _, line := range strings.Split(body, "\n") {
+			if m := gatekeeperRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+				violations = append(violations, Violation{
+					Resource: resource,
+					Webhook:  webhook,
+					Policy:   m[1],
+					Message:  m[2],
+				})
+			}
+		}
+	}
+	if len(violations) == 0 {
+		violations = append(violations, Violation{
+			Resource: resource,
+			Webhook:  webhook,
+			Message:  body,
+		})
+	}
+	return violations
+}
+
+// ParseViolations extracts the admission violations from the deployment error,
+// Helm aggregates the rejections of several resources on a single error.
+func ParseViolations(err error) []Violation {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	violations := []Violation{}
+
+	matches := webhookRe.FindAllStringSubmatchIndex(msg, -1)
+	for i, m := range matches {
+		start, end := 0, len(msg)
+		if i > 0 {
+			start = matches[i-1][1]
+		}
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		body := msg[m[1]:end]
+		// Helm lists aggregated errors as "\n\t* <error>".
+		if idx := strings.Index(body, "\n\t* "); idx >= 0 {
+			body = body[:idx]
+		}
+		violations = append(violations, parseDenial(
+			msg[m[2]:m[3]],
+			resourceOf(msg[start:m[0]]),
+			strings.TrimSpace(body),
+		)...)
+	}
+
+	for _, m := range policyRe.FindAllStringSubmatchIndex(msg, -1) {
+		violations = append(violations, Violation{
+			Resource: resourceOf(msg[:m[0]]),
+			Policy:   msg[m[2]:m[3]],
+			Message:  strings.TrimSpace(msg[m[4]:m[5]]),
+		})
+	}
+	return violations
+}
@@ -0,0 +1,118 @@
+package installer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestParseViolations(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []Violation
+	}{{
+		name: "gatekeeper",
+		err: fmt.Errorf("%w: %w", deployer.ErrInstallFailed, errors.New(
+			`cannot patch "web" with kind Deployment: admission webhook `+
+				`"validation.gatekeeper.sh" denied the request: `+
+				"[required-labels] missing label \"owner\"\n"+
+				"[allowed-repos] container <web> has an invalid image repo")),
+		want: []Violation{{
+			Resource: "Deployment/web",
+			Webhook:  "validation.gatekeeper.sh",
+			Policy:   "required-labels",
+			Message:  `missing label "owner"`,
+		}, {
+			Resource: "Deployment/web",
+			Webhook:  "validation.gatekeeper.sh",
+			Policy:   "allowed-repos",
+			Message:  "container <web> has an invalid image repo",
+		}},
+	}, {
+		name: "kyverno",
+		err: errors.New(`failed to create resource: admission webhook ` +
+			`"validate.kyverno.svc-fail" denied the request: 
+
+resource Deployment/product-a/web was blocked due to the following policies 
+
+require-requests-limits:
+  validate-resources: 'validation error: CPU and memory resource requests and limits are required.'
+`),
+		want: []Violation{{
+			Resource: "Deployment/product-a/web",
+			Webhook:  "validate.kyverno.svc-fail",
+			Policy:   "require-requests-limits",
+			Message: "validate-resources: validation error: CPU and memory " +
+				"resource requests and limits are required.",
+		}},
+	}, {
+		name: "validating admission policy",
+		err: errors.New(`failed to create resource: Deployment.apps "web" is ` +
+			`forbidden: ValidatingAdmissionPolicy 'replica-limit' with binding ` +
+			`'replica-limit-binding' denied request: replicas must be at most 5`),
+		want: []Violation{{
+			Resource: "Deployment/web",
+			Policy:   "replica-limit",
+			Message:  "replicas must be at most 5",
+		}},
+	}, {
+		name: "aggregated by helm",
+		err: errors.New("2 errors occurred:\n" +
+			"\t* admission webhook \"a.example.com\" denied the request: no\n" +
+			"\t* cannot patch \"db\" with kind StatefulSet: admission webhook " +
+			"\"b.example.com\" denied the request: nope\n\n"),
+		want: []Violation{{
+			Webhook: "a.example.com",
+			Message: "no",
+		}, {
+			Resource: "StatefulSet/db",
+			Webhook:  "b.example.com",
+			Message:  "nope",
+		}},
+	}, {
+		name: "not an admission failure",
+		err:  errors.New("timed out waiting for the condition"),
+		want: []Violation{},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			g.Expect(ParseViolations(tt.err)).To(o.Equal(tt.want))
+		})
+	}
+}
+
+func TestSummaryViolations(t *testing.T) {
+	g := o.NewWithT(t)
+
+	s := NewSummary(0)
+	s.Add(Result{Name: "a", Namespace: "ns-a", Status: StatusDeployed})
+	s.Add(Result{
+		Name:      "b",
+		Namespace: "ns-b",
+		Status:    StatusFailed,
+		Err: errors.New(`admission webhook "validation.gatekeeper.sh" ` +
+			`denied the request: [required-labels] missing label "owner"`),
+	})
+	g.Expect(s.Violations()).To(o.Equal([]Violation{{
+		Dependency: "b",
+		Namespace:  "ns-b",
+		Webhook:    "validation.gatekeeper.sh",
+		Policy:     "required-labels",
+		Message:    `missing label "owner"`,
+	}}))
+
+	var out bytes.Buffer
+	s.Print(&out)
+	g.Expect(out.String()).To(o.ContainSubstring("# b (admission-denied)"))
+	g.Expect(out.String()).To(o.ContainSubstring(
+		`  - [required-labels] missing label "owner" ` +
+			`(webhook "validation.gatekeeper.sh")`))
+}
@@ -0,0 +1,91 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+)
+
+// SetAdoptConflicts sets whether the existing resources not owned by the
+// release are adopted, instead of failing the installation.
+func (i *Installer) SetAdoptConflicts(adopt bool) {
+	i.adopt = adopt
+}
+
+// checkConflicts looks for existing resources the rendered manifests would
+// overwrite but aren't owned by the release. The conflicts are reported, and
+// either adopted by the release or fail the installation.
+func (i *Installer) checkConflicts(ctx context.Context, hc *deployer.Helm) error {
+	conflicts, err := hc.Conflicts(ctx, i.values)
+	if err != nil {
+		return fmt.Errorf("checking resources ownership: %w", err)
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	i.logger.Warn("Existing resources are not owned by the release",
+		"conflicts", len(conflicts), "adopt", i.adopt)
+	fmt.Printf("\nResources not owned by the release %q:\n", i.dep.Name())
+	for _, c := range conflicts {
+		fmt.Printf("  - %s\n", c.String())
+	}
+	if !i.adopt {
+		return fmt.Errorf(
+			"%w: %d resource(s) on namespace %q, use --adopt to take them over",
+			deployer.ErrResourceConflict, len(conflicts), i.dep.Namespace())
+	}
+	if i.flags.DryRun {
+		i.logger.Debug("[DRY-RUN] Skipping the adoption of existing resources")
+		return nil
+	}
+	return hc.Adopt(ctx, conflicts)
+}
+
+// SetAdoptReleases sets whether the existing release named after the dependency
+// chart, but not deployed by the installer, is adopted instead of failing the
+// installation.
+func (i *Installer) SetAdoptReleases(adopt bool) {
+	i.adoptReleases = adopt
+}
+
+// Adoption returns the release adopted by the installation, nil when none.
+func (i *Installer) Adoption() *Adoption {
+	return i.adoption
+}
+
+// checkRelease looks for an existing release of the dependency chart not
+// deployed by the installer, the release is either adopted, upgraded and
+// labeled as the installer's own afterwards, or fails the installation.
+func (i *Installer) checkRelease(hc *deployer.Helm) error {
+	foreign, err := hc.ForeignRelease()
+	if err != nil {
+		return fmt.Errorf("checking release ownership: %w", err)
+	}
+	if foreign == nil {
+		return nil
+	}
+	i.logger.Warn("Existing release not deployed by the installer",
+		"release", foreign.Name, "chart", foreign.Chart,
+		"revision", foreign.Revision, "adopt", i.adoptReleases)
+	fmt.Printf("\nRelease not deployed by the installer:\n  - %s\n",
+		foreign.String())
+	if !i.adoptReleases {
+		return fmt.Errorf("%w: %s, use --adopt-releases to take it over",
+			deployer.ErrForeignRelease, foreign)
+	}
+	if i.flags.DryRun {
+		i.logger.Debug("[DRY-RUN] Skipping the adoption of the existing release")
+		return nil
+	}
+	i.adoption = &Adoption{
+		Time:       time.Now().UTC(),
+		Dependency: i.dep.Name(),
+		Namespace:  foreign.Namespace,
+		Chart:      foreign.Chart,
+		Revision:   foreign.Revision,
+		Owner:      foreign.Owner,
+	}
+	return nil
+}
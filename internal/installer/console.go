@@ -0,0 +1,218 @@
+package installer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	operatorv1client "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+	"gopkg.in/yaml.v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ConsoleSetting the installer setting enabling the OpenShift console links and
+// plugins registration after a successful deployment.
+const ConsoleSetting = "openshiftConsole"
+
+// consoleLinkGVR the OpenShift ConsoleLink resource, cluster scoped.
+var consoleLinkGVR = schema.GroupVersionResource{
+	Group:    "console.openshift.io",
+	Version:  "v1",
+	Resource: "consolelinks",
+}
+
+// urlRe matches the first HTTP(S) URL on the release notes.
+var urlRe = regexp.MustCompile(`https?://[^\s"'<>)\]]+`)
+
+// ConsoleEnabled returns whether the configuration enables the OpenShift console
+// integration.
+func ConsoleEnabled(cfg *config.Config) bool {
+	enabled, _ := cfg.Installer.Settings[ConsoleSetting].(bool)
+	return enabled
+}
+
+// ConsoleURL returns the product UI URL, the first URL on the release notes.
+func ConsoleURL(notes string) string {
+	return strings.TrimRight(urlRe.FindString(notes), ".,;:")
+}
+
+// ConsolePlugins returns the ConsolePlugin names shipped on the release
+// manifest.
+func ConsolePlugins(manifest string) ([]string, error) {
+	plugins := []string{}
+	decoder := yaml.NewDecoder(bytes.NewBufferString(manifest))
+	for {
+		var doc struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+		}
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return plugins, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc.Kind == "ConsolePlugin" && doc.Metadata.Name != "" {
+			plugins = append(plugins, doc.Metadata.Name)
+		}
+	}
+}
+
+// Console registers the deployed products on the OpenShift console, creating a
+// ConsoleLink for the product UI and enabling the ConsolePlugins shipped.
+type Console struct {
+	logger  *slog.Logger  // application logger
+	kube    k8s.Interface // kubernetes client
+	appName string        // application name, console menu section
+}
+
+// Supported returns whether the cluster serves the OpenShift console APIs.
+func (c *Console) Supported() bool {
+	dc, err := c.kube.DiscoveryClient("")
+	if err != nil {
+		return false
+	}
+	_, err = dc.ServerResourcesForGroupVersion(
+		consoleLinkGVR.GroupVersion().String())
+	return err == nil
+}
+
+// NewConsoleLink returns the ConsoleLink for the product UI, listed on the
+// application menu section named after the application.
+func (c *Console) NewConsoleLink(
+	product, name, href string,
+) *unstructured.Unstructured {
+	link := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "console.openshift.io/v1",
+		"kind":       "ConsoleLink",
+		"metadata": map[string]any{
+			"name": fmt.Sprintf("%s-%s", c.appName, name),
+		},
+		"spec": map[string]any{
+			"href":     href,
+			"text":     product,
+			"location": "ApplicationMenu",
+			"applicationMenu": map[string]any{
+				"section": c.appName,
+			},
+		},
+	}}
+	link.SetLabels(map[string]string{
+		annotations.ManagedBy:   c.appName,
+		annotations.Application: c.appName,
+		annotations.Chart:       name,
+	})
+	return link
+}
+
+// applyConsoleLink creates or updates the ConsoleLink.
+func (c *Console) applyConsoleLink(
+	ctx context.Context,
+	link *unstructured.Unstructured,
+) error {
+	dynamicClient, err := c.kube.DynamicClient("")
+	if err != nil {
+		return err
+	}
+	client := dynamicClient.Resource(consoleLinkGVR)
+	existing, err := client.Get(ctx, link.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = client.Create(ctx, link, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	link.SetResourceVersion(existing.GetResourceVersion())
+	_, err = client.Update(ctx, link, metav1.UpdateOptions{})
+	return err
+}
+
+// enablePlugins adds the plugins to the cluster Console operator, keeping the
+// plugins already enabled.
+func (c *Console) enablePlugins(ctx context.Context, plugins []string) error {
+	restConfig, err := c.kube.RESTClientGetter("").ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	operatorClient, err := operatorv1client.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	console, err := operatorClient.Consoles().
+		Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, plugin := range plugins {
+		if !slices.Contains(console.Spec.Plugins, plugin) {
+			console.Spec.Plugins = append(console.Spec.Plugins, plugin)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	_, err = operatorClient.Consoles().
+		Update(ctx, console, metav1.UpdateOptions{})
+	return err
+}
+
+// Register creates the ConsoleLink for the product UI found on the release
+// notes, and enables the ConsolePlugins found on the release manifest.
+func (c *Console) Register(
+	ctx context.Context,
+	product, name, notes, manifest string,
+) error {
+	logger := c.logger.With("product", product, "dependency", name)
+
+	if href := ConsoleURL(notes); href != "" {
+		logger.Debug("Applying the ConsoleLink", "href", href)
+		link := c.NewConsoleLink(product, name, href)
+		if err := c.applyConsoleLink(ctx, link); err != nil {
+			return fmt.Errorf("applying ConsoleLink %q: %w",
+				link.GetName(), err)
+		}
+	} else {
+		logger.Debug("No product URL found on the release notes")
+	}
+
+	plugins, err := ConsolePlugins(manifest)
+	if err != nil {
+		return fmt.Errorf("inspecting the release manifest: %w", err)
+	}
+	if len(plugins) == 0 {
+		return nil
+	}
+	logger.Debug("Enabling the ConsolePlugins", "plugins", plugins)
+	if err = c.enablePlugins(ctx, plugins); err != nil {
+		return fmt.Errorf("enabling ConsolePlugins %v: %w", plugins, err)
+	}
+	return nil
+}
+
+// NewConsole instantiates the OpenShift console registration.
+func NewConsole(
+	logger *slog.Logger,
+	kube k8s.Interface,
+	appName string,
+) *Console {
+	return &Console{logger: logger, kube: kube, appName: appName}
+}
@@ -0,0 +1,64 @@
+package installer
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestConsole(t *testing.T) {
+	g := o.NewWithT(t)
+
+	t.Run("ConsoleURL", func(t *testing.T) {
+		g.Expect(ConsoleURL(`
+Product A is deployed, access the console at:
+
+  https://product-a.apps.example.com/dashboard.
+
+Documentation: https://docs.example.com`)).
+			To(o.Equal("https://product-a.apps.example.com/dashboard"))
+		g.Expect(ConsoleURL("Product A is deployed.")).To(o.BeEmpty())
+	})
+
+	t.Run("ConsolePlugins", func(t *testing.T) {
+		plugins, err := ConsolePlugins(`---
+apiVersion: v1
+kind: Service
+metadata:
+  name: product-a
+---
+apiVersion: console.openshift.io/v1
+kind: ConsolePlugin
+metadata:
+  name: product-a-plugin
+`)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(plugins).To(o.Equal([]string{"product-a-plugin"}))
+
+		plugins, err = ConsolePlugins("")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(plugins).To(o.BeEmpty())
+	})
+
+	t.Run("NewConsoleLink", func(t *testing.T) {
+		c := NewConsole(slog.New(slog.NewTextHandler(io.Discard, nil)),
+			k8s.NewFakeKube(), "helmet-ex")
+		link := c.NewConsoleLink(
+			"Product A", "helmet-product-a", "https://product-a.example.com")
+		g.Expect(link.GetName()).To(o.Equal("helmet-ex-helmet-product-a"))
+		g.Expect(link.GetLabels()).
+			To(o.HaveKeyWithValue(annotations.ManagedBy, "helmet-ex"))
+
+		href, _, _ := unstructured.NestedString(link.Object, "spec", "href")
+		g.Expect(href).To(o.Equal("https://product-a.example.com"))
+		section, _, _ := unstructured.NestedString(
+			link.Object, "spec", "applicationMenu", "section")
+		g.Expect(section).To(o.Equal("helmet-ex"))
+	})
+}
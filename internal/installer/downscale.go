@@ -0,0 +1,93 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DownscaleSetting the installer setting applying the downscaling values overlay
+// on the dependencies supporting it. When not set, the overlay is applied on
+// CodeReady Containers, the "crc" setting, and single-node clusters.
+const DownscaleSetting = "downscale"
+
+// crcSetting the application setting telling the cluster is CodeReady
+// Containers, OpenShift Local.
+const crcSetting = "crc"
+
+// DownscaleValues the downscaling values overlay: a single replica, and reduced
+// resource requests, following the "helm create" values conventions.
+func DownscaleValues() chartutil.Values {
+	return chartutil.Values{
+		"replicaCount": 1,
+		"resources": map[string]any{
+			"requests": map[string]any{
+				"cpu":    "10m",
+				"memory": "64Mi",
+			},
+		},
+	}
+}
+
+// singleNode returns whether the cluster has a single node.
+func singleNode(ctx context.Context, kube k8s.Interface) (bool, error) {
+	client, err := kube.CoreV1ClientSet("")
+	if err != nil {
+		return false, err
+	}
+	nodes, err := client.Nodes().List(ctx, metav1.ListOptions{Limit: 2})
+	if err != nil {
+		return false, err
+	}
+	return len(nodes.Items) == 1, nil
+}
+
+// Downscale returns whether the downscaling overlay applies on the cluster, as
+// the "downscale" setting tells, otherwise when the "crc" setting is enabled or
+// the cluster has a single node. Failing to inspect the nodes doesn't downscale.
+func Downscale(
+	ctx context.Context,
+	kube k8s.Interface,
+	cfg *config.Config,
+) (bool, error) {
+	if setting, ok := cfg.Installer.Settings[DownscaleSetting]; ok {
+		downscale, ok := setting.(bool)
+		if !ok {
+			return false, fmt.Errorf("%w: setting %q must be a boolean",
+				config.ErrInvalidConfig, DownscaleSetting)
+		}
+		return downscale, nil
+	}
+	if crc, _ := cfg.Installer.Settings[crcSetting].(bool); crc {
+		return true, nil
+	}
+	single, err := singleNode(ctx, kube)
+	return err == nil && single, nil
+}
+
+// setDownscale selects the downscaling overlay, when the dependency supports it
+// and the cluster calls for it.
+func (i *Installer) setDownscale(ctx context.Context, cfg *config.Config) error {
+	i.downscale = nil
+	supported, err := i.dep.Downscale()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRender, err)
+	}
+	if !supported {
+		return nil
+	}
+	downscale, err := Downscale(ctx, i.kube, cfg)
+	if err != nil {
+		return err
+	}
+	if downscale {
+		i.logger.Debug("Applying the downscaling values overlay")
+		i.downscale = DownscaleValues()
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// Values layers, from the lowest to the highest precedence.
+const (
+	// LayerChartDefault the chart's, and its subcharts', "values.yaml".
+	LayerChartDefault = "chart default"
+	// LayerSizing the chart values preset of the selected sizing profile.
+	LayerSizing = "sizing profile"
+	// LayerDownscale the downscaling values overlay, on single-node clusters.
+	LayerDownscale = "downscaling"
+	// LayerValuesTemplate the rendered installer values template.
+	LayerValuesTemplate = "installer template"
+	// LayerProductProperties the dependency's product properties, consumed by
+	// the installer values template.
+	LayerProductProperties = "product properties"
+)
+
+// ValueLayer the value of a key on a single values layer.
+type ValueLayer struct {
+	Layer string `json:"layer"`
+	Set   bool   `json:"set"`
+	Value any    `json:"value,omitempty"`
+}
+
+// Explanation the provenance of a key on the dependency's final values.
+type Explanation struct {
+	Key    string       `json:"key"`
+	Value  any          `json:"value,omitempty"`
+	Source string       `json:"source,omitempty"` // layer of the final value
+	Layers []ValueLayer `json:"layers"`
+}
+
+// lookupValue returns the value of the dot separated key on the values tree.
+func lookupValue(values map[string]any, key string) (any, bool) {
+	var current any = values
+	for _, name := range strings.Split(key, ".") {
+		var table map[string]any
+		switch t := current.(type) {
+		case map[string]any:
+			table = t
+		case chartutil.Values:
+			table = t
+		default:
+			return nil, false
+		}
+		value, ok := table[name]
+		if !ok {
+			return nil, false
+		}
+		current = value
+	}
+	return current, true
+}
+
+// Explain shows where the key's final value comes from, the values must be
+// rendered beforehand with the same configuration and values template.
+func (i *Installer) Explain(
+	ctx context.Context,
+	cfg *config.Config,
+	valuesTmpl string,
+	key string,
+) (*Explanation, error) {
+	if i.values == nil {
+		return nil, fmt.Errorf("values not set")
+	}
+	defaults, err := chartutil.CoalesceValues(i.dep.Chart(), chartutil.Values{})
+	if err != nil {
+		return nil, err
+	}
+	// The value references are explained unresolved, as printed.
+	final, err := chartutil.CoalesceValues(i.dep.Chart(), i.unresolved)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := chartutil.ReadValues(i.valuesBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Explanation{Key: key}
+	chartValue, chartSet := lookupValue(defaults, key)
+	sizingValue, sizingSet := lookupValue(i.sizing, key)
+	downscaleValue, downscaleSet := lookupValue(i.downscale, key)
+	tmplValue, tmplSet := lookupValue(rendered, key)
+	e.Layers = append(e.Layers,
+		ValueLayer{Layer: LayerChartDefault, Set: chartSet, Value: chartValue},
+		ValueLayer{Layer: LayerSizing, Set: sizingSet, Value: sizingValue},
+		ValueLayer{Layer: LayerDownscale, Set: downscaleSet, Value: downscaleValue},
+		ValueLayer{Layer: LayerValuesTemplate, Set: tmplSet, Value: tmplValue},
+	)
+	properties := ValueLayer{Layer: LayerProductProperties}
+	if tmplSet {
+		if properties.Set, err = i.fromProductProperties(
+			ctx, cfg, valuesTmpl, key, tmplValue,
+		); err != nil {
+			return nil, err
+		}
+		if properties.Set {
+			properties.Value = tmplValue
+		}
+	}
+	e.Layers = append(e.Layers, properties)
+
+	e.Value, _ = lookupValue(final, key)
+	for _, layer := range e.Layers {
+		if layer.Set {
+			e.Source = layer.Layer
+		}
+	}
+	return e, nil
+}
+
+// fromProductProperties renders the values template again without the
+// dependency's product properties, when the key's value changes it comes from
+// the product properties.
+func (i *Installer) fromProductProperties(
+	ctx context.Context,
+	cfg *config.Config,
+	valuesTmpl string,
+	key string,
+	value any,
+) (bool, error) {
+	name := i.dep.ProductName()
+	if name == "" {
+		return false, nil
+	}
+	cp, err := cfg.DeepCopy()
+	if err != nil {
+		return false, err
+	}
+	product, err := cp.GetProduct(name)
+	if err != nil {
+		return false, err
+	}
+	if len(product.Properties) == 0 {
+		return false, nil
+	}
+	// The values template variables are based on the decoded products.
+	product.Properties = map[string]interface{}{}
+
+	without := *i
+	if err = without.SetValues(ctx, cp, valuesTmpl); err != nil {
+		// The template requires the product properties, but not necessarily
+		// for this key, thus the provenance can't be determined.
+		i.logger.Debug("Unable to render values without product properties",
+			"product", name, "error", err)
+		return false, nil
+	}
+	values, err := chartutil.ReadValues(without.valuesBytes)
+	if err != nil {
+		return false, err
+	}
+	withoutValue, ok := lookupValue(values, key)
+	return !ok || !reflect.DeepEqual(withoutValue, value), nil
+}
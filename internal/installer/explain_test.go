@@ -0,0 +1,90 @@
+package installer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestInstallerExplain(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := config.NewConfigFromFile(
+		cfs, "config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+
+	hc := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name: "test-chart",
+			Annotations: map[string]string{
+				annotations.ProductName: "Product B",
+			},
+		},
+		Values: map[string]any{
+			"replicas": 1,
+			"storage":  map[string]any{"size": "1Gi", "class": "default"},
+		},
+	}
+	valuesTmpl := `
+replicas: 2
+storage:
+  class: {{ .Installer.Products.Product_B.Properties.storageClass }}
+`
+	i := NewInstaller(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		flags.NewFlags(),
+		k8s.NewFakeKube(),
+		resolver.NewDependencyWithNamespace(hc, "test-ns"),
+		nil,
+	)
+	ctx := context.Background()
+	g.Expect(i.SetValues(ctx, cfg, valuesTmpl)).To(o.Succeed())
+	g.Expect(i.RenderValues(ctx)).To(o.Succeed())
+
+	t.Run("ChartDefault", func(t *testing.T) {
+		g := o.NewWithT(t)
+		e, err := i.Explain(ctx, cfg, valuesTmpl, "storage.size")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(e.Value).To(o.Equal("1Gi"))
+		g.Expect(e.Source).To(o.Equal(LayerChartDefault))
+	})
+
+	t.Run("ValuesTemplate", func(t *testing.T) {
+		g := o.NewWithT(t)
+		e, err := i.Explain(ctx, cfg, valuesTmpl, "replicas")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(e.Value).To(o.BeEquivalentTo(2))
+		g.Expect(e.Source).To(o.Equal(LayerValuesTemplate))
+		g.Expect(e.Layers[0]).To(o.Equal(
+			ValueLayer{Layer: LayerChartDefault, Set: true, Value: 1}))
+	})
+
+	t.Run("ProductProperties", func(t *testing.T) {
+		g := o.NewWithT(t)
+		e, err := i.Explain(ctx, cfg, valuesTmpl, "storage.class")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(e.Value).To(o.Equal("standard"))
+		g.Expect(e.Source).To(o.Equal(LayerProductProperties))
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		g := o.NewWithT(t)
+		e, err := i.Explain(ctx, cfg, valuesTmpl, "storage.missing")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(e.Value).To(o.BeNil())
+		g.Expect(e.Source).To(o.BeEmpty())
+	})
+}
@@ -0,0 +1,151 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+	"github.com/redhat-appstudio/helmet/internal/monitor"
+	"github.com/redhat-appstudio/helmet/internal/scan"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// FailureClass classifies a dependency deployment failure.
+type FailureClass string
+
+const (
+	// FailureRender the values template or the chart templates don't render.
+	FailureRender FailureClass = "render-error"
+	// FailureHook a Helm hook, or chart test, failed.
+	FailureHook FailureClass = "hook-failure"
+	// FailureTimeout resources aren't ready within the timeout.
+	FailureTimeout FailureClass = "timeout"
+	// FailureAPI the Kubernetes API rejected the request.
+	FailureAPI FailureClass = "api-rejection"
+	// FailureAdmission an admission webhook, or policy, denied a resource.
+	FailureAdmission FailureClass = "admission-denied"
+	// FailurePolicy the rendered manifests violate the enforced security policy.
+	FailurePolicy FailureClass = "policy-violation"
+	// FailureConflict existing resources aren't owned by the release, or the
+	// release itself wasn't deployed by the installer.
+	FailureConflict FailureClass = "resource-conflict"
+	// FailureUnknown the failure doesn't match any known class.
+	FailureUnknown FailureClass = "unknown"
+)
+
+var (
+	// ErrRender the values template or the chart values can't be rendered.
+	ErrRender = helmeterrors.New(helmeterrors.ErrDeployFailed, "render error")
+	// ErrValuesSchema the rendered values violate the chart values schema.
+	ErrValuesSchema = helmeterrors.New(helmeterrors.ErrDeployFailed,
+		"values don't match the chart schema")
+	// ErrGitOpsValues the values would disclose the sensitive configuration, or
+	// the referenced Secrets, on the manifests emitted for the GitOps repository.
+	ErrGitOpsValues = helmeterrors.New(helmeterrors.ErrInvalidConfig,
+		"values can't be emitted for GitOps")
+)
+
+// Helm flattens most errors into strings, the markers below identify the
+// failure class from the error message when the error chain is lost.
+var (
+	renderMarkers = []string{
+		"template: ",
+		"parse error",
+		"YAML parse error",
+		"error converting YAML",
+		"execution error at",
+	}
+	hookMarkers = []string{
+		"failed pre-install",
+		"failed post-install",
+		"pre-upgrade hooks failed",
+		"post-upgrade hooks failed",
+		"warning: Hook ",
+	}
+	timeoutMarkers = []string{
+		"timed out waiting",
+		"context deadline exceeded",
+	}
+	apiMarkers = []string{
+		"is invalid",
+		"is forbidden",
+		"unable to build kubernetes objects",
+		"already exists",
+		"no matches for kind",
+		"Unauthorized",
+	}
+	transientMarkers = []string{
+		"etcdserver: request timed out",
+		"the server is currently unable to handle the request",
+		"connection refused",
+		"TLS handshake timeout",
+		"the object has been modified",
+	}
+)
+
+// containsAny checks whether the message contains any of the markers.
+func containsAny(msg string, markers []string) bool {
+	for _, m := range markers {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassifyFailure returns the failure class of the dependency deployment error.
+func ClassifyFailure(err error) FailureClass {
+	if err == nil {
+		return ""
+	}
+	msg := err.Error()
+	var status apierrors.APIStatus
+	switch {
+	case errors.Is(err, scan.ErrPolicyViolation):
+		return FailurePolicy
+	case errors.Is(err, deployer.ErrResourceConflict),
+		errors.Is(err, deployer.ErrForeignRelease):
+		return FailureConflict
+	case isAdmissionDenied(msg):
+		return FailureAdmission
+	case errors.Is(err, ErrRender) || errors.Is(err, ErrValuesSchema) ||
+		containsAny(msg, renderMarkers):
+		return FailureRender
+	case errors.Is(err, deployer.ErrVerifyFailed) || containsAny(msg, hookMarkers):
+		return FailureHook
+	case errors.Is(err, monitor.ErrTimeout) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		containsAny(msg, timeoutMarkers):
+		return FailureTimeout
+	case errors.As(err, &status) || containsAny(msg, apiMarkers) ||
+		containsAny(msg, transientMarkers):
+		return FailureAPI
+	default:
+		return FailureUnknown
+	}
+}
+
+// IsRetryable checks whether the failure may succeed on a new attempt: transient
+// API errors are retried, and timeouts only when informed, since a release timing
+// out already waited the whole Helm timeout. Render errors, hook failures,
+// admission denials, policy violations, resource conflicts and API rejections
+// are not.
+func IsRetryable(err error, timeouts bool) bool {
+	switch ClassifyFailure(err) {
+	case FailureTimeout:
+		return timeouts
+	case FailureAPI:
+		return apierrors.IsServerTimeout(err) ||
+			apierrors.IsTimeout(err) ||
+			apierrors.IsTooManyRequests(err) ||
+			apierrors.IsServiceUnavailable(err) ||
+			apierrors.IsInternalError(err) ||
+			apierrors.IsConflict(err) ||
+			containsAny(err.Error(), transientMarkers)
+	default:
+		return false
+	}
+}
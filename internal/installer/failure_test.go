@@ -0,0 +1,127 @@
+package installer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+	"github.com/redhat-appstudio/helmet/internal/monitor"
+	"github.com/redhat-appstudio/helmet/internal/scan"
+
+	o "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	configMaps := schema.GroupResource{Resource: "configmaps"}
+	tests := []struct {
+		name      string
+		err       error
+		class     FailureClass
+		retryable bool
+	}{{
+		name:  "values template",
+		err:   fmt.Errorf("%w: bad template", ErrRender),
+		class: FailureRender,
+	}, {
+		name: "chart template",
+		err: fmt.Errorf("%w: %w", deployer.ErrInstallFailed, errors.New(
+			`template: chart/templates/cm.yaml:3:4: executing "x"`)),
+		class: FailureRender,
+	}, {
+		name: "post-install hook",
+		err: fmt.Errorf("%w: %w", deployer.ErrInstallFailed, errors.New(
+			"failed post-install: timed out waiting for the condition")),
+		class: FailureHook,
+	}, {
+		name:  "chart tests",
+		err:   fmt.Errorf("%w: %w", deployer.ErrVerifyFailed, errors.New("pod failed")),
+		class: FailureHook,
+	}, {
+		name:  "monitor timeout",
+		err:   fmt.Errorf("waiting for release resources: %w", monitor.ErrTimeout),
+		class: FailureTimeout,
+	}, {
+		name:  "rejected by the API",
+		err:   apierrors.NewForbidden(configMaps, "test", errors.New("denied")),
+		class: FailureAPI,
+	}, {
+		name: "denied by an admission webhook",
+		err: fmt.Errorf("%w: %w", deployer.ErrInstallFailed, errors.New(
+			`admission webhook "validation.gatekeeper.sh" denied the request: `+
+				`[required-labels] missing label "owner"`)),
+		class: FailureAdmission,
+	}, {
+		name:  "security policy enforced",
+		err:   fmt.Errorf("%w: 2 finding(s)", scan.ErrPolicyViolation),
+		class: FailurePolicy,
+	}, {
+		name: "resources not owned by the release",
+		err: fmt.Errorf("%w: 1 resource(s) on namespace %q",
+			deployer.ErrResourceConflict, "test"),
+		class: FailureConflict,
+	}, {
+		name: "release not deployed by the installer",
+		err: fmt.Errorf("%w: test/helmet-product-a",
+			deployer.ErrForeignRelease),
+		class: FailureConflict,
+	}, {
+		name:      "transient API error",
+		err:       apierrors.NewTooManyRequests("slow down", 1),
+		class:     FailureAPI,
+		retryable: true,
+	}, {
+		name:  "unknown",
+		err:   errors.New("something else"),
+		class: FailureUnknown,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			g.Expect(ClassifyFailure(tt.err)).To(o.Equal(tt.class))
+			g.Expect(IsRetryable(tt.err, false)).To(o.Equal(tt.retryable))
+			// Timeouts are only retried when asked to.
+			g.Expect(IsRetryable(tt.err, true)).
+				To(o.Equal(tt.retryable || tt.class == FailureTimeout))
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	g := o.NewWithT(t)
+
+	s := NewSummary(1)
+	g.Expect(s.Retry()).To(o.BeTrue())
+	g.Expect(s.Retry()).To(o.BeFalse())
+
+	s.Add(Result{Name: "a", Status: StatusDeployed, Attempts: 1})
+	g.Expect(s.Err()).To(o.Succeed())
+
+	s.Add(Result{Name: "b", Status: StatusRetried, Attempts: 2})
+	s.Add(Result{
+		Name:     "c",
+		Status:   StatusFailed,
+		Attempts: 1,
+		Err:      fmt.Errorf("%w: bad template", ErrRender),
+	})
+	s.Add(Result{Name: "d", Status: StatusSkipped, Err: errors.New("c failed")})
+	g.Expect(s.Err()).To(o.MatchError(ErrDeployFailed))
+	g.Expect(s.Err()).To(o.MatchError(o.ContainSubstring("1 failed, 1 skipped")))
+
+	var out bytes.Buffer
+	s.Print(&out)
+	g.Expect(out.String()).To(o.ContainSubstring(
+		"Deployed: 1, Retried: 1, Failed: 1, Skipped: 1"))
+	g.Expect(out.String()).To(o.ContainSubstring("Retry budget: 1 of 1 used"))
+	g.Expect(out.String()).To(o.ContainSubstring("# c (render-error)"))
+	g.Expect(out.String()).NotTo(o.ContainSubstring("Emitted"))
+
+	s.Add(Result{Name: "e", Status: StatusEmitted, Attempts: 1})
+	out.Reset()
+	s.Print(&out)
+	g.Expect(out.String()).To(o.ContainSubstring("Emitted for GitOps: 1"))
+}
@@ -0,0 +1,67 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+)
+
+// ErrUnhealthy one or more dependency releases need to be repaired.
+var ErrUnhealthy = helmeterrors.New(helmeterrors.ErrDeployFailed,
+	"unhealthy releases")
+
+// Health the health of a dependency release, as checked before repairing.
+type Health struct {
+	Dependency string   `json:"dependency"`        // dependency name
+	Namespace  string   `json:"namespace"`         // release namespace
+	Reasons    []string `json:"reasons,omitempty"` // why it's unhealthy
+}
+
+// Healthy returns whether the release has no reason to be repaired.
+func (h *Health) Healthy() bool {
+	return len(h.Reasons) == 0
+}
+
+// Health inspects the dependency release against the rendered values, thus the
+// values must be rendered first, see RenderValues.
+func (i *Installer) Health(ctx context.Context) (*Health, error) {
+	if i.values == nil {
+		return nil, fmt.Errorf("values not set")
+	}
+	hc, err := i.helmClient()
+	if err != nil {
+		return nil, err
+	}
+	reasons, err := hc.Health(ctx, i.values)
+	if err != nil {
+		return nil, fmt.Errorf("checking release health: %w", err)
+	}
+	return &Health{
+		Dependency: i.dep.Name(),
+		Namespace:  i.dep.Namespace(),
+		Reasons:    reasons,
+	}, nil
+}
+
+// RepairSet returns the dependencies to redeploy, in deployment order: the
+// unhealthy ones and their downstream dependents, directly or transitively.
+func RepairSet(
+	deps resolver.Dependencies,
+	unhealthy map[string]bool,
+) resolver.Dependencies {
+	selected := map[string]bool{}
+	repair := resolver.Dependencies{}
+	for _, dep := range deps {
+		include := unhealthy[dep.Name()]
+		for _, name := range dep.DependsOn() {
+			include = include || selected[name]
+		}
+		if include {
+			selected[dep.Name()] = true
+			repair = append(repair, dep)
+		}
+	}
+	return repair
+}
@@ -0,0 +1,56 @@
+package installer
+
+import (
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestRepairSet(t *testing.T) {
+	dep := func(name, dependsOn string) resolver.Dependency {
+		return *resolver.NewDependencyWithNamespace(&chart.Chart{
+			Metadata: &chart.Metadata{
+				Name: name,
+				Annotations: map[string]string{
+					annotations.DependsOn: dependsOn,
+				},
+			},
+		}, "test-ns")
+	}
+	deps := resolver.Dependencies{
+		dep("operators", ""),
+		dep("storage", ""),
+		dep("product-a", "operators"),
+		dep("product-b", "product-a, storage"),
+		dep("product-c", "storage"),
+	}
+	names := func(deps resolver.Dependencies) []string {
+		s := []string{}
+		for _, d := range deps {
+			s = append(s, d.Name())
+		}
+		return s
+	}
+
+	t.Run("healthy", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(RepairSet(deps, map[string]bool{})).To(o.BeEmpty())
+	})
+
+	t.Run("downstream dependents", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(names(RepairSet(deps, map[string]bool{"operators": true}))).
+			To(o.Equal([]string{"operators", "product-a", "product-b"}))
+		g.Expect(names(RepairSet(deps, map[string]bool{"product-c": true}))).
+			To(o.Equal([]string{"product-c"}))
+	})
+
+	g := o.NewWithT(t)
+	g.Expect((&Health{}).Healthy()).To(o.BeTrue())
+	g.Expect((&Health{Reasons: []string{"release status \"failed\""}}).Healthy()).
+		To(o.BeFalse())
+}
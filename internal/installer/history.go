@@ -0,0 +1,253 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HistoryKey the history ConfigMap data key holding the durations, as a YAML
+// mapping of the dependency name to its durations, oldest first.
+const HistoryKey = "durations.yaml"
+
+// HistorySize the number of durations kept per dependency.
+const HistorySize = 5
+
+// RepairsKey the history ConfigMap data key holding the repairs, as a YAML list,
+// oldest first.
+const RepairsKey = "repairs.yaml"
+
+// RepairsSize the number of repairs kept.
+const RepairsSize = 10
+
+// Repair a run of the "repair" subcommand, the unhealthy dependencies found and
+// the ones redeployed.
+type Repair struct {
+	Time       time.Time           `yaml:"time"`            // repair start
+	Unhealthy  map[string][]string `yaml:"unhealthy"`       // reasons by dependency
+	Redeployed []string            `yaml:"redeployed"`      // in deployment order
+	Error      string              `yaml:"error,omitempty"` // repair failure
+}
+
+// AdoptionsKey the history ConfigMap data key holding the adopted releases, as
+// a YAML list, oldest first.
+const AdoptionsKey = "adoptions.yaml"
+
+// AdoptionsSize the number of adoptions kept.
+const AdoptionsSize = 10
+
+// Adoption a pre-existing Helm release, not deployed by the installer, taken
+// over by the deployment with "--adopt-releases".
+type Adoption struct {
+	Time       time.Time `yaml:"time"`       // adoption time
+	Dependency string    `yaml:"dependency"` // dependency name
+	Namespace  string    `yaml:"namespace"`  // release namespace
+	Chart      string    `yaml:"chart"`      // "name-version" found on the release
+	Revision   int       `yaml:"revision"`   // release revision adopted
+	Owner      string    `yaml:"owner"`      // previous owner description
+}
+
+// History the durations of the past successful deployments, per dependency,
+// persisted in a ConfigMap on the installer namespace across runs.
+type History struct {
+	kube      k8s.Interface              // kubernetes client
+	namespace string                     // installer namespace
+	name      string                     // configmap name
+	managedBy string                     // application name
+	durations map[string][]time.Duration // durations by dependency name
+	repairs   []Repair                   // past repairs, oldest first
+	adoptions []Adoption                 // adopted releases, oldest first
+	runs      []Run                      // deploy runs, oldest first
+}
+
+// HistoryName returns the name of the history ConfigMap for the application.
+func HistoryName(appName string) string {
+	return fmt.Sprintf("%s-deploy-history", appName)
+}
+
+// Load reads the durations, repairs, adoptions and deploy runs from the cluster,
+// a missing ConfigMap is an empty history.
+func (h *History) Load(ctx context.Context) error {
+	coreClient, err := h.kube.CoreV1ClientSet(h.namespace)
+	if err != nil {
+		return err
+	}
+	cm, err := coreClient.ConfigMaps(h.namespace).
+		Get(ctx, h.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	stored := map[string][]string{}
+	if err = yaml.Unmarshal([]byte(cm.Data[HistoryKey]), &stored); err != nil {
+		return fmt.Errorf("configmap %s/%s: invalid %q: %w",
+			h.namespace, h.name, HistoryKey, err)
+	}
+	for name, values := range stored {
+		for _, v := range values {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("configmap %s/%s: dependency %q: %w",
+					h.namespace, h.name, name, err)
+			}
+			h.durations[name] = append(h.durations[name], d)
+		}
+	}
+	if err = yaml.Unmarshal([]byte(cm.Data[RepairsKey]), &h.repairs); err != nil {
+		return fmt.Errorf("configmap %s/%s: invalid %q: %w",
+			h.namespace, h.name, RepairsKey, err)
+	}
+	if err = yaml.Unmarshal([]byte(cm.Data[AdoptionsKey]), &h.adoptions); err != nil {
+		return fmt.Errorf("configmap %s/%s: invalid %q: %w",
+			h.namespace, h.name, AdoptionsKey, err)
+	}
+	if err = yaml.Unmarshal([]byte(cm.Data[RunsKey]), &h.runs); err != nil {
+		return fmt.Errorf("configmap %s/%s: invalid %q: %w",
+			h.namespace, h.name, RunsKey, err)
+	}
+	return nil
+}
+
+// Expected returns the usual duration of the dependency deployment, the median
+// of the recorded durations, zero when the dependency has no history.
+func (h *History) Expected(name string) time.Duration {
+	durations := slices.Clone(h.durations[name])
+	if len(durations) == 0 {
+		return 0
+	}
+	slices.Sort(durations)
+	return durations[len(durations)/2]
+}
+
+// Record appends the duration of a successful deployment, keeping the latest
+// HistorySize durations. Failed and skipped results are ignored.
+func (h *History) Record(r Result) {
+	if r.Status != StatusDeployed && r.Status != StatusRetried {
+		return
+	}
+	durations := append(h.durations[r.Name], r.Duration.Round(time.Second))
+	if len(durations) > HistorySize {
+		durations = durations[len(durations)-HistorySize:]
+	}
+	h.durations[r.Name] = durations
+}
+
+// RecordRepair appends the repair, keeping the latest RepairsSize repairs.
+func (h *History) RecordRepair(r Repair) {
+	h.repairs = append(h.repairs, r)
+	if len(h.repairs) > RepairsSize {
+		h.repairs = h.repairs[len(h.repairs)-RepairsSize:]
+	}
+}
+
+// Repairs returns the recorded repairs, oldest first.
+func (h *History) Repairs() []Repair {
+	return h.repairs
+}
+
+// RecordAdoption appends the adopted release, keeping the latest AdoptionsSize
+// adoptions.
+func (h *History) RecordAdoption(a Adoption) {
+	h.adoptions = append(h.adoptions, a)
+	if len(h.adoptions) > AdoptionsSize {
+		h.adoptions = h.adoptions[len(h.adoptions)-AdoptionsSize:]
+	}
+}
+
+// Adoptions returns the recorded adoptions, oldest first.
+func (h *History) Adoptions() []Adoption {
+	return h.adoptions
+}
+
+// Save creates or updates the history ConfigMap with the recorded durations,
+// repairs, adoptions and deploy runs.
+func (h *History) Save(ctx context.Context) error {
+	stored := map[string][]string{}
+	for name, durations := range h.durations {
+		for _, d := range durations {
+			stored[name] = append(stored[name], d.String())
+		}
+	}
+	payload, err := yaml.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	data := map[string]string{HistoryKey: string(payload)}
+	if len(h.repairs) > 0 {
+		if payload, err = yaml.Marshal(h.repairs); err != nil {
+			return err
+		}
+		data[RepairsKey] = string(payload)
+	}
+	if len(h.adoptions) > 0 {
+		if payload, err = yaml.Marshal(h.adoptions); err != nil {
+			return err
+		}
+		data[AdoptionsKey] = string(payload)
+	}
+	if len(h.runs) > 0 {
+		if payload, err = yaml.Marshal(h.runs); err != nil {
+			return err
+		}
+		data[RunsKey] = string(payload)
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      h.name,
+			Namespace: h.namespace,
+			Labels: map[string]string{
+				annotations.ManagedBy:   h.managedBy,
+				annotations.Application: h.managedBy,
+			},
+		},
+		Data: data,
+	}
+	coreClient, err := h.kube.CoreV1ClientSet(h.namespace)
+	if err != nil {
+		return err
+	}
+	configMaps := coreClient.ConfigMaps(h.namespace)
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+	}
+	return err
+}
+
+// DescribeExpected describes the usual deployment duration, rounded to minutes
+// when longer than one, for instance "'helmet-operators' usually takes ~4m".
+func DescribeExpected(name string, expected time.Duration) string {
+	return fmt.Sprintf("'%s' usually takes ~%s", name, roughDuration(expected))
+}
+
+// roughDuration formats the duration in whole minutes, or seconds when shorter
+// than a minute.
+func roughDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Round(time.Second).Seconds()))
+	}
+	return fmt.Sprintf("%dm", int(d.Round(time.Minute).Minutes()))
+}
+
+// NewHistory instantiates the deployment history of the application, stored on
+// the installer namespace.
+func NewHistory(kube k8s.Interface, namespace, appName string) *History {
+	return &History{
+		kube:      kube,
+		namespace: namespace,
+		name:      HistoryName(appName),
+		managedBy: appName,
+		durations: map[string][]time.Duration{},
+	}
+}
@@ -0,0 +1,143 @@
+package installer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHistory(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	t.Run("Record", func(t *testing.T) {
+		g := o.NewWithT(t)
+		h := NewHistory(k8s.NewFakeKube(), "installer", "helmet-ex")
+		g.Expect(h.Expected("operators")).To(o.BeZero())
+
+		for _, minutes := range []int{9, 3, 4, 5, 4, 6} {
+			h.Record(Result{
+				Name:     "operators",
+				Status:   StatusDeployed,
+				Duration: time.Duration(minutes) * time.Minute,
+			})
+		}
+		// Failures don't count, only the latest durations are kept.
+		h.Record(Result{
+			Name:     "operators",
+			Status:   StatusFailed,
+			Duration: time.Hour,
+		})
+		g.Expect(h.durations["operators"]).To(o.HaveLen(HistorySize))
+		g.Expect(h.Expected("operators")).To(o.Equal(4 * time.Minute))
+		g.Expect(DescribeExpected("operators", h.Expected("operators"))).
+			To(o.Equal("'operators' usually takes ~4m"))
+		g.Expect(roughDuration(42 * time.Second)).To(o.Equal("42s"))
+	})
+
+	t.Run("Load", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      HistoryName("helmet-ex"),
+				Namespace: "installer",
+			},
+			Data: map[string]string{
+				HistoryKey: "operators:\n- 4m0s\n- 2m0s\n- 5m0s\n",
+			},
+		}
+		h := NewHistory(k8s.NewFakeKube(cm), "installer", "helmet-ex")
+		g.Expect(h.Load(ctx)).To(o.Succeed())
+		g.Expect(h.Expected("operators")).To(o.Equal(4 * time.Minute))
+
+		// Missing history is empty, saving creates it.
+		h = NewHistory(k8s.NewFakeKube(), "installer", "helmet-ex")
+		g.Expect(h.Load(ctx)).To(o.Succeed())
+		h.Record(Result{Name: "a", Status: StatusRetried, Duration: time.Minute})
+		g.Expect(h.Save(ctx)).To(o.Succeed())
+
+		cm.Data[HistoryKey] = "operators: [soon]\n"
+		h = NewHistory(k8s.NewFakeKube(cm), "installer", "helmet-ex")
+		g.Expect(h.Load(ctx)).ToNot(o.Succeed())
+	})
+
+	t.Run("Repairs", func(t *testing.T) {
+		g := o.NewWithT(t)
+		kube := k8s.NewFakeKube(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      HistoryName("helmet-ex"),
+				Namespace: "installer",
+			},
+			Data: map[string]string{
+				HistoryKey: "operators:\n- 4m0s\n",
+				RepairsKey: "- time: 2026-01-02T03:04:05Z\n" +
+					"  unhealthy:\n    operators: [release status \"failed\"]\n" +
+					"  redeployed: [operators]\n",
+			},
+		})
+		h := NewHistory(kube, "installer", "helmet-ex")
+		g.Expect(h.Load(ctx)).To(o.Succeed())
+		g.Expect(h.Repairs()).To(o.HaveLen(1))
+		g.Expect(h.Repairs()[0].Redeployed).To(o.Equal([]string{"operators"}))
+
+		for range RepairsSize {
+			h.RecordRepair(Repair{Redeployed: []string{"product-a"}})
+		}
+		g.Expect(h.Repairs()).To(o.HaveLen(RepairsSize))
+		g.Expect(h.Repairs()[0].Redeployed).To(o.Equal([]string{"product-a"}))
+	})
+
+	t.Run("Adoptions", func(t *testing.T) {
+		g := o.NewWithT(t)
+		kube := k8s.NewFakeKube(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      HistoryName("helmet-ex"),
+				Namespace: "installer",
+			},
+			Data: map[string]string{
+				AdoptionsKey: "- time: 2026-01-02T03:04:05Z\n" +
+					"  dependency: operators\n" +
+					"  namespace: helmet-operators\n" +
+					"  chart: operators-1.0.0\n" +
+					"  revision: 3\n" +
+					"  owner: not deployed by \"helmet-ex\"\n",
+			},
+		})
+		h := NewHistory(kube, "installer", "helmet-ex")
+		g.Expect(h.Load(ctx)).To(o.Succeed())
+		g.Expect(h.Adoptions()).To(o.HaveLen(1))
+		g.Expect(h.Adoptions()[0].Chart).To(o.Equal("operators-1.0.0"))
+		g.Expect(h.Adoptions()[0].Revision).To(o.Equal(3))
+
+		for range AdoptionsSize {
+			h.RecordAdoption(Adoption{Dependency: "product-a"})
+		}
+		g.Expect(h.Adoptions()).To(o.HaveLen(AdoptionsSize))
+		g.Expect(h.Adoptions()[0].Dependency).To(o.Equal("product-a"))
+		g.Expect(h.Save(ctx)).To(o.Succeed())
+	})
+
+	t.Run("Summary", func(t *testing.T) {
+		g := o.NewWithT(t)
+		s := NewSummary(0)
+		s.Add(Result{
+			Name:     "operators",
+			Status:   StatusDeployed,
+			Attempts: 1,
+			Duration: 3*time.Minute + 50*time.Second,
+			Expected: 4 * time.Minute,
+		})
+		var out bytes.Buffer
+		s.Print(&out)
+		g.Expect(out.String()).To(o.MatchRegexp(`3m50s\s+~4m`))
+	})
+
+	g.Expect(HistoryName("helmet-ex")).To(o.Equal("helmet-ex-deploy-history"))
+}
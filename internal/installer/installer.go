@@ -1,20 +1,27 @@
 package installer
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"html/template"
 	"log/slog"
+	"strings"
 
+	"github.com/redhat-appstudio/helmet/internal/annotations"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/deployer"
 	"github.com/redhat-appstudio/helmet/internal/engine"
 	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integration"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 	"github.com/redhat-appstudio/helmet/internal/monitor"
 	"github.com/redhat-appstudio/helmet/internal/printer"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
+	"github.com/redhat-appstudio/helmet/internal/scan"
 
 	"helm.sh/helm/v3/pkg/chartutil"
+	"k8s.io/apimachinery/pkg/api/meta"
 )
 
 // Installer represents the "helm install" using its APIs, this component deploys
@@ -25,9 +32,33 @@ type Installer struct {
 	kube   k8s.Interface        // kubernetes client
 	dep    *resolver.Dependency // dependency to install
 
-	valuesBytes      []byte           // rendered values
-	values           chartutil.Values // helm chart values
-	installerTarball []byte           // embedded installer tarball
+	valuesBytes      []byte                   // rendered values
+	values           chartutil.Values         // helm chart values
+	unresolved       chartutil.Values         // values keeping the value references
+	installerTarball []byte                   // embedded installer tarball
+	valuesContext    map[string]any           // application provided values context
+	sizing           chartutil.Values         // sizing profile values preset
+	downscale        chartutil.Values         // downscaling values overlay
+	valueRefs        config.ValueRefAllowlist // application allowlisted value references
+	narrowedRefs     config.ValueRefAllowlist // value references narrowed by the config
+	sensitive        config.SensitiveFields   // sensitive configuration fields
+	valuesFrom       []deployer.ValuesFrom    // value references left to Flux
+	managedBy        string                   // application name owning the resources
+	replicator       *integration.Replicator  // integration secrets replicator
+	namespaceLabels  map[string]string        // product namespace labels
+	policy           *scan.Policy             // security policy gate
+	adopt            bool                     // adopt resources not owned by the release
+	adoptReleases    bool                     // adopt releases not deployed by the installer
+	adoption         *Adoption                // release adopted by the installation
+	monitorOpts      monitor.Options          // release status check settings
+	rehearsal        meta.RESTMapper          // kinds scope, on rehearsals
+	executor         deployer.ExecutorOptions // deploy executor
+}
+
+// gitOps checks whether the release is emitted for the GitOps repository, the
+// values must not carry the sensitive configuration, nor the referenced Secrets.
+func (i *Installer) gitOps() bool {
+	return i.executor.Name == deployer.ExecutorFlux
 }
 
 // SetValues prepares the values template for the Helm chart installation.
@@ -36,19 +67,107 @@ func (i *Installer) SetValues(
 	cfg *config.Config,
 	valuesTmpl string,
 ) error {
+	var products []config.Product
+	var err error
+	if i.gitOps() {
+		// The sensitive fields are rendered redacted, and the product value
+		// references can't be told apart once rendered, thus they are refused.
+		if paths := cfg.ProductValueFromPaths(); len(paths) > 0 {
+			return fmt.Errorf("%w: value references on the product "+
+				"properties, use the values template references instead: %s",
+				ErrGitOpsValues, strings.Join(paths, ", "))
+		}
+		if cfg, _, err = i.sensitive.Redact(cfg); err != nil {
+			return err
+		}
+		products = cfg.Installer.Products
+	} else {
+		i.logger.Debug("Resolving the product properties value references")
+		if products, err = cfg.ResolveProducts(ctx, i.kube); err != nil {
+			return err
+		}
+	}
+
 	i.logger.Debug("Preparing values template context")
 	variables := engine.NewVariables()
-	err := variables.SetInstaller(cfg)
-	if err != nil {
+	if err = variables.SetInstaller(cfg); err != nil {
+		return err
+	}
+	if err = variables.SetProducts(products); err != nil {
 		return err
 	}
 	if err = variables.SetOpenShift(ctx, i.kube); err != nil {
 		return err
 	}
+	if err = variables.SetContext(i.valuesContext); err != nil {
+		return err
+	}
 
 	i.logger.Debug("Rendering values template")
 	i.valuesBytes, err = engine.NewEngine(i.kube, valuesTmpl).Render(variables)
-	return err
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRender, err)
+	}
+	if i.gitOps() && i.rendersSensitive() {
+		return fmt.Errorf("%w: the values template renders sensitive "+
+			"configuration fields", ErrGitOpsValues)
+	}
+	if i.narrowedRefs, err = cfg.ValueRefAllowlist(); err != nil {
+		return err
+	}
+	if err = i.setSizing(cfg); err != nil {
+		return err
+	}
+	return i.setDownscale(ctx, cfg)
+}
+
+// rendersSensitive checks whether the rendered values carry the redacted
+// sensitive fields, the template engine escapes the placeholder.
+func (i *Installer) rendersSensitive() bool {
+	for _, placeholder := range []string{
+		config.SensitivePlaceholder,
+		template.HTMLEscapeString(config.SensitivePlaceholder),
+	} {
+		if bytes.Contains(i.valuesBytes, []byte(placeholder)) {
+			return true
+		}
+	}
+	return false
+}
+
+// setSizing selects the chart values preset for the sizing profile of the
+// dependency, the product's or the global profile.
+func (i *Installer) setSizing(cfg *config.Config) error {
+	profile, err := cfg.Sizing(i.dep.ProductName())
+	if err != nil {
+		return err
+	}
+	if i.sizing, err = i.dep.SizingPreset(profile); err != nil {
+		return fmt.Errorf("%w: %w", ErrRender, err)
+	}
+	if profile != "" && i.sizing == nil {
+		i.logger.Debug("The chart doesn't declare the sizing profile",
+			"sizing", profile)
+	}
+	return nil
+}
+
+// SetValuesContext sets the application provided values, available as ".Context"
+// in the values template.
+func (i *Installer) SetValuesContext(values map[string]any) {
+	i.valuesContext = values
+}
+
+// SetValueRefAllowlist sets the value references the application allows, the
+// configuration may only narrow them.
+func (i *Installer) SetValueRefAllowlist(allowlist config.ValueRefAllowlist) {
+	i.valueRefs = allowlist
+}
+
+// SetSensitiveFields sets the configuration fields holding sensitive values,
+// kept out of the manifests emitted for GitOps.
+func (i *Installer) SetSensitiveFields(fields config.SensitiveFields) {
+	i.sensitive = fields
 }
 
 // PrintRawValues prints the raw values template to the console.
@@ -57,30 +176,205 @@ func (i *Installer) PrintRawValues() {
 	fmt.Printf("#\n# Values (Raw)\n#\n\n%s\n", i.valuesBytes)
 }
 
-// RenderValues parses the values template and prepares the Helm chart values.
-func (i *Installer) RenderValues() error {
+// RenderValues parses the values template and prepares the Helm chart values,
+// on top of the downscaling overlay and the sizing preset, validating them against the chart schema.
+func (i *Installer) RenderValues(ctx context.Context) error {
 	if i.valuesBytes == nil {
 		return fmt.Errorf("values not set")
 	}
 
 	i.logger.Debug("Preparing rendered values for Helm installation")
 	var err error
-	i.values, err = chartutil.ReadValues(i.valuesBytes)
-	return err
+	if i.unresolved, err = chartutil.ReadValues(i.valuesBytes); err != nil {
+		return fmt.Errorf("%w: %w", ErrRender, err)
+	}
+	// The cluster Secrets and ConfigMaps referenced by the rendered values are
+	// only read here, the unresolved values keep the placeholders, they are the
+	// ones printed. Emitted for GitOps, the references are left to Flux.
+	if i.gitOps() {
+		i.logger.Debug("Extracting the rendered values references")
+		if err = i.extractValueRefs(); err != nil {
+			return err
+		}
+	} else {
+		i.logger.Debug("Resolving the rendered values references")
+		i.values, err = config.ResolveValueRefs(
+			ctx, i.kube, i.valueRefs, i.narrowedRefs, i.unresolved)
+		if err != nil {
+			return err
+		}
+	}
+	// The downscaling overlay and the sizing preset are merged underneath, the
+	// values template prevails.
+	for _, overlay := range []chartutil.Values{i.downscale, i.sizing} {
+		if overlay != nil {
+			i.values = chartutil.CoalesceTables(i.values, overlay)
+			i.unresolved = chartutil.CoalesceTables(i.unresolved, overlay)
+		}
+	}
+	return i.validateValues()
 }
 
-// PrintValues prints the parsed values to the console.
+// extractValueRefs sets the values without the value references, the Flux
+// HelmRelease reads them from the referenced Secrets and ConfigMaps instead.
+func (i *Installer) extractValueRefs() error {
+	values, targets, err := config.ExtractValueRefs(
+		i.valueRefs, i.narrowedRefs, i.unresolved)
+	if err != nil {
+		return err
+	}
+	i.values = values
+	i.valuesFrom = make([]deployer.ValuesFrom, 0, len(targets))
+	for _, t := range targets {
+		keyRef, kind := t.Ref.SecretKeyRef, "Secret"
+		if keyRef == nil {
+			keyRef, kind = t.Ref.ConfigMapKeyRef, "ConfigMap"
+		}
+		i.valuesFrom = append(i.valuesFrom, deployer.ValuesFrom{
+			Kind:       kind,
+			Namespace:  keyRef.Namespace,
+			Name:       keyRef.Name,
+			Key:        keyRef.Key,
+			TargetPath: t.Path,
+		})
+	}
+	return nil
+}
+
+// validateValues validates the rendered values against the chart's, and its
+// subcharts', "values.schema.json", before Helm is involved. The values are
+// coalesced with the chart defaults, as Helm does.
+func (i *Installer) validateValues() error {
+	i.logger.Debug("Validating values against the chart schema")
+	// Emitted for GitOps the referenced values are absent, the placeholders
+	// stand for them.
+	vals := i.values
+	if i.gitOps() {
+		vals = i.unresolved
+	}
+	values, err := chartutil.CoalesceValues(i.dep.Chart(), vals)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRender, err)
+	}
+	if err = chartutil.ValidateAgainstSchema(i.dep.Chart(), values); err != nil {
+		product := i.dep.ProductName()
+		if product == "" {
+			product = "none"
+		}
+		return fmt.Errorf(
+			"%w: dependency %q (product %q, namespace %q):\n%w",
+			ErrValuesSchema,
+			i.dep.Name(),
+			product,
+			i.dep.Namespace(),
+			err,
+		)
+	}
+	return nil
+}
+
+// PrintValues prints the parsed values to the console, the value references are
+// printed unresolved, the referenced Secrets are not disclosed.
 func (i *Installer) PrintValues() {
 	i.logger.Debug("Showing parsed values")
-	printer.ValuesPrinter("Values", i.values)
+	printer.ValuesPrinter("Values", i.unresolved)
 }
 
-// Install performs the installation of the Helm chart.
-func (i *Installer) Install(ctx context.Context) error {
-	if i.values == nil {
-		return fmt.Errorf("values not set")
+// setHookOptions configures the Helm hooks settings from the dependency.
+func (i *Installer) setHookOptions(hc *deployer.Helm) error {
+	var hooks deployer.HookOptions
+	var err error
+	if hooks.Disabled, err = i.dep.NoHooks(); err != nil {
+		return err
+	}
+	if hooks.Timeout, err = i.dep.HooksTimeout(); err != nil {
+		return err
+	}
+	if hooks.DeletePolicies, err = i.dep.HookDeletePolicy(); err != nil {
+		return err
+	}
+	// Hooks are not post-rendered, thus on rehearsals they could reach the
+	// cluster-scoped resources.
+	if i.rehearsal != nil {
+		hooks.Disabled = true
+	}
+	hc.SetHookOptions(hooks)
+	return nil
+}
+
+// SetManagedBy sets the application name applied as ownership label on every
+// resource the installer creates.
+func (i *Installer) SetManagedBy(appName string) {
+	i.managedBy = appName
+}
+
+// ownership returns the ownership labels and annotations for the dependency
+// resources, empty when the managing application is not set.
+func (i *Installer) ownership() deployer.Ownership {
+	if i.managedBy == "" {
+		return deployer.Ownership{}
+	}
+	ownership := deployer.Ownership{
+		Labels: map[string]string{
+			annotations.Application: i.managedBy,
+			annotations.Chart:       i.dep.Name(),
+		},
 	}
+	if product := i.dep.ProductName(); product != "" {
+		ownership.Annotations = map[string]string{
+			annotations.ProductName: product,
+		}
+	}
+	return ownership
+}
 
+// SetMonitorOptions sets how the release resources status is checked, after the
+// Helm chart is installed.
+func (i *Installer) SetMonitorOptions(opts monitor.Options) {
+	i.monitorOpts = opts
+}
+
+// SetExecutor sets the deploy executor, installing the Helm chart release.
+func (i *Installer) SetExecutor(opts deployer.ExecutorOptions) {
+	i.executor = opts
+}
+
+// SetReplicator sets the integration secrets replicator, the replicas in the
+// dependency namespace are synchronized before the Helm chart is installed.
+func (i *Installer) SetReplicator(r *integration.Replicator) {
+	i.replicator = r
+}
+
+// applyNamespacePolicy handles the target namespace according to the
+// dependency policy. On dry-run the namespace is only checked.
+func (i *Installer) applyNamespacePolicy(ctx context.Context) error {
+	policy, err := i.dep.NamespacePolicy()
+	if err != nil {
+		return err
+	}
+	if i.rehearsal != nil {
+		policy = k8s.NamespaceCreate
+	}
+	if i.flags.DryRun && policy != k8s.NamespaceRequire {
+		i.logger.Debug("Skipping namespace policy (dry-run)",
+			"namespace-policy", policy)
+		return nil
+	}
+	ownership := i.ownership()
+	return k8s.ApplyNamespacePolicy(
+		ctx,
+		i.logger,
+		i.kube,
+		i.dep.Namespace(),
+		policy,
+		ownership.Labels,
+		ownership.Annotations,
+	)
+}
+
+// helmClient returns the Helm client for the dependency, with the hooks options
+// and the ownership applied.
+func (i *Installer) helmClient() (*deployer.Helm, error) {
 	i.logger.Debug("Loading Helm client for dependency and namespace")
 	hc, err := deployer.NewHelm(
 		i.logger,
@@ -89,32 +383,106 @@ func (i *Installer) Install(ctx context.Context) error {
 		i.dep.Namespace(),
 		i.dep.Chart(),
 	)
+	if err != nil {
+		return nil, err
+	}
+	if err = i.setHookOptions(hc); err != nil {
+		return nil, err
+	}
+	hc.SetOwnership(i.ownership())
+	hc.SetPrintedValues(i.unresolved)
+	if i.rehearsal != nil {
+		hc.SetNamespacedOnly(i.rehearsal)
+	}
+	return hc, nil
+}
+
+// Install performs the installation of the Helm chart.
+func (i *Installer) Install(ctx context.Context) error {
+	if i.values == nil {
+		return fmt.Errorf("values not set")
+	}
+
+	hc, err := i.helmClient()
+	if err != nil {
+		return err
+	}
+	executor, err := deployer.NewExecutor(hc, i.executor)
 	if err != nil {
 		return err
 	}
 
+	if i.policy.Enabled() {
+		i.logger.Debug("Scanning the rendered manifests",
+			"mode", i.policy.Mode, "rules", i.policy.Rules)
+		if err = i.gate(ctx, hc); err != nil {
+			return err
+		}
+	}
+
+	// The GitOps controller reconciles the emitted release, the namespace, the
+	// secret replicas and the release resources are left to it.
+	if flux, ok := executor.(*deployer.Flux); ok {
+		flux.SetDependsOn(i.dep.DependsOn())
+		flux.SetValuesFrom(i.valuesFrom)
+		i.logger.Debug("Emitting the Flux HelmRelease")
+		if err = flux.Deploy(ctx, i.values); err != nil {
+			return err
+		}
+		i.logger.Info("Flux HelmRelease emitted!")
+		return nil
+	}
+
+	i.logger.Debug("Checking for an existing release not deployed by the installer")
+	if err = i.checkRelease(hc); err != nil {
+		return err
+	}
+	i.logger.Debug("Checking for resources not owned by the release")
+	if err = i.checkConflicts(ctx, hc); err != nil {
+		return err
+	}
+
+	i.logger.Debug("Applying the namespace policy")
+	if err = i.applyNamespacePolicy(ctx); err != nil {
+		return err
+	}
+	if i.replicator != nil && !i.flags.DryRun {
+		i.logger.Debug("Synchronizing integration secret replicas")
+		if _, err = i.replicator.SyncNamespace(
+			ctx, i.dep.Namespace(),
+		); err != nil {
+			return fmt.Errorf("replicating integration secrets: %w", err)
+		}
+	}
+
 	// Performing the installation, or upgrade, of the Helm chart dependency,
 	// using the values rendered before hand.
-	i.logger.Debug("Installing the Helm chart")
-	if err = hc.Deploy(ctx, i.values); err != nil {
+	i.logger.Debug("Installing the Helm chart", "executor", i.executor.Name)
+	if err = executor.Deploy(ctx, i.values); err != nil {
 		return err
 	}
 	// Verifying if the installation was successful, by running the Helm chart
 	// tests interactively.
 	i.logger.Debug("Verifying the Helm chart release")
-	if err = hc.VerifyWithRetry(); err != nil {
+	if err = executor.VerifyWithRetry(); err != nil {
 		return err
 	}
 
+	i.logger.Debug("Labeling the product namespace")
+	if err = i.labelNamespace(ctx); err != nil {
+		return fmt.Errorf("labeling namespace %q: %w", i.dep.Namespace(), err)
+	}
+
 	if !i.flags.DryRun {
 		m := monitor.NewMonitor(i.logger, i.kube)
+		m.SetOptions(i.monitorOpts)
 		i.logger.Debug("Collecting resources for monitoring...")
-		if err = hc.VisitReleaseResources(ctx, m); err != nil {
+		if err = executor.VisitReleaseResources(ctx, m); err != nil {
 			return err
 		}
 		i.logger.Debug("Monitoring the Helm chart release...")
 		if err = m.Watch(i.flags.Timeout); err != nil {
-			return err
+			return fmt.Errorf("waiting for release resources: %w", err)
 		}
 		i.logger.Debug("Monitoring completed, release is successful!")
 	} else {
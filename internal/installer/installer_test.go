@@ -0,0 +1,329 @@
+package installer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestInstallerRenderValues(t *testing.T) {
+	hc := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name: "test-chart",
+			Annotations: map[string]string{
+				annotations.ProductName: "Product A",
+			},
+		},
+		Values: map[string]any{"replicas": 1},
+		Schema: []byte(`{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["replicas", "url"],
+  "properties": {
+    "replicas": {"type": "integer", "minimum": 1},
+    "url": {"type": "string"}
+  }
+}`),
+	}
+	newInstaller := func(values string) *Installer {
+		dep := resolver.NewDependencyWithNamespace(hc, "test-ns")
+		i := NewInstaller(
+			slog.New(slog.NewTextHandler(io.Discard, nil)),
+			flags.NewFlags(),
+			nil,
+			dep,
+			nil,
+		)
+		i.valuesBytes = []byte(values)
+		return i
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		// The chart defaults are coalesced, "replicas" is not informed.
+		g.Expect(newInstaller("url: https://example.com").RenderValues(context.Background())).
+			To(o.Succeed())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		err := newInstaller("replicas: 0").RenderValues(context.Background())
+		g.Expect(err).To(o.MatchError(ErrValuesSchema))
+		g.Expect(err.Error()).To(o.ContainSubstring(`dependency "test-chart"`))
+		g.Expect(err.Error()).To(o.ContainSubstring(`product "Product A"`))
+		g.Expect(err.Error()).To(o.ContainSubstring("url"))
+		g.Expect(ClassifyFailure(err)).To(o.Equal(FailureRender))
+	})
+
+	t.Run("value references", func(t *testing.T) {
+		g := o.NewWithT(t)
+		placeholder := "url: configMapRef:shared/endpoints/url"
+		i := newInstaller(placeholder)
+		i.kube = k8s.NewFakeKube(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "endpoints", Namespace: "shared"},
+			Data:       map[string]string{"url": "https://example.com"},
+		})
+		g.Expect(i.RenderValues(context.Background())).To(o.MatchError(config.ErrValueRefDenied))
+
+		i.SetValueRefAllowlist(
+			config.ValueRefAllowlist{"configMap:shared/endpoints"})
+		g.Expect(i.RenderValues(context.Background())).To(o.Succeed())
+		g.Expect(i.values["url"]).To(o.Equal("https://example.com"))
+		// The rendered values keep the placeholder, so do the printed ones.
+		g.Expect(string(i.valuesBytes)).To(o.Equal(placeholder))
+		g.Expect(i.unresolved["url"]).
+			To(o.Equal("configMapRef:shared/endpoints/url"))
+	})
+
+	t.Run("flux value references", func(t *testing.T) {
+		g := o.NewWithT(t)
+		i := newInstaller("url: secretRef:flux-system/endpoints/url")
+		i.SetExecutor(deployer.ExecutorOptions{Name: deployer.ExecutorFlux})
+		g.Expect(i.RenderValues(context.Background())).
+			To(o.MatchError(config.ErrValueRefDenied))
+
+		// The cluster isn't read, the reference is left to Flux.
+		i.SetValueRefAllowlist(
+			config.ValueRefAllowlist{"secret:flux-system/endpoints"})
+		g.Expect(i.RenderValues(context.Background())).To(o.Succeed())
+		g.Expect(i.values).NotTo(o.HaveKey("url"))
+		g.Expect(i.valuesFrom).To(o.Equal([]deployer.ValuesFrom{{
+			Kind:       "Secret",
+			Namespace:  "flux-system",
+			Name:       "endpoints",
+			Key:        "url",
+			TargetPath: "url",
+		}}))
+	})
+}
+
+func TestInstallerSetValuesGitOps(t *testing.T) {
+	cfs := chartfs.New(os.DirFS("../../test"))
+	hc := &chart.Chart{Metadata: &chart.Metadata{Name: "test-chart"}}
+	ctx := context.Background()
+	newInstaller := func(g o.Gomega) (*Installer, *config.Config) {
+		cfg, err := config.NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cfg.SetPath("settings.token", "s3cr3t")).To(o.Succeed())
+		i := NewInstaller(
+			slog.New(slog.NewTextHandler(io.Discard, nil)),
+			flags.NewFlags(),
+			k8s.NewFakeKube(),
+			resolver.NewDependencyWithNamespace(hc, "test-ns"),
+			nil,
+		)
+		i.SetSensitiveFields(config.SensitiveFields{"settings.token"})
+		i.SetExecutor(deployer.ExecutorOptions{Name: deployer.ExecutorFlux})
+		return i, cfg
+	}
+
+	t.Run("sensitive", func(t *testing.T) {
+		g := o.NewWithT(t)
+		i, cfg := newInstaller(g)
+		err := i.SetValues(ctx, cfg,
+			"token: {{ .Installer.Settings.token }}\n")
+		g.Expect(err).To(o.MatchError(ErrGitOpsValues))
+		g.Expect(err.Error()).NotTo(o.ContainSubstring("s3cr3t"))
+	})
+
+	t.Run("not sensitive", func(t *testing.T) {
+		g := o.NewWithT(t)
+		i, cfg := newInstaller(g)
+		g.Expect(i.SetValues(ctx, cfg, "key: value\n")).To(o.Succeed())
+		g.Expect(string(i.valuesBytes)).To(o.Equal("key: value\n"))
+	})
+}
+
+func TestInstallerSizing(t *testing.T) {
+	cfs := chartfs.New(os.DirFS("../../test"))
+	hc := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name: "test-chart",
+			Annotations: map[string]string{
+				annotations.ProductName: "Product B",
+			},
+		},
+		Values: map[string]any{
+			"replicas":  1,
+			"resources": map[string]any{"memory": "1Gi", "cpu": "1"},
+		},
+		Files: []*chart.File{{
+			Name: "sizing/small.yaml",
+			Data: []byte("resources:\n  memory: 256Mi\n"),
+		}, {
+			Name: "sizing/large.yaml",
+			Data: []byte("replicas: 3\nresources:\n  memory: 8Gi\n"),
+		}},
+	}
+	valuesTmpl := "replicas: 2\n"
+	ctx := context.Background()
+	render := func(g o.Gomega, sizing map[string]any) *Installer {
+		cfg, err := config.NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		for path, value := range sizing {
+			g.Expect(cfg.SetPath(path, value)).To(o.Succeed())
+		}
+		i := NewInstaller(
+			slog.New(slog.NewTextHandler(io.Discard, nil)),
+			flags.NewFlags(),
+			k8s.NewFakeKube(),
+			resolver.NewDependencyWithNamespace(hc, "test-ns"),
+			nil,
+		)
+		g.Expect(i.SetValues(ctx, cfg, valuesTmpl)).To(o.Succeed())
+		g.Expect(i.RenderValues(ctx)).To(o.Succeed())
+		return i
+	}
+
+	t.Run("None", func(t *testing.T) {
+		g := o.NewWithT(t)
+		i := render(g, nil)
+		g.Expect(i.values).To(o.HaveKeyWithValue("replicas", o.BeEquivalentTo(2)))
+		g.Expect(i.values).NotTo(o.HaveKey("resources"))
+	})
+
+	t.Run("Global", func(t *testing.T) {
+		g := o.NewWithT(t)
+		i := render(g, map[string]any{"settings.sizing": config.SizingSmall})
+		g.Expect(i.values).To(o.HaveKeyWithValue("resources",
+			map[string]any{"memory": "256Mi"}))
+
+		cfg, err := config.NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		e, err := i.Explain(ctx, cfg, valuesTmpl, "resources.memory")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(e.Value).To(o.Equal("256Mi"))
+		g.Expect(e.Source).To(o.Equal(LayerSizing))
+	})
+
+	t.Run("Product", func(t *testing.T) {
+		g := o.NewWithT(t)
+		// The product profile prevails, and the values template over it.
+		i := render(g, map[string]any{
+			"settings.sizing":                 config.SizingSmall,
+			"products[name=Product B].sizing": config.SizingLarge,
+		})
+		g.Expect(i.values).To(o.HaveKeyWithValue("replicas", o.BeEquivalentTo(2)))
+		g.Expect(i.values).To(o.HaveKeyWithValue("resources",
+			map[string]any{"memory": "8Gi"}))
+
+		// The chart doesn't declare the medium profile.
+		i = render(g, map[string]any{"settings.sizing": config.SizingMedium})
+		g.Expect(i.values).NotTo(o.HaveKey("resources"))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := config.NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cfg.SetPath("settings.sizing", "huge")).
+			To(o.MatchError(config.ErrInvalidConfig))
+		g.Expect(cfg.SetPath("products[name=Product B].sizing", "huge")).
+			To(o.MatchError(config.ErrInvalidConfig))
+	})
+}
+
+func TestInstallerDownscale(t *testing.T) {
+	cfs := chartfs.New(os.DirFS("../../test"))
+	hc := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name: "test-chart",
+			Annotations: map[string]string{
+				annotations.ProductName: "Product B",
+				annotations.Downscale:   "true",
+			},
+		},
+		Values: map[string]any{"replicaCount": 3},
+		Files: []*chart.File{{
+			Name: "sizing/small.yaml",
+			Data: []byte("replicaCount: 2\nresources:\n  limits:\n    memory: 256Mi\n"),
+		}},
+	}
+	node := func(name string) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	ctx := context.Background()
+	render := func(
+		g o.Gomega,
+		hc *chart.Chart,
+		kube k8s.Interface,
+		settings map[string]any,
+	) *Installer {
+		cfg, err := config.NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		for path, value := range settings {
+			g.Expect(cfg.SetPath(path, value)).To(o.Succeed())
+		}
+		i := NewInstaller(
+			slog.New(slog.NewTextHandler(io.Discard, nil)),
+			flags.NewFlags(),
+			kube,
+			resolver.NewDependencyWithNamespace(hc, "test-ns"),
+			nil,
+		)
+		g.Expect(i.SetValues(ctx, cfg, "key: value\n")).To(o.Succeed())
+		g.Expect(i.RenderValues(ctx)).To(o.Succeed())
+		return i
+	}
+
+	t.Run("SingleNode", func(t *testing.T) {
+		g := o.NewWithT(t)
+		i := render(g, hc, k8s.NewFakeKube(node("sno")),
+			map[string]any{"settings.sizing": config.SizingSmall})
+		// The overlay prevails over the sizing preset, merging with it.
+		g.Expect(i.values).To(o.HaveKeyWithValue(
+			"replicaCount", o.BeEquivalentTo(1)))
+		g.Expect(i.values).To(o.HaveKeyWithValue("resources", map[string]any{
+			"requests": map[string]any{"cpu": "10m", "memory": "64Mi"},
+			"limits":   map[string]any{"memory": "256Mi"},
+		}))
+	})
+
+	t.Run("MultipleNodes", func(t *testing.T) {
+		g := o.NewWithT(t)
+		kube := k8s.NewFakeKube(node("node-1"), node("node-2"))
+		i := render(g, hc, kube, nil)
+		g.Expect(i.values).NotTo(o.HaveKey("replicaCount"))
+
+		// The "crc" setting, or the "downscale" one, enables it regardless.
+		i = render(g, hc, kube, map[string]any{"settings.crc": true})
+		g.Expect(i.values).To(o.HaveKey("replicaCount"))
+		i = render(g, hc, kube, map[string]any{"settings.downscale": true})
+		g.Expect(i.values).To(o.HaveKey("replicaCount"))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		g := o.NewWithT(t)
+		i := render(g, hc, k8s.NewFakeKube(node("sno")),
+			map[string]any{"settings.downscale": false, "settings.crc": true})
+		g.Expect(i.values).NotTo(o.HaveKey("replicaCount"))
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		g := o.NewWithT(t)
+		unsupported := *hc
+		unsupported.Metadata = &chart.Metadata{Name: "test-chart"}
+		i := render(g, &unsupported, k8s.NewFakeKube(node("sno")), nil)
+		g.Expect(i.values).NotTo(o.HaveKey("replicaCount"))
+	})
+}
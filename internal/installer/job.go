@@ -9,10 +9,13 @@ import (
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/annotations"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/policy"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	applycorev1 "k8s.io/client-go/applyconfigurations/core/v1"
 	applymetav1 "k8s.io/client-go/applyconfigurations/meta/v1"
 	applyrbacv1 "k8s.io/client-go/applyconfigurations/rbac/v1"
@@ -186,19 +189,13 @@ func (j *Job) applyClusterRoleBinding(
 	return err
 }
 
-// createJob creates a Kubernetes Job to deploy the application, preparing the
+// newJob returns the Kubernetes Job to deploy the application, preparing the
 // installer to run on a container image and connect to the Kubernetes API
 // in-cluster.
-func (j *Job) createJob(
-	ctx context.Context,
+func (j *Job) newJob(
 	verbose, dryRun bool,
 	namespace, image string,
-) error {
-	bc, err := j.kube.BatchV1ClientSet("")
-	if err != nil {
-		return err
-	}
-
+) *batchv1.Job {
 	// Setting up the list of arguments for the deployment job.
 	args := []string{"deploy"}
 	if verbose {
@@ -219,12 +216,20 @@ func (j *Job) createJob(
 				// the cluster, using the service account credentials.
 				Name:  "KUBECONFIG",
 				Value: "",
+			}, {
+				// Tells the operations policy the command runs on the Job.
+				Name:  policy.JobEnv,
+				Value: "true",
 			}},
 			Args: args,
 		}},
 		RestartPolicy: corev1.RestartPolicyNever,
 	}
-	job := &batchv1.Job{
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: batchv1.SchemeGroupVersion.String(),
+			Kind:       "Job",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: namespace,
 			Name:      fmt.Sprintf("%s-deploy-job", j.appName),
@@ -240,10 +245,66 @@ func (j *Job) createJob(
 			BackoffLimit: &j.retries,
 		},
 	}
+}
+
+// createJob creates the Kubernetes Job to deploy the application.
+func (j *Job) createJob(
+	ctx context.Context,
+	verbose, dryRun bool,
+	namespace, image string,
+) error {
+	bc, err := j.kube.BatchV1ClientSet("")
+	if err != nil {
+		return err
+	}
+	job := j.newJob(verbose, dryRun, namespace, image)
 	_, err = bc.Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
 	return err
 }
 
+// Manifests returns the resources to run the deployment job in-cluster, without
+// creating them: the service account, a ClusterRole granting only the informed
+// rules, see RBACRules, its binding to the service account, and the job itself.
+func (j *Job) Manifests(
+	rules []rbacv1.PolicyRule,
+	verbose, dryRun bool,
+	namespace, image string,
+) []runtime.Object {
+	rbacVersion := rbacv1.SchemeGroupVersion.String()
+	return []runtime.Object{
+		&corev1.ServiceAccount{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      j.appName,
+			},
+		},
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacVersion, Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: j.appName},
+			Rules:      rules,
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: rbacVersion,
+				Kind:       "ClusterRoleBinding",
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: j.appName},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     j.appName,
+			},
+			Subjects: []rbacv1.Subject{{
+				Kind:      rbacv1.ServiceAccountKind,
+				Namespace: namespace,
+				Name:      j.appName,
+			}},
+		},
+		j.newJob(verbose, dryRun, namespace, image),
+	}
+}
+
 // deleteJob deletes the installer job.
 func (j *Job) deleteJob(ctx context.Context) error {
 	bc, err := j.kube.BatchV1ClientSet("")
@@ -0,0 +1,62 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// NamespaceLabelsSetting the installer setting with the labels applied to the
+// product namespaces, picked up by the cluster monitoring and network policies.
+const NamespaceLabelsSetting = "namespaceLabels"
+
+// NamespaceLabels returns the product namespace labels informed on the
+// configuration, validating the label names and values.
+func NamespaceLabels(cfg *config.Config) (map[string]string, error) {
+	setting, ok := cfg.Installer.Settings[NamespaceLabelsSetting]
+	if !ok || setting == nil {
+		return nil, nil
+	}
+	m, ok := config.AsMap(setting)
+	if !ok {
+		return nil, fmt.Errorf("%w: setting %q must be a map of labels",
+			config.ErrInvalidConfig, NamespaceLabelsSetting)
+	}
+	labels := make(map[string]string, len(m))
+	for k, v := range m {
+		value := fmt.Sprint(v)
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return nil, fmt.Errorf("%w: setting %q: label %q: %s",
+				config.ErrInvalidConfig, NamespaceLabelsSetting, k,
+				strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("%w: setting %q: label %q value %q: %s",
+				config.ErrInvalidConfig, NamespaceLabelsSetting, k, value,
+				strings.Join(errs, ", "))
+		}
+		labels[k] = value
+	}
+	return labels, nil
+}
+
+// SetNamespaceLabels sets the labels applied to the dependency namespace, when
+// the dependency belongs to a product.
+func (i *Installer) SetNamespaceLabels(labels map[string]string) {
+	i.namespaceLabels = labels
+}
+
+// labelNamespace applies the namespace labels on the product namespace, after
+// the Helm chart is installed the namespace is guaranteed to exist.
+func (i *Installer) labelNamespace(ctx context.Context) error {
+	if i.dep.ProductName() == "" || i.flags.DryRun {
+		return nil
+	}
+	return k8s.LabelNamespace(
+		ctx, i.logger, i.kube, i.dep.Namespace(), i.namespaceLabels)
+}
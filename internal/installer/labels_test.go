@@ -0,0 +1,67 @@
+package installer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestNamespaceLabels(t *testing.T) {
+	cfs := chartfs.New(os.DirFS("../../test"))
+	newConfig := func(t *testing.T, labels any) *config.Config {
+		g := o.NewWithT(t)
+		cfg, err := config.NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		if labels != nil {
+			cfg.Installer.Settings[NamespaceLabelsSetting] = labels
+		}
+		return cfg
+	}
+
+	t.Run("unset", func(t *testing.T) {
+		g := o.NewWithT(t)
+		labels, err := NamespaceLabels(newConfig(t, nil))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(labels).To(o.BeEmpty())
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		labels, err := NamespaceLabels(newConfig(t, map[string]any{
+			"monitoring":                      "enabled",
+			"app.kubernetes.io/part-of":       "helmet-ex",
+			"openshift.io/cluster-monitoring": true,
+		}))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(labels).To(o.Equal(map[string]string{
+			"monitoring":                      "enabled",
+			"app.kubernetes.io/part-of":       "helmet-ex",
+			"openshift.io/cluster-monitoring": "true",
+		}))
+	})
+
+	t.Run("decoded settings", func(t *testing.T) {
+		g := o.NewWithT(t)
+		labels, err := NamespaceLabels(newConfig(t, config.Settings{
+			"monitoring": "enabled",
+		}))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(labels).To(o.Equal(map[string]string{"monitoring": "enabled"}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := NamespaceLabels(newConfig(t, map[string]any{
+			"invalid label": "enabled",
+		}))
+		g.Expect(err).To(o.MatchError(config.ErrInvalidConfig))
+
+		_, err = NamespaceLabels(newConfig(t, "monitoring=enabled"))
+		g.Expect(err).To(o.MatchError(config.ErrInvalidConfig))
+	})
+}
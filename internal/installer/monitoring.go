@@ -0,0 +1,329 @@
+package installer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"gopkg.in/yaml.v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// MonitoringSetting the installer setting enabling the export of the products
+// metrics endpoints to the Prometheus Operator, after a successful deployment.
+const MonitoringSetting = "monitoring"
+
+// DefaultMetricsPath the metrics endpoint path, when not annotated.
+const DefaultMetricsPath = "/metrics"
+
+// Prometheus Operator resources, namespaced.
+var (
+	serviceMonitorGVR = schema.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1",
+		Resource: "servicemonitors",
+	}
+	podMonitorGVR = schema.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1",
+		Resource: "podmonitors",
+	}
+	prometheusRuleGVR = schema.GroupVersionResource{
+		Group:    "monitoring.coreos.com",
+		Version:  "v1",
+		Resource: "prometheusrules",
+	}
+)
+
+// MonitoringEnabled returns whether the configuration enables the monitoring
+// export.
+func MonitoringEnabled(cfg *config.Config) bool {
+	enabled, _ := cfg.Installer.Settings[MonitoringSetting].(bool)
+	return enabled
+}
+
+// MetricsEndpoint a metrics endpoint annotated on the release manifest, served
+// by a Service or by the pods of a workload.
+type MetricsEndpoint struct {
+	Kind     string            // resource kind, Service or a workload kind
+	Name     string            // resource name
+	Port     string            // metrics port name
+	Path     string            // metrics HTTP path
+	Selector map[string]string // Service labels, or workload pods labels
+}
+
+// Service returns whether the endpoint is served by a Service, otherwise by the
+// workload pods.
+func (e MetricsEndpoint) Service() bool {
+	return e.Kind == "Service"
+}
+
+// MetricsEndpoints returns the metrics endpoints annotated on the Services and
+// workloads of the release manifest.
+func MetricsEndpoints(manifest string) ([]MetricsEndpoint, error) {
+	endpoints := []MetricsEndpoint{}
+	decoder := yaml.NewDecoder(bytes.NewBufferString(manifest))
+	for {
+		var doc struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name        string            `yaml:"name"`
+				Labels      map[string]string `yaml:"labels"`
+				Annotations map[string]string `yaml:"annotations"`
+			} `yaml:"metadata"`
+			Spec struct {
+				Selector struct {
+					MatchLabels map[string]string `yaml:"matchLabels"`
+				} `yaml:"selector"`
+			} `yaml:"spec"`
+		}
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return endpoints, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		port := doc.Metadata.Annotations[annotations.MetricsPort]
+		if port == "" {
+			continue
+		}
+		endpoint := MetricsEndpoint{
+			Kind: doc.Kind,
+			Name: doc.Metadata.Name,
+			Port: port,
+			Path: doc.Metadata.Annotations[annotations.MetricsPath],
+		}
+		if endpoint.Path == "" {
+			endpoint.Path = DefaultMetricsPath
+		}
+		switch doc.Kind {
+		case "Service":
+			endpoint.Selector = doc.Metadata.Labels
+		case "Deployment", "StatefulSet", "DaemonSet":
+			endpoint.Selector = doc.Spec.Selector.MatchLabels
+		default:
+			return nil, fmt.Errorf("%s %q: %q is only supported on Services "+
+				"and workloads", doc.Kind, doc.Metadata.Name,
+				annotations.MetricsPort)
+		}
+		if len(endpoint.Selector) == 0 {
+			return nil, fmt.Errorf("%s %q: no labels to select the metrics "+
+				"endpoint", doc.Kind, doc.Metadata.Name)
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+}
+
+// Monitoring exports the deployed products metrics endpoints to the Prometheus
+// Operator, creating a ServiceMonitor or PodMonitor per endpoint, and a baseline
+// PrometheusRule alerting when they're down.
+type Monitoring struct {
+	logger  *slog.Logger  // application logger
+	kube    k8s.Interface // kubernetes client
+	appName string        // application name, owner of the resources
+}
+
+// Supported returns whether the cluster serves the Prometheus Operator APIs.
+func (m *Monitoring) Supported() bool {
+	dc, err := m.kube.DiscoveryClient("")
+	if err != nil {
+		return false
+	}
+	_, err = dc.ServerResourcesForGroupVersion(
+		serviceMonitorGVR.GroupVersion().String())
+	return err == nil
+}
+
+// newResource returns a Prometheus Operator resource owned by the application,
+// for the dependency.
+func (m *Monitoring) newResource(
+	kind, name, namespace, dependency string,
+	spec map[string]any,
+) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "monitoring.coreos.com/v1",
+		"kind":       kind,
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": spec,
+	}}
+	u.SetLabels(map[string]string{
+		annotations.ManagedBy:   m.appName,
+		annotations.Application: m.appName,
+		annotations.Chart:       dependency,
+	})
+	return u
+}
+
+// toAny converts the labels to a generic map, as unstructured objects require.
+func toAny(labels map[string]string) map[string]any {
+	m := make(map[string]any, len(labels))
+	for k, v := range labels {
+		m[k] = v
+	}
+	return m
+}
+
+// NewMonitor returns the ServiceMonitor, or the PodMonitor, scraping the metrics
+// endpoint. The monitor is named after the annotated resource.
+func (m *Monitoring) NewMonitor(
+	dependency, namespace string,
+	endpoint MetricsEndpoint,
+) *unstructured.Unstructured {
+	scrape := map[string]any{
+		"port": endpoint.Port,
+		"path": endpoint.Path,
+	}
+	selector := map[string]any{"matchLabels": toAny(endpoint.Selector)}
+	if endpoint.Service() {
+		return m.newResource("ServiceMonitor", endpoint.Name, namespace,
+			dependency, map[string]any{
+				"selector":  selector,
+				"endpoints": []any{scrape},
+			})
+	}
+	return m.newResource("PodMonitor", endpoint.Name, namespace,
+		dependency, map[string]any{
+			"selector":            selector,
+			"podMetricsEndpoints": []any{scrape},
+		})
+}
+
+// job returns the Prometheus "job" label of the endpoint targets, the Service
+// name for ServiceMonitors, and "namespace/name" for PodMonitors.
+func job(namespace string, endpoint MetricsEndpoint) string {
+	if endpoint.Service() {
+		return endpoint.Name
+	}
+	return namespace + "/" + endpoint.Name
+}
+
+// NewPrometheusRule returns the baseline alerts for the dependency metrics
+// endpoints: targets down, and targets absent, when nothing is scraped at all.
+func (m *Monitoring) NewPrometheusRule(
+	dependency, namespace string,
+	endpoints []MetricsEndpoint,
+) *unstructured.Unstructured {
+	jobs := make([]string, 0, len(endpoints))
+	for _, e := range endpoints {
+		jobs = append(jobs, regexp.QuoteMeta(job(namespace, e)))
+	}
+	selector := fmt.Sprintf(`namespace=%q, job=~%q`,
+		namespace, strings.Join(jobs, "|"))
+	labels := map[string]any{
+		"severity":   "warning",
+		"dependency": dependency,
+	}
+	return m.newResource("PrometheusRule", dependency, namespace,
+		dependency, map[string]any{
+			"groups": []any{map[string]any{
+				"name": dependency,
+				"rules": []any{map[string]any{
+					"alert":  "MetricsTargetDown",
+					"expr":   fmt.Sprintf("up{%s} == 0", selector),
+					"for":    "5m",
+					"labels": labels,
+					"annotations": map[string]any{
+						"summary": fmt.Sprintf(
+							"%s metrics target {{ $labels.job }} is down",
+							dependency),
+					},
+				}, map[string]any{
+					"alert":  "MetricsTargetAbsent",
+					"expr":   fmt.Sprintf("absent(up{%s})", selector),
+					"for":    "15m",
+					"labels": labels,
+					"annotations": map[string]any{
+						"summary": fmt.Sprintf(
+							"%s metrics are not scraped", dependency),
+					},
+				}},
+			}},
+		})
+}
+
+// apply creates or updates the namespaced resource.
+func (m *Monitoring) apply(
+	ctx context.Context,
+	gvr schema.GroupVersionResource,
+	obj *unstructured.Unstructured,
+) error {
+	dynamicClient, err := m.kube.DynamicClient("")
+	if err != nil {
+		return err
+	}
+	client := dynamicClient.Resource(gvr).Namespace(obj.GetNamespace())
+	existing, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = client.Create(ctx, obj, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	_, err = client.Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+// Export applies the monitors for the metrics endpoints annotated on the
+// release manifest, and the baseline alerts. Releases without metrics endpoints
+// are skipped.
+func (m *Monitoring) Export(
+	ctx context.Context,
+	dependency, namespace, manifest string,
+) error {
+	logger := m.logger.With("dependency", dependency, "namespace", namespace)
+
+	endpoints, err := MetricsEndpoints(manifest)
+	if err != nil {
+		return fmt.Errorf("inspecting the release manifest: %w", err)
+	}
+	if len(endpoints) == 0 {
+		logger.Debug("No metrics endpoints annotated on the release")
+		return nil
+	}
+	for _, endpoint := range endpoints {
+		monitor := m.NewMonitor(dependency, namespace, endpoint)
+		gvr := podMonitorGVR
+		if endpoint.Service() {
+			gvr = serviceMonitorGVR
+		}
+		logger.Debug("Applying the metrics monitor",
+			"kind", monitor.GetKind(), "name", monitor.GetName())
+		if err = m.apply(ctx, gvr, monitor); err != nil {
+			return fmt.Errorf("applying %s %q: %w",
+				monitor.GetKind(), monitor.GetName(), err)
+		}
+	}
+	rule := m.NewPrometheusRule(dependency, namespace, endpoints)
+	logger.Debug("Applying the baseline alerts", "name", rule.GetName())
+	if err = m.apply(ctx, prometheusRuleGVR, rule); err != nil {
+		return fmt.Errorf("applying PrometheusRule %q: %w", rule.GetName(), err)
+	}
+	return nil
+}
+
+// NewMonitoring instantiates the monitoring export.
+func NewMonitoring(
+	logger *slog.Logger,
+	kube k8s.Interface,
+	appName string,
+) *Monitoring {
+	return &Monitoring{logger: logger, kube: kube, appName: appName}
+}
@@ -0,0 +1,116 @@
+package installer
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestMonitoring(t *testing.T) {
+	g := o.NewWithT(t)
+
+	manifest := `---
+apiVersion: v1
+kind: Service
+metadata:
+  name: product-a
+  labels:
+    app: product-a
+  annotations:
+    helmet.redhat-appstudio.github.com/metrics-port: metrics
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: product-a-worker
+  annotations:
+    helmet.redhat-appstudio.github.com/metrics-port: http
+    helmet.redhat-appstudio.github.com/metrics-path: /q/metrics
+spec:
+  selector:
+    matchLabels:
+      app: product-a-worker
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: product-a
+`
+
+	t.Run("MetricsEndpoints", func(t *testing.T) {
+		endpoints, err := MetricsEndpoints(manifest)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(endpoints).To(o.Equal([]MetricsEndpoint{{
+			Kind:     "Service",
+			Name:     "product-a",
+			Port:     "metrics",
+			Path:     DefaultMetricsPath,
+			Selector: map[string]string{"app": "product-a"},
+		}, {
+			Kind:     "Deployment",
+			Name:     "product-a-worker",
+			Port:     "http",
+			Path:     "/q/metrics",
+			Selector: map[string]string{"app": "product-a-worker"},
+		}}))
+
+		endpoints, err = MetricsEndpoints("")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(endpoints).To(o.BeEmpty())
+
+		_, err = MetricsEndpoints(`---
+kind: ConfigMap
+metadata:
+  name: product-a
+  annotations:
+    helmet.redhat-appstudio.github.com/metrics-port: metrics
+`)
+		g.Expect(err).To(o.HaveOccurred())
+	})
+
+	m := NewMonitoring(slog.New(slog.NewTextHandler(io.Discard, nil)),
+		k8s.NewFakeKube(), "helmet-ex")
+	endpoints, err := MetricsEndpoints(manifest)
+	g.Expect(err).To(o.Succeed())
+
+	t.Run("NewMonitor", func(t *testing.T) {
+		monitor := m.NewMonitor("helmet-product-a", "product-a", endpoints[0])
+		g.Expect(monitor.GetKind()).To(o.Equal("ServiceMonitor"))
+		g.Expect(monitor.GetNamespace()).To(o.Equal("product-a"))
+		g.Expect(monitor.GetLabels()).
+			To(o.HaveKeyWithValue(annotations.ManagedBy, "helmet-ex"))
+		scrape, _, _ := unstructured.NestedSlice(
+			monitor.Object, "spec", "endpoints")
+		g.Expect(scrape).To(o.Equal([]any{map[string]any{
+			"port": "metrics",
+			"path": DefaultMetricsPath,
+		}}))
+
+		monitor = m.NewMonitor("helmet-product-a", "product-a", endpoints[1])
+		g.Expect(monitor.GetKind()).To(o.Equal("PodMonitor"))
+		app, _, _ := unstructured.NestedString(
+			monitor.Object, "spec", "selector", "matchLabels", "app")
+		g.Expect(app).To(o.Equal("product-a-worker"))
+	})
+
+	t.Run("NewPrometheusRule", func(t *testing.T) {
+		rule := m.NewPrometheusRule("helmet-product-a", "product-a", endpoints)
+		g.Expect(rule.GetName()).To(o.Equal("helmet-product-a"))
+		groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+		g.Expect(groups).To(o.HaveLen(1))
+		rules := groups[0].(map[string]any)["rules"].([]any)
+		g.Expect(rules).To(o.HaveLen(2))
+		g.Expect(rules[0].(map[string]any)["expr"]).To(o.Equal(
+			`up{namespace="product-a", job=~"product-a|product-a/product-a-worker"} == 0`))
+	})
+
+	t.Run("Supported", func(t *testing.T) {
+		g.Expect(m.Supported()).To(o.BeFalse())
+	})
+}
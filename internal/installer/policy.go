@@ -0,0 +1,93 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+	"github.com/redhat-appstudio/helmet/internal/scan"
+)
+
+// SetSecurityPolicy sets the security policy applied on the rendered manifests
+// before the Helm chart is installed, see scan.Policy.
+func (i *Installer) SetSecurityPolicy(policy *scan.Policy) {
+	i.policy = policy
+}
+
+// scanManifests renders the chart manifests client-side and applies the policy
+// rules, the findings carry the dependency name and namespace.
+func (i *Installer) scanManifests(
+	ctx context.Context,
+	hc *deployer.Helm,
+	policy *scan.Policy,
+) ([]scan.Finding, error) {
+	manifest, err := hc.Render(ctx, i.values)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRender, err)
+	}
+	findings, err := policy.Scan(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRender, err)
+	}
+	for idx := range findings {
+		findings[idx].Dependency = i.dep.Name()
+		findings[idx].Namespace = i.dep.Namespace()
+	}
+	return findings, nil
+}
+
+// Scan renders the chart manifests and applies the policy rules, without
+// installing. The values must be rendered beforehand.
+func (i *Installer) Scan(
+	ctx context.Context,
+	policy *scan.Policy,
+) ([]scan.Finding, error) {
+	if i.values == nil {
+		return nil, fmt.Errorf("values not set")
+	}
+	hc, err := i.helmClient()
+	if err != nil {
+		return nil, err
+	}
+	return i.scanManifests(ctx, hc, policy)
+}
+
+// gate scans the rendered manifests before the installation, the findings are
+// reported and, when enforcing the policy, block the installation.
+func (i *Installer) gate(ctx context.Context, hc *deployer.Helm) error {
+	findings, err := i.scanManifests(ctx, hc, i.policy)
+	if err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	i.logger.Warn("Rendered manifests violate the security policy",
+		"mode", i.policy.Mode, "findings", len(findings))
+	fmt.Printf("\nSecurity policy findings (%s mode):\n", i.policy.Mode)
+	scan.PrintReport(os.Stdout, findings)
+	return i.policy.Enforce(findings)
+}
+
+// Images renders the chart manifests and returns the container images of its
+// workloads, hooks included, without installing. The values must be rendered
+// beforehand.
+func (i *Installer) Images(ctx context.Context) ([]string, error) {
+	if i.values == nil {
+		return nil, fmt.Errorf("values not set")
+	}
+	hc, err := i.helmClient()
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := hc.Render(ctx, i.values)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRender, err)
+	}
+	images, err := scan.Images(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRender, err)
+	}
+	return images, nil
+}
@@ -0,0 +1,118 @@
+package installer
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+	"github.com/redhat-appstudio/helmet/internal/scan"
+
+	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestInstallerScan(t *testing.T) {
+	g := o.NewWithT(t)
+	hc := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       "test-chart",
+			Version:    "0.1.0",
+		},
+		Values: map[string]any{"privileged": false},
+		Templates: []*chart.File{{
+			Name: "templates/pod.yaml",
+			Data: []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: test
+spec:
+  containers:
+    - name: test
+      securityContext:
+        privileged: {{ .Values.privileged }}
+      resources:
+        limits:
+          cpu: 100m
+          memory: 64Mi
+`),
+		}},
+	}
+	i := NewInstaller(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		flags.NewFlags(),
+		k8s.NewFakeKube(),
+		resolver.NewDependencyWithNamespace(hc, "test-ns"),
+		nil,
+	)
+	policy := &scan.Policy{Mode: scan.ModeEnforce, Rules: scan.Rules}
+
+	_, err := i.Scan(context.Background(), policy)
+	g.Expect(err).To(o.HaveOccurred())
+
+	i.valuesBytes = []byte("privileged: false")
+	g.Expect(i.RenderValues(context.Background())).To(o.Succeed())
+	findings, err := i.Scan(context.Background(), policy)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(findings).To(o.BeEmpty())
+
+	i.valuesBytes = []byte("privileged: true")
+	g.Expect(i.RenderValues(context.Background())).To(o.Succeed())
+	findings, err = i.Scan(context.Background(), policy)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(findings).To(o.Equal([]scan.Finding{{
+		Dependency: "test-chart",
+		Namespace:  "test-ns",
+		Rule:       scan.RulePrivileged,
+		Resource:   "Pod/test",
+		Container:  "test",
+		Message:    "runs privileged",
+	}}))
+	g.Expect(policy.Enforce(findings)).To(o.MatchError(scan.ErrPolicyViolation))
+}
+
+func TestInstallerImages(t *testing.T) {
+	g := o.NewWithT(t)
+	hc := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       "test-chart",
+			Version:    "0.1.0",
+		},
+		Templates: []*chart.File{{
+			Name: "templates/job.yaml",
+			Data: []byte(`apiVersion: batch/v1
+kind: Job
+metadata:
+  name: test
+  annotations:
+    helm.sh/hook: post-install
+spec:
+  template:
+    spec:
+      containers:
+        - name: test
+          image: quay.io/example/test:{{ .Values.tag }}
+`),
+		}},
+	}
+	i := NewInstaller(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		flags.NewFlags(),
+		k8s.NewFakeKube(),
+		resolver.NewDependencyWithNamespace(hc, "test-ns"),
+		nil,
+	)
+	_, err := i.Images(context.Background())
+	g.Expect(err).To(o.HaveOccurred())
+
+	i.valuesBytes = []byte("tag: \"1.0\"")
+	g.Expect(i.RenderValues(context.Background())).To(o.Succeed())
+	images, err := i.Images(context.Background())
+	g.Expect(err).To(o.Succeed())
+	g.Expect(images).To(o.Equal([]string{"quay.io/example/test:1.0"}))
+}
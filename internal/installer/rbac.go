@@ -0,0 +1,228 @@
+package installer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	"gopkg.in/yaml.v3"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// manageVerbs the verbs Helm needs to install, upgrade and uninstall the
+// resources of a release.
+var manageVerbs = []string{
+	"get", "list", "watch", "create", "update", "patch", "delete",
+}
+
+// baseRules the permissions the installer needs regardless of the catalog: the
+// product namespaces, the configuration, Helm release storage and integration
+// secrets, the hooks and tests pods and jobs, and the OpenShift cluster details.
+var baseRules = []rbacv1.PolicyRule{{
+	APIGroups: []string{""},
+	Resources: []string{"namespaces"},
+	Verbs:     []string{"get", "list", "watch", "create", "update", "patch"},
+}, {
+	APIGroups: []string{""},
+	Resources: []string{"configmaps", "secrets"},
+	Verbs:     manageVerbs,
+}, {
+	APIGroups: []string{""},
+	Resources: []string{"events", "pods", "pods/log"},
+	Verbs:     []string{"get", "list", "watch"},
+}, {
+	APIGroups: []string{"batch"},
+	Resources: []string{"jobs"},
+	Verbs:     manageVerbs,
+}, {
+	APIGroups: []string{"config.openshift.io"},
+	Resources: []string{"clusterversions"},
+	Verbs:     []string{"get"},
+}, {
+	APIGroups: []string{"operator.openshift.io"},
+	Resources: []string{"ingresscontrollers"},
+	Verbs:     []string{"get"},
+}, {
+	APIGroups: []string{"project.openshift.io"},
+	Resources: []string{"projects"},
+	Verbs:     []string{"get"},
+}, {
+	APIGroups: []string{"project.openshift.io"},
+	Resources: []string{"projectrequests"},
+	Verbs:     []string{"create"},
+}}
+
+// policyRules accumulates the verbs granted per API group and resource.
+type policyRules map[string]map[string][]string
+
+// add grants the verbs on the resources of the API group.
+func (p policyRules) add(group string, resources, verbs []string) {
+	if p[group] == nil {
+		p[group] = map[string][]string{}
+	}
+	for _, resource := range resources {
+		for _, verb := range verbs {
+			if !slices.Contains(p[group][resource], verb) {
+				p[group][resource] = append(p[group][resource], verb)
+			}
+		}
+	}
+}
+
+// Rules returns the policy rules sorted by API group, the resources sharing the
+// same verbs are combined on a single rule.
+func (p policyRules) Rules() []rbacv1.PolicyRule {
+	rules := []rbacv1.PolicyRule{}
+	groups := make([]string, 0, len(p))
+	for group := range p {
+		groups = append(groups, group)
+	}
+	slices.Sort(groups)
+	for _, group := range groups {
+		byVerbs := map[string]*rbacv1.PolicyRule{}
+		keys := []string{}
+		for resource, verbs := range p[group] {
+			key := strings.Join(verbs, ",")
+			rule, ok := byVerbs[key]
+			if !ok {
+				rule = &rbacv1.PolicyRule{
+					APIGroups: []string{group},
+					Verbs:     verbs,
+				}
+				byVerbs[key] = rule
+				keys = append(keys, key)
+			}
+			rule.Resources = append(rule.Resources, resource)
+		}
+		for _, rule := range byVerbs {
+			slices.Sort(rule.Resources)
+		}
+		slices.SortFunc(keys, func(a, b string) int {
+			return strings.Compare(
+				byVerbs[a].Resources[0], byVerbs[b].Resources[0])
+		})
+		for _, key := range keys {
+			rules = append(rules, *byVerbs[key])
+		}
+	}
+	return rules
+}
+
+// manifestResources returns the group and resource of every document on the
+// manifest. The mapper resolves the resource names served by the cluster, kinds
+// it doesn't know yet, like custom resources installed by the same catalog, fall
+// back to the conventional plural.
+func manifestResources(
+	mapper meta.RESTMapper,
+	manifest string,
+) ([]schema.GroupResource, error) {
+	resources := []schema.GroupResource{}
+	decoder := yaml.NewDecoder(bytes.NewBufferString(manifest))
+	for {
+		var doc struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+		}
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return resources, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc.Kind == "" {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(doc.APIVersion)
+		if err != nil {
+			return nil, err
+		}
+		gvk := gv.WithKind(doc.Kind)
+		if mapper != nil {
+			mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+			if err == nil {
+				resources = append(resources, mapping.Resource.GroupResource())
+				continue
+			}
+		}
+		plural, _ := meta.UnsafeGuessKindToResource(gvk)
+		resources = append(resources, plural.GroupResource())
+	}
+}
+
+// RBACRules returns the minimal policy rules to deploy the catalog: the
+// resources found on the rendered manifests, plus the installer's own needs for
+// the configuration backend and the enabled settings. The mapper may be nil,
+// then resource names are guessed from the kinds.
+func RBACRules(
+	cfg *config.Config,
+	backend string,
+	mapper meta.RESTMapper,
+	manifests ...string,
+) ([]rbacv1.PolicyRule, error) {
+	p := policyRules{}
+	for _, rule := range baseRules {
+		p.add(rule.APIGroups[0], rule.Resources, rule.Verbs)
+	}
+	for _, manifest := range manifests {
+		resources, err := manifestResources(mapper, manifest)
+		if err != nil {
+			return nil, err
+		}
+		for _, gr := range resources {
+			p.add(gr.Group, []string{gr.Resource}, manageVerbs)
+			// Creating roles and bindings granting permissions the installer
+			// doesn't hold requires escalating and binding.
+			if gr.Group == rbacv1.GroupName {
+				p.add(rbacv1.GroupName, []string{"clusterroles", "roles"},
+					[]string{"bind", "escalate"})
+			}
+		}
+	}
+	if backend == config.BackendCRD {
+		p.add("apiextensions.k8s.io", []string{"customresourcedefinitions"},
+			[]string{"get", "create"})
+		p.add(annotations.RepoURI,
+			[]string{config.InstallationResource.Resource}, manageVerbs)
+	}
+	if cfg != nil && ConsoleEnabled(cfg) {
+		p.add(consoleLinkGVR.Group, []string{consoleLinkGVR.Resource},
+			[]string{"get", "create", "update"})
+		p.add("operator.openshift.io", []string{"consoles"},
+			[]string{"get", "update"})
+	}
+	if cfg != nil && MonitoringEnabled(cfg) {
+		p.add(serviceMonitorGVR.Group, []string{
+			serviceMonitorGVR.Resource,
+			podMonitorGVR.Resource,
+			prometheusRuleGVR.Resource,
+		}, []string{"get", "create", "update"})
+	}
+	return p.Rules(), nil
+}
+
+// Manifest renders the chart manifests client-side, hooks included, without
+// installing. The values must be rendered beforehand.
+func (i *Installer) Manifest(ctx context.Context) (string, error) {
+	if i.values == nil {
+		return "", fmt.Errorf("values not set")
+	}
+	hc, err := i.helmClient()
+	if err != nil {
+		return "", err
+	}
+	manifest, err := hc.Render(ctx, i.values)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrRender, err)
+	}
+	return manifest, nil
+}
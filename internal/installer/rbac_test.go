@@ -0,0 +1,139 @@
+package installer
+
+import (
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// findRule returns the rule granting the resource on the API group.
+func findRule(
+	rules []rbacv1.PolicyRule,
+	group, resource string,
+) *rbacv1.PolicyRule {
+	for _, rule := range rules {
+		for _, r := range rule.Resources {
+			if rule.APIGroups[0] == group && r == resource {
+				return &rule
+			}
+		}
+	}
+	return nil
+}
+
+func TestRBACRules(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := config.NewConfigFromFile(
+		cfs, "config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+
+	manifest := `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: product-a
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: product-a
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: product-a
+---
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: product-a
+`
+
+	t.Run("manifests", func(t *testing.T) {
+		rules, err := RBACRules(cfg, "", nil, manifest)
+		g.Expect(err).To(o.Succeed())
+
+		deployments := findRule(rules, "apps", "deployments")
+		g.Expect(deployments).ToNot(o.BeNil())
+		g.Expect(deployments.Verbs).To(o.Equal(manageVerbs))
+		g.Expect(findRule(rules, "", "services")).ToNot(o.BeNil())
+		g.Expect(findRule(rules, "example.com", "widgets")).ToNot(o.BeNil())
+
+		roles := findRule(rules, rbacv1.GroupName, "clusterroles")
+		g.Expect(roles).ToNot(o.BeNil())
+		g.Expect(roles.Verbs).To(o.ContainElements("create", "bind", "escalate"))
+
+		g.Expect(findRule(rules, "", "secrets")).ToNot(o.BeNil())
+		g.Expect(findRule(rules, "", "namespaces")).ToNot(o.BeNil())
+		g.Expect(findRule(rules, "", "pods")).ToNot(o.BeNil())
+		g.Expect(findRule(rules, "", "nodes")).To(o.BeNil())
+	})
+
+	t.Run("base", func(t *testing.T) {
+		rules, err := RBACRules(cfg, "", nil)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(findRule(rules, rbacv1.GroupName, "clusterroles")).To(o.BeNil())
+		g.Expect(findRule(rules, config.InstallationResource.Group,
+			config.InstallationResource.Resource)).To(o.BeNil())
+
+		rules, err = RBACRules(cfg, config.BackendCRD, nil)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(findRule(rules, config.InstallationResource.Group,
+			config.InstallationResource.Resource)).ToNot(o.BeNil())
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		settings, err := cfg.DeepCopy()
+		g.Expect(err).To(o.Succeed())
+		settings.Installer.Settings = map[string]any{
+			ConsoleSetting:    true,
+			MonitoringSetting: true,
+		}
+		rules, err := RBACRules(settings, "", nil)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(findRule(rules, "console.openshift.io", "consolelinks")).
+			ToNot(o.BeNil())
+		g.Expect(findRule(rules, "monitoring.coreos.com", "servicemonitors")).
+			ToNot(o.BeNil())
+	})
+
+	t.Run("invalid manifest", func(t *testing.T) {
+		_, err := RBACRules(cfg, "", nil, "kind: [")
+		g.Expect(err).ToNot(o.Succeed())
+	})
+}
+
+func TestJobManifests(t *testing.T) {
+	g := o.NewWithT(t)
+
+	appCtx := api.NewAppContext("helmet")
+	job := NewJob(appCtx, k8s.NewFakeKube())
+	rules := []rbacv1.PolicyRule{{
+		APIGroups: []string{""},
+		Resources: []string{"configmaps"},
+		Verbs:     []string{"get"},
+	}}
+	objs := job.Manifests(rules, false, true, "helmet", "image:latest")
+	g.Expect(objs).To(o.HaveLen(4))
+
+	role, ok := objs[1].(*rbacv1.ClusterRole)
+	g.Expect(ok).To(o.BeTrue())
+	g.Expect(role.Rules).To(o.Equal(rules))
+
+	binding, ok := objs[2].(*rbacv1.ClusterRoleBinding)
+	g.Expect(ok).To(o.BeTrue())
+	g.Expect(binding.RoleRef.Name).To(o.Equal(role.GetName()))
+	g.Expect(binding.Subjects[0].Namespace).To(o.Equal("helmet"))
+
+	kind := objs[3].GetObjectKind().GroupVersionKind()
+	g.Expect(kind.Kind).To(o.Equal("Job"))
+}
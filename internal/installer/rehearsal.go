@@ -0,0 +1,72 @@
+package installer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// rehearsalSuffix the infix of the rehearsal namespaces, followed by a random
+// suffix, "<namespace>-rehearsal-<suffix>".
+const rehearsalSuffix = "-rehearsal-"
+
+// RehearsalNamespaces maps the namespaces of the dependencies to throwaway ones,
+// with generated names. Dependencies sharing a namespace share the rehearsal
+// namespace as well.
+func RehearsalNamespaces(deps resolver.Dependencies) (map[string]string, error) {
+	namespaces := map[string]string{}
+	for _, dep := range deps {
+		if _, ok := namespaces[dep.Namespace()]; ok {
+			continue
+		}
+		b := make([]byte, 3)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("failed to generate random bytes: %w", err)
+		}
+		suffix := rehearsalSuffix + hex.EncodeToString(b)
+		// Namespace names are limited to 63 characters.
+		prefix := dep.Namespace()
+		if limit := 63 - len(suffix); len(prefix) > limit {
+			prefix = prefix[:limit]
+		}
+		namespaces[dep.Namespace()] = prefix + suffix
+	}
+	return namespaces, nil
+}
+
+// SetRehearsal sets the installation as a rehearsal on a throwaway namespace:
+// the namespace is created, the cluster-scoped resources, CRDs included, and
+// the hooks are skipped. The mapper tells the kinds scope.
+func (i *Installer) SetRehearsal(mapper meta.RESTMapper) {
+	i.rehearsal = mapper
+}
+
+// Uninstall removes the dependency release, used to tear down rehearsals.
+func (i *Installer) Uninstall(ctx context.Context) error {
+	hc, err := i.helmClient()
+	if err != nil {
+		return err
+	}
+	return hc.Uninstall(ctx)
+}
+
+// DeleteNamespace deletes the rehearsal namespace, and everything left on it.
+func DeleteNamespace(ctx context.Context, kube k8s.Interface, namespace string) error {
+	client, err := kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return err
+	}
+	err = client.Namespaces().Delete(ctx, namespace, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
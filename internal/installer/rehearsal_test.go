@@ -0,0 +1,37 @@
+package installer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestRehearsalNamespaces(t *testing.T) {
+	g := o.NewWithT(t)
+
+	dep := func(name, ns string) resolver.Dependency {
+		return *resolver.NewDependencyWithNamespace(&chart.Chart{
+			Metadata: &chart.Metadata{Name: name},
+		}, ns)
+	}
+	long := strings.Repeat("a", 63)
+	namespaces, err := RehearsalNamespaces(resolver.Dependencies{
+		dep("a", "product"),
+		dep("b", "product"),
+		dep("c", long),
+	})
+	g.Expect(err).To(o.Succeed())
+	g.Expect(namespaces).To(o.HaveLen(2))
+	g.Expect(namespaces["product"]).To(
+		o.MatchRegexp(`^product-rehearsal-[0-9a-f]{6}$`))
+	g.Expect(namespaces[long]).To(o.HaveLen(63))
+	g.Expect(namespaces[long]).To(o.HavePrefix("aaaa"))
+
+	again, err := RehearsalNamespaces(resolver.Dependencies{dep("a", "product")})
+	g.Expect(err).To(o.Succeed())
+	g.Expect(again["product"]).NotTo(o.Equal(namespaces["product"]))
+}
@@ -0,0 +1,91 @@
+package installer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+)
+
+// Upgrade a dependency whose chart version changes on the deployment, carrying
+// the notes of the chart version about to be deployed.
+type Upgrade struct {
+	Name            string // dependency name
+	Namespace       string // target namespace
+	From            string // deployed chart version
+	To              string // chart version to deploy
+	Notes           string // what's new on the chart version
+	BreakingChanges string // breaking changes on the chart version
+}
+
+// NewUpgrade returns the upgrade of the dependency from the deployed chart
+// version, nil when the chart is not installed or the version is unchanged.
+func NewUpgrade(dep *resolver.Dependency, deployed string) *Upgrade {
+	version := dep.Chart().Metadata.Version
+	if deployed == "" || deployed == version {
+		return nil
+	}
+	return &Upgrade{
+		Name:            dep.Name(),
+		Namespace:       dep.Namespace(),
+		From:            deployed,
+		To:              version,
+		Notes:           dep.ReleaseNotes(),
+		BreakingChanges: dep.BreakingChanges(),
+	}
+}
+
+// ReleaseNotes the upgrades of a deployment, aggregating the release notes of
+// every dependency upgraded.
+type ReleaseNotes []Upgrade
+
+// HasBreakingChanges returns true when any upgrade carries breaking changes.
+func (r ReleaseNotes) HasBreakingChanges() bool {
+	for _, u := range r {
+		if u.BreakingChanges != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Print prints the upgraded dependencies versions to the writer, followed by
+// the aggregated breaking changes and "what's new" sections.
+func (r ReleaseNotes) Print(w io.Writer) {
+	fmt.Fprintf(w, "%s\n# Release Notes\n%s\n\n",
+		strings.Repeat("#", 60), strings.Repeat("#", 60))
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Dependency\tNamespace\tFrom\tTo")
+	for _, u := range r {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n",
+			u.Name, u.Namespace, u.From, u.To)
+	}
+	table.Flush()
+
+	section := func(title string, text func(Upgrade) string) {
+		printed := false
+		for _, u := range r {
+			if text(u) == "" {
+				continue
+			}
+			if !printed {
+				fmt.Fprintf(w, "\n%s:\n", title)
+				printed = true
+			}
+			fmt.Fprintf(w, "\n  %s %s:\n", u.Name, u.To)
+			for _, line := range strings.Split(text(u), "\n") {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+	}
+	section("Breaking changes", func(u Upgrade) string {
+		return u.BreakingChanges
+	})
+	section("What's new", func(u Upgrade) string {
+		return u.Notes
+	})
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,62 @@
+package installer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestReleaseNotes(t *testing.T) {
+	g := o.NewWithT(t)
+
+	newDependency := func(name, version string, notes map[string]string) *resolver.Dependency {
+		return resolver.NewDependencyWithNamespace(&chart.Chart{
+			Metadata: &chart.Metadata{
+				Name:        name,
+				Version:     version,
+				Annotations: notes,
+			},
+		}, "test-ns")
+	}
+	a := newDependency("chart-a", "1.1.0", map[string]string{
+		annotations.ReleaseNotes: "Adds the dashboard.\nFaster startup.\n",
+	})
+	b := newDependency("chart-b", "2.0.0", map[string]string{
+		annotations.BreakingChanges: "Drops the legacy API.",
+	})
+
+	t.Run("NewUpgrade", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(NewUpgrade(a, "")).To(o.BeNil())
+		g.Expect(NewUpgrade(a, "1.1.0")).To(o.BeNil())
+		g.Expect(*NewUpgrade(a, "1.0.0")).To(o.Equal(Upgrade{
+			Name:      "chart-a",
+			Namespace: "test-ns",
+			From:      "1.0.0",
+			To:        "1.1.0",
+			Notes:     "Adds the dashboard.\nFaster startup.",
+		}))
+	})
+
+	notes := ReleaseNotes{*NewUpgrade(a, "1.0.0")}
+	g.Expect(notes.HasBreakingChanges()).To(o.BeFalse())
+	notes = append(notes, *NewUpgrade(b, "1.4.2"))
+	g.Expect(notes.HasBreakingChanges()).To(o.BeTrue())
+
+	t.Run("Print", func(t *testing.T) {
+		g := o.NewWithT(t)
+		var buf bytes.Buffer
+		notes.Print(&buf)
+		out := buf.String()
+		g.Expect(out).To(o.ContainSubstring("chart-a     test-ns    1.0.0  1.1.0"))
+		g.Expect(out).To(o.ContainSubstring(
+			"Breaking changes:\n\n  chart-b 2.0.0:\n    Drops the legacy API.\n"))
+		g.Expect(out).To(o.ContainSubstring(
+			"What's new:\n\n  chart-a 1.1.0:\n    Adds the dashboard.\n    Faster startup.\n"))
+	})
+}
@@ -0,0 +1,217 @@
+package installer
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// ErrRunNotFound the deploy run is not recorded on the deployment history.
+var ErrRunNotFound = helmeterrors.New(helmeterrors.ErrInvalidUsage,
+	"deploy run not found")
+
+// RunsKey the history ConfigMap data key holding the deploy runs, as a YAML
+// list, oldest first.
+const RunsKey = "runs.yaml"
+
+// RunsSize the number of deploy runs kept.
+const RunsSize = 20
+
+// LatestRun refers to the most recent deploy run, in place of its ID.
+const LatestRun = "latest"
+
+// ReleaseNotInstalled the release status of the dependencies not installed.
+const ReleaseNotInstalled = "not installed"
+
+// RunRelease a dependency release as it was after a deploy run.
+type RunRelease struct {
+	Dependency string `yaml:"dependency" json:"dependency"`                 // dependency name
+	Namespace  string `yaml:"namespace" json:"namespace"`                   // release namespace
+	Chart      string `yaml:"chart" json:"chart"`                           // chart name
+	Version    string `yaml:"version,omitempty" json:"version,omitempty"`   // deployed chart version
+	Revision   int    `yaml:"revision,omitempty" json:"revision,omitempty"` // release revision
+	Status     string `yaml:"status" json:"status"`                         // release status
+	Result     Status `yaml:"result,omitempty" json:"result,omitempty"`     // outcome on the run, when part of it
+}
+
+// Run a run of the "deploy" subcommand, the configuration it used and the
+// releases of the whole topology once it finished, so the installation can be
+// reconstructed as it was after the run.
+type Run struct {
+	ID         string       `yaml:"id" json:"id"`                           // run identifier, see NewRunID
+	Time       time.Time    `yaml:"time" json:"time"`                       // run start
+	ConfigHash string       `yaml:"configHash" json:"configHash"`           // configuration SHA-256
+	Error      string       `yaml:"error,omitempty" json:"error,omitempty"` // run failure
+	Releases   []RunRelease `yaml:"releases" json:"releases"`               // in deployment order
+}
+
+// Outcome describes the run outcome, "completed" or "failed".
+func (r *Run) Outcome() string {
+	if r.Error != "" {
+		return "failed"
+	}
+	return "completed"
+}
+
+// NewRunID returns the deploy run identifier of the start time, its UTC
+// timestamp, e.g. "20260102-030405".
+func NewRunID(start time.Time) string {
+	return start.UTC().Format("20060102-150405")
+}
+
+// NewRunRelease describes the dependency release, nil when not installed, with
+// the dependency result on the run, when part of it.
+func NewRunRelease(
+	dep *resolver.Dependency,
+	rel *release.Release,
+	result Status,
+) RunRelease {
+	r := RunRelease{
+		Dependency: dep.Name(),
+		Namespace:  dep.Namespace(),
+		Chart:      dep.Chart().Name(),
+		Status:     ReleaseNotInstalled,
+		Result:     result,
+	}
+	if rel == nil {
+		return r
+	}
+	r.Revision = rel.Version
+	if rel.Info != nil {
+		r.Status = rel.Info.Status.String()
+	}
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		r.Version = rel.Chart.Metadata.Version
+	}
+	return r
+}
+
+// RecordRun appends the deploy run, keeping the latest RunsSize runs.
+func (h *History) RecordRun(r Run) {
+	h.runs = append(h.runs, r)
+	if len(h.runs) > RunsSize {
+		h.runs = h.runs[len(h.runs)-RunsSize:]
+	}
+}
+
+// Runs returns the recorded deploy runs, oldest first.
+func (h *History) Runs() []Run {
+	return h.runs
+}
+
+// GetRun returns the deploy run by ID, or the most recent for LatestRun.
+func (h *History) GetRun(id string) (*Run, error) {
+	if id == LatestRun && len(h.runs) > 0 {
+		return &h.runs[len(h.runs)-1], nil
+	}
+	for i := range h.runs {
+		if h.runs[i].ID == id {
+			return &h.runs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q, %d run(s) recorded", ErrRunNotFound,
+		id, len(h.runs))
+}
+
+// RunChange a dependency release which differs between two deploy runs.
+type RunChange struct {
+	Dependency string // dependency name
+	Before     string // release on the earlier run, "version (revision)"
+	After      string // release on the later run, "version (revision)"
+}
+
+// describe describes the release version and revision.
+func (r RunRelease) describe() string {
+	if r.Revision == 0 {
+		return r.Status
+	}
+	return fmt.Sprintf("%s (revision %d)", r.Version, r.Revision)
+}
+
+// DiffRuns returns the releases changed from the earlier to the later run, in
+// the later run order, followed by the ones gone from the topology.
+func DiffRuns(earlier, later *Run) []RunChange {
+	before := map[string]RunRelease{}
+	for _, r := range earlier.Releases {
+		before[r.Dependency] = r
+	}
+	changes := []RunChange{}
+	for _, r := range later.Releases {
+		b, found := before[r.Dependency]
+		delete(before, r.Dependency)
+		switch {
+		case !found:
+			changes = append(changes, RunChange{
+				Dependency: r.Dependency, Before: "-", After: r.describe(),
+			})
+		case b.Version != r.Version || b.Revision != r.Revision ||
+			b.Status != r.Status:
+			changes = append(changes, RunChange{
+				Dependency: r.Dependency, Before: b.describe(), After: r.describe(),
+			})
+		}
+	}
+	for _, r := range earlier.Releases {
+		if _, gone := before[r.Dependency]; gone {
+			changes = append(changes, RunChange{
+				Dependency: r.Dependency, Before: r.describe(), After: "-",
+			})
+		}
+	}
+	return changes
+}
+
+// PrintRuns prints the deploy runs table, most recent first.
+func PrintRuns(w io.Writer, runs []Run) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(a ...any) {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", a...)
+	}
+	row("Run", "Time", "Config", "Outcome", "Releases")
+	for i := len(runs) - 1; i >= 0; i-- {
+		r := runs[i]
+		row(r.ID, r.Time.Format(time.RFC3339), ShortHash(r.ConfigHash),
+			r.Outcome(), strconv.Itoa(len(r.Releases)))
+	}
+	_ = table.Flush()
+}
+
+// PrintReleases prints the run releases table.
+func (r *Run) PrintReleases(w io.Writer) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(a ...any) {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", a...)
+	}
+	row("Dependency", "Namespace", "Chart", "Version", "Revision", "Status",
+		"Run")
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	for _, rel := range r.Releases {
+		revision := "-"
+		if rel.Revision > 0 {
+			revision = strconv.Itoa(rel.Revision)
+		}
+		row(rel.Dependency, rel.Namespace, rel.Chart, orDash(rel.Version),
+			revision, rel.Status, orDash(string(rel.Result)))
+	}
+	_ = table.Flush()
+}
+
+// ShortHash abbreviates the configuration hash, like git does.
+func ShortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
@@ -0,0 +1,112 @@
+package installer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRuns(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	g.Expect(NewRunID(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))).
+		To(o.Equal("20260102-030405"))
+
+	kube := k8s.NewFakeKube(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      HistoryName("helmet-ex"),
+			Namespace: "installer",
+		},
+		Data: map[string]string{
+			RunsKey: `
+- id: 20260102-030405
+  time: 2026-01-02T03:04:05Z
+  configHash: 0123456789abcdef
+  releases:
+  - {dependency: operators, namespace: ops, chart: operators, version: 1.0.0, revision: 1, status: deployed, result: deployed}
+  - {dependency: product-a, namespace: a, chart: product-a, version: 2.0.0, revision: 3, status: deployed}
+  - {dependency: product-b, namespace: b, chart: product-b, status: not installed}
+- id: 20260103-030405
+  time: 2026-01-03T03:04:05Z
+  configHash: fedcba9876543210
+  error: "dependency \"product-a\" failed"
+  releases:
+  - {dependency: operators, namespace: ops, chart: operators, version: 1.0.0, revision: 1, status: deployed}
+  - {dependency: product-a, namespace: a, chart: product-a, version: 2.1.0, revision: 4, status: failed, result: failed}
+  - {dependency: product-c, namespace: c, chart: product-c, status: not installed}
+`,
+		},
+	})
+	h := NewHistory(kube, "installer", "helmet-ex")
+	g.Expect(h.Load(ctx)).To(o.Succeed())
+	g.Expect(h.Runs()).To(o.HaveLen(2))
+
+	t.Run("GetRun", func(t *testing.T) {
+		g := o.NewWithT(t)
+		run, err := h.GetRun("20260102-030405")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(run.Outcome()).To(o.Equal("completed"))
+		g.Expect(run.Releases[0].Result).To(o.Equal(StatusDeployed))
+
+		latest, err := h.GetRun(LatestRun)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(latest.ID).To(o.Equal("20260103-030405"))
+		g.Expect(latest.Outcome()).To(o.Equal("failed"))
+
+		_, err = h.GetRun("20250102-030405")
+		g.Expect(err).To(o.MatchError(ErrRunNotFound))
+		_, err = NewHistory(k8s.NewFakeKube(), "installer", "helmet-ex").
+			GetRun(LatestRun)
+		g.Expect(err).To(o.MatchError(ErrRunNotFound))
+	})
+
+	t.Run("DiffRuns", func(t *testing.T) {
+		g := o.NewWithT(t)
+		runs := h.Runs()
+		g.Expect(DiffRuns(&runs[0], &runs[1])).To(o.Equal([]RunChange{{
+			Dependency: "product-a",
+			Before:     "2.0.0 (revision 3)",
+			After:      "2.1.0 (revision 4)",
+		}, {
+			Dependency: "product-c",
+			Before:     "-",
+			After:      ReleaseNotInstalled,
+		}, {
+			Dependency: "product-b",
+			Before:     ReleaseNotInstalled,
+			After:      "-",
+		}}))
+		g.Expect(DiffRuns(&runs[1], &runs[1])).To(o.BeEmpty())
+	})
+
+	t.Run("Print", func(t *testing.T) {
+		g := o.NewWithT(t)
+		var out bytes.Buffer
+		PrintRuns(&out, h.Runs())
+		g.Expect(out.String()).To(o.MatchRegexp(
+			`(?s)20260103-030405.*fedcba987654\s+failed.*20260102-030405`))
+
+		out.Reset()
+		h.Runs()[0].PrintReleases(&out)
+		g.Expect(out.String()).To(o.MatchRegexp(
+			`product-b\s+b\s+product-b\s+-\s+-\s+not installed\s+-`))
+	})
+
+	t.Run("RecordRun", func(t *testing.T) {
+		g := o.NewWithT(t)
+		for i := range RunsSize {
+			h.RecordRun(Run{ID: NewRunID(time.Unix(int64(i), 0))})
+		}
+		g.Expect(h.Runs()).To(o.HaveLen(RunsSize))
+		g.Expect(h.Runs()[0].ID).To(o.Equal("19700101-000000"))
+		g.Expect(h.Save(ctx)).To(o.Succeed())
+	})
+}
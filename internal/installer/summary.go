@@ -0,0 +1,178 @@
+package installer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+)
+
+// Status the outcome of a dependency deployment.
+type Status string
+
+const (
+	// StatusDeployed deployed on the first attempt.
+	StatusDeployed Status = "deployed"
+	// StatusRetried deployed after retrying.
+	StatusRetried Status = "retried"
+	// StatusFailed failed after all attempts.
+	StatusFailed Status = "failed"
+	// StatusSkipped not attempted due to a previous failure.
+	StatusSkipped Status = "skipped"
+	// StatusEmitted emitted for the GitOps controller, not deployed.
+	StatusEmitted Status = "emitted"
+)
+
+// ErrDeployFailed one or more dependencies failed to deploy.
+var ErrDeployFailed = helmeterrors.New(helmeterrors.ErrDeployFailed,
+	"deploy failed")
+
+// Result the deployment outcome of a single dependency.
+type Result struct {
+	Name      string        // dependency name
+	Namespace string        // target namespace
+	Status    Status        // deployment outcome
+	Attempts  int           // number of attempts
+	Duration  time.Duration // time spent on all attempts
+	Expected  time.Duration // usual duration, from the deployment history
+	Err       error         // last error, or the skip reason
+}
+
+// Class returns the failure class of the result, empty when deployed.
+func (r *Result) Class() FailureClass {
+	if r.Status != StatusFailed {
+		return ""
+	}
+	return ClassifyFailure(r.Err)
+}
+
+// Summary collects the deployment results, and the retry budget used.
+type Summary struct {
+	results []Result // results in deployment order
+	budget  int      // total retries allowed
+	used    int      // retries consumed
+}
+
+// Add records the dependency result.
+func (s *Summary) Add(r Result) {
+	s.results = append(s.results, r)
+}
+
+// Results returns the dependency results, in deployment order.
+func (s *Summary) Results() []Result {
+	return s.results
+}
+
+// Retry consumes one retry from the budget, returns false when exhausted.
+func (s *Summary) Retry() bool {
+	if s.used >= s.budget {
+		return false
+	}
+	s.used++
+	return true
+}
+
+// Count returns the number of results with the status.
+func (s *Summary) Count(status Status) int {
+	count := 0
+	for _, r := range s.results {
+		if r.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Err returns ErrDeployFailed when any dependency failed or was skipped.
+func (s *Summary) Err() error {
+	failed, skipped := s.Count(StatusFailed), s.Count(StatusSkipped)
+	if failed == 0 && skipped == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d failed, %d skipped of %d dependencies",
+		ErrDeployFailed, failed, skipped, len(s.results))
+}
+
+// Violations returns the admission violations of the failed dependencies.
+func (s *Summary) Violations() []Violation {
+	violations := []Violation{}
+	for _, r := range s.results {
+		if r.Class() != FailureAdmission {
+			continue
+		}
+		for _, v := range ParseViolations(r.Err) {
+			v.Dependency, v.Namespace = r.Name, r.Namespace
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// Print prints the summary table to the writer, followed by the failure
+// details and the retry budget.
+func (s *Summary) Print(w io.Writer) {
+	fmt.Fprintf(w, "\n%s\n# Deployment Summary\n%s\n\n",
+		strings.Repeat("#", 60), strings.Repeat("#", 60))
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(a ...any) {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", a...)
+	}
+	row("Index", "Dependency", "Namespace", "Status", "Attempts", "Failure",
+		"Duration", "Expected")
+	for i, r := range s.results {
+		expected := "-"
+		if r.Expected > 0 {
+			expected = "~" + roughDuration(r.Expected)
+		}
+		row(
+			fmt.Sprintf("%2d", i+1),
+			r.Name,
+			r.Namespace,
+			string(r.Status),
+			fmt.Sprintf("%d", r.Attempts),
+			string(r.Class()),
+			r.Duration.Round(time.Second).String(),
+			expected,
+		)
+	}
+	table.Flush()
+
+	fmt.Fprintf(w, "\nDeployed: %d, Retried: %d, Failed: %d, Skipped: %d\n",
+		s.Count(StatusDeployed),
+		s.Count(StatusRetried),
+		s.Count(StatusFailed),
+		s.Count(StatusSkipped),
+	)
+	if emitted := s.Count(StatusEmitted); emitted > 0 {
+		fmt.Fprintf(w, "Emitted for GitOps: %d\n", emitted)
+	}
+	fmt.Fprintf(w, "Retry budget: %d of %d used\n", s.used, s.budget)
+
+	for _, r := range s.results {
+		if r.Err == nil {
+			continue
+		}
+		switch r.Status {
+		case StatusFailed:
+			fmt.Fprintf(w, "\n# %s (%s):\n%s\n", r.Name, r.Class(), r.Err)
+			if r.Class() != FailureAdmission {
+				continue
+			}
+			fmt.Fprintf(w, "\nPolicy violations:\n")
+			for _, v := range ParseViolations(r.Err) {
+				fmt.Fprintf(w, "  - %s\n", v)
+			}
+		case StatusSkipped:
+			fmt.Fprintf(w, "\n# %s (skipped): %s\n", r.Name, r.Err)
+		}
+	}
+}
+
+// NewSummary instantiates the summary with the retry budget.
+func NewSummary(budget int) *Summary {
+	return &Summary{budget: budget}
+}
@@ -0,0 +1,161 @@
+package integration
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ACR represents the Azure Container Registry integration coordinates,
+// authenticated with a service principal, or a repository scoped token.
+type ACR struct {
+	url          string // registry URL
+	clientID     string // service principal client ID
+	clientSecret string // service principal client secret
+	tokenName    string // repository scoped token name
+	token        string // repository scoped token password
+
+	client *http.Client // registry http client
+}
+
+var _ Interface = &ACR{}
+var _ Credential = &ACR{}
+var _ Verifier = &ACR{}
+
+// CredentialFlag the service principal secret can be informed via STDIN or the
+// keychain.
+func (a *ACR) CredentialFlag() string {
+	return "client-secret"
+}
+
+// PersistentFlags adds the persistent flags to the informed Cobra command.
+func (a *ACR) PersistentFlags(c *cobra.Command) {
+	p := c.PersistentFlags()
+
+	p.StringVar(&a.url, "url", a.url,
+		"Container registry URL, e.g. https://myregistry.azurecr.io")
+	p.StringVar(&a.clientID, "client-id", a.clientID,
+		"Service principal client ID, with the AcrPush role")
+	p.StringVar(&a.clientSecret, "client-secret", a.clientSecret,
+		"Service principal client secret")
+	p.StringVar(&a.tokenName, "token-name", a.tokenName,
+		"Repository scoped token name, instead of a service principal")
+	p.StringVar(&a.token, "token", a.token,
+		"Repository scoped token password")
+
+	for _, f := range []string{"url"} {
+		if err := c.MarkPersistentFlagRequired(f); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// SetArgument sets additional arguments to the integration.
+func (a *ACR) SetArgument(string, string) error {
+	return nil
+}
+
+// LoggerWith decorates the logger with the integration flags.
+func (a *ACR) LoggerWith(logger *slog.Logger) *slog.Logger {
+	return logger.With(
+		"url", a.url,
+		"client-id", a.clientID,
+		"client-secret-len", len(a.clientSecret),
+		"token-name", a.tokenName,
+		"token-len", len(a.token),
+	)
+}
+
+// credentials returns the registry username and password, of the service
+// principal or the token.
+func (a *ACR) credentials() (string, string) {
+	if a.clientID != "" {
+		return a.clientID, a.clientSecret
+	}
+	return a.tokenName, a.token
+}
+
+// Validate validates the integration configuration, either the service
+// principal or the token must be informed.
+func (a *ACR) Validate() error {
+	if err := ValidateURL(a.url); err != nil {
+		return err
+	}
+	principal := a.clientID != "" || a.clientSecret != ""
+	token := a.tokenName != "" || a.token != ""
+	switch {
+	case principal && token:
+		return fmt.Errorf("either the service principal or the token must " +
+			"be specified, not both")
+	case principal && (a.clientID == "" || a.clientSecret == ""):
+		return fmt.Errorf("client-id and client-secret are required for the " +
+			"service principal")
+	case token && (a.tokenName == "" || a.token == ""):
+		return fmt.Errorf("token-name and token are required for the token")
+	case !principal && !token:
+		return fmt.Errorf("either the service principal or the token is " +
+			"required")
+	}
+	return nil
+}
+
+// Type returns the type of the integration.
+func (a *ACR) Type() corev1.SecretType {
+	return corev1.SecretTypeDockerConfigJson
+}
+
+// Data returns the ACR integration data, the registry credentials are stored as
+// the ".dockerconfigjson".
+func (a *ACR) Data(
+	_ context.Context,
+	_ *runcontext.RunContext,
+	_ *config.Config,
+) (map[string][]byte, error) {
+	u, err := url.Parse(a.url)
+	if err != nil {
+		return nil, err
+	}
+	username, password := a.credentials()
+	dockerConfig, err := json.Marshal(map[string]any{
+		"auths": map[string]any{
+			u.Host: map[string]string{
+				"username": username,
+				"password": password,
+				"auth": base64.StdEncoding.EncodeToString(
+					[]byte(username + ":" + password)),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		".dockerconfigjson": dockerConfig,
+		"url":               []byte(a.url),
+		"client-id":         []byte(a.clientID),
+		"client-secret":     []byte(a.clientSecret),
+		"token-name":        []byte(a.tokenName),
+		"token":             []byte(a.token),
+	}, nil
+}
+
+// Verify asserts the registry accepts the stored credentials.
+func (a *ACR) Verify(ctx context.Context, data map[string][]byte) error {
+	return verifyDockerConfig(ctx, a.client, data[".dockerconfigjson"])
+}
+
+// NewACR instantiates a new ACR integration.
+func NewACR() *ACR {
+	return &ACR{client: &http.Client{Timeout: 30 * time.Second}}
+}
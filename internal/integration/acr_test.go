@@ -0,0 +1,47 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestACR(t *testing.T) {
+	g := o.NewWithT(t)
+
+	a := NewACR()
+	a.url = "https://example.azurecr.io"
+	g.Expect(a.Validate()).To(o.HaveOccurred())
+	a.clientID = "00000000-0000-0000-0000-000000000000"
+	g.Expect(a.Validate()).To(o.HaveOccurred())
+	a.clientSecret = "secret"
+	g.Expect(a.Validate()).To(o.Succeed())
+	a.token = "password"
+	g.Expect(a.Validate()).To(o.HaveOccurred())
+
+	data, err := a.Data(context.Background(), nil, nil)
+	g.Expect(err).To(o.Succeed())
+	// "00000000-0000-0000-0000-000000000000:secret", base64 encoded.
+	g.Expect(string(data[".dockerconfigjson"])).To(o.MatchJSON(`{"auths": {
+		"example.azurecr.io": {
+			"username": "00000000-0000-0000-0000-000000000000",
+			"password": "secret",
+			"auth": "MDAwMDAwMDAtMDAwMC0wMDAwLTAwMDAtMDAwMDAwMDAwMDAwOnNlY3JldA=="
+		}
+	}}`))
+
+	a = NewACR()
+	a.url = "https://example.azurecr.io"
+	a.tokenName = "pull-token"
+	g.Expect(a.Validate()).To(o.HaveOccurred())
+	a.token = "password"
+	g.Expect(a.Validate()).To(o.Succeed())
+	data, err = a.Data(context.Background(), nil, nil)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(string(data[".dockerconfigjson"])).To(
+		o.ContainSubstring(`"username":"pull-token"`))
+
+	a.url = "example.azurecr.io"
+	g.Expect(a.Validate()).To(o.MatchError(ErrInvalidURL))
+}
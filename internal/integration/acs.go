@@ -21,6 +21,12 @@ type ACS struct {
 }
 
 var _ Interface = &ACS{}
+var _ Credential = &ACS{}
+
+// CredentialFlag the ACS API token can be informed via STDIN or the keychain.
+func (a *ACS) CredentialFlag() string {
+	return "token"
+}
 
 // PersistentFlags adds the persistent flags to the informed Cobra command.
 func (a *ACS) PersistentFlags(c *cobra.Command) {
@@ -23,6 +23,12 @@ type Azure struct {
 }
 
 var _ Interface = &Azure{}
+var _ Credential = &Azure{}
+
+// CredentialFlag the Azure API token can be informed via STDIN or the keychain.
+func (a *Azure) CredentialFlag() string {
+	return "token"
+}
 
 // PersistentFlags adds the persistent flags to the informed Cobra command.
 func (a *Azure) PersistentFlags(c *cobra.Command) {
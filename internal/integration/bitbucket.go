@@ -19,6 +19,12 @@ type BitBucket struct {
 }
 
 var _ Interface = &BitBucket{}
+var _ Credential = &BitBucket{}
+
+// CredentialFlag the BitBucket app password can be informed via STDIN or the keychain.
+func (b *BitBucket) CredentialFlag() string {
+	return "app-password"
+}
 
 // PersistentFlags adds the persistent flags to the informed Cobra command.
 func (b *BitBucket) PersistentFlags(c *cobra.Command) {
@@ -0,0 +1,53 @@
+package integration
+
+import "slices"
+
+// Capability an optional feature of an integration, beyond storing the informed
+// values on the integration secret.
+type Capability string
+
+const (
+	// CapabilityVerification the informed credentials are verified against the
+	// provider API before the secret is stored, or the stored ones on demand,
+	// see Verifier.
+	CapabilityVerification Capability = "verification"
+	// CapabilityProvisioning resources are created on the provider, like the
+	// GitHub App, instead of informed.
+	CapabilityProvisioning Capability = "provisioning"
+	// CapabilityRotation the token expiry is discovered from the provider API,
+	// so the token rotation is reported when due, see Expirer.
+	CapabilityRotation Capability = "rotation"
+)
+
+// Capable is implemented by integrations reaching the provider API on Data,
+// it returns the capabilities besides the ones inferred, like rotation.
+type Capable interface {
+	// Capabilities returns the integration capabilities.
+	Capabilities() []Capability
+}
+
+// Capabilities returns the capabilities of the integration data, verifiers are
+// capable of verification.
+func (i *Integration) Capabilities() []Capability {
+	capabilities := []Capability{}
+	if c, ok := i.data.(Capable); ok {
+		capabilities = append(capabilities, c.Capabilities()...)
+	}
+	if _, ok := i.data.(Verifier); ok &&
+		!slices.Contains(capabilities, CapabilityVerification) {
+		capabilities = append(capabilities, CapabilityVerification)
+	}
+	if _, ok := i.data.(Expirer); ok {
+		capabilities = append(capabilities, CapabilityRotation)
+	}
+	return capabilities
+}
+
+// CredentialFlag returns the flag holding the sensitive credential, which value
+// can be read from STDIN or the keychain, empty when the integration has none.
+func (i *Integration) CredentialFlag() string {
+	if c, ok := i.data.(Credential); ok {
+		return c.CredentialFlag()
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+package integration
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/keychain"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// Credential is implemented by integrations having a sensitive flag, which value
+// can be read from STDIN, the OS keychain or an interactive prompt instead of
+// the command-line, keeping it out of the shell history.
+type Credential interface {
+	// CredentialFlag returns the name of the flag holding the credential.
+	CredentialFlag() string
+}
+
+// CredentialRequiredAnnotation flag annotation marking a required credential,
+// it replaces cobra's required flag annotation, the credential may come from
+// other sources than the command-line.
+const CredentialRequiredAnnotation = "helmet_credential_required"
+
+// ErrCredentialRequired the credential is not informed by any source.
+var ErrCredentialRequired = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"credential is required")
+
+// credentialFlags decorates the command with the credential flags, the
+// credential flag is no longer required by cobra, it's resolved on Complete.
+func (i *Integration) credentialFlags(cmd *cobra.Command) {
+	c, ok := i.data.(Credential)
+	if !ok {
+		return
+	}
+	i.credentialFlag = c.CredentialFlag()
+	flag := cmd.PersistentFlags().Lookup(i.credentialFlag)
+	if flag == nil {
+		panic(fmt.Sprintf("credential flag %q is not defined", i.credentialFlag))
+	}
+	if _, required := flag.Annotations[cobra.BashCompOneRequiredFlag]; required {
+		i.credentialRequired = true
+		delete(flag.Annotations, cobra.BashCompOneRequiredFlag)
+		flag.Annotations[CredentialRequiredAnnotation] = []string{"true"}
+	}
+
+	p := cmd.PersistentFlags()
+	p.BoolVar(&i.credentialStdin, i.credentialFlag+"-stdin", i.credentialStdin,
+		fmt.Sprintf("Reads the %s from STDIN", i.credentialFlag))
+	p.BoolVar(&i.credentialStore, "keychain", i.credentialStore,
+		fmt.Sprintf("Stores the %s in the OS keychain for reuse", i.credentialFlag))
+}
+
+// readCredentialStdin reads the first line of the input.
+func readCredentialStdin(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// promptCredential prompts for the credential when the input is a terminal,
+// the typed characters are not echoed.
+func (i *Integration) promptCredential() (string, bool, error) {
+	f, ok := i.cmd.InOrStdin().(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return "", false, nil
+	}
+	fmt.Fprintf(i.cmd.ErrOrStderr(), "%s: ", i.credentialFlag)
+	secret, err := term.ReadPassword(int(f.Fd()))
+	fmt.Fprintln(i.cmd.ErrOrStderr())
+	if err != nil {
+		return "", false, err
+	}
+	return string(secret), true, nil
+}
+
+// credential resolves the credential value, in order: the command-line flag,
+// STDIN, the OS keychain and at last an interactive prompt. Returns whether the
+// value should be stored in the keychain.
+func (i *Integration) credential() (string, bool, error) {
+	flag := i.cmd.Flags().Lookup(i.credentialFlag)
+	if flag.Changed {
+		return flag.Value.String(), true, nil
+	}
+	if i.credentialStdin {
+		secret, err := readCredentialStdin(i.cmd.InOrStdin())
+		return secret, true, err
+	}
+	secret, err := i.keychain.Get(i.name, i.credentialFlag)
+	switch {
+	case err == nil:
+		i.log().Debug("Using the credential stored in the keychain",
+			"flag", i.credentialFlag)
+		return secret, false, nil
+	case !errors.Is(err, keychain.ErrNotFound) &&
+		!errors.Is(err, keychain.ErrUnsupported):
+		i.log().Warn("Unable to read the keychain", "err", err)
+	}
+	if !i.credentialRequired {
+		return "", false, nil
+	}
+	secret, prompted, err := i.promptCredential()
+	if err != nil {
+		return "", false, err
+	}
+	if !prompted {
+		return "", false, fmt.Errorf("%w: use --%s, --%s-stdin or the keychain",
+			ErrCredentialRequired, i.credentialFlag, i.credentialFlag)
+	}
+	return secret, true, nil
+}
+
+// Complete resolves the integration credential, when the integration supports
+// it, and stores it in the keychain when requested.
+func (i *Integration) Complete() error {
+	if i.credentialFlag == "" {
+		return nil
+	}
+	secret, store, err := i.credential()
+	if err != nil {
+		return err
+	}
+	if secret == "" {
+		if i.credentialRequired {
+			return fmt.Errorf("%w: %s is empty",
+				ErrCredentialRequired, i.credentialFlag)
+		}
+		return nil
+	}
+	if err = i.cmd.Flags().Set(i.credentialFlag, secret); err != nil {
+		return err
+	}
+	if store && i.credentialStore {
+		i.log().Debug("Storing the credential in the keychain",
+			"flag", i.credentialFlag)
+		return i.keychain.Set(i.name, i.credentialFlag, secret)
+	}
+	return nil
+}
+
+// SetKeychain sets the keychain used to store credentials.
+func (i *Integration) SetKeychain(kc keychain.Interface) {
+	i.keychain = kc
+}
@@ -0,0 +1,129 @@
+package integration
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/keychain"
+
+	"github.com/spf13/cobra"
+)
+
+const testSecretName = "test-gitlab-integration"
+
+// testCredentialIntegration instantiates the GitLab integration decorating a
+// command with informed input, using an in-memory keychain.
+func testCredentialIntegration(
+	t *testing.T,
+	kc keychain.Interface,
+	stdin string,
+	args ...string,
+) (*Integration, *cobra.Command) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	i := NewSecret(logger, nil, testSecretName, NewGitLab(logger))
+	i.SetKeychain(kc)
+	cmd := &cobra.Command{Use: "gitlab"}
+	cmd.SetIn(strings.NewReader(stdin))
+	i.PersistentFlags(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	return i, cmd
+}
+
+func TestIntegrationCredential(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name        string
+		stored      string
+		stdin       string
+		args        []string
+		expected    string
+		expectedKC  string
+		expectedErr error
+	}{
+		{
+			name:     "flag",
+			args:     []string{"--token=flag"},
+			expected: "flag",
+		},
+		{
+			name:       "flag stored in keychain",
+			args:       []string{"--token=flag", "--keychain"},
+			expected:   "flag",
+			expectedKC: "flag",
+		},
+		{
+			name:     "stdin",
+			stdin:    "stdin\n",
+			args:     []string{"--token-stdin"},
+			expected: "stdin",
+		},
+		{
+			name:       "stdin takes precedence over keychain",
+			stored:     "stored",
+			stdin:      "stdin\n",
+			args:       []string{"--token-stdin", "--keychain"},
+			expected:   "stdin",
+			expectedKC: "stdin",
+		},
+		{
+			name:       "keychain",
+			stored:     "stored",
+			expected:   "stored",
+			expectedKC: "stored",
+		},
+		{
+			name:        "missing credential",
+			expectedErr: ErrCredentialRequired,
+		},
+		{
+			name:        "empty stdin",
+			args:        []string{"--token-stdin"},
+			expectedErr: ErrCredentialRequired,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			kc := keychain.NewMemory()
+			if tc.stored != "" {
+				_ = kc.Set(testSecretName, "token", tc.stored)
+			}
+			i, cmd := testCredentialIntegration(t, kc, tc.stdin, tc.args...)
+
+			err := i.Complete()
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected err %v, got %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if got := cmd.Flags().Lookup("token").Value.String(); got != tc.expected {
+				t.Errorf("expected token %q, got %q", tc.expected, got)
+			}
+			stored, _ := kc.Get(testSecretName, "token")
+			if stored != tc.expectedKC {
+				t.Errorf("expected keychain %q, got %q", tc.expectedKC, stored)
+			}
+		})
+	}
+}
+
+func TestIntegrationCredentialNotRequired(t *testing.T) {
+	t.Parallel()
+	// The credential flag is no longer enforced by cobra.
+	_, cmd := testCredentialIntegration(t, keychain.NewMemory(), "")
+	flag := cmd.PersistentFlags().Lookup("token")
+	if _, ok := flag.Annotations[cobra.BashCompOneRequiredFlag]; ok {
+		t.Errorf("expected token flag not to be required by cobra")
+	}
+	if _, ok := flag.Annotations[CredentialRequiredAnnotation]; !ok {
+		t.Errorf("expected token flag to be annotated as required credential")
+	}
+}
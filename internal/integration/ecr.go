@@ -0,0 +1,258 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ErrECRRequest the ECR API, or the registry, refused or failed the request.
+var ErrECRRequest = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"ecr request failed")
+
+// ecrRegistryRe matches the private ECR registry hostname, capturing the region,
+// "<account>.dkr.ecr.<region>.amazonaws.com".
+var ecrRegistryRe = regexp.MustCompile(
+	`^\d{12}\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+
+// ECR represents the AWS Elastic Container Registry integration coordinates,
+// authenticated with an access key, or an IAM role assumed by the workloads
+// through IRSA (IAM Roles for Service Accounts).
+type ECR struct {
+	registry        string // registry hostname
+	region          string // AWS region
+	accessKeyID     string // access key ID
+	secretAccessKey string // secret access key
+	roleARN         string // IAM role assumed with IRSA
+
+	client      *http.Client // registry and ECR API http client
+	apiEndpoint string       // ECR API endpoint, empty for the regional one
+	expiresAt   *time.Time   // registry token expiry, obtained on Data
+}
+
+var _ Interface = &ECR{}
+var _ Credential = &ECR{}
+var _ Expirer = &ECR{}
+var _ Capable = &ECR{}
+var _ Verifier = &ECR{}
+
+// Capabilities the access key is verified obtaining a registry token.
+func (e *ECR) Capabilities() []Capability {
+	return []Capability{CapabilityVerification}
+}
+
+// CredentialFlag the secret access key can be informed via STDIN or the
+// keychain.
+func (e *ECR) CredentialFlag() string {
+	return "secret-access-key"
+}
+
+// PersistentFlags adds the persistent flags to the informed Cobra command.
+func (e *ECR) PersistentFlags(c *cobra.Command) {
+	p := c.PersistentFlags()
+
+	p.StringVar(&e.registry, "registry", e.registry,
+		"ECR registry hostname, e.g. 123456789012.dkr.ecr.us-east-1.amazonaws.com")
+	p.StringVar(&e.region, "region", e.region,
+		"AWS region, by default extracted from the registry hostname")
+	p.StringVar(&e.accessKeyID, "access-key-id", e.accessKeyID,
+		"AWS access key ID, with the ecr:GetAuthorizationToken permission")
+	p.StringVar(&e.secretAccessKey, "secret-access-key", e.secretAccessKey,
+		"AWS secret access key")
+	p.StringVar(&e.roleARN, "role-arn", e.roleARN,
+		"IAM role assumed by the workloads with IRSA, instead of an access key")
+
+	for _, f := range []string{"registry"} {
+		if err := c.MarkPersistentFlagRequired(f); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// SetArgument sets additional arguments to the integration.
+func (e *ECR) SetArgument(string, string) error {
+	return nil
+}
+
+// LoggerWith decorates the logger with the integration flags.
+func (e *ECR) LoggerWith(logger *slog.Logger) *slog.Logger {
+	return logger.With(
+		"registry", e.registry,
+		"region", e.region,
+		"access-key-id", e.accessKeyID,
+		"secret-access-key-len", len(e.secretAccessKey),
+		"role-arn", e.roleARN,
+	)
+}
+
+// accessKey returns whether the integration authenticates with an access key,
+// otherwise with the IRSA role.
+func (e *ECR) accessKey() bool {
+	return e.accessKeyID != ""
+}
+
+// Type returns the type of the integration, the access key generates registry
+// credentials.
+func (e *ECR) Type() corev1.SecretType {
+	if e.accessKey() {
+		return corev1.SecretTypeDockerConfigJson
+	}
+	return corev1.SecretTypeOpaque
+}
+
+// Validate validates the integration configuration, either the access key or
+// the IRSA role must be informed.
+func (e *ECR) Validate() error {
+	if e.region == "" {
+		match := ecrRegistryRe.FindStringSubmatch(e.registry)
+		if match == nil {
+			return fmt.Errorf("unable to extract the region from the registry "+
+				"%q, use --region", e.registry)
+		}
+		e.region = match[1]
+	}
+	switch {
+	case e.accessKeyID != "" && e.secretAccessKey == "":
+		return fmt.Errorf("secret-access-key is required when access-key-id " +
+			"is specified")
+	case e.accessKeyID == "" && e.secretAccessKey != "":
+		return fmt.Errorf("access-key-id is required when secret-access-key " +
+			"is specified")
+	case e.accessKey() && e.roleARN != "":
+		return fmt.Errorf("either the access key or role-arn must be " +
+			"specified, not both")
+	case !e.accessKey() && e.roleARN == "":
+		return fmt.Errorf("either the access key or role-arn is required")
+	}
+	return nil
+}
+
+// authorizationToken obtains the registry token with the access key, the base64
+// encoded "AWS:<password>" pair, and its expiry.
+func (e *ECR) authorizationToken(
+	ctx context.Context,
+) (string, *time.Time, error) {
+	opts := ecr.Options{
+		Region: e.region,
+		Credentials: credentials.NewStaticCredentialsProvider(
+			e.accessKeyID, e.secretAccessKey, ""),
+		HTTPClient: e.client,
+	}
+	if e.apiEndpoint != "" {
+		opts.BaseEndpoint = &e.apiEndpoint
+	}
+	out, err := ecr.New(opts).GetAuthorizationToken(
+		ctx, &ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %w", ErrECRRequest, err)
+	}
+	if len(out.AuthorizationData) == 0 ||
+		out.AuthorizationData[0].AuthorizationToken == nil {
+		return "", nil, fmt.Errorf("%w: no authorization data returned",
+			ErrECRRequest)
+	}
+	data := out.AuthorizationData[0]
+	return *data.AuthorizationToken, data.ExpiresAt, nil
+}
+
+// ping asserts the registry is reachable, with the informed token accepted.
+// Without a token, the registry must answer asking for authentication.
+func (e *ECR) ping(ctx context.Context, token string) error {
+	endpoint := fmt.Sprintf("https://%s/v2/", e.registry)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Basic "+token)
+	}
+	res, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: registry unreachable: %w", ErrECRRequest, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusOK ||
+		(token == "" && res.StatusCode == http.StatusUnauthorized) {
+		return nil
+	}
+	return fmt.Errorf("%w: GET %s: %s", ErrECRRequest, endpoint, res.Status)
+}
+
+// ExpiresAt returns the registry token expiry, obtained on Data.
+func (e *ECR) ExpiresAt() *time.Time {
+	return e.expiresAt
+}
+
+// Data returns the ECR integration data. With the access key, a registry token
+// is obtained and stored as the ".dockerconfigjson", after asserting the
+// registry accepts it; with the IRSA role, the registry must be reachable.
+func (e *ECR) Data(
+	ctx context.Context,
+	_ *runcontext.RunContext,
+	_ *config.Config,
+) (map[string][]byte, error) {
+	data := map[string][]byte{
+		"registry":          []byte(e.registry),
+		"region":            []byte(e.region),
+		"access-key-id":     []byte(e.accessKeyID),
+		"secret-access-key": []byte(e.secretAccessKey),
+		"role-arn":          []byte(e.roleARN),
+	}
+	if !e.accessKey() {
+		return data, e.ping(ctx, "")
+	}
+	token, expiresAt, err := e.authorizationToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = e.ping(ctx, token); err != nil {
+		return nil, err
+	}
+	e.expiresAt = expiresAt
+	dockerConfig, err := json.Marshal(map[string]any{
+		"auths": map[string]any{
+			e.registry: map[string]string{"auth": token},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	data[".dockerconfigjson"] = dockerConfig
+	return data, nil
+}
+
+// Verify asserts the stored access key obtains a registry token the registry
+// accepts; with the IRSA role, the registry must be reachable.
+func (e *ECR) Verify(ctx context.Context, data map[string][]byte) error {
+	e.registry = string(data["registry"])
+	e.region = string(data["region"])
+	e.accessKeyID = string(data["access-key-id"])
+	e.secretAccessKey = string(data["secret-access-key"])
+	e.roleARN = string(data["role-arn"])
+	if !e.accessKey() {
+		return e.ping(ctx, "")
+	}
+	token, _, err := e.authorizationToken(ctx)
+	if err != nil {
+		return err
+	}
+	return e.ping(ctx, token)
+}
+
+// NewECR instantiates a new ECR integration.
+func NewECR() *ECR {
+	return &ECR{client: &http.Client{Timeout: 30 * time.Second}}
+}
@@ -0,0 +1,219 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ecrToken the base64 encoded "AWS:password" registry token.
+const ecrToken = "QVdTOnBhc3N3b3Jk"
+
+// fakeECR a minimal ECR API, answering GetAuthorizationToken, and registry.
+func fakeECR(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.Header.Get("X-Amz-Target"), "GetAuthorizationToken"):
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential=AKIAEXAMPLE/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"authorizationData": []map[string]any{{
+				"authorizationToken": ecrToken,
+				"expiresAt":          1.7e9,
+			}},
+		})
+	case r.URL.Path == "/v2/":
+		if r.Header.Get("Authorization") != "Basic "+ecrToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+const ecrRoleARN = "arn:aws:iam::123456789012:role/ecr-pull"
+
+func TestECRValidate(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name            string
+		registry        string
+		region          string
+		accessKeyID     string
+		secretAccessKey string
+		roleARN         string
+		expectedRegion  string
+		expectedErr     string
+	}{
+		{
+			name:           "region from the registry",
+			registry:       "123456789012.dkr.ecr.eu-west-1.amazonaws.com",
+			roleARN:        ecrRoleARN,
+			expectedRegion: "eu-west-1",
+		},
+		{
+			name:        "region required",
+			registry:    "registry.example.com",
+			roleARN:     ecrRoleARN,
+			expectedErr: "--region",
+		},
+		{
+			name:        "credential required",
+			registry:    "registry.example.com",
+			region:      "us-east-1",
+			expectedErr: "either the access key or role-arn is required",
+		},
+		{
+			name:        "secret access key required",
+			registry:    "registry.example.com",
+			region:      "us-east-1",
+			accessKeyID: "AKIAEXAMPLE",
+			expectedErr: "secret-access-key is required",
+		},
+		{
+			name:            "access key",
+			registry:        "registry.example.com",
+			region:          "us-east-1",
+			accessKeyID:     "AKIAEXAMPLE",
+			secretAccessKey: "secret",
+			expectedRegion:  "us-east-1",
+		},
+		{
+			name:            "access key and role",
+			registry:        "registry.example.com",
+			region:          "us-east-1",
+			accessKeyID:     "AKIAEXAMPLE",
+			secretAccessKey: "secret",
+			roleARN:         ecrRoleARN,
+			expectedErr:     "not both",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			e := NewECR()
+			e.registry = tc.registry
+			e.region = tc.region
+			e.accessKeyID = tc.accessKeyID
+			e.secretAccessKey = tc.secretAccessKey
+			e.roleARN = tc.roleARN
+			err := e.Validate()
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected err %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e.region != tc.expectedRegion {
+				t.Errorf("expected region %q, got %q", tc.expectedRegion, e.region)
+			}
+		})
+	}
+}
+
+func TestECRData(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name            string
+		accessKeyID     string
+		secretAccessKey string
+		roleARN         string
+		unreachable     bool
+		expectedType    corev1.SecretType
+		expectedExpiry  bool
+		expectedErr     error
+	}{
+		{
+			name:            "access key",
+			accessKeyID:     "AKIAEXAMPLE",
+			secretAccessKey: "secret",
+			expectedType:    corev1.SecretTypeDockerConfigJson,
+			expectedExpiry:  true,
+		},
+		{
+			name:            "invalid access key",
+			accessKeyID:     "AKIAINVALID",
+			secretAccessKey: "secret",
+			expectedErr:     ErrECRRequest,
+		},
+		{
+			name:         "IRSA",
+			roleARN:      ecrRoleARN,
+			expectedType: corev1.SecretTypeOpaque,
+		},
+		{
+			name:        "unreachable registry",
+			roleARN:     ecrRoleARN,
+			unreachable: true,
+			expectedErr: ErrECRRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewTLSServer(http.HandlerFunc(fakeECR))
+			defer server.Close()
+
+			e := NewECR()
+			e.registry = strings.TrimPrefix(server.URL, "https://")
+			e.region = "us-east-1"
+			e.client = server.Client()
+			e.apiEndpoint = server.URL
+			e.accessKeyID = tc.accessKeyID
+			e.secretAccessKey = tc.secretAccessKey
+			e.roleARN = tc.roleARN
+			if tc.unreachable {
+				e.registry = "127.0.0.1:1"
+			}
+
+			data, err := e.Data(context.Background(), nil, nil)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected err %v, got %v", tc.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if e.Type() != tc.expectedType {
+				t.Errorf("expected type %q, got %q", tc.expectedType, e.Type())
+			}
+			if tc.expectedType == corev1.SecretTypeDockerConfigJson {
+				expected := `{"auths":{"` + e.registry +
+					`":{"auth":"` + ecrToken + `"}}}`
+				if got := string(data[".dockerconfigjson"]); got != expected {
+					t.Errorf("expected docker config %s, got %s", expected, got)
+				}
+			} else {
+				if _, ok := data[".dockerconfigjson"]; ok {
+					t.Errorf("expected no docker config, got %s",
+						data[".dockerconfigjson"])
+				}
+				if got := string(data["role-arn"]); got != tc.roleARN {
+					t.Errorf("expected role-arn %q, got %q", tc.roleARN, got)
+				}
+			}
+			expiry := e.ExpiresAt()
+			if tc.expectedExpiry {
+				if expiry == nil || expiry.Unix() != int64(1.7e9) {
+					t.Errorf("expected expiry at %d, got %v", int64(1.7e9), expiry)
+				}
+			} else if expiry != nil {
+				t.Errorf("expected no expiry, got %v", expiry)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Expirer is implemented by integrations able to discover when the informed
+// token expires, using the provider API. It's called after Data.
+type Expirer interface {
+	// ExpiresAt returns the token expiry, nil when it doesn't expire or it's
+	// unknown.
+	ExpiresAt() *time.Time
+}
+
+// ParseDuration parses a Go duration, additionally accepting days, "30d".
+func ParseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// ParseExpiry parses the expiry as a RFC 3339 timestamp, a date "2006-01-02",
+// or a duration relative to now, "90d".
+func ParseExpiry(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	if d, err := ParseDuration(s); err == nil && d > 0 {
+		return now.Add(d).Truncate(time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf(
+		"invalid expiry %q, expecting a RFC 3339 timestamp, a date (%s) or a duration (90d)",
+		s, time.DateOnly)
+}
+
+// SecretExpiry returns the expiry recorded on the integration secret, nil when
+// not recorded.
+func SecretExpiry(secret *corev1.Secret) (*time.Time, error) {
+	value, ok := secret.GetAnnotations()[annotations.ExpiresAt]
+	if !ok || value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("secret %s/%s: invalid %q annotation: %w",
+			secret.GetNamespace(), secret.GetName(), annotations.ExpiresAt, err)
+	}
+	return &t, nil
+}
+
+// expiry returns the token expiry to record on the secret, the informed
+// "--expires" takes precedence over the provider's.
+func (i *Integration) expiry() (*time.Time, error) {
+	if i.expires != "" {
+		t, err := ParseExpiry(i.expires, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		return &t, nil
+	}
+	if e, ok := i.data.(Expirer); ok {
+		return e.ExpiresAt(), nil
+	}
+	return nil, nil
+}
+
+// Expiry returns the token expiry recorded on the integration secret, nil when
+// the secret doesn't exist or the expiry is unknown.
+func (i *Integration) Expiry(
+	ctx context.Context,
+	cfg *config.Config,
+) (*time.Time, error) {
+	store, err := i.store(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := store.Get(ctx, i.secretName(cfg))
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return SecretExpiry(secret)
+}
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		expiry  string
+		want    time.Time
+		wantErr bool
+	}{{
+		name:   "timestamp",
+		expiry: "2026-03-01T08:00:00Z",
+		want:   time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+	}, {
+		name:   "date",
+		expiry: "2026-03-01",
+		want:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+	}, {
+		name:   "days",
+		expiry: "90d",
+		want:   now.Add(90 * 24 * time.Hour),
+	}, {
+		name:   "duration",
+		expiry: "36h",
+		want:   now.Add(36 * time.Hour),
+	}, {
+		name:    "negative duration",
+		expiry:  "-1h",
+		wantErr: true,
+	}, {
+		name:    "invalid",
+		expiry:  "next month",
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+			got, err := ParseExpiry(tt.expiry, now)
+			if tt.wantErr {
+				g.Expect(err).To(o.HaveOccurred())
+				return
+			}
+			g.Expect(err).To(o.Succeed())
+			g.Expect(got).To(o.BeTemporally("==", tt.want))
+		})
+	}
+}
+
+func TestIntegrationExpiry(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+	cfg, err := config.NewConfigFromFile(
+		chartfs.New(os.DirFS("../../test")),
+		"config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+
+	secret := func(name, expiresAt string) *corev1.Secret {
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace: cfg.Namespace(),
+			Name:      name,
+		}}
+		if expiresAt != "" {
+			s.SetAnnotations(map[string]string{
+				annotations.ExpiresAt: expiresAt,
+			})
+		}
+		return s
+	}
+	kube := k8s.NewFakeKube(
+		secret("expiring", "2026-03-01T08:00:00Z"),
+		secret("unknown", ""),
+		secret("invalid", "tomorrow"),
+	)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	expiry := func(name string) (*time.Time, error) {
+		return NewSecret(logger, kube, name, NewGitLab(logger)).Expiry(ctx, cfg)
+	}
+
+	expiresAt, err := expiry("expiring")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(*expiresAt).To(o.BeTemporally("==",
+		time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)))
+
+	expiresAt, err = expiry("unknown")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(expiresAt).To(o.BeNil())
+
+	expiresAt, err = expiry("missing")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(expiresAt).To(o.BeNil())
+
+	_, err = expiry("invalid")
+	g.Expect(err).To(o.HaveOccurred())
+
+	// The informed expiry takes precedence over the provider's.
+	i := NewSecret(logger, kube, "new", NewGitLab(logger))
+	i.expires = "2026-03-01"
+	expiresAt, err = i.expiry()
+	g.Expect(err).To(o.Succeed())
+	g.Expect(expiresAt.Format(time.DateOnly)).To(o.Equal("2026-03-01"))
+}
@@ -0,0 +1,90 @@
+package integration
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+)
+
+// ExternalURLsSetting the installer setting with the externally visible
+// addresses, handed to external services like GitHub, when the cluster is
+// reachable only through a reverse proxy or from a different domain.
+const ExternalURLsSetting = "externalURLs"
+
+// ExternalURLs the externally visible addresses, informed on the
+// ExternalURLsSetting setting.
+type ExternalURLs struct {
+	// Domain replaces the cluster ingress domain on the URLs derived from it,
+	// for instance "apps.proxy.example.com".
+	Domain string
+	// Webhook the webhook URL, for instance the Pipelines-as-Code controller
+	// behind the proxy.
+	Webhook string
+	// Homepage the homepage URL.
+	Homepage string
+	// Callback the authentication callback URL.
+	Callback string
+}
+
+// GetExternalURLs returns the external addresses informed on the configuration,
+// empty when the setting is absent. URLs must be absolute HTTP(S) URLs, and the
+// domain a host name.
+func GetExternalURLs(cfg *config.Config) (*ExternalURLs, error) {
+	e := &ExternalURLs{}
+	if cfg == nil {
+		return e, nil
+	}
+	setting, ok := cfg.Installer.Settings[ExternalURLsSetting]
+	if !ok || setting == nil {
+		return e, nil
+	}
+	invalid := func(format string, a ...any) error {
+		return fmt.Errorf("%w: setting %q: %s", config.ErrInvalidConfig,
+			ExternalURLsSetting, fmt.Sprintf(format, a...))
+	}
+	var attrs map[string]any
+	switch v := setting.(type) {
+	case config.Settings:
+		attrs = v
+	case map[string]any:
+		attrs = v
+	default:
+		return nil, invalid("expected an object, got %v", setting)
+	}
+	fields := map[string]*string{
+		"domain":   &e.Domain,
+		"webhook":  &e.Webhook,
+		"homepage": &e.Homepage,
+		"callback": &e.Callback,
+	}
+	for key, value := range attrs {
+		field, ok := fields[key]
+		if !ok {
+			return nil, invalid("unknown attribute %q", key)
+		}
+		s, ok := value.(string)
+		if !ok {
+			return nil, invalid("%q must be a string, got %v", key, value)
+		}
+		*field = s
+	}
+	if strings.ContainsAny(e.Domain, ":/") {
+		return nil, invalid("domain %q must be a host name, without scheme", e.Domain)
+	}
+	for key, value := range map[string]string{
+		"webhook":  e.Webhook,
+		"homepage": e.Homepage,
+		"callback": e.Callback,
+	} {
+		if value == "" {
+			continue
+		}
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, invalid("%s %q must be an absolute HTTP(S) URL", key, value)
+		}
+	}
+	return e, nil
+}
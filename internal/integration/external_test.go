@@ -0,0 +1,94 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+)
+
+func externalConfig(t *testing.T, settings string) *config.Config {
+	t.Helper()
+	cfg, err := config.NewConfigFromBytes([]byte(`
+helmet_ex:
+  settings:
+`+settings+`
+  products: []
+`), "installer-ns", "helmet_ex")
+	if err != nil {
+		t.Fatalf("build config: %v", err)
+	}
+	return cfg
+}
+
+func TestGetExternalURLs(t *testing.T) {
+	t.Parallel()
+
+	e, err := GetExternalURLs(nil)
+	if err != nil || *e != (ExternalURLs{}) {
+		t.Fatalf("GetExternalURLs(nil): got %v, %v", e, err)
+	}
+
+	cfg := externalConfig(t, `
+    externalURLs:
+      domain: apps.proxy.example.com
+      webhook: https://hooks.proxy.example.com/pac
+`)
+	e, err = GetExternalURLs(cfg)
+	if err != nil {
+		t.Fatalf("GetExternalURLs: %v", err)
+	}
+	if e.Domain != "apps.proxy.example.com" || e.Webhook != "https://hooks.proxy.example.com/pac" {
+		t.Errorf("GetExternalURLs: got %+v", e)
+	}
+
+	for name, settings := range map[string]string{
+		"not an object":  "    externalURLs: proxy.example.com",
+		"unknown":        "    externalURLs:\n      api: https://api.example.com",
+		"not a string":   "    externalURLs:\n      webhook: 8080",
+		"domain scheme":  "    externalURLs:\n      domain: https://proxy.example.com",
+		"relative URL":   "    externalURLs:\n      homepage: /console",
+		"unknown scheme": "    externalURLs:\n      callback: ftp://proxy.example.com",
+	} {
+		_, err := GetExternalURLs(externalConfig(t, settings))
+		if !errors.Is(err, config.ErrInvalidConfig) {
+			t.Errorf("%s: got err %v, want %v", name, err, config.ErrInvalidConfig)
+		}
+	}
+}
+
+func TestGitHub_SetClusterURLs_ExternalURLs(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+	gh := NewGitHub(logger)
+	gh.name = "test-app"
+	gh.homepageURL = "https://flag-homepage.example.com"
+	// The provider only fills what flags and settings leave empty.
+	gh.SetURLProvider(&mockURLProvider{
+		callbackURL: "https://provider-callback.example.com",
+		webhookURL:  "https://provider-webhook.example.com",
+	})
+
+	cfg := externalConfig(t, `
+    externalURLs:
+      webhook: https://hooks.proxy.example.com/pac
+      homepage: https://console.proxy.example.com
+`)
+	if err := gh.setClusterURLs(ctx, nil, cfg); err != nil {
+		t.Fatalf("setClusterURLs: %v", err)
+	}
+	if gh.webhookURL != "https://hooks.proxy.example.com/pac" {
+		t.Errorf("webhook: got %q, want the setting", gh.webhookURL)
+	}
+	if gh.homepageURL != "https://flag-homepage.example.com" {
+		t.Errorf("homepage: got %q, want the flag", gh.homepageURL)
+	}
+	if gh.callbackURL != "https://provider-callback.example.com" {
+		t.Errorf("callback: got %q, want the provider", gh.callbackURL)
+	}
+}
@@ -0,0 +1,297 @@
+package integration
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ErrGARRequest the registry refused or failed the request.
+var ErrGARRequest = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"artifact registry request failed")
+
+// garUsername the registry username authenticating with a service account key.
+const garUsername = "_json_key"
+
+var (
+	// garRegistryRe matches the Artifact Registry Docker hostname,
+	// "<location>-docker.pkg.dev".
+	garRegistryRe = regexp.MustCompile(`^[a-z0-9-]+-docker\.pkg\.dev$`)
+	// garServiceAccountRe matches the Google service account email, capturing
+	// the project, "<name>@<project>.iam.gserviceaccount.com".
+	garServiceAccountRe = regexp.MustCompile(
+		`^[a-z0-9-]+@([a-z0-9-]+)\.iam\.gserviceaccount\.com$`)
+)
+
+// GAR represents the Google Artifact Registry integration coordinates,
+// authenticated with a service account JSON key, or a Google service account
+// impersonated by the workloads through Workload Identity.
+type GAR struct {
+	registry          string // registry hostname
+	project           string // Google Cloud project
+	serviceAccountKey string // service account JSON key
+	serviceAccount    string // service account impersonated with Workload Identity
+
+	client *http.Client // registry http client
+}
+
+var _ Interface = &GAR{}
+var _ Credential = &GAR{}
+var _ Capable = &GAR{}
+var _ Verifier = &GAR{}
+
+// Capabilities the service account key is verified against the registry.
+func (g *GAR) Capabilities() []Capability {
+	return []Capability{CapabilityVerification}
+}
+
+// CredentialFlag the service account key can be informed via STDIN or the
+// keychain.
+func (g *GAR) CredentialFlag() string {
+	return "service-account-key"
+}
+
+// PersistentFlags adds the persistent flags to the informed Cobra command.
+func (g *GAR) PersistentFlags(c *cobra.Command) {
+	p := c.PersistentFlags()
+
+	p.StringVar(&g.registry, "registry", g.registry,
+		"Artifact Registry hostname, e.g. us-central1-docker.pkg.dev")
+	p.StringVar(&g.project, "project", g.project,
+		"Google Cloud project, by default the service account's")
+	p.StringVar(&g.serviceAccountKey, "service-account-key", g.serviceAccountKey,
+		"Service account JSON key, with the Artifact Registry Reader role")
+	p.StringVar(&g.serviceAccount, "service-account", g.serviceAccount,
+		"Service account impersonated by the workloads with Workload Identity, instead of a key")
+
+	for _, f := range []string{"registry"} {
+		if err := c.MarkPersistentFlagRequired(f); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// SetArgument sets additional arguments to the integration.
+func (g *GAR) SetArgument(string, string) error {
+	return nil
+}
+
+// LoggerWith decorates the logger with the integration flags.
+func (g *GAR) LoggerWith(logger *slog.Logger) *slog.Logger {
+	return logger.With(
+		"registry", g.registry,
+		"project", g.project,
+		"service-account-key-len", len(g.serviceAccountKey),
+		"service-account", g.serviceAccount,
+	)
+}
+
+// key returns whether the integration authenticates with a service account key,
+// otherwise with Workload Identity.
+func (g *GAR) key() bool {
+	return g.serviceAccountKey != ""
+}
+
+// Type returns the type of the integration, the service account key generates
+// registry credentials.
+func (g *GAR) Type() corev1.SecretType {
+	if g.key() {
+		return corev1.SecretTypeDockerConfigJson
+	}
+	return corev1.SecretTypeOpaque
+}
+
+// validateKey asserts the service account key is a JSON key, returning its
+// service account email and project.
+func (g *GAR) validateKey() (string, string, error) {
+	var key struct {
+		Type        string `json:"type"`
+		ProjectID   string `json:"project_id"`
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+	}
+	if err := json.Unmarshal([]byte(g.serviceAccountKey), &key); err != nil {
+		return "", "", fmt.Errorf("invalid service-account-key: %w", err)
+	}
+	if key.Type != "service_account" || key.ClientEmail == "" ||
+		key.PrivateKey == "" {
+		return "", "", fmt.Errorf("invalid service-account-key: not a " +
+			"service account JSON key")
+	}
+	return key.ClientEmail, key.ProjectID, nil
+}
+
+// Validate validates the integration configuration, either the service account
+// key or the Workload Identity service account must be informed.
+func (g *GAR) Validate() error {
+	if !garRegistryRe.MatchString(g.registry) {
+		return fmt.Errorf("invalid registry %q, expected the Artifact Registry "+
+			"hostname, \"<location>-docker.pkg.dev\"", g.registry)
+	}
+	switch {
+	case g.key() && g.serviceAccount != "":
+		return fmt.Errorf("either service-account-key or service-account " +
+			"must be specified, not both")
+	case g.key():
+		_, project, err := g.validateKey()
+		if err != nil {
+			return err
+		}
+		if g.project == "" {
+			g.project = project
+		}
+	case g.serviceAccount != "":
+		match := garServiceAccountRe.FindStringSubmatch(g.serviceAccount)
+		if match == nil {
+			return fmt.Errorf("invalid service-account %q, expected "+
+				"\"<name>@<project>.iam.gserviceaccount.com\"", g.serviceAccount)
+		}
+		if g.project == "" {
+			g.project = match[1]
+		}
+	default:
+		return fmt.Errorf("either service-account-key or service-account " +
+			"is required")
+	}
+	return nil
+}
+
+// auth returns the registry basic authentication, base64 encoded.
+func (g *GAR) auth() string {
+	return base64.StdEncoding.EncodeToString(
+		[]byte(garUsername + ":" + g.serviceAccountKey))
+}
+
+// ping asserts the registry is reachable. With the service account key, the
+// registry token service must grant a token for the key, following the
+// registry authentication challenge.
+func (g *GAR) ping(ctx context.Context) error {
+	endpoint := fmt.Sprintf("https://%s/v2/", g.registry)
+	res, err := g.get(ctx, endpoint, "")
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("%w: GET %s: %s", ErrGARRequest, endpoint, res.Status)
+	}
+	if !g.key() {
+		return nil
+	}
+
+	// Following the challenge, "Bearer realm=<url>,service=<service>".
+	challenge := res.Header.Get("WWW-Authenticate")
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return fmt.Errorf("%w: unexpected authentication challenge %q",
+			ErrGARRequest, challenge)
+	}
+	attrs := map[string]string{}
+	for _, m := range registryChallengeRe.FindAllStringSubmatch(challenge, -1) {
+		attrs[m[1]] = m[2]
+	}
+	realm, err := url.Parse(attrs["realm"])
+	if err != nil || attrs["realm"] == "" {
+		return fmt.Errorf("%w: invalid authentication realm %q",
+			ErrGARRequest, attrs["realm"])
+	}
+	query := realm.Query()
+	if service := attrs["service"]; service != "" {
+		query.Set("service", service)
+	}
+	realm.RawQuery = query.Encode()
+	tokenRes, err := g.get(ctx, realm.String(), g.auth())
+	if err != nil {
+		return err
+	}
+	defer tokenRes.Body.Close()
+	if tokenRes.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: service account key refused: %s",
+			ErrGARRequest, tokenRes.Status)
+	}
+	return nil
+}
+
+// get issues the GET request, with the basic authentication when informed.
+func (g *GAR) get(
+	ctx context.Context,
+	endpoint, auth string,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", "Basic "+auth)
+	}
+	res, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: registry unreachable: %w", ErrGARRequest, err)
+	}
+	return res, nil
+}
+
+// Data returns the GAR integration data. With the service account key, the
+// registry credentials are stored as the ".dockerconfigjson", after asserting
+// the registry accepts the key; with Workload Identity, the registry must be
+// reachable.
+func (g *GAR) Data(
+	ctx context.Context,
+	_ *runcontext.RunContext,
+	_ *config.Config,
+) (map[string][]byte, error) {
+	if err := g.ping(ctx); err != nil {
+		return nil, err
+	}
+	data := map[string][]byte{
+		"registry":            []byte(g.registry),
+		"project":             []byte(g.project),
+		"service-account-key": []byte(g.serviceAccountKey),
+		"service-account":     []byte(g.serviceAccount),
+	}
+	if !g.key() {
+		return data, nil
+	}
+	dockerConfig, err := json.Marshal(map[string]any{
+		"auths": map[string]any{
+			g.registry: map[string]string{
+				"username": garUsername,
+				"password": g.serviceAccountKey,
+				"auth":     g.auth(),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	data[".dockerconfigjson"] = dockerConfig
+	return data, nil
+}
+
+// Verify asserts the registry accepts the stored service account key; with
+// Workload Identity, the registry must be reachable.
+func (g *GAR) Verify(ctx context.Context, data map[string][]byte) error {
+	g.registry = string(data["registry"])
+	g.project = string(data["project"])
+	g.serviceAccountKey = string(data["service-account-key"])
+	g.serviceAccount = string(data["service-account"])
+	return g.ping(ctx)
+}
+
+// NewGAR instantiates a new GAR integration.
+func NewGAR() *GAR {
+	return &GAR{client: &http.Client{Timeout: 30 * time.Second}}
+}
@@ -46,6 +46,19 @@ type GitHub struct {
 }
 
 var _ Interface = &GitHub{}
+var _ Credential = &GitHub{}
+var _ Capable = &GitHub{}
+var _ Verifier = &GitHub{}
+
+// CredentialFlag the GitHub personal access token can be informed via STDIN or the keychain.
+func (g *GitHub) CredentialFlag() string {
+	return "token"
+}
+
+// Capabilities the GitHub App is created using the GitHub API.
+func (g *GitHub) Capabilities() []Capability {
+	return []Capability{CapabilityProvisioning}
+}
 
 // GitHubAppName key to identify the GitHubApp name.
 const GitHubAppName = "name"
@@ -113,12 +126,27 @@ func (g *GitHub) Type() corev1.SecretType {
 }
 
 // setClusterURLs resolves GitHub App URLs from flags first, then from the
-// optional URL provider (via adapter). It validates that required URLs (webhook, homepage) are set.
+// external URLs setting, and finally from the optional URL provider (via
+// adapter). It validates that required URLs (webhook, homepage) are set.
 func (g *GitHub) setClusterURLs(
 	ctx context.Context,
 	runCtx *runcontext.RunContext,
 	cfg *config.Config,
 ) error {
+	external, err := GetExternalURLs(cfg)
+	if err != nil {
+		return err
+	}
+	if g.callbackURL == "" {
+		g.callbackURL = external.Callback
+	}
+	if g.webhookURL == "" {
+		g.webhookURL = external.Webhook
+	}
+	if g.homepageURL == "" {
+		g.homepageURL = external.Homepage
+	}
+
 	if g.urlProvider != nil {
 		provider := newURLProviderAdapter(g.urlProvider, runCtx, cfg)
 		if g.callbackURL == "" {
@@ -145,7 +173,8 @@ func (g *GitHub) setClusterURLs(
 	}
 
 	if g.webhookURL == "" || g.homepageURL == "" {
-		return fmt.Errorf("GitHub App webhook and homepage URLs must be provided via flags or URLProvider")
+		return fmt.Errorf("GitHub App webhook and homepage URLs must be provided via flags or URLProvider, or the %q setting",
+			ExternalURLsSetting)
 	}
 
 	return nil
@@ -201,6 +230,15 @@ func (g *GitHub) Data(
 		return nil, err
 	}
 
+	if g.token != "" {
+		g.log().Info("Verifying the GitHub personal access token")
+		login, err := g.client.VerifyToken(ctx, g.token)
+		if err != nil {
+			return nil, err
+		}
+		g.log().Debug("GitHub personal access token verified", "login", login)
+	}
+
 	g.log().Info("Generating the GitHub application manifest")
 	manifest := g.generateAppManifest()
 
@@ -237,6 +275,25 @@ func (g *GitHub) Data(
 	}, nil
 }
 
+// Verify asserts the stored private key authenticates as the GitHub App, and
+// the personal access token, when stored, authenticates too.
+func (g *GitHub) Verify(ctx context.Context, data map[string][]byte) error {
+	app := g.client.ForHost(string(data["host"]))
+	slug, err := app.VerifyApp(ctx, string(data["id"]), data["pem"])
+	if err != nil {
+		return err
+	}
+	g.log().Debug("GitHub App verified", "slug", slug)
+	if token := string(data["token"]); token != "" {
+		login, err := app.VerifyToken(ctx, token)
+		if err != nil {
+			return err
+		}
+		g.log().Debug("GitHub personal access token verified", "login", login)
+	}
+	return nil
+}
+
 // NewGitHub instances a new GitHub App integration.
 func NewGitHub(logger *slog.Logger) *GitHub {
 	return &GitHub{
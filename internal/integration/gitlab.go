@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
@@ -30,9 +31,25 @@ type GitLab struct {
 	appSecret     string // gitlab application client secret
 	token         string // api token credentials
 	webhookSecret string // Optional: Webhook secret
+
+	expiresAt *time.Time // api token expiry, when known
 }
 
 var _ Interface = &GitLab{}
+var _ Credential = &GitLab{}
+var _ Expirer = &GitLab{}
+var _ Capable = &GitLab{}
+var _ Verifier = &GitLab{}
+
+// Capabilities the informed token is verified obtaining the current user.
+func (g *GitLab) Capabilities() []Capability {
+	return []Capability{CapabilityVerification}
+}
+
+// CredentialFlag the GitLab API token can be informed via STDIN or the keychain.
+func (g *GitLab) CredentialFlag() string {
+	return "token"
+}
 
 // PersistentFlags adds the persistent flags to the informed Cobra command.
 func (g *GitLab) PersistentFlags(c *cobra.Command) {
@@ -102,9 +119,8 @@ func (g *GitLab) Validate() error {
 	return nil
 }
 
-// getCurrentGitLabUser returns the current username authenticated, using the
-// informed access token.
-func (g *GitLab) getCurrentGitLabUser() (string, error) {
+// client returns the GitLab API client, using the informed access token.
+func (g *GitLab) client() (*gitlab.Client, error) {
 	gitLabURL := fmt.Sprintf("https://%s", g.host)
 	if g.port != 443 {
 		gitLabURL += fmt.Sprintf(":%d", g.port)
@@ -124,9 +140,14 @@ func (g *GitLab) getCurrentGitLabUser() (string, error) {
 	)
 	if err != nil {
 		g.log().Error("Error building gitlab client")
-		return "", err
+		return nil, err
 	}
+	return client, nil
+}
 
+// getCurrentGitLabUser returns the current username authenticated, using the
+// informed access token.
+func (g *GitLab) getCurrentGitLabUser(client *gitlab.Client) (string, error) {
 	user, _, err := client.Users.CurrentUser()
 	if err != nil {
 		g.log().Error("Error getting user")
@@ -135,6 +156,26 @@ func (g *GitLab) getCurrentGitLabUser() (string, error) {
 	return user.Username, nil
 }
 
+// getTokenExpiry returns the access token expiry, nil when it doesn't expire or
+// the token isn't a personal access token.
+func (g *GitLab) getTokenExpiry(client *gitlab.Client) *time.Time {
+	token, _, err := client.PersonalAccessTokens.GetSinglePersonalAccessToken()
+	if err != nil {
+		g.log().Debug("Unable to inspect the access token expiry", "err", err)
+		return nil
+	}
+	if token.ExpiresAt == nil {
+		return nil
+	}
+	expiresAt := time.Time(*token.ExpiresAt)
+	return &expiresAt
+}
+
+// ExpiresAt returns the access token expiry, obtained on Data.
+func (g *GitLab) ExpiresAt() *time.Time {
+	return g.expiresAt
+}
+
 func (g *GitLab) generateWebhookSecret() (string, error) {
 	b := make([]byte, 32) // 32 bytes = 64 hex characters
 	if _, err := rand.Read(b); err != nil {
@@ -150,10 +191,15 @@ func (g *GitLab) Data(
 	_ *runcontext.RunContext,
 	_ *config.Config,
 ) (map[string][]byte, error) {
-	username, err := g.getCurrentGitLabUser()
+	client, err := g.client()
 	if err != nil {
 		return nil, err
 	}
+	username, err := g.getCurrentGitLabUser(client)
+	if err != nil {
+		return nil, err
+	}
+	g.expiresAt = g.getTokenExpiry(client)
 
 	if g.webhookSecret == "" {
 		secret, err := g.generateWebhookSecret()
@@ -177,6 +223,21 @@ func (g *GitLab) Data(
 	}, nil
 }
 
+// Verify asserts the stored token authenticates, obtaining the current user.
+func (g *GitLab) Verify(_ context.Context, data map[string][]byte) error {
+	g.host = string(data["host"])
+	if port, err := strconv.Atoi(string(data["port"])); err == nil {
+		g.port = port
+	}
+	g.token = string(data["token"])
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	_, err = g.getCurrentGitLabUser(client)
+	return err
+}
+
 // NewGitLab instantiate a new GitLab integration. By default it uses the public
 // GitLab host.
 func NewGitLab(logger *slog.Logger) *GitLab {
@@ -2,20 +2,24 @@ package integration
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/url"
 	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 )
 
 // ErrInvalidURL is an error returned when a URL is invalid, malformed.
-var ErrInvalidURL = errors.New("invalid URL")
+var ErrInvalidURL = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"invalid URL")
 
 // ErrInvalidJSON is an error returned when a string is not a valid JSON.
-var ErrInvalidJSON = errors.New("invalid JSON")
+var ErrInvalidJSON = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"invalid JSON")
 
 // ErrJSONContainsSpaces is an error returned when a JSON key or value contains spaces.
-var ErrJSONContainsSpaces = errors.New("contains unexpected spaces")
+var ErrJSONContainsSpaces = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"contains unexpected spaces")
 
 // ValidateURL check if the informed URL is valid.
 func ValidateURL(location string) error {
@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
@@ -20,9 +24,18 @@ type ImageRegistry struct {
 	url            string // API endpoint
 	token          string // API token
 	organization   string // optional: Quay organization name for additional token secret
+
+	client *http.Client // registry http client
 }
 
 var _ Interface = &ImageRegistry{}
+var _ Credential = &ImageRegistry{}
+var _ Verifier = &ImageRegistry{}
+
+// CredentialFlag the container registry API token can be informed via STDIN or the keychain.
+func (i *ImageRegistry) CredentialFlag() string {
+	return "token"
+}
 
 const (
 	// QuayURL is the default URL for public Quay.
@@ -109,7 +122,55 @@ func (i *ImageRegistry) Data(
 	}, nil
 }
 
+// verifyOrganization asserts the API token reads the Quay organization.
+func (i *ImageRegistry) verifyOrganization(ctx context.Context) error {
+	endpoint := fmt.Sprintf("%s/api/v1/organization/%s",
+		strings.TrimSuffix(i.url, "/"), url.PathEscape(i.organization))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+i.token)
+	res, err := i.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: registry API unreachable: %w",
+			ErrRegistryRequest, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: organization %q: %s %s: %s", ErrRegistryRequest,
+			i.organization, req.Method, req.URL.Redacted(), res.Status)
+	}
+	return nil
+}
+
+// Verify asserts the registries accept the stored credentials, read-write and
+// read-only, and the API token reads the Quay organization, when informed.
+func (i *ImageRegistry) Verify(
+	ctx context.Context,
+	data map[string][]byte,
+) error {
+	i.url = string(data["url"])
+	i.token = string(data["token"])
+	i.organization = string(data["organization"])
+	for _, k := range []string{".dockerconfigjson", ".dockerconfigjsonreadonly"} {
+		if len(data[k]) == 0 {
+			continue
+		}
+		if err := verifyDockerConfig(ctx, i.client, data[k]); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+	}
+	if i.token == "" || i.organization == "" {
+		return nil
+	}
+	return i.verifyOrganization(ctx)
+}
+
 // NewContainerRegistry creates a new instance with the default URL.
 func NewContainerRegistry(defaultURL string) *ImageRegistry {
-	return &ImageRegistry{url: defaultURL}
+	return &ImageRegistry{
+		url:    defaultURL,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
 }
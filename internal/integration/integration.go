@@ -4,13 +4,19 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
 
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/keychain"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -23,20 +29,38 @@ type Integration struct {
 	name   string        // kubernetes secret name
 	data   Interface     // provides secret data
 
-	force bool // overwrite the existing secret
+	force       bool     // overwrite the existing secret
+	replicateTo []string // namespaces to keep a copy of the secret in
+	expires     string   // token expiry, recorded on the secret
+	vaultSecret string   // "vault" integration secret name, see store
+
+	cmd                *cobra.Command     // command decorated with flags
+	keychain           keychain.Interface // stores reusable credentials
+	credentialFlag     string             // flag holding the credential
+	credentialRequired bool               // credential must be informed
+	credentialStdin    bool               // read credential from stdin
+	credentialStore    bool               // store credential in keychain
 }
 
 // ErrSecretAlreadyExists integration secret already exists.
-var ErrSecretAlreadyExists = fmt.Errorf("secret already exists")
+var ErrSecretAlreadyExists = helmeterrors.New(helmeterrors.ErrConflict,
+	"secret already exists")
 
 // PersistentFlags decorates the cobra instance with persistent flags.
 func (i *Integration) PersistentFlags(cmd *cobra.Command) {
 	p := cmd.PersistentFlags()
 
 	p.BoolVar(&i.force, "force", i.force, "Overwrite the existing secret")
+	p.StringSliceVar(&i.replicateTo, "replicate-to", i.replicateTo,
+		"Namespaces to keep a synchronized copy of the secret in")
+	p.StringVar(&i.expires, "expires", i.expires,
+		"Token expiry, a RFC 3339 timestamp, a date (2006-01-02) or a duration (90d)")
 
 	// Decorating the command with integration data flags.
 	i.data.PersistentFlags(cmd)
+
+	i.cmd = cmd
+	i.credentialFlags(cmd)
 }
 
 // SetArgument exposes the data provider method.
@@ -46,6 +70,11 @@ func (i *Integration) SetArgument(k, v string) error {
 
 // Validate validates the secret payload, using the data interface.
 func (i *Integration) Validate() error {
+	if i.expires != "" {
+		if _, err := ParseExpiry(i.expires, time.Now()); err != nil {
+			return err
+		}
+	}
 	return i.data.Validate()
 }
 
@@ -65,17 +94,48 @@ func (i *Integration) secretName(cfg *config.Config) types.NamespacedName {
 	}
 }
 
-// Exists checks whether the integration secret exists in the cluster.
+// Exists checks whether the integration secret exists on the secret backend,
+// the cluster by default.
 func (i *Integration) Exists(
 	ctx context.Context,
 	cfg *config.Config,
 ) (bool, error) {
-	return k8s.SecretExists(ctx, i.kube, i.secretName(cfg))
+	store, err := i.store(ctx, cfg)
+	if err != nil {
+		return false, err
+	}
+	_, err = store.Get(ctx, i.secretName(cfg))
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Secret reads the integration secret from the secret backend, nil when the
+// integration isn't configured.
+func (i *Integration) Secret(
+	ctx context.Context,
+	cfg *config.Config,
+) (*corev1.Secret, error) {
+	store, err := i.store(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := store.Get(ctx, i.secretName(cfg))
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return secret, err
 }
 
-// prepare prepares the cluster to receive the integration secret, when the force
-// flag is enabled an existing secret is deleted.
-func (i *Integration) prepare(ctx context.Context, cfg *config.Config) error {
+// prepare prepares the backend to receive the integration secret, when force is
+// enabled an existing secret is deleted.
+func (i *Integration) prepare(
+	ctx context.Context,
+	cfg *config.Config,
+	store SecretStore,
+	force bool,
+) error {
 	i.log().Debug("Checking whether the integration secret exists")
 	exists, err := i.Exists(ctx, cfg)
 	if err != nil {
@@ -85,22 +145,38 @@ func (i *Integration) prepare(ctx context.Context, cfg *config.Config) error {
 		i.log().Debug("Integration secret does not exist")
 		return nil
 	}
-	if !i.force {
+	if !force {
 		i.log().Debug("Integration secret already exists")
 		return fmt.Errorf("%w: %s",
 			ErrSecretAlreadyExists, i.secretName(cfg).String())
 	}
 	i.log().Debug("Integration secret already exists, recreating it")
-	return i.Delete(ctx, cfg)
+	// Keeping the replication targets of the existing secret, unless informed.
+	if store.Replicated() &&
+		(i.cmd == nil || !i.cmd.Flags().Changed("replicate-to")) {
+		secret, err := store.Get(ctx, i.secretName(cfg))
+		if err != nil {
+			return err
+		}
+		i.replicateTo = ReplicaTargets(secret)
+	}
+	return store.Delete(ctx, i.secretName(cfg))
 }
 
-// Create creates the integration secret in the cluster. It uses the integration
-// data provider to obtain the secret payload.
+// Create creates the integration secret on the secret backend, the cluster by
+// default. It uses the integration data provider to obtain the secret payload.
 func (i *Integration) Create(ctx context.Context, runCtx *runcontext.RunContext, cfg *config.Config) error {
-	err := i.prepare(ctx, cfg)
+	store, err := i.store(ctx, cfg)
 	if err != nil {
 		return err
 	}
+	if len(i.replicateTo) > 0 && !store.Replicated() {
+		return fmt.Errorf("%w: --replicate-to: secrets stored on %q are not "+
+			"replicated", helmeterrors.ErrInvalidUsage, SecretBackendVault)
+	}
+	if err = i.prepare(ctx, cfg, store, i.force); err != nil {
+		return err
+	}
 
 	// The integration provider prepares and returns the payload to create the
 	// Kubernetes secret.
@@ -109,6 +185,10 @@ func (i *Integration) Create(ctx context.Context, runCtx *runcontext.RunContext,
 	if err != nil {
 		return err
 	}
+	expiry, err := i.expiry()
+	if err != nil {
+		return err
+	}
 	namespace := i.secretName(cfg).Namespace
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -118,23 +198,78 @@ func (i *Integration) Create(ctx context.Context, runCtx *runcontext.RunContext,
 		Type: i.data.Type(),
 		Data: payload,
 	}
+	secretAnnotations := map[string]string{}
+	if len(i.replicateTo) > 0 {
+		secretAnnotations[annotations.ReplicateTo] = strings.Join(i.replicateTo, ",")
+	}
+	if expiry != nil {
+		secretAnnotations[annotations.ExpiresAt] = expiry.UTC().Format(time.RFC3339)
+	}
+	if len(secretAnnotations) > 0 {
+		secret.SetAnnotations(secretAnnotations)
+	}
 
 	i.log().Debug("Creating the integration secret")
-	coreClient, err := i.kube.CoreV1ClientSet(namespace)
+	if err = store.Create(ctx, secret); err != nil {
+		return err
+	}
+	i.log().Info("Integration secret is created successfully!")
+	if !store.Replicated() {
+		return nil
+	}
+
+	// Propagating the new payload to the replicas right away, instead of
+	// waiting for the next synchronization. Only this secret's replicas, a
+	// conflict on other integrations doesn't fail the creation.
+	i.log().Debug("Synchronizing the integration secret replicas")
+	_, err = NewReplicator(i.logger, i.kube, namespace).SyncSecret(ctx, i.name)
+	return err
+}
+
+// Restore stores the informed secret, exported from another installation, as the
+// integration secret on the secret backend. Only the type, annotations and data
+// are kept, an existing secret is only replaced with force.
+func (i *Integration) Restore(
+	ctx context.Context,
+	cfg *config.Config,
+	secret *corev1.Secret,
+	force bool,
+) error {
+	store, err := i.store(ctx, cfg)
 	if err != nil {
 		return err
 	}
-	_, err = coreClient.Secrets(namespace).
-		Create(ctx, secret, metav1.CreateOptions{})
-	if err == nil {
-		i.log().Info("Integration secret is created successfully!")
+	if err = i.prepare(ctx, cfg, store, force); err != nil {
+		return err
+	}
+	namespace := i.secretName(cfg).Namespace
+	restored := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   namespace,
+			Name:        i.name,
+			Annotations: secret.GetAnnotations(),
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+	i.log().Debug("Restoring the integration secret")
+	if err = store.Create(ctx, restored); err != nil {
+		return err
+	}
+	if !store.Replicated() {
+		return nil
 	}
+	_, err = NewReplicator(i.logger, i.kube, namespace).Sync(ctx)
 	return err
 }
 
-// Delete deletes the Kubernetes secret.
+// Delete deletes the integration secret from the secret backend.
 func (i *Integration) Delete(ctx context.Context, cfg *config.Config) error {
-	return k8s.DeleteSecret(ctx, i.kube, i.secretName(cfg))
+	store, err := i.store(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return store.Delete(ctx, i.secretName(cfg))
 }
 
 // NewSecret instantiates a new secret manager, it uses the integration data
@@ -145,5 +280,11 @@ func NewSecret(
 	name string,
 	data Interface,
 ) *Integration {
-	return &Integration{logger: logger, kube: kube, name: name, data: data}
+	return &Integration{
+		logger:   logger,
+		kube:     kube,
+		name:     name,
+		data:     data,
+		keychain: keychain.NewKeychain(),
+	}
 }
@@ -19,6 +19,12 @@ type Jenkins struct {
 }
 
 var _ Interface = &Jenkins{}
+var _ Credential = &Jenkins{}
+
+// CredentialFlag the Jenkins API token can be informed via STDIN or the keychain.
+func (j *Jenkins) CredentialFlag() string {
+	return "token"
+}
 
 // PersistentFlags adds the persistent flags to the informed Cobra command.
 func (j *Jenkins) PersistentFlags(c *cobra.Command) {
@@ -0,0 +1,413 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ErrKeycloakRequest the Keycloak server refused or failed the request.
+var ErrKeycloakRequest = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"keycloak request failed")
+
+const (
+	// keycloakAdminRealm the realm authenticating the administrator.
+	keycloakAdminRealm = "master"
+	// keycloakAdminClient the client issuing the administrator token.
+	keycloakAdminClient = "admin-cli"
+)
+
+// Keycloak represents the Keycloak, or Red Hat Single Sign-On, integration
+// coordinates, the OIDC client of a realm. The client is either informed, or
+// created on the realm with the administrator credentials.
+type Keycloak struct {
+	url           string   // keycloak server URL
+	realm         string   // realm name
+	clientID      string   // OIDC client ID
+	clientSecret  string   // OIDC client secret
+	adminUsername string   // administrator username, "master" realm
+	adminPassword string   // administrator password
+	redirectURIs  []string // client redirect URIs, when created
+	create        bool     // create the client on the realm
+
+	issuer string       // realm issuer, discovered
+	client *http.Client // keycloak http client
+}
+
+var _ Interface = &Keycloak{}
+var _ Credential = &Keycloak{}
+var _ Capable = &Keycloak{}
+var _ Verifier = &Keycloak{}
+
+// CredentialFlag the client secret can be informed via STDIN or the keychain.
+func (k *Keycloak) CredentialFlag() string {
+	return "client-secret"
+}
+
+// Capabilities the client credentials are verified against the realm, and the
+// client is created on the realm with "--create".
+func (k *Keycloak) Capabilities() []Capability {
+	return []Capability{CapabilityVerification, CapabilityProvisioning}
+}
+
+// PersistentFlags adds the persistent flags to the informed Cobra command.
+func (k *Keycloak) PersistentFlags(c *cobra.Command) {
+	p := c.PersistentFlags()
+
+	p.StringVar(&k.url, "url", k.url,
+		"Keycloak server URL, e.g. https://sso.example.com")
+	p.StringVar(&k.realm, "realm", k.realm,
+		"Realm of the OIDC client")
+	p.StringVar(&k.clientID, "client-id", k.clientID,
+		"OIDC client ID")
+	p.StringVar(&k.clientSecret, "client-secret", k.clientSecret,
+		"OIDC client secret, generated by Keycloak when omitted with --create")
+	p.StringVar(&k.adminUsername, "admin-username", k.adminUsername,
+		"Administrator username, of the \"master\" realm, to create the client")
+	p.StringVar(&k.adminPassword, "admin-password", k.adminPassword,
+		"Administrator password")
+	p.StringSliceVar(&k.redirectURIs, "redirect-uri", k.redirectURIs,
+		"Redirect URIs of the created client, by default the external callback URL")
+	p.BoolVar(&k.create, "create", k.create,
+		"Create the client on the realm, with the administrator credentials")
+
+	for _, f := range []string{"url", "realm", "client-id"} {
+		if err := c.MarkPersistentFlagRequired(f); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// SetArgument sets additional arguments to the integration.
+func (k *Keycloak) SetArgument(string, string) error {
+	return nil
+}
+
+// LoggerWith decorates the logger with the integration flags.
+func (k *Keycloak) LoggerWith(logger *slog.Logger) *slog.Logger {
+	return logger.With(
+		"url", k.url,
+		"realm", k.realm,
+		"client-id", k.clientID,
+		"client-secret-len", len(k.clientSecret),
+		"admin-username", k.adminUsername,
+		"admin-password-len", len(k.adminPassword),
+		"redirect-uris", k.redirectURIs,
+		"create", k.create,
+	)
+}
+
+// Validate validates the integration configuration, the administrator
+// credentials are required to create the client, and the client secret
+// otherwise.
+func (k *Keycloak) Validate() error {
+	if err := ValidateURL(k.url); err != nil {
+		return err
+	}
+	k.url = strings.TrimSuffix(k.url, "/")
+	if k.realm == "" {
+		return fmt.Errorf("realm is required")
+	}
+	if k.clientID == "" {
+		return fmt.Errorf("client-id is required")
+	}
+	admin := k.adminUsername != "" || k.adminPassword != ""
+	switch {
+	case k.create && (k.adminUsername == "" || k.adminPassword == ""):
+		return fmt.Errorf("admin-username and admin-password are required " +
+			"to create the client")
+	case !k.create && admin:
+		return fmt.Errorf("the administrator credentials are only used " +
+			"to create the client, with --create")
+	case !k.create && len(k.redirectURIs) > 0:
+		return fmt.Errorf("redirect-uri is only used to create the client, " +
+			"with --create")
+	case !k.create && k.clientSecret == "":
+		return fmt.Errorf("client-secret is required, unless the client " +
+			"is created with --create")
+	}
+	for _, uri := range k.redirectURIs {
+		if err := ValidateURL(uri); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Type returns the type of the integration.
+func (k *Keycloak) Type() corev1.SecretType {
+	return corev1.SecretTypeOpaque
+}
+
+// realmURL returns the URL of the realm endpoint, prefixed by the elements
+// informed, "admin" for the admin REST API.
+func (k *Keycloak) realmURL(realm string, elem ...string) string {
+	u, _ := url.Parse(k.url)
+	elem = append(elem, "realms", realm)
+	u.Path = path.Join(append([]string{u.Path}, elem...)...)
+	return u.String()
+}
+
+// do issues the request, decoding the JSON response body on the informed
+// value, when informed. Responses other than the expected status are errors.
+func (k *Keycloak) do(
+	req *http.Request,
+	expected int,
+	v any,
+) (*http.Response, error) {
+	res, err := k.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: keycloak unreachable: %w",
+			ErrKeycloakRequest, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != expected {
+		return res, fmt.Errorf("%w: %s %s: %s", ErrKeycloakRequest,
+			req.Method, req.URL.Redacted(), res.Status)
+	}
+	if v == nil {
+		return res, nil
+	}
+	if err = json.NewDecoder(res.Body).Decode(v); err != nil {
+		return res, fmt.Errorf("%w: %s %s: invalid response: %w",
+			ErrKeycloakRequest, req.Method, req.URL.Redacted(), err)
+	}
+	return res, nil
+}
+
+// newRequest creates a request with the context and JSON accepted, the body is
+// form encoded when url.Values, JSON encoded otherwise.
+func (k *Keycloak) newRequest(
+	ctx context.Context,
+	method, endpoint, token string,
+	body any,
+) (*http.Request, error) {
+	var r io.Reader
+	contentType := ""
+	switch b := body.(type) {
+	case nil:
+	case url.Values:
+		r = strings.NewReader(b.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	default:
+		payload, err := json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(payload)
+		contentType = "application/json"
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, r)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+// discover reads the realm OpenID Connect discovery document, returning the
+// realm token endpoint and recording the issuer.
+func (k *Keycloak) discover(ctx context.Context) (string, error) {
+	req, err := k.newRequest(ctx, http.MethodGet,
+		k.realmURL(k.realm)+"/.well-known/openid-configuration", "", nil)
+	if err != nil {
+		return "", err
+	}
+	var discovery struct {
+		Issuer        string `json:"issuer"`
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if _, err = k.do(req, http.StatusOK, &discovery); err != nil {
+		return "", err
+	}
+	if discovery.Issuer == "" || discovery.TokenEndpoint == "" {
+		return "", fmt.Errorf("%w: realm %q: incomplete OIDC discovery",
+			ErrKeycloakRequest, k.realm)
+	}
+	k.issuer = discovery.Issuer
+	return discovery.TokenEndpoint, nil
+}
+
+// verify asserts the realm accepts the client credentials. Clients without
+// service accounts refuse the client credentials grant as an unauthorized
+// client, after authenticating it, thus accepted.
+func (k *Keycloak) verify(ctx context.Context, tokenEndpoint string) error {
+	req, err := k.newRequest(ctx, http.MethodPost, tokenEndpoint, "",
+		url.Values{"grant_type": {"client_credentials"}})
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(url.QueryEscape(k.clientID), url.QueryEscape(k.clientSecret))
+	res, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: keycloak unreachable: %w", ErrKeycloakRequest, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+	var tokenErr struct {
+		Error string `json:"error"`
+	}
+	_ = json.NewDecoder(res.Body).Decode(&tokenErr)
+	if res.StatusCode == http.StatusBadRequest &&
+		tokenErr.Error == "unauthorized_client" {
+		return nil
+	}
+	return fmt.Errorf("%w: client credentials refused: %s %s",
+		ErrKeycloakRequest, res.Status, tokenErr.Error)
+}
+
+// adminToken obtains the administrator access token.
+func (k *Keycloak) adminToken(ctx context.Context) (string, error) {
+	req, err := k.newRequest(ctx, http.MethodPost,
+		k.realmURL(keycloakAdminRealm)+"/protocol/openid-connect/token", "",
+		url.Values{
+			"grant_type": {"password"},
+			"client_id":  {keycloakAdminClient},
+			"username":   {k.adminUsername},
+			"password":   {k.adminPassword},
+		})
+	if err != nil {
+		return "", err
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if _, err = k.do(req, http.StatusOK, &token); err != nil {
+		return "", fmt.Errorf("administrator credentials refused: %w", err)
+	}
+	return token.AccessToken, nil
+}
+
+// createClient creates the confidential OIDC client on the realm, recording the
+// client secret, generated by Keycloak unless informed.
+func (k *Keycloak) createClient(ctx context.Context, cfg *config.Config) error {
+	token, err := k.adminToken(ctx)
+	if err != nil {
+		return err
+	}
+	redirectURIs := k.redirectURIs
+	if len(redirectURIs) == 0 {
+		external, err := GetExternalURLs(cfg)
+		if err != nil {
+			return err
+		}
+		if external.Callback != "" {
+			redirectURIs = []string{external.Callback}
+		}
+	}
+	representation := map[string]any{
+		"clientId":                  k.clientID,
+		"protocol":                  "openid-connect",
+		"enabled":                   true,
+		"publicClient":              false,
+		"standardFlowEnabled":       true,
+		"directAccessGrantsEnabled": false,
+		"redirectUris":              redirectURIs,
+	}
+	if k.clientSecret != "" {
+		representation["secret"] = k.clientSecret
+	}
+	clientsURL := k.realmURL(k.realm, "admin") + "/clients"
+	req, err := k.newRequest(
+		ctx, http.MethodPost, clientsURL, token, representation)
+	if err != nil {
+		return err
+	}
+	res, err := k.do(req, http.StatusCreated, nil)
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusConflict {
+			return fmt.Errorf("%w: client %q already exists on realm %q",
+				ErrKeycloakRequest, k.clientID, k.realm)
+		}
+		return err
+	}
+	// The created client internal ID is the last element of its location.
+	id := path.Base(res.Header.Get("Location"))
+	if id == "." || id == "/" {
+		return fmt.Errorf("%w: client %q created without location",
+			ErrKeycloakRequest, k.clientID)
+	}
+	req, err = k.newRequest(ctx, http.MethodGet,
+		clientsURL+"/"+url.PathEscape(id)+"/client-secret", token, nil)
+	if err != nil {
+		return err
+	}
+	var secret struct {
+		Value string `json:"value"`
+	}
+	if _, err = k.do(req, http.StatusOK, &secret); err != nil {
+		return err
+	}
+	k.clientSecret = secret.Value
+	return nil
+}
+
+// Data returns the Keycloak integration data. The realm issuer is discovered,
+// the client is created with "--create", and the client credentials verified
+// against the realm before stored.
+func (k *Keycloak) Data(
+	ctx context.Context,
+	_ *runcontext.RunContext,
+	cfg *config.Config,
+) (map[string][]byte, error) {
+	tokenEndpoint, err := k.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if k.create {
+		if err = k.createClient(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err = k.verify(ctx, tokenEndpoint); err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		"url":           []byte(k.url),
+		"realm":         []byte(k.realm),
+		"issuer":        []byte(k.issuer),
+		"client-id":     []byte(k.clientID),
+		"client-secret": []byte(k.clientSecret),
+	}, nil
+}
+
+// Verify asserts the realm accepts the stored client credentials.
+func (k *Keycloak) Verify(ctx context.Context, data map[string][]byte) error {
+	k.url = string(data["url"])
+	k.realm = string(data["realm"])
+	k.clientID = string(data["client-id"])
+	k.clientSecret = string(data["client-secret"])
+	tokenEndpoint, err := k.discover(ctx)
+	if err != nil {
+		return err
+	}
+	return k.verify(ctx, tokenEndpoint)
+}
+
+// NewKeycloak instantiates a new Keycloak integration.
+func NewKeycloak() *Keycloak {
+	return &Keycloak{client: &http.Client{Timeout: 30 * time.Second}}
+}
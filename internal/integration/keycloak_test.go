@@ -0,0 +1,202 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	o "github.com/onsi/gomega"
+)
+
+// fakeKeycloak a minimal Keycloak server, serving the "demo" realm discovery
+// and token endpoints, and the clients admin REST API.
+type fakeKeycloak struct {
+	server  *httptest.Server
+	clients map[string]map[string]any // clients by client ID
+}
+
+func (f *fakeKeycloak) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	realm := f.server.URL + "/realms/demo"
+	switch r.URL.Path {
+	case "/realms/demo/.well-known/openid-configuration":
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":         realm,
+			"token_endpoint": realm + "/protocol/openid-connect/token",
+		})
+	case "/realms/demo/protocol/openid-connect/token":
+		id, secret, _ := r.BasicAuth()
+		client, ok := f.clients[id]
+		if !ok || client["secret"] != secret {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": "invalid_client"}`))
+			return
+		}
+		// The clients have no service account.
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error": "unauthorized_client"}`))
+	case "/realms/master/protocol/openid-connect/token":
+		if r.FormValue("client_id") != keycloakAdminClient ||
+			r.FormValue("username") != "admin" ||
+			r.FormValue("password") != "admin" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token": "admin-token"}`))
+	case "/admin/realms/demo/clients":
+		if r.Header.Get("Authorization") != "Bearer admin-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var client map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&client)
+		id, _ := client["clientId"].(string)
+		if _, exists := f.clients[id]; exists {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		if _, ok := client["secret"]; !ok {
+			client["secret"] = "generated"
+		}
+		f.clients[id] = client
+		w.Header().Set("Location", f.server.URL+"/admin/realms/demo/clients/"+id)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		for id, client := range f.clients {
+			if r.URL.Path == "/admin/realms/demo/clients/"+id+"/client-secret" {
+				_ = json.NewEncoder(w).Encode(map[string]any{
+					"type": "secret", "value": client["secret"]})
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newFakeKeycloak starts a fake Keycloak server with the "existing" client,
+// closed when the test finishes.
+func newFakeKeycloak(t *testing.T) *fakeKeycloak {
+	fake := &fakeKeycloak{clients: map[string]map[string]any{
+		"existing": {"clientId": "existing", "secret": "secret"},
+	}}
+	fake.server = httptest.NewServer(fake)
+	t.Cleanup(fake.server.Close)
+	return fake
+}
+
+func TestKeycloak(t *testing.T) {
+	ctx := context.Background()
+
+	// newKeycloak returns the module on the "demo" realm of a new fake server,
+	// the subtests don't share the server clients.
+	newKeycloak := func(t *testing.T) (*Keycloak, *fakeKeycloak) {
+		fake := newFakeKeycloak(t)
+		k := NewKeycloak()
+		k.url = fake.server.URL + "/"
+		k.realm = "demo"
+		k.client = fake.server.Client()
+		return k, fake
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		g := o.NewWithT(t)
+		k, fake := newKeycloak(t)
+		k.clientID = "existing"
+		g.Expect(k.Validate()).To(o.HaveOccurred())
+
+		k.clientSecret = "secret"
+		g.Expect(k.Validate()).To(o.Succeed())
+		g.Expect(k.url).To(o.Equal(fake.server.URL))
+
+		k.adminUsername = "admin"
+		g.Expect(k.Validate()).To(o.HaveOccurred())
+
+		k.create = true
+		g.Expect(k.Validate()).To(o.MatchError(o.ContainSubstring("admin-password")))
+
+		k.adminPassword = "admin"
+		k.clientSecret = ""
+		g.Expect(k.Validate()).To(o.Succeed())
+
+		k.redirectURIs = []string{"example.com/callback"}
+		g.Expect(k.Validate()).To(o.HaveOccurred())
+	})
+
+	t.Run("Existing", func(t *testing.T) {
+		g := o.NewWithT(t)
+		k, fake := newKeycloak(t)
+		k.clientID = "existing"
+		k.clientSecret = "secret"
+		g.Expect(k.Validate()).To(o.Succeed())
+
+		data, err := k.Data(ctx, nil, nil)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(data["issuer"])).To(o.Equal(fake.server.URL + "/realms/demo"))
+		g.Expect(string(data["client-secret"])).To(o.Equal("secret"))
+	})
+
+	t.Run("InvalidClientSecret", func(t *testing.T) {
+		g := o.NewWithT(t)
+		k, _ := newKeycloak(t)
+		k.clientID = "existing"
+		k.clientSecret = "wrong"
+		g.Expect(k.Validate()).To(o.Succeed())
+		_, err := k.Data(ctx, nil, nil)
+		g.Expect(err).To(o.MatchError(ErrKeycloakRequest))
+	})
+
+	t.Run("UnknownRealm", func(t *testing.T) {
+		g := o.NewWithT(t)
+		k, _ := newKeycloak(t)
+		k.realm = "other"
+		k.clientID = "existing"
+		k.clientSecret = "secret"
+		g.Expect(k.Validate()).To(o.Succeed())
+		_, err := k.Data(ctx, nil, nil)
+		g.Expect(err).To(o.MatchError(ErrKeycloakRequest))
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		g := o.NewWithT(t)
+		k, fake := newKeycloak(t)
+		k.clientID = "created"
+		k.adminUsername = "admin"
+		k.adminPassword = "admin"
+		k.create = true
+		g.Expect(k.Validate()).To(o.Succeed())
+
+		// The redirect URI defaults to the external callback URL.
+		cfg := &config.Config{Installer: config.Spec{Settings: config.Settings{
+			ExternalURLsSetting: map[string]any{
+				"callback": "https://app.example.com/callback",
+			},
+		}}}
+		data, err := k.Data(ctx, nil, cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(data["client-id"])).To(o.Equal("created"))
+		g.Expect(string(data["client-secret"])).To(o.Equal("generated"))
+		g.Expect(fake.clients["created"]["redirectUris"]).To(o.ConsistOf(
+			"https://app.example.com/callback"))
+		g.Expect(fake.clients["created"]["publicClient"]).To(o.BeFalse())
+
+		// The client exists already.
+		_, err = k.Data(ctx, nil, cfg)
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("already exists")))
+	})
+
+	t.Run("CreateInvalidAdmin", func(t *testing.T) {
+		g := o.NewWithT(t)
+		k, fake := newKeycloak(t)
+		k.clientID = "refused"
+		k.adminUsername = "admin"
+		k.adminPassword = "wrong"
+		k.create = true
+		g.Expect(k.Validate()).To(o.Succeed())
+		_, err := k.Data(ctx, nil, nil)
+		g.Expect(err).To(o.MatchError(ErrKeycloakRequest))
+		g.Expect(fake.clients).NotTo(o.HaveKey("refused"))
+	})
+}
@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Chat providers receiving the notifications.
+const (
+	// NotificationSlack Slack incoming webhooks.
+	NotificationSlack = "slack"
+	// NotificationTeams Microsoft Teams incoming webhooks, or workflows.
+	NotificationTeams = "teams"
+)
+
+// NotificationProviders the chat providers supported.
+var NotificationProviders = []string{NotificationSlack, NotificationTeams}
+
+// Notification integration secret keys.
+const (
+	// NotificationProviderKey the chat provider.
+	NotificationProviderKey = "provider"
+	// NotificationWebhookURLKey the incoming webhook URL.
+	NotificationWebhookURLKey = "webhook-url"
+	// NotificationChannelKey the channel posted to, informative for the
+	// providers binding the webhook to a channel.
+	NotificationChannelKey = "channel"
+	// NotificationDeployEventsKey the deploy events posted by the installer,
+	// comma separated.
+	NotificationDeployEventsKey = "deploy-events"
+)
+
+// Notification represents the chat notification integration coordinates, the
+// incoming webhook products post their notifications to, for instance the
+// pipeline notifications. The installer posts the deploy events as well.
+type Notification struct {
+	provider     string   // chat provider
+	webhookURL   string   // incoming webhook URL
+	channel      string   // channel posted to
+	deployEvents []string // deploy events posted by the installer
+}
+
+var _ Interface = &Notification{}
+var _ Credential = &Notification{}
+
+// CredentialFlag the webhook URL embeds its credentials, it can be informed
+// via STDIN or the keychain.
+func (n *Notification) CredentialFlag() string {
+	return "webhook-url"
+}
+
+// PersistentFlags adds the persistent flags to the informed Cobra command.
+func (n *Notification) PersistentFlags(c *cobra.Command) {
+	p := c.PersistentFlags()
+
+	p.StringVar(&n.provider, "provider", n.provider, fmt.Sprintf(
+		"Chat provider, one of %s", strings.Join(NotificationProviders, ", ")))
+	p.StringVar(&n.webhookURL, "webhook-url", n.webhookURL,
+		"Incoming webhook URL of the channel")
+	p.StringVar(&n.channel, "channel", n.channel,
+		"Channel posted to, e.g. #deployments")
+	p.StringSliceVar(&n.deployEvents, "deploy-events", n.deployEvents,
+		"Deploy events posted by the installer, empty to post none")
+
+	for _, f := range []string{"provider"} {
+		if err := c.MarkPersistentFlagRequired(f); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// SetArgument sets additional arguments to the integration.
+func (n *Notification) SetArgument(string, string) error {
+	return nil
+}
+
+// LoggerWith decorates the logger with the integration flags.
+func (n *Notification) LoggerWith(logger *slog.Logger) *slog.Logger {
+	return logger.With(
+		"provider", n.provider,
+		"webhook-url-len", len(n.webhookURL),
+		"channel", n.channel,
+		"deploy-events", n.deployEvents,
+	)
+}
+
+// Type returns the type of the integration.
+func (n *Notification) Type() corev1.SecretType {
+	return corev1.SecretTypeOpaque
+}
+
+// Validate validates the integration configuration, the webhook URL must be
+// HTTPS, as it carries the credentials.
+func (n *Notification) Validate() error {
+	if !slices.Contains(NotificationProviders, n.provider) {
+		return fmt.Errorf("invalid provider %q, expected one of %s",
+			n.provider, strings.Join(NotificationProviders, ", "))
+	}
+	if n.webhookURL == "" {
+		return fmt.Errorf("webhook-url is required")
+	}
+	u, err := url.Parse(n.webhookURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%w: invalid webhook-url, an HTTPS URL is expected",
+			ErrInvalidURL)
+	}
+	for _, event := range n.deployEvents {
+		if !slices.Contains(config.WebhookEvents, event) {
+			return fmt.Errorf("unknown deploy event %q, expected one of %s",
+				event, strings.Join(config.WebhookEvents, ", "))
+		}
+	}
+	return nil
+}
+
+// Data returns the notification integration data.
+func (n *Notification) Data(
+	_ context.Context,
+	_ *runcontext.RunContext,
+	_ *config.Config,
+) (map[string][]byte, error) {
+	return map[string][]byte{
+		NotificationProviderKey:     []byte(n.provider),
+		NotificationWebhookURLKey:   []byte(n.webhookURL),
+		NotificationChannelKey:      []byte(n.channel),
+		NotificationDeployEventsKey: []byte(strings.Join(n.deployEvents, ",")),
+	}, nil
+}
+
+// NewNotification instantiates a new chat notification integration, the
+// installer posts the completed and failed deployments by default.
+func NewNotification() *Notification {
+	return &Notification{deployEvents: []string{
+		config.WebhookEventCompleted,
+		config.WebhookEventFailed,
+	}}
+}
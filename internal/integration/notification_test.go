@@ -0,0 +1,46 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestNotification(t *testing.T) {
+	g := o.NewWithT(t)
+
+	n := NewNotification()
+	n.provider = "irc"
+	n.webhookURL = "https://hooks.slack.com/services/T0/B0/secret"
+	g.Expect(n.Validate()).To(o.HaveOccurred())
+	n.provider = NotificationSlack
+	g.Expect(n.Validate()).To(o.Succeed())
+	n.webhookURL = "http://hooks.slack.com/services/T0/B0/secret"
+	g.Expect(n.Validate()).To(o.MatchError(ErrInvalidURL))
+	n.webhookURL = "https://hooks.slack.com/services/T0/B0/secret"
+	n.deployEvents = []string{"deploy.unknown"}
+	g.Expect(n.Validate()).To(o.HaveOccurred())
+
+	n.channel = "#deployments"
+	n.deployEvents = []string{"deploy.started", "deploy.failed"}
+	g.Expect(n.Validate()).To(o.Succeed())
+	data, err := n.Data(context.Background(), nil, nil)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(data).To(o.Equal(map[string][]byte{
+		NotificationProviderKey:     []byte("slack"),
+		NotificationWebhookURLKey:   []byte(n.webhookURL),
+		NotificationChannelKey:      []byte("#deployments"),
+		NotificationDeployEventsKey: []byte("deploy.started,deploy.failed"),
+	}))
+
+	n = NewNotification()
+	n.provider = NotificationTeams
+	g.Expect(n.Validate()).To(o.HaveOccurred())
+	n.webhookURL = "https://example.webhook.office.com/webhookb2/secret"
+	n.deployEvents = nil
+	g.Expect(n.Validate()).To(o.Succeed())
+	data, err = n.Data(context.Background(), nil, nil)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(data[NotificationDeployEventsKey]).To(o.BeEmpty())
+}
@@ -0,0 +1,201 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ErrPagerDutyRequest the PagerDuty REST API refused or failed the request.
+var ErrPagerDutyRequest = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"pagerduty request failed")
+
+// PagerDuty default endpoints, the US service region.
+const (
+	// PagerDutyAPIURL the REST API URL.
+	PagerDutyAPIURL = "https://api.pagerduty.com"
+	// PagerDutyEventsURL the Events API v2 enqueue URL.
+	PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+)
+
+// PagerDuty integration secret keys.
+const (
+	// PagerDutyRoutingKeyKey the Events API v2 integration key of the service.
+	PagerDutyRoutingKeyKey = "routing-key"
+	// PagerDutyAPITokenKey the REST API token, optional.
+	PagerDutyAPITokenKey = "api-token"
+	// PagerDutyAPIURLKey the REST API URL.
+	PagerDutyAPIURLKey = "api-url"
+	// PagerDutyEventsURLKey the Events API v2 enqueue URL.
+	PagerDutyEventsURLKey = "events-url"
+)
+
+// pagerDutyRoutingKeyRE the Events API v2 integration keys, 32 characters.
+var pagerDutyRoutingKeyRE = regexp.MustCompile(`^[a-zA-Z0-9]{32}$`)
+
+// PagerDuty represents the PagerDuty coordinates of the alerting-aware products,
+// the service routing key they trigger the incidents with, on the Events API,
+// and optionally the REST API token managing them.
+type PagerDuty struct {
+	routingKey string // events API v2 integration key
+	apiToken   string // REST API token
+	apiURL     string // REST API URL
+	eventsURL  string // events API v2 enqueue URL
+
+	client *http.Client // REST API http client
+}
+
+var _ Interface = &PagerDuty{}
+var _ Credential = &PagerDuty{}
+var _ Capable = &PagerDuty{}
+var _ Verifier = &PagerDuty{}
+
+// CredentialFlag the REST API token can be informed via STDIN or the keychain.
+func (p *PagerDuty) CredentialFlag() string {
+	return "api-token"
+}
+
+// Capabilities the REST API token, when informed, is verified before the
+// secret is stored.
+func (p *PagerDuty) Capabilities() []Capability {
+	return []Capability{CapabilityVerification}
+}
+
+// PersistentFlags adds the persistent flags to the informed Cobra command.
+func (p *PagerDuty) PersistentFlags(c *cobra.Command) {
+	f := c.PersistentFlags()
+
+	f.StringVar(&p.routingKey, "routing-key", p.routingKey,
+		"Events API v2 integration key of the PagerDuty service")
+	f.StringVar(&p.apiToken, "api-token", p.apiToken,
+		"PagerDuty REST API token, optional")
+	f.StringVar(&p.apiURL, "api-url", p.apiURL,
+		"PagerDuty REST API URL, e.g. https://api.eu.pagerduty.com")
+	f.StringVar(&p.eventsURL, "events-url", p.eventsURL,
+		"PagerDuty Events API v2 URL, e.g. https://events.eu.pagerduty.com/v2/enqueue")
+
+	if err := c.MarkPersistentFlagRequired("routing-key"); err != nil {
+		panic(err)
+	}
+}
+
+// SetArgument sets additional arguments to the integration.
+func (p *PagerDuty) SetArgument(string, string) error {
+	return nil
+}
+
+// LoggerWith decorates the logger with the integration flags.
+func (p *PagerDuty) LoggerWith(logger *slog.Logger) *slog.Logger {
+	return logger.With(
+		"routing-key-len", len(p.routingKey),
+		"api-token-len", len(p.apiToken),
+		"api-url", p.apiURL,
+		"events-url", p.eventsURL,
+	)
+}
+
+// Validate validates the integration configuration, the routing key must be a
+// 32 characters Events API v2 integration key.
+func (p *PagerDuty) Validate() error {
+	if p.routingKey == "" {
+		return fmt.Errorf("routing-key is required")
+	}
+	if !pagerDutyRoutingKeyRE.MatchString(p.routingKey) {
+		return fmt.Errorf("invalid routing-key, a 32 characters Events API v2 " +
+			"integration key is expected")
+	}
+	for _, u := range []string{p.apiURL, p.eventsURL} {
+		if err := ValidateURL(u); err != nil {
+			return fmt.Errorf("%w: %q", err, u)
+		}
+	}
+	p.apiURL = strings.TrimSuffix(p.apiURL, "/")
+	return nil
+}
+
+// Type returns the type of the integration.
+func (p *PagerDuty) Type() corev1.SecretType {
+	return corev1.SecretTypeOpaque
+}
+
+// verifyToken asserts the REST API token authenticates, listing the account
+// abilities, which any valid token is allowed to.
+func (p *PagerDuty) verifyToken(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, p.apiURL+"/abilities", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Authorization", "Token token="+p.apiToken)
+	res, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: pagerduty unreachable: %w", ErrPagerDutyRequest, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s %s: %s", ErrPagerDutyRequest,
+			req.Method, req.URL.Redacted(), res.Status)
+	}
+	return nil
+}
+
+// Data returns the PagerDuty integration data, the REST API token, when
+// informed, is verified before stored. The routing key can't be verified
+// without triggering an incident.
+func (p *PagerDuty) Data(
+	ctx context.Context,
+	_ *runcontext.RunContext,
+	_ *config.Config,
+) (map[string][]byte, error) {
+	data := map[string][]byte{
+		PagerDutyRoutingKeyKey: []byte(p.routingKey),
+		PagerDutyAPIURLKey:     []byte(p.apiURL),
+		PagerDutyEventsURLKey:  []byte(p.eventsURL),
+	}
+	if p.apiToken == "" {
+		return data, nil
+	}
+	if err := p.verifyToken(ctx); err != nil {
+		return nil, err
+	}
+	data[PagerDutyAPITokenKey] = []byte(p.apiToken)
+	return data, nil
+}
+
+// Verify asserts the stored REST API token authenticates. Without the token
+// there's nothing to verify, the routing key can't be verified without
+// triggering an incident.
+func (p *PagerDuty) Verify(ctx context.Context, data map[string][]byte) error {
+	p.apiToken = string(data[PagerDutyAPITokenKey])
+	if p.apiToken == "" {
+		return fmt.Errorf("%w: the routing key can't be verified without "+
+			"triggering an incident, and no api-token is stored",
+			ErrVerificationUnsupported)
+	}
+	if apiURL := string(data[PagerDutyAPIURLKey]); apiURL != "" {
+		p.apiURL = apiURL
+	}
+	return p.verifyToken(ctx)
+}
+
+// NewPagerDuty instantiates a new PagerDuty integration, on the US service
+// region endpoints by default.
+func NewPagerDuty() *PagerDuty {
+	return &PagerDuty{
+		apiURL:    PagerDutyAPIURL,
+		eventsURL: PagerDutyEventsURL,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
@@ -0,0 +1,96 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestPagerDuty(t *testing.T) {
+	ctx := context.Background()
+	routingKey := strings.Repeat("a1", 16)
+	api := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/abilities" ||
+				r.Header.Get("Authorization") != "Token token=valid" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"abilities": ["teams"]}`))
+		},
+	))
+	defer api.Close()
+
+	newPagerDuty := func() *PagerDuty {
+		p := NewPagerDuty()
+		p.routingKey = routingKey
+		p.apiURL = api.URL + "/"
+		p.client = api.Client()
+		return p
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		g := o.NewWithT(t)
+		p := newPagerDuty()
+		p.routingKey = ""
+		g.Expect(p.Validate()).To(o.MatchError(o.ContainSubstring("required")))
+
+		p.routingKey = "not-a-routing-key"
+		g.Expect(p.Validate()).To(o.MatchError(o.ContainSubstring("32 characters")))
+
+		p = newPagerDuty()
+		p.eventsURL = "events.pagerduty.com"
+		g.Expect(p.Validate()).To(o.MatchError(ErrInvalidURL))
+
+		p = newPagerDuty()
+		g.Expect(p.Validate()).To(o.Succeed())
+		g.Expect(p.apiURL).To(o.Equal(api.URL))
+	})
+
+	t.Run("RoutingKey", func(t *testing.T) {
+		g := o.NewWithT(t)
+		p := newPagerDuty()
+		g.Expect(p.Validate()).To(o.Succeed())
+
+		data, err := p.Data(ctx, nil, nil)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(data[PagerDutyRoutingKeyKey])).To(o.Equal(routingKey))
+		g.Expect(string(data[PagerDutyEventsURLKey])).To(o.Equal(PagerDutyEventsURL))
+		g.Expect(data).NotTo(o.HaveKey(PagerDutyAPITokenKey))
+	})
+
+	t.Run("APIToken", func(t *testing.T) {
+		g := o.NewWithT(t)
+		p := newPagerDuty()
+		p.apiToken = "valid"
+		g.Expect(p.Validate()).To(o.Succeed())
+
+		data, err := p.Data(ctx, nil, nil)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(data[PagerDutyAPITokenKey])).To(o.Equal("valid"))
+
+		p.apiToken = "invalid"
+		_, err = p.Data(ctx, nil, nil)
+		g.Expect(err).To(o.MatchError(ErrPagerDutyRequest))
+	})
+	t.Run("Verify", func(t *testing.T) {
+		g := o.NewWithT(t)
+		data := map[string][]byte{
+			PagerDutyRoutingKeyKey: []byte(routingKey),
+			PagerDutyAPIURLKey:     []byte(api.URL),
+		}
+		g.Expect(newPagerDuty().Verify(ctx, data)).
+			To(o.MatchError(ErrVerificationUnsupported))
+
+		data[PagerDutyAPITokenKey] = []byte("valid")
+		g.Expect(newPagerDuty().Verify(ctx, data)).To(o.Succeed())
+
+		data[PagerDutyAPITokenKey] = []byte("revoked")
+		g.Expect(newPagerDuty().Verify(ctx, data)).
+			To(o.MatchError(ErrPagerDutyRequest))
+	})
+}
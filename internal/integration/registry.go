@@ -0,0 +1,143 @@
+package integration
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+)
+
+// ErrRegistryRequest the container registry refused or failed the request.
+var ErrRegistryRequest = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"registry request failed")
+
+// registryChallengeRe matches the authentication challenge attributes.
+var registryChallengeRe = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// dockerConfigAuths returns the registries of the docker config JSON and their
+// basic authentication, base64 encoded.
+func dockerConfigAuths(dockerConfig []byte) (map[string]string, error) {
+	var cfg struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(dockerConfig, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid docker config: %w", err)
+	}
+	auths := map[string]string{}
+	for registry, entry := range cfg.Auths {
+		auth := entry.Auth
+		if auth == "" && entry.Username != "" {
+			auth = base64.StdEncoding.EncodeToString(
+				[]byte(entry.Username + ":" + entry.Password))
+		}
+		// Registries are informed as hostnames, or URLs.
+		if u, err := url.Parse(registry); err == nil && u.Host != "" {
+			registry = u.Host
+		}
+		auths[registry] = auth
+	}
+	return auths, nil
+}
+
+// verifyDockerConfig asserts every registry of the docker config JSON accepts
+// its credentials, see verifyRegistryAuth.
+func verifyDockerConfig(
+	ctx context.Context,
+	client *http.Client,
+	dockerConfig []byte,
+) error {
+	auths, err := dockerConfigAuths(dockerConfig)
+	if err != nil {
+		return err
+	}
+	for registry, auth := range auths {
+		if err = verifyRegistryAuth(ctx, client, registry, auth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyRegistryAuth asserts the registry accepts the basic authentication,
+// following the registry authentication challenge: with "Basic" the registry
+// API must accept the credentials, with "Bearer" the token service must grant
+// a token for them.
+func verifyRegistryAuth(
+	ctx context.Context,
+	client *http.Client,
+	registry, auth string,
+) error {
+	endpoint := fmt.Sprintf("https://%s/v2/", registry)
+	get := func(endpoint, auth string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(
+			ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		if auth != "" {
+			req.Header.Set("Authorization", "Basic "+auth)
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: registry %q unreachable: %w",
+				ErrRegistryRequest, registry, err)
+		}
+		return res, nil
+	}
+
+	res, err := get(endpoint, "")
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	challenge := res.Header.Get("WWW-Authenticate")
+	if res.StatusCode != http.StatusUnauthorized ||
+		!strings.HasPrefix(challenge, "Bearer ") {
+		// Anonymous or basic authentication, the registry API itself must
+		// accept the credentials.
+		if res, err = get(endpoint, auth); err != nil {
+			return err
+		}
+		res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("%w: GET %s: credentials refused: %s",
+				ErrRegistryRequest, endpoint, res.Status)
+		}
+		return nil
+	}
+
+	// Following the challenge, "Bearer realm=<url>,service=<service>".
+	attrs := map[string]string{}
+	for _, m := range registryChallengeRe.FindAllStringSubmatch(challenge, -1) {
+		attrs[m[1]] = m[2]
+	}
+	realm, err := url.Parse(attrs["realm"])
+	if err != nil || attrs["realm"] == "" {
+		return fmt.Errorf("%w: invalid authentication realm %q",
+			ErrRegistryRequest, attrs["realm"])
+	}
+	query := realm.Query()
+	if service := attrs["service"]; service != "" {
+		query.Set("service", service)
+	}
+	realm.RawQuery = query.Encode()
+	if res, err = get(realm.String(), auth); err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: registry %q: credentials refused: %s",
+			ErrRegistryRequest, registry, res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestVerifyDockerConfig(t *testing.T) {
+	ctx := context.Background()
+	valid := base64.StdEncoding.EncodeToString([]byte("robot:secret"))
+
+	// newRegistry fakes a registry challenging for the informed scheme, the
+	// "Bearer" token service is served by the registry itself.
+	newRegistry := func(scheme string) *httptest.Server {
+		var srv *httptest.Server
+		srv = httptest.NewTLSServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				authorized := r.Header.Get("Authorization") == "Basic "+valid
+				switch {
+				case r.URL.Path == "/token" && authorized &&
+					r.URL.Query().Get("service") == "registry":
+					_, _ = w.Write([]byte(`{"token": "t"}`))
+				case r.URL.Path == "/v2/" && scheme == "Basic" && authorized:
+					w.WriteHeader(http.StatusOK)
+				case r.URL.Path == "/v2/" && scheme == "Bearer":
+					w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+						`Bearer realm="%s/token",service="registry"`, srv.URL))
+					w.WriteHeader(http.StatusUnauthorized)
+				default:
+					w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+			},
+		))
+		return srv
+	}
+	dockerConfig := func(srv *httptest.Server, entry string) []byte {
+		host := strings.TrimPrefix(srv.URL, "https://")
+		return []byte(fmt.Sprintf(`{"auths": {%q: %s}}`, host, entry))
+	}
+
+	for _, scheme := range []string{"Basic", "Bearer"} {
+		t.Run(scheme, func(t *testing.T) {
+			g := o.NewWithT(t)
+			srv := newRegistry(scheme)
+			defer srv.Close()
+
+			g.Expect(verifyDockerConfig(ctx, srv.Client(), dockerConfig(
+				srv, fmt.Sprintf(`{"auth": %q}`, valid)))).To(o.Succeed())
+			g.Expect(verifyDockerConfig(ctx, srv.Client(), dockerConfig(
+				srv, `{"username": "robot", "password": "secret"}`))).
+				To(o.Succeed())
+
+			err := verifyDockerConfig(ctx, srv.Client(), dockerConfig(
+				srv, `{"username": "robot", "password": "typo"}`))
+			g.Expect(err).To(o.MatchError(ErrRegistryRequest))
+			g.Expect(err).To(o.MatchError(o.ContainSubstring("refused")))
+		})
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		g.Expect(verifyDockerConfig(ctx, http.DefaultClient, []byte("{"))).
+			To(o.MatchError(o.ContainSubstring("invalid docker config")))
+	})
+}
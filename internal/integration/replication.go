@@ -0,0 +1,297 @@
+package integration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ReplicationAction the action taken on a replicated secret.
+type ReplicationAction string
+
+const (
+	// ReplicaCreated the replica didn't exist, it's created.
+	ReplicaCreated ReplicationAction = "created"
+	// ReplicaUpdated the source payload changed, the replica is updated.
+	ReplicaUpdated ReplicationAction = "updated"
+	// ReplicaUnchanged the replica is in sync with the source.
+	ReplicaUnchanged ReplicationAction = "unchanged"
+	// ReplicaDeleted the source is gone, or no longer lists the namespace.
+	ReplicaDeleted ReplicationAction = "deleted"
+	// ReplicaPending the target namespace doesn't exist yet.
+	ReplicaPending ReplicationAction = "pending"
+)
+
+// ErrReplicaConflict the target namespace holds a secret with the same name
+// which isn't a replica, it's never overwritten.
+var ErrReplicaConflict = helmeterrors.New(helmeterrors.ErrConflict,
+	"secret exists and is not a replica")
+
+// ReplicationResult the outcome of replicating a secret into a namespace.
+type ReplicationResult struct {
+	Source    string            // source secret name
+	Namespace string            // target namespace
+	Action    ReplicationAction // action taken
+}
+
+// Replicator keeps copies of the integration secrets, from the installer
+// namespace, in the namespaces listed on the source secret ReplicateTo
+// annotation. Replicas are updated whenever the source payload hash changes,
+// propagating credentials rotation, and removed when no longer listed.
+type Replicator struct {
+	logger    *slog.Logger  // application logger
+	kube      k8s.Interface // kubernetes client
+	namespace string        // installer's namespace, holds the sources
+}
+
+// SecretHash returns the hash of the secret type and payload.
+func SecretHash(secret *corev1.Secret) string {
+	h := sha256.New()
+	h.Write([]byte(secret.Type))
+	keys := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "\x00%s\x00", k)
+		h.Write(secret.Data[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ReplicaTargets returns the namespaces the secret is replicated to.
+func ReplicaTargets(secret *corev1.Secret) []string {
+	targets := []string{}
+	for _, ns := range strings.Split(
+		secret.GetAnnotations()[annotations.ReplicateTo], ",",
+	) {
+		ns = strings.TrimSpace(ns)
+		if ns != "" && ns != secret.GetNamespace() &&
+			!slices.Contains(targets, ns) {
+			targets = append(targets, ns)
+		}
+	}
+	return targets
+}
+
+// sourceRef returns the source secret reference stored on the replicas.
+func (r *Replicator) sourceRef(name string) string {
+	return fmt.Sprintf("%s/%s", r.namespace, name)
+}
+
+// replica returns the replica of the source secret for the namespace.
+func (r *Replicator) replica(
+	source *corev1.Secret,
+	namespace, hash string,
+) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      source.GetName(),
+			Labels: map[string]string{
+				annotations.ReplicaOf: source.GetName(),
+			},
+			Annotations: map[string]string{
+				annotations.ReplicaSource: r.sourceRef(source.GetName()),
+				annotations.SourceHash:    hash,
+			},
+		},
+		Type: source.Type,
+		Data: source.Data,
+	}
+}
+
+// replicate ensures the replica of the source secret in the namespace is in
+// sync, the replicas written are read back before returning.
+func (r *Replicator) replicate(
+	ctx context.Context,
+	source *corev1.Secret,
+	namespace string,
+) (ReplicationAction, error) {
+	coreClient, err := r.kube.CoreV1ClientSet(namespace)
+	if err != nil {
+		return "", err
+	}
+	if _, err = coreClient.Namespaces().
+		Get(ctx, namespace, metav1.GetOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ReplicaPending, nil
+		}
+		return "", err
+	}
+
+	hash := SecretHash(source)
+	desired := r.replica(source, namespace, hash)
+	// written waits for the replica written to be readable, in sync with the
+	// source, before the charts consuming it are installed.
+	written := func(action ReplicationAction, err error) (ReplicationAction, error) {
+		if err != nil {
+			return "", err
+		}
+		_, err = k8s.WaitForSecret(ctx, coreClient, types.NamespacedName{
+			Namespace: namespace,
+			Name:      source.GetName(),
+		}, func(s *corev1.Secret) bool {
+			return s.GetAnnotations()[annotations.SourceHash] == hash
+		})
+		return action, err
+	}
+	existing, err := coreClient.Secrets(namespace).
+		Get(ctx, source.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = coreClient.Secrets(namespace).
+			Create(ctx, desired, metav1.CreateOptions{})
+		return written(ReplicaCreated, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	if existing.GetAnnotations()[annotations.ReplicaSource] !=
+		r.sourceRef(source.GetName()) {
+		return "", fmt.Errorf("%w: %s/%s",
+			ErrReplicaConflict, namespace, source.GetName())
+	}
+	if existing.GetAnnotations()[annotations.SourceHash] == hash {
+		return ReplicaUnchanged, nil
+	}
+	// The secret type is immutable, a type change recreates the replica.
+	if existing.Type != desired.Type {
+		if err = coreClient.Secrets(namespace).Delete(
+			ctx, existing.GetName(), metav1.DeleteOptions{},
+		); err != nil {
+			return "", err
+		}
+		_, err = coreClient.Secrets(namespace).
+			Create(ctx, desired, metav1.CreateOptions{})
+		return written(ReplicaUpdated, err)
+	}
+	desired.SetResourceVersion(existing.GetResourceVersion())
+	_, err = coreClient.Secrets(namespace).
+		Update(ctx, desired, metav1.UpdateOptions{})
+	return written(ReplicaUpdated, err)
+}
+
+// sync replicates the source secrets and prunes the stale replicas. When the
+// namespace is informed only its replicas are synchronized, and when the name
+// is informed only the replicas of that source secret.
+func (r *Replicator) sync(
+	ctx context.Context,
+	only string,
+	name string,
+) ([]ReplicationResult, error) {
+	coreClient, err := r.kube.CoreV1ClientSet(r.namespace)
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := coreClient.Secrets(r.namespace).
+		List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	results := []ReplicationResult{}
+	var errs []error
+	// desired source secret names by target namespace.
+	desired := map[string][]string{}
+	for i := range secrets.Items {
+		source := &secrets.Items[i]
+		for _, ns := range ReplicaTargets(source) {
+			desired[ns] = append(desired[ns], source.GetName())
+			if (only != "" && ns != only) ||
+				(name != "" && source.GetName() != name) {
+				continue
+			}
+			action, err := r.replicate(ctx, source, ns)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			r.logger.Debug("Integration secret replicated",
+				"secret", source.GetName(), "namespace", ns, "action", action)
+			results = append(results, ReplicationResult{
+				Source:    source.GetName(),
+				Namespace: ns,
+				Action:    action,
+			})
+		}
+	}
+
+	// Pruning the replicas no longer desired.
+	replicas, err := coreClient.Secrets(only).List(ctx, metav1.ListOptions{
+		LabelSelector: annotations.ReplicaOf,
+	})
+	if err != nil {
+		return nil, errors.Join(append(errs, err)...)
+	}
+	for _, replica := range replicas.Items {
+		replicaName, ns := replica.GetName(), replica.GetNamespace()
+		if (name != "" && replicaName != name) ||
+			replica.GetAnnotations()[annotations.ReplicaSource] !=
+				r.sourceRef(replicaName) ||
+			slices.Contains(desired[ns], replicaName) {
+			continue
+		}
+		if err = coreClient.Secrets(ns).Delete(
+			ctx, replicaName, metav1.DeleteOptions{},
+		); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		}
+		r.logger.Debug("Stale integration secret replica deleted",
+			"secret", replicaName, "namespace", ns)
+		results = append(results, ReplicationResult{
+			Source:    replicaName,
+			Namespace: ns,
+			Action:    ReplicaDeleted,
+		})
+	}
+	return results, errors.Join(errs...)
+}
+
+// Sync replicates all integration secrets, and prunes the stale replicas.
+func (r *Replicator) Sync(ctx context.Context) ([]ReplicationResult, error) {
+	return r.sync(ctx, "", "")
+}
+
+// SyncNamespace synchronizes only the replicas in the informed namespace.
+func (r *Replicator) SyncNamespace(
+	ctx context.Context,
+	namespace string,
+) ([]ReplicationResult, error) {
+	return r.sync(ctx, namespace, "")
+}
+
+// SyncSecret synchronizes only the replicas of the informed source secret, the
+// other secrets replicas, and their conflicts, are left to Sync.
+func (r *Replicator) SyncSecret(
+	ctx context.Context,
+	name string,
+) ([]ReplicationResult, error) {
+	return r.sync(ctx, "", name)
+}
+
+// NewReplicator instantiates the replicator for the integration secrets on the
+// installer namespace.
+func NewReplicator(
+	logger *slog.Logger,
+	kube k8s.Interface,
+	namespace string,
+) *Replicator {
+	return &Replicator{logger: logger, kube: kube, namespace: namespace}
+}
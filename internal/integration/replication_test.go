@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReplicator(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	namespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "helmet",
+			Name:      "helmet-ex-quay-integration",
+			Annotations: map[string]string{
+				annotations.ReplicateTo: "product-a, product-b,missing,helmet",
+			},
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{".dockerconfigjson": []byte("{}")},
+	}
+	g.Expect(ReplicaTargets(source)).
+		To(o.Equal([]string{"product-a", "product-b", "missing"}))
+
+	r := NewReplicator(
+		slog.New(slog.NewTextHandler(io.Discard, nil)), nil, "helmet")
+
+	// The outdated replica carries the hash of a previous payload.
+	outdated := r.replica(source, "product-b", "previous-hash")
+	// The stale replica source no longer lists the namespace.
+	stale := r.replica(source, "product-c", SecretHash(source))
+
+	t.Run("Sync", func(t *testing.T) {
+		r.kube = k8s.NewFakeKube(
+			namespace("helmet"),
+			namespace("product-a"),
+			namespace("product-b"),
+			namespace("product-c"),
+			source,
+			outdated,
+			stale,
+		)
+		results, err := r.Sync(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(results).To(o.ConsistOf(
+			ReplicationResult{source.Name, "product-a", ReplicaCreated},
+			ReplicationResult{source.Name, "product-b", ReplicaUpdated},
+			ReplicationResult{source.Name, "missing", ReplicaPending},
+			ReplicationResult{source.Name, "product-c", ReplicaDeleted},
+		))
+	})
+
+	t.Run("SyncNamespace", func(t *testing.T) {
+		r.kube = k8s.NewFakeKube(
+			namespace("product-a"),
+			namespace("product-c"),
+			source,
+			r.replica(source, "product-a", SecretHash(source)),
+			stale,
+		)
+		results, err := r.SyncNamespace(ctx, "product-a")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(results).To(o.Equal([]ReplicationResult{
+			{source.Name, "product-a", ReplicaUnchanged},
+		}))
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		r.kube = k8s.NewFakeKube(
+			namespace("product-a"),
+			source,
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Namespace: "product-a",
+				Name:      source.Name,
+			}},
+		)
+		_, err := r.SyncNamespace(ctx, "product-a")
+		g.Expect(err).To(o.MatchError(ErrReplicaConflict))
+	})
+
+	t.Run("SyncSecret", func(t *testing.T) {
+		other := source.DeepCopy()
+		other.Name = "helmet-ex-acs-integration"
+		r.kube = k8s.NewFakeKube(
+			namespace("product-a"),
+			namespace("product-c"),
+			source,
+			other,
+			stale,
+			// The other secret conflicts, and its replica is stale.
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Namespace: "product-a",
+				Name:      other.Name,
+			}},
+			r.replica(other, "product-c", SecretHash(other)),
+		)
+		results, err := r.SyncSecret(ctx, source.Name)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(results).To(o.ConsistOf(
+			ReplicationResult{source.Name, "product-a", ReplicaCreated},
+			ReplicationResult{source.Name, "product-b", ReplicaPending},
+			ReplicationResult{source.Name, "missing", ReplicaPending},
+			ReplicationResult{source.Name, "product-c", ReplicaDeleted},
+		))
+	})
+}
@@ -0,0 +1,270 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ErrRekorRequest the Rekor transparency log refused or failed the request.
+var ErrRekorRequest = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"rekor request failed")
+
+// ErrInvalidSigstoreKey the cosign key material is not valid.
+var ErrInvalidSigstoreKey = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"invalid cosign key")
+
+// Sigstore integration secret keys, the key pair ones are the same "cosign
+// generate-key-pair k8s://" creates, so cosign reads the secret as is.
+const (
+	// SigstorePrivateKeyKey the encrypted cosign private key, PEM encoded.
+	SigstorePrivateKeyKey = "cosign.key"
+	// SigstorePublicKeyKey the cosign public key, PEM encoded.
+	SigstorePublicKeyKey = "cosign.pub"
+	// SigstorePasswordKey the cosign private key password.
+	SigstorePasswordKey = "cosign.password"
+	// SigstoreRekorURLKey the Rekor transparency log URL.
+	SigstoreRekorURLKey = "rekor-url"
+	// SigstoreFulcioURLKey the Fulcio certificate authority URL, keyless.
+	SigstoreFulcioURLKey = "fulcio-url"
+	// SigstoreOIDCIssuerKey the OIDC issuer of the keyless identities.
+	SigstoreOIDCIssuerKey = "oidc-issuer"
+)
+
+// sigstorePrivateKeyTypes the PEM block types of the cosign private keys, the
+// older cosign releases label them as cosign's, the newer as sigstore's.
+var sigstorePrivateKeyTypes = []string{
+	"ENCRYPTED SIGSTORE PRIVATE KEY",
+	"ENCRYPTED COSIGN PRIVATE KEY",
+}
+
+// Sigstore represents the Sigstore coordinates signing and verifying the
+// supply-chain artifacts with cosign, either a cosign key pair, or the keyless
+// Fulcio certificate authority, recording the signatures on the Rekor
+// transparency log.
+type Sigstore struct {
+	privateKeyFile string // cosign private key file
+	publicKeyFile  string // cosign public key file
+	password       string // cosign private key password
+	rekorURL       string // rekor transparency log URL
+	fulcioURL      string // fulcio certificate authority URL
+	oidcIssuer     string // keyless identities OIDC issuer
+
+	privateKey []byte       // private key PEM, read from the file
+	publicKey  []byte       // public key PEM, read from the file
+	client     *http.Client // rekor http client
+}
+
+var _ Interface = &Sigstore{}
+var _ Credential = &Sigstore{}
+var _ Capable = &Sigstore{}
+var _ Verifier = &Sigstore{}
+
+// CredentialFlag the private key password can be informed via STDIN or the
+// keychain.
+func (s *Sigstore) CredentialFlag() string {
+	return "password"
+}
+
+// Capabilities the Rekor transparency log is verified before the secret is
+// stored.
+func (s *Sigstore) Capabilities() []Capability {
+	return []Capability{CapabilityVerification}
+}
+
+// PersistentFlags adds the persistent flags to the informed Cobra command.
+func (s *Sigstore) PersistentFlags(c *cobra.Command) {
+	p := c.PersistentFlags()
+
+	p.StringVar(&s.privateKeyFile, "private-key-file", s.privateKeyFile,
+		"Cosign private key file, e.g. cosign.key")
+	p.StringVar(&s.publicKeyFile, "public-key-file", s.publicKeyFile,
+		"Cosign public key file, e.g. cosign.pub")
+	p.StringVar(&s.password, "password", s.password,
+		"Cosign private key password")
+	p.StringVar(&s.rekorURL, "rekor-url", s.rekorURL,
+		"Rekor transparency log URL, e.g. https://rekor.sigstore.dev")
+	p.StringVar(&s.fulcioURL, "fulcio-url", s.fulcioURL,
+		"Fulcio certificate authority URL for keyless signing, e.g. https://fulcio.sigstore.dev")
+	p.StringVar(&s.oidcIssuer, "oidc-issuer", s.oidcIssuer,
+		"OIDC issuer of the keyless signing identities, e.g. https://oauth2.sigstore.dev/auth")
+
+	if err := c.MarkPersistentFlagRequired("rekor-url"); err != nil {
+		panic(err)
+	}
+}
+
+// SetArgument sets additional arguments to the integration.
+func (s *Sigstore) SetArgument(string, string) error {
+	return nil
+}
+
+// LoggerWith decorates the logger with the integration flags.
+func (s *Sigstore) LoggerWith(logger *slog.Logger) *slog.Logger {
+	return logger.With(
+		"private-key-file", s.privateKeyFile,
+		"public-key-file", s.publicKeyFile,
+		"password-len", len(s.password),
+		"rekor-url", s.rekorURL,
+		"fulcio-url", s.fulcioURL,
+		"oidc-issuer", s.oidcIssuer,
+	)
+}
+
+// readPEM reads the PEM encoded key file, asserting its block type is one of
+// the informed.
+func readPEM(file string, types ...string) ([]byte, error) {
+	payload, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSigstoreKey, err)
+	}
+	block, _ := pem.Decode(payload)
+	if block == nil {
+		return nil, fmt.Errorf("%w: %q is not PEM encoded",
+			ErrInvalidSigstoreKey, file)
+	}
+	for _, t := range types {
+		if block.Type == t {
+			return payload, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q holds a %q, expected %s",
+		ErrInvalidSigstoreKey, file, block.Type, strings.Join(types, " or "))
+}
+
+// Validate validates the integration configuration, either the cosign key pair
+// or the Fulcio URL, for keyless signing, are required; the key pair files are
+// read and checked.
+func (s *Sigstore) Validate() error {
+	if s.rekorURL == "" {
+		return fmt.Errorf("rekor-url is required")
+	}
+	if err := ValidateURL(s.rekorURL); err != nil {
+		return fmt.Errorf("%w: %q", err, s.rekorURL)
+	}
+	s.rekorURL = strings.TrimSuffix(s.rekorURL, "/")
+
+	keyPair := s.privateKeyFile != "" || s.publicKeyFile != ""
+	switch {
+	case !keyPair && s.fulcioURL == "":
+		return fmt.Errorf("either the cosign key pair, private-key-file and " +
+			"public-key-file, or the fulcio-url for keyless signing is required")
+	case keyPair && (s.privateKeyFile == "" || s.publicKeyFile == ""):
+		return fmt.Errorf("private-key-file and public-key-file are required " +
+			"together")
+	case !keyPair && s.password != "":
+		return fmt.Errorf("password is only used with the private-key-file")
+	case s.fulcioURL == "" && s.oidcIssuer != "":
+		return fmt.Errorf("oidc-issuer is only used for keyless signing, " +
+			"with fulcio-url")
+	}
+	if s.fulcioURL != "" {
+		if err := ValidateURL(s.fulcioURL); err != nil {
+			return fmt.Errorf("%w: %q", err, s.fulcioURL)
+		}
+	}
+	if s.oidcIssuer != "" {
+		if err := ValidateURL(s.oidcIssuer); err != nil {
+			return fmt.Errorf("%w: %q", err, s.oidcIssuer)
+		}
+	}
+	if !keyPair {
+		return nil
+	}
+
+	var err error
+	if s.privateKey, err = readPEM(
+		s.privateKeyFile, sigstorePrivateKeyTypes...,
+	); err != nil {
+		return err
+	}
+	s.publicKey, err = readPEM(s.publicKeyFile, "PUBLIC KEY")
+	return err
+}
+
+// Type returns the type of the integration.
+func (s *Sigstore) Type() corev1.SecretType {
+	return corev1.SecretTypeOpaque
+}
+
+// verifyRekor asserts the Rekor transparency log responds, reading its log
+// info, the tree size and root hash.
+func (s *Sigstore) verifyRekor(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, s.rekorURL+"/api/v1/log", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: rekor unreachable: %w", ErrRekorRequest, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s %s: %s", ErrRekorRequest,
+			req.Method, req.URL.Redacted(), res.Status)
+	}
+	var info struct {
+		RootHash string `json:"rootHash"`
+		TreeSize *int64 `json:"treeSize"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&info); err != nil ||
+		info.RootHash == "" || info.TreeSize == nil {
+		return fmt.Errorf("%w: %s: not a Rekor log info response",
+			ErrRekorRequest, req.URL.Redacted())
+	}
+	return nil
+}
+
+// Data returns the Sigstore integration data, the Rekor transparency log is
+// verified before stored. The key pair is only stored when informed, likewise
+// the keyless coordinates.
+func (s *Sigstore) Data(
+	ctx context.Context,
+	_ *runcontext.RunContext,
+	_ *config.Config,
+) (map[string][]byte, error) {
+	if err := s.verifyRekor(ctx); err != nil {
+		return nil, err
+	}
+	data := map[string][]byte{
+		SigstoreRekorURLKey: []byte(s.rekorURL),
+	}
+	if s.privateKey != nil {
+		data[SigstorePrivateKeyKey] = s.privateKey
+		data[SigstorePublicKeyKey] = s.publicKey
+		data[SigstorePasswordKey] = []byte(s.password)
+	}
+	if s.fulcioURL != "" {
+		data[SigstoreFulcioURLKey] = []byte(s.fulcioURL)
+	}
+	if s.oidcIssuer != "" {
+		data[SigstoreOIDCIssuerKey] = []byte(s.oidcIssuer)
+	}
+	return data, nil
+}
+
+// Verify asserts the stored Rekor transparency log responds.
+func (s *Sigstore) Verify(ctx context.Context, data map[string][]byte) error {
+	s.rekorURL = string(data[SigstoreRekorURLKey])
+	return s.verifyRekor(ctx)
+}
+
+// NewSigstore instantiates a new Sigstore integration.
+func NewSigstore() *Sigstore {
+	return &Sigstore{client: &http.Client{Timeout: 30 * time.Second}}
+}
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestSigstore(t *testing.T) {
+	ctx := context.Background()
+	rekor := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/log" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"rootHash": "abc123", "treeSize": 42}`))
+		},
+	))
+	defer rekor.Close()
+
+	dir := t.TempDir()
+	writeKey := func(name, blockType string) string {
+		file := filepath.Join(dir, name)
+		payload := "-----BEGIN " + blockType + "-----\n" +
+			"c2lnc3RvcmU=\n-----END " + blockType + "-----\n"
+		if err := os.WriteFile(file, []byte(payload), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	privateKey := writeKey("cosign.key", "ENCRYPTED SIGSTORE PRIVATE KEY")
+	publicKey := writeKey("cosign.pub", "PUBLIC KEY")
+
+	newSigstore := func() *Sigstore {
+		s := NewSigstore()
+		s.rekorURL = rekor.URL + "/"
+		s.client = rekor.Client()
+		return s
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		g := o.NewWithT(t)
+		s := newSigstore()
+		g.Expect(s.Validate()).To(o.MatchError(o.ContainSubstring("keyless")))
+
+		s.privateKeyFile = privateKey
+		g.Expect(s.Validate()).To(o.MatchError(o.ContainSubstring("together")))
+
+		// The keys are swapped.
+		s.privateKeyFile = publicKey
+		s.publicKeyFile = privateKey
+		g.Expect(s.Validate()).To(o.MatchError(ErrInvalidSigstoreKey))
+
+		s.privateKeyFile = filepath.Join(dir, "missing.key")
+		g.Expect(s.Validate()).To(o.MatchError(ErrInvalidSigstoreKey))
+
+		s.privateKeyFile = privateKey
+		s.publicKeyFile = publicKey
+		g.Expect(s.Validate()).To(o.Succeed())
+		g.Expect(s.rekorURL).To(o.Equal(rekor.URL))
+
+		s.oidcIssuer = "https://oauth2.sigstore.dev/auth"
+		g.Expect(s.Validate()).To(o.MatchError(o.ContainSubstring("fulcio-url")))
+
+		s = newSigstore()
+		s.fulcioURL = "fulcio.sigstore.dev"
+		g.Expect(s.Validate()).To(o.MatchError(ErrInvalidURL))
+
+		s.fulcioURL = "https://fulcio.sigstore.dev"
+		s.password = "secret"
+		g.Expect(s.Validate()).To(o.HaveOccurred())
+	})
+
+	t.Run("KeyPair", func(t *testing.T) {
+		g := o.NewWithT(t)
+		s := newSigstore()
+		s.privateKeyFile = privateKey
+		s.publicKeyFile = publicKey
+		s.password = "secret"
+		g.Expect(s.Validate()).To(o.Succeed())
+
+		data, err := s.Data(ctx, nil, nil)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(data).To(o.HaveKey(SigstorePrivateKeyKey))
+		g.Expect(data).To(o.HaveKey(SigstorePublicKeyKey))
+		g.Expect(string(data[SigstorePasswordKey])).To(o.Equal("secret"))
+		g.Expect(string(data[SigstoreRekorURLKey])).To(o.Equal(rekor.URL))
+		g.Expect(data).NotTo(o.HaveKey(SigstoreFulcioURLKey))
+	})
+
+	t.Run("Keyless", func(t *testing.T) {
+		g := o.NewWithT(t)
+		s := newSigstore()
+		s.fulcioURL = "https://fulcio.sigstore.dev"
+		s.oidcIssuer = "https://oauth2.sigstore.dev/auth"
+		g.Expect(s.Validate()).To(o.Succeed())
+
+		data, err := s.Data(ctx, nil, nil)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(data).NotTo(o.HaveKey(SigstorePrivateKeyKey))
+		g.Expect(string(data[SigstoreOIDCIssuerKey])).
+			To(o.Equal("https://oauth2.sigstore.dev/auth"))
+	})
+
+	t.Run("RekorNotResponding", func(t *testing.T) {
+		g := o.NewWithT(t)
+		s := newSigstore()
+		s.rekorURL = rekor.URL + "/not-rekor"
+		s.fulcioURL = "https://fulcio.sigstore.dev"
+		g.Expect(s.Validate()).To(o.Succeed())
+		_, err := s.Data(ctx, nil, nil)
+		g.Expect(err).To(o.MatchError(ErrRekorRequest))
+	})
+}
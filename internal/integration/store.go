@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// SecretBackendSetting the installer setting choosing where the integration
+// secrets are stored, "kubernetes" (default) writes them as Secrets on the
+// installer namespace, "vault" on the HashiCorp Vault configured by the "vault"
+// integration, which itself is always a Kubernetes Secret.
+const SecretBackendSetting = "integrationSecretBackend"
+
+// Integration secret backends, see SecretBackendSetting.
+const (
+	SecretBackendKubernetes = "kubernetes"
+	SecretBackendVault      = "vault"
+)
+
+// SecretStore stores the integration secrets, the Secret object is the payload
+// exchanged regardless of the backend.
+type SecretStore interface {
+	// Get returns the secret, a Kubernetes "not found" error when missing.
+	Get(context.Context, types.NamespacedName) (*corev1.Secret, error)
+	// Create stores the secret, it must not exist.
+	Create(context.Context, *corev1.Secret) error
+	// Delete removes the secret.
+	Delete(context.Context, types.NamespacedName) error
+	// Replicated returns whether the secret lives on the cluster, thus it can
+	// be replicated to other namespaces.
+	Replicated() bool
+}
+
+// kubeStore stores the integration secrets as Kubernetes Secrets.
+type kubeStore struct {
+	kube k8s.Interface // kubernetes client
+}
+
+var _ SecretStore = &kubeStore{}
+
+// Get returns the Kubernetes Secret.
+func (k *kubeStore) Get(
+	ctx context.Context,
+	name types.NamespacedName,
+) (*corev1.Secret, error) {
+	return k8s.GetSecret(ctx, k.kube, name)
+}
+
+// Create creates the Kubernetes Secret, and waits for it to be readable, so the
+// steps depending on it don't race its creation.
+func (k *kubeStore) Create(ctx context.Context, secret *corev1.Secret) error {
+	coreClient, err := k.kube.CoreV1ClientSet(secret.GetNamespace())
+	if err != nil {
+		return err
+	}
+	_, err = coreClient.Secrets(secret.GetNamespace()).
+		Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return err
+	}
+	_, err = k8s.WaitForSecret(ctx, coreClient, types.NamespacedName{
+		Namespace: secret.GetNamespace(),
+		Name:      secret.GetName(),
+	}, nil)
+	return err
+}
+
+// Delete deletes the Kubernetes Secret.
+func (k *kubeStore) Delete(ctx context.Context, name types.NamespacedName) error {
+	return k8s.DeleteSecret(ctx, k.kube, name)
+}
+
+// Replicated Kubernetes Secrets are replicated.
+func (k *kubeStore) Replicated() bool {
+	return true
+}
+
+// SecretBackend returns the integration secret backend on the configuration,
+// "kubernetes" when the setting is absent.
+func SecretBackend(cfg *config.Config) (string, error) {
+	if cfg == nil {
+		return SecretBackendKubernetes, nil
+	}
+	setting, ok := cfg.Installer.Settings[SecretBackendSetting]
+	if !ok || setting == nil {
+		return SecretBackendKubernetes, nil
+	}
+	switch backend := fmt.Sprint(setting); backend {
+	case SecretBackendKubernetes, SecretBackendVault:
+		return backend, nil
+	default:
+		return "", fmt.Errorf("%w: setting %q: unknown backend %q, "+
+			"expecting %q or %q", config.ErrInvalidConfig,
+			SecretBackendSetting, backend,
+			SecretBackendKubernetes, SecretBackendVault)
+	}
+}
+
+// store returns the secret store of the integration, after the configured
+// backend. The Vault backend reads its coordinates from the "vault" integration
+// secret, which is stored on the cluster.
+func (i *Integration) store(
+	ctx context.Context,
+	cfg *config.Config,
+) (SecretStore, error) {
+	kube := &kubeStore{kube: i.kube}
+	backend, err := SecretBackend(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if backend == SecretBackendKubernetes || i.vaultSecret == i.name {
+		return kube, nil
+	}
+	if i.vaultSecret == "" {
+		return nil, fmt.Errorf("%w: setting %q is %q, but the application "+
+			"doesn't register the vault integration", ErrVaultNotConfigured,
+			SecretBackendSetting, SecretBackendVault)
+	}
+	secret, err := kube.Get(ctx, types.NamespacedName{
+		Namespace: cfg.Namespace(),
+		Name:      i.vaultSecret,
+	})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: setting %q is %q, but the vault "+
+			"integration is not configured", ErrVaultNotConfigured,
+			SecretBackendSetting, SecretBackendVault)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewVaultStore(secret)
+}
+
+// SetVaultSecret sets the name of the "vault" integration secret, holding the
+// Vault coordinates used when it's the configured backend.
+func (i *Integration) SetVaultSecret(name string) {
+	i.vaultSecret = name
+}
@@ -21,6 +21,12 @@ type TrustificationAuth struct {
 }
 
 var _ Interface = &TrustificationAuth{}
+var _ Credential = &TrustificationAuth{}
+
+// CredentialFlag the OIDC client secret can be informed via STDIN or the keychain.
+func (t *TrustificationAuth) CredentialFlag() string {
+	return "oidc-client-secret"
+}
 
 // PersistentFlags adds the persistent flags to the informed Cobra command.
 func (t *TrustificationAuth) PersistentFlags(c *cobra.Command) {
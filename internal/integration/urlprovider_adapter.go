@@ -21,8 +21,9 @@ type urlProviderAdapter struct {
 
 // Ensure urlProviderAdapter implements both interfaces at compile time.
 var (
-	_ integrations.IntegrationContext = (*urlProviderAdapter)(nil)
-	_ URLProvider                     = (*urlProviderAdapter)(nil)
+	_ integrations.IntegrationContext    = (*urlProviderAdapter)(nil)
+	_ integrations.ExternalDomainContext = (*urlProviderAdapter)(nil)
+	_ URLProvider                        = (*urlProviderAdapter)(nil)
 )
 
 // GetOpenShiftIngressDomain implements integrations.IntegrationContext.
@@ -30,6 +31,19 @@ func (a *urlProviderAdapter) GetOpenShiftIngressDomain(ctx context.Context) (str
 	return k8s.GetOpenShiftIngressDomain(ctx, a.runCtx.Kube)
 }
 
+// GetExternalDomain implements integrations.ExternalDomainContext, the domain on
+// the external URLs setting takes precedence over the ingress domain.
+func (a *urlProviderAdapter) GetExternalDomain(ctx context.Context) (string, error) {
+	external, err := GetExternalURLs(a.cfg)
+	if err != nil {
+		return "", err
+	}
+	if external.Domain != "" {
+		return external.Domain, nil
+	}
+	return a.GetOpenShiftIngressDomain(ctx)
+}
+
 // GetProductNamespace implements integrations.IntegrationContext.
 func (a *urlProviderAdapter) GetProductNamespace(productName string) (string, error) {
 	product, err := a.cfg.GetProduct(productName)
@@ -157,3 +157,61 @@ func Test_urlProviderAdapter_GetOpenShiftIngressDomain(t *testing.T) {
 	// ErrIngressDomainNotFound or a connection/API error depending on environment.
 	// Asserting err != nil is enough to confirm the IntegrationContext path is used.
 }
+
+func Test_urlProviderAdapter_GetExternalDomain(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	cfg, err := config.NewConfigFromBytes([]byte(`
+helmet_ex:
+  settings:
+    externalURLs:
+      domain: apps.proxy.example.com
+  products: []
+`), "installer-ns", "helmet_ex")
+	if err != nil {
+		t.Fatalf("build config: %v", err)
+	}
+
+	adapter := newURLProviderAdapter(&mockPublicURLProvider{}, nil, cfg)
+
+	got, err := adapter.GetExternalDomain(ctx)
+	if err != nil {
+		t.Fatalf("GetExternalDomain: %v", err)
+	}
+	if got != "apps.proxy.example.com" {
+		t.Errorf("GetExternalDomain: got %q, want %q", got, "apps.proxy.example.com")
+	}
+
+	got, err = integrations.ExternalDomain(ctx, adapter)
+	if err != nil {
+		t.Fatalf("ExternalDomain: %v", err)
+	}
+	if got != "apps.proxy.example.com" {
+		t.Errorf("ExternalDomain: got %q, want %q", got, "apps.proxy.example.com")
+	}
+}
+
+// ingressOnlyContext an IntegrationContext without the external domain, as
+// implemented before ExternalDomainContext.
+type ingressOnlyContext struct{}
+
+func (ingressOnlyContext) GetOpenShiftIngressDomain(context.Context) (string, error) {
+	return "apps.example.com", nil
+}
+
+func (ingressOnlyContext) GetProductNamespace(string) (string, error) {
+	return "", nil
+}
+
+func Test_ExternalDomain_fallback(t *testing.T) {
+	t.Parallel()
+
+	got, err := integrations.ExternalDomain(context.Background(), ingressOnlyContext{})
+	if err != nil {
+		t.Fatalf("ExternalDomain: %v", err)
+	}
+	if got != "apps.example.com" {
+		t.Errorf("ExternalDomain: got %q, want %q", got, "apps.example.com")
+	}
+}
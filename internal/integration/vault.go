@@ -0,0 +1,308 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ErrVaultNotConfigured the Vault backend is chosen, but its integration secret
+// is missing.
+var ErrVaultNotConfigured = helmeterrors.New(helmeterrors.ErrPrerequisitesMissing,
+	"vault is not configured")
+
+// ErrVaultRequest the Vault API refused, or failed, the request.
+var ErrVaultRequest = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"vault request failed")
+
+// DefaultVaultMount the default KV version 2 secrets engine mount.
+const DefaultVaultMount = "secret"
+
+// Vault represents the HashiCorp Vault integration coordinates, the KV version
+// 2 secrets engine holding the secrets.
+type Vault struct {
+	url       string // Vault API address
+	token     string // Vault token
+	namespace string // Vault Enterprise namespace
+	mount     string // KV version 2 mount path
+}
+
+var _ Interface = &Vault{}
+var _ Credential = &Vault{}
+
+// CredentialFlag the Vault token can be informed via STDIN or the keychain.
+func (v *Vault) CredentialFlag() string {
+	return "token"
+}
+
+// PersistentFlags adds the persistent flags to the informed Cobra command.
+func (v *Vault) PersistentFlags(c *cobra.Command) {
+	p := c.PersistentFlags()
+
+	p.StringVar(&v.url, "url", v.url,
+		"Vault API address, e.g. https://vault.example.com:8200")
+	p.StringVar(&v.token, "token", v.token,
+		"Vault token, with read and write access to the mount")
+	p.StringVar(&v.namespace, "vault-namespace", v.namespace,
+		"Vault Enterprise namespace")
+	p.StringVar(&v.mount, "mount", v.mount,
+		"KV version 2 secrets engine mount path")
+
+	for _, f := range []string{"token", "url"} {
+		if err := c.MarkPersistentFlagRequired(f); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// SetArgument sets additional arguments to the integration.
+func (v *Vault) SetArgument(string, string) error {
+	return nil
+}
+
+// LoggerWith decorates the logger with the integration flags.
+func (v *Vault) LoggerWith(logger *slog.Logger) *slog.Logger {
+	return logger.With(
+		"url", v.url,
+		"vault-namespace", v.namespace,
+		"mount", v.mount,
+		"token-len", len(v.token),
+	)
+}
+
+// Type returns the type of the integration.
+func (v *Vault) Type() corev1.SecretType {
+	return corev1.SecretTypeOpaque
+}
+
+// Validate validates the integration configuration.
+func (v *Vault) Validate() error {
+	if strings.Trim(v.mount, "/") == "" {
+		return fmt.Errorf("the KV mount path must not be empty")
+	}
+	return ValidateURL(v.url)
+}
+
+// Data returns the integration data for Vault.
+func (v *Vault) Data(
+	_ context.Context,
+	_ *runcontext.RunContext,
+	_ *config.Config,
+) (map[string][]byte, error) {
+	return map[string][]byte{
+		"url":       []byte(v.url),
+		"token":     []byte(v.token),
+		"namespace": []byte(v.namespace),
+		"mount":     []byte(strings.Trim(v.mount, "/")),
+	}, nil
+}
+
+// NewVault instantiates a new Vault integration.
+func NewVault() *Vault {
+	return &Vault{mount: DefaultVaultMount}
+}
+
+// Vault KV custom metadata keys carrying the Secret attributes stored beside the
+// data, the type and the annotations.
+const (
+	vaultTypeKey             = "type"
+	vaultAnnotationKeyPrefix = "annotation."
+)
+
+// vaultStore stores the integration secrets on the Vault KV version 2 secrets
+// engine, at "<mount>/<namespace>/<name>". The Secret data are the KV data, the
+// type and annotations are kept on the custom metadata.
+type vaultStore struct {
+	client    *http.Client // http client
+	url       string       // Vault API address
+	token     string       // Vault token
+	namespace string       // Vault Enterprise namespace
+	mount     string       // KV version 2 mount path
+}
+
+var _ SecretStore = &vaultStore{}
+
+// endpoint returns the KV API address for the secret, on the "data" or
+// "metadata" endpoints.
+func (v *vaultStore) endpoint(kind string, name types.NamespacedName) string {
+	return strings.TrimSuffix(v.url, "/") + "/v1/" +
+		path.Join(v.mount, kind, url.PathEscape(name.Namespace),
+			url.PathEscape(name.Name))
+}
+
+// do performs the Vault API request, decoding the response data into out, when
+// informed. Returns a Kubernetes "not found" error for missing secrets.
+func (v *vaultStore) do(
+	ctx context.Context,
+	method, endpoint string,
+	name types.NamespacedName,
+	body, out any,
+) error {
+	var payload io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrVaultRequest, err)
+	}
+	defer res.Body.Close()
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return apierrors.NewNotFound(
+			schema.GroupResource{Resource: "secrets"}, name.String())
+	case res.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%w: %s %s: %s: %s", ErrVaultRequest, method,
+			endpoint, res.Status, strings.TrimSpace(string(msg)))
+	case out == nil:
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// Get reads the secret data and custom metadata.
+func (v *vaultStore) Get(
+	ctx context.Context,
+	name types.NamespacedName,
+) (*corev1.Secret, error) {
+	var data struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := v.do(ctx, http.MethodGet, v.endpoint("data", name), name,
+		nil, &data); err != nil {
+		return nil, err
+	}
+	// Deleted versions are answered without data.
+	if data.Data.Data == nil {
+		return nil, apierrors.NewNotFound(
+			schema.GroupResource{Resource: "secrets"}, name.String())
+	}
+	var metadata struct {
+		Data struct {
+			CustomMetadata map[string]string `json:"custom_metadata"`
+		} `json:"data"`
+	}
+	if err := v.do(ctx, http.MethodGet, v.endpoint("metadata", name), name,
+		nil, &metadata); err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: name.Namespace,
+			Name:      name.Name,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{},
+	}
+	for k, value := range data.Data.Data {
+		secret.Data[k] = []byte(value)
+	}
+	for k, value := range metadata.Data.CustomMetadata {
+		switch {
+		case k == vaultTypeKey:
+			secret.Type = corev1.SecretType(value)
+		case strings.HasPrefix(k, vaultAnnotationKeyPrefix):
+			if secret.Annotations == nil {
+				secret.Annotations = map[string]string{}
+			}
+			secret.Annotations[strings.TrimPrefix(
+				k, vaultAnnotationKeyPrefix)] = value
+		}
+	}
+	return secret, nil
+}
+
+// Create writes the secret data, and its type and annotations on the custom
+// metadata.
+func (v *vaultStore) Create(ctx context.Context, secret *corev1.Secret) error {
+	name := types.NamespacedName{
+		Namespace: secret.GetNamespace(),
+		Name:      secret.GetName(),
+	}
+	data := map[string]string{}
+	for k, value := range secret.Data {
+		data[k] = string(value)
+	}
+	if err := v.do(ctx, http.MethodPost, v.endpoint("data", name), name,
+		map[string]any{"data": data}, nil); err != nil {
+		return err
+	}
+	metadata := map[string]string{vaultTypeKey: string(secret.Type)}
+	for k, value := range secret.GetAnnotations() {
+		metadata[vaultAnnotationKeyPrefix+k] = value
+	}
+	return v.do(ctx, http.MethodPost, v.endpoint("metadata", name), name,
+		map[string]any{"custom_metadata": metadata}, nil)
+}
+
+// Delete removes all the versions of the secret.
+func (v *vaultStore) Delete(ctx context.Context, name types.NamespacedName) error {
+	return v.do(ctx, http.MethodDelete, v.endpoint("metadata", name), name,
+		nil, nil)
+}
+
+// Replicated Vault secrets are not on the cluster, thus not replicated.
+func (v *vaultStore) Replicated() bool {
+	return false
+}
+
+// NewVaultStore instantiates the Vault secret store after the "vault"
+// integration secret data.
+func NewVaultStore(secret *corev1.Secret) (SecretStore, error) {
+	v := &vaultStore{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		url:       string(secret.Data["url"]),
+		token:     string(secret.Data["token"]),
+		namespace: string(secret.Data["namespace"]),
+		mount:     string(secret.Data["mount"]),
+	}
+	if v.mount == "" {
+		v.mount = DefaultVaultMount
+	}
+	if err := ValidateURL(v.url); err != nil {
+		return nil, fmt.Errorf("secret %s/%s: %w",
+			secret.GetNamespace(), secret.GetName(), err)
+	}
+	if v.token == "" {
+		return nil, fmt.Errorf("%w: secret %s/%s: token is empty",
+			ErrVaultNotConfigured, secret.GetNamespace(), secret.GetName())
+	}
+	return v, nil
+}
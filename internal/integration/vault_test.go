@@ -0,0 +1,173 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeVault a minimal Vault KV version 2 API, on the "secret" mount.
+type fakeVault struct {
+	mu       sync.Mutex
+	data     map[string]map[string]string
+	metadata map[string]map[string]string
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("X-Vault-Token") != "s3cr3t" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	kind, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v1/secret/"), "/")
+	store := f.data
+	field := "data"
+	if kind == "metadata" {
+		store, field = f.metadata, "custom_metadata"
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if _, ok := f.data[key]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{field: store[key]},
+		})
+	case http.MethodPost:
+		body := map[string]map[string]string{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		store[key] = body[field]
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		delete(f.data, key)
+		delete(f.metadata, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestVaultBackend(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+	vault := &fakeVault{
+		data:     map[string]map[string]string{},
+		metadata: map[string]map[string]string{},
+	}
+	server := httptest.NewServer(vault)
+	defer server.Close()
+
+	cfg, err := config.NewConfigFromFile(
+		chartfs.New(os.DirFS("../../test")),
+		"config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+	kube := k8s.NewFakeKube(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cfg.Namespace(),
+			Name:      "helmet-ex-vault-integration",
+		},
+		Data: map[string][]byte{
+			"url":   []byte(server.URL),
+			"token": []byte("s3cr3t"),
+			"mount": []byte("secret"),
+		},
+	})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	newJenkins := func() *Integration {
+		j := NewJenkins()
+		j.url, j.username, j.token = "https://jenkins.example.com", "admin", "t0k3n"
+		i := NewSecret(logger, kube, "helmet-ex-jenkins-integration", j)
+		i.SetVaultSecret("helmet-ex-vault-integration")
+		return i
+	}
+
+	t.Run("SecretBackend", func(t *testing.T) {
+		g := o.NewWithT(t)
+		backend, err := SecretBackend(cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(backend).To(o.Equal(SecretBackendKubernetes))
+
+		settings, err := cfg.DeepCopy()
+		g.Expect(err).To(o.Succeed())
+		settings.Installer.Settings[SecretBackendSetting] = "etcd"
+		_, err = SecretBackend(settings)
+		g.Expect(err).To(o.MatchError(config.ErrInvalidConfig))
+	})
+
+	cfg.Installer.Settings[SecretBackendSetting] = SecretBackendVault
+
+	t.Run("Create", func(t *testing.T) {
+		g := o.NewWithT(t)
+		i := newJenkins()
+		exists, err := i.Exists(ctx, cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(exists).To(o.BeFalse())
+
+		i.expires = "2026-03-01"
+		g.Expect(i.Create(ctx, nil, cfg)).To(o.Succeed())
+		key := cfg.Namespace() + "/helmet-ex-jenkins-integration"
+		g.Expect(vault.data[key]).To(o.HaveKeyWithValue("token", "t0k3n"))
+		g.Expect(vault.metadata[key]).To(o.HaveKeyWithValue(
+			"annotation."+annotations.ExpiresAt, "2026-03-01T00:00:00Z"))
+
+		// Stored on Vault, not on the cluster.
+		exists, err = k8s.SecretExists(ctx, kube, i.secretName(cfg))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(exists).To(o.BeFalse())
+		exists, err = i.Exists(ctx, cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(exists).To(o.BeTrue())
+		expiresAt, err := i.Expiry(ctx, cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(expiresAt).ToNot(o.BeNil())
+
+		g.Expect(newJenkins().Create(ctx, nil, cfg)).
+			To(o.MatchError(ErrSecretAlreadyExists))
+		i.force = true
+		g.Expect(i.Create(ctx, nil, cfg)).To(o.Succeed())
+
+		g.Expect(i.Delete(ctx, cfg)).To(o.Succeed())
+		g.Expect(vault.data).ToNot(o.HaveKey(key))
+	})
+
+	t.Run("Replication", func(t *testing.T) {
+		g := o.NewWithT(t)
+		i := newJenkins()
+		i.replicateTo = []string{"product-a"}
+		g.Expect(i.Create(ctx, nil, cfg)).ToNot(o.Succeed())
+	})
+
+	t.Run("Not configured", func(t *testing.T) {
+		g := o.NewWithT(t)
+		i := NewSecret(logger, k8s.NewFakeKube(),
+			"helmet-ex-jenkins-integration", NewJenkins())
+		_, err := i.Exists(ctx, cfg)
+		g.Expect(err).To(o.MatchError(ErrVaultNotConfigured))
+		i.SetVaultSecret("helmet-ex-vault-integration")
+		_, err = i.Exists(ctx, cfg)
+		g.Expect(err).To(o.MatchError(ErrVaultNotConfigured))
+
+		// The vault integration itself is always stored on the cluster.
+		v := NewSecret(logger, kube, "helmet-ex-vault-integration", NewVault())
+		v.SetVaultSecret("helmet-ex-vault-integration")
+		exists, err := v.Exists(ctx, cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(exists).To(o.BeTrue())
+	})
+}
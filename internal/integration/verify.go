@@ -0,0 +1,57 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+)
+
+// Verifier is implemented by integrations able to check the stored secret
+// against the provider API, live, so a secret stored with a mistyped or
+// revoked credential isn't reported as configured only.
+type Verifier interface {
+	// Verify checks the integration secret data connects and authenticates on
+	// the provider.
+	Verify(ctx context.Context, data map[string][]byte) error
+}
+
+var (
+	// ErrVerificationUnsupported the integration can't be verified live.
+	ErrVerificationUnsupported = helmeterrors.New(
+		helmeterrors.ErrInvalidUsage, "verification not supported")
+	// ErrNotConfigured the integration secret doesn't exist.
+	ErrNotConfigured = helmeterrors.New(
+		helmeterrors.ErrPrerequisitesMissing, "integration not configured")
+	// ErrVerificationFailed the provider refused the integration secret, or
+	// isn't reachable.
+	ErrVerificationFailed = helmeterrors.New(
+		helmeterrors.ErrInvalidIntegration, "integration verification failed")
+)
+
+// Verify reads the integration secret and verifies it against the provider,
+// see Verifier. Unsupported verifications are ErrVerificationUnsupported, the
+// missing secret ErrNotConfigured.
+func (i *Integration) Verify(ctx context.Context, cfg *config.Config) error {
+	v, ok := i.data.(Verifier)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrVerificationUnsupported, i.name)
+	}
+	secret, err := i.Secret(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		return fmt.Errorf("%w: %s", ErrNotConfigured, i.secretName(cfg))
+	}
+	i.logger.Debug("Verifying the integration secret", "secret-name", i.name)
+	if err = v.Verify(ctx, secret.Data); err != nil {
+		if errors.Is(err, ErrVerificationUnsupported) {
+			return err
+		}
+		return fmt.Errorf("%w: %s: %w", ErrVerificationFailed, i.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIntegrationVerify(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+	cfg, err := config.NewConfigFromFile(
+		chartfs.New(os.DirFS("../../test")),
+		"config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+
+	api := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer valid" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"xray_version": "3.111.9"}`))
+		},
+	))
+	defer api.Close()
+
+	secret := func(name, token string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cfg.Namespace(),
+				Name:      name,
+			},
+			Data: map[string][]byte{
+				XrayURLKey:   []byte(api.URL),
+				XrayTokenKey: []byte(token),
+			},
+		}
+	}
+	kube := k8s.NewFakeKube(secret("valid", "valid"), secret("revoked", "typo"))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	verify := func(name string, data Interface) error {
+		return NewSecret(logger, kube, name, data).Verify(ctx, cfg)
+	}
+	xray := func() *Xray {
+		x := NewXray()
+		x.client = api.Client()
+		return x
+	}
+
+	g.Expect(verify("valid", xray())).To(o.Succeed())
+
+	err = verify("revoked", xray())
+	g.Expect(err).To(o.MatchError(ErrVerificationFailed))
+	g.Expect(err).To(o.MatchError(ErrXrayRequest))
+
+	g.Expect(verify("missing", xray())).To(o.MatchError(ErrNotConfigured))
+
+	g.Expect(verify("valid", NewNotification())).
+		To(o.MatchError(ErrVerificationUnsupported))
+
+	// Verifiers are capable of verification, listed once.
+	g.Expect(NewSecret(logger, kube, "acr", NewACR()).Capabilities()).
+		To(o.ConsistOf(CapabilityVerification))
+	g.Expect(NewSecret(logger, kube, "xray", xray()).Capabilities()).
+		To(o.ConsistOf(CapabilityVerification))
+	g.Expect(NewSecret(logger, kube, "azure", NewAzure()).Capabilities()).
+		To(o.BeEmpty())
+}
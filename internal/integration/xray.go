@@ -0,0 +1,148 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ErrXrayRequest the JFrog Xray REST API refused or failed the request.
+var ErrXrayRequest = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"xray request failed")
+
+// Xray integration secret keys.
+const (
+	// XrayURLKey the Xray REST API base URL.
+	XrayURLKey = "url"
+	// XrayTokenKey the JFrog access token.
+	XrayTokenKey = "token"
+)
+
+// Xray represents the JFrog Xray coordinates of the artifact scanning products.
+// It complements the "artifactory" integration, the artifact storage, so the
+// products requiring the scanning are told apart on the CEL expressions.
+type Xray struct {
+	url   string // Xray REST API base URL
+	token string // JFrog access token
+
+	client *http.Client // REST API http client
+}
+
+var _ Interface = &Xray{}
+var _ Credential = &Xray{}
+var _ Capable = &Xray{}
+var _ Verifier = &Xray{}
+
+// CredentialFlag the access token can be informed via STDIN or the keychain.
+func (x *Xray) CredentialFlag() string {
+	return "token"
+}
+
+// Capabilities the access token is verified before the secret is stored.
+func (x *Xray) Capabilities() []Capability {
+	return []Capability{CapabilityVerification}
+}
+
+// PersistentFlags adds the persistent flags to the informed Cobra command.
+func (x *Xray) PersistentFlags(c *cobra.Command) {
+	p := c.PersistentFlags()
+
+	p.StringVar(&x.url, "url", x.url,
+		"Xray URL, e.g. https://example.jfrog.io/xray")
+	p.StringVar(&x.token, "token", x.token,
+		"JFrog access token, allowed to read the Xray data")
+
+	for _, f := range []string{"url", "token"} {
+		if err := c.MarkPersistentFlagRequired(f); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// SetArgument sets additional arguments to the integration.
+func (x *Xray) SetArgument(string, string) error {
+	return nil
+}
+
+// LoggerWith decorates the logger with the integration flags.
+func (x *Xray) LoggerWith(logger *slog.Logger) *slog.Logger {
+	return logger.With("url", x.url, "token-len", len(x.token))
+}
+
+// Validate validates the integration configuration.
+func (x *Xray) Validate() error {
+	if x.url == "" {
+		return fmt.Errorf("url is required")
+	}
+	if err := ValidateURL(x.url); err != nil {
+		return fmt.Errorf("%w: %q", err, x.url)
+	}
+	if x.token == "" {
+		return fmt.Errorf("token is required")
+	}
+	x.url = strings.TrimSuffix(x.url, "/")
+	return nil
+}
+
+// Type returns the type of the integration.
+func (x *Xray) Type() corev1.SecretType {
+	return corev1.SecretTypeOpaque
+}
+
+// verifyToken asserts the access token authenticates on Xray, reading its
+// version, which requires an authenticated user.
+func (x *Xray) verifyToken(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, x.url+"/api/v1/system/version", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+x.token)
+	res, err := x.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: xray unreachable: %w", ErrXrayRequest, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s %s: %s", ErrXrayRequest,
+			req.Method, req.URL.Redacted(), res.Status)
+	}
+	return nil
+}
+
+// Data returns the Xray integration data, once the access token is verified.
+func (x *Xray) Data(
+	ctx context.Context,
+	_ *runcontext.RunContext,
+	_ *config.Config,
+) (map[string][]byte, error) {
+	if err := x.verifyToken(ctx); err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		XrayURLKey:   []byte(x.url),
+		XrayTokenKey: []byte(x.token),
+	}, nil
+}
+
+// Verify asserts the stored access token authenticates on Xray.
+func (x *Xray) Verify(ctx context.Context, data map[string][]byte) error {
+	x.url = string(data[XrayURLKey])
+	x.token = string(data[XrayTokenKey])
+	return x.verifyToken(ctx)
+}
+
+// NewXray instantiates a new JFrog Xray integration.
+func NewXray() *Xray {
+	return &Xray{client: &http.Client{Timeout: 30 * time.Second}}
+}
@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestXray(t *testing.T) {
+	ctx := context.Background()
+	api := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/xray/api/v1/system/version" ||
+				r.Header.Get("Authorization") != "Bearer valid" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"xray_version": "3.111.9"}`))
+		},
+	))
+	defer api.Close()
+
+	newXray := func() *Xray {
+		x := NewXray()
+		x.url = api.URL + "/xray/"
+		x.token = "valid"
+		x.client = api.Client()
+		return x
+	}
+
+	t.Run("Validate", func(t *testing.T) {
+		g := o.NewWithT(t)
+		x := newXray()
+		x.url = "example.jfrog.io/xray"
+		g.Expect(x.Validate()).To(o.MatchError(ErrInvalidURL))
+
+		x = newXray()
+		x.token = ""
+		g.Expect(x.Validate()).To(o.MatchError(o.ContainSubstring("required")))
+
+		x = newXray()
+		g.Expect(x.Validate()).To(o.Succeed())
+		g.Expect(x.url).To(o.Equal(api.URL + "/xray"))
+	})
+
+	t.Run("Data", func(t *testing.T) {
+		g := o.NewWithT(t)
+		x := newXray()
+		g.Expect(x.Validate()).To(o.Succeed())
+
+		data, err := x.Data(ctx, nil, nil)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(string(data[XrayURLKey])).To(o.Equal(api.URL + "/xray"))
+		g.Expect(string(data[XrayTokenKey])).To(o.Equal("valid"))
+
+		x.token = "invalid"
+		_, err = x.Data(ctx, nil, nil)
+		g.Expect(err).To(o.MatchError(ErrXrayRequest))
+	})
+}
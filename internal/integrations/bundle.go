@@ -0,0 +1,201 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrInvalidBundle the integrations bundle can't be imported.
+var ErrInvalidBundle = helmeterrors.New(helmeterrors.ErrInvalidIntegration,
+	"invalid integrations bundle")
+
+// bundleAnnotations the integration secret annotations carried by the bundle,
+// the others belong to the source cluster.
+var bundleAnnotations = []string{
+	annotations.ReplicateTo,
+	annotations.ExpiresAt,
+}
+
+// BundleEntry an integration secret on the bundle.
+type BundleEntry struct {
+	// Name the integration name.
+	Name string `json:"name"`
+	// Type the integration secret type.
+	Type corev1.SecretType `json:"type,omitempty"`
+	// Annotations the integration secret annotations, see bundleAnnotations.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Data the integration secret payload.
+	Data map[string][]byte `json:"data"`
+}
+
+// Secret returns the integration secret stored on the entry.
+func (e *BundleEntry) Secret() *corev1.Secret {
+	secret := &corev1.Secret{Type: e.Type, Data: e.Data}
+	if len(e.Annotations) > 0 {
+		secret.SetAnnotations(maps.Clone(e.Annotations))
+	}
+	return secret
+}
+
+// Bundle the integration secrets exported from an installation, to import them
+// on another, for instance promoting the staging integrations to production.
+type Bundle struct {
+	// CreatedAt when the bundle was exported.
+	CreatedAt time.Time `json:"createdAt"`
+	// Namespace the installer namespace the bundle was exported from.
+	Namespace string `json:"namespace"`
+	// Integrations the integration secrets, sorted by name.
+	Integrations []BundleEntry `json:"integrations"`
+}
+
+// Names returns the integration names on the bundle.
+func (b *Bundle) Names() []string {
+	names := make([]string, 0, len(b.Integrations))
+	for _, e := range b.Integrations {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+// Marshal returns the bundle as YAML.
+func (b *Bundle) Marshal() ([]byte, error) {
+	return yaml.Marshal(b)
+}
+
+// LoadBundle reads the bundle YAML, decrypted.
+func LoadBundle(payload []byte) (*Bundle, error) {
+	b := &Bundle{}
+	if err := yaml.Unmarshal(payload, b); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+	return b, nil
+}
+
+// Export copies the configured integration secrets into a bundle.
+func (m *Manager) Export(
+	ctx context.Context,
+	cfg *config.Config,
+) (*Bundle, error) {
+	b := &Bundle{
+		CreatedAt:    time.Now().UTC(),
+		Namespace:    cfg.Namespace(),
+		Integrations: []BundleEntry{},
+	}
+	names := m.IntegrationNames()
+	slices.Sort(names)
+	for _, name := range names {
+		secret, err := m.integrations[IntegrationName(name)].Secret(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("integration %q: %w", name, err)
+		}
+		if secret == nil {
+			continue
+		}
+		e := BundleEntry{Name: name, Type: secret.Type, Data: secret.Data}
+		for _, k := range bundleAnnotations {
+			if v, ok := secret.GetAnnotations()[k]; ok {
+				if e.Annotations == nil {
+					e.Annotations = map[string]string{}
+				}
+				e.Annotations[k] = v
+			}
+		}
+		b.Integrations = append(b.Integrations, e)
+	}
+	return b, nil
+}
+
+// importOrder returns the bundle entries in import order, the "vault"
+// integration first, other secrets may be stored on it.
+func (b *Bundle) importOrder() []BundleEntry {
+	entries := slices.Clone(b.Integrations)
+	slices.SortStableFunc(entries, func(x, y BundleEntry) int {
+		switch {
+		case x.Name == string(Vault) && y.Name != string(Vault):
+			return -1
+		case y.Name == string(Vault) && x.Name != string(Vault):
+			return 1
+		default:
+			return strings.Compare(x.Name, y.Name)
+		}
+	})
+	return entries
+}
+
+// Import creates the integration secrets stored on the bundle, returning the
+// integration names imported. The bundle is validated first: every integration
+// must be registered by the application, and, unless force is enabled, none may
+// be configured already, so a failed import doesn't leave a partial set behind.
+func (m *Manager) Import(
+	ctx context.Context,
+	cfg *config.Config,
+	b *Bundle,
+	force bool,
+) ([]string, error) {
+	supported := m.IntegrationNames()
+	slices.Sort(supported)
+	seen := map[string]bool{}
+	for _, e := range b.Integrations {
+		if !slices.Contains(supported, e.Name) {
+			return nil, fmt.Errorf("%w: integration %q is not supported, "+
+				"expecting one of: %s", ErrInvalidBundle, e.Name,
+				strings.Join(supported, ", "))
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("%w: integration %q is duplicated",
+				ErrInvalidBundle, e.Name)
+		}
+		seen[e.Name] = true
+		if len(e.Data) == 0 {
+			return nil, fmt.Errorf("%w: integration %q has no data",
+				ErrInvalidBundle, e.Name)
+		}
+	}
+	if !force {
+		conflicts := []string{}
+		for _, name := range b.Names() {
+			exists, err := m.integrations[IntegrationName(name)].Exists(ctx, cfg)
+			// Without Vault configured yet, nothing is stored on it, the
+			// bundle may bring the "vault" integration itself.
+			if errors.Is(err, integration.ErrVaultNotConfigured) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("integration %q: %w", name, err)
+			}
+			if exists {
+				conflicts = append(conflicts, name)
+			}
+		}
+		if len(conflicts) > 0 {
+			slices.Sort(conflicts)
+			return nil, fmt.Errorf(
+				"%w: integrations already configured, use --force to replace "+
+					"them: %s", helmeterrors.ErrConflict,
+				strings.Join(conflicts, ", "))
+		}
+	}
+
+	imported := []string{}
+	for _, e := range b.importOrder() {
+		i := m.integrations[IntegrationName(e.Name)]
+		if err := i.Restore(ctx, cfg, e.Secret(), force); err != nil {
+			return imported, fmt.Errorf("integration %q: %w", e.Name, err)
+		}
+		imported = append(imported, e.Name)
+	}
+	return imported, nil
+}
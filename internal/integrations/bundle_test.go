@@ -0,0 +1,216 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// clusterKube a fake cluster keeping the objects created, k8s.FakeKube serves a
+// new clientset on every call.
+type clusterKube struct {
+	*k8s.FakeKube
+	cs kubernetes.Interface
+}
+
+func (c *clusterKube) ClientSet(string) (kubernetes.Interface, error) {
+	return c.cs, nil
+}
+
+func (c *clusterKube) CoreV1ClientSet(
+	string,
+) (corev1client.CoreV1Interface, error) {
+	return c.cs.CoreV1(), nil
+}
+
+func newClusterKube(objects ...runtime.Object) *clusterKube {
+	return &clusterKube{
+		FakeKube: k8s.NewFakeKube(),
+		cs:       fake.NewSimpleClientset(objects...),
+	}
+}
+
+func TestBundle(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+	cfg, err := config.NewConfigFromFile(
+		chartfs.New(os.DirFS("../../test")),
+		"config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	newManager := func(kube k8s.Interface) *Manager {
+		m := NewManager()
+		for _, name := range []IntegrationName{GitHub, GitLab, Quay} {
+			m.integrations[name] = integration.NewSecret(logger, kube,
+				SecretName("helmet-ex", string(name)),
+				integration.NewGitLab(logger))
+		}
+		return m
+	}
+	secret := func(module, token string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "test-namespace",
+				Name:      SecretName("helmet-ex", module),
+				Annotations: map[string]string{
+					annotations.ExpiresAt:                              "2027-01-02T00:00:00Z",
+					"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{"token": []byte(token)},
+		}
+	}
+
+	staging := newManager(k8s.NewFakeKube(
+		secret("gitlab", "gitlab-token"),
+		secret("github", "github-token"),
+	))
+	bundle, err := staging.Export(ctx, cfg)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(bundle.Names()).To(o.Equal([]string{"github", "gitlab"}))
+	g.Expect(bundle.Integrations[1].Annotations).To(o.Equal(map[string]string{
+		annotations.ExpiresAt: "2027-01-02T00:00:00Z",
+	}))
+
+	payload, err := bundle.Marshal()
+	g.Expect(err).To(o.Succeed())
+	bundle, err = LoadBundle(payload)
+	g.Expect(err).To(o.Succeed())
+
+	t.Run("Import", func(t *testing.T) {
+		g := o.NewWithT(t)
+		kube := newClusterKube()
+		imported, err := newManager(kube).Import(ctx, cfg, bundle, false)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(imported).To(o.Equal([]string{"github", "gitlab"}))
+
+		s, err := k8s.GetSecret(ctx, kube, types.NamespacedName{
+			Namespace: "test-namespace",
+			Name:      SecretName("helmet-ex", "gitlab"),
+		})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(s.Data).To(o.HaveKeyWithValue("token", []byte("gitlab-token")))
+		g.Expect(s.GetAnnotations()).
+			To(o.HaveKeyWithValue(annotations.ExpiresAt, "2027-01-02T00:00:00Z"))
+	})
+
+	t.Run("Import/conflict", func(t *testing.T) {
+		g := o.NewWithT(t)
+		kube := newClusterKube(secret("gitlab", "production-token"))
+		m := newManager(kube)
+
+		// Nothing is imported while any integration is configured already.
+		_, err := m.Import(ctx, cfg, bundle, false)
+		g.Expect(err).To(o.MatchError(helmeterrors.ErrConflict))
+		g.Expect(err).To(o.MatchError(o.ContainSubstring(": gitlab")))
+		exists, err := m.integrations[GitHub].Exists(ctx, cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(exists).To(o.BeFalse())
+
+		imported, err := m.Import(ctx, cfg, bundle, true)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(imported).To(o.HaveLen(2))
+		s, err := m.integrations[GitLab].Secret(ctx, cfg)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(s.Data).To(o.HaveKeyWithValue("token", []byte("gitlab-token")))
+	})
+
+	t.Run("Import/invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		m := newManager(k8s.NewFakeKube())
+		_, err := m.Import(ctx, cfg, &Bundle{Integrations: []BundleEntry{{
+			Name: "jira",
+			Data: map[string][]byte{"token": []byte("t")},
+		}}}, false)
+		g.Expect(err).To(o.MatchError(ErrInvalidBundle))
+		g.Expect(err).To(o.MatchError(o.ContainSubstring(
+			`integration "jira" is not supported`)))
+
+		_, err = m.Import(ctx, cfg, &Bundle{Integrations: []BundleEntry{{
+			Name: "quay",
+		}}}, false)
+		g.Expect(err).To(o.MatchError(ErrInvalidBundle))
+	})
+
+	t.Run("Encryption", func(t *testing.T) {
+		g := o.NewWithT(t)
+		entity, err := openpgp.NewEntity("operator", "", "ops@example.com",
+			&packet.Config{Algorithm: packet.PubKeyAlgoEd25519})
+		g.Expect(err).To(o.Succeed())
+		armored := func(private bool) *bytes.Buffer {
+			var buf bytes.Buffer
+			blockType := openpgp.PublicKeyType
+			if private {
+				blockType = openpgp.PrivateKeyType
+			}
+			w, err := armor.Encode(&buf, blockType, nil)
+			g.Expect(err).To(o.Succeed())
+			if private {
+				g.Expect(entity.SerializePrivateWithoutSigning(w, nil)).To(o.Succeed())
+			} else {
+				g.Expect(entity.Serialize(w)).To(o.Succeed())
+			}
+			g.Expect(w.Close()).To(o.Succeed())
+			return &buf
+		}
+
+		public := armored(false)
+		encrypted, err := EncryptBundle(payload, public)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(BundleEncrypted(encrypted)).To(o.BeTrue())
+		g.Expect(BundleEncrypted(payload)).To(o.BeFalse())
+		g.Expect(encrypted).NotTo(o.ContainSubstring("github"))
+
+		// The passphrase is requested only for protected keys.
+		noPassphrase := func() ([]byte, error) {
+			t.Fatal("unexpected passphrase request")
+			return nil, nil
+		}
+		decrypted, err := DecryptBundle(encrypted, armored(true), noPassphrase)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(decrypted).To(o.Equal(payload))
+
+		g.Expect(entity.EncryptPrivateKeys([]byte("secret"), nil)).
+			To(o.Succeed())
+		protected := armored(true)
+		_, err = DecryptBundle(encrypted, bytes.NewReader(protected.Bytes()),
+			func() ([]byte, error) { return []byte("typo"), nil })
+		g.Expect(err).To(o.MatchError(ErrBundleKey))
+		decrypted, err = DecryptBundle(encrypted, protected,
+			func() ([]byte, error) { return []byte("secret"), nil })
+		g.Expect(err).To(o.Succeed())
+		g.Expect(decrypted).To(o.Equal(payload))
+
+		// Another key can't decrypt the bundle.
+		other, err := openpgp.NewEntity("other", "", "other@example.com",
+			&packet.Config{Algorithm: packet.PubKeyAlgoEd25519})
+		g.Expect(err).To(o.Succeed())
+		entity = other
+		_, err = DecryptBundle(encrypted, armored(true), noPassphrase)
+		g.Expect(err).To(o.MatchError(ErrBundleKey))
+	})
+}
@@ -0,0 +1,119 @@
+package integrations
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// bundleMessageType the armor type of the encrypted bundles, an OpenPGP message.
+const bundleMessageType = "PGP MESSAGE"
+
+// ErrBundleKey the OpenPGP key informed can't encrypt or decrypt the bundle.
+var ErrBundleKey = helmeterrors.New(helmeterrors.ErrInvalidUsage,
+	"invalid bundle key")
+
+// PassphraseFn returns the passphrase protecting the private key.
+type PassphraseFn func() ([]byte, error)
+
+// BundleEncrypted checks whether the bundle payload is OpenPGP encrypted, armored.
+func BundleEncrypted(payload []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(payload),
+		[]byte("-----BEGIN "+bundleMessageType+"-----"))
+}
+
+// EncryptBundle encrypts the bundle payload for the recipients on the armored
+// OpenPGP public keyrings, for instance "gpg --export --armor <recipient>". The
+// encrypted bundle is armored as well.
+func EncryptBundle(payload []byte, keyrings ...io.Reader) ([]byte, error) {
+	recipients := openpgp.EntityList{}
+	for _, keyring := range keyrings {
+		keys, err := openpgp.ReadArmoredKeyRing(keyring)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrBundleKey, err)
+		}
+		recipients = append(recipients, keys...)
+	}
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("%w: no public keys found", ErrBundleKey)
+	}
+	var buf bytes.Buffer
+	armored, err := armor.Encode(&buf, bundleMessageType, nil)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := openpgp.Encrypt(armored, recipients, nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBundleKey, err)
+	}
+	if _, err = plaintext.Write(payload); err != nil {
+		return nil, err
+	}
+	if err = plaintext.Close(); err != nil {
+		return nil, err
+	}
+	if err = armored.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// locked checks whether the entity private keys are protected by a passphrase.
+func locked(e *openpgp.Entity) bool {
+	if e.PrivateKey != nil && e.PrivateKey.Encrypted {
+		return true
+	}
+	for _, sub := range e.Subkeys {
+		if sub.PrivateKey != nil && sub.PrivateKey.Encrypted {
+			return true
+		}
+	}
+	return false
+}
+
+// DecryptBundle decrypts the armored bundle payload with the armored OpenPGP
+// private keyring, for instance "gpg --export-secret-keys --armor <recipient>".
+// The passphrase is only requested when the private keys are protected.
+func DecryptBundle(
+	payload []byte,
+	keyring io.Reader,
+	passphrase PassphraseFn,
+) ([]byte, error) {
+	keys, err := openpgp.ReadArmoredKeyRing(keyring)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBundleKey, err)
+	}
+	var secret []byte
+	for _, e := range keys {
+		if !locked(e) {
+			continue
+		}
+		if secret == nil {
+			if secret, err = passphrase(); err != nil {
+				return nil, err
+			}
+		}
+		if err = e.DecryptPrivateKeys(secret); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrBundleKey, err)
+		}
+	}
+
+	block, err := armor.Decode(bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBundle, err)
+	}
+	if block.Type != bundleMessageType {
+		return nil, fmt.Errorf("%w: unexpected armor type %q",
+			ErrInvalidBundle, block.Type)
+	}
+	md, err := openpgp.ReadMessage(block.Body, keys, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrBundleKey, err)
+	}
+	return io.ReadAll(md.UnverifiedBody)
+}
@@ -0,0 +1,92 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+)
+
+// ExpiryWarningSetting the installer setting with the window, before the token
+// expiry, in which the integration is reported as expiring, "14d" by default.
+const ExpiryWarningSetting = "tokenExpiryWarning"
+
+// DefaultExpiryWarning the default token expiry warning window.
+const DefaultExpiryWarning = 14 * 24 * time.Hour
+
+// TokenExpiry an integration token expiring within the warning window, or
+// already expired.
+type TokenExpiry struct {
+	Integration string    // integration name
+	ExpiresAt   time.Time // token expiry
+}
+
+// Expired checks whether the token is expired.
+func (t TokenExpiry) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// Describe describes the token expiry relative to now, in a single line.
+func (t TokenExpiry) Describe(now time.Time) string {
+	at := t.ExpiresAt.UTC().Format(time.RFC3339)
+	if t.Expired(now) {
+		return fmt.Sprintf("%q token expired on %s", t.Integration, at)
+	}
+	days := int(t.ExpiresAt.Sub(now).Hours() / 24)
+	return fmt.Sprintf("%q token expires on %s, in %d day(s)",
+		t.Integration, at, days)
+}
+
+// ExpiryWarning returns the token expiry warning window informed on the
+// configuration, or the default.
+func ExpiryWarning(cfg *config.Config) (time.Duration, error) {
+	setting, ok := cfg.Installer.Settings[ExpiryWarningSetting]
+	if !ok || setting == nil {
+		return DefaultExpiryWarning, nil
+	}
+	window, err := integration.ParseDuration(fmt.Sprint(setting))
+	if err != nil || window < 0 {
+		return 0, fmt.Errorf("%w: setting %q: invalid window %q, use \"14d\" or \"336h\"",
+			config.ErrInvalidConfig, ExpiryWarningSetting, setting)
+	}
+	return window, nil
+}
+
+// ExpiringTokens returns the configured integrations which token expires within
+// the warning window, sorted by expiry. Integrations without a recorded expiry
+// are not reported.
+func (m *Manager) ExpiringTokens(
+	ctx context.Context,
+	cfg *config.Config,
+	now time.Time,
+) ([]TokenExpiry, error) {
+	window, err := ExpiryWarning(cfg)
+	if err != nil {
+		return nil, err
+	}
+	expiring := []TokenExpiry{}
+	for name, i := range m.integrations {
+		expiresAt, err := i.Expiry(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		if expiresAt == nil || expiresAt.Sub(now) > window {
+			continue
+		}
+		expiring = append(expiring, TokenExpiry{
+			Integration: string(name),
+			ExpiresAt:   *expiresAt,
+		})
+	}
+	slices.SortFunc(expiring, func(a, b TokenExpiry) int {
+		if c := a.ExpiresAt.Compare(b.ExpiresAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Integration, b.Integration)
+	})
+	return expiring, nil
+}
@@ -0,0 +1,84 @@
+package integrations
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExpiringTokens(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	newConfig := func(t *testing.T, window any) *config.Config {
+		g := o.NewWithT(t)
+		cfg, err := config.NewConfigFromFile(
+			chartfs.New(os.DirFS("../../test")),
+			"config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		if window != nil {
+			cfg.Installer.Settings[ExpiryWarningSetting] = window
+		}
+		return cfg
+	}
+
+	secret := func(module string, expiresAt time.Time) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-namespace",
+			Name:      SecretName("helmet-ex", module),
+			Annotations: map[string]string{
+				annotations.ExpiresAt: expiresAt.Format(time.RFC3339),
+			},
+		}}
+	}
+	kube := k8s.NewFakeKube(
+		secret("github", now.Add(10*24*time.Hour)),
+		secret("gitlab", now.Add(-time.Hour)),
+		secret("quay", now.Add(60*24*time.Hour)),
+	)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	m := NewManager()
+	for _, name := range []IntegrationName{GitHub, GitLab, Quay, Jenkins} {
+		m.integrations[name] = integration.NewSecret(logger, kube,
+			SecretName("helmet-ex", string(name)),
+			integration.NewGitLab(logger))
+	}
+
+	t.Run("default window", func(t *testing.T) {
+		g := o.NewWithT(t)
+		expiring, err := m.ExpiringTokens(ctx, newConfig(t, nil), now)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(expiring).To(o.HaveLen(2))
+		g.Expect(expiring[0].Integration).To(o.Equal("gitlab"))
+		g.Expect(expiring[0].Expired(now)).To(o.BeTrue())
+		g.Expect(expiring[0].Describe(now)).To(o.ContainSubstring("expired on"))
+		g.Expect(expiring[1].Integration).To(o.Equal("github"))
+		g.Expect(expiring[1].Describe(now)).To(o.ContainSubstring("in 10 day(s)"))
+	})
+
+	t.Run("configured window", func(t *testing.T) {
+		g := o.NewWithT(t)
+		expiring, err := m.ExpiringTokens(ctx, newConfig(t, "90d"), now)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(expiring).To(o.HaveLen(3))
+		g.Expect(expiring[2].Integration).To(o.Equal("quay"))
+	})
+
+	t.Run("invalid window", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := m.ExpiringTokens(ctx, newConfig(t, "soon"), now)
+		g.Expect(err).To(o.MatchError(config.ErrInvalidConfig))
+	})
+}
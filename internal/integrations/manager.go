@@ -8,6 +8,8 @@ import (
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/integration"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 // IntegrationName name of a integration.
@@ -22,18 +24,27 @@ type Manager struct {
 }
 
 const (
+	ACR                   IntegrationName = "acr"
 	ACS                   IntegrationName = "acs"
 	Artifactory           IntegrationName = "artifactory"
 	Azure                 IntegrationName = "azure"
 	BitBucket             IntegrationName = "bitbucket"
+	ECR                   IntegrationName = "ecr"
+	GAR                   IntegrationName = "gar"
 	GitHub                IntegrationName = "github"
 	GitLab                IntegrationName = "gitlab"
 	Jenkins               IntegrationName = "jenkins"
+	Keycloak              IntegrationName = "keycloak"
 	Nexus                 IntegrationName = "nexus"
+	Notification          IntegrationName = "notification"
+	PagerDuty             IntegrationName = "pagerduty"
 	Quay                  IntegrationName = "quay"
+	Sigstore              IntegrationName = "sigstore"
 	TrustedArtifactSigner IntegrationName = "tas"
 	Trustification        IntegrationName = "trustification"
 	TrustificationAuth    IntegrationName = "trustificationauth"
+	Vault                 IntegrationName = "vault"
+	Xray                  IntegrationName = "xray"
 )
 
 // Integration returns the integration instance by name.
@@ -45,6 +56,20 @@ func (m *Manager) Integration(name IntegrationName) *integration.Integration {
 	return i
 }
 
+// Secret returns the integration secret by name, nil when the integration isn't
+// registered by the application, or isn't configured.
+func (m *Manager) Secret(
+	ctx context.Context,
+	cfg *config.Config,
+	name IntegrationName,
+) (*corev1.Secret, error) {
+	i, exists := m.integrations[name]
+	if !exists {
+		return nil, nil
+	}
+	return i.Secret(ctx, cfg)
+}
+
 // IntegrationNames returns a list of all integration names.
 func (m *Manager) IntegrationNames() []string {
 	names := make([]string, 0, len(m.integrations))
@@ -99,14 +124,26 @@ func (m *Manager) LoadModules(
 	for _, mod := range modules {
 		impl := mod.Init(runCtx.Logger, runCtx.Kube)
 
-		secretName := fmt.Sprintf("%s-%s-integration", appName, mod.Name)
-		wrapper := integration.NewSecret(runCtx.Logger, runCtx.Kube, secretName, impl)
+		wrapper := integration.NewSecret(
+			runCtx.Logger, runCtx.Kube, SecretName(appName, mod.Name), impl)
 
 		m.Register(mod, wrapper)
 	}
+	// The integration secrets are stored on Vault when it's the configured
+	// backend, only possible when the Vault integration is registered.
+	if _, ok := m.integrations[Vault]; ok {
+		for _, i := range m.integrations {
+			i.SetVaultSecret(SecretName(appName, string(Vault)))
+		}
+	}
 	return nil
 }
 
+// SecretName returns the name of the integration module secret.
+func SecretName(appName, module string) string {
+	return fmt.Sprintf("%s-%s-integration", appName, module)
+}
+
 // NewManager instantiates a new Manager.
 func NewManager() *Manager {
 	return &Manager{
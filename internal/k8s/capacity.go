@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterCapacity the cluster size and capabilities, from the allocatable
+// resources of the schedulable nodes and the APIs served.
+type ClusterCapacity struct {
+	Nodes     int   // schedulable nodes
+	MilliCPU  int64 // allocatable CPU, in millicores
+	Memory    int64 // allocatable memory, in bytes
+	GPUs      int64 // allocatable GPUs, like "nvidia.com/gpu"
+	OpenShift bool  // the cluster serves the OpenShift APIs
+	CRC       bool  // the cluster is CodeReady Containers, OpenShift Local
+}
+
+// CPUCores returns the allocatable CPU, in cores.
+func (c ClusterCapacity) CPUCores() float64 {
+	return float64(c.MilliCPU) / 1000
+}
+
+// MemoryGiB returns the allocatable memory, in GiB.
+func (c ClusterCapacity) MemoryGiB() float64 {
+	return float64(c.Memory) / (1 << 30)
+}
+
+// String describes the cluster capacity, for instance "3 nodes, 24 vCPU and
+// 96 GiB of memory, OpenShift".
+func (c ClusterCapacity) String() string {
+	nodes := "nodes"
+	if c.Nodes == 1 {
+		nodes = "node"
+	}
+	s := fmt.Sprintf("%d %s, %.4g vCPU and %.4g GiB of memory",
+		c.Nodes, nodes, c.CPUCores(), c.MemoryGiB())
+	if c.GPUs > 0 {
+		s += fmt.Sprintf(", %d GPUs", c.GPUs)
+	}
+	switch {
+	case c.CRC:
+		s += ", CodeReady Containers"
+	case c.OpenShift:
+		s += ", OpenShift"
+	}
+	return s
+}
+
+// crcNodePrefix the node name prefix of CodeReady Containers clusters.
+const crcNodePrefix = "crc"
+
+// gpuResourceSuffix the suffix of the extended resources advertising GPUs, by
+// the device plugins, for instance "nvidia.com/gpu" and "amd.com/gpu".
+const gpuResourceSuffix = "/gpu"
+
+// GetClusterCapacity inspects the cluster nodes and APIs. Unschedulable nodes
+// are not accounted. CodeReady Containers is detected by its single OpenShift
+// node, named after it.
+func GetClusterCapacity(ctx context.Context, kube Interface) (ClusterCapacity, error) {
+	capacity := ClusterCapacity{}
+	coreClient, err := kube.CoreV1ClientSet("")
+	if err != nil {
+		return capacity, err
+	}
+	nodes, err := coreClient.Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return capacity, err
+	}
+	var nodeName string
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		capacity.Nodes++
+		capacity.MilliCPU += node.Status.Allocatable.Cpu().MilliValue()
+		capacity.Memory += node.Status.Allocatable.Memory().Value()
+		for name, quantity := range node.Status.Allocatable {
+			if strings.HasSuffix(string(name), gpuResourceSuffix) {
+				capacity.GPUs += quantity.Value()
+			}
+		}
+		nodeName = node.GetName()
+	}
+
+	dc, err := kube.DiscoveryClient("")
+	if err != nil {
+		return capacity, err
+	}
+	_, err = dc.ServerResourcesForGroupVersion("config.openshift.io/v1")
+	capacity.OpenShift = err == nil
+	capacity.CRC = capacity.OpenShift && capacity.Nodes == 1 &&
+		strings.HasPrefix(nodeName, crcNodePrefix)
+	return capacity, nil
+}
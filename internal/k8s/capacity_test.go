@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestGetClusterCapacity(t *testing.T) {
+	g := o.NewWithT(t)
+
+	node := func(name, cpu, memory string, unschedulable bool) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				},
+			},
+		}
+	}
+	kube := NewFakeKube(
+		node("worker-0", "7500m", "30Gi", false),
+		node("worker-1", "8", "32Gi", false),
+		node("worker-2", "8", "32Gi", true),
+	)
+
+	capacity, err := GetClusterCapacity(context.Background(), kube)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(capacity).To(o.Equal(ClusterCapacity{
+		Nodes:    2,
+		MilliCPU: 15500,
+		Memory:   62 << 30,
+	}))
+	g.Expect(capacity.String()).To(
+		o.Equal("2 nodes, 15.5 vCPU and 62 GiB of memory"))
+
+	crc := ClusterCapacity{
+		Nodes: 1, MilliCPU: 4000, Memory: 16 << 30, OpenShift: true, CRC: true,
+	}
+	g.Expect(crc.String()).To(
+		o.Equal("1 node, 4 vCPU and 16 GiB of memory, CodeReady Containers"))
+}
@@ -0,0 +1,98 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// ErrReadAfterWrite the object written isn't readable, as written, within the
+// read-after-write timeout.
+var ErrReadAfterWrite = errors.New("object not readable after write")
+
+// ReadAfterWriteTimeout how long the objects written are read back, before the
+// dependent steps proceed. Slow, or load balanced, API servers may not serve
+// the objects right after they're written.
+var ReadAfterWriteTimeout = 30 * time.Second
+
+// readAfterWriteInterval the interval between the reads.
+var readAfterWriteInterval = 500 * time.Millisecond
+
+// retryableReadError returns true for the read errors expected while the API
+// server catches up with the write: the object not found yet, timeouts and
+// throttling.
+func retryableReadError(err error) bool {
+	return apierrors.IsNotFound(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err)
+}
+
+// WaitForObject reads the object written until it's readable and ready, the
+// ready function is optional. Read errors expected while the API server
+// catches up are retried, up to ReadAfterWriteTimeout, the others fail right
+// away.
+func WaitForObject[T any](
+	ctx context.Context,
+	kind, name string,
+	get func(context.Context) (T, error),
+	ready func(T) bool,
+) (T, error) {
+	var obj T
+	var lastErr error
+	err := wait.PollUntilContextTimeout(
+		ctx, readAfterWriteInterval, ReadAfterWriteTimeout, true,
+		func(ctx context.Context) (bool, error) {
+			current, err := get(ctx)
+			if err != nil {
+				if retryableReadError(err) {
+					lastErr = err
+					return false, nil
+				}
+				return false, err
+			}
+			obj = current
+			if ready != nil && !ready(current) {
+				lastErr = fmt.Errorf("%s %q is not ready", kind, name)
+				return false, nil
+			}
+			return true, nil
+		},
+	)
+	if err == nil {
+		return obj, nil
+	}
+	// Timing out, rather than the caller context done, means the object isn't
+	// readable yet.
+	if wait.Interrupted(err) && ctx.Err() == nil && lastErr != nil {
+		return obj, fmt.Errorf("%w: %s %q after %s: %w",
+			ErrReadAfterWrite, kind, name, ReadAfterWriteTimeout, lastErr)
+	}
+	return obj, err
+}
+
+// WaitForSecret reads the secret written until it's readable and ready, see
+// WaitForObject.
+func WaitForSecret(
+	ctx context.Context,
+	client corev1client.SecretsGetter,
+	name types.NamespacedName,
+	ready func(*corev1.Secret) bool,
+) (*corev1.Secret, error) {
+	return WaitForObject(ctx, "secret", name.String(),
+		func(ctx context.Context) (*corev1.Secret, error) {
+			return client.Secrets(name.Namespace).
+				Get(ctx, name.Name, metav1.GetOptions{})
+		},
+		ready,
+	)
+}
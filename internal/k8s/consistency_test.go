@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWaitForObject(t *testing.T) {
+	ctx := context.Background()
+	interval, timeout := readAfterWriteInterval, ReadAfterWriteTimeout
+	readAfterWriteInterval, ReadAfterWriteTimeout = time.Millisecond, 50*time.Millisecond
+	t.Cleanup(func() {
+		readAfterWriteInterval, ReadAfterWriteTimeout = interval, timeout
+	})
+
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, "s")
+	// eventually returns the object after the informed number of not found
+	// reads.
+	eventually := func(misses int) (*int, func(context.Context) (string, error)) {
+		reads := 0
+		return &reads, func(context.Context) (string, error) {
+			reads++
+			if reads <= misses {
+				return "", notFound
+			}
+			return "object", nil
+		}
+	}
+
+	t.Run("Eventually", func(t *testing.T) {
+		g := o.NewWithT(t)
+		reads, get := eventually(2)
+		obj, err := WaitForObject(ctx, "secret", "s", get, nil)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(obj).To(o.Equal("object"))
+		g.Expect(*reads).To(o.Equal(3))
+	})
+
+	t.Run("Ready", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, get := eventually(0)
+		_, err := WaitForObject(ctx, "secret", "s", get,
+			func(string) bool { return false })
+		g.Expect(err).To(o.MatchError(ErrReadAfterWrite))
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("not ready")))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, get := eventually(1 << 20)
+		_, err := WaitForObject(ctx, "secret", "s", get, nil)
+		g.Expect(err).To(o.MatchError(ErrReadAfterWrite))
+		g.Expect(apierrors.IsNotFound(err)).To(o.BeTrue())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		g := o.NewWithT(t)
+		forbidden := apierrors.NewForbidden(
+			schema.GroupResource{Resource: "secrets"}, "s", errors.New("denied"))
+		reads := 0
+		_, err := WaitForObject(ctx, "secret", "s",
+			func(context.Context) (string, error) {
+				reads++
+				return "", forbidden
+			}, nil)
+		g.Expect(apierrors.IsForbidden(err)).To(o.BeTrue())
+		g.Expect(err).NotTo(o.MatchError(ErrReadAfterWrite))
+		g.Expect(reads).To(o.Equal(1))
+	})
+
+	t.Run("WaitForSecret", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cs := fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s"},
+			Data:       map[string][]byte{"token": []byte("t")},
+		})
+		s, err := WaitForSecret(ctx, cs.CoreV1(),
+			types.NamespacedName{Namespace: "ns", Name: "s"},
+			func(s *corev1.Secret) bool { return len(s.Data) > 0 })
+		g.Expect(err).To(o.Succeed())
+		g.Expect(s.Data).To(o.HaveKey("token"))
+
+		_, err = WaitForSecret(ctx, cs.CoreV1(),
+			types.NamespacedName{Namespace: "ns", Name: "missing"}, nil)
+		g.Expect(err).To(o.MatchError(ErrReadAfterWrite))
+	})
+}
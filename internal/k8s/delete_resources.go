@@ -9,8 +9,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// labelSelector is the label set for resources to be deleted.
-const labelSelector = annotations.PostDeploy + "=delete"
+// TemporaryResourcesSelector is the label set for resources to be deleted.
+const TemporaryResourcesSelector = annotations.PostDeploy + "=delete"
 
 // DeleteClusterRoleBindings deletes Kubernetes ClusterRoleBindings by label.
 func DeleteClusterRoleBindings(
@@ -24,7 +24,7 @@ func DeleteClusterRoleBindings(
 	}
 	return rbacClient.ClusterRoleBindings().
 		DeleteCollection(ctx, metav1.DeleteOptions{},
-			metav1.ListOptions{LabelSelector: labelSelector})
+			metav1.ListOptions{LabelSelector: TemporaryResourcesSelector})
 }
 
 // DeleteClusterRoles deletes Kubernetes ClusterRoles by label.
@@ -39,7 +39,7 @@ func DeleteClusterRoles(
 	}
 	return rbacClient.ClusterRoles().
 		DeleteCollection(ctx, metav1.DeleteOptions{},
-			metav1.ListOptions{LabelSelector: labelSelector})
+			metav1.ListOptions{LabelSelector: TemporaryResourcesSelector})
 }
 
 // DeleteRoleBindings deletes Kubernetes RoleBindings by label.
@@ -53,7 +53,7 @@ func DeleteRoleBindings(
 		return err
 	}
 	RoleBindingsList, err := rbacClient.RoleBindings("").
-		List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		List(ctx, metav1.ListOptions{LabelSelector: TemporaryResourcesSelector})
 	if err != nil {
 		return err
 	}
@@ -78,7 +78,7 @@ func DeleteRoles(
 		return err
 	}
 	RolesList, err := rbacClient.Roles("").
-		List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		List(ctx, metav1.ListOptions{LabelSelector: TemporaryResourcesSelector})
 	if err != nil {
 		return err
 	}
@@ -103,7 +103,7 @@ func DeleteServiceAccounts(
 		return err
 	}
 	ServiceAccountList, err := coreClient.ServiceAccounts("").
-		List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		List(ctx, metav1.ListOptions{LabelSelector: TemporaryResourcesSelector})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,79 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultStorageClassAnnotation marks the cluster default storage class.
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// odfProvisionerPrefix the provisioner prefix of the OpenShift Data Foundation
+// storage classes, for instance "openshift-storage.rbd.csi.ceph.com".
+const odfProvisionerPrefix = "openshift-storage."
+
+// ClusterDiscovery the cluster attributes inspected to pre-populate the
+// configuration: the capacity, storage and ingress.
+type ClusterDiscovery struct {
+	ClusterCapacity
+	IngressDomain       string   // OpenShift ingress domain, empty when unknown
+	StorageClasses      []string // storage class names, sorted
+	DefaultStorageClass string   // default storage class, empty when none
+	ODF                 bool     // OpenShift Data Foundation storage available
+}
+
+// String describes the cluster, for instance "3 nodes, 24 vCPU and 96 GiB of
+// memory, OpenShift, ingress domain "apps.example.com", default storage class
+// "gp3-csi"".
+func (d ClusterDiscovery) String() string {
+	s := d.ClusterCapacity.String()
+	if d.IngressDomain != "" {
+		s += fmt.Sprintf(", ingress domain %q", d.IngressDomain)
+	}
+	if d.DefaultStorageClass != "" {
+		s += fmt.Sprintf(", default storage class %q", d.DefaultStorageClass)
+	}
+	if d.ODF {
+		s += ", OpenShift Data Foundation"
+	}
+	return s
+}
+
+// DiscoverCluster inspects the cluster capacity, see GetClusterCapacity, the
+// storage classes and, on OpenShift, the ingress domain. The ingress domain is
+// best effort, left empty when unavailable.
+func DiscoverCluster(ctx context.Context, kube Interface) (ClusterDiscovery, error) {
+	var err error
+	d := ClusterDiscovery{StorageClasses: []string{}}
+	if d.ClusterCapacity, err = GetClusterCapacity(ctx, kube); err != nil {
+		return d, err
+	}
+
+	cs, err := kube.ClientSet("")
+	if err != nil {
+		return d, err
+	}
+	classes, err := cs.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return d, err
+	}
+	for _, class := range classes.Items {
+		d.StorageClasses = append(d.StorageClasses, class.GetName())
+		if class.GetAnnotations()[defaultStorageClassAnnotation] == "true" {
+			d.DefaultStorageClass = class.GetName()
+		}
+		if strings.HasPrefix(class.Provisioner, odfProvisionerPrefix) {
+			d.ODF = true
+		}
+	}
+	slices.Sort(d.StorageClasses)
+
+	if d.OpenShift {
+		d.IngressDomain, _ = GetOpenShiftIngressDomain(ctx, kube)
+	}
+	return d, nil
+}
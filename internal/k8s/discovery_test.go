@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestDiscoverCluster(t *testing.T) {
+	g := o.NewWithT(t)
+
+	storageClass := func(name, provisioner string, isDefault bool) *storagev1.StorageClass {
+		sc := &storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: name},
+			Provisioner: provisioner,
+		}
+		if isDefault {
+			sc.Annotations = map[string]string{
+				defaultStorageClassAnnotation: "true",
+			}
+		}
+		return sc
+	}
+	kube := NewFakeKube(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker-0"},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("8"),
+					corev1.ResourceMemory: resource.MustParse("32Gi"),
+					"nvidia.com/gpu":      resource.MustParse("2"),
+				},
+			},
+		},
+		storageClass("ocs-storagecluster-ceph-rbd",
+			"openshift-storage.rbd.csi.ceph.com", false),
+		storageClass("gp3-csi", "ebs.csi.aws.com", true),
+	)
+
+	d, err := DiscoverCluster(context.Background(), kube)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(d.GPUs).To(o.Equal(int64(2)))
+	g.Expect(d.StorageClasses).To(o.Equal(
+		[]string{"gp3-csi", "ocs-storagecluster-ceph-rbd"}))
+	g.Expect(d.DefaultStorageClass).To(o.Equal("gp3-csi"))
+	g.Expect(d.ODF).To(o.BeTrue())
+	g.Expect(d.IngressDomain).To(o.BeEmpty())
+	g.Expect(d.String()).To(o.Equal("1 node, 8 vCPU and 32 GiB of memory, " +
+		`2 GPUs, default storage class "gp3-csi", OpenShift Data Foundation`))
+}
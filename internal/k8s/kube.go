@@ -1,9 +1,11 @@
 package k8s
 
 import (
-	"errors"
 	"fmt"
+	"math"
+	"sync"
 
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 	"github.com/redhat-appstudio/helmet/internal/flags"
 
 	corev1 "k8s.io/api/core/v1"
@@ -15,23 +17,55 @@ import (
 	batchv1client "k8s.io/client-go/kubernetes/typed/batch/v1"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
 )
 
 // Kube represents the Kubernetes client helper.
 type Kube struct {
 	flags *flags.Flags // global flags
+
+	limiterOnce sync.Once               // creates the limiter once
+	limiter     flowcontrol.RateLimiter // shared by all clients
 }
 
 var _ Interface = &Kube{}
 
 // ErrClientNotConnected kubernetes clients is not able to access the API.
-var ErrClientNotConnected = errors.New("kubernetes client not connected")
+var ErrClientNotConnected = helmeterrors.New(helmeterrors.ErrClusterUnreachable,
+	"kubernetes client not connected")
+
+// rateLimiter returns the rate limiter shared by all clients, nil when "--kube-qps"
+// isn't informed and the client defaults apply. Each client would otherwise
+// throttle on its own, thus the limit would not hold for the installer.
+func (k *Kube) rateLimiter() flowcontrol.RateLimiter {
+	k.limiterOnce.Do(func() {
+		if k.flags.KubeQPS <= 0 {
+			return
+		}
+		burst := k.flags.KubeBurst
+		if burst <= 0 {
+			burst = int(math.Ceil(float64(k.flags.KubeQPS) * 2))
+		}
+		k.limiter = flowcontrol.NewTokenBucketRateLimiter(k.flags.KubeQPS, burst)
+	})
+	return k.limiter
+}
 
 // RESTClientGetter returns a REST client getter for the given namespace.
 func (k *Kube) RESTClientGetter(namespace string) genericclioptions.RESTClientGetter {
 	g := genericclioptions.NewConfigFlags(false)
 	g.KubeConfig = &k.flags.KubeConfigPath
+	if k.flags.KubeContext != "" {
+		g.Context = &k.flags.KubeContext
+	}
 	g.Namespace = &namespace
+	g.WrapConfigFn = func(c *rest.Config) *rest.Config {
+		if limiter := k.rateLimiter(); limiter != nil {
+			c.RateLimiter = limiter
+		}
+		return c
+	}
 	return g
 }
 
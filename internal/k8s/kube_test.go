@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/flags"
+
+	o "github.com/onsi/gomega"
+)
+
+// TestKubeRateLimiter tests the rate limiter shared by the clients.
+func TestKubeRateLimiter(t *testing.T) {
+	g := o.NewWithT(t)
+
+	f := flags.NewFlags()
+	g.Expect(NewKube(f).rateLimiter()).To(o.BeNil())
+
+	f.KubeQPS = 2.5
+	k := NewKube(f)
+	limiter := k.rateLimiter()
+	g.Expect(limiter).NotTo(o.BeNil())
+	g.Expect(limiter.QPS()).To(o.BeNumerically("==", 2.5))
+	g.Expect(k.rateLimiter()).To(o.BeIdenticalTo(limiter))
+	// The burst defaults to twice the QPS.
+	for range 5 {
+		g.Expect(limiter.TryAccept()).To(o.BeTrue())
+	}
+	g.Expect(limiter.TryAccept()).To(o.BeFalse())
+}
@@ -2,15 +2,76 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"time"
+	"maps"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
+// NamespacePolicy determines how the deploy engine handles the target namespace.
+type NamespacePolicy string
+
+const (
+	// NamespaceIgnore the namespace is not managed, the chart or the operator
+	// is responsible for it.
+	NamespaceIgnore NamespacePolicy = "ignore"
+	// NamespaceCreate creates the namespace when missing, existing namespaces
+	// are used as is.
+	NamespaceCreate NamespacePolicy = "create"
+	// NamespaceAdopt creates the namespace when missing, existing namespaces
+	// receive the ownership labels and annotations.
+	NamespaceAdopt NamespacePolicy = "adopt"
+	// NamespaceRequire fails when the namespace doesn't exist.
+	NamespaceRequire NamespacePolicy = "require"
+)
+
+// NamespacePolicies valid namespace policies.
+var NamespacePolicies = []NamespacePolicy{
+	NamespaceIgnore,
+	NamespaceCreate,
+	NamespaceAdopt,
+	NamespaceRequire,
+}
+
+// ErrNamespaceNotFound the namespace is required but doesn't exist.
+var ErrNamespaceNotFound = errors.New("namespace not found")
+
+// createNamespace creates the namespace and waits for it to be readable and
+// active, see WaitForObject. A namespace created meanwhile, not yet visible when
+// it was checked, is waited for as well.
+func createNamespace(
+	ctx context.Context,
+	logger *slog.Logger,
+	client corev1client.CoreV1Interface,
+	ns *corev1.Namespace,
+) error {
+	logger.Info("Creating namespace...")
+	_, err := client.Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	logger.Info("Namespace created, waiting for it to be ready...")
+	_, err = WaitForObject(ctx, "namespace", ns.GetName(),
+		func(ctx context.Context) (*corev1.Namespace, error) {
+			return client.Namespaces().Get(ctx, ns.GetName(), metav1.GetOptions{})
+		},
+		func(ns *corev1.Namespace) bool {
+			return ns.Status.Phase == corev1.NamespaceActive
+		},
+	)
+	if err != nil {
+		return err
+	}
+	logger.Info("Namespace is ready!")
+	return nil
+}
+
 // EnsureNamespace ensures the Kubernetes namespace exists.
 // Uses vanilla Kubernetes Namespace API which works on both OpenShift and KinD.
 func EnsureNamespace(
@@ -19,7 +80,26 @@ func EnsureNamespace(
 	kube Interface,
 	namespace string,
 ) error {
-	logger = logger.With("namespace", namespace)
+	return ApplyNamespacePolicy(
+		ctx, logger, kube, namespace, NamespaceCreate, nil, nil)
+}
+
+// ApplyNamespacePolicy handles the namespace according to the policy, created
+// and adopted namespaces receive the informed labels and annotations.
+func ApplyNamespacePolicy(
+	ctx context.Context,
+	logger *slog.Logger,
+	kube Interface,
+	namespace string,
+	policy NamespacePolicy,
+	labels map[string]string,
+	annotations map[string]string,
+) error {
+	logger = logger.With("namespace", namespace, "namespace-policy", policy)
+	if policy == "" || policy == NamespaceIgnore {
+		logger.Debug("Namespace is not managed.")
+		return nil
+	}
 
 	logger.Debug("Verifying Kubernetes client connection...")
 	if err := kube.Connected(); err != nil {
@@ -32,48 +112,76 @@ func EnsureNamespace(
 	}
 
 	logger.Debug("Checking if namespace exists...")
-	_, err = client.Namespaces().Get(ctx, namespace, metav1.GetOptions{})
-	if err == nil {
+	ns, err := client.Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	switch {
+	case !exists && policy == NamespaceRequire:
+		return fmt.Errorf("%w: %q", ErrNamespaceNotFound, namespace)
+	case !exists:
+		return createNamespace(ctx, logger, client, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        namespace,
+				Labels:      labels,
+				Annotations: annotations,
+			},
+		})
+	case policy != NamespaceAdopt:
 		logger.Debug("Namespace already exists.")
 		return nil
 	}
-	if !apierrors.IsNotFound(err) {
-		return err
-	}
 
-	ns := &corev1.Namespace{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: namespace,
-		},
+	logger.Info("Adopting existing namespace...")
+	if ns.Labels == nil {
+		ns.Labels = map[string]string{}
 	}
+	maps.Copy(ns.Labels, labels)
+	if ns.Annotations == nil {
+		ns.Annotations = map[string]string{}
+	}
+	maps.Copy(ns.Annotations, annotations)
+	_, err = client.Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+	return err
+}
 
-	logger.Info("Creating namespace...")
-	_, err = client.Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+// LabelNamespace adds the informed labels to an existing namespace, the labels
+// already in place are kept. The namespace is only updated on changes.
+func LabelNamespace(
+	ctx context.Context,
+	logger *slog.Logger,
+	kube Interface,
+	namespace string,
+	labels map[string]string,
+) error {
+	if len(labels) == 0 {
+		return nil
+	}
+	client, err := kube.CoreV1ClientSet("default")
 	if err != nil {
 		return err
 	}
-
-	// Wait for namespace to be ready
-	logger.Info("Namespace created, waiting for it to be ready...")
-	timeout := 30 * time.Second
-	interval := 500 * time.Millisecond
-	deadline := time.Now().Add(timeout)
-
-	for {
-		ns, err := client.Namespaces().Get(ctx, namespace, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-
-		if ns.Status.Phase == corev1.NamespaceActive {
-			logger.Info("Namespace is ready!")
-			return nil
-		}
-
-		if time.Now().After(deadline) {
-			return fmt.Errorf("timeout waiting for namespace to be ready")
+	ns, err := client.Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if ns.Labels == nil {
+		ns.Labels = map[string]string{}
+	}
+	updated := false
+	for k, v := range labels {
+		if current, ok := ns.Labels[k]; !ok || current != v {
+			ns.Labels[k] = v
+			updated = true
 		}
-
-		time.Sleep(interval)
 	}
+	if !updated {
+		logger.Debug("Namespace labels are up to date.", "namespace", namespace)
+		return nil
+	}
+	logger.Info("Labeling namespace...", "namespace", namespace)
+	_, err = client.Namespaces().Update(ctx, ns, metav1.UpdateOptions{})
+	return err
 }
@@ -59,3 +59,87 @@ func TestEnsureNamespace(t *testing.T) {
 		})
 	}
 }
+
+// TestApplyNamespacePolicy tests the namespace policies against existing and
+// missing namespaces.
+func TestApplyNamespacePolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		policy    NamespacePolicy
+		wantErr   error
+	}{
+		{name: "ignore missing", namespace: "new-namespace", policy: NamespaceIgnore},
+		{name: "create missing", namespace: "new-namespace", policy: NamespaceCreate},
+		{name: "create existing", namespace: "existing-namespace", policy: NamespaceCreate},
+		{name: "adopt missing", namespace: "new-namespace", policy: NamespaceAdopt},
+		{name: "adopt existing", namespace: "existing-namespace", policy: NamespaceAdopt},
+		{name: "require existing", namespace: "existing-namespace", policy: NamespaceRequire},
+		{
+			name:      "require missing",
+			namespace: "new-namespace",
+			policy:    NamespaceRequire,
+			wantErr:   ErrNamespaceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			kube := NewFakeKube(stubs.NamespaceRuntimeObject("existing-namespace"))
+			err := ApplyNamespacePolicy(
+				context.TODO(),
+				slog.Default(),
+				kube,
+				tt.namespace,
+				tt.policy,
+				map[string]string{"app.kubernetes.io/managed-by": "test"},
+				nil,
+			)
+			if tt.wantErr != nil {
+				g.Expect(err).To(o.MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(o.HaveOccurred())
+		})
+	}
+}
+
+// TestLabelNamespace tests labeling existing and missing namespaces.
+func TestLabelNamespace(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		labels    map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "label existing",
+			namespace: "existing-namespace",
+			labels:    map[string]string{"monitoring": "enabled"},
+		},
+		{name: "no labels", namespace: "new-namespace"},
+		{
+			name:      "label missing",
+			namespace: "new-namespace",
+			labels:    map[string]string{"monitoring": "enabled"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := o.NewWithT(t)
+
+			kube := NewFakeKube(stubs.NamespaceRuntimeObject("existing-namespace"))
+			err := LabelNamespace(
+				context.TODO(), slog.Default(), kube, tt.namespace, tt.labels)
+			if tt.wantErr {
+				g.Expect(err).To(o.HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(o.HaveOccurred())
+		})
+	}
+}
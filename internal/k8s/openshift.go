@@ -22,6 +22,46 @@ import (
 // ErrIngressDomainNotFound returned when the OpenShift ingress domain is empty.
 var ErrIngressDomainNotFound = fmt.Errorf("ingress domain not found")
 
+// OpenShiftInfo the OpenShift cluster attributes, empty when unavailable.
+type OpenShiftInfo struct {
+	IngressDomain   string `json:"ingressDomain,omitempty"`
+	IngressRouterCA string `json:"ingressRouterCA,omitempty"`
+	Version         string `json:"version,omitempty"`
+}
+
+// OpenShiftInfoProvider clients knowing the OpenShift attributes beforehand,
+// for instance recorded on a cluster snapshot, the API is not queried.
+type OpenShiftInfoProvider interface {
+	OpenShiftInfo() OpenShiftInfo
+}
+
+// recordedOpenShiftInfo returns the attribute recorded by the client, when it's
+// an OpenShiftInfoProvider. Empty attributes result in the informed error.
+func recordedOpenShiftInfo(
+	kube Interface,
+	attribute func(OpenShiftInfo) string,
+	errNotFound error,
+) (string, bool, error) {
+	p, ok := kube.(OpenShiftInfoProvider)
+	if !ok {
+		return "", false, nil
+	}
+	if value := attribute(p.OpenShiftInfo()); value != "" {
+		return value, true, nil
+	}
+	return "", true, errNotFound
+}
+
+// GetOpenShiftInfo reads the OpenShift cluster attributes, the ones unavailable,
+// i.e. on vanilla Kubernetes, are left empty.
+func GetOpenShiftInfo(ctx context.Context, kube Interface) OpenShiftInfo {
+	info := OpenShiftInfo{}
+	info.IngressDomain, _ = GetOpenShiftIngressDomain(ctx, kube)
+	info.IngressRouterCA, _ = GetOpenShiftIngressRouteCA(ctx, kube)
+	info.Version, _ = GetOpenShiftVersion(ctx, kube)
+	return info
+}
+
 // Returns `default` IngressController CR if exists.
 func getIngressControllerCR(ctx context.Context, kube Interface) (*v1.IngressController, error) {
 	objectRef := &corev1.ObjectReference{
@@ -99,6 +139,11 @@ func getIngressControllerDefaultCertificate(ctx context.Context, kube Interface)
 // uses `router-ca` secret from `openshift-ingress-operator` namespace.
 // Related documentation: https://docs.openshift.com/container-platform/4.18/security/certificates/replacing-default-ingress-certificate.html#replacing-default-ingress
 func GetOpenShiftIngressRouteCA(ctx context.Context, kube Interface) (string, error) {
+	if ca, ok, err := recordedOpenShiftInfo(kube, func(i OpenShiftInfo) string {
+		return i.IngressRouterCA
+	}, fmt.Errorf("ingress router CA not found")); ok {
+		return ca, err
+	}
 	defaultCertSecretName, err := getIngressControllerDefaultCertificate(ctx, kube)
 	if err != nil {
 		return "", err
@@ -127,6 +172,11 @@ func GetOpenShiftIngressRouteCA(ctx context.Context, kube Interface) (string, er
 
 // GetOpenShiftIngressDomain returns the OpenShift Ingress domain.
 func GetOpenShiftIngressDomain(ctx context.Context, kube Interface) (string, error) {
+	if domain, ok, err := recordedOpenShiftInfo(kube, func(i OpenShiftInfo) string {
+		return i.IngressDomain
+	}, ErrIngressDomainNotFound); ok {
+		return domain, err
+	}
 	ingressController, err := getIngressControllerCR(ctx, kube)
 	if err != nil {
 		return "", err
@@ -141,6 +191,11 @@ func GetOpenShiftIngressDomain(ctx context.Context, kube Interface) (string, err
 
 // GetOpenShiftVersion returns the OpenShift version.
 func GetOpenShiftVersion(ctx context.Context, kube Interface) (string, error) {
+	if version, ok, err := recordedOpenShiftInfo(kube, func(i OpenShiftInfo) string {
+		return i.Version
+	}, fmt.Errorf("cluster desired version not found")); ok {
+		return version, err
+	}
 	clusterVersion, err := getConfigVersionCR(ctx, kube)
 	if err != nil {
 		return "", err
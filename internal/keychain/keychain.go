@@ -0,0 +1,31 @@
+// Package keychain stores credentials in the operating system keychain, so
+// reusable operator tokens don't need to be informed on the command-line.
+package keychain
+
+import "errors"
+
+// Interface represents a credential store, entries are identified by service
+// and account.
+type Interface interface {
+	// Get returns the secret stored for the service and account.
+	Get(service, account string) (string, error)
+
+	// Set stores the secret for the service and account, replacing the existing.
+	Set(service, account, secret string) error
+
+	// Delete removes the secret stored for the service and account.
+	Delete(service, account string) error
+}
+
+var (
+	// ErrNotFound the credential is not stored in the keychain.
+	ErrNotFound = errors.New("credential not found in keychain")
+	// ErrUnsupported the keychain is not available on this platform.
+	ErrUnsupported = errors.New("keychain is not supported on this platform")
+)
+
+// NewKeychain instantiates the keychain for the current operating system:
+// macOS Keychain, Windows Credential Manager, or the Secret Service on Linux.
+func NewKeychain() Interface {
+	return newKeychain()
+}
@@ -0,0 +1,61 @@
+package keychain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// securityNotFound exit code of "security" when the item is not found.
+const securityNotFound = 44
+
+// darwinKeychain uses the macOS "security" command-line tool.
+type darwinKeychain struct{}
+
+// run executes "security" with the informed arguments and input.
+func (darwinKeychain) run(stdin string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("security", args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == securityNotFound {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("security: %w: %s",
+			err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSuffix(stdout.String(), "\n"), nil
+}
+
+// Get returns the secret stored for the service and account.
+func (k darwinKeychain) Get(service, account string) (string, error) {
+	return k.run("", "find-generic-password", "-s", service, "-a", account, "-w")
+}
+
+// Set stores the secret for the service and account. The secret is informed
+// hex encoded through the interactive mode, keeping it out of the process
+// arguments.
+func (k darwinKeychain) Set(service, account, secret string) error {
+	_, err := k.run(fmt.Sprintf(
+		"add-generic-password -U -s %q -a %q -X %s\n",
+		service, account, hex.EncodeToString([]byte(secret)),
+	), "-i")
+	return err
+}
+
+// Delete removes the secret stored for the service and account.
+func (k darwinKeychain) Delete(service, account string) error {
+	_, err := k.run("", "delete-generic-password", "-s", service, "-a", account)
+	return err
+}
+
+func newKeychain() Interface {
+	return darwinKeychain{}
+}
@@ -0,0 +1,65 @@
+package keychain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// secretTool the Secret Service command-line tool, part of libsecret.
+const secretTool = "secret-tool"
+
+// linuxKeychain uses the Secret Service (GNOME Keyring, KWallet) through
+// "secret-tool".
+type linuxKeychain struct{}
+
+// run executes "secret-tool" with the informed arguments and input.
+func (linuxKeychain) run(stdin string, args ...string) (string, error) {
+	path, err := exec.LookPath(secretTool)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrUnsupported, err)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() == 0 {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("%s: %w: %s",
+			secretTool, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// Get returns the secret stored for the service and account.
+func (k linuxKeychain) Get(service, account string) (string, error) {
+	return k.run("", "lookup", "service", service, "account", account)
+}
+
+// Set stores the secret for the service and account, the secret is informed
+// via STDIN.
+func (k linuxKeychain) Set(service, account, secret string) error {
+	_, err := k.run(secret, "store",
+		fmt.Sprintf("--label=%s (%s)", service, account),
+		"service", service, "account", account)
+	return err
+}
+
+// Delete removes the secret stored for the service and account.
+func (k linuxKeychain) Delete(service, account string) error {
+	if _, err := k.Get(service, account); err != nil {
+		return err
+	}
+	_, err := k.run("", "clear", "service", service, "account", account)
+	return err
+}
+
+func newKeychain() Interface {
+	return linuxKeychain{}
+}
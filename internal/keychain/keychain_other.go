@@ -0,0 +1,25 @@
+//go:build !darwin && !linux && !windows
+
+package keychain
+
+// unsupportedKeychain is used on platforms without a known keychain.
+type unsupportedKeychain struct{}
+
+// Get always fails, the keychain is not supported.
+func (unsupportedKeychain) Get(string, string) (string, error) {
+	return "", ErrUnsupported
+}
+
+// Set always fails, the keychain is not supported.
+func (unsupportedKeychain) Set(string, string, string) error {
+	return ErrUnsupported
+}
+
+// Delete always fails, the keychain is not supported.
+func (unsupportedKeychain) Delete(string, string) error {
+	return ErrUnsupported
+}
+
+func newKeychain() Interface {
+	return unsupportedKeychain{}
+}
@@ -0,0 +1,122 @@
+package keychain
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	// credTypeGeneric CRED_TYPE_GENERIC.
+	credTypeGeneric = 1
+	// credPersistLocalMachine CRED_PERSIST_LOCAL_MACHINE.
+	credPersistLocalMachine = 2
+	// errorNotFound ERROR_NOT_FOUND.
+	errorNotFound = syscall.Errno(1168)
+)
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredRead   = advapi32.NewProc("CredReadW")
+	procCredWrite  = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+// credential mirrors the CREDENTIALW structure.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// windowsKeychain uses the Windows Credential Manager.
+type windowsKeychain struct{}
+
+// target composes the generic credential target name.
+func (windowsKeychain) target(service, account string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(service + ":" + account)
+}
+
+// callErr translates the Win32 error returned by a credentials call.
+func callErr(err error) error {
+	if errors.Is(err, errorNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// Get returns the secret stored for the service and account.
+func (k windowsKeychain) Get(service, account string) (string, error) {
+	target, err := k.target(service, account)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	r, _, err := procCredRead.Call(
+		uintptr(unsafe.Pointer(target)),
+		credTypeGeneric,
+		0,
+		uintptr(unsafe.Pointer(&cred)),
+	)
+	if r == 0 {
+		return "", callErr(err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred))) //nolint:errcheck
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+// Set stores the secret for the service and account.
+func (k windowsKeychain) Set(service, account, secret string) error {
+	target, err := k.target(service, account)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(account)
+	if err != nil {
+		return err
+	}
+	blob := []byte(secret)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	r, _, err := procCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0)
+	if r == 0 {
+		return callErr(err)
+	}
+	return nil
+}
+
+// Delete removes the secret stored for the service and account.
+func (k windowsKeychain) Delete(service, account string) error {
+	target, err := k.target(service, account)
+	if err != nil {
+		return err
+	}
+	r, _, err := procCredDelete.Call(
+		uintptr(unsafe.Pointer(target)), credTypeGeneric, 0)
+	if r == 0 {
+		return callErr(err)
+	}
+	return nil
+}
+
+func newKeychain() Interface {
+	return windowsKeychain{}
+}
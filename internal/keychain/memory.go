@@ -0,0 +1,53 @@
+package keychain
+
+import "sync"
+
+// Memory is an in-memory keychain, used when the operating system keychain is
+// not desired, for instance on tests.
+type Memory struct {
+	mu      sync.Mutex        // guards entries
+	entries map[string]string // secrets by service and account
+}
+
+var _ Interface = &Memory{}
+
+// key composes the entry key.
+func (m *Memory) key(service, account string) string {
+	return service + "/" + account
+}
+
+// Get returns the secret stored for the service and account.
+func (m *Memory) Get(service, account string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	secret, ok := m.entries[m.key(service, account)]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set stores the secret for the service and account.
+func (m *Memory) Set(service, account, secret string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[m.key(service, account)] = secret
+	return nil
+}
+
+// Delete removes the secret stored for the service and account.
+func (m *Memory) Delete(service, account string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := m.key(service, account)
+	if _, ok := m.entries[key]; !ok {
+		return ErrNotFound
+	}
+	delete(m.entries, key)
+	return nil
+}
+
+// NewMemory instantiates an empty in-memory keychain.
+func NewMemory() *Memory {
+	return &Memory{entries: map[string]string{}}
+}
@@ -0,0 +1,253 @@
+package logs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ErrNoPods the product workloads have no pods, or no container matching.
+var ErrNoPods = helmeterrors.New(helmeterrors.ErrPrerequisitesMissing,
+	"no pods found")
+
+// Options the selection of the container logs read.
+type Options struct {
+	Container string        // container name, every container when empty
+	Since     time.Duration // only the newer logs, every log when zero
+	Tail      int64         // last lines per container, every line when zero
+	Follow    bool          // streams the new lines until interrupted
+	Limit     int64         // bytes per container, unlimited when zero
+}
+
+// Source a pod container of a product workload, whose logs are read.
+type Source struct {
+	Workload  string // workload, "Kind/name"
+	Namespace string // pod namespace
+	Pod       string // pod name
+	Container string // container name
+}
+
+// String describes the source as "pod/container", prefixing its log lines.
+func (s Source) String() string {
+	return s.Pod + "/" + s.Container
+}
+
+// Logs reads the logs of the product workloads pods, resolved from the release
+// workloads label selectors, aggregating them line by line.
+type Logs struct {
+	logger *slog.Logger  // application logger
+	kube   k8s.Interface // kubernetes client
+	opts   Options       // logs selection
+}
+
+// containers returns the pod container names, init containers first, matching
+// the container option.
+func (l *Logs) containers(pod *corev1.Pod) []string {
+	names := []string{}
+	for _, c := range slices.Concat(
+		pod.Spec.InitContainers, pod.Spec.Containers,
+	) {
+		if l.opts.Container == "" || l.opts.Container == c.Name {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// Sources lists the pods of the workloads, and their containers, ordered by
+// workload and pod. The pods selected by more than one workload are listed
+// once, for the first.
+func (l *Logs) Sources(
+	ctx context.Context,
+	workloads []deployer.Workload,
+) ([]Source, error) {
+	sources := []Source{}
+	seen := map[string]bool{}
+	containers := map[string]bool{}
+	for _, w := range workloads {
+		coreClient, err := l.kube.CoreV1ClientSet(w.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		pods, err := coreClient.Pods(w.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(w.Selector).String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing %s pods: %w", w, err)
+		}
+		slices.SortFunc(pods.Items, func(a, b corev1.Pod) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		for _, pod := range pods.Items {
+			key := pod.Namespace + "/" + pod.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			for _, c := range slices.Concat(
+				pod.Spec.InitContainers, pod.Spec.Containers,
+			) {
+				containers[c.Name] = true
+			}
+			for _, name := range l.containers(&pod) {
+				sources = append(sources, Source{
+					Workload:  w.String(),
+					Namespace: pod.Namespace,
+					Pod:       pod.Name,
+					Container: name,
+				})
+			}
+		}
+	}
+	switch {
+	case len(seen) == 0:
+		names := make([]string, 0, len(workloads))
+		for _, w := range workloads {
+			names = append(names, w.String())
+		}
+		return nil, fmt.Errorf("%w: for the workloads %s",
+			ErrNoPods, strings.Join(names, ", "))
+	case len(sources) == 0:
+		return nil, fmt.Errorf("%w: container %q not found, the containers are: %s",
+			ErrNoPods, l.opts.Container,
+			strings.Join(slices.Sorted(maps.Keys(containers)), ", "))
+	}
+	return sources, nil
+}
+
+// podLogOptions returns the Kubernetes log options of the container.
+func (l *Logs) podLogOptions(container string) *corev1.PodLogOptions {
+	opts := &corev1.PodLogOptions{
+		Container: container,
+		Follow:    l.opts.Follow,
+	}
+	if l.opts.Since > 0 {
+		seconds := int64(l.opts.Since.Seconds())
+		opts.SinceSeconds = &seconds
+	}
+	if l.opts.Tail > 0 {
+		opts.TailLines = &l.opts.Tail
+	}
+	if l.opts.Limit > 0 {
+		opts.LimitBytes = &l.opts.Limit
+	}
+	return opts
+}
+
+// stream copies the source logs to the writer, each line prefixed by the
+// source, the writer is guarded by the mutex.
+func (l *Logs) stream(
+	ctx context.Context,
+	w io.Writer,
+	mu *sync.Mutex,
+	s Source,
+) error {
+	coreClient, err := l.kube.CoreV1ClientSet(s.Namespace)
+	if err != nil {
+		return err
+	}
+	rc, err := coreClient.Pods(s.Namespace).
+		GetLogs(s.Pod, l.podLogOptions(s.Container)).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		mu.Lock()
+		_, err = fmt.Fprintf(w, "[%s] %s\n", s, scanner.Text())
+		mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	if err = scanner.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// Write writes the sources logs to the writer, one source after the other, or
+// all at once when following, until the context is done. Sources which logs
+// can't be read, like containers waiting to start, are reported in place.
+func (l *Logs) Write(ctx context.Context, w io.Writer, sources []Source) error {
+	var mu sync.Mutex
+	read := func(s Source) error {
+		err := l.stream(ctx, w, &mu, s)
+		if err == nil || ctx.Err() != nil {
+			return nil
+		}
+		l.logger.Debug("Unable to read the container logs",
+			"source", s.String(), "err", err)
+		mu.Lock()
+		defer mu.Unlock()
+		_, err = fmt.Fprintf(w, "[%s] unable to read logs: %s\n", s, err)
+		return err
+	}
+	if !l.opts.Follow {
+		for _, s := range sources {
+			if err := read(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	g := errgroup.Group{}
+	for _, s := range sources {
+		g.Go(func() error { return read(s) })
+	}
+	return g.Wait()
+}
+
+// Workloads resolves the product, or dependency, on the topology, returning its
+// dependency and the workloads of its deployed release.
+func Workloads(
+	logger *slog.Logger,
+	f *flags.Flags,
+	kube k8s.Interface,
+	topology *resolver.Topology,
+	name string,
+) (*resolver.Dependency, []deployer.Workload, error) {
+	dep, err := topology.GetProductDependency(name)
+	if err != nil {
+		if dep, err = topology.GetDependency(name); err != nil {
+			return nil, nil, fmt.Errorf("%w: %q is neither a product nor a "+
+				"dependency on the topology", resolver.ErrDependencyNotFound, name)
+		}
+	}
+	hc, err := deployer.NewHelm(logger, f, kube, dep.Namespace(), dep.Chart())
+	if err != nil {
+		return nil, nil, err
+	}
+	workloads, err := hc.Workloads()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading the release of %q: %w", dep.Name(), err)
+	}
+	return dep, workloads, nil
+}
+
+// NewLogs instantiates the logs reader with the selection options.
+func NewLogs(logger *slog.Logger, kube k8s.Interface, opts Options) *Logs {
+	return &Logs{logger: logger, kube: kube, opts: opts}
+}
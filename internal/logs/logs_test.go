@@ -0,0 +1,108 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/deployer"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLogs(t *testing.T) {
+	ctx := context.Background()
+	pod := func(name string, labels map[string]string, containers ...string) *corev1.Pod {
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "product-a",
+			Labels:    labels,
+		}}
+		for _, c := range containers {
+			p.Spec.Containers = append(p.Spec.Containers, corev1.Container{Name: c})
+		}
+		return p
+	}
+	kube := k8s.NewFakeKube(
+		pod("api-1", map[string]string{"app": "api"}, "api", "proxy"),
+		pod("api-0", map[string]string{"app": "api"}, "api", "proxy"),
+		pod("db-0", map[string]string{"app": "db"}, "db"),
+		pod("other", map[string]string{"app": "other"}, "other"),
+	)
+	workloads := []deployer.Workload{{
+		Kind:      "Deployment",
+		Namespace: "product-a",
+		Name:      "api",
+		Selector:  map[string]string{"app": "api"},
+	}, {
+		Kind:      "StatefulSet",
+		Namespace: "product-a",
+		Name:      "db",
+		Selector:  map[string]string{"app": "db"},
+	}}
+
+	t.Run("Sources", func(t *testing.T) {
+		g := o.NewWithT(t)
+		l := NewLogs(slog.Default(), kube, Options{})
+		sources, err := l.Sources(ctx, workloads)
+		g.Expect(err).To(o.Succeed())
+		names := []string{}
+		for _, s := range sources {
+			names = append(names, s.String())
+		}
+		g.Expect(names).To(o.Equal([]string{
+			"api-0/api", "api-0/proxy", "api-1/api", "api-1/proxy", "db-0/db",
+		}))
+		g.Expect(sources[4].Workload).To(o.Equal("StatefulSet/db"))
+	})
+
+	t.Run("Container", func(t *testing.T) {
+		g := o.NewWithT(t)
+		l := NewLogs(slog.Default(), kube, Options{Container: "proxy"})
+		sources, err := l.Sources(ctx, workloads)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(sources).To(o.HaveLen(2))
+
+		l = NewLogs(slog.Default(), kube, Options{Container: "missing"})
+		_, err = l.Sources(ctx, workloads)
+		g.Expect(err).To(o.MatchError(ErrNoPods))
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("api, db, proxy")))
+	})
+
+	t.Run("NoPods", func(t *testing.T) {
+		g := o.NewWithT(t)
+		l := NewLogs(slog.Default(), k8s.NewFakeKube(), Options{})
+		_, err := l.Sources(ctx, workloads)
+		g.Expect(err).To(o.MatchError(ErrNoPods))
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("Deployment/api")))
+	})
+
+	t.Run("Write", func(t *testing.T) {
+		g := o.NewWithT(t)
+		opts := Options{Since: 10 * time.Minute, Tail: 5, Limit: 1024}
+		l := NewLogs(slog.Default(), kube, opts)
+		sources, err := l.Sources(ctx, workloads)
+		g.Expect(err).To(o.Succeed())
+
+		var out bytes.Buffer
+		g.Expect(l.Write(ctx, &out, sources)).To(o.Succeed())
+		// The fake clientset serves "fake logs" for every container.
+		g.Expect(out.String()).To(o.HavePrefix("[api-0/api] fake logs\n"))
+		g.Expect(out.String()).To(o.HaveSuffix("[db-0/db] fake logs\n"))
+
+		plo := l.podLogOptions("api")
+		g.Expect(*plo.SinceSeconds).To(o.Equal(int64(600)))
+		g.Expect(*plo.TailLines).To(o.Equal(int64(5)))
+		g.Expect(*plo.LimitBytes).To(o.Equal(int64(1024)))
+
+		l = NewLogs(slog.Default(), kube, Options{Follow: true})
+		out.Reset()
+		g.Expect(l.Write(ctx, &out, sources)).To(o.Succeed())
+		g.Expect(out.String()).To(o.ContainSubstring("[api-1/proxy] fake logs\n"))
+	})
+}
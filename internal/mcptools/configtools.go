@@ -5,11 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
 
 	"dario.cat/mergo"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -22,13 +27,18 @@ import (
 // subcommand it uses the ConfigManager to manage the configuration in the
 // cluster.
 type ConfigTools struct {
-	appName string                   // application name for dynamic naming
-	logger  *slog.Logger             // application logger
-	cfs     *chartfs.ChartFS         // embedded filesystem
-	cm      *config.ConfigMapManager // cluster config manager
-	kube    k8s.Interface            // kubernetes client
+	appName string                    // application name for dynamic naming
+	logger  *slog.Logger              // application logger
+	cfs     *chartfs.ChartFS          // embedded filesystem
+	cm      *config.ConfigMapManager  // cluster config manager
+	kube    k8s.Interface             // kubernetes client
+	tb      *resolver.TopologyBuilder // topology builder
 
-	defaultCfg *config.Config // default config (embedded)
+	settings   config.SettingRegistry // settings known by the application
+	discovery  []config.DiscoveryRule // cluster discovery rules
+	defaultCfg *config.Config         // default config (embedded)
+	history    *configHistory         // configuration changes by session
+	pager      *Pager                 // paginates the configuration
 }
 
 const (
@@ -44,32 +54,85 @@ const (
 	configProductNamespaceSuffix = "_config_product_namespace"
 	// configProductPropertiesSuffix manipulates the properties of a product suffix.
 	configProductPropertiesSuffix = "_config_product_properties"
+	// configProductBatchSuffix applies several product changes at once suffix.
+	configProductBatchSuffix = "_config_product_batch"
+	// configSetSuffix changes a single configuration value by path suffix.
+	configSetSuffix = "_config_set"
+	// configDriftSuffix reports the cluster configuration drift suffix.
+	configDriftSuffix = "_config_drift"
 )
 
 // Arguments for the config tools.
 const (
-	NamespaceArg  = "namespace"
-	KeyArg        = "key"
-	ValueArg      = "value"
-	NameArg       = "name"
-	EnabledArg    = "enabled"
-	PropertiesArg = "properties"
+	NamespaceArg   = "namespace"
+	EnvironmentArg = "environment"
+	KeyArg         = "key"
+	ValueArg       = "value"
+	NameArg        = "name"
+	EnabledArg     = "enabled"
+	PropertiesArg  = "properties"
+	ProductsArg    = "products"
+	PathArg        = "path"
+	OutputArg      = "output"
+	ConfigArg      = "config"
+	DiscoverArg    = "discover"
 )
 
+// formatConfig formats the configuration on the informed output, "json",
+// "yaml" or "table", only showing the informed products, when any.
+func formatConfig(
+	cfg *config.Config,
+	output string,
+	products []string,
+) (string, error) {
+	out, err := printer.NewOutput(output)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if out.Table() {
+		err = cfg.PrintTable(&b, products)
+		return b.String(), err
+	}
+	items, err := cfg.Items(products)
+	if err != nil {
+		return "", err
+	}
+	err = out.Print(&b, items)
+	return b.String(), err
+}
+
 // getHandler similar to "config --get" subcommand it returns an existing
 // cluster configuration. If no such configuration exists it returns the
 // installer's default.
 func (c *ConfigTools) getHandler(
 	ctx context.Context,
-	_ mcp.CallToolRequest,
+	ctr mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
+	output := ctr.GetString(OutputArg, "")
+	products := ctr.GetStringSlice(ProductsArg, []string{})
+
 	cfg, err := c.cm.GetConfig(ctx)
 	// The cluster is already configured, showing the user the existing
-	// configuration as text.
+	// configuration as text, or on the requested output format. Sensitive
+	// fields are redacted. The session observes the configuration mutation,
+	// the following changes must be based on it.
 	if err == nil {
-		return mcp.NewToolResultText(
-			fmt.Sprintf("Current %s configuration:\n%s", c.appName, cfg.String()),
-		), nil
+		c.history.observe(sessionID(ctx), cfg.Sequence())
+		if cfg, err = c.cm.Redact(cfg); err != nil {
+			return nil, err
+		}
+		if output == "" && len(products) == 0 {
+			return mcp.NewToolResultText(fmt.Sprintf(
+				"Current %s configuration, mutation #%d applied by %q:\n%s",
+				c.appName, cfg.Sequence(), cfg.MutatedBy(), cfg.String(),
+			)), nil
+		}
+		formatted, err := formatConfig(cfg, output, products)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return mcp.NewToolResultText(formatted), nil
 	}
 
 	// Return error when different than configuration not found.
@@ -138,6 +201,40 @@ Unable to retrieve the configuration from the cluster!`,
 	}
 	cfg := cfgPtr
 
+	// Pre-populating the defaults for the cluster, when requested, before the
+	// environment overlay.
+	var discovered string
+	if ctr.GetBool(DiscoverArg, false) {
+		d, defaults, err := config.DiscoverDefaults(ctx, c.kube, c.discovery)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr(`
+Unable to inspect the cluster!`,
+				err,
+			), nil
+		}
+		for _, path := range slices.Sorted(maps.Keys(defaults)) {
+			if err := cfg.SetPath(path, defaults[path]); err != nil {
+				return mcp.NewToolResultErrorFromErr(fmt.Sprintf(`
+Unable to apply the discovered default %q!`, path),
+					err,
+				), nil
+			}
+		}
+		discovered = fmt.Sprintf(`
+
+Discovered %s, and applied the defaults: %v`, d, defaults)
+	}
+
+	// Applying the environment overlay, when informed.
+	if env, _ := ctr.GetArguments()[EnvironmentArg].(string); env != "" {
+		if err := cfg.ApplyEnvironment(env); err != nil {
+			return mcp.NewToolResultErrorFromErr(`
+Unable to apply the environment overlay on the default configuration!`,
+				err,
+			), nil
+		}
+	}
+
 	// Ensure the configuration is valid.
 	if err := cfg.Validate(); err != nil {
 		return nil, err
@@ -158,11 +255,13 @@ Unable to retrieve the configuration from the cluster!`,
 	if err := c.cm.Create(ctx, cfg); err != nil {
 		return nil, err
 	}
+	c.history.observe(sessionID(ctx), cfg.Sequence())
 
 	return mcp.NewToolResultText(fmt.Sprintf(`
-%s default configuration is successfully applied in %q namespace`,
+%s default configuration is successfully applied in %q namespace%s`,
 		c.appName,
 		cfg.Namespace(),
+		discovered,
 	)), nil
 }
 
@@ -195,14 +294,25 @@ You must inform the %q argument with the name of the attribute to update!`,
 			KeyArg,
 		), nil
 	}
-	value, ok := ctr.GetArguments()[ValueArg].(bool)
-	if !ok {
+	value, ok := ctr.GetArguments()[ValueArg]
+	if _, isBool := value.(bool); !ok || (len(c.settings) == 0 && !isBool) {
 		return mcp.NewToolResultErrorf(`
 You must inform the %q argument with the value for the informed key %q!`,
 			ValueArg,
 			key,
 		), nil
 	}
+	setting, err := c.settings.Resolve(key)
+	if err == nil && setting != nil {
+		value, err = setting.Coerce(value)
+	}
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Invalid setting, inspect the tool's schema for the known settings, their types
+and allowed values.`,
+			err,
+		), nil
+	}
 
 	cfg, res := c.getConfig(ctx)
 	if res != nil {
@@ -210,7 +320,7 @@ You must inform the %q argument with the value for the informed key %q!`,
 	}
 
 	// Updating the configuration instance and the cluster.
-	err := cfg.Set(fmt.Sprintf("%s.settings.%s", c.appName, key), value)
+	err = cfg.Set(fmt.Sprintf("%s.settings.%s", c.appName, key), value)
 	if err != nil {
 		return mcp.NewToolResultErrorf(`
 Unable to update the existing configuration with informed settings:
@@ -223,12 +333,8 @@ Unable to update the existing configuration with informed settings:
 			err,
 		), nil
 	}
-	if err = c.cm.Update(ctx, cfg); err != nil {
-		return mcp.NewToolResultErrorFromErr(`
-Unable to update the cluster configuration!
-`,
-			err,
-		), nil
+	if err = c.update(ctx, configSettingsSuffix, cfg); err != nil {
+		return c.updateError(err), nil
 	}
 
 	return mcp.NewToolResultText(fmt.Sprintf(`
@@ -260,6 +366,7 @@ Unable to find the product name %q: %q`,
 // and persists the changes to the cluster configuration.
 func (c *ConfigTools) setProduct(
 	ctx context.Context,
+	tool string,
 	cfg *config.Config,
 	name string,
 	spec config.Product,
@@ -272,12 +379,8 @@ Unable to update product %q: %q`,
 			err,
 		)
 	}
-	if err = c.cm.Update(ctx, cfg); err != nil {
-		return mcp.NewToolResultErrorFromErr(`
-Unable to update the cluster configuration!
-`,
-			err,
-		)
+	if err = c.update(ctx, tool, cfg); err != nil {
+		return c.updateError(err)
 	}
 	return nil
 }
@@ -318,7 +421,9 @@ You must inform the %q argument, with the status of the product %q`,
 	// Toggle the product status.
 	spec.Enabled = enabled
 
-	if res = c.setProduct(ctx, cfg, name, config.Product{Enabled: enabled}); res != nil {
+	res = c.setProduct(ctx, configProductEnabledSuffix, cfg, name,
+		config.Product{Enabled: enabled})
+	if res != nil {
 		return res, nil
 	}
 
@@ -364,7 +469,8 @@ You must inform the %q argument for the %q product.`,
 	// Toggle the namespace on the product spec.
 	spec.Namespace = &namespace
 
-	if res = c.setProduct(ctx, cfg, name, *spec); res != nil {
+	res = c.setProduct(ctx, configProductNamespaceSuffix, cfg, name, *spec)
+	if res != nil {
 		return res, nil
 	}
 
@@ -428,7 +534,17 @@ Unable to merge informed properties with existing configuration!
 		), nil
 	}
 
-	if res = c.setProduct(ctx, cfg, name, *spec); res != nil {
+	// Asserting the properties match the schema declared by the product chart.
+	if err = c.checkProperties(name, spec.Properties); err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+The informed properties don't match the product properties schema, the cluster
+configuration is unchanged!`,
+			err,
+		), nil
+	}
+
+	res = c.setProduct(ctx, configProductPropertiesSuffix, cfg, name, *spec)
+	if res != nil {
 		return res, nil
 	}
 
@@ -442,6 +558,333 @@ cluster.
 	)), nil
 }
 
+// checkProperties asserts the product properties match the schema declared by
+// the product chart, if any.
+func (c *ConfigTools) checkProperties(
+	name string,
+	properties map[string]interface{},
+) error {
+	d, err := c.tb.GetCollection().GetProductDependency(name)
+	if err != nil {
+		return err
+	}
+	schema, err := d.PropertiesSchema()
+	if err != nil || schema == nil {
+		return err
+	}
+	_, err = schema.Apply(properties)
+	return err
+}
+
+// applyProductChange applies a single batch entry on the configuration, the
+// entry is an object with the product name and the optional "enabled",
+// "namespace" and "properties" attributes.
+func (c *ConfigTools) applyProductChange(
+	cfg *config.Config,
+	entry map[string]interface{},
+) error {
+	name, ok := entry[NameArg].(string)
+	if !ok || name == "" {
+		return fmt.Errorf("the %q attribute is required", NameArg)
+	}
+	spec, err := cfg.GetProduct(name)
+	if err != nil {
+		return err
+	}
+	if v, exists := entry[EnabledArg]; exists {
+		if spec.Enabled, ok = v.(bool); !ok {
+			return fmt.Errorf("product %q: %q must be a boolean", name, EnabledArg)
+		}
+	}
+	if v, exists := entry[NamespaceArg]; exists {
+		namespace, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("product %q: %q must be a string", name, NamespaceArg)
+		}
+		spec.Namespace = &namespace
+	}
+	if v, exists := entry[PropertiesArg]; exists {
+		properties, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("product %q: %q must be an object", name, PropertiesArg)
+		}
+		if spec.Properties == nil {
+			spec.Properties = map[string]interface{}{}
+		}
+		err = mergo.Merge(&spec.Properties, properties, mergo.WithOverride)
+		if err != nil {
+			return fmt.Errorf("product %q: %w", name, err)
+		}
+	}
+	return cfg.SetProduct(name, *spec)
+}
+
+// configProductBatchHandler applies a list of product changes atomically. All
+// changes are applied on the configuration in memory, the topology is resolved
+// once, and only when all of them succeed the cluster configuration is updated.
+func (c *ConfigTools) configProductBatchHandler(
+	ctx context.Context,
+	ctr mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	entries, ok := ctr.GetArguments()[ProductsArg].([]interface{})
+	if !ok || len(entries) == 0 {
+		return mcp.NewToolResultErrorf(`
+You must inform the %q argument with the list of product changes to apply.`,
+			ProductsArg,
+		), nil
+	}
+
+	cfg, res := c.getConfig(ctx)
+	if res != nil {
+		return res, nil
+	}
+
+	names := make([]string, 0, len(entries))
+	for i, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			return mcp.NewToolResultErrorf(`
+The %q entry at index %d must be an object.`,
+				ProductsArg,
+				i,
+			), nil
+		}
+		if err := c.applyProductChange(cfg, entry); err != nil {
+			return mcp.NewToolResultErrorf(`
+Unable to apply the product changes, the cluster configuration is unchanged!
+
+  Index: %d
+  Error: %s`,
+				i,
+				err,
+			), nil
+		}
+		names = append(names, entry[NameArg].(string))
+	}
+
+	// Asserting the final configuration is valid, and the dependency topology
+	// can be resolved, before persisting the changes in the cluster.
+	if err := cfg.Validate(); err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+The product changes result in an invalid configuration, the cluster
+configuration is unchanged!`,
+			err,
+		), nil
+	}
+	r := resolver.NewResolver(cfg, c.tb.GetCollection(), resolver.NewTopology())
+	if err := r.Resolve(); err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+The product changes result in an unresolvable topology, the cluster
+configuration is unchanged!`,
+			err,
+		), nil
+	}
+
+	if err := c.update(ctx, configProductBatchSuffix, cfg); err != nil {
+		return c.updateError(err), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf(`
+The products %v are updated, and the configuration is applied in the cluster.`,
+		names,
+	)), nil
+}
+
+// configSetHandler changes a single configuration value, identified by its path
+// relative to the application root key, analogous to "config set".
+func (c *ConfigTools) configSetHandler(
+	ctx context.Context,
+	ctr mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	path, ok := ctr.GetArguments()[PathArg].(string)
+	if !ok || path == "" {
+		return mcp.NewToolResultErrorf(`
+You must inform the %q argument with the configuration path to change!`,
+			PathArg,
+		), nil
+	}
+	raw, ok := ctr.GetArguments()[ValueArg].(string)
+	if !ok {
+		return mcp.NewToolResultErrorf(`
+You must inform the %q argument with the value for the informed path %q!`,
+			ValueArg,
+			path,
+		), nil
+	}
+	value, err := config.CoerceValue(raw)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to parse the informed value!`,
+			err,
+		), nil
+	}
+
+	cfg, res := c.getConfig(ctx)
+	if res != nil {
+		return res, nil
+	}
+	if err = cfg.SetPath(path, value); err != nil {
+		return mcp.NewToolResultErrorf(`
+Unable to update the existing configuration, the cluster configuration is
+unchanged:
+
+   Path: %q
+  Value: %v
+  Error: %s`,
+			path,
+			value,
+			err,
+		), nil
+	}
+	r := resolver.NewResolver(cfg, c.tb.GetCollection(), resolver.NewTopology())
+	if err = r.Resolve(); err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+The change results in an unresolvable topology, the cluster configuration is
+unchanged!`,
+			err,
+		), nil
+	}
+	if err = c.update(ctx, configSetSuffix, cfg); err != nil {
+		return c.updateError(err), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf(`
+The configuration path %q is set to %v, and the configuration is applied in the
+cluster.`,
+		path,
+		value,
+	)), nil
+}
+
+// configDriftHandler compares the expected configuration, informed or the
+// installer's default, with the cluster's, analogous to "config reconcile
+// --dry-run". It only reports the drift.
+func (c *ConfigTools) configDriftHandler(
+	ctx context.Context,
+	ctr mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	cluster, res := c.getConfig(ctx)
+	if res != nil {
+		return res, nil
+	}
+
+	payload, err := c.defaultCfg.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	if informed := ctr.GetString(ConfigArg, ""); informed != "" {
+		payload = []byte(informed)
+	}
+	expected, err := config.NewConfigFromBytes(
+		payload, cluster.Namespace(), c.appName)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to load the expected configuration!`,
+			err,
+		), nil
+	}
+	if env := ctr.GetString(EnvironmentArg, ""); env != "" {
+		if err = expected.ApplyEnvironment(env); err != nil {
+			return mcp.NewToolResultErrorFromErr(`
+Unable to apply the environment overlay on the expected configuration!`,
+				err,
+			), nil
+		}
+	}
+
+	drift, err := c.cm.Drift(ctx, expected)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to compare the configurations!`,
+			err,
+		), nil
+	}
+	if len(drift.Changes) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf(`
+No drift, the %s cluster configuration matches the expected configuration.`,
+			c.appName,
+		)), nil
+	}
+	var b strings.Builder
+	for _, change := range drift.Changes {
+		fmt.Fprintf(&b, "- %s: expected %v, cluster %v\n",
+			change.Path, change.Local, change.Cluster)
+	}
+	return mcp.NewToolResultText(fmt.Sprintf(`
+The %s cluster configuration drifted, %d field(s) differ from the expected
+configuration:
+
+%s
+Use the %q tool, or "config reconcile", to apply the expected values.`,
+		c.appName,
+		len(drift.Changes),
+		b.String(),
+		c.appName+configSetSuffix,
+	)), nil
+}
+
+// settingSchema returns the JSON schema of the setting value.
+func settingSchema(s config.Setting) map[string]any {
+	schema := s.JSONSchema()
+	if s.Description != "" {
+		schema["description"] = fmt.Sprintf("%s: %s", s.Key, s.Description)
+	}
+	return schema
+}
+
+// configSettingsArgs returns the key and value arguments of the settings tool.
+// With settings registered by the application, the key is restricted to them
+// and the value described by each setting type, allowed values and default.
+func (c *ConfigTools) configSettingsArgs() []mcp.ToolOption {
+	keyDesc := fmt.Sprintf(`
+The key in '.%s.settings' object to update, for instance "crc".`,
+		c.appName,
+	)
+	valueDesc := fmt.Sprintf(`
+The value for the informed key in '.%s.settings' object.`,
+		c.appName,
+	)
+	if len(c.settings) == 0 {
+		return []mcp.ToolOption{
+			mcp.WithString(KeyArg, mcp.Description(keyDesc)),
+			mcp.WithBoolean(ValueArg, mcp.Description(valueDesc)),
+		}
+	}
+
+	var known strings.Builder
+	schemas := make([]any, 0, len(c.settings))
+	for _, s := range c.settings {
+		fmt.Fprintf(&known, "\n  - %q (%s", s.Key, s.Type)
+		if len(s.Allowed) > 0 {
+			fmt.Fprintf(&known, ", one of %v", s.Allowed)
+		}
+		if s.Default != nil {
+			fmt.Fprintf(&known, ", default %v", s.Default)
+		}
+		fmt.Fprint(&known, ")")
+		if s.Description != "" {
+			fmt.Fprintf(&known, ": %s", s.Description)
+		}
+		schemas = append(schemas, settingSchema(s))
+	}
+	return []mcp.ToolOption{
+		mcp.WithString(
+			KeyArg,
+			mcp.Description(keyDesc),
+			mcp.Enum(c.settings.Keys()...),
+		),
+		mcp.WithAny(
+			ValueArg,
+			mcp.Description(valueDesc+
+				" The value type depends on the key, the known settings are:"+
+				known.String()),
+			func(schema map[string]any) {
+				schema["anyOf"] = schemas
+			},
+		),
+	}
+}
+
 // Init registers the ConfigTools on the provided MCP server instance.
 func (c *ConfigTools) Init(s *server.MCPServer) {
 	s.AddTools([]server.ServerTool{{
@@ -453,8 +896,25 @@ exists yet. Use the default configuration as the reference to create a new %s
 configuration for the cluster.`,
 				c.appName, c.appName,
 			)),
+			mcp.WithString(
+				OutputArg,
+				mcp.Description(`
+Output format of the existing configuration, "json", "yaml" or "table". JSON and
+YAML wrap the configuration, or the products, as {"items": [...]}. By default
+the raw configuration is returned.`,
+				),
+				mcp.Enum(printer.OutputTable, printer.OutputJSON, printer.OutputYAML),
+			),
+			mcp.WithArray(
+				ProductsArg,
+				mcp.Description(`
+Only show the informed product names, instead of the whole configuration.`,
+				),
+				mcp.WithStringItems(),
+			),
+			withPagination(),
 		),
-		Handler: c.getHandler,
+		Handler: c.pager.Handler(c.getHandler),
 	}, {
 		Tool: mcp.NewTool(
 			c.appName+configInitSuffix,
@@ -471,33 +931,38 @@ The main namespace for %s ('.%s.namespace'), where services will be deployed by
 				)),
 				mcp.DefaultString(c.defaultCfg.Namespace()),
 			),
+			mcp.WithString(
+				EnvironmentArg,
+				mcp.Description(fmt.Sprintf(`
+The environment overlay applied to the default configuration, one of the names
+on '.%s.environments'. Optional, by default no overlay is applied.`,
+					c.appName,
+				)),
+			),
+			mcp.WithBoolean(
+				DiscoverArg,
+				mcp.Description(`
+Inspects the cluster, capacity, GPUs, storage classes, OpenShift Data Foundation
+and ingress domain, to pre-populate the configuration defaults for it. Optional,
+by default the cluster isn't inspected.`,
+				),
+				mcp.DefaultBool(false),
+			),
 		),
 		Handler: c.initHandler,
 	}, {
 		Tool: mcp.NewTool(
 			c.appName+configSettingsSuffix,
-			mcp.WithDescription(fmt.Sprintf(`
+			append([]mcp.ToolOption{
+				mcp.WithDescription(fmt.Sprintf(`
 Modifies the top level settings, '.%s.settings' in the configuration. It defines
 the global settings for the installer applied to all products. Use the tool %q to
 inspect the configuration's '.%s.settings' attributes and their current values,
 pay attention to the data type of the values, and make sure they are compatible
 with the expected types.`,
-				c.appName, c.appName+configGetSuffix, c.appName,
-			)),
-			mcp.WithString(
-				KeyArg,
-				mcp.Description(fmt.Sprintf(`
-The key in '.%s.settings' object to update, for instance "crc".`,
-					c.appName,
+					c.appName, c.appName+configGetSuffix, c.appName,
 				)),
-			),
-			mcp.WithBoolean(
-				ValueArg,
-				mcp.Description(fmt.Sprintf(`
-The value for the informed key in '.%s.settings' object.`,
-					c.appName,
-				)),
-			),
+			}, c.configSettingsArgs()...)...,
 		),
 		Handler: c.configSettingsHandler,
 	}, {
@@ -551,7 +1016,9 @@ The namespace where the product components will take place.`,
 			c.appName+configProductPropertiesSuffix,
 			mcp.WithDescription(`
 Updates the properties of a given product, the product '.properties' attributes
-will be updated using the informed object.`,
+will be updated using the informed object. When the product chart declares a
+properties schema, the resulting properties must match it: the types, required
+properties and allowed values.`,
 			),
 			mcp.WithString(
 				NameArg,
@@ -567,7 +1034,93 @@ The properties object with the attributes for the informed product name.`,
 			),
 		),
 		Handler: c.configProductPropertiesHandler,
+	}, {
+		Tool: mcp.NewTool(
+			c.appName+configProductBatchSuffix,
+			mcp.WithDescription(fmt.Sprintf(`
+Applies several product changes at once, atomically. Either all changes are
+applied to the cluster configuration or none is. Prefer this tool over %q, %q
+and %q when more than one product must change, the configuration is validated
+and the topology resolved only once.`,
+				c.appName+configProductEnabledSuffix,
+				c.appName+configProductNamespaceSuffix,
+				c.appName+configProductPropertiesSuffix,
+			)),
+			mcp.WithArray(
+				ProductsArg,
+				mcp.Description(`
+List of product changes, each entry must have the product "name" and any of the
+attributes to change: "enabled" (boolean), "namespace" (string) and
+"properties" (object, merged with existing properties).`,
+				),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						NameArg:       map[string]any{"type": "string"},
+						EnabledArg:    map[string]any{"type": "boolean"},
+						NamespaceArg:  map[string]any{"type": "string"},
+						PropertiesArg: map[string]any{"type": "object"},
+					},
+					"required": []string{NameArg},
+				}),
+			),
+		),
+		Handler: c.configProductBatchHandler,
+	}, {
+		Tool: mcp.NewTool(
+			c.appName+configSetSuffix,
+			mcp.WithDescription(fmt.Sprintf(`
+Changes a single value of the %s configuration, identified by its path relative
+to '.%s'. List items are selected by attribute or index, for instance
+"settings.crc" or "products[name=Product B].properties.replicas". Missing object
+keys are created, the configuration is validated before it's applied.`,
+				c.appName, c.appName,
+			)),
+			mcp.WithString(
+				PathArg,
+				mcp.Description(`
+The dotted configuration path to change.`,
+				),
+			),
+			mcp.WithString(
+				ValueArg,
+				mcp.Description(`
+The value, following YAML typing: "3" is a number, "true" a boolean, "[a, b]" a
+list and "{key: value}" an object. Quote it to keep a string, "'3'".`,
+				),
+			),
+		),
+		Handler: c.configSetHandler,
+	}, {
+		Tool: mcp.NewTool(
+			c.appName+configDriftSuffix,
+			mcp.WithDescription(fmt.Sprintf(`
+Reports the drift of the %s configuration in the cluster, changed out-of-band
+for instance with "kubectl edit", from the expected configuration. Each changed
+field is listed with its path, expected and cluster values. The cluster
+configuration is not changed.`,
+				c.appName,
+			)),
+			mcp.WithString(
+				ConfigArg,
+				mcp.Description(fmt.Sprintf(`
+The expected configuration YAML payload, under the '%s' root key. Optional, by
+default the installer's default configuration is expected.`,
+					c.appName,
+				)),
+			),
+			mcp.WithString(
+				EnvironmentArg,
+				mcp.Description(fmt.Sprintf(`
+The environment overlay applied to the expected configuration, one of the names
+on '.%s.environments'. Optional, by default no overlay is applied.`,
+					c.appName,
+				)),
+			),
+		),
+		Handler: c.configDriftHandler,
 	}}...)
+	s.AddTools(c.undoTool())
 }
 
 // NewConfigTools instantiates a new ConfigTools.
@@ -577,10 +1130,12 @@ func NewConfigTools(
 	cfs *chartfs.ChartFS,
 	kube k8s.Interface,
 	cm *config.ConfigMapManager,
+	tb *resolver.TopologyBuilder,
 ) (*ConfigTools, error) {
 	// Loading the default configuration to serve as a reference for MCP tools.
 	defaultCfg, err := config.NewConfigDefault(
-		cfs, appCtx.Namespace, appCtx.IdentifierName())
+		cfs, appCtx.Namespace, appCtx.IdentifierName(),
+		config.WithDefaults(config.Defaults(appCtx.ConfigDefaults)))
 	if err != nil {
 		return nil, err
 	}
@@ -591,7 +1146,12 @@ func NewConfigTools(
 		cfs:        cfs,
 		kube:       kube,
 		cm:         cm,
+		tb:         tb,
+		settings:   appCtx.Settings,
+		discovery:  appCtx.DiscoveryRules,
 		defaultCfg: defaultCfg,
+		history:    newConfigHistory(),
+		pager:      NewPager(),
 	}
 	return c, nil
 }
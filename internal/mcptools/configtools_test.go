@@ -0,0 +1,54 @@
+package mcptools
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	o "github.com/onsi/gomega"
+)
+
+func TestConfigProductBatchHandler(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	cfg, err := config.NewConfigFromFile(
+		chartfs.New(os.DirFS("../../test")),
+		"config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+	m := config.NewConfigMapManager(newClusterKube(), "helmet-ex")
+	g.Expect(m.Create(ctx, cfg)).To(o.Succeed())
+	c := &ConfigTools{appName: "helmet-ex", cm: m, history: newConfigHistory()}
+
+	resourceVersion := func() string {
+		cm, err := m.GetConfigMap(ctx)
+		g.Expect(err).To(o.Succeed())
+		return cm.GetResourceVersion()
+	}
+	before := resourceVersion()
+
+	// The first entry is valid, the second fails, none is applied.
+	ctr := mcp.CallToolRequest{}
+	ctr.Params.Arguments = map[string]any{ProductsArg: []any{
+		map[string]any{NameArg: "Product A", EnabledArg: false},
+		map[string]any{NameArg: "Product B", EnabledArg: "no"},
+	}}
+	res, err := c.configProductBatchHandler(ctx, ctr)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(res.IsError).To(o.BeTrue())
+	g.Expect(res.Content[0].(mcp.TextContent).Text).To(o.ContainSubstring(
+		"the cluster configuration is unchanged"))
+
+	g.Expect(resourceVersion()).To(o.Equal(before))
+	current, err := m.GetConfig(ctx)
+	g.Expect(err).To(o.Succeed())
+	for _, name := range []string{"Product A", "Product B"} {
+		spec, err := current.GetProduct(name)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(spec.Enabled).To(o.BeTrue())
+	}
+}
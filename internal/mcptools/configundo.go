@@ -0,0 +1,268 @@
+package mcptools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// configUndoSuffix reverts the last configuration changes of the session suffix.
+const configUndoSuffix = "_config_undo"
+
+// CountArg the number of changes to revert.
+const CountArg = "count"
+
+// configChange a configuration change applied by a config tool, the previous
+// configuration and the ConfigMap version written by the change.
+type configChange struct {
+	tool            string         // tool name
+	time            time.Time      // when the change was applied
+	previous        *config.Config // configuration before the change
+	resourceVersion string         // ConfigMap version after the change
+}
+
+// configHistory the configuration changes applied on each MCP session, oldest
+// first, and the configuration mutation sequence number last observed by each
+// session, by session ID.
+type configHistory struct {
+	mu       sync.Mutex
+	changes  map[string][]configChange
+	observed map[string]int64
+}
+
+// newConfigHistory instantiates an empty configHistory.
+func newConfigHistory() *configHistory {
+	return &configHistory{
+		changes:  map[string][]configChange{},
+		observed: map[string]int64{},
+	}
+}
+
+// sessionID returns the MCP client session ID, empty without a session.
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// record appends the change to the session history.
+func (h *configHistory) record(session string, change configChange) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.changes[session] = append(h.changes[session], change)
+}
+
+// last returns the last count changes of the session, oldest first.
+func (h *configHistory) last(session string, count int) ([]configChange, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	changes := h.changes[session]
+	if count < 1 || count > len(changes) {
+		return nil, fmt.Errorf(
+			"can't revert %d changes, the session applied %d", count, len(changes))
+	}
+	return slices.Clone(changes[len(changes)-count:]), nil
+}
+
+// observe records the configuration mutation sequence number read, or written,
+// by the session.
+func (h *configHistory) observe(session string, sequence int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.observed[session] = sequence
+}
+
+// current asserts the configuration is still on the mutation the session last
+// observed, otherwise it was changed by another actor, a user or another
+// session, and the session's view is stale. Sessions yet to observe the
+// configuration are not checked.
+func (h *configHistory) current(session string, cfg *config.Config) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	observed, ok := h.observed[session]
+	if !ok || observed == cfg.Sequence() {
+		return nil
+	}
+	return fmt.Errorf("%w: the session last observed mutation #%d, the "+
+		"configuration is on mutation #%d, applied by %q", config.ErrStaleConfig,
+		observed, cfg.Sequence(), cfg.MutatedBy())
+}
+
+// drop removes the last count changes of the session, reverted to the informed
+// ConfigMap version. The configuration is back to the state written by the
+// remaining last change, so it can be reverted from the new version.
+func (h *configHistory) drop(session string, count int, resourceVersion string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	changes := h.changes[session]
+	changes = changes[:max(len(changes)-count, 0)]
+	if len(changes) > 0 {
+		changes[len(changes)-1].resourceVersion = resourceVersion
+	}
+	h.changes[session] = changes
+}
+
+// update persists the configuration in the cluster, recording the change on the
+// session history for the informed tool, see configUndoHandler. Changes based on
+// a stale view of the configuration, mutated after the session last observed
+// it, are refused with ErrStaleConfig.
+func (c *ConfigTools) update(
+	ctx context.Context,
+	tool string,
+	cfg *config.Config,
+) error {
+	session := sessionID(ctx)
+	if err := c.history.current(session, cfg); err != nil {
+		return err
+	}
+	previous, _, err := c.cm.GetConfigVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if err = c.cm.Update(ctx, cfg); err != nil {
+		return err
+	}
+	cm, err := c.cm.GetConfigMap(ctx)
+	if err != nil {
+		return err
+	}
+	c.history.record(session, configChange{
+		tool:            c.appName + tool,
+		time:            time.Now(),
+		previous:        previous,
+		resourceVersion: cm.GetResourceVersion(),
+	})
+	c.history.observe(session, cfg.Sequence())
+	return nil
+}
+
+// updateError returns the tool error for a failed configuration update, telling
+// apart the configuration modified concurrently by another actor, and after
+// the session last observed it.
+func (c *ConfigTools) updateError(err error) *mcp.CallToolResult {
+	if errors.Is(err, config.ErrStaleConfig) {
+		return mcp.NewToolResultErrorf(`
+The cluster configuration was modified by another actor, a user on the command
+line or another session, after this session last read it: %s.
+
+The change is not applied. Refresh the configuration with %q, review whether
+the change still makes sense on top of it, and retry.`,
+			err,
+			c.appName+configGetSuffix,
+		)
+	}
+	if errors.Is(err, config.ErrConfigConflict) {
+		return mcp.NewToolResultErrorf(`
+The cluster configuration was modified by another actor, a user or another
+session, after it was read for this change. The change is not applied, use %q
+to inspect the current configuration, and retry the change on top of it.`,
+			c.appName+configGetSuffix,
+		)
+	}
+	return mcp.NewToolResultErrorFromErr(`
+Unable to update the cluster configuration!
+`,
+		err,
+	)
+}
+
+// configUndoHandler reverts the last changes applied by the config tools in the
+// current MCP session, restoring the configuration previous to them. Changes
+// applied outside the session meanwhile are never overwritten.
+func (c *ConfigTools) configUndoHandler(
+	ctx context.Context,
+	ctr mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	count := ctr.GetInt(CountArg, 1)
+	session := sessionID(ctx)
+	changes, err := c.history.last(session, count)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to revert the configuration changes of this session!`,
+			err,
+		), nil
+	}
+
+	// The configuration must still be the one written by the last change.
+	cm, err := c.cm.GetConfigMap(ctx)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to retrieve the cluster configuration!`,
+			err,
+		), nil
+	}
+	latest := changes[len(changes)-1]
+	if cm.GetResourceVersion() != latest.resourceVersion {
+		return mcp.NewToolResultErrorf(`
+The cluster configuration changed outside this session after the last change,
+reverting would overwrite it. The cluster configuration is unchanged, use %q
+to inspect it.`,
+			c.appName+configGetSuffix,
+		), nil
+	}
+	err = c.cm.UpdateVersion(ctx, changes[0].previous, latest.resourceVersion)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to revert the cluster configuration, it's unchanged!`,
+			err,
+		), nil
+	}
+	if cm, err = c.cm.GetConfigMap(ctx); err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+The cluster configuration is reverted, but it can't be retrieved again!`,
+			err,
+		), nil
+	}
+	c.history.drop(session, count, cm.GetResourceVersion())
+	c.history.observe(session, changes[0].previous.Sequence())
+
+	reverted := make([]string, 0, len(changes))
+	for i := len(changes) - 1; i >= 0; i-- {
+		reverted = append(reverted, fmt.Sprintf("%s (%s)",
+			changes[i].tool, changes[i].time.Format(time.RFC3339)))
+	}
+	return mcp.NewToolResultText(fmt.Sprintf(`
+Reverted the last %d configuration changes of this session, most recent first:
+%v
+
+The cluster configuration is restored to its state before them.`,
+		count,
+		reverted,
+	)), nil
+}
+
+// undoTool returns the "config undo" tool.
+func (c *ConfigTools) undoTool() server.ServerTool {
+	return server.ServerTool{
+		Tool: mcp.NewTool(
+			c.appName+configUndoSuffix,
+			mcp.WithDescription(fmt.Sprintf(`
+Reverts the last configuration changes applied by the %s configuration tools
+in this session, restoring the cluster configuration to its state before them.
+Use it to recover from a bad change. The configuration created by %q can't be
+reverted, and when the configuration changed outside this session after the
+last change nothing is reverted.`,
+				c.appName,
+				c.appName+configInitSuffix,
+			)),
+			mcp.WithNumber(
+				CountArg,
+				mcp.Description(`
+The number of changes to revert, the most recent ones. Optional, by default
+only the last change is reverted.`,
+				),
+				mcp.Min(1),
+			),
+		),
+		Handler: c.configUndoHandler,
+	}
+}
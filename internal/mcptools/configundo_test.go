@@ -0,0 +1,181 @@
+package mcptools
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// clusterKube a fake cluster keeping the objects written, with a new resource
+// version on every write, k8s.FakeKube serves a new clientset on every call.
+type clusterKube struct {
+	*k8s.FakeKube
+	cs *fake.Clientset
+}
+
+func (c *clusterKube) ClientSet(string) (kubernetes.Interface, error) {
+	return c.cs, nil
+}
+
+func (c *clusterKube) CoreV1ClientSet(
+	string,
+) (corev1client.CoreV1Interface, error) {
+	return c.cs.CoreV1(), nil
+}
+
+func newClusterKube() *clusterKube {
+	cs := fake.NewSimpleClientset()
+	version := 0
+	bump := func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if cm, ok := action.(k8stesting.CreateAction).
+			GetObject().(*corev1.ConfigMap); ok {
+			version++
+			cm.ResourceVersion = strconv.Itoa(version)
+		}
+		return false, nil, nil
+	}
+	cs.PrependReactor("create", "configmaps", bump)
+	cs.PrependReactor("update", "configmaps", bump)
+	return &clusterKube{FakeKube: k8s.NewFakeKube(), cs: cs}
+}
+
+func TestConfigHistory(t *testing.T) {
+	g := o.NewWithT(t)
+	h := newConfigHistory()
+	change := func(tool string) configChange {
+		return configChange{tool: tool, time: time.Now()}
+	}
+
+	h.record("a", change("set"))
+	h.record("a", change("enable"))
+	h.record("b", change("set"))
+
+	changes, err := h.last("a", 2)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(changes).To(o.HaveLen(2))
+	g.Expect(changes[0].tool).To(o.Equal("set"))
+	g.Expect(changes[1].tool).To(o.Equal("enable"))
+
+	_, err = h.last("a", 3)
+	g.Expect(err).To(o.MatchError(o.ContainSubstring(
+		"can't revert 3 changes, the session applied 2")))
+	_, err = h.last("a", 0)
+	g.Expect(err).To(o.HaveOccurred())
+	_, err = h.last("c", 1)
+	g.Expect(err).To(o.HaveOccurred())
+
+	h.drop("a", 1, "7")
+	changes, err = h.last("a", 1)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(changes[0].tool).To(o.Equal("set"))
+	g.Expect(changes[0].resourceVersion).To(o.Equal("7"))
+	// Other sessions are untouched.
+	_, err = h.last("b", 1)
+	g.Expect(err).To(o.Succeed())
+	h.drop("a", 5, "8")
+	_, err = h.last("a", 1)
+	g.Expect(err).To(o.HaveOccurred())
+
+	t.Run("current", func(t *testing.T) {
+		g := o.NewWithT(t)
+		cfg, err := config.NewConfigFromFile(
+			chartfs.New(os.DirFS("../../test")),
+			"config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+
+		// Sessions yet to observe the configuration are not checked.
+		g.Expect(h.current("a", cfg)).To(o.Succeed())
+		h.observe("a", cfg.Sequence())
+		g.Expect(h.current("a", cfg)).To(o.Succeed())
+		h.observe("a", cfg.Sequence()+1)
+		g.Expect(h.current("a", cfg)).To(o.MatchError(config.ErrStaleConfig))
+	})
+}
+
+func TestConfigUndoHandler(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	newConfig := func() *config.Config {
+		cfg, err := config.NewConfigFromFile(
+			chartfs.New(os.DirFS("../../test")),
+			"config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		return cfg
+	}
+	m := config.NewConfigMapManager(newClusterKube(), "helmet-ex")
+	g.Expect(m.Create(ctx, newConfig())).To(o.Succeed())
+	c := &ConfigTools{appName: "helmet-ex", cm: m, history: newConfigHistory()}
+
+	// set applies a change through the config tools, on the session.
+	set := func(value bool) {
+		cfg, err := m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cfg.SetPath("settings.crc", value)).To(o.Succeed())
+		g.Expect(c.update(ctx, configSettingsSuffix, cfg)).To(o.Succeed())
+	}
+	crc := func() any {
+		cfg, err := m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		return cfg.Installer.Settings["crc"]
+	}
+	undo := func(count int) (string, bool) {
+		ctr := mcp.CallToolRequest{}
+		ctr.Params.Arguments = map[string]any{CountArg: count}
+		res, err := c.configUndoHandler(ctx, ctr)
+		g.Expect(err).To(o.Succeed())
+		return res.Content[0].(mcp.TextContent).Text, res.IsError
+	}
+
+	t.Run("changed outside the session", func(t *testing.T) {
+		g := o.NewWithT(t)
+		set(true)
+		cfg, err := m.GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(cfg.SetPath("settings.crc", false)).To(o.Succeed())
+		g.Expect(m.Update(ctx, cfg)).To(o.Succeed())
+
+		text, isError := undo(1)
+		g.Expect(isError).To(o.BeTrue())
+		g.Expect(text).To(o.ContainSubstring("changed outside this session"))
+		g.Expect(crc()).To(o.Equal(false))
+
+		// The session refreshes its view, as the config get tool does.
+		c.history.drop("", 1, "")
+		c.history.observe("", cfg.Sequence())
+	})
+
+	t.Run("reverted", func(t *testing.T) {
+		g := o.NewWithT(t)
+		before := crc()
+		set(true)
+		set(false)
+		set(true)
+
+		_, isError := undo(2)
+		g.Expect(isError).To(o.BeFalse())
+		g.Expect(crc()).To(o.Equal(true))
+		_, isError = undo(1)
+		g.Expect(isError).To(o.BeFalse())
+		g.Expect(crc()).To(o.Equal(before))
+
+		text, isError := undo(1)
+		g.Expect(isError).To(o.BeTrue())
+		g.Expect(text).To(o.ContainSubstring("the session applied 0"))
+	})
+}
@@ -53,3 +53,8 @@ func NewMCPToolsContext(
 //
 //nolint:revive
 type MCPToolsBuilder func(MCPToolsContext) ([]Interface, error)
+
+// ToolFilter decides how a registered tool is exposed by the MCP server. It
+// receives the tool name, including the application prefix, and returns the
+// name to expose it with; an empty name hides the tool.
+type ToolFilter func(name string) string
@@ -0,0 +1,189 @@
+package mcptools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Instructions generates the MCP server instructions by inspecting the actual
+// capabilities of the installer, the registered tools, integration modules and
+// product catalog, and the current installer phase in the cluster. The static
+// instructions are used as preamble.
+type Instructions struct {
+	appName string                    // application name
+	base    string                    // static instructions
+	cfs     *chartfs.ChartFS          // embedded filesystem
+	cm      *config.ConfigMapManager  // cluster configuration
+	tb      *resolver.TopologyBuilder // topology builder
+	job     *installer.Job            // cluster deployment job
+	im      *integrations.Manager     // integrations manager
+
+	defaults config.Defaults // application configuration defaults
+}
+
+// firstLine returns the first non-empty line of the informed text.
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// statusSection describes the current installer phase.
+func (i *Instructions) statusSection(ctx context.Context, b *strings.Builder) {
+	phase, err := getInstallerPhase(ctx, i.cm, i.tb, i.job)
+	fmt.Fprintf(b, "### Current Status\n\nThe installer is in the %q phase", phase)
+	if err != nil && !errors.Is(err, config.ErrConfigMapNotFound) {
+		fmt.Fprintf(b, ", reason: %s", firstLine(err.Error()))
+	}
+	fmt.Fprintf(b, `. The phase reflects the moment the session started, use the
+%q tool to refresh it.
+
+`,
+		i.appName+statusSuffix,
+	)
+}
+
+// toolsSection lists the registered tools.
+func (i *Instructions) toolsSection(s *server.MCPServer, b *strings.Builder) {
+	tools := s.ListTools()
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	b.WriteString("### Tools\n\n")
+	for _, name := range names {
+		fmt.Fprintf(b, "- `%s`: %s\n",
+			name, firstLine(tools[name].Tool.Description))
+	}
+	b.WriteString("\n")
+}
+
+// integrationsSection lists the integration modules, and the ones configured
+// in the cluster.
+func (i *Instructions) integrationsSection(
+	ctx context.Context,
+	cfg *config.Config,
+	b *strings.Builder,
+) {
+	names := i.im.IntegrationNames()
+	slices.Sort(names)
+	fmt.Fprintf(b, "### Integrations\n\nAvailable integration modules: `%s`.\n",
+		strings.Join(names, "`, `"))
+	if cfg != nil {
+		configured, err := i.im.ConfiguredIntegrations(ctx, cfg)
+		if err == nil && len(configured) > 0 {
+			slices.Sort(configured)
+			fmt.Fprintf(b, "Configured in the cluster: `%s`.\n",
+				strings.Join(configured, "`, `"))
+		}
+	}
+	b.WriteString("\n")
+}
+
+// productsSection describes the product catalog, using the cluster
+// configuration when available, or the installer's default.
+func (i *Instructions) productsSection(cfg *config.Config, b *strings.Builder) {
+	source := "cluster"
+	if cfg == nil {
+		var err error
+		cfg, err = config.NewConfigDefault(i.cfs, "", i.appName,
+			config.WithDefaults(i.defaults))
+		if err != nil {
+			return
+		}
+		source = "default"
+	}
+
+	fmt.Fprintf(b, `### Products
+
+Products in the %s configuration:
+
+| Product | Enabled | Namespace | Integrations Provided | Integrations Required |
+|---------|---------|-----------|-----------------------|-----------------------|
+`,
+		source,
+	)
+	for _, p := range cfg.Installer.Products {
+		var provided, required string
+		d, err := i.tb.GetCollection().GetProductDependency(p.Name)
+		if err == nil {
+			provided = strings.Join(d.IntegrationsProvided(), ", ")
+			required = d.IntegrationsRequired()
+		}
+		fmt.Fprintf(b, "| %s | %v | %s | %s | %s |\n",
+			p.Name, p.Enabled, p.GetNamespace(), provided, required)
+	}
+	b.WriteString("\n")
+}
+
+// Generate returns the instructions, the static preamble followed by the live
+// capabilities of the installer.
+func (i *Instructions) Generate(ctx context.Context, s *server.MCPServer) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(i.base))
+	b.WriteString(`
+
+## Live Capabilities
+
+The section below is generated by the installer when the session starts, it
+reflects the tools, integrations and products actually available.
+
+`)
+	cfg, err := i.cm.GetConfig(ctx)
+	if err != nil {
+		cfg = nil
+	}
+	i.statusSection(ctx, &b)
+	i.toolsSection(s, &b)
+	i.integrationsSection(ctx, cfg, &b)
+	i.productsSection(cfg, &b)
+	return b.String()
+}
+
+// NewInstructions instantiates the instructions generator, the informed base
+// instructions are used as preamble.
+func NewInstructions(toolsCtx MCPToolsContext, base string) (*Instructions, error) {
+	tb, err := resolver.NewTopologyBuilder(
+		toolsCtx.AppContext,
+		toolsCtx.Logger,
+		toolsCtx.ChartFS,
+		toolsCtx.IntegrationManager,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if base == "" {
+		base = fmt.Sprintf("# %s: Installer Assistant", toolsCtx.AppContext.Name)
+	}
+	cm := config.NewConfigMapManager(toolsCtx.Kube, toolsCtx.AppContext.Name)
+	cm.SetBackend(toolsCtx.AppContext.ConfigBackend)
+	cm.SetMigrations(toolsCtx.AppContext.ConfigMigrations)
+	cm.SetTransforms(config.Transforms(toolsCtx.AppContext.ConfigTransforms))
+	return &Instructions{
+		appName: toolsCtx.AppContext.IdentifierName(),
+		base:    base,
+		cfs:     toolsCtx.ChartFS,
+		cm:      cm,
+		tb:      tb,
+		job:     installer.NewJob(toolsCtx.AppContext, toolsCtx.Kube),
+		im:      toolsCtx.IntegrationManager,
+
+		defaults: config.Defaults(toolsCtx.AppContext.ConfigDefaults),
+	}, nil
+}
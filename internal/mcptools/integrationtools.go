@@ -2,23 +2,31 @@ package mcptools
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
 	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 type IntegrationTools struct {
-	appName        string                   // application name
-	cliName        string                   // original name for CLI
-	integrationCmd *cobra.Command           // integration subcommand
-	cm             *config.ConfigMapManager // configuration manager
-	im             *integrations.Manager    // integrations manager
+	appName        string                    // application name
+	cliName        string                    // original name for CLI
+	integrationCmd *cobra.Command            // integration subcommand
+	cm             *config.ConfigMapManager  // configuration manager
+	im             *integrations.Manager     // integrations manager
+	tb             *resolver.TopologyBuilder // charts collection
+	pager          *Pager                    // paginates the descriptions
 }
 
 const (
@@ -28,6 +36,11 @@ const (
 	integrationScaffoldSuffix = "_integration_scaffold"
 	// integrationStatusSuffix checks if integrations are configured suffix.
 	integrationStatusSuffix = "_integration_status"
+	// integrationDescribeSuffix describes the integrations capabilities suffix.
+	integrationDescribeSuffix = "_integration_describe"
+	// integrationVerifySuffix verifies the integrations against the providers
+	// suffix.
+	integrationVerifySuffix = "_integration_verify"
 )
 
 // Arguments for the integration tools.
@@ -35,6 +48,19 @@ const (
 	NamesArg = "names"
 )
 
+// integrationCmds returns the integration commands, one per integration, the
+// bundle commands, "export" and "import", aren't integrations.
+func (i *IntegrationTools) integrationCmds() []*cobra.Command {
+	names := i.im.IntegrationNames()
+	cmds := []*cobra.Command{}
+	for _, sc := range i.integrationCmd.Commands() {
+		if slices.Contains(names, sc.Name()) {
+			cmds = append(cmds, sc)
+		}
+	}
+	return cmds
+}
+
 // listHandler generates a formatted string listing all available integration
 // commands. It iterates through the registered subcommands of the integration
 // command and appends their names and short descriptions to a string builder,
@@ -46,7 +72,7 @@ func (i *IntegrationTools) listHandler(
 	var output strings.Builder
 	output.WriteString(fmt.Sprintf("# `%s` Integrations\n\n", i.appName))
 
-	for _, subCmd := range i.integrationCmd.Commands() {
+	for _, subCmd := range i.integrationCmds() {
 		output.WriteString(fmt.Sprintf("## `%s`\n\n%s\n\n",
 			subCmd.Name(),
 			subCmd.Short,
@@ -84,7 +110,7 @@ You must inform the %q argument, with the integration name(s)!`,
 	}
 
 	byName := map[string]*cobra.Command{}
-	for _, sc := range i.integrationCmd.Commands() {
+	for _, sc := range i.integrationCmds() {
 		byName[sc.Name()] = sc
 	}
 	var unknown []string
@@ -141,17 +167,221 @@ You must inform at least one integration name via the '%s' argument`,
 	var output strings.Builder
 	output.WriteString("# Integrations Status\n\n")
 
+	window, err := integrations.ExpiryWarning(cfg)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(
+			"Invalid token expiry warning window", err), nil
+	}
+	now := time.Now()
 	for _, name := range names {
-		if _, found := configuredMap[name]; found {
-			output.WriteString(fmt.Sprintf("- `%s`: Configured\n", name))
-		} else {
+		if _, found := configuredMap[name]; !found {
 			output.WriteString(fmt.Sprintf("- `%s`: Not Configured\n", name))
+			continue
+		}
+		expiresAt, err := i.im.Integration(
+			integrations.IntegrationName(name)).Expiry(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		status := "Configured"
+		if expiresAt != nil {
+			e := integrations.TokenExpiry{Integration: name, ExpiresAt: *expiresAt}
+			switch {
+			case e.Expired(now):
+				status = "Configured, token EXPIRED on " +
+					expiresAt.UTC().Format(time.RFC3339)
+			case expiresAt.Sub(now) <= window:
+				status = "Configured, token EXPIRING on " +
+					expiresAt.UTC().Format(time.RFC3339)
+			default:
+				status = "Configured, token expires on " +
+					expiresAt.UTC().Format(time.RFC3339)
+			}
 		}
+		output.WriteString(fmt.Sprintf("- `%s`: %s\n", name, status))
 	}
 
 	return mcp.NewToolResultText(output.String()), nil
 }
 
+// verifyHandler verifies the informed integrations, or all the configured,
+// connect and authenticate on their providers, reporting each integration
+// outcome.
+func (i *IntegrationTools) verifyHandler(
+	ctx context.Context,
+	ctr mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	cfg, err := i.cm.GetConfig(ctx)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(
+			"Unable to load cluster configuration", err), nil
+	}
+
+	names := ctr.GetStringSlice(NamesArg, []string{})
+	if len(names) == 0 {
+		if names, err = i.im.ConfiguredIntegrations(ctx, cfg); err != nil {
+			return nil, err
+		}
+	}
+	unknown := []string{}
+	known := i.im.IntegrationNames()
+	for _, name := range names {
+		if !slices.Contains(known, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return mcp.NewToolResultErrorf(
+			"Unknown integrations: %s. Use the tool %q to list them.",
+			strings.Join(unknown, ", "), i.appName+integrationListSuffix,
+		), nil
+	}
+	slices.Sort(names)
+
+	var output strings.Builder
+	output.WriteString("# Integrations Verification\n\n")
+	if len(names) == 0 {
+		output.WriteString("No integrations configured.\n")
+	}
+	for _, name := range names {
+		status := "Verified"
+		err := i.im.Integration(
+			integrations.IntegrationName(name)).Verify(ctx, cfg)
+		switch {
+		case errors.Is(err, integration.ErrNotConfigured):
+			status = "Not Configured"
+		case errors.Is(err, integration.ErrVerificationUnsupported):
+			status = "Verification not supported"
+		case err != nil:
+			status = "FAILED: " + err.Error()
+		}
+		output.WriteString(fmt.Sprintf("- `%s`: %s\n", name, status))
+	}
+
+	return mcp.NewToolResultText(output.String()), nil
+}
+
+// describeIntegration describes the integration subcommand flags, the
+// integration capabilities and the charts requiring it.
+func (i *IntegrationTools) describeIntegration(
+	sc *cobra.Command,
+	cel *resolver.CEL,
+) (string, error) {
+	var output strings.Builder
+	name := sc.Name()
+	wrapper := i.im.Integration(integrations.IntegrationName(name))
+
+	output.WriteString(fmt.Sprintf("## `%s`\n\n%s\n\nUsage: `%s %s`\n\n",
+		name, sc.Short, i.cliName, sc.UseLine()))
+
+	capabilities := []string{}
+	for _, c := range wrapper.Capabilities() {
+		capabilities = append(capabilities, string(c))
+	}
+	if len(capabilities) == 0 {
+		capabilities = append(capabilities, "none, the informed values are stored as is")
+	}
+	output.WriteString(fmt.Sprintf("Capabilities: %s\n\n",
+		strings.Join(capabilities, ", ")))
+
+	dependencies, err := i.tb.GetCollection().
+		GetDependenciesRequiringIntegration(cel, name)
+	if err != nil {
+		return "", err
+	}
+	requiredBy := []string{}
+	for _, d := range dependencies {
+		if product := d.ProductName(); product != "" {
+			requiredBy = append(requiredBy,
+				fmt.Sprintf("product %q (`%s`)", product, d.Name()))
+		} else {
+			requiredBy = append(requiredBy, fmt.Sprintf("`%s`", d.Name()))
+		}
+	}
+	if len(requiredBy) == 0 {
+		requiredBy = append(requiredBy, "no chart requires it")
+	}
+	output.WriteString(fmt.Sprintf("Required by: %s\n\n",
+		strings.Join(requiredBy, ", ")))
+
+	credential := wrapper.CredentialFlag()
+	output.WriteString("| Flag | Type | Required | Default | Description |\n")
+	output.WriteString("|------|------|----------|---------|-------------|\n")
+	sc.PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		required := "no"
+		if v, ok := f.Annotations[cobra.BashCompOneRequiredFlag]; ok &&
+			len(v) > 0 && v[0] == "true" {
+			required = "yes"
+		}
+		if _, ok := f.Annotations[integration.CredentialRequiredAnnotation]; ok {
+			required = "yes"
+		}
+		if f.Name == credential {
+			required += fmt.Sprintf(", credential, use `--%s-stdin`", f.Name)
+		}
+		def := f.DefValue
+		if def == "" || def == "[]" {
+			def = "-"
+		}
+		output.WriteString(fmt.Sprintf("| `--%s` | %s | %s | %s | %s |\n",
+			f.Name, f.Value.Type(), required, def,
+			strings.ReplaceAll(f.Usage, "|", `\|`)))
+	})
+	return output.String(), nil
+}
+
+// describeHandler describes the informed integrations, or all when no names
+// are informed: flags, capabilities and the charts requiring them.
+func (i *IntegrationTools) describeHandler(
+	_ context.Context,
+	ctr mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	names := ctr.GetStringSlice(NamesArg, []string{})
+	all := len(names) == 0
+	byName := map[string]*cobra.Command{}
+	for _, sc := range i.integrationCmds() {
+		byName[sc.Name()] = sc
+		if all {
+			names = append(names, sc.Name())
+		}
+	}
+	cel, err := resolver.NewCEL(i.im.IntegrationNames()...)
+	if err != nil {
+		return nil, err
+	}
+
+	var output strings.Builder
+	output.WriteString(fmt.Sprintf(`# %s Integrations Capabilities
+
+Each integration is configured by the "%s integration <name>" command, the
+flags marked as credential must be informed by the user, never by automated
+agents, see %q.`,
+		i.cliName, i.cliName, i.appName+integrationScaffoldSuffix,
+	))
+	var unknown []string
+	for _, name := range names {
+		sc, ok := byName[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		description, err := i.describeIntegration(sc, cel)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr(
+				"Unable to inspect the charts requiring the integration", err), nil
+		}
+		output.WriteString("\n\n")
+		output.WriteString(description)
+	}
+	if len(unknown) > 0 {
+		return mcp.NewToolResultErrorf(
+			"Unknown integration name(s): %s. Use %q to list valid names.",
+			strings.Join(unknown, ", "), i.appName+integrationListSuffix,
+		), nil
+	}
+	return mcp.NewToolResultText(output.String()), nil
+}
+
 // Init registers the integration management tools with the MCP server. These
 // tools allow users to list available integrations, scaffold their
 // configurations, and check their current status.
@@ -187,7 +417,8 @@ The missing integrations that are mandatory for deployment.`,
 		Tool: mcp.NewTool(
 			i.appName+integrationStatusSuffix,
 			mcp.WithDescription(`
-Detect whether the informed integration names are configured.`,
+Detect whether the informed integration names are configured, and when their
+tokens expire, when known. Expired or expiring tokens must be renewed.`,
 			),
 			mcp.WithArray(
 				NamesArg,
@@ -198,6 +429,46 @@ The integration names to check the status for.`,
 			),
 		),
 		Handler: i.integrationStatusHandler,
+	}, {
+		Tool: mcp.NewTool(
+			i.appName+integrationDescribeSuffix,
+			mcp.WithDescription(fmt.Sprintf(`
+Describe the %s integrations: the '%s integration <name>' flags, which are
+required and which hold credentials, whether the integration verifies the
+credentials, provisions resources on the provider or discovers the token expiry
+for rotation, and which products require it. Without names all integrations are
+described.`,
+				i.cliName, i.cliName,
+			)),
+			mcp.WithArray(
+				NamesArg,
+				mcp.Description(`
+The integration names to describe, optional.`,
+				),
+				mcp.WithStringItems(),
+			),
+			withPagination(),
+		),
+		Handler: i.pager.Handler(i.describeHandler),
+	}, {
+		Tool: mcp.NewTool(
+			i.appName+integrationVerifySuffix,
+			mcp.WithDescription(fmt.Sprintf(`
+Verify the informed integrations connect and authenticate on their providers,
+using the credentials stored, like '%s integration <name> --verify'. Without
+names all configured integrations are verified. Failed integrations must be
+recreated with valid credentials.`,
+				i.cliName,
+			)),
+			mcp.WithArray(
+				NamesArg,
+				mcp.Description(`
+The integration names to verify, optional.`,
+				),
+				mcp.WithStringItems(),
+			),
+		),
+		Handler: i.verifyHandler,
 	}}...)
 }
 
@@ -207,6 +478,7 @@ func NewIntegrationTools(
 	integrationCmd *cobra.Command,
 	cm *config.ConfigMapManager,
 	im *integrations.Manager,
+	tb *resolver.TopologyBuilder,
 ) *IntegrationTools {
 	return &IntegrationTools{
 		appName:        appName,
@@ -214,5 +486,7 @@ func NewIntegrationTools(
 		integrationCmd: integrationCmd,
 		cm:             cm,
 		im:             im,
+		tb:             tb,
+		pager:          NewPager(),
 	}
 }
@@ -0,0 +1,196 @@
+package mcptools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/logs"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// LogsTool a MCP tool reading the logs of the product workloads, capped in size
+// to fit the conversation.
+type LogsTool struct {
+	appName string                    // application name
+	logger  *slog.Logger              // application logger
+	flags   *flags.Flags              // global flags
+	kube    k8s.Interface             // kubernetes client
+	cm      *config.ConfigMapManager  // cluster configuration
+	tb      *resolver.TopologyBuilder // topology builder
+}
+
+var _ Interface = &LogsTool{}
+
+const (
+	// logsSuffix reads the product workloads logs suffix.
+	logsSuffix = "_logs"
+
+	// ContainerArg the container name.
+	ContainerArg = "container"
+	// SinceArg the logs age, as a duration.
+	SinceArg = "since"
+	// TailArg the last lines per container.
+	TailArg = "tail"
+
+	// DefaultLogsTail the last lines read per container, by default.
+	DefaultLogsTail = 100
+	// MaxLogsSize the logs size cap, in bytes, shared by the containers.
+	MaxLogsSize = 32 * 1024
+	// minContainerLogsSize the smallest size read per container, in bytes.
+	minContainerLogsSize = 1024
+)
+
+// logsHandler resolves the product workloads pods, and returns their latest
+// logs, the size cap shared by the containers.
+func (l *LogsTool) logsHandler(
+	ctx context.Context,
+	ctr mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	name := ctr.GetString(NameArg, "")
+	if name == "" {
+		return mcp.NewToolResultError(`
+You must inform the Red Hat product name`,
+		), nil
+	}
+	opts := logs.Options{
+		Container: ctr.GetString(ContainerArg, ""),
+		Tail:      int64(max(ctr.GetInt(TailArg, DefaultLogsTail), 1)),
+	}
+	if since := ctr.GetString(SinceArg, ""); since != "" {
+		d, err := time.ParseDuration(since)
+		if err != nil || d < 0 {
+			return mcp.NewToolResultError(fmt.Sprintf(`
+Invalid %q duration %q, inform a positive duration like "10m" or "1h".`,
+				SinceArg, since,
+			)), nil
+		}
+		opts.Since = d
+	}
+
+	cfg, err := l.cm.GetConfig(ctx)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to read the cluster configuration, the products are not deployed.`,
+			err,
+		), nil
+	}
+	topology := resolver.NewTopology()
+	err = resolver.NewResolver(cfg, l.tb.GetCollection(), topology).Resolve()
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to resolve the dependency topology.`,
+			err,
+		), nil
+	}
+	dep, workloads, err := logs.Workloads(
+		l.logger, l.flags, l.kube, topology, name)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(fmt.Sprintf(`
+Unable to find the workloads of the product %q, use the tool %q to check
+whether it's deployed.`,
+			name, l.appName+statusSuffix,
+		),
+			err,
+		), nil
+	}
+
+	reader := logs.NewLogs(l.logger, l.kube, opts)
+	sources, err := reader.Sources(ctx, workloads)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr(fmt.Sprintf(`
+Unable to find the pods of the product %q on namespace %q.`,
+			name, dep.Namespace(),
+		),
+			err,
+		), nil
+	}
+	// The size cap is shared by the containers, each one reading its share.
+	opts.Limit = int64(max(MaxLogsSize/len(sources), minContainerLogsSize))
+	reader = logs.NewLogs(l.logger, l.kube, opts)
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "# Logs of %q, dependency %q on namespace %q\n\n",
+		name, dep.Name(), dep.Namespace())
+	if err = reader.Write(ctx, &out, sources); err != nil {
+		return mcp.NewToolResultErrorFromErr(`
+Unable to read the product workloads logs.`,
+			err,
+		), nil
+	}
+	if out.Len() > MaxLogsSize {
+		out.Truncate(MaxLogsSize)
+		fmt.Fprintf(&out, "\n\n(Truncated to %d bytes, inform %q or %q to "+
+			"narrow the logs.)", MaxLogsSize, ContainerArg, TailArg)
+	}
+	return mcp.NewToolResultText(out.String()), nil
+}
+
+func (l *LogsTool) Init(s *server.MCPServer) {
+	s.AddTools([]server.ServerTool{{
+		Tool: mcp.NewTool(
+			l.appName+logsSuffix,
+			mcp.WithDescription(fmt.Sprintf(`
+Retrieve the latest logs of the informed product workloads, the pods of its
+Deployments, StatefulSets and DaemonSets, to troubleshoot the installation. Each
+line is prefixed by "[pod/container]", the output is capped to %d bytes, shared
+by the containers.`,
+				MaxLogsSize,
+			)),
+			mcp.WithString(
+				NameArg,
+				mcp.Description(`
+The name of the Red Hat product, or dependency, to retrieve the logs.`,
+				),
+				mcp.Required(),
+			),
+			mcp.WithString(
+				ContainerArg,
+				mcp.Description(`
+The container name, every container of the pods by default.`,
+				),
+			),
+			mcp.WithString(
+				SinceArg,
+				mcp.Description(`
+Only the logs newer than the duration, like "10m" or "1h".`,
+				),
+			),
+			mcp.WithNumber(
+				TailArg,
+				mcp.Description(fmt.Sprintf(`
+The last lines per container, %d by default.`,
+					DefaultLogsTail,
+				)),
+				mcp.Min(1),
+			),
+		),
+		Handler: l.logsHandler,
+	}}...)
+}
+
+func NewLogsTool(
+	appName string,
+	logger *slog.Logger,
+	f *flags.Flags,
+	kube k8s.Interface,
+	cm *config.ConfigMapManager,
+	tb *resolver.TopologyBuilder,
+) *LogsTool {
+	return &LogsTool{
+		appName: appName,
+		logger:  logger,
+		flags:   f,
+		kube:    kube,
+		cm:      cm,
+		tb:      tb,
+	}
+}
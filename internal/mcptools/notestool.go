@@ -26,6 +26,7 @@ type NotesTool struct {
 	cm      *config.ConfigMapManager  // cluster configuration
 	tb      *resolver.TopologyBuilder // topology builder
 	job     *installer.Job            // cluster deployment job
+	pager   *Pager                    // paginates the notes
 }
 
 var _ Interface = &NotesTool{}
@@ -127,8 +128,9 @@ by this installer, from the informed product name.`,
 The name of the Red Hat product to retrieve connection information.`,
 				),
 			),
+			withPagination(),
 		),
-		Handler: n.notesHandler,
+		Handler: n.pager.Handler(n.notesHandler),
 	}}...)
 }
 
@@ -149,5 +151,6 @@ func NewNotesTool(
 		cm:      cm,
 		tb:      tb,
 		job:     job,
+		pager:   NewPager(),
 	}
 }
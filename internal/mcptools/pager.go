@@ -0,0 +1,165 @@
+package mcptools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// CursorArg the cursor of the next page of a paginated tool output.
+	CursorArg = "cursor"
+	// PageSizeArg the maximum page size, in bytes, of a paginated tool output.
+	PageSizeArg = "page_size"
+
+	// DefaultPageSize the default page size, in bytes.
+	DefaultPageSize = 16 * 1024
+	// minPageSize the smallest page size accepted, in bytes.
+	minPageSize = 1024
+	// maxSnapshots the tool outputs kept, the oldest cursors expire first.
+	maxSnapshots = 16
+)
+
+// Pager splits the large tool outputs in pages. The first call renders the
+// output, keeping it as a snapshot in memory when it exceeds the page size, the
+// following pages are read from the snapshot, by cursor, so the pages are
+// consistent even when the cluster state changes meanwhile.
+type Pager struct {
+	mu        sync.Mutex        // guards the snapshots
+	snapshots map[string]string // tool outputs by snapshot ID
+	order     []string          // snapshot IDs, oldest first
+}
+
+// withPagination declares the pagination arguments on the tool.
+func withPagination() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithString(
+			CursorArg,
+			mcp.Description(`
+Cursor of the next page, as returned by the previous call when the output is
+paginated. When informed, the other arguments are ignored.`,
+			),
+		)(t)
+		mcp.WithNumber(
+			PageSizeArg,
+			mcp.Description(fmt.Sprintf(`
+Maximum page size in bytes, %d by default. Larger outputs are split in pages,
+broken on line boundaries.`,
+				DefaultPageSize,
+			)),
+			mcp.Min(minPageSize),
+		)(t)
+	}
+}
+
+// pageSize returns the page size informed on the request.
+func pageSize(ctr mcp.CallToolRequest) int {
+	return max(ctr.GetInt(PageSizeArg, DefaultPageSize), minPageSize)
+}
+
+// store keeps the output snapshot, returning its ID.
+func (p *Pager) store(output string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	id := hex.EncodeToString(b)
+	if len(p.order) == maxSnapshots {
+		delete(p.snapshots, p.order[0])
+		p.order = p.order[1:]
+	}
+	p.snapshots[id] = output
+	p.order = append(p.order, id)
+	return id
+}
+
+// snapshot returns the output snapshot by ID.
+func (p *Pager) snapshot(id string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	output, ok := p.snapshots[id]
+	return output, ok
+}
+
+// page returns the output page starting on the offset, and the cursor of the
+// next page, empty on the last page.
+func (p *Pager) page(id, output string, offset, size int) *mcp.CallToolResult {
+	end := min(offset+size, len(output))
+	if end < len(output) {
+		// Breaking the page on the last line boundary, unless the line alone
+		// exceeds the page size.
+		if i := strings.LastIndexByte(output[offset:end], '\n'); i >= 0 {
+			end = offset + i + 1
+		}
+	}
+	text := output[offset:end]
+	if end == len(output) {
+		return mcp.NewToolResultText(fmt.Sprintf(
+			"%s\n---\nLast page, bytes %d-%d of %d.", text, offset, end, len(output)))
+	}
+	return mcp.NewToolResultText(fmt.Sprintf(`%s
+---
+Bytes %d-%d of %d. Call the tool again with %s %q to read the next page.`,
+		text, offset, end, len(output), CursorArg, id+":"+strconv.Itoa(end)))
+}
+
+// next returns the page of the informed cursor.
+func (p *Pager) next(cursor string, size int) *mcp.CallToolResult {
+	id, offsetStr, _ := strings.Cut(cursor, ":")
+	offset, err := strconv.Atoi(offsetStr)
+	output, ok := p.snapshot(id)
+	if !ok || err != nil || offset < 0 || offset >= len(output) {
+		return mcp.NewToolResultError(fmt.Sprintf(`
+The cursor %q is invalid or expired, call the tool again without %q to render
+the output from the first page.`,
+			cursor, CursorArg,
+		))
+	}
+	return p.page(id, output, offset, size)
+}
+
+// Handler paginates the tool handler output. Without cursor, the handler is
+// called, and its text output is returned as is when it fits a single page,
+// otherwise the first page is returned; with cursor, the next page is read
+// from the snapshot. Errors are never paginated.
+func (p *Pager) Handler(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(
+		ctx context.Context,
+		ctr mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		size := pageSize(ctr)
+		if cursor := ctr.GetString(CursorArg, ""); cursor != "" {
+			return p.next(cursor, size), nil
+		}
+		res, err := handler(ctx, ctr)
+		if err != nil || res == nil || res.IsError {
+			return res, err
+		}
+
+		var output strings.Builder
+		for _, content := range res.Content {
+			text, ok := content.(mcp.TextContent)
+			if !ok {
+				return res, nil
+			}
+			output.WriteString(text.Text)
+		}
+		if output.Len() <= size {
+			return res, nil
+		}
+		return p.page(p.store(output.String()), output.String(), 0, size), nil
+	}
+}
+
+// NewPager instantiates the pager.
+func NewPager() *Pager {
+	return &Pager{snapshots: map[string]string{}}
+}
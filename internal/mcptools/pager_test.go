@@ -0,0 +1,93 @@
+package mcptools
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	o "github.com/onsi/gomega"
+)
+
+// callPager calls the paginated handler with the informed arguments, returning
+// the result text.
+func callPager(
+	g *o.WithT,
+	handler server.ToolHandlerFunc,
+	args map[string]any,
+) (string, bool) {
+	ctr := mcp.CallToolRequest{}
+	ctr.Params.Arguments = args
+	res, err := handler(context.Background(), ctr)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(res.Content).To(o.HaveLen(1))
+	return res.Content[0].(mcp.TextContent).Text, res.IsError
+}
+
+func TestPager(t *testing.T) {
+	var output strings.Builder
+	for i := range 200 {
+		fmt.Fprintf(&output, "line %03d: %s\n", i, strings.Repeat("x", 40))
+	}
+	calls := 0
+	p := NewPager()
+	handler := p.Handler(func(
+		context.Context,
+		mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText(output.String()), nil
+	})
+	cursorRe := regexp.MustCompile(`cursor "([^"]+)"`)
+
+	t.Run("SinglePage", func(t *testing.T) {
+		g := o.NewWithT(t)
+		text, _ := callPager(g, handler, map[string]any{PageSizeArg: 64 * 1024})
+		g.Expect(text).To(o.Equal(output.String()))
+	})
+
+	t.Run("Pages", func(t *testing.T) {
+		g := o.NewWithT(t)
+		calls = 0
+		var pages strings.Builder
+		args := map[string]any{PageSizeArg: 4096}
+		for range 10 {
+			text, isErr := callPager(g, handler, args)
+			g.Expect(isErr).To(o.BeFalse())
+			page, footer, ok := strings.Cut(text, "\n---\n")
+			g.Expect(ok).To(o.BeTrue())
+			g.Expect(len(page)).To(o.BeNumerically("<=", 4096))
+			g.Expect(page).To(o.HaveSuffix("\n"))
+			pages.WriteString(page)
+
+			match := cursorRe.FindStringSubmatch(footer)
+			if match == nil {
+				g.Expect(footer).To(o.HavePrefix("Last page"))
+				break
+			}
+			args = map[string]any{PageSizeArg: 4096, CursorArg: match[1]}
+		}
+		g.Expect(pages.String()).To(o.Equal(output.String()))
+		g.Expect(calls).To(o.Equal(1))
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, isErr := callPager(g, handler, map[string]any{CursorArg: "unknown:0"})
+		g.Expect(isErr).To(o.BeTrue())
+	})
+
+	t.Run("ExpiredCursor", func(t *testing.T) {
+		g := o.NewWithT(t)
+		text, _ := callPager(g, handler, map[string]any{PageSizeArg: 4096})
+		cursor := cursorRe.FindStringSubmatch(text)[1]
+		for range maxSnapshots {
+			_, _ = callPager(g, handler, map[string]any{PageSizeArg: 4096})
+		}
+		_, isErr := callPager(g, handler, map[string]any{CursorArg: cursor})
+		g.Expect(isErr).To(o.BeTrue())
+	})
+}
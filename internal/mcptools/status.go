@@ -2,59 +2,145 @@ package mcptools
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/readiness"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 )
 
+// getInstallerPhase returns the installer phase, and the error explaining why
+// the installation is not further along. The error is used by the callers for
+// detailed messaging.
 func getInstallerPhase(
 	ctx context.Context,
 	cm *config.ConfigMapManager,
 	tb *resolver.TopologyBuilder,
 	job *installer.Job,
 ) (string, error) {
-	// Ensure the cluster is configured.
+	phase, err := readiness.NewReadiness(cm, tb, job).Phase(ctx)
+	return string(phase), err
+}
+
+// tokenExpiryWarnings returns the warnings about integration tokens expiring
+// within the configured window, empty when there are none. It's best effort,
+// the cluster configuration may not exist yet.
+func tokenExpiryWarnings(
+	ctx context.Context,
+	cm *config.ConfigMapManager,
+	im *integrations.Manager,
+) string {
 	cfg, err := cm.GetConfig(ctx)
 	if err != nil {
-		// If config is missing, we are in AwaitingConfigurationPhase.
-		// The specific error will be used by the caller for detailed messaging.
-		return AwaitingConfigurationPhase, err
+		return ""
 	}
+	now := time.Now()
+	expiring, err := im.ExpiringTokens(ctx, cfg, now)
+	if err != nil {
+		return fmt.Sprintf("\n\nUnable to check the integration tokens expiry: %s",
+			err.Error())
+	}
+	if len(expiring) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(`
+
+## Integration Tokens Expiry
 
-	// Given the cluster is configured, inspect the topology to ensure all
-	// dependencies and integrations are resolved.
-	if _, err = tb.Build(ctx, cfg); err != nil {
-		// If topology build fails, we are in AwaitingIntegrationsPhase.
-		// The specific resolver error will be used by the caller for detailed messaging.
-		return AwaitingIntegrationsPhase, err
+ATTENTION: The following integration tokens are expired, or about to expire. Warn
+the user to renew the tokens and re-create the integrations, with "--force",
+before the deployed products stop working:
+
+`)
+	for _, e := range expiring {
+		fmt.Fprintf(&b, "- %s\n", e.Describe(now))
 	}
+	return b.String()
+}
 
-	// Given integrations are in place, inspect the current state of the
-	// cluster deployment job.
-	jobState, err := job.GetState(ctx)
+// capacityRecommendations inspects the cluster capacity and returns the
+// recommendations of the application capacity rules, empty without rules. It's
+// best effort, inspection failures are reported instead.
+func capacityRecommendations(
+	ctx context.Context,
+	appName string,
+	kube k8s.Interface,
+	rules []api.CapacityRule,
+) string {
+	if len(rules) == 0 {
+		return ""
+	}
+	capacity, err := k8s.GetClusterCapacity(ctx, kube)
 	if err != nil {
-		// If job state cannot be determined, it's an operational error.
-		// Return InstallerErrorPhase with the original error.
-		return InstallerErrorPhase, err
-	}
-
-	// Map the job state to an installer phase.
-	switch jobState {
-	case installer.NotFound:
-		return ReadyToDeployPhase, nil
-	case installer.Deploying, installer.Failed:
-		// Both 'Deploying' and 'Failed' states indicate that the deployment
-		// process is active or has attempted to run, thus falling under
-		// the 'DeployingPhase' for overall status reporting.
-		return DeployingPhase, nil
-	case installer.Done:
-		return CompletedPhase, nil
-	default:
-		// Unrecognized installer state from s.job.GetState.
-		// This is also an operational error.
-		return InstallerErrorPhase,
-			errors.New("unknown installer job state reported by cluster")
+		return fmt.Sprintf("\n\nUnable to inspect the cluster capacity: %s",
+			err.Error())
+	}
+	var recommendations []string
+	for _, rule := range rules {
+		if r := rule(capacity); r != "" {
+			recommendations = append(recommendations, r)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n\n## Cluster Capacity\n\nThe cluster has %s.\n\n", capacity)
+	if len(recommendations) == 0 {
+		b.WriteString("No configuration changes are recommended for this cluster.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, `ATTENTION: Present the following recommendations to the user, and apply the
+ones accepted after creating the configuration with %q:
+
+`, appName+configInitSuffix)
+	for _, r := range recommendations {
+		fmt.Fprintf(&b, "- %s\n", r)
+	}
+	return b.String()
+}
+
+// productChecks runs the product verification checks registered by the
+// application, and returns their outcome, empty without checks. It's best
+// effort, the cluster configuration may be unavailable.
+func productChecks(
+	ctx context.Context,
+	cm *config.ConfigMapManager,
+	kube k8s.Interface,
+	checkers []api.CheckerModule,
+) string {
+	if len(checkers) == 0 {
+		return ""
+	}
+	cfg, err := cm.GetConfig(ctx)
+	if err != nil {
+		return fmt.Sprintf("\n\nUnable to run the product checks: %s", err.Error())
+	}
+	checks := readiness.RunChecks(ctx, kube, cfg, checkers)
+
+	var b strings.Builder
+	b.WriteString("\n\n## Product Checks\n\n")
+	if checks.Err() != nil {
+		b.WriteString(`ATTENTION: One or more product verification checks failed, the products may
+not work as expected. Present the failures to the user:
+
+`)
+	}
+	for _, c := range checks {
+		result := "passed"
+		if !c.Passed {
+			result = "failed"
+		}
+		fmt.Fprintf(&b, "- %s: %s", c.Name, result)
+		if c.Message != "" {
+			fmt.Fprintf(&b, ", %s", c.Message)
+		}
+		b.WriteString("\n")
 	}
+	return b.String()
 }
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -20,6 +23,11 @@ type StatusTool struct {
 	cm      *config.ConfigMapManager  // cluster configuration
 	tb      *resolver.TopologyBuilder // topology builder
 	job     *installer.Job            // cluster deployment job
+	im      *integrations.Manager     // integrations manager
+	kube    k8s.Interface             // kubernetes client
+	rules   []api.CapacityRule        // cluster capacity rules
+	checks  []api.CheckerModule       // product verification checks
+	pager   *Pager                    // paginates the status report
 }
 
 var _ Interface = &StatusTool{}
@@ -29,27 +37,43 @@ const (
 	statusSuffix = "_status"
 
 	// AwaitingConfigurationPhase first step, the cluster is not configured yet.
-	AwaitingConfigurationPhase = "AWAITING_CONFIGURATION"
+	AwaitingConfigurationPhase = string(api.PhaseAwaitingConfiguration)
 	// AwaitingIntegrationsPhase second step, the cluster doesn't have the
 	// required integrations configured yet.
-	AwaitingIntegrationsPhase = "AWAITING_INTEGRATIONS"
+	AwaitingIntegrationsPhase = string(api.PhaseAwaitingIntegrations)
 	// ReadyToDeployPhase third step, the cluster is ready to deploy. It's
 	// configured and has all required integrations in place.
-	ReadyToDeployPhase = "READY_TO_DEPLOY"
+	ReadyToDeployPhase = string(api.PhaseReadyToDeploy)
 	// DeployingPhase fourth step, the installer is currently deploying the
 	// dependencies, Helm charts.
-	DeployingPhase = "DEPLOYING"
+	DeployingPhase = string(api.PhaseDeploying)
 	// CompletedPhase final step, the installation process is complete, and the
 	// cluster is ready.
-	CompletedPhase = "COMPLETED"
+	CompletedPhase = string(api.PhaseCompleted)
 	// InstallerErrorPhase indicates an error occurred while trying to determine
 	// the installer's operational status (e.g., failed to get job state).
-	InstallerErrorPhase = "INSTALLER_ERROR"
+	InstallerErrorPhase = string(api.PhaseInstallerError)
 )
 
-// statusHandler shows the installer overall status by inspecting the cluster to
-// determine the current state of the installation.
+// statusHandler shows the installer overall status, followed by the warnings
+// about integration tokens about to expire.
 func (s *StatusTool) statusHandler(
+	ctx context.Context,
+	ctr mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	result, err := s.phaseHandler(ctx, ctr)
+	if err != nil || result.IsError {
+		return result, err
+	}
+	if warnings := tokenExpiryWarnings(ctx, s.cm, s.im); warnings != "" {
+		result.Content = append(result.Content, mcp.NewTextContent(warnings))
+	}
+	return result, nil
+}
+
+// phaseHandler shows the installer phase by inspecting the cluster to determine
+// the current state of the installation.
+func (s *StatusTool) phaseHandler(
 	ctx context.Context,
 	_ mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
@@ -64,8 +88,9 @@ func (s *StatusTool) statusHandler(
 	switch phase {
 	case AwaitingConfigurationPhase:
 		return mcp.NewToolResultText(fmt.Sprintf(
-			"# Current Status: %q\n\n%s",
+			"# Current Status: %q\n\n%s%s",
 			phase, missingClusterConfigErrorFromErr(s.appName, err),
+			capacityRecommendations(ctx, s.appName, s.kube, s.rules),
 		)), nil
 	case AwaitingIntegrationsPhase:
 		switch {
@@ -163,8 +188,9 @@ The %s components have been deployed successfully. You can use the following
 command to inspect the installation logs and get initial information for each
 product deployed:
 
-> %s`,
+> %s%s`,
 			phase, s.appName, logsCmdEx,
+			productChecks(ctx, s.cm, s.kube, s.checks),
 		)), nil
 	case InstallerErrorPhase:
 		// Indicates an operational error during job state determination.
@@ -181,10 +207,14 @@ func (s *StatusTool) Init(mcpServer *server.MCPServer) {
 			s.appName+statusSuffix,
 			mcp.WithDescription(`
 Reports the overall installer status, the first tool to be called to identify the
-installer status in the cluster and define the next tool to call.
+installer status in the cluster and define the next tool to call. Integration
+tokens expired, or about to expire, are reported as well. While awaiting the
+configuration, the cluster capacity is analyzed with recommendations for it.
+Once the installation is complete, the product verification checks are run.
 			`),
+			withPagination(),
 		),
-		Handler: s.statusHandler,
+		Handler: s.pager.Handler(s.statusHandler),
 	}}...)
 }
 
@@ -194,11 +224,20 @@ func NewStatusTool(
 	cm *config.ConfigMapManager,
 	tb *resolver.TopologyBuilder,
 	job *installer.Job,
+	im *integrations.Manager,
+	kube k8s.Interface,
+	rules []api.CapacityRule,
+	checks []api.CheckerModule,
 ) *StatusTool {
 	return &StatusTool{
 		appName: appName,
 		cm:      cm,
 		tb:      tb,
 		job:     job,
+		im:      im,
+		kube:    kube,
+		rules:   rules,
+		checks:  checks,
+		pager:   NewPager(),
 	}
 }
@@ -21,17 +21,21 @@ type TopologyTool struct {
 	cfs     *chartfs.ChartFS          // embedded filesystem
 	cm      *config.ConfigMapManager  // cluster configuration
 	tb      *resolver.TopologyBuilder // topology builder
+	pager   *Pager                    // paginates the topology
 }
 
 const (
 	// topologySuffix mcp topology tool name suffix.
 	topologySuffix = "_topology"
+
+	// DetailArg lists each chart with the annotations driving the resolution.
+	DetailArg = "detail"
 )
 
 // topologyHandler shows a table of the topology.
 func (t *TopologyTool) topologyHandler(
 	ctx context.Context,
-	_ mcp.CallToolRequest,
+	ctr mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
 	// Load the installer configuration from the cluster.
 	cfg, err := t.cm.GetConfig(ctx)
@@ -51,6 +55,28 @@ func (t *TopologyTool) topologyHandler(
 	}
 
 	var buf bytes.Buffer
+	if ctr.GetBool(DetailArg, false) {
+		r.PrintDetail(&buf)
+		return mcp.NewToolResultText(fmt.Sprintf(`
+The topology lists each chart, in the dependency graph order, with the chart
+annotations driving the resolution:
+
+  - Namespace: the namespace where the chart is installed, followed by the rule
+    choosing it: the product the chart belongs to, the product informed by the
+    "use-product-namespace" annotation, or the installer namespace.
+  - Namespace-Policy: how the deployment handles the namespace.
+  - Product: the name of the product the chart is associated with.
+  - Product-Requires: products required by the product, when enabled.
+  - Product-Conflicts: products that can't be enabled with the product.
+  - Depends-On: comma-separated list of charts the chart depends on.
+  - Weight: the chart weight, ordering charts on the same dependency level.
+  - Provided-Integrations: comma-separated integrations provided by the chart.
+  - Required-Integrations: CEL expressions with the required integrations.
+
+---
+%s`,
+			buf.String())), nil
+	}
 	r.Print(&buf)
 
 	return mcp.NewToolResultText(fmt.Sprintf(`
@@ -77,8 +103,18 @@ func (t *TopologyTool) Init(s *server.MCPServer) {
 Report the dependency topology of the installer based on the
 cluster configuration and installer dependencies (Helm charts).
 			`),
+			mcp.WithBoolean(
+				DetailArg,
+				mcp.Description(`
+List each chart with the annotations driving the resolution, including how its
+namespace is chosen and the products it requires or conflicts with, to audit
+the chart annotations.`,
+				),
+				mcp.DefaultBool(false),
+			),
+			withPagination(),
 		),
-		Handler: t.topologyHandler,
+		Handler: t.pager.Handler(t.topologyHandler),
 	}}...)
 }
 
@@ -94,5 +130,6 @@ func NewTopologyTool(
 		cfs:     cfs,
 		cm:      cm,
 		tb:      tb,
+		pager:   NewPager(),
 	}
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/redhat-appstudio/helmet/internal/integration"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -30,7 +32,8 @@ Inspecting the configuration in the cluster returned the following error:
 
 // generateIntegrationSubCmdUsage generates a formatted usage string for an
 // integration subcommand. It includes the command name, its long description, and
-// an example usage showing required flags with placeholder values.
+// an example usage showing required flags with placeholder values, required
+// credentials are read from STDIN to keep them out of the shell history.
 func generateIntegrationSubCmdUsage(appName string, cmd *cobra.Command) string {
 	var usage strings.Builder
 	usage.WriteString(fmt.Sprintf("%s integration %s", appName, cmd.Name()))
@@ -40,6 +43,9 @@ func generateIntegrationSubCmdUsage(appName string, cmd *cobra.Command) string {
 		if ok && len(annotations) > 0 && annotations[0] == "true" {
 			usage.WriteString(fmt.Sprintf(" --%s=\"OVERWRITE_ME\"", f.Name))
 		}
+		if _, ok := f.Annotations[integration.CredentialRequiredAnnotation]; ok {
+			usage.WriteString(fmt.Sprintf(" --%s-stdin", f.Name))
+		}
 	})
 
 	return fmt.Sprintf(
@@ -2,11 +2,14 @@ package monitor
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 // AssertNamespaceFn returns a function that asserts if the informed namespace
@@ -35,3 +38,52 @@ func AssertNamespaceFn(
 		return err
 	}, nil
 }
+
+// watchNamespaceTimeout the maximum duration of a single namespace watch, the
+// monitor retries afterwards.
+const watchNamespaceTimeout = int64(60)
+
+// WatchNamespaceFn returns a function that asserts if the informed namespace
+// exists, otherwise it waits watching the namespace, until it's created or the
+// watch expires, in which case it returns error. Watching keeps a single request
+// open, instead of polling the API.
+//
+//nolint:revive // returning unexported type is intentional for encapsulation
+func WatchNamespaceFn(
+	ctx context.Context,
+	logger *slog.Logger,
+	kube k8s.Interface,
+	namespace string,
+) (monitorQueueFn, error) {
+	client, err := kube.CoreV1ClientSet("default")
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		logger := logger.With("namespace", namespace)
+		_, err := client.Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		if err == nil {
+			logger.Debug("Namespace exists!")
+			return nil
+		}
+		logger.Debug("Watching the namespace...")
+		timeout := watchNamespaceTimeout
+		selector := fields.OneTermEqualSelector("metadata.name", namespace)
+		w, err := client.Namespaces().Watch(ctx, metav1.ListOptions{
+			FieldSelector:  selector.String(),
+			TimeoutSeconds: &timeout,
+		})
+		if err != nil {
+			return err
+		}
+		defer w.Stop()
+		for event := range w.ResultChan() {
+			if event.Type == watch.Added || event.Type == watch.Modified {
+				logger.Debug("Namespace exists!")
+				return nil
+			}
+		}
+		logger.Debug("Namespace is not found!")
+		return fmt.Errorf("namespace %q not found", namespace)
+	}, nil
+}
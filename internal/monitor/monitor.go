@@ -2,11 +2,11 @@ package monitor
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
 
 	"k8s.io/cli-runtime/pkg/resource"
@@ -15,6 +15,40 @@ import (
 // monitorQueueFn is a function type for monitoring a specific resource.
 type monitorQueueFn func() error
 
+// ErrTimeout the monitored resources are not ready within the timeout.
+var ErrTimeout = helmeterrors.New(helmeterrors.ErrClusterUnreachable,
+	"timeout reached")
+
+// Status check strategies, either polling the namespaces on an interval or
+// watching them, which keeps a single request open per namespace.
+const (
+	StrategyPoll  = "poll"
+	StrategyWatch = "watch"
+)
+
+// DefaultPollInterval the default interval between status checks.
+const DefaultPollInterval = 2 * time.Second
+
+// Options the status check settings, protecting constrained clusters from
+// frequent requests.
+type Options struct {
+	Strategy string        // status check strategy, poll by default
+	Interval time.Duration // interval between status checks
+}
+
+// Validate asserts the strategy is known and the interval positive.
+func (o Options) Validate() error {
+	if o.Strategy != "" && o.Strategy != StrategyPoll &&
+		o.Strategy != StrategyWatch {
+		return fmt.Errorf("invalid status check strategy %q, use %q or %q",
+			o.Strategy, StrategyPoll, StrategyWatch)
+	}
+	if o.Interval < 0 {
+		return fmt.Errorf("invalid status check interval %q", o.Interval)
+	}
+	return nil
+}
+
 // Monitor is the monitoring actor which collects interesting resources from a
 // Helm Chart release payload, and monitors them until they are ready. The
 // monitoring is executed with a queue of functions, which are executed in order
@@ -22,10 +56,19 @@ type monitorQueueFn func() error
 type Monitor struct {
 	logger *slog.Logger  // application logger
 	kube   k8s.Interface // kubernetes client
+	opts   Options       // status check settings
 
 	queue []monitorQueueFn // monitor function queue
 }
 
+// SetOptions sets the status check strategy and interval.
+func (m *Monitor) SetOptions(opts Options) {
+	if opts.Interval == 0 {
+		opts.Interval = DefaultPollInterval
+	}
+	m.opts = opts
+}
+
 var _ Interface = &Monitor{}
 
 // Collect inspects the resource and adds a monitoring function to the queue.
@@ -53,7 +96,11 @@ func (m *Monitor) Collect(ctx context.Context, r *resource.Info) error {
 		} else {
 			logger.Debug("Namespace detected, waiting for namespace to be active...")
 		}
-		fn, err := AssertNamespaceFn(ctx, m.logger, m.kube, r.Name)
+		assertFn := AssertNamespaceFn
+		if m.opts.Strategy == StrategyWatch {
+			assertFn = WatchNamespaceFn
+		}
+		fn, err := assertFn(ctx, m.logger, m.kube, r.Name)
 		if err != nil {
 			return err
 		}
@@ -77,7 +124,7 @@ func (m *Monitor) Watch(timeout time.Duration) error {
 	for len(m.queue) > 0 {
 		// If the timeout is reached, return an error.
 		if time.Since(start) >= timeout {
-			return errors.New("timeout reached")
+			return ErrTimeout
 		}
 
 		// Run the monitor function, if successful remove it from the queue.
@@ -88,7 +135,7 @@ func (m *Monitor) Watch(timeout time.Duration) error {
 		} else {
 			logger.Debug("Monitor function failed!",
 				"queue-remaining", len(m.queue))
-			time.Sleep(2 * time.Second)
+			time.Sleep(m.opts.Interval)
 		}
 	}
 	logger.Debug("Monitoring complete, queue is empty!")
@@ -100,6 +147,7 @@ func NewMonitor(logger *slog.Logger, kube k8s.Interface) *Monitor {
 	return &Monitor{
 		logger: logger.With("type", "monitor"),
 		kube:   kube,
+		opts:   Options{Strategy: StrategyPoll, Interval: DefaultPollInterval},
 		queue:  []monitorQueueFn{},
 	}
 }
@@ -89,3 +89,22 @@ func TestMonitorWatch(t *testing.T) {
 		g.Expect(err).ToNot(o.HaveOccurred())
 	})
 }
+
+// TestMonitorOptions tests the status check settings, and the namespace watch.
+func TestMonitorOptions(t *testing.T) {
+	g := o.NewWithT(t)
+
+	g.Expect(Options{Strategy: StrategyWatch}.Validate()).To(o.Succeed())
+	g.Expect(Options{Strategy: "stream"}.Validate()).ToNot(o.Succeed())
+	g.Expect(Options{Interval: -time.Second}.Validate()).ToNot(o.Succeed())
+
+	m := NewMonitor(slog.Default(), k8s.NewFakeKube())
+	g.Expect(m.opts.Interval).To(o.Equal(DefaultPollInterval))
+	m.SetOptions(Options{Strategy: StrategyWatch})
+	g.Expect(m.opts.Interval).To(o.Equal(DefaultPollInterval))
+
+	kube := k8s.NewFakeKube(stubs.NamespaceRuntimeObject("test"))
+	fn, err := WatchNamespaceFn(context.TODO(), slog.Default(), kube, "test")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(fn()).To(o.Succeed())
+}
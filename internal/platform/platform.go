@@ -0,0 +1,161 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/constants"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"github.com/Masterminds/semver/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsupportedPlatform the cluster platform version is outside the ranges
+// supported by the installer.
+var ErrUnsupportedPlatform = helmeterrors.New(
+	helmeterrors.ErrPrerequisitesMissing, "unsupported cluster platform")
+
+// ErrInvalidPlatforms the supported platforms file is malformed.
+var ErrInvalidPlatforms = helmeterrors.New(
+	helmeterrors.ErrInvalidConfig, "invalid supported platforms")
+
+// Platforms the cluster platform version ranges supported by the installer,
+// declared on the installer tarball, see constants.PlatformsFilename. The
+// ranges are semantic version constraints, as Helm's "kubeVersion", for
+// instance ">= 1.28, < 1.33". Empty ranges aren't enforced.
+type Platforms struct {
+	Kubernetes string `yaml:"kubernetes,omitempty"` // Kubernetes versions
+	OpenShift  string `yaml:"openshift,omitempty"`  // OpenShift versions
+
+	kubernetes *semver.Constraints // parsed Kubernetes range
+	openShift  *semver.Constraints // parsed OpenShift range
+}
+
+// Versions the cluster platform versions.
+type Versions struct {
+	Kubernetes string `json:"kubernetes"`          // API server version
+	OpenShift  string `json:"openshift,omitempty"` // empty on vanilla Kubernetes
+}
+
+// String describes the versions, for instance "Kubernetes v1.30.4, OpenShift
+// 4.17.2".
+func (v Versions) String() string {
+	s := "Kubernetes " + v.Kubernetes
+	if v.OpenShift != "" {
+		s += ", OpenShift " + v.OpenShift
+	}
+	return s
+}
+
+// Empty returns true when no version range is declared.
+func (p *Platforms) Empty() bool {
+	return p.kubernetes == nil && p.openShift == nil
+}
+
+// parse parses the version ranges.
+func (p *Platforms) parse() error {
+	var err error
+	for _, r := range []struct {
+		name        string
+		value       string
+		constraints **semver.Constraints
+	}{
+		{"kubernetes", p.Kubernetes, &p.kubernetes},
+		{"openshift", p.OpenShift, &p.openShift},
+	} {
+		if strings.TrimSpace(r.value) == "" {
+			continue
+		}
+		if *r.constraints, err = semver.NewConstraint(r.value); err != nil {
+			return fmt.Errorf("%w: %s %q: %w",
+				ErrInvalidPlatforms, r.name, r.value, err)
+		}
+	}
+	return nil
+}
+
+// coreVersion parses the version, dropping the pre-release and build metadata
+// distributions append, for instance "v1.30.4+k3s1" or "v1.29.6-eks-a1b2c3d",
+// so they don't fall outside the ranges.
+func coreVersion(version string) (*semver.Version, error) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return semver.New(v.Major(), v.Minor(), v.Patch(), "", ""), nil
+}
+
+// check asserts the version is within the range, when declared.
+func check(name, version string, constraints *semver.Constraints) error {
+	if constraints == nil {
+		return nil
+	}
+	v, err := coreVersion(version)
+	if err != nil {
+		return fmt.Errorf("%w: unable to parse the %s version %q: %w",
+			ErrUnsupportedPlatform, name, version, err)
+	}
+	if !constraints.Check(v) {
+		return fmt.Errorf("%w: %s %s is not supported, the installer requires %q",
+			ErrUnsupportedPlatform, name, version, constraints.String())
+	}
+	return nil
+}
+
+// Check asserts the cluster versions are within the supported ranges. The
+// OpenShift range only applies to OpenShift clusters.
+func (p *Platforms) Check(v Versions) error {
+	if err := check("Kubernetes", v.Kubernetes, p.kubernetes); err != nil {
+		return err
+	}
+	if v.OpenShift == "" {
+		return nil
+	}
+	return check("OpenShift", v.OpenShift, p.openShift)
+}
+
+// Load reads the supported platforms from the installer filesystem. Installers
+// without the file support any platform.
+func Load(cfs *chartfs.ChartFS) (*Platforms, error) {
+	p := &Platforms{}
+	payload, err := cfs.ReadFile(constants.PlatformsFilename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return p, nil
+		}
+		return nil, err
+	}
+	if err = yaml.Unmarshal(payload, p); err != nil {
+		return nil, fmt.Errorf("%w: %q: %w",
+			ErrInvalidPlatforms, constants.PlatformsFilename, err)
+	}
+	if err = p.parse(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ClusterVersions reads the cluster Kubernetes version, and the OpenShift
+// version when available.
+func ClusterVersions(ctx context.Context, kube k8s.Interface) (Versions, error) {
+	v := Versions{}
+	dc, err := kube.DiscoveryClient("default")
+	if err != nil {
+		return v, err
+	}
+	info, err := dc.ServerVersion()
+	if err != nil {
+		return v, fmt.Errorf("%w: unable to read the Kubernetes version: %w",
+			helmeterrors.ErrClusterUnreachable, err)
+	}
+	v.Kubernetes = info.GitVersion
+	// Vanilla Kubernetes clusters lack the ClusterVersion resource.
+	v.OpenShift, _ = k8s.GetOpenShiftVersion(ctx, kube)
+	return v, nil
+}
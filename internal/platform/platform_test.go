@@ -0,0 +1,59 @@
+package platform
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/constants"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestPlatforms(t *testing.T) {
+	load := func(payload string) (*Platforms, error) {
+		return Load(chartfs.New(fstest.MapFS{
+			constants.PlatformsFilename: &fstest.MapFile{Data: []byte(payload)},
+		}))
+	}
+
+	t.Run("Load", func(t *testing.T) {
+		g := o.NewWithT(t)
+		p, err := Load(chartfs.New(fstest.MapFS{}))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(p.Empty()).To(o.BeTrue())
+		g.Expect(p.Check(Versions{Kubernetes: "v1.20.0"})).To(o.Succeed())
+
+		_, err = load("kubernetes: not a range")
+		g.Expect(err).To(o.MatchError(ErrInvalidPlatforms))
+		_, err = load("kubernetes: [1.28]")
+		g.Expect(err).To(o.MatchError(ErrInvalidPlatforms))
+	})
+
+	t.Run("Check", func(t *testing.T) {
+		g := o.NewWithT(t)
+		p, err := load(`
+kubernetes: ">= 1.28, < 1.33"
+openshift: ">= 4.15"
+`)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(p.Empty()).To(o.BeFalse())
+
+		g.Expect(p.Check(Versions{Kubernetes: "v1.30.4+k3s1"})).To(o.Succeed())
+		g.Expect(p.Check(Versions{Kubernetes: "v1.28.0-eks-a1b2c3d"})).
+			To(o.Succeed())
+		g.Expect(p.Check(Versions{
+			Kubernetes: "v1.30.4", OpenShift: "4.17.0-rc.1",
+		})).To(o.Succeed())
+
+		err = p.Check(Versions{Kubernetes: "v1.27.9"})
+		g.Expect(err).To(o.MatchError(ErrUnsupportedPlatform))
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("Kubernetes v1.27.9")))
+		g.Expect(p.Check(Versions{Kubernetes: "v1.33.0"})).
+			To(o.MatchError(ErrUnsupportedPlatform))
+		err = p.Check(Versions{Kubernetes: "v1.30.4", OpenShift: "4.14.12"})
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("OpenShift 4.14.12")))
+		g.Expect(p.Check(Versions{Kubernetes: "unknown"})).
+			To(o.MatchError(ErrUnsupportedPlatform))
+	})
+}
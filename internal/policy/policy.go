@@ -0,0 +1,151 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+)
+
+// ErrDenied the command or tool isn't allowed by the operations policy.
+var ErrDenied = helmeterrors.New(helmeterrors.ErrInvalidUsage,
+	"operation not allowed by the policy")
+
+// Setting the installer setting with the operations policy, the commands and
+// MCP tools allowed on the cluster, for instance:
+//
+//	operationsPolicy:
+//	  deny: [cleanup, "config edit"]
+//	  denyTools: ["*_config_*"]
+//	  inClusterOnly: [deploy]
+//
+// Being a setting, the policy is declared per environment with the
+// "environments" overlays, and lives on the cluster configuration.
+const Setting = "operationsPolicy"
+
+// JobEnv the environment variable set on the installer Job container, telling
+// the commands run in the cluster by the Job apart.
+const JobEnv = "INSTALLER_JOB"
+
+// Policy the operations allowed in the environment.
+type Policy struct {
+	Deny          []string // denied command paths
+	DenyTools     []string // denied MCP tool name patterns
+	InClusterOnly []string // commands allowed only on the installer Job
+}
+
+// matchCommand returns whether the command path is the rule's, or one of its
+// subcommands, "integration" matches "integration github".
+func matchCommand(rule, command string) bool {
+	ruleWords := strings.Fields(rule)
+	commandWords := strings.Fields(command)
+	return len(ruleWords) <= len(commandWords) &&
+		slices.Equal(ruleWords, commandWords[:len(ruleWords)])
+}
+
+// Allow asserts the command, informed by its path without the application name,
+// is allowed. The inJob flag tells whether it runs on the installer Job.
+func (p *Policy) Allow(command string, inJob bool) error {
+	for _, rule := range p.Deny {
+		if matchCommand(rule, command) {
+			return fmt.Errorf("%w: %q is denied by the %q setting",
+				ErrDenied, command, Setting)
+		}
+	}
+	if inJob {
+		return nil
+	}
+	for _, rule := range p.InClusterOnly {
+		if matchCommand(rule, command) {
+			return fmt.Errorf("%w: %q is only allowed in the cluster, "+
+				"through the installer Job", ErrDenied, command)
+		}
+	}
+	return nil
+}
+
+// AllowTool returns whether the MCP tool, informed by its registered name, is
+// allowed.
+func (p *Policy) AllowTool(name string) bool {
+	for _, pattern := range p.DenyTools {
+		// Patterns are validated, errors are not expected.
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// InJob returns whether the process runs on the installer Job, see JobEnv.
+func InJob() bool {
+	return os.Getenv(JobEnv) == "true"
+}
+
+// stringList parses the informed policy attribute as a list of strings.
+func stringList(m map[string]any, key string) ([]string, error) {
+	value, ok := m[key]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: setting %q: %q must be a list",
+			config.ErrInvalidConfig, Setting, key)
+	}
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok || strings.TrimSpace(s) == "" {
+			return nil, fmt.Errorf("%w: setting %q: %q must hold non-empty "+
+				"strings, got %v", config.ErrInvalidConfig, Setting, key, item)
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+// NewPolicyFromConfig returns the operations policy informed on the installer
+// settings, by default everything is allowed.
+func NewPolicyFromConfig(cfg *config.Config) (*Policy, error) {
+	policy := &Policy{}
+	if cfg == nil {
+		return policy, nil
+	}
+	setting, ok := cfg.Installer.Settings[Setting]
+	if !ok || setting == nil {
+		return policy, nil
+	}
+	m, ok := config.AsMap(setting)
+	if !ok {
+		return nil, fmt.Errorf("%w: setting %q must be a map",
+			config.ErrInvalidConfig, Setting)
+	}
+	for key := range m {
+		if !slices.Contains([]string{"deny", "denyTools", "inClusterOnly"}, key) {
+			return nil, fmt.Errorf("%w: setting %q: unknown attribute %q, "+
+				"expecting \"deny\", \"denyTools\" or \"inClusterOnly\"",
+				config.ErrInvalidConfig, Setting, key)
+		}
+	}
+	var err error
+	if policy.Deny, err = stringList(m, "deny"); err != nil {
+		return nil, err
+	}
+	if policy.DenyTools, err = stringList(m, "denyTools"); err != nil {
+		return nil, err
+	}
+	for _, pattern := range policy.DenyTools {
+		if _, err = path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("%w: setting %q: tool pattern %q: %w",
+				config.ErrInvalidConfig, Setting, pattern, err)
+		}
+	}
+	if policy.InClusterOnly, err = stringList(m, "inClusterOnly"); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
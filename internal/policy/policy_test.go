@@ -0,0 +1,89 @@
+package policy
+
+import (
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestNewPolicyFromConfig(t *testing.T) {
+	cfs := chartfs.New(os.DirFS("../../test"))
+	newConfig := func(t *testing.T, setting any) *config.Config {
+		g := o.NewWithT(t)
+		cfg, err := config.NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		if setting != nil {
+			cfg.Installer.Settings[Setting] = setting
+		}
+		return cfg
+	}
+
+	t.Run("unset", func(t *testing.T) {
+		g := o.NewWithT(t)
+		policy, err := NewPolicyFromConfig(newConfig(t, nil))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(policy.Allow("cleanup", false)).To(o.Succeed())
+		g.Expect(policy.AllowTool("helmet-ex_deploy")).To(o.BeTrue())
+	})
+
+	t.Run("commands", func(t *testing.T) {
+		g := o.NewWithT(t)
+		policy, err := NewPolicyFromConfig(newConfig(t, config.Settings{
+			"deny":          []any{"cleanup", "integration github"},
+			"inClusterOnly": []any{"deploy"},
+		}))
+		g.Expect(err).To(o.Succeed())
+
+		g.Expect(policy.Allow("cleanup", false)).To(o.MatchError(ErrDenied))
+		g.Expect(policy.Allow("cleanup", true)).To(o.MatchError(ErrDenied))
+		g.Expect(policy.Allow("integration github", false)).
+			To(o.MatchError(ErrDenied))
+		g.Expect(policy.Allow("integration gitlab", false)).To(o.Succeed())
+		g.Expect(policy.Allow("integration", false)).To(o.Succeed())
+		// Matching whole words, "deploy" doesn't match "deployment".
+		g.Expect(policy.Allow("deployment", false)).To(o.Succeed())
+
+		err = policy.Allow("deploy", false)
+		g.Expect(err).To(o.MatchError(ErrDenied))
+		g.Expect(err.Error()).To(o.ContainSubstring("installer Job"))
+		g.Expect(policy.Allow("deploy", true)).To(o.Succeed())
+	})
+
+	t.Run("tools", func(t *testing.T) {
+		g := o.NewWithT(t)
+		policy, err := NewPolicyFromConfig(newConfig(t, map[string]any{
+			"denyTools": []any{"*_config_*", "helmet-ex_deploy"},
+		}))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(policy.AllowTool("helmet-ex_config_set")).To(o.BeFalse())
+		g.Expect(policy.AllowTool("helmet-ex_deploy")).To(o.BeFalse())
+		g.Expect(policy.AllowTool("helmet-ex_status")).To(o.BeTrue())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := NewPolicyFromConfig(newConfig(t, "deny"))
+		g.Expect(err).To(o.MatchError(config.ErrInvalidConfig))
+		_, err = NewPolicyFromConfig(newConfig(t, config.Settings{
+			"allow": []any{"deploy"},
+		}))
+		g.Expect(err).To(o.MatchError(config.ErrInvalidConfig))
+		_, err = NewPolicyFromConfig(newConfig(t, config.Settings{
+			"deny": "cleanup",
+		}))
+		g.Expect(err).To(o.MatchError(config.ErrInvalidConfig))
+		_, err = NewPolicyFromConfig(newConfig(t, config.Settings{
+			"deny": []any{""},
+		}))
+		g.Expect(err).To(o.MatchError(config.ErrInvalidConfig))
+		_, err = NewPolicyFromConfig(newConfig(t, config.Settings{
+			"denyTools": []any{"[deploy"},
+		}))
+		g.Expect(err).To(o.MatchError(config.ErrInvalidConfig))
+	})
+}
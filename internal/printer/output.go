@@ -0,0 +1,218 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// Output formats, informed as "--output <format>[=<argument>]".
+const (
+	// OutputTable the command's own human readable table, default.
+	OutputTable = "table"
+	// OutputJSON the items as a JSON document.
+	OutputJSON = "json"
+	// OutputYAML the items as a YAML document.
+	OutputYAML = "yaml"
+	// OutputJSONPath a JSONPath template, e.g. "jsonpath={.items[*].name}".
+	OutputJSONPath = "jsonpath"
+	// OutputGoTemplate a Go template, e.g.
+	// "go-template={{range .items}}{{.name}}{{end}}".
+	OutputGoTemplate = "go-template"
+	// OutputCustomColumns a table of the informed columns, e.g.
+	// "custom-columns=NAME:.name,NAMESPACE:.namespace".
+	OutputCustomColumns = "custom-columns"
+)
+
+// OutputFormats the output formats supported.
+var OutputFormats = []string{
+	OutputTable,
+	OutputJSON,
+	OutputYAML,
+	OutputJSONPath,
+	OutputGoTemplate,
+	OutputCustomColumns,
+}
+
+// ErrInvalidOutput the output format is unknown or its argument is invalid.
+var ErrInvalidOutput = helmeterrors.New(helmeterrors.ErrInvalidUsage,
+	"invalid output format")
+
+// column a custom column header and its JSONPath expression.
+type column struct {
+	header string
+	path   *jsonpath.JSONPath
+}
+
+// Output prints a list of items in the format chosen by the user, the same way
+// for every listing command. Items are printed by their JSON representation,
+// wrapped as {"items": [...]}, thus templates refer to the JSON field names.
+type Output struct {
+	format   string             // output format
+	jsonPath *jsonpath.JSONPath // parsed jsonpath template
+	tmpl     *template.Template // parsed go-template
+	columns  []column           // parsed custom-columns
+}
+
+// Table returns true when the command's own table should be printed.
+func (o *Output) Table() bool {
+	return o.format == OutputTable
+}
+
+// data returns the items as generic JSON data, wrapped as {"items": [...]}.
+func data(items any) (map[string]any, error) {
+	payload, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var list []any
+	if err = json.Unmarshal(payload, &list); err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []any{}
+	}
+	return map[string]any{"items": list}, nil
+}
+
+// Print prints the items, a slice, on the informed writer. The table format is
+// printed by the caller, see Table.
+func (o *Output) Print(w io.Writer, items any) error {
+	doc, err := data(items)
+	if err != nil {
+		return err
+	}
+	switch o.format {
+	case OutputJSON:
+		payload, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", payload)
+		return err
+	case OutputYAML:
+		payload, err := yaml.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(payload)
+		return err
+	case OutputJSONPath:
+		return o.jsonPath.Execute(w, doc)
+	case OutputGoTemplate:
+		return o.tmpl.Execute(w, doc)
+	case OutputCustomColumns:
+		return o.printColumns(w, doc["items"].([]any))
+	default:
+		return fmt.Errorf("%w: %q is printed by the command", ErrInvalidOutput,
+			o.format)
+	}
+}
+
+// printColumns prints a table with the custom columns, one row per item.
+func (o *Output) printColumns(w io.Writer, items []any) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	headers := make([]string, 0, len(o.columns))
+	for _, c := range o.columns {
+		headers = append(headers, c.header)
+	}
+	fmt.Fprintln(table, strings.Join(headers, "\t"))
+	for _, item := range items {
+		cells := make([]string, 0, len(o.columns))
+		for _, c := range o.columns {
+			results, err := c.path.FindResults(item)
+			if err != nil {
+				return err
+			}
+			values := []string{}
+			for _, result := range results {
+				for _, v := range result {
+					values = append(values, fmt.Sprint(v.Interface()))
+				}
+			}
+			cell := strings.Join(values, ",")
+			if cell == "" {
+				cell = "<none>"
+			}
+			cells = append(cells, cell)
+		}
+		fmt.Fprintln(table, strings.Join(cells, "\t"))
+	}
+	return table.Flush()
+}
+
+// parseJSONPath parses a kubectl style JSONPath template, the surrounding braces
+// are optional.
+func parseJSONPath(name, expr string) (*jsonpath.JSONPath, error) {
+	if !strings.HasPrefix(expr, "{") {
+		expr = fmt.Sprintf("{%s}", expr)
+	}
+	j := jsonpath.New(name).AllowMissingKeys(true)
+	if err := j.Parse(expr); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidOutput, name, err)
+	}
+	return j, nil
+}
+
+// NewOutput parses the informed output, "<format>[=<argument>]", an empty value
+// means the table format.
+func NewOutput(value string) (*Output, error) {
+	format, arg, _ := strings.Cut(value, "=")
+	o := &Output{format: format}
+	if format == "" {
+		o.format = OutputTable
+	}
+	if !slices.Contains(OutputFormats, o.format) {
+		return nil, fmt.Errorf("%w: %q, supported formats: %s",
+			ErrInvalidOutput, o.format, strings.Join(OutputFormats, ", "))
+	}
+	var err error
+	switch o.format {
+	case OutputTable, OutputJSON, OutputYAML:
+		if arg != "" {
+			return nil, fmt.Errorf("%w: %q takes no argument", ErrInvalidOutput,
+				o.format)
+		}
+		return o, nil
+	}
+	if arg == "" {
+		return nil, fmt.Errorf("%w: %q requires an argument, %q",
+			ErrInvalidOutput, o.format, o.format+"=...")
+	}
+	switch o.format {
+	case OutputJSONPath:
+		o.jsonPath, err = parseJSONPath(OutputJSONPath, arg)
+	case OutputGoTemplate:
+		o.tmpl, err = template.New(OutputGoTemplate).Parse(arg)
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrInvalidOutput, err)
+		}
+	case OutputCustomColumns:
+		for _, spec := range strings.Split(arg, ",") {
+			header, expr, ok := strings.Cut(spec, ":")
+			if !ok || header == "" || expr == "" {
+				return nil, fmt.Errorf(
+					"%w: custom column %q, expected <header>:<jsonpath>",
+					ErrInvalidOutput, spec)
+			}
+			path, err := parseJSONPath(header, expr)
+			if err != nil {
+				return nil, err
+			}
+			o.columns = append(o.columns, column{header: header, path: path})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
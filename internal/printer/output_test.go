@@ -0,0 +1,78 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	o "github.com/onsi/gomega"
+)
+
+func TestOutput(t *testing.T) {
+	g := o.NewWithT(t)
+
+	type item struct {
+		Name      string   `json:"name"`
+		Namespace string   `json:"namespace"`
+		DependsOn []string `json:"dependsOn,omitempty"`
+	}
+	items := []item{
+		{Name: "foundation", Namespace: "helmet"},
+		{Name: "product-a", Namespace: "product-a",
+			DependsOn: []string{"foundation", "operators"}},
+	}
+
+	render := func(value string) string {
+		out, err := NewOutput(value)
+		g.Expect(err).To(o.Succeed())
+		var buf bytes.Buffer
+		g.Expect(out.Print(&buf, items)).To(o.Succeed())
+		return buf.String()
+	}
+
+	t.Run("Table", func(t *testing.T) {
+		for _, value := range []string{"", "table"} {
+			out, err := NewOutput(value)
+			g.Expect(err).To(o.Succeed())
+			g.Expect(out.Table()).To(o.BeTrue())
+		}
+	})
+
+	t.Run("JSONPath", func(t *testing.T) {
+		g.Expect(render("jsonpath={.items[*].name}")).
+			To(o.Equal("foundation product-a"))
+		g.Expect(render(`jsonpath={range .items[*]}{.namespace}{"\n"}{end}`)).
+			To(o.Equal("helmet\nproduct-a\n"))
+	})
+
+	t.Run("GoTemplate", func(t *testing.T) {
+		g.Expect(render(
+			`go-template={{range .items}}{{.name}}@{{.namespace}};{{end}}`,
+		)).To(o.Equal("foundation@helmet;product-a@product-a;"))
+	})
+
+	t.Run("CustomColumns", func(t *testing.T) {
+		g.Expect(render("custom-columns=NAME:.name,DEPS:.dependsOn[*]")).
+			To(o.Equal("NAME        DEPS\n" +
+				"foundation  <none>\n" +
+				"product-a   foundation,operators\n"))
+	})
+
+	t.Run("Documents", func(t *testing.T) {
+		g.Expect(render("json")).To(o.ContainSubstring(`"name": "product-a"`))
+		g.Expect(render("yaml")).To(o.ContainSubstring("- name: foundation"))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, value := range []string{
+			"wide",
+			"jsonpath",
+			"json=.items",
+			"jsonpath={.items[",
+			"go-template={{.items",
+			"custom-columns=NAME",
+		} {
+			_, err := NewOutput(value)
+			g.Expect(err).To(o.MatchError(ErrInvalidOutput), value)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package readiness
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+)
+
+// ErrChecksFailed one or more verification checks failed.
+var ErrChecksFailed = helmeterrors.New(helmeterrors.ErrDeployFailed,
+	"verification checks failed")
+
+// Check the outcome of a registered verification check.
+type Check struct {
+	Name    string `json:"name"`    // check name
+	Passed  bool   `json:"passed"`  // check succeeded
+	Message string `json:"message"` // details, the failure reason
+}
+
+// Checks the outcome of the verification checks, in registration order.
+type Checks []Check
+
+// Err returns ErrChecksFailed listing the failed checks, nil when all passed.
+func (c Checks) Err() error {
+	failed := []string{}
+	for _, check := range c {
+		if !check.Passed {
+			failed = append(failed, check.Name)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %v", ErrChecksFailed, failed)
+}
+
+// RunChecks instantiates and runs the checkers, in registration order. A
+// checker panicking is reported as failed, the remaining ones still run.
+func RunChecks(
+	ctx context.Context,
+	kube k8s.Interface,
+	cfg *config.Config,
+	modules []api.CheckerModule,
+) Checks {
+	checks := make(Checks, 0, len(modules))
+	for _, m := range modules {
+		checks = append(checks, runCheck(ctx, kube, cfg, m))
+	}
+	return checks
+}
+
+// runCheck runs a single checker, recovering from its panics.
+func runCheck(
+	ctx context.Context,
+	kube k8s.Interface,
+	cfg *config.Config,
+	m api.CheckerModule,
+) (check Check) {
+	check.Name = m.Name
+	defer func() {
+		if r := recover(); r != nil {
+			check.Passed = false
+			check.Message = fmt.Sprintf("checker panicked: %v", r)
+		}
+	}()
+	result := m.Init(kube, cfg).Check(ctx)
+	check.Passed = result.Passed
+	check.Message = result.Message
+	return check
+}
@@ -0,0 +1,59 @@
+package readiness
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+)
+
+// checkerFn adapts a function to the api.Checker interface.
+type checkerFn func(context.Context) api.CheckResult
+
+func (f checkerFn) Check(ctx context.Context) api.CheckResult {
+	return f(ctx)
+}
+
+func TestRunChecks(t *testing.T) {
+	g := o.NewWithT(t)
+
+	module := func(name string, fn checkerFn) api.CheckerModule {
+		return api.CheckerModule{
+			Name: name,
+			Init: func(_ k8s.Interface, _ *config.Config) api.Checker {
+				return fn
+			},
+		}
+	}
+	passing := module("passing", func(context.Context) api.CheckResult {
+		return api.CheckResult{Passed: true, Message: "all good"}
+	})
+
+	checks := RunChecks(context.Background(), k8s.NewFakeKube(), nil,
+		[]api.CheckerModule{passing})
+	g.Expect(checks).To(o.Equal(Checks{
+		{Name: "passing", Passed: true, Message: "all good"},
+	}))
+	g.Expect(checks.Err()).To(o.Succeed())
+
+	checks = RunChecks(context.Background(), k8s.NewFakeKube(), nil,
+		[]api.CheckerModule{
+			module("failing", func(context.Context) api.CheckResult {
+				return api.CheckResult{Message: "endpoint unreachable"}
+			}),
+			module("panicking", func(context.Context) api.CheckResult {
+				panic("boom")
+			}),
+			passing,
+		})
+	g.Expect(checks).To(o.HaveLen(3))
+	g.Expect(checks[1].Passed).To(o.BeFalse())
+	g.Expect(checks[1].Message).To(o.ContainSubstring("boom"))
+	g.Expect(checks[2].Passed).To(o.BeTrue())
+	g.Expect(checks.Err()).To(o.MatchError(ErrChecksFailed))
+	g.Expect(checks.Err()).To(o.MatchError(o.ContainSubstring("[failing panicking]")))
+}
@@ -0,0 +1,129 @@
+package readiness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+)
+
+// Readiness computes the installation phase and conditions by inspecting the
+// cluster configuration, the topology and the installer job.
+type Readiness struct {
+	cm  *config.ConfigMapManager  // cluster configuration
+	tb  *resolver.TopologyBuilder // topology builder
+	job *installer.Job            // cluster deployment job
+}
+
+var _ api.Readiness = &Readiness{}
+
+// ErrUnknownJobState the installer job state is not recognized.
+var ErrUnknownJobState = helmeterrors.New(helmeterrors.ErrDeployFailed,
+	"unknown installer job state reported by cluster")
+
+// evaluate inspects the cluster, returning the phase, the conditions and the
+// error explaining the phase, when any.
+func (r *Readiness) evaluate(
+	ctx context.Context,
+) (api.Phase, []api.Condition, error) {
+	conditions := []api.Condition{
+		{Type: api.ConditionConfigured, Status: api.ConditionUnknown},
+		{Type: api.ConditionIntegrationsReady, Status: api.ConditionUnknown},
+		{Type: api.ConditionDeployed, Status: api.ConditionUnknown},
+	}
+	set := func(i int, status api.ConditionStatus, reason, message string) {
+		conditions[i].Status = status
+		conditions[i].Reason = reason
+		conditions[i].Message = message
+	}
+
+	// Ensure the cluster is configured. Only a missing configuration awaits
+	// it, otherwise the configuration can't be inspected.
+	cfg, err := r.cm.GetConfig(ctx)
+	if errors.Is(err, config.ErrConfigMapNotFound) {
+		set(0, api.ConditionFalse, "ConfigNotFound", err.Error())
+		return api.PhaseAwaitingConfiguration, conditions, err
+	}
+	if err != nil {
+		set(0, api.ConditionUnknown, "ConfigUnreadable", err.Error())
+		return api.PhaseInstallerError, conditions, err
+	}
+	set(0, api.ConditionTrue, "ConfigFound",
+		fmt.Sprintf("configuration %q found", r.cm.Name()))
+
+	// Given the cluster is configured, inspect the topology to ensure all
+	// dependencies and integrations are resolved.
+	if _, err = r.tb.Build(ctx, cfg); err != nil {
+		reason := "TopologyInvalid"
+		if errors.Is(err, resolver.ErrMissingIntegrations) ||
+			errors.Is(err, resolver.ErrPrerequisiteIntegration) {
+			reason = "IntegrationsMissing"
+		}
+		set(1, api.ConditionFalse, reason, err.Error())
+		return api.PhaseAwaitingIntegrations, conditions, err
+	}
+	set(1, api.ConditionTrue, "TopologyResolved",
+		"all dependencies and integrations are resolved")
+
+	// Given integrations are in place, inspect the current state of the
+	// cluster deployment job.
+	jobState, err := r.job.GetState(ctx)
+	if err != nil {
+		set(2, api.ConditionUnknown, "JobStateUnknown", err.Error())
+		return api.PhaseInstallerError, conditions, err
+	}
+
+	// Map the job state to an installer phase. Both 'Deploying' and 'Failed'
+	// states indicate the deployment process is active or has attempted to
+	// run, thus falling under the deploying phase.
+	switch jobState {
+	case installer.NotFound:
+		set(2, api.ConditionFalse, "NotDeployed", "installer job not found")
+		return api.PhaseReadyToDeploy, conditions, nil
+	case installer.Deploying:
+		set(2, api.ConditionFalse, "Deploying", "installer job is running")
+		return api.PhaseDeploying, conditions, nil
+	case installer.Failed:
+		set(2, api.ConditionFalse, "DeployFailed", "installer job has failed")
+		return api.PhaseDeploying, conditions, nil
+	case installer.Done:
+		set(2, api.ConditionTrue, "Completed", "installer job has succeeded")
+		return api.PhaseCompleted, conditions, nil
+	default:
+		set(2, api.ConditionUnknown, "JobStateUnknown",
+			ErrUnknownJobState.Error())
+		return api.PhaseInstallerError, conditions, ErrUnknownJobState
+	}
+}
+
+// Phase returns the current installation phase, and the error explaining why
+// the installation is not further along, when any.
+func (r *Readiness) Phase(ctx context.Context) (api.Phase, error) {
+	phase, _, err := r.evaluate(ctx)
+	return phase, err
+}
+
+// Status returns the current installation readiness. Only failures inspecting
+// the cluster, the installer error phase, are returned as error.
+func (r *Readiness) Status(ctx context.Context) (*api.ReadinessStatus, error) {
+	phase, conditions, err := r.evaluate(ctx)
+	status := &api.ReadinessStatus{Phase: phase, Conditions: conditions}
+	if phase == api.PhaseInstallerError {
+		return status, err
+	}
+	return status, nil
+}
+
+// NewReadiness instantiates the readiness inspector.
+func NewReadiness(
+	cm *config.ConfigMapManager,
+	tb *resolver.TopologyBuilder,
+	job *installer.Job,
+) *Readiness {
+	return &Readiness{cm: cm, tb: tb, job: job}
+}
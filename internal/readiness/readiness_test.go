@@ -0,0 +1,93 @@
+package readiness
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReadiness(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	appCtx := api.NewAppContext("helmet-ex")
+	cfs := chartfs.New(os.DirFS("../../test"))
+	kube := k8s.NewFakeKube()
+
+	tb, err := resolver.NewTopologyBuilder(
+		appCtx,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		cfs,
+		integrations.NewManager(),
+	)
+	g.Expect(err).To(o.Succeed())
+
+	r := NewReadiness(
+		config.NewConfigMapManager(kube, appCtx.Name),
+		tb,
+		installer.NewJob(appCtx, kube),
+	)
+
+	t.Run("AwaitingConfiguration", func(t *testing.T) {
+		phase, err := r.Phase(ctx)
+		g.Expect(err).To(o.MatchError(config.ErrConfigMapNotFound))
+		g.Expect(phase).To(o.Equal(api.PhaseAwaitingConfiguration))
+
+		status, err := r.Status(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(status.Ready()).To(o.BeFalse())
+		g.Expect(status.Phase).To(o.Equal(api.PhaseAwaitingConfiguration))
+
+		configured := status.Condition(api.ConditionConfigured)
+		g.Expect(configured).ToNot(o.BeNil())
+		g.Expect(configured.Status).To(o.Equal(api.ConditionFalse))
+		g.Expect(configured.Reason).To(o.Equal("ConfigNotFound"))
+
+		for _, c := range []string{
+			api.ConditionIntegrationsReady, api.ConditionDeployed,
+		} {
+			g.Expect(status.Condition(c).Status).
+				To(o.Equal(api.ConditionUnknown))
+		}
+	})
+
+	t.Run("ConfigUnreadable", func(t *testing.T) {
+		g := o.NewWithT(t)
+		configMap := func(name string) *corev1.ConfigMap {
+			return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "helmet-ex",
+				Labels:    map[string]string{annotations.Config: "true"},
+			}}
+		}
+		kube := k8s.NewFakeKube(configMap("first"), configMap("second"))
+		r := NewReadiness(
+			config.NewConfigMapManager(kube, appCtx.Name),
+			tb,
+			installer.NewJob(appCtx, kube),
+		)
+
+		// The configuration exists, but can't be read, not awaited.
+		status, err := r.Status(ctx)
+		g.Expect(err).To(o.MatchError(config.ErrMultipleConfigMapFound))
+		g.Expect(status.Phase).To(o.Equal(api.PhaseInstallerError))
+		configured := status.Condition(api.ConditionConfigured)
+		g.Expect(configured.Status).To(o.Equal(api.ConditionUnknown))
+		g.Expect(configured.Reason).To(o.Equal("ConfigUnreadable"))
+	})
+}
@@ -1,10 +1,11 @@
 package resolver
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
 	"github.com/google/cel-go/cel"
 )
 
@@ -17,9 +18,11 @@ type CEL struct {
 
 var (
 	// ErrInvalidExpression the expression is not a valid CEL expression.
-	ErrInvalidExpression = errors.New("invalid CEL expression")
+	ErrInvalidExpression = helmeterrors.New(helmeterrors.ErrTopologyUnresolved,
+		"invalid CEL expression")
 	// ErrMissingIntegrations one or more integrations aren't configured.
-	ErrMissingIntegrations = errors.New("missing integrations")
+	ErrMissingIntegrations = helmeterrors.New(helmeterrors.ErrPrerequisitesMissing,
+		"missing integrations")
 )
 
 // Evaluate evaluates the provided CEL expression against the current context of
@@ -29,20 +32,13 @@ func (c *CEL) Evaluate(configured map[string]bool, expression string) error {
 	// expression issues.
 	ast, issues := c.env.Compile(expression)
 	if issues != nil && issues.Err() != nil {
-		return fmt.Errorf("%w: %q", ErrInvalidExpression, expression)
+		return fmt.Errorf("%w: %q: %s",
+			ErrInvalidExpression, expression, issues.String())
 	}
 
-	// Generating a checked AST, where the types are validated, this allows
-	// extracing the actual integration names referenced in the expression.
-	checkedAST, issues := c.env.Check(ast)
-	if issues != nil && issues.Err() != nil {
-		return fmt.Errorf("%w: %q", ErrInvalidExpression, expression)
-	}
-	referenced := []string{}
-	for _, ref := range checkedAST.NativeRep().ReferenceMap() {
-		if ref.Name != "" {
-			referenced = append(referenced, ref.Name)
-		}
+	referenced, err := c.references(ast, expression)
+	if err != nil {
+		return err
 	}
 
 	// Generating the program from the AST, and evaluating it against the context
@@ -79,6 +75,34 @@ func (c *CEL) Evaluate(configured map[string]bool, expression string) error {
 		ErrMissingIntegrations, strings.Join(missing, ", "))
 }
 
+// references returns the integration names referenced in the compiled expression.
+func (c *CEL) references(ast *cel.Ast, expression string) ([]string, error) {
+	// Generating a checked AST, where the types are validated, this allows
+	// extracing the actual integration names referenced in the expression.
+	checkedAST, issues := c.env.Check(ast)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("%w: %q: %s",
+			ErrInvalidExpression, expression, issues.String())
+	}
+	referenced := []string{}
+	for _, ref := range checkedAST.NativeRep().ReferenceMap() {
+		if ref.Name != "" {
+			referenced = append(referenced, ref.Name)
+		}
+	}
+	return referenced, nil
+}
+
+// References returns the integration names referenced in the expression.
+func (c *CEL) References(expression string) ([]string, error) {
+	ast, issues := c.env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("%w: %q: %s",
+			ErrInvalidExpression, expression, issues.String())
+	}
+	return c.references(ast, expression)
+}
+
 // NewCEL creates a new CEL instance with the all valid integration names. These
 // names are considered variables in the CEL expression, limiting the scope of the
 // expression to only valid integrations.
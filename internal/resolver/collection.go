@@ -1,11 +1,11 @@
 package resolver
 
 import (
-	"errors"
 	"fmt"
 	"slices"
 
 	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 	"helm.sh/helm/v3/pkg/chart"
 )
 
@@ -21,9 +21,11 @@ type DependencyWalkFn func(string, Dependency) error
 
 var (
 	// ErrInvalidCollection the collection is invalid.
-	ErrInvalidCollection = errors.New("invalid collection")
+	ErrInvalidCollection = helmeterrors.New(helmeterrors.ErrTopologyUnresolved,
+		"invalid collection")
 	// ErrDependencyNotFound the dependency is not found in the collection.
-	ErrDependencyNotFound = errors.New("dependency not found")
+	ErrDependencyNotFound = helmeterrors.New(helmeterrors.ErrTopologyUnresolved,
+		"dependency not found")
 )
 
 // Get returns the dependency with the given name.
@@ -52,6 +54,18 @@ func (c *Collection) Walk(fn DependencyWalkFn) error {
 	return nil
 }
 
+// ProductNames returns the product names declared by the dependencies, sorted.
+func (c *Collection) ProductNames() []string {
+	names := []string{}
+	for _, d := range c.dependencies {
+		if name := d.ProductName(); name != "" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
 // GetProductDependency returns the dependency associated with the informed
 // product. Returns error when no dependency is found.
 func (c *Collection) GetProductDependency(product string) (*Dependency, error) {
@@ -95,6 +109,30 @@ func (c *Collection) GetProductNameForIntegration(integrationName string) string
 	return productName
 }
 
+// GetDependenciesRequiringIntegration returns the dependencies referencing the
+// integration name on the "integrations-required" expression, sorted by name.
+func (c *Collection) GetDependenciesRequiringIntegration(
+	cel *CEL,
+	integrationName string,
+) ([]Dependency, error) {
+	dependencies := []Dependency{}
+	err := c.Walk(func(name string, d Dependency) error {
+		expression := d.IntegrationsRequired()
+		if expression == "" {
+			return nil
+		}
+		referenced, err := cel.References(expression)
+		if err != nil {
+			return fmt.Errorf("chart %q: %w", name, err)
+		}
+		if slices.Contains(referenced, integrationName) {
+			dependencies = append(dependencies, d)
+		}
+		return nil
+	})
+	return dependencies, err
+}
+
 // NewCollection creates a new Collection from the given charts. It returns an
 // error if there are duplicate charts and product names.
 func NewCollection(_ *api.AppContext, charts []chart.Chart) (*Collection, error) {
@@ -108,6 +146,28 @@ func NewCollection(_ *api.AppContext, charts []chart.Chart) (*Collection, error)
 		if _, err := d.Weight(); err != nil {
 			return nil, fmt.Errorf("%w:  %w", ErrInvalidCollection, err)
 		}
+		// Asserting the Helm hooks annotations are valid.
+		if _, err := d.NoHooks(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCollection, err)
+		}
+		if _, err := d.HooksTimeout(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCollection, err)
+		}
+		if _, err := d.HookDeletePolicy(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCollection, err)
+		}
+		if _, err := d.NamespacePolicy(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCollection, err)
+		}
+		// Asserting the product properties schema is valid.
+		if _, err := d.PropertiesSchema(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCollection, err)
+		}
+		// Asserting the sizing presets are valid.
+		if _, err := d.SizingPresets(); err != nil {
+			return nil, fmt.Errorf("%w: chart %q: %w",
+				ErrInvalidCollection, d.Name(), err)
+		}
 		// Dependencies in the collection must have unique names.
 		if _, err := c.Get(d.Name()); err == nil {
 			return nil, fmt.Errorf("%w: duplicate chart: %s",
@@ -22,4 +22,40 @@ func TestNewCollection(t *testing.T) {
 	c, err := NewCollection(appCtx, charts)
 	g.Expect(err).To(o.Succeed())
 	g.Expect(c).NotTo(o.BeNil())
+	g.Expect(c.ProductNames()).To(o.Equal(
+		[]string{"Product A", "Product B", "Product C", "Product D"}))
+}
+
+func TestCollection_GetDependenciesRequiringIntegration(t *testing.T) {
+	g := o.NewWithT(t)
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	charts, err := cfs.GetAllCharts()
+	g.Expect(err).To(o.Succeed())
+	c, err := NewCollection(api.NewAppContext("helmet-ex"), charts)
+	g.Expect(err).To(o.Succeed())
+
+	cel, err := NewCEL("acs", "quay", "nexus")
+	g.Expect(err).To(o.Succeed())
+
+	names := func(integrationName string) []string {
+		dependencies, err := c.GetDependenciesRequiringIntegration(
+			cel, integrationName)
+		g.Expect(err).To(o.Succeed())
+		names := []string{}
+		for _, d := range dependencies {
+			names = append(names, d.Name())
+		}
+		return names
+	}
+	g.Expect(names("acs")).To(o.Equal(
+		[]string{"helmet-integrations", "helmet-product-c"}))
+	g.Expect(names("nexus")).To(o.Equal([]string{"helmet-product-d"}))
+	g.Expect(names("github")).To(o.BeEmpty())
+
+	// Expressions referencing unknown integrations are invalid.
+	cel, err = NewCEL("acs")
+	g.Expect(err).To(o.Succeed())
+	_, err = c.GetDependenciesRequiringIntegration(cel, "acs")
+	g.Expect(err).To(o.MatchError(ErrInvalidExpression))
 }
@@ -3,12 +3,23 @@ package resolver
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
 	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
 )
 
+// SizingDir the chart directory holding the sizing presets, one values file per
+// sizing profile, for instance "sizing/small.yaml".
+const SizingDir = "sizing"
+
 // Dependency represent a installer Dependency, which consists of a Helm chart
 // instance, namespace and metadata. The relevant Helm chart metadata is read by
 // helper methods.
@@ -85,11 +96,47 @@ func (d *Dependency) ProductName() string {
 	return d.getAnnotation(annotations.ProductName)
 }
 
+// ProductRequires returns the products required by the product chart, from the
+// chart annotations.
+func (d *Dependency) ProductRequires() []string {
+	return commaSeparatedToSlice(d.getAnnotation(annotations.ProductRequires))
+}
+
+// ProductConflicts returns the products conflicting with the product chart,
+// from the chart annotations.
+func (d *Dependency) ProductConflicts() []string {
+	return commaSeparatedToSlice(d.getAnnotation(annotations.ProductConflicts))
+}
+
 // UseProductNamespace returns the product namespace from the chart annotations.
 func (d *Dependency) UseProductNamespace() string {
 	return d.getAnnotation(annotations.UseProductNamespace)
 }
 
+// NamespaceProduct returns the product whose namespace the dependency is deployed
+// on, the product the chart belongs to takes precedence over the
+// "use-product-namespace" annotation. Empty means the installer namespace.
+func (d *Dependency) NamespaceProduct() string {
+	if p := d.ProductName(); p != "" {
+		return p
+	}
+	return d.UseProductNamespace()
+}
+
+// NamespaceRule describes how the dependency namespace is chosen: the namespace
+// of the product the chart belongs to, the one of the product informed by
+// "use-product-namespace", or the installer namespace.
+func (d *Dependency) NamespaceRule() string {
+	switch {
+	case d.ProductName() != "":
+		return fmt.Sprintf("product %q", d.ProductName())
+	case d.UseProductNamespace() != "":
+		return fmt.Sprintf("use-product-namespace %q", d.UseProductNamespace())
+	default:
+		return "installer"
+	}
+}
+
 // IntegrationsProvided returns the integrations provided.
 func (d *Dependency) IntegrationsProvided() []string {
 	provided := d.getAnnotation(annotations.IntegrationsProvided)
@@ -101,6 +148,164 @@ func (d *Dependency) IntegrationsRequired() string {
 	return d.getAnnotation(annotations.IntegrationsRequired)
 }
 
+// ReleaseNotes returns the chart version release notes, "what's new", from the
+// chart annotations.
+func (d *Dependency) ReleaseNotes() string {
+	return strings.TrimSpace(d.getAnnotation(annotations.ReleaseNotes))
+}
+
+// BreakingChanges returns the chart version breaking changes, from the chart
+// annotations.
+func (d *Dependency) BreakingChanges() string {
+	return strings.TrimSpace(d.getAnnotation(annotations.BreakingChanges))
+}
+
+// License returns the chart license, a SPDX license expression, from the chart
+// annotations.
+func (d *Dependency) License() string {
+	return strings.TrimSpace(d.getAnnotation(annotations.License))
+}
+
+// PropertiesSchema returns the schema of the product properties declared on the
+// chart annotations, nil when the chart doesn't declare it.
+func (d *Dependency) PropertiesSchema() (config.PropertiesSchema, error) {
+	v := d.getAnnotation(annotations.PropertiesSchema)
+	if strings.TrimSpace(v) == "" {
+		return nil, nil
+	}
+	schema, err := config.ParsePropertiesSchema(v)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"invalid value for annotation %q: %w", annotations.PropertiesSchema, err)
+	}
+	return schema, nil
+}
+
+// SizingPresets returns the values presets declared by the chart, by sizing
+// profile, the files on SizingDir. Only the known profiles are accepted.
+func (d *Dependency) SizingPresets() (map[string]chartutil.Values, error) {
+	presets := map[string]chartutil.Values{}
+	for _, f := range d.chart.Files {
+		dir, file, ok := strings.Cut(f.Name, "/")
+		if !ok || dir != SizingDir {
+			continue
+		}
+		profile := strings.TrimSuffix(file, ".yaml")
+		if profile == file || !slices.Contains(config.SizingProfiles, profile) {
+			return nil, fmt.Errorf(
+				"invalid sizing preset %q, expected %s/<profile>.yaml, "+
+					"profiles: %v", f.Name, SizingDir, config.SizingProfiles)
+		}
+		values, err := chartutil.ReadValues(f.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sizing preset %q: %w", f.Name, err)
+		}
+		presets[profile] = values
+	}
+	return presets, nil
+}
+
+// SizingPreset returns the chart values preset for the sizing profile, nil when
+// the chart doesn't declare it.
+func (d *Dependency) SizingPreset(profile string) (chartutil.Values, error) {
+	if profile == "" {
+		return nil, nil
+	}
+	presets, err := d.SizingPresets()
+	if err != nil {
+		return nil, err
+	}
+	return presets[profile], nil
+}
+
+// NoHooks returns whether Helm hooks are disabled for this dependency, the
+// annotation must be a valid boolean. By default hooks are enabled.
+func (d *Dependency) NoHooks() (bool, error) {
+	v := d.getAnnotation(annotations.NoHooks)
+	if v == "" {
+		return false, nil
+	}
+	noHooks, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf(
+			"invalid value %q for annotation %q", v, annotations.NoHooks)
+	}
+	return noHooks, nil
+}
+
+// Downscale returns whether the chart supports the downscaling values overlay,
+// applied on single-node clusters, the annotation must be a valid boolean. By
+// default charts don't support it.
+func (d *Dependency) Downscale() (bool, error) {
+	v := d.getAnnotation(annotations.Downscale)
+	if v == "" {
+		return false, nil
+	}
+	downscale, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf(
+			"invalid value %q for annotation %q", v, annotations.Downscale)
+	}
+	return downscale, nil
+}
+
+// HooksTimeout returns the timeout for the Helm hooks of this dependency, it
+// raises the release timeout when longer than the global timeout. When the
+// annotation is not set, zero is returned and the global timeout applies.
+func (d *Dependency) HooksTimeout() (time.Duration, error) {
+	v := d.getAnnotation(annotations.HooksTimeout)
+	if v == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(v)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf(
+			"invalid value %q for annotation %q", v, annotations.HooksTimeout)
+	}
+	return timeout, nil
+}
+
+// hookDeletePolicies valid Helm hook deletion policies.
+var hookDeletePolicies = []string{
+	"before-hook-creation",
+	"hook-succeeded",
+	"hook-failed",
+}
+
+// HookDeletePolicy returns the default deletion policies for the Helm hooks of
+// this dependency, applied to hooks without their own deletion policy.
+func (d *Dependency) HookDeletePolicy() ([]string, error) {
+	v := d.getAnnotation(annotations.HookDeletePolicy)
+	if v == "" {
+		return nil, nil
+	}
+	policies := commaSeparatedToSlice(v)
+	for _, p := range policies {
+		if !slices.Contains(hookDeletePolicies, p) {
+			return nil, fmt.Errorf(
+				"invalid value %q for annotation %q, expected one of: %v",
+				p, annotations.HookDeletePolicy, hookDeletePolicies)
+		}
+	}
+	return policies, nil
+}
+
+// NamespacePolicy returns how the deploy engine handles the target namespace,
+// by default the namespace is not managed.
+func (d *Dependency) NamespacePolicy() (k8s.NamespacePolicy, error) {
+	v := d.getAnnotation(annotations.NamespacePolicy)
+	if v == "" {
+		return k8s.NamespaceIgnore, nil
+	}
+	policy := k8s.NamespacePolicy(v)
+	if !slices.Contains(k8s.NamespacePolicies, policy) {
+		return "", fmt.Errorf(
+			"invalid value %q for annotation %q, expected one of: %v",
+			v, annotations.NamespacePolicy, k8s.NamespacePolicies)
+	}
+	return policy, nil
+}
+
 // NewDependency creates a new Dependency for the Helm chart and initially using
 // empty target namespace.
 func NewDependency(hc *chart.Chart) *Dependency {
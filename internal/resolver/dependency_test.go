@@ -3,10 +3,14 @@ package resolver
 import (
 	"os"
 	"testing"
+	"time"
 
+	"github.com/redhat-appstudio/helmet/internal/annotations"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
 
 	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
 )
 
 func TestNewDependency(t *testing.T) {
@@ -45,3 +49,176 @@ func TestNewDependency(t *testing.T) {
 		g.Expect(d.UseProductNamespace()).To(o.BeEmpty())
 	})
 }
+
+func TestDependencyHooks(t *testing.T) {
+	newDependency := func(annotations map[string]string) *Dependency {
+		return NewDependency(&chart.Chart{Metadata: &chart.Metadata{
+			Name:        "test",
+			Annotations: annotations,
+		}})
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		g := o.NewWithT(t)
+		d := newDependency(map[string]string{})
+
+		noHooks, err := d.NoHooks()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(noHooks).To(o.BeFalse())
+
+		timeout, err := d.HooksTimeout()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(timeout).To(o.BeZero())
+
+		policies, err := d.HookDeletePolicy()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(policies).To(o.BeEmpty())
+
+		downscale, err := d.Downscale()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(downscale).To(o.BeFalse())
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		d := newDependency(map[string]string{
+			annotations.NoHooks:          "true",
+			annotations.HooksTimeout:     "20m",
+			annotations.HookDeletePolicy: "hook-succeeded, hook-failed",
+			annotations.Downscale:        "true",
+		})
+
+		noHooks, err := d.NoHooks()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(noHooks).To(o.BeTrue())
+
+		timeout, err := d.HooksTimeout()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(timeout).To(o.Equal(20 * time.Minute))
+
+		policies, err := d.HookDeletePolicy()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(policies).To(o.Equal([]string{"hook-succeeded", "hook-failed"}))
+
+		downscale, err := d.Downscale()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(downscale).To(o.BeTrue())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		d := newDependency(map[string]string{
+			annotations.NoHooks:          "maybe",
+			annotations.HooksTimeout:     "-1m",
+			annotations.HookDeletePolicy: "always",
+			annotations.Downscale:        "maybe",
+		})
+
+		_, err := d.NoHooks()
+		g.Expect(err).NotTo(o.Succeed())
+
+		_, err = d.HooksTimeout()
+		g.Expect(err).NotTo(o.Succeed())
+
+		_, err = d.HookDeletePolicy()
+		g.Expect(err).NotTo(o.Succeed())
+
+		_, err = d.Downscale()
+		g.Expect(err).NotTo(o.Succeed())
+	})
+}
+
+func TestDependencyNamespacePolicy(t *testing.T) {
+	g := o.NewWithT(t)
+	newDependency := func(policy string) *Dependency {
+		return NewDependency(&chart.Chart{Metadata: &chart.Metadata{
+			Name: "test",
+			Annotations: map[string]string{
+				annotations.NamespacePolicy: policy,
+			},
+		}})
+	}
+
+	policy, err := newDependency("").NamespacePolicy()
+	g.Expect(err).To(o.Succeed())
+	g.Expect(policy).To(o.Equal(k8s.NamespaceIgnore))
+
+	policy, err = newDependency("adopt").NamespacePolicy()
+	g.Expect(err).To(o.Succeed())
+	g.Expect(policy).To(o.Equal(k8s.NamespaceAdopt))
+
+	_, err = newDependency("delete").NamespacePolicy()
+	g.Expect(err).NotTo(o.Succeed())
+}
+
+func TestDependencySizingPresets(t *testing.T) {
+	newDependency := func(files ...*chart.File) *Dependency {
+		return NewDependency(&chart.Chart{
+			Metadata: &chart.Metadata{Name: "test"},
+			Files:    files,
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		d := newDependency(
+			&chart.File{Name: "README.md", Data: []byte("# test")},
+			&chart.File{Name: "sizing/small.yaml", Data: []byte("replicas: 1")},
+			&chart.File{Name: "sizing/large.yaml", Data: []byte("replicas: 3")},
+		)
+
+		presets, err := d.SizingPresets()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(presets).To(o.HaveLen(2))
+
+		preset, err := d.SizingPreset("large")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(preset).To(o.HaveKeyWithValue("replicas", o.BeEquivalentTo(3)))
+
+		preset, err = d.SizingPreset("medium")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(preset).To(o.BeNil())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := newDependency(
+			&chart.File{Name: "sizing/huge.yaml", Data: []byte("replicas: 9")},
+		).SizingPresets()
+		g.Expect(err).NotTo(o.Succeed())
+
+		_, err = newDependency(
+			&chart.File{Name: "sizing/small.yaml", Data: []byte("- replicas")},
+		).SizingPresets()
+		g.Expect(err).NotTo(o.Succeed())
+	})
+}
+
+func TestDependencyNamespaceRule(t *testing.T) {
+	g := o.NewWithT(t)
+	newDependency := func(annotated map[string]string) *Dependency {
+		return NewDependency(&chart.Chart{Metadata: &chart.Metadata{
+			Name:        "test",
+			Annotations: annotated,
+		}})
+	}
+
+	d := newDependency(nil)
+	g.Expect(d.NamespaceProduct()).To(o.BeEmpty())
+	g.Expect(d.NamespaceRule()).To(o.Equal("installer"))
+
+	d = newDependency(map[string]string{
+		annotations.UseProductNamespace: "Product A",
+	})
+	g.Expect(d.NamespaceProduct()).To(o.Equal("Product A"))
+	g.Expect(d.NamespaceRule()).
+		To(o.Equal(`use-product-namespace "Product A"`))
+
+	// The product the chart belongs to takes precedence.
+	d = newDependency(map[string]string{
+		annotations.ProductName:         "Product B",
+		annotations.UseProductNamespace: "Product A",
+	})
+	g.Expect(d.NamespaceProduct()).To(o.Equal("Product B"))
+	g.Expect(d.NamespaceRule()).To(o.Equal(`product "Product B"`))
+}
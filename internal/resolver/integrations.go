@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/integrations"
 )
@@ -19,9 +20,11 @@ type Integrations struct {
 
 var (
 	// ErrUnknownIntegration the integration name is not supported, unknown.
-	ErrUnknownIntegration = errors.New("unknown integration")
+	ErrUnknownIntegration = helmeterrors.New(helmeterrors.ErrTopologyUnresolved,
+		"unknown integration")
 	// ErrPrerequisiteIntegration dependency prerequisite integration(s) missing.
-	ErrPrerequisiteIntegration = errors.New(
+	ErrPrerequisiteIntegration = helmeterrors.New(
+		helmeterrors.ErrPrerequisitesMissing,
 		"dependency prerequisite integration(s) missing")
 )
 
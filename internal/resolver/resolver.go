@@ -6,6 +6,7 @@ import (
 	"strings"
 	"text/tabwriter"
 
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 	"github.com/redhat-appstudio/helmet/internal/config"
 )
 
@@ -17,25 +18,20 @@ type Resolver struct {
 }
 
 // ErrCircularDependency reports a circular dependency.
-var ErrCircularDependency = fmt.Errorf("circular dependency detected")
+var ErrCircularDependency = helmeterrors.New(helmeterrors.ErrTopologyUnresolved,
+	"circular dependency detected")
 
 // ErrMissingDependency reports an unmet dependency.
-var ErrMissingDependency = fmt.Errorf("unmet dependency detected")
+var ErrMissingDependency = helmeterrors.New(helmeterrors.ErrTopologyUnresolved,
+	"unmet dependency detected")
 
 // setDependencyNamespace sets the desired namespace on the informed dependency.
 // By default, charts are deployed on the same namespace than the installer, while
 // product assossiated dependencies will use the namespace configured for it.
 func (r *Resolver) setDependencyNamespace(d *Dependency) error {
-	var product string
-	// Check if the Helm chart should use the product namespace.
-	if p := d.UseProductNamespace(); p != "" {
-		product = p
-	}
-	// Check if the Helm chart is associated with a product, which takes
-	// precedence over the "use-product-namespace" annotation.
-	if p := d.ProductName(); p != "" {
-		product = p
-	}
+	// Product charts, or charts using a product namespace, are deployed on the
+	// product namespace.
+	product := d.NamespaceProduct()
 
 	// Choosing the namespace for the dependency, a product chart will use what's
 	// defined for it, while regular charts will use the installer's namespace.
@@ -101,6 +97,51 @@ func (r *Resolver) dependsOn(
 	return nil
 }
 
+// checkProductConstraints asserts the enabled products satisfy the requires and
+// conflicts constraints declared on their charts, before resolving anything.
+func (r *Resolver) checkProductConstraints() error {
+	for _, product := range r.cfg.GetEnabledProducts() {
+		d, err := r.collection.GetProductDependency(product.Name)
+		if err != nil {
+			return err
+		}
+		if err = r.cfg.CheckConstraints(
+			product.Name, d.ProductRequires(), d.ProductConflicts()); err != nil {
+			return fmt.Errorf("chart %q: %w", d.Name(), err)
+		}
+	}
+	return nil
+}
+
+// checkProductProperties asserts the properties of the enabled products match the
+// schema declared on their charts, the absent properties assume the schema
+// defaults on the configuration in memory.
+func (r *Resolver) checkProductProperties() error {
+	for i, product := range r.cfg.Installer.Products {
+		if !product.Enabled {
+			continue
+		}
+		d, err := r.collection.GetProductDependency(product.Name)
+		if err != nil {
+			return err
+		}
+		schema, err := d.PropertiesSchema()
+		if err != nil {
+			return err
+		}
+		if schema == nil {
+			continue
+		}
+		properties, err := schema.Apply(product.Properties)
+		if err != nil {
+			return fmt.Errorf("chart %q: product %q: %w",
+				d.Name(), product.Name, err)
+		}
+		r.cfg.Installer.Products[i].Properties = properties
+	}
+	return nil
+}
+
 // resolveEnabledProducts resolves the dependencies of enabled products.
 func (r *Resolver) resolveEnabledProducts() error {
 	for _, product := range r.cfg.GetEnabledProducts() {
@@ -168,6 +209,12 @@ func (r *Resolver) resolveDependencies() error {
 
 // Resolve resolves the all dependencies in the collection to create the topology.
 func (r *Resolver) Resolve() error {
+	if err := r.checkProductConstraints(); err != nil {
+		return err
+	}
+	if err := r.checkProductProperties(); err != nil {
+		return err
+	}
 	if err := r.resolveEnabledProducts(); err != nil {
 		return err
 	}
@@ -198,6 +245,33 @@ func (r *Resolver) Print(w io.Writer) {
 	table.Flush()
 }
 
+// PrintDetail prints the resolved dependencies with the chart annotations driving
+// the resolution, one block per dependency, for chart authors auditing them.
+func (r *Resolver) PrintDetail(w io.Writer) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	field := func(name string, value any) {
+		fmt.Fprintf(table, "    %s:\t%v\n", name, value)
+	}
+	for i, d := range r.topology.Dependencies() {
+		if i > 0 {
+			fmt.Fprintln(table)
+		}
+		weight, _ := d.Weight()
+		policy, _ := d.NamespacePolicy()
+		fmt.Fprintf(table, "%2d. %s\n", i+1, d.Name())
+		field("Namespace", fmt.Sprintf("%s (%s)", d.Namespace(), d.NamespaceRule()))
+		field("Namespace-Policy", policy)
+		field("Product", d.ProductName())
+		field("Product-Requires", strings.Join(d.ProductRequires(), ", "))
+		field("Product-Conflicts", strings.Join(d.ProductConflicts(), ", "))
+		field("Depends-On", strings.Join(d.DependsOn(), ", "))
+		field("Weight", weight)
+		field("Provided-Integrations", strings.Join(d.IntegrationsProvided(), ", "))
+		field("Required-Integrations", d.IntegrationsRequired())
+	}
+	table.Flush()
+}
+
 // NewResolver instantiates a new Resolver. It takes the configuration, collection
 // and topology as parameters.
 func NewResolver(cfg *config.Config, c *Collection, t *Topology) *Resolver {
@@ -1,14 +1,20 @@
 package resolver
 
 import (
+	"bytes"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
 	"github.com/redhat-appstudio/helmet/internal/chartfs"
 	"github.com/redhat-appstudio/helmet/internal/config"
 
 	o "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
 )
 
 // resolveTopology creates a new Topology and resolves it using the provided
@@ -90,6 +96,119 @@ func TestNewResolver(t *testing.T) {
 		}))
 	})
 
+	t.Run("Resolve/product constraints", func(t *testing.T) {
+		g := o.NewWithT(t)
+		constrained := func(annotation, value string) *Collection {
+			annotated := make([]chart.Chart, 0, len(charts))
+			for _, hc := range charts {
+				if hc.Name() == "helmet-product-a" {
+					metadata := *hc.Metadata
+					metadata.Annotations = maps.Clone(metadata.Annotations)
+					metadata.Annotations[annotation] = value
+					hc.Metadata = &metadata
+				}
+				annotated = append(annotated, hc)
+			}
+			c, err := NewCollection(appCtx, annotated)
+			g.Expect(err).To(o.Succeed())
+			return c
+		}
+
+		r := NewResolver(cfg, constrained(
+			annotations.ProductRequires, "Product B, Product C"), NewTopology())
+		g.Expect(r.Resolve()).To(o.Succeed())
+
+		r = NewResolver(cfg, constrained(
+			annotations.ProductConflicts, "Product D"), NewTopology())
+		g.Expect(r.Resolve()).To(o.MatchError(o.ContainSubstring(
+			`chart "helmet-product-a": invalid configuration: ` +
+				`product "Product A" conflicts with product "Product D"`)))
+
+		r = NewResolver(cfg, constrained(
+			annotations.ProductRequires, "Product E"), NewTopology())
+		g.Expect(r.Resolve()).To(o.MatchError(config.ErrInvalidConfig))
+	})
+
+	t.Run("Resolve/product properties", func(t *testing.T) {
+		g := o.NewWithT(t)
+		withSchema := func(schema string) *Collection {
+			annotated := make([]chart.Chart, 0, len(charts))
+			for _, hc := range charts {
+				if hc.Name() == "helmet-product-b" {
+					metadata := *hc.Metadata
+					metadata.Annotations = maps.Clone(metadata.Annotations)
+					metadata.Annotations[annotations.PropertiesSchema] = schema
+					hc.Metadata = &metadata
+				}
+				annotated = append(annotated, hc)
+			}
+			c, err := NewCollection(appCtx, annotated)
+			g.Expect(err).To(o.Succeed())
+			return c
+		}
+
+		// The absent properties assume the schema defaults.
+		cfgCopy, err := cfg.DeepCopy()
+		g.Expect(err).To(o.Succeed())
+		r := NewResolver(cfgCopy, withSchema(`
+storageClass:
+  type: string
+  allowed: [standard, fast]
+replicas:
+  type: int
+  default: 2
+`), NewTopology())
+		g.Expect(r.Resolve()).To(o.Succeed())
+		productB, err := cfgCopy.GetProduct("Product B")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(productB.Properties).To(o.HaveKeyWithValue("replicas", 2))
+
+		r = NewResolver(cfg, withSchema(`
+storageClass:
+  type: string
+  allowed: [fast]
+`), NewTopology())
+		g.Expect(r.Resolve()).To(o.MatchError(config.ErrInvalidProperty))
+
+		r = NewResolver(cfg, withSchema(`
+region:
+  type: string
+  required: true
+`), NewTopology())
+		g.Expect(r.Resolve()).To(o.MatchError(o.ContainSubstring(
+			`chart "helmet-product-b": product "Product B": ` +
+				`invalid product property: "region" is required`)))
+
+		annotated := slices.Clone(charts)
+		for i := range annotated {
+			if annotated[i].Name() == "helmet-product-b" {
+				metadata := *annotated[i].Metadata
+				metadata.Annotations = map[string]string{
+					annotations.PropertiesSchema: "replicas: {type: list}",
+				}
+				annotated[i].Metadata = &metadata
+			}
+		}
+		_, err = NewCollection(appCtx, annotated)
+		g.Expect(err).To(o.MatchError(ErrInvalidCollection))
+	})
+
+	t.Run("PrintDetail", func(t *testing.T) {
+		g := o.NewWithT(t)
+		r := NewResolver(cfg, c, NewTopology())
+		g.Expect(r.Resolve()).To(o.Succeed())
+
+		var buf bytes.Buffer
+		r.PrintDetail(&buf)
+		g.Expect(buf.String()).To(o.ContainSubstring(" 1. helmet-foundation\n"))
+		g.Expect(buf.String()).To(o.MatchRegexp(
+			`Namespace: +helmet-product-a \(product "Product A"\)`))
+		g.Expect(buf.String()).To(o.MatchRegexp(
+			`Namespace: +test-namespace \(installer\)`))
+		g.Expect(strings.Count(buf.String(), "Required-Integrations:")).
+			To(o.Equal(len(r.topology.Dependencies())))
+	})
+
 	t.Run("Inspect", func(t *testing.T) {
 		topology := resolveTopology(g, cfg, c)
 
@@ -25,6 +25,16 @@ func (t *Topology) GetDependency(name string) (*Dependency, error) {
 	return nil, fmt.Errorf("dependency %q not found", name)
 }
 
+// GetProductDependency returns the dependency of the informed product.
+func (t *Topology) GetProductDependency(product string) (*Dependency, error) {
+	for i := range t.dependencies {
+		if name := t.dependencies[i].ProductName(); name != "" && name == product {
+			return &t.dependencies[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: for product %s", ErrDependencyNotFound, product)
+}
+
 // Contains checks if a dependency Contains in the topology.
 func (t *Topology) Contains(name string) bool {
 	for _, d := range t.dependencies {
@@ -32,6 +32,10 @@ func TestNewTopology(t *testing.T) {
 	g.Expect(err).To(o.Succeed())
 	networkingDep := NewDependencyWithNamespace(networkingChart, ns)
 
+	productAChart, err := cfs.GetChartFiles("charts/helmet-product-a")
+	g.Expect(err).To(o.Succeed())
+	productADep := NewDependencyWithNamespace(productAChart, ns)
+
 	topology := NewTopology()
 
 	t.Run("Append", func(t *testing.T) {
@@ -64,4 +68,14 @@ func TestNewTopology(t *testing.T) {
 			"helmet-networking",
 		}))
 	})
+
+	t.Run("GetProductDependency", func(t *testing.T) {
+		topology.Append(*productADep)
+		dep, err := topology.GetProductDependency("Product A")
+		g.Expect(err).To(o.Succeed())
+		g.Expect(dep.Name()).To(o.Equal("helmet-product-a"))
+
+		_, err = topology.GetProductDependency("Product B")
+		g.Expect(err).To(o.MatchError(ErrDependencyNotFound))
+	})
 }
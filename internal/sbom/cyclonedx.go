@@ -0,0 +1,131 @@
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// CycloneDXVersion the CycloneDX specification version of the documents.
+const CycloneDXVersion = "1.5"
+
+// purl returns the OCI package URL of the image, pinned to its digest, empty
+// when the digest is unknown.
+func (c *Component) purl() string {
+	if c.Kind != KindImage || c.Digest == "" {
+		return ""
+	}
+	query := url.Values{"repository_url": {c.Name}}
+	if c.Version != "" {
+		query.Set("tag", c.Version)
+	}
+	return "pkg:oci/" + path.Base(c.Name) + "@" +
+		url.PathEscape(c.Digest) + "?" + query.Encode()
+}
+
+// sha256 returns the hex encoded SHA-256 image digest, empty otherwise.
+func (c *Component) sha256() string {
+	hex, ok := strings.CutPrefix(c.Digest, "sha256:")
+	if !ok {
+		return ""
+	}
+	return hex
+}
+
+// cycloneDXComponent the CycloneDX representation of the component.
+func (c *Component) cycloneDXComponent() map[string]any {
+	component := map[string]any{
+		"bom-ref": c.ID(),
+		"type":    "application",
+		"name":    c.Name,
+	}
+	if c.Version != "" {
+		component["version"] = c.Version
+	}
+	if c.License != "" {
+		component["licenses"] = []any{map[string]any{"expression": c.License}}
+	}
+	switch c.Kind {
+	case KindChart:
+		component["properties"] = []any{
+			map[string]any{"name": "helmet:kind", "value": string(c.Kind)},
+			map[string]any{"name": "helmet:namespace", "value": c.Namespace},
+		}
+	case KindImage:
+		component["type"] = "container"
+		if purl := c.purl(); purl != "" {
+			component["purl"] = purl
+		}
+		if hex := c.sha256(); hex != "" {
+			component["hashes"] = []any{
+				map[string]any{"alg": "SHA-256", "content": hex},
+			}
+		}
+		if c.Source != "" {
+			component["externalReferences"] = []any{
+				map[string]any{"type": "vcs", "url": c.Source},
+			}
+		}
+	}
+	return component
+}
+
+// writeCycloneDX writes the document as CycloneDX JSON. The application depends
+// on the charts, and each chart on its images.
+func (d *Document) writeCycloneDX(w io.Writer) error {
+	app := map[string]any{
+		"bom-ref": "application:" + d.Name,
+		"type":    "application",
+		"name":    d.Name,
+		"version": d.Version,
+	}
+	components := []any{}
+	for _, c := range d.Components() {
+		components = append(components, c.cycloneDXComponent())
+	}
+	charts := []string{}
+	dependencies := []any{}
+	for _, c := range d.Charts {
+		charts = append(charts, c.ID())
+		images := []string{}
+		for _, reference := range c.Images {
+			images = append(images, KindImage.id(reference))
+		}
+		dependencies = append(dependencies, map[string]any{
+			"ref": c.ID(), "dependsOn": images,
+		})
+	}
+	for _, c := range d.Images {
+		dependencies = append(dependencies, map[string]any{
+			"ref": c.ID(), "dependsOn": []string{},
+		})
+	}
+	dependencies = append([]any{map[string]any{
+		"ref": app["bom-ref"], "dependsOn": charts,
+	}}, dependencies...)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]any{
+		"bomFormat":    "CycloneDX",
+		"specVersion":  CycloneDXVersion,
+		"serialNumber": "urn:uuid:" + d.Serial,
+		"version":      1,
+		"metadata": map[string]any{
+			"timestamp": d.Created.Format(time.RFC3339),
+			"tools": map[string]any{
+				"components": []any{map[string]any{
+					"type":    "application",
+					"name":    d.Name,
+					"version": d.Version,
+				}},
+			},
+			"component": app,
+		},
+		"components":   components,
+		"dependencies": dependencies,
+	})
+}
@@ -0,0 +1,65 @@
+package sbom
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// Image labels carrying the license and the source, the OCI annotations, and
+// the labels of the images built before them.
+var (
+	licenseLabels = []string{"org.opencontainers.image.licenses", "license"}
+	sourceLabels  = []string{
+		"org.opencontainers.image.source", "io.openshift.build.source-location",
+	}
+)
+
+// label returns the first label found on the image configuration.
+func label(labels map[string]string, keys []string) string {
+	for _, k := range keys {
+		if v := labels[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Inspect reads the image metadata from the registry, its digest, the digest
+// of the image index for multi-platform images, and its license and source
+// from the image labels, the labels of the informed platform image. The
+// registry credentials are read from the Docker configuration.
+func (c *Component) Inspect(ctx context.Context, options ...remote.Option) error {
+	ref, err := name.ParseReference(c.Reference)
+	if err != nil {
+		return err
+	}
+	options = append([]remote.Option{
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain),
+	}, options...)
+	desc, err := remote.Get(ref, options...)
+	if err != nil {
+		return fmt.Errorf("inspecting image %q: %w", c.Reference, err)
+	}
+	c.Digest = desc.Digest.String()
+	img, err := desc.Image()
+	if err != nil {
+		return fmt.Errorf("inspecting image %q: %w", c.Reference, err)
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return fmt.Errorf("inspecting image %q configuration: %w",
+			c.Reference, err)
+	}
+	if c.License == "" {
+		c.License = label(cfg.Config.Labels, licenseLabels)
+	}
+	if c.Source == "" {
+		c.Source = label(cfg.Config.Labels, sourceLabels)
+	}
+	return nil
+}
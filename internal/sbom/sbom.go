@@ -0,0 +1,197 @@
+package sbom
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/uuid"
+)
+
+// Document formats, informed as "--format <format>".
+const (
+	// FormatCycloneDX a CycloneDX 1.5 JSON document, default.
+	FormatCycloneDX = "cyclonedx"
+	// FormatSPDX an SPDX 2.3 JSON document.
+	FormatSPDX = "spdx"
+	// FormatLicenses a human readable license report, a table.
+	FormatLicenses = "licenses"
+)
+
+// Formats the document formats supported.
+var Formats = []string{FormatCycloneDX, FormatSPDX, FormatLicenses}
+
+// ErrInvalidFormat the document format is unknown.
+var ErrInvalidFormat = helmeterrors.New(helmeterrors.ErrInvalidUsage,
+	"invalid SBOM format")
+
+// Kind the component kind.
+type Kind string
+
+const (
+	// KindChart a Helm chart, a dependency of the resolved topology.
+	KindChart Kind = "chart"
+	// KindImage a container image, of the chart workloads.
+	KindImage Kind = "image"
+)
+
+// id returns the identifier of the component of the kind, named.
+func (k Kind) id(name string) string {
+	return string(k) + ":" + name
+}
+
+// Component a chart or container image of the installed stack.
+type Component struct {
+	Kind      Kind     `json:"kind"`                // component kind
+	Name      string   `json:"name"`                // chart name, or image repository
+	Version   string   `json:"version,omitempty"`   // chart version, or image tag
+	Namespace string   `json:"namespace,omitempty"` // chart target namespace
+	Reference string   `json:"reference,omitempty"` // image reference, as rendered
+	Digest    string   `json:"digest,omitempty"`    // image manifest digest
+	License   string   `json:"license,omitempty"`   // SPDX license expression
+	Source    string   `json:"source,omitempty"`    // image source repository
+	Images    []string `json:"images,omitempty"`    // chart images, by reference
+}
+
+// ID returns the component identifier, unique on the document.
+func (c *Component) ID() string {
+	if c.Kind == KindImage {
+		return c.Kind.id(c.Reference)
+	}
+	return c.Kind.id(c.Name)
+}
+
+// NewImage returns the image component of the reference, the version is the
+// reference tag, or its digest.
+func NewImage(reference string) (*Component, error) {
+	ref, err := name.ParseReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	c := &Component{
+		Kind:      KindImage,
+		Name:      ref.Context().Name(),
+		Reference: reference,
+	}
+	switch r := ref.(type) {
+	case name.Tag:
+		c.Version = r.TagStr()
+	case name.Digest:
+		c.Digest = r.DigestStr()
+	}
+	// A reference may carry both, "<repository>:<tag>@<digest>".
+	if tag, _, ok := strings.Cut(reference, "@"); ok {
+		if t, err := name.NewTag(tag, name.StrictValidation); err == nil {
+			c.Version = t.TagStr()
+		}
+	}
+	return c, nil
+}
+
+// Document the bill of materials of the installed stack, the application, the
+// charts of the resolved topology and their container images.
+type Document struct {
+	Name    string    // application name
+	Version string    // application version
+	Created time.Time // document creation time
+	Serial  string    // document unique identifier, a UUID
+
+	Charts []*Component // charts, in deployment order
+	Images []*Component // images, sorted by reference
+}
+
+// AddChart adds the chart component, and the images of its workloads not on
+// the document yet.
+func (d *Document) AddChart(chart *Component, images []string) error {
+	chart.Kind = KindChart
+	chart.Images = images
+	d.Charts = append(d.Charts, chart)
+	for _, reference := range images {
+		if d.Image(reference) != nil {
+			continue
+		}
+		image, err := NewImage(reference)
+		if err != nil {
+			return fmt.Errorf("chart %q: invalid image %q: %w",
+				chart.Name, reference, err)
+		}
+		d.Images = append(d.Images, image)
+	}
+	slices.SortFunc(d.Images, func(a, b *Component) int {
+		return strings.Compare(a.Reference, b.Reference)
+	})
+	return nil
+}
+
+// Image returns the image component of the reference, nil when not found.
+func (d *Document) Image(reference string) *Component {
+	for _, image := range d.Images {
+		if image.Reference == reference {
+			return image
+		}
+	}
+	return nil
+}
+
+// Components returns the charts and images.
+func (d *Document) Components() []*Component {
+	return append(slices.Clone(d.Charts), d.Images...)
+}
+
+// Write writes the document on the informed format.
+func (d *Document) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatCycloneDX:
+		return d.writeCycloneDX(w)
+	case FormatSPDX:
+		return d.writeSPDX(w)
+	case FormatLicenses:
+		return d.writeLicenses(w)
+	}
+	return fmt.Errorf("%w: %q, expected one of %s", ErrInvalidFormat,
+		format, strings.Join(Formats, ", "))
+}
+
+// writeLicenses writes the license report, a table of the components and their
+// licenses, the unknown licenses are reported as such.
+func (d *Document) writeLicenses(w io.Writer) error {
+	t := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(t, "KIND\tNAME\tVERSION\tLICENSE")
+	unknown := 0
+	for _, c := range d.Components() {
+		license := c.License
+		if license == "" {
+			license = "UNKNOWN"
+			unknown++
+		}
+		version := c.Version
+		if version == "" {
+			version = c.Digest
+		}
+		fmt.Fprintf(t, "%s\t%s\t%s\t%s\n", c.Kind, c.Name, version, license)
+	}
+	if err := t.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%d components, %d without a known license.\n",
+		len(d.Charts)+len(d.Images), unknown)
+	return err
+}
+
+// NewDocument instantiates the document of the application.
+func NewDocument(appName, appVersion string) *Document {
+	return &Document{
+		Name:    appName,
+		Version: appVersion,
+		Created: time.Now().UTC(),
+		Serial:  uuid.NewString(),
+		Charts:  []*Component{},
+		Images:  []*Component{},
+	}
+}
@@ -0,0 +1,224 @@
+package sbom
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	o "github.com/onsi/gomega"
+)
+
+const digest = "sha256:" +
+	"4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"
+
+func TestNewImage(t *testing.T) {
+	g := o.NewWithT(t)
+	c, err := NewImage("quay.io/example/app:1.0")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(c.Name).To(o.Equal("quay.io/example/app"))
+	g.Expect(c.Version).To(o.Equal("1.0"))
+	g.Expect(c.Digest).To(o.BeEmpty())
+
+	c, err = NewImage("quay.io/example/app@" + digest)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(c.Version).To(o.BeEmpty())
+	g.Expect(c.Digest).To(o.Equal(digest))
+
+	c, err = NewImage("quay.io/example/app:1.0@" + digest)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(c.Version).To(o.Equal("1.0"))
+	g.Expect(c.Digest).To(o.Equal(digest))
+
+	_, err = NewImage("quay.io/Example/app:1.0")
+	g.Expect(err).To(o.HaveOccurred())
+}
+
+// testDocument a document with two charts sharing an image.
+func testDocument(g *o.WithT) *Document {
+	d := NewDocument("helmet-ex", "v1.0.0")
+	d.Created = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	d.Serial = "00000000-0000-0000-0000-000000000000"
+	g.Expect(d.AddChart(&Component{
+		Name: "product-a", Version: "0.1.0", Namespace: "product-a",
+		License: "Apache-2.0",
+	}, []string{
+		"quay.io/example/app:1.0@" + digest,
+		"quay.io/example/tools:2.0",
+	})).To(o.Succeed())
+	g.Expect(d.AddChart(&Component{
+		Name: "product-b", Version: "0.2.0", Namespace: "product-b",
+	}, []string{"quay.io/example/tools:2.0"})).To(o.Succeed())
+	d.Image("quay.io/example/app:1.0@" + digest).License = "MIT"
+	return d
+}
+
+func TestDocument(t *testing.T) {
+	t.Run("AddChart", func(t *testing.T) {
+		g := o.NewWithT(t)
+		d := testDocument(g)
+		g.Expect(d.Charts).To(o.HaveLen(2))
+		g.Expect(d.Images).To(o.HaveLen(2))
+		g.Expect(d.Components()).To(o.HaveLen(4))
+
+		err := d.AddChart(&Component{Name: "product-c"}, []string{"Invalid"})
+		g.Expect(err).To(o.MatchError(o.ContainSubstring("product-c")))
+	})
+
+	t.Run("CycloneDX", func(t *testing.T) {
+		g := o.NewWithT(t)
+		var b strings.Builder
+		g.Expect(testDocument(g).Write(&b, FormatCycloneDX)).To(o.Succeed())
+		var bom struct {
+			BOMFormat    string `json:"bomFormat"`
+			SerialNumber string `json:"serialNumber"`
+			Components   []struct {
+				Ref      string `json:"bom-ref"`
+				Type     string `json:"type"`
+				PURL     string `json:"purl"`
+				Licenses []struct {
+					Expression string `json:"expression"`
+				} `json:"licenses"`
+			} `json:"components"`
+			Dependencies []struct {
+				Ref       string   `json:"ref"`
+				DependsOn []string `json:"dependsOn"`
+			} `json:"dependencies"`
+		}
+		g.Expect(json.Unmarshal([]byte(b.String()), &bom)).To(o.Succeed())
+		g.Expect(bom.BOMFormat).To(o.Equal("CycloneDX"))
+		g.Expect(bom.SerialNumber).To(o.HavePrefix("urn:uuid:"))
+		g.Expect(bom.Components).To(o.HaveLen(4))
+		g.Expect(bom.Components[0].Licenses[0].Expression).
+			To(o.Equal("Apache-2.0"))
+		app := bom.Components[2]
+		g.Expect(app.Type).To(o.Equal("container"))
+		g.Expect(app.PURL).To(o.Equal("pkg:oci/app@sha256:" +
+			strings.TrimPrefix(digest, "sha256:") +
+			"?repository_url=quay.io%2Fexample%2Fapp&tag=1.0"))
+		g.Expect(bom.Components[3].PURL).To(o.BeEmpty())
+		g.Expect(bom.Dependencies[0].Ref).To(o.Equal("application:helmet-ex"))
+		g.Expect(bom.Dependencies[0].DependsOn).To(o.Equal(
+			[]string{"chart:product-a", "chart:product-b"}))
+		g.Expect(bom.Dependencies[2].DependsOn).To(o.Equal(
+			[]string{"image:quay.io/example/tools:2.0"}))
+	})
+
+	t.Run("SPDX", func(t *testing.T) {
+		g := o.NewWithT(t)
+		var b strings.Builder
+		g.Expect(testDocument(g).Write(&b, FormatSPDX)).To(o.Succeed())
+		var doc struct {
+			SPDXVersion string `json:"spdxVersion"`
+			Packages    []struct {
+				SPDXID          string `json:"SPDXID"`
+				LicenseDeclared string `json:"licenseDeclared"`
+			} `json:"packages"`
+			Relationships []struct {
+				Element string `json:"spdxElementId"`
+				Type    string `json:"relationshipType"`
+				Related string `json:"relatedSpdxElement"`
+			} `json:"relationships"`
+		}
+		g.Expect(json.Unmarshal([]byte(b.String()), &doc)).To(o.Succeed())
+		g.Expect(doc.SPDXVersion).To(o.Equal(SPDXVersion))
+		g.Expect(doc.Packages).To(o.HaveLen(5))
+		for _, pkg := range doc.Packages {
+			g.Expect(pkg.SPDXID).To(o.MatchRegexp(`^SPDXRef-[a-zA-Z0-9.-]+$`))
+		}
+		g.Expect(doc.Packages[2].LicenseDeclared).To(o.Equal(spdxNoAssertion))
+		g.Expect(doc.Relationships).To(o.HaveLen(6))
+		g.Expect(doc.Relationships[0].Type).To(o.Equal("DESCRIBES"))
+		g.Expect(doc.Relationships[5].Element).To(o.Equal(
+			"SPDXRef-chart-product-b"))
+		g.Expect(doc.Relationships[5].Related).To(o.Equal(
+			"SPDXRef-image-quay.io-example-tools-2.0"))
+	})
+
+	t.Run("Licenses", func(t *testing.T) {
+		g := o.NewWithT(t)
+		var b strings.Builder
+		g.Expect(testDocument(g).Write(&b, FormatLicenses)).To(o.Succeed())
+		g.Expect(b.String()).To(o.MatchRegexp(
+			`chart +product-a +0.1.0 +Apache-2.0`))
+		g.Expect(b.String()).To(o.MatchRegexp(
+			`image +quay.io/example/tools +2.0 +UNKNOWN`))
+		g.Expect(b.String()).To(o.ContainSubstring(
+			"4 components, 2 without a known license."))
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		g := o.NewWithT(t)
+		var b strings.Builder
+		g.Expect(testDocument(g).Write(&b, "xml")).
+			To(o.MatchError(ErrInvalidFormat))
+	})
+}
+
+// fakeRegistry serves the "example/app:1.0" image, labeled with its license
+// and source.
+func fakeRegistry(t *testing.T) (*httptest.Server, string) {
+	config := []byte(`{"architecture": "amd64", "os": "linux", "config": {
+		"Labels": {
+			"org.opencontainers.image.licenses": "Apache-2.0",
+			"io.openshift.build.source-location": "https://github.com/example/app"
+		}
+	}}`)
+	configSum := sha256.Sum256(config)
+	configDigest := "sha256:" + hex.EncodeToString(configSum[:])
+	manifest := []byte(fmt.Sprintf(`{
+		"schemaVersion": 2,
+		"mediaType": "application/vnd.oci.image.manifest.v1+json",
+		"config": {
+			"mediaType": "application/vnd.oci.image.config.v1+json",
+			"digest": %q,
+			"size": %d
+		},
+		"layers": []
+	}`, configDigest, len(config)))
+	manifestSum := sha256.Sum256(manifest)
+	manifestDigest := "sha256:" + hex.EncodeToString(manifestSum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/v2/":
+			case "/v2/example/app/manifests/1.0":
+				w.Header().Set("Content-Type",
+					"application/vnd.oci.image.manifest.v1+json")
+				w.Header().Set("Docker-Content-Digest", manifestDigest)
+				_, _ = w.Write(manifest)
+			case "/v2/example/app/blobs/" + configDigest:
+				_, _ = w.Write(config)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	t.Cleanup(server.Close)
+	return server, manifestDigest
+}
+
+func TestInspect(t *testing.T) {
+	g := o.NewWithT(t)
+	server, manifestDigest := fakeRegistry(t)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	c, err := NewImage(host + "/example/app:1.0")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(c.Inspect(context.Background())).To(o.Succeed())
+	g.Expect(c.Digest).To(o.Equal(manifestDigest))
+	g.Expect(c.License).To(o.Equal("Apache-2.0"))
+	g.Expect(c.Source).To(o.Equal("https://github.com/example/app"))
+
+	c, err = NewImage(host + "/example/missing:1.0")
+	g.Expect(err).To(o.Succeed())
+	g.Expect(c.Inspect(context.Background())).
+		To(o.MatchError(o.ContainSubstring("missing")))
+	g.Expect(c.Digest).To(o.BeEmpty())
+}
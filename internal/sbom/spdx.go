@@ -0,0 +1,122 @@
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+)
+
+// SPDXVersion the SPDX specification version of the documents.
+const SPDXVersion = "SPDX-2.3"
+
+// spdxNoAssertion the SPDX value for the information not known.
+const spdxNoAssertion = "NOASSERTION"
+
+// spdxInvalidRe matches the characters not allowed on SPDX identifiers.
+var spdxInvalidRe = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// spdxID returns the SPDX identifier of the element.
+func spdxID(id string) string {
+	return "SPDXRef-" + spdxInvalidRe.ReplaceAllString(id, "-")
+}
+
+// spdxPackage the SPDX package representation of the component.
+func (c *Component) spdxPackage() map[string]any {
+	license := c.License
+	if license == "" {
+		license = spdxNoAssertion
+	}
+	pkg := map[string]any{
+		"SPDXID":           spdxID(c.ID()),
+		"name":             c.Name,
+		"downloadLocation": spdxNoAssertion,
+		"filesAnalyzed":    false,
+		"licenseConcluded": spdxNoAssertion,
+		"licenseDeclared":  license,
+		"copyrightText":    spdxNoAssertion,
+	}
+	if c.Version != "" {
+		pkg["versionInfo"] = c.Version
+	}
+	switch c.Kind {
+	case KindChart:
+		pkg["primaryPackagePurpose"] = "APPLICATION"
+		if c.Namespace != "" {
+			pkg["comment"] = "Helm chart, deployed on the namespace " + c.Namespace
+		}
+	case KindImage:
+		pkg["primaryPackagePurpose"] = "CONTAINER"
+		if c.Source != "" {
+			pkg["sourceInfo"] = "built from " + c.Source
+		}
+		if hex := c.sha256(); hex != "" {
+			pkg["checksums"] = []any{map[string]any{
+				"algorithm": "SHA256", "checksumValue": hex,
+			}}
+		}
+		if purl := c.purl(); purl != "" {
+			pkg["externalRefs"] = []any{map[string]any{
+				"referenceCategory": "PACKAGE-MANAGER",
+				"referenceType":     "purl",
+				"referenceLocator":  purl,
+			}}
+		}
+	}
+	return pkg
+}
+
+// writeSPDX writes the document as SPDX JSON. The document describes the
+// application, which contains the charts, depending on their images.
+func (d *Document) writeSPDX(w io.Writer) error {
+	appID := spdxID("application:" + d.Name)
+	packages := []any{map[string]any{
+		"SPDXID":                appID,
+		"name":                  d.Name,
+		"versionInfo":           d.Version,
+		"downloadLocation":      spdxNoAssertion,
+		"filesAnalyzed":         false,
+		"licenseConcluded":      spdxNoAssertion,
+		"licenseDeclared":       spdxNoAssertion,
+		"copyrightText":         spdxNoAssertion,
+		"primaryPackagePurpose": "APPLICATION",
+	}}
+	for _, c := range d.Components() {
+		packages = append(packages, c.spdxPackage())
+	}
+	relationship := func(element, kind, related string) map[string]any {
+		return map[string]any{
+			"spdxElementId":      element,
+			"relationshipType":   kind,
+			"relatedSpdxElement": related,
+		}
+	}
+	relationships := []any{relationship("SPDXRef-DOCUMENT", "DESCRIBES", appID)}
+	for _, c := range d.Charts {
+		relationships = append(relationships,
+			relationship(appID, "CONTAINS", spdxID(c.ID())))
+		for _, reference := range c.Images {
+			relationships = append(relationships, relationship(
+				spdxID(c.ID()), "DEPENDS_ON", spdxID(KindImage.id(reference))))
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]any{
+		"spdxVersion": SPDXVersion,
+		"dataLicense": "CC0-1.0",
+		"SPDXID":      "SPDXRef-DOCUMENT",
+		"name":        d.Name + "-" + d.Version,
+		"documentNamespace": "https://" + annotations.RepoURI + "/spdx/" +
+			d.Name + "-" + d.Version + "-" + d.Serial,
+		"creationInfo": map[string]any{
+			"created":  d.Created.Format(time.RFC3339),
+			"creators": []string{"Tool: " + d.Name + "-" + d.Version},
+		},
+		"packages":      packages,
+		"relationships": relationships,
+	})
+}
@@ -0,0 +1,179 @@
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Product represents a new product to be added to the installer, it generates
+// the Helm chart skeleton, the values template section and the configuration
+// entry for the product.
+type Product struct {
+	Name                 string   // product name
+	Namespace            string   // product namespace
+	Weight               int      // chart weight annotation
+	DependsOn            []string // chart dependencies
+	IntegrationsProvided []string // integrations provided by the chart
+	IntegrationsRequired string   // CEL expression with required integrations
+}
+
+// chartYamlTmpl template for the product's "Chart.yaml".
+const chartYamlTmpl = `apiVersion: v2
+name: {{ .ChartName }}
+description: {{ .Name }}
+version: "0.1.0"
+annotations:
+  {{ .Annotations.ProductName }}: {{ printf "%q" .Name }}
+  {{ .Annotations.Weight }}: "{{ .Weight }}"
+{{- if .DependsOn }}
+  {{ .Annotations.DependsOn }}: {{ join .DependsOn }}
+{{- else }}
+  # {{ .Annotations.DependsOn }}: ""
+{{- end }}
+{{- if .IntegrationsProvided }}
+  {{ .Annotations.IntegrationsProvided }}: {{ join .IntegrationsProvided }}
+{{- else }}
+  # {{ .Annotations.IntegrationsProvided }}: ""
+{{- end }}
+{{- if .IntegrationsRequired }}
+  {{ .Annotations.IntegrationsRequired }}: {{ printf "%q" .IntegrationsRequired }}
+{{- else }}
+  # {{ .Annotations.IntegrationsRequired }}: ""
+{{- end }}
+`
+
+// valuesTemplateTmpl template for the product's section in the values template,
+// using alternative delimiters to output the values template actions.
+const valuesTemplateTmpl = `
+################################################################################
+# [[ .Title ]]
+################################################################################
+[[ .ValuesKey ]]:
+{{- with index .Installer.Products "[[ .KeyName ]]" }}
+  enabled: {{ .Enabled }}
+  namespace: {{ .Namespace }}
+  properties: {{- .Properties | default dict | toYaml | nindent 4 }}
+{{- end }}
+`
+
+// config returns the product configuration.
+func (p *Product) config() *config.Product {
+	namespace := p.Namespace
+	return &config.Product{
+		Name:      p.Name,
+		Enabled:   true,
+		Namespace: &namespace,
+	}
+}
+
+// KeyName returns the product key name, used in the values template.
+func (p *Product) KeyName() string {
+	return p.config().KeyName()
+}
+
+// ValuesKey returns the product's root key in the Helm chart values.
+func (p *Product) ValuesKey() string {
+	return strings.ToLower(p.KeyName())
+}
+
+// ChartName returns the product's Helm chart name.
+func (p *Product) ChartName() string {
+	return strings.ReplaceAll(p.ValuesKey(), "_", "-")
+}
+
+// Validate asserts the product name and namespace are informed.
+func (p *Product) Validate() error {
+	if p.Name == "" || p.KeyName() == "" {
+		return fmt.Errorf("invalid product name %q", p.Name)
+	}
+	if p.Namespace == "" {
+		return fmt.Errorf("product %q: missing namespace", p.Name)
+	}
+	return nil
+}
+
+// render renders the informed template using the product as context.
+func (p *Product) render(tmpl *template.Template) ([]byte, error) {
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, struct {
+		*Product
+		Title       string
+		ChartName   string
+		KeyName     string
+		ValuesKey   string
+		Annotations map[string]string
+	}{
+		Product:   p,
+		Title:     strings.ToUpper(p.Name),
+		ChartName: p.ChartName(),
+		KeyName:   p.KeyName(),
+		ValuesKey: p.ValuesKey(),
+		Annotations: map[string]string{
+			"ProductName":          annotations.ProductName,
+			"Weight":               annotations.Weight,
+			"DependsOn":            annotations.DependsOn,
+			"IntegrationsProvided": annotations.IntegrationsProvided,
+			"IntegrationsRequired": annotations.IntegrationsRequired,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ChartFiles returns the Helm chart skeleton files, the file path is relative
+// to the installer's charts directory.
+func (p *Product) ChartFiles() (map[string][]byte, error) {
+	tmpl, err := template.New("Chart.yaml").
+		Funcs(template.FuncMap{
+			"join": func(s []string) string { return strings.Join(s, ", ") },
+		}).
+		Parse(chartYamlTmpl)
+	if err != nil {
+		return nil, err
+	}
+	chartYaml, err := p.render(tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := p.ChartName()
+	return map[string][]byte{
+		path.Join(dir, "Chart.yaml"): chartYaml,
+		path.Join(dir, "values.yaml"): fmt.Appendf(
+			nil, "---\n%s: {}\n", p.ValuesKey()),
+		path.Join(dir, "templates", "NOTES.txt"): fmt.Appendf(
+			nil, "%s is deployed on namespace {{ .Release.Namespace }}.\n", p.Name),
+	}, nil
+}
+
+// ValuesTemplate returns the product's section for the values template.
+func (p *Product) ValuesTemplate() ([]byte, error) {
+	tmpl, err := template.New("values.yaml.tpl").
+		Delims("[[", "]]").
+		Parse(valuesTemplateTmpl)
+	if err != nil {
+		return nil, err
+	}
+	return p.render(tmpl)
+}
+
+// ConfigEntry returns the product entry added to the installer configuration,
+// as an item of the "products" list.
+func (p *Product) ConfigEntry() ([]byte, error) {
+	return yaml.Marshal([]config.Product{*p.config()})
+}
+
+// AddToConfig adds the product, enabled, to the installer configuration.
+func (p *Product) AddToConfig(cfg *config.Config) error {
+	return cfg.AddProduct(*p.config())
+}
@@ -0,0 +1,94 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/engine"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+
+	o "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+func TestProduct(t *testing.T) {
+	g := o.NewWithT(t)
+
+	p := &Product{
+		Name:                 "Product X",
+		Namespace:            "product-x",
+		Weight:               10,
+		DependsOn:            []string{"helmet-foundation", "helmet-operators"},
+		IntegrationsRequired: "quay || nexus",
+	}
+	g.Expect(p.Validate()).To(o.Succeed())
+	g.Expect(p.KeyName()).To(o.Equal("Product_X"))
+	g.Expect(p.ValuesKey()).To(o.Equal("product_x"))
+	g.Expect(p.ChartName()).To(o.Equal("product-x"))
+
+	t.Run("ChartFiles", func(t *testing.T) {
+		files, err := p.ChartFiles()
+		g.Expect(err).To(o.Succeed())
+
+		dir := t.TempDir()
+		for name, payload := range files {
+			path := filepath.Join(dir, name)
+			g.Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(o.Succeed())
+			g.Expect(os.WriteFile(path, payload, 0o600)).To(o.Succeed())
+		}
+
+		// The generated chart must be loadable, and the annotations must be
+		// understood by the resolver.
+		hc, err := chartfs.New(os.DirFS(dir)).GetChartFiles(p.ChartName())
+		g.Expect(err).To(o.Succeed())
+		d := resolver.NewDependency(hc)
+		g.Expect(d.Name()).To(o.Equal("product-x"))
+		g.Expect(d.ProductName()).To(o.Equal("Product X"))
+		g.Expect(d.DependsOn()).To(o.Equal(p.DependsOn))
+		g.Expect(d.IntegrationsProvided()).To(o.BeEmpty())
+		g.Expect(d.IntegrationsRequired()).To(o.Equal("quay || nexus"))
+		weight, err := d.Weight()
+		g.Expect(err).To(o.Succeed())
+		g.Expect(weight).To(o.Equal(10))
+	})
+
+	t.Run("ValuesTemplate", func(t *testing.T) {
+		cfg, err := config.NewConfigFromFile(
+			chartfs.New(os.DirFS("../../test")),
+			"config.yaml",
+			"test-namespace",
+			"helmet_ex",
+		)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(p.AddToConfig(cfg)).To(o.Succeed())
+
+		tmpl, err := p.ValuesTemplate()
+		g.Expect(err).To(o.Succeed())
+
+		variables := engine.NewVariables()
+		g.Expect(variables.SetInstaller(cfg)).To(o.Succeed())
+		payload, err := engine.NewEngine(nil, string(tmpl)).Render(variables)
+		g.Expect(err).To(o.Succeed())
+
+		values := map[string]map[string]interface{}{}
+		g.Expect(yaml.Unmarshal(payload, &values)).To(o.Succeed())
+		g.Expect(values).To(o.HaveKey("product_x"))
+		g.Expect(values["product_x"]).To(o.HaveKeyWithValue("enabled", true))
+		g.Expect(values["product_x"]).To(
+			o.HaveKeyWithValue("namespace", "product-x"))
+	})
+
+	t.Run("ConfigEntry", func(t *testing.T) {
+		payload, err := p.ConfigEntry()
+		g.Expect(err).To(o.Succeed())
+		entries := []config.Product{}
+		g.Expect(yaml.Unmarshal(payload, &entries)).To(o.Succeed())
+		g.Expect(entries).To(o.HaveLen(1))
+		g.Expect(entries[0].Name).To(o.Equal("Product X"))
+		g.Expect(entries[0].Enabled).To(o.BeTrue())
+		g.Expect(entries[0].GetNamespace()).To(o.Equal("product-x"))
+	})
+}
@@ -0,0 +1,138 @@
+package scan
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+)
+
+// Mode how the policy findings are handled.
+type Mode string
+
+const (
+	// ModeOff the rendered manifests aren't scanned on deploy.
+	ModeOff Mode = "off"
+	// ModeWarn the findings are reported, the deployment proceeds.
+	ModeWarn Mode = "warn"
+	// ModeEnforce the findings are reported and fail the deployment.
+	ModeEnforce Mode = "enforce"
+)
+
+// Setting the installer setting with the security policy, for instance:
+//
+//	securityScan:
+//	  mode: enforce
+//	  rules: [privileged, host-path]
+const Setting = "securityScan"
+
+// Policy the security policy applied on the rendered manifests.
+type Policy struct {
+	Mode  Mode   // findings handling
+	Rules []Rule // rules applied
+}
+
+// ParseMode parses the informed mode name.
+func ParseMode(s string) (Mode, error) {
+	mode := Mode(s)
+	if !slices.Contains([]Mode{ModeOff, ModeWarn, ModeEnforce}, mode) {
+		return "", fmt.Errorf("invalid mode %q, expecting %q, %q or %q",
+			s, ModeOff, ModeWarn, ModeEnforce)
+	}
+	return mode, nil
+}
+
+// ParseRules parses the informed rule names, empty means all rules.
+func ParseRules(names []string) ([]Rule, error) {
+	if len(names) == 0 {
+		return slices.Clone(Rules), nil
+	}
+	rules := make([]Rule, 0, len(names))
+	for _, name := range names {
+		rule := Rule(name)
+		if !slices.Contains(Rules, rule) {
+			return nil, fmt.Errorf("invalid rule %q, expecting one of %v",
+				name, Rules)
+		}
+		if !slices.Contains(rules, rule) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// NewPolicyFromConfig returns the security policy informed on the installer
+// settings, by default the policy is off and applies all rules.
+func NewPolicyFromConfig(cfg *config.Config) (*Policy, error) {
+	policy := &Policy{Mode: ModeOff, Rules: slices.Clone(Rules)}
+	setting, ok := cfg.Installer.Settings[Setting]
+	if !ok || setting == nil {
+		return policy, nil
+	}
+	m, ok := config.AsMap(setting)
+	if !ok {
+		return nil, fmt.Errorf("%w: setting %q must be a map",
+			config.ErrInvalidConfig, Setting)
+	}
+	var err error
+	if mode, ok := m["mode"]; ok {
+		if policy.Mode, err = ParseMode(fmt.Sprint(mode)); err != nil {
+			return nil, fmt.Errorf("%w: setting %q: %w",
+				config.ErrInvalidConfig, Setting, err)
+		}
+	}
+	if rules, ok := m["rules"]; ok {
+		items, ok := rules.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: setting %q: rules must be a list",
+				config.ErrInvalidConfig, Setting)
+		}
+		names := make([]string, 0, len(items))
+		for _, item := range items {
+			names = append(names, fmt.Sprint(item))
+		}
+		if policy.Rules, err = ParseRules(names); err != nil {
+			return nil, fmt.Errorf("%w: setting %q: %w",
+				config.ErrInvalidConfig, Setting, err)
+		}
+	}
+	return policy, nil
+}
+
+// Enabled checks whether the rendered manifests are scanned on deploy.
+func (p *Policy) Enabled() bool {
+	return p != nil && p.Mode != ModeOff
+}
+
+// Scan applies the policy rules on the rendered manifests, see Scan.
+func (p *Policy) Scan(manifest string) ([]Finding, error) {
+	return Scan(p.Rules, manifest)
+}
+
+// Enforce returns ErrPolicyViolation when enforcing the policy and there are
+// findings.
+func (p *Policy) Enforce(findings []Finding) error {
+	if p.Mode != ModeEnforce || len(findings) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %d finding(s) on rules %v",
+		ErrPolicyViolation, len(findings), p.Rules)
+}
+
+// PrintReport prints the findings as a table.
+func PrintReport(w io.Writer, findings []Finding) {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "No security policy findings.")
+		return
+	}
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "DEPENDENCY\tNAMESPACE\tRULE\tRESOURCE\tCONTAINER\tMESSAGE")
+	for _, f := range findings {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			f.Dependency, f.Namespace, f.Rule, f.Resource, f.Container,
+			f.Message)
+	}
+	table.Flush()
+}
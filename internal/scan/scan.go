@@ -0,0 +1,230 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule a security check applied on the workloads of the rendered manifests.
+type Rule string
+
+const (
+	// RulePrivileged containers running privileged, or allowing privilege
+	// escalation.
+	RulePrivileged Rule = "privileged"
+	// RuleHostPath pods mounting volumes from the node filesystem.
+	RuleHostPath Rule = "host-path"
+	// RuleResourceLimits containers without CPU or memory limits.
+	RuleResourceLimits Rule = "resource-limits"
+)
+
+// Rules all the rules supported, the default rule set.
+var Rules = []Rule{RulePrivileged, RuleHostPath, RuleResourceLimits}
+
+// ErrPolicyViolation the rendered manifests violate the security policy, in
+// enforce mode.
+var ErrPolicyViolation = helmeterrors.New(helmeterrors.ErrDeployFailed,
+	"security policy violation")
+
+// Finding a workload violating a security rule.
+type Finding struct {
+	Dependency string `json:"dependency,omitempty"` // dependency scanned
+	Namespace  string `json:"namespace,omitempty"`  // dependency namespace
+	Rule       Rule   `json:"rule"`                 // rule violated
+	Resource   string `json:"resource"`             // "Kind/name"
+	Container  string `json:"container,omitempty"`  // container, when applicable
+	Message    string `json:"message"`              // violation description
+}
+
+// String describes the finding in a single line.
+func (f Finding) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", f.Rule, f.Resource)
+	if f.Container != "" {
+		fmt.Fprintf(&b, " container %q", f.Container)
+	}
+	fmt.Fprintf(&b, ": %s", f.Message)
+	return b.String()
+}
+
+// podSpecPaths the pod spec location per workload kind.
+var podSpecPaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// lookup walks the object on the informed keys, returning nil when any key is
+// missing.
+func lookup(obj map[string]any, keys ...string) any {
+	var current any = obj
+	for _, k := range keys {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = m[k]
+	}
+	return current
+}
+
+// listOf returns the list of objects on the informed keys.
+func listOf(obj map[string]any, keys ...string) []map[string]any {
+	items, _ := lookup(obj, keys...).([]any)
+	objects := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			objects = append(objects, m)
+		}
+	}
+	return objects
+}
+
+// isTrue checks whether the value on the informed keys is boolean true.
+func isTrue(obj map[string]any, keys ...string) bool {
+	v, ok := lookup(obj, keys...).(bool)
+	return ok && v
+}
+
+// isSet checks whether the value on the informed keys is informed.
+func isSet(obj map[string]any, keys ...string) bool {
+	v := lookup(obj, keys...)
+	return v != nil && v != ""
+}
+
+// checkPodSpec applies the rules on the pod spec of the resource.
+func checkPodSpec(rules []Rule, resource string, spec map[string]any) []Finding {
+	findings := []Finding{}
+	if slices.Contains(rules, RuleHostPath) {
+		for _, v := range listOf(spec, "volumes") {
+			if v["hostPath"] == nil {
+				continue
+			}
+			findings = append(findings, Finding{
+				Rule:     RuleHostPath,
+				Resource: resource,
+				Message: fmt.Sprintf("volume %q mounts host path %q",
+					v["name"], lookup(v, "hostPath", "path")),
+			})
+		}
+	}
+	containers := listOf(spec, "initContainers")
+	containers = append(containers, listOf(spec, "containers")...)
+	for _, c := range containers {
+		name, _ := c["name"].(string)
+		if slices.Contains(rules, RulePrivileged) {
+			if isTrue(c, "securityContext", "privileged") {
+				findings = append(findings, Finding{
+					Rule:      RulePrivileged,
+					Resource:  resource,
+					Container: name,
+					Message:   "runs privileged",
+				})
+			} else if isTrue(c, "securityContext", "allowPrivilegeEscalation") {
+				findings = append(findings, Finding{
+					Rule:      RulePrivileged,
+					Resource:  resource,
+					Container: name,
+					Message:   "allows privilege escalation",
+				})
+			}
+		}
+		if slices.Contains(rules, RuleResourceLimits) {
+			missing := []string{}
+			for _, r := range []string{"cpu", "memory"} {
+				if !isSet(c, "resources", "limits", r) {
+					missing = append(missing, r)
+				}
+			}
+			if len(missing) > 0 {
+				findings = append(findings, Finding{
+					Rule:      RuleResourceLimits,
+					Resource:  resource,
+					Container: name,
+					Message: fmt.Sprintf("missing %s limits",
+						strings.Join(missing, " and ")),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// workloads calls fn for the pod spec of each workload on the rendered
+// manifests, a multi-document YAML payload, in document order. The resource is
+// the workload "Kind/name".
+func workloads(
+	manifest string,
+	fn func(resource string, spec map[string]any),
+) error {
+	dec := yaml.NewDecoder(strings.NewReader(manifest))
+	for {
+		var obj map[string]any
+		err := dec.Decode(&obj)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("parsing rendered manifests: %w", err)
+		}
+		kind, _ := obj["kind"].(string)
+		path, ok := podSpecPaths[kind]
+		if !ok {
+			continue
+		}
+		spec, ok := lookup(obj, path...).(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _ := lookup(obj, "metadata", "name").(string)
+		fn(kind+"/"+name, spec)
+	}
+}
+
+// Scan applies the rules on the workloads of the rendered manifests, a
+// multi-document YAML payload, returning the findings in document order.
+func Scan(rules []Rule, manifest string) ([]Finding, error) {
+	findings := []Finding{}
+	err := workloads(manifest, func(resource string, spec map[string]any) {
+		findings = append(findings, checkPodSpec(rules, resource, spec)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// Images returns the container images of the workloads on the rendered
+// manifests, init and ephemeral containers included, sorted and without
+// duplicates.
+func Images(manifest string) ([]string, error) {
+	images := []string{}
+	err := workloads(manifest, func(_ string, spec map[string]any) {
+		for _, key := range []string{
+			"initContainers", "containers", "ephemeralContainers",
+		} {
+			for _, c := range listOf(spec, key) {
+				if image, _ := c["image"].(string); image != "" {
+					images = append(images, image)
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(images)
+	return slices.Compact(images), nil
+}
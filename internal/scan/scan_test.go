@@ -0,0 +1,187 @@
+package scan
+
+import (
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+
+	o "github.com/onsi/gomega"
+)
+
+const manifest = `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  privileged: "true"
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: agent
+spec:
+  template:
+    spec:
+      volumes:
+        - name: host
+          hostPath:
+            path: /var/run
+      initContainers:
+        - name: setup
+          image: quay.io/example/tools:1.0
+          securityContext:
+            allowPrivilegeEscalation: true
+          resources:
+            limits:
+              cpu: 100m
+              memory: 64Mi
+      containers:
+        - name: agent
+          image: quay.io/example/agent:2.1
+          securityContext:
+            privileged: true
+          resources:
+            limits:
+              memory: 128Mi
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: cleanup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: cleanup
+              image: quay.io/example/tools:1.0
+              resources:
+                limits:
+                  cpu: 100m
+                  memory: 64Mi
+`
+
+func TestScan(t *testing.T) {
+	t.Run("all rules", func(t *testing.T) {
+		g := o.NewWithT(t)
+		findings, err := Scan(Rules, manifest)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(findings).To(o.Equal([]Finding{{
+			Rule:     RuleHostPath,
+			Resource: "Deployment/agent",
+			Message:  `volume "host" mounts host path "/var/run"`,
+		}, {
+			Rule:      RulePrivileged,
+			Resource:  "Deployment/agent",
+			Container: "setup",
+			Message:   "allows privilege escalation",
+		}, {
+			Rule:      RulePrivileged,
+			Resource:  "Deployment/agent",
+			Container: "agent",
+			Message:   "runs privileged",
+		}, {
+			Rule:      RuleResourceLimits,
+			Resource:  "Deployment/agent",
+			Container: "agent",
+			Message:   "missing cpu limits",
+		}}))
+	})
+
+	t.Run("selected rules", func(t *testing.T) {
+		g := o.NewWithT(t)
+		findings, err := Scan([]Rule{RuleResourceLimits}, manifest)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(findings).To(o.HaveLen(1))
+		g.Expect(findings[0].String()).To(o.Equal(
+			`[resource-limits] Deployment/agent container "agent": missing cpu limits`))
+	})
+
+	t.Run("invalid manifest", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := Scan(Rules, "kind: [")
+		g.Expect(err).To(o.HaveOccurred())
+	})
+}
+
+func TestImages(t *testing.T) {
+	g := o.NewWithT(t)
+	images, err := Images(manifest)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(images).To(o.Equal([]string{
+		"quay.io/example/agent:2.1",
+		"quay.io/example/tools:1.0",
+	}))
+
+	_, err = Images("kind: [")
+	g.Expect(err).To(o.HaveOccurred())
+}
+
+func TestNewPolicyFromConfig(t *testing.T) {
+	cfs := chartfs.New(os.DirFS("../../test"))
+	newConfig := func(t *testing.T, setting any) *config.Config {
+		g := o.NewWithT(t)
+		cfg, err := config.NewConfigFromFile(
+			cfs, "config.yaml", "test-namespace", "helmet_ex")
+		g.Expect(err).To(o.Succeed())
+		if setting != nil {
+			cfg.Installer.Settings[Setting] = setting
+		}
+		return cfg
+	}
+
+	t.Run("unset", func(t *testing.T) {
+		g := o.NewWithT(t)
+		policy, err := NewPolicyFromConfig(newConfig(t, nil))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(policy.Enabled()).To(o.BeFalse())
+		g.Expect(policy.Rules).To(o.Equal(Rules))
+	})
+
+	t.Run("enforce", func(t *testing.T) {
+		g := o.NewWithT(t)
+		policy, err := NewPolicyFromConfig(newConfig(t, config.Settings{
+			"mode":  "enforce",
+			"rules": []any{"privileged", "host-path", "privileged"},
+		}))
+		g.Expect(err).To(o.Succeed())
+		g.Expect(policy.Enabled()).To(o.BeTrue())
+		g.Expect(policy.Rules).To(o.Equal([]Rule{RulePrivileged, RuleHostPath}))
+
+		findings, err := policy.Scan(manifest)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(findings).To(o.HaveLen(3))
+		g.Expect(policy.Enforce(findings)).To(o.MatchError(ErrPolicyViolation))
+		g.Expect(policy.Enforce(nil)).To(o.Succeed())
+	})
+
+	t.Run("warn", func(t *testing.T) {
+		g := o.NewWithT(t)
+		policy, err := NewPolicyFromConfig(newConfig(t, map[string]any{
+			"mode": "warn",
+		}))
+		g.Expect(err).To(o.Succeed())
+		findings, err := policy.Scan(manifest)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(policy.Enforce(findings)).To(o.Succeed())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		g := o.NewWithT(t)
+		_, err := NewPolicyFromConfig(newConfig(t, config.Settings{
+			"mode": "block",
+		}))
+		g.Expect(err).To(o.MatchError(config.ErrInvalidConfig))
+		_, err = NewPolicyFromConfig(newConfig(t, config.Settings{
+			"rules": []any{"seccomp"},
+		}))
+		g.Expect(err).To(o.MatchError(config.ErrInvalidConfig))
+		_, err = NewPolicyFromConfig(newConfig(t, "enforce"))
+		g.Expect(err).To(o.MatchError(config.ErrInvalidConfig))
+	})
+}
@@ -0,0 +1,127 @@
+package snapshot
+
+import (
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// Kube Kubernetes client backed by the snapshot objects, changes are kept in
+// memory and never reach a cluster.
+type Kube struct {
+	*k8s.FakeKube
+
+	openShift    k8s.OpenShiftInfo         // recorded OpenShift attributes
+	objects      []runtime.Object          // unstructured snapshot objects
+	apiResources []*metav1.APIResourceList // resources discovered
+}
+
+var (
+	_ k8s.Interface             = &Kube{}
+	_ k8s.OpenShiftInfoProvider = &Kube{}
+)
+
+// OpenShiftInfo returns the OpenShift attributes recorded on the snapshot.
+func (k *Kube) OpenShiftInfo() k8s.OpenShiftInfo {
+	return k.openShift
+}
+
+// DiscoveryClient returns a discovery client listing the resources recorded on
+// the snapshot.
+func (k *Kube) DiscoveryClient(namespace string) (discovery.DiscoveryInterface, error) {
+	dc, err := k.FakeKube.DiscoveryClient(namespace)
+	if err != nil {
+		return nil, err
+	}
+	if fake, ok := dc.(*fakediscovery.FakeDiscovery); ok {
+		fake.Resources = k.apiResources
+	}
+	return dc, nil
+}
+
+// DynamicClient returns a dynamic client backed by the snapshot objects.
+func (k *Kube) DynamicClient(string) (dynamic.Interface, error) {
+	return fakedynamic.NewSimpleDynamicClient(scheme.Scheme, k.objects...), nil
+}
+
+// GetDynamicClientForObjectRef returns a dynamic client for the object
+// reference. Kinds not recorded on the snapshot have no objects.
+func (k *Kube) GetDynamicClientForObjectRef(
+	objectRef *corev1.ObjectReference,
+) (dynamic.ResourceInterface, error) {
+	gvk := objectRef.GroupVersionKind()
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+	namespaced := objectRef.Namespace != ""
+	for _, list := range k.apiResources {
+		if list.GroupVersion != gvk.GroupVersion().String() {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if r.Kind == gvk.Kind {
+				gvr = gvk.GroupVersion().WithResource(r.Name)
+				namespaced = r.Namespaced
+			}
+		}
+	}
+	dynamicClient, err := k.DynamicClient(objectRef.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if namespaced {
+		return dynamicClient.Resource(gvr).Namespace(objectRef.Namespace), nil
+	}
+	return dynamicClient.Resource(gvr), nil
+}
+
+// NewKube instantiates the client with the recorded OpenShift attributes and
+// objects.
+func NewKube(
+	openShift k8s.OpenShiftInfo,
+	objects []unstructured.Unstructured,
+) (*Kube, error) {
+	typed, err := typedObjects(objects)
+	if err != nil {
+		return nil, err
+	}
+	k := &Kube{
+		FakeKube:  k8s.NewFakeKube(typed...),
+		openShift: openShift,
+	}
+	resources := map[schema.GroupVersion]*metav1.APIResourceList{}
+	known := map[schema.GroupVersionKind]bool{}
+	for i := range objects {
+		obj := objects[i].DeepCopy()
+		k.objects = append(k.objects, obj)
+
+		gvk := obj.GroupVersionKind()
+		if known[gvk] {
+			continue
+		}
+		known[gvk] = true
+		list, ok := resources[gvk.GroupVersion()]
+		if !ok {
+			list = &metav1.APIResourceList{
+				GroupVersion: gvk.GroupVersion().String(),
+			}
+			resources[gvk.GroupVersion()] = list
+			k.apiResources = append(k.apiResources, list)
+		}
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		list.APIResources = append(list.APIResources, metav1.APIResource{
+			Name:       gvr.Resource,
+			Kind:       gvk.Kind,
+			Namespaced: obj.GetNamespace() != "",
+		})
+	}
+	return k, nil
+}
@@ -0,0 +1,227 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Redacted replaces the secret data values when the snapshot is captured without
+// secret data.
+const Redacted = "REDACTED"
+
+// ErrInvalidSnapshot the snapshot file can't be used for the simulation.
+var ErrInvalidSnapshot = helmeterrors.New(helmeterrors.ErrInvalidUsage,
+	"invalid cluster snapshot")
+
+// Snapshot the cluster objects relevant for planning the deployment, recorded
+// to simulate the deployment offline.
+type Snapshot struct {
+	// CapturedAt when the snapshot was recorded.
+	CapturedAt time.Time `json:"capturedAt"`
+	// Namespace the installer namespace.
+	Namespace string `json:"namespace"`
+	// OpenShift the cluster attributes exposed to the values template.
+	OpenShift k8s.OpenShiftInfo `json:"openshift"`
+	// Objects the cluster objects recorded.
+	Objects []unstructured.Unstructured `json:"objects"`
+}
+
+// add records the typed object informed.
+func (s *Snapshot) add(obj runtime.Object, apiVersion, kind string) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	u := unstructured.Unstructured{Object: content}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	// Server managed metadata is not relevant for the simulation.
+	u.SetManagedFields(nil)
+	u.SetResourceVersion("")
+	s.Objects = append(s.Objects, u)
+	return nil
+}
+
+// Validate asserts the snapshot contains the installer configuration.
+func (s *Snapshot) Validate() error {
+	if s.Namespace == "" {
+		return fmt.Errorf("%w: namespace is not set", ErrInvalidSnapshot)
+	}
+	for _, obj := range s.Objects {
+		if obj.GetKind() == "ConfigMap" &&
+			obj.GetLabels()[annotations.Config] == "true" {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: installer configuration not found", ErrInvalidSnapshot)
+}
+
+// Kube returns a Kubernetes client backed by the snapshot objects.
+func (s *Snapshot) Kube() (*Kube, error) {
+	return NewKube(s.OpenShift, s.Objects)
+}
+
+// Save writes the snapshot as YAML on the informed path.
+func (s *Snapshot) Save(path string) error {
+	payload, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, payload, 0o600)
+}
+
+// Load reads the snapshot YAML from the informed path.
+func Load(path string) (*Snapshot, error) {
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{}
+	if err = yaml.Unmarshal(payload, s); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	if err = s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Capture records the cluster objects relevant for planning the deployment: the
+// installer configuration, the integration secrets, the installer and product
+// namespaces, and the OpenShift attributes. Unless secretData is enabled, the
+// integration secrets data is redacted.
+func Capture(
+	ctx context.Context,
+	kube k8s.Interface,
+	cm *config.ConfigMapManager,
+	integrationSecrets []string,
+	secretData bool,
+) (*Snapshot, error) {
+	configMap, err := cm.GetConfigMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := cm.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{
+		CapturedAt: time.Now().UTC(),
+		Namespace:  cfg.Namespace(),
+		OpenShift:  k8s.GetOpenShiftInfo(ctx, kube),
+	}
+	if err = s.add(configMap, "v1", "ConfigMap"); err != nil {
+		return nil, err
+	}
+
+	for _, name := range integrationSecrets {
+		secret, err := k8s.GetSecret(ctx, kube, types.NamespacedName{
+			Namespace: cfg.Namespace(),
+			Name:      name,
+		})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !secretData {
+			for k := range secret.Data {
+				secret.Data[k] = []byte(Redacted)
+			}
+			for k := range secret.StringData {
+				secret.StringData[k] = Redacted
+			}
+		}
+		if err = s.add(secret, "v1", "Secret"); err != nil {
+			return nil, err
+		}
+	}
+
+	namespaces := []string{cfg.Namespace()}
+	for _, product := range cfg.GetEnabledProducts() {
+		namespaces = append(namespaces, product.GetNamespace())
+	}
+	slices.Sort(namespaces)
+	coreClient, err := kube.CoreV1ClientSet(cfg.Namespace())
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range slices.Compact(namespaces) {
+		ns, err := coreClient.Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if err = s.add(ns, "v1", "Namespace"); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// objectsOf returns the typed objects of the informed kind, recorded on the
+// snapshot.
+func objectsOf[T any](objects []unstructured.Unstructured, kind string) ([]*T, error) {
+	typed := []*T{}
+	for _, obj := range objects {
+		if obj.GetAPIVersion() != "v1" || obj.GetKind() != kind {
+			continue
+		}
+		t := new(T)
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(
+			obj.Object, t,
+		); err != nil {
+			return nil, fmt.Errorf("%w: %s %q: %w",
+				ErrInvalidSnapshot, kind, obj.GetName(), err)
+		}
+		typed = append(typed, t)
+	}
+	return typed, nil
+}
+
+// typedObjects returns the core objects recorded on the snapshot, as typed
+// objects for the Kubernetes clientset.
+func typedObjects(objects []unstructured.Unstructured) ([]runtime.Object, error) {
+	typed := []runtime.Object{}
+	configMaps, err := objectsOf[corev1.ConfigMap](objects, "ConfigMap")
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range configMaps {
+		typed = append(typed, o)
+	}
+	secrets, err := objectsOf[corev1.Secret](objects, "Secret")
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range secrets {
+		typed = append(typed, o)
+	}
+	namespaces, err := objectsOf[corev1.Namespace](objects, "Namespace")
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range namespaces {
+		typed = append(typed, o)
+	}
+	return typed, nil
+}
@@ -0,0 +1,124 @@
+package snapshot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/constants"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSnapshot(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := config.NewConfigFromFile(
+		cfs, "config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+
+	// The cluster holds the configuration, an integration secret and the
+	// installer namespace.
+	kube := k8s.NewFakeKube(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "helmet-ex-config",
+				Namespace: "test-namespace",
+				Labels:    map[string]string{annotations.Config: "true"},
+			},
+			Data: map[string]string{constants.ConfigFilename: cfg.String()},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "helmet-ex-quay-integration",
+				Namespace: "test-namespace",
+			},
+			Data: map[string][]byte{"token": []byte("s3cr3t")},
+		},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"},
+		},
+	)
+
+	snap, err := Capture(
+		ctx,
+		kube,
+		config.NewConfigMapManager(kube, "helmet-ex"),
+		[]string{"helmet-ex-quay-integration", "helmet-ex-acs-integration"},
+		false,
+	)
+	g.Expect(err).To(o.Succeed())
+	g.Expect(snap.Namespace).To(o.Equal("test-namespace"))
+	g.Expect(snap.Objects).To(o.HaveLen(3))
+
+	path := filepath.Join(t.TempDir(), "snapshot.yaml")
+	g.Expect(snap.Save(path)).To(o.Succeed())
+	loaded, err := Load(path)
+	g.Expect(err).To(o.Succeed())
+
+	t.Run("Configuration", func(t *testing.T) {
+		offline, err := loaded.Kube()
+		g.Expect(err).To(o.Succeed())
+		stored, err := config.NewConfigMapManager(offline, "helmet-ex").
+			GetConfig(ctx)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(stored.String()).To(o.Equal(cfg.String()))
+	})
+
+	t.Run("Secrets", func(t *testing.T) {
+		offline, err := loaded.Kube()
+		g.Expect(err).To(o.Succeed())
+		secret, err := k8s.GetSecret(ctx, offline, types.NamespacedName{
+			Namespace: "test-namespace",
+			Name:      "helmet-ex-quay-integration",
+		})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(secret.Data).To(
+			o.HaveKeyWithValue("token", []byte(Redacted)))
+
+		exists, err := k8s.SecretExists(ctx, offline, types.NamespacedName{
+			Namespace: "test-namespace",
+			Name:      "helmet-ex-acs-integration",
+		})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(exists).To(o.BeFalse())
+	})
+
+	t.Run("Lookup", func(t *testing.T) {
+		offline, err := loaded.Kube()
+		g.Expect(err).To(o.Succeed())
+		client, err := offline.GetDynamicClientForObjectRef(
+			&corev1.ObjectReference{APIVersion: "v1", Kind: "Namespace"})
+		g.Expect(err).To(o.Succeed())
+		ns, err := client.Get(ctx, "test-namespace", metav1.GetOptions{})
+		g.Expect(err).To(o.Succeed())
+		g.Expect(ns.GetName()).To(o.Equal("test-namespace"))
+	})
+
+	t.Run("OpenShift", func(t *testing.T) {
+		offline, err := NewKube(k8s.OpenShiftInfo{
+			IngressDomain: "apps.example.com",
+			Version:       "4.18.3",
+		}, loaded.Objects)
+		g.Expect(err).To(o.Succeed())
+		info := k8s.GetOpenShiftInfo(ctx, offline)
+		g.Expect(info.IngressDomain).To(o.Equal("apps.example.com"))
+		g.Expect(info.Version).To(o.Equal("4.18.3"))
+		g.Expect(info.IngressRouterCA).To(o.BeEmpty())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		empty := &Snapshot{Namespace: "test-namespace"}
+		g.Expect(empty.Validate()).To(o.MatchError(ErrInvalidSnapshot))
+	})
+}
@@ -0,0 +1,228 @@
+package subcmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// CELEval represents the "cel eval" subcommand, it evaluates a integrations
+// requirement expression against the cluster integrations, or a state file.
+type CELEval struct {
+	cmd     *cobra.Command         // cobra command
+	appCtx  *api.AppContext        // application context
+	runCtx  *runcontext.RunContext // run context
+	flags   *flags.Flags           // global flags
+	manager *integrations.Manager  // integrations manager
+
+	statePath  string          // path to the integrations state file
+	expression string          // CEL expression to evaluate
+	configured map[string]bool // integrations state
+}
+
+var _ api.SubCommand = (*CELEval)(nil)
+
+const celEvalDesc = `
+Evaluates a CEL expression, as used in the "integrations-required" chart
+annotation, against the integrations configured in the cluster.
+
+Alternatively, the integrations state can be informed by a YAML file, mapping
+the integration names to a boolean, for instance:
+
+  github: true
+  quay: false
+
+Integrations not present in the state file are considered not configured.
+`
+
+// Cmd exposes the cobra instance.
+func (c *CELEval) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// log returns a decorated logger.
+func (c *CELEval) log() *slog.Logger {
+	return c.flags.LoggerWith(c.runCtx.Logger.With("type", "cel-eval"))
+}
+
+// Complete loads the expression and the integrations state.
+func (c *CELEval) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expecting one CEL expression, got %d",
+			helmeterrors.ErrInvalidUsage, len(args))
+	}
+	c.expression = args[0]
+
+	c.configured = map[string]bool{}
+	for _, name := range c.manager.IntegrationNames() {
+		c.configured[name] = false
+	}
+
+	// Loading the integrations state from the informed file.
+	if c.statePath != "" {
+		c.log().Debug("Loading integrations state file", "path", c.statePath)
+		payload, err := os.ReadFile(c.statePath)
+		if err != nil {
+			return err
+		}
+		state := map[string]bool{}
+		if err = yaml.Unmarshal(payload, &state); err != nil {
+			return fmt.Errorf("invalid integrations state file %q: %w",
+				c.statePath, err)
+		}
+		for name, configured := range state {
+			if _, exists := c.configured[name]; !exists {
+				return fmt.Errorf("%w: %q in state file %q",
+					resolver.ErrUnknownIntegration, name, c.statePath)
+			}
+			c.configured[name] = configured
+		}
+		return nil
+	}
+
+	// Loading the integrations state from the cluster.
+	cfg, err := bootstrapConfig(c.cmd.Context(), c.appCtx, c.runCtx)
+	if err != nil {
+		return err
+	}
+	c.log().Debug("Inspecting the cluster integrations")
+	configured, err := c.manager.ConfiguredIntegrations(c.cmd.Context(), cfg)
+	if err != nil {
+		return err
+	}
+	for _, name := range configured {
+		c.configured[name] = true
+	}
+	return nil
+}
+
+// Validate asserts the expression is informed.
+func (c *CELEval) Validate() error {
+	if strings.TrimSpace(c.expression) == "" {
+		return fmt.Errorf("%w: empty expression", resolver.ErrInvalidExpression)
+	}
+	return nil
+}
+
+// Run evaluates the expression and prints the result.
+func (c *CELEval) Run() error {
+	cel, err := resolver.NewCEL(c.manager.IntegrationNames()...)
+	if err != nil {
+		return err
+	}
+	err = cel.Evaluate(c.configured, c.expression)
+	switch {
+	case err == nil:
+		fmt.Printf("%q evaluates to true\n", c.expression)
+		return nil
+	case errors.Is(err, resolver.ErrMissingIntegrations):
+		fmt.Printf("%q evaluates to false, %s\n", c.expression, err)
+		return nil
+	default:
+		return err
+	}
+}
+
+// NewCELEval instantiates the "cel eval" subcommand.
+func NewCELEval(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+	manager *integrations.Manager,
+) *CELEval {
+	c := &CELEval{
+		cmd: &cobra.Command{
+			Use:          "eval <expression>",
+			Short:        "Evaluates a integrations requirement expression",
+			Long:         celEvalDesc,
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: manager,
+	}
+	c.cmd.PersistentFlags().StringVar(
+		&c.statePath,
+		"state",
+		"",
+		"YAML file with the integrations state, instead of the cluster",
+	)
+	return c
+}
+
+// CELVars represents the "cel vars" subcommand, it lists the identifiers
+// available for integrations requirement expressions.
+type CELVars struct {
+	cmd     *cobra.Command        // cobra command
+	manager *integrations.Manager // integrations manager
+}
+
+var _ api.SubCommand = (*CELVars)(nil)
+
+// Cmd exposes the cobra instance.
+func (c *CELVars) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// Complete noop.
+func (c *CELVars) Complete(_ []string) error {
+	return nil
+}
+
+// Validate noop.
+func (c *CELVars) Validate() error {
+	return nil
+}
+
+// Run prints the integration names, the variables in CEL expressions.
+func (c *CELVars) Run() error {
+	names := c.manager.IntegrationNames()
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Printf("%s\tbool\n", name)
+	}
+	return nil
+}
+
+// NewCELVars instantiates the "cel vars" subcommand.
+func NewCELVars(manager *integrations.Manager) *CELVars {
+	return &CELVars{
+		cmd: &cobra.Command{
+			Use:          "vars",
+			Short:        "Lists the identifiers available in expressions",
+			SilenceUsage: true,
+		},
+		manager: manager,
+	}
+}
+
+// NewCEL instantiates the "cel" command, grouping the helpers for authoring the
+// "integrations-required" chart annotation expressions.
+func NewCEL(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+	manager *integrations.Manager,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cel",
+		Short: "Helpers for integrations requirement CEL expressions",
+	}
+	cmd.AddCommand(api.NewRunner(NewCELEval(appCtx, runCtx, f, manager)).Cmd())
+	cmd.AddCommand(api.NewRunner(NewCELVars(manager)).Cmd())
+	return cmd
+}
@@ -0,0 +1,62 @@
+package subcmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+)
+
+// TestCELEval_ClusterState verifies the integrations state is loaded from the
+// cluster when no state file is informed.
+func TestCELEval_ClusterState(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	cfg := loadTestConfig(t)
+	runCtx := testRunContext(t,
+		configMapForConfig(cfg),
+		integrationSecret("quay"),
+	)
+	manager := testManager(t, runCtx)
+
+	c := NewCELEval(testAppContext(), runCtx, flags.NewFlags(), manager)
+	c.Cmd().SetContext(context.Background())
+	g.Expect(c.Complete([]string{"quay && acs"})).To(gomega.Succeed())
+	g.Expect(c.Validate()).To(gomega.Succeed())
+
+	g.Expect(c.configured).To(gomega.HaveKeyWithValue("quay", true))
+	g.Expect(c.configured).To(gomega.HaveKeyWithValue("acs", false))
+	g.Expect(c.Run()).To(gomega.Succeed())
+}
+
+// TestCELEval_StateFile verifies the integrations state is loaded from the
+// informed file, and unknown integration names are rejected.
+func TestCELEval_StateFile(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	runCtx := testRunContext(t)
+	manager := testManager(t, runCtx)
+
+	statePath := filepath.Join(t.TempDir(), "state.yaml")
+	err := os.WriteFile(statePath, []byte("github: true\nquay: false\n"), 0o600)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	c := NewCELEval(testAppContext(), runCtx, flags.NewFlags(), manager)
+	c.statePath = statePath
+	g.Expect(c.Complete([]string{"github || gitlab"})).To(gomega.Succeed())
+	g.Expect(c.configured).To(gomega.HaveKeyWithValue("github", true))
+	g.Expect(c.configured).To(gomega.HaveKeyWithValue("gitlab", false))
+	g.Expect(c.Run()).To(gomega.Succeed())
+
+	c.expression = "unknown && github"
+	g.Expect(c.Run()).To(gomega.MatchError(resolver.ErrInvalidExpression))
+
+	err = os.WriteFile(statePath, []byte("unknown: true\n"), 0o600)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(c.Complete([]string{"github"})).To(
+		gomega.MatchError(resolver.ErrUnknownIntegration))
+}
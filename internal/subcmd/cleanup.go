@@ -0,0 +1,142 @@
+package subcmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/cleanup"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// Cleanup is the cleanup subcommand, it removes the resources left behind by
+// crashed deployments.
+type Cleanup struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	cleanup   *cleanup.Cleanup // leftover resources finder
+	olderThan time.Duration    // age threshold
+}
+
+var _ api.SubCommand = (*Cleanup)(nil)
+
+const cleanupDesc = `
+Detects and removes the resources left behind by crashed, or interrupted,
+deployments:
+
+  - Helm release revisions stuck on "pending-install", "pending-upgrade" or
+    "pending-rollback", the release lock preventing further deployments.
+  - Finished installer Jobs, created by the MCP server deploy tool.
+  - Temporary RBAC resources and ServiceAccounts, removed at the end of every
+    successful deployment.
+
+Only resources older than the threshold (--older-than) are considered, so
+deployments in progress are not disturbed. With --dry-run the stale resources
+are listed without being removed.
+`
+
+// Cmd exposes the cobra instance.
+func (c *Cleanup) Cmd() *cobra.Command {
+	return c.cmd
+}
+
+// log returns a decorated logger.
+func (c *Cleanup) log() *slog.Logger {
+	return c.flags.LoggerWith(
+		c.runCtx.Logger.With("older-than", c.olderThan))
+}
+
+// Complete collects the Helm release names managed by the installer.
+func (c *Cleanup) Complete(_ []string) error {
+	charts, err := c.runCtx.ChartFS.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	releases := make([]string, 0, len(charts))
+	for _, hc := range charts {
+		releases = append(releases, hc.Name())
+	}
+	c.cleanup = cleanup.NewCleanup(
+		c.log(),
+		c.runCtx.Kube,
+		releases,
+		installer.NewJob(c.appCtx, c.runCtx.Kube).LabelSelector(),
+		c.olderThan,
+	)
+	return nil
+}
+
+// Validate asserts the age threshold is valid.
+func (c *Cleanup) Validate() error {
+	if c.olderThan <= 0 {
+		return fmt.Errorf("invalid --older-than %s, must be greater than zero",
+			c.olderThan)
+	}
+	return nil
+}
+
+// Run finds the stale resources and removes them, unless on dry-run.
+func (c *Cleanup) Run() error {
+	ctx := c.cmd.Context()
+	candidates, err := c.cleanup.Find(ctx)
+	if err != nil {
+		return err
+	}
+	out := c.cmd.OutOrStdout()
+	if len(candidates) == 0 {
+		fmt.Fprintf(out, "No stale resources older than %s found.\n",
+			c.olderThan)
+		return nil
+	}
+	if c.flags.DryRun {
+		cleanup.Print(out, candidates, "would delete")
+		return nil
+	}
+
+	var errs []error
+	for _, candidate := range candidates {
+		c.log().Debug("Deleting stale resource",
+			"kind", candidate.Kind,
+			"namespace", candidate.Namespace,
+			"name", candidate.Name,
+		)
+		if err := c.cleanup.Delete(ctx, candidate); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s/%s: %w", candidate.Kind,
+				candidate.Namespace, candidate.Name, err))
+		}
+	}
+	cleanup.Print(out, candidates, "deleted")
+	return errors.Join(errs...)
+}
+
+// NewCleanup instantiates the cleanup subcommand.
+func NewCleanup(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *Cleanup {
+	c := &Cleanup{
+		cmd: &cobra.Command{
+			Use:          "cleanup",
+			Short:        "Removes resources left behind by crashed deployments",
+			Long:         cleanupDesc,
+			SilenceUsage: true,
+		},
+		appCtx:    appCtx,
+		runCtx:    runCtx,
+		flags:     f,
+		olderThan: time.Hour,
+	}
+	c.cmd.PersistentFlags().DurationVar(&c.olderThan, "older-than",
+		c.olderThan, "Only remove resources older than the threshold")
+	return c
+}
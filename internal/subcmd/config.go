@@ -1,13 +1,18 @@
 package subcmd
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 
 	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/flags"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/printer"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
 
@@ -25,11 +30,19 @@ type Config struct {
 	manager    *config.ConfigMapManager // cluster configuration manager
 	configPath string                   // configuration file relative path
 
-	namespace string // installer's namespace
-	create    bool   // create a new configuration
-	force     bool   // overrides existing configuration
-	get       bool   // show the current configuration
-	delete    bool   // delete the current configuration
+	namespace   string        // installer's namespace
+	environment string        // environment overlay to apply
+	expandEnv   bool          // expand environment variables on the file
+	setEnv      flags.EnvVars // variables informed for the file
+	create      bool          // create a new configuration
+	discover    bool          // discover the cluster defaults
+	force       bool          // overrides existing configuration
+	get         bool          // show the current configuration
+	delete      bool          // delete the current configuration
+
+	output   string          // output format flag, only used with --get
+	out      *printer.Output // output printer
+	products []string        // products to show, only used with --get
 }
 
 var _ api.SubCommand = (*Config)(nil)
@@ -60,6 +73,22 @@ func (c *Config) PersistentFlags(p *pflag.FlagSet) {
 		c.appCtx.Namespace,
 		"Installer target namespace (only used with --create)",
 	)
+	p.StringVarP(
+		&c.environment,
+		"environment",
+		"e",
+		"",
+		"Environment overlay applied to the configuration (only used with --create)",
+	)
+	p.BoolVar(
+		&c.discover,
+		"discover",
+		false,
+		"Inspect the cluster to pre-populate the configuration defaults (only used with --create)",
+	)
+	flags.SetExpandEnvFlag(p, &c.expandEnv)
+	flags.SetSetEnvFlag(p, c.setEnv)
+	flags.SetClusterFlag(p, &c.flags.KubeContext)
 	p.BoolVarP(
 		&c.force,
 		"force",
@@ -81,18 +110,43 @@ func (c *Config) PersistentFlags(p *pflag.FlagSet) {
 		false,
 		"Delete the current cluster configuration",
 	)
+	flags.SetOutputFlag(p, &c.output)
+	p.Lookup(flags.OutputFlag).Usage = "Output format, only used with --get: " +
+		"table, json, yaml, jsonpath=<template>, go-template=<template> or " +
+		"custom-columns=<header>:<jsonpath>,... (default the raw configuration)"
+	p.StringSliceVar(
+		&c.products,
+		"product",
+		nil,
+		"Only show the informed products (only used with --get)",
+	)
 }
 
 // validateFlags validates the flags passed to the subcommand.
 func (c *Config) validateFlags() error {
 	if c.get && c.delete {
-		return fmt.Errorf("cannot use --get and --delete at the same time")
+		return fmt.Errorf("%w: cannot use --get and --delete at the same time",
+			helmeterrors.ErrInvalidUsage)
 	}
 	if !c.create && !c.force && !c.get && !c.delete {
-		return fmt.Errorf("either --create, --get or --delete must be set")
+		return fmt.Errorf("%w: either --create, --get or --delete must be set",
+			helmeterrors.ErrInvalidUsage)
 	}
 	if c.cmd.Flags().Changed("namespace") && !c.create {
-		return fmt.Errorf("--namespace flag can only be used with --create")
+		return fmt.Errorf("%w: --namespace flag can only be used with --create",
+			helmeterrors.ErrInvalidUsage)
+	}
+	if c.environment != "" && !c.create {
+		return fmt.Errorf("%w: --environment flag can only be used with --create",
+			helmeterrors.ErrInvalidUsage)
+	}
+	if c.discover && !c.create {
+		return fmt.Errorf("%w: --discover flag can only be used with --create",
+			helmeterrors.ErrInvalidUsage)
+	}
+	if (c.output != "" || len(c.products) > 0) && !c.get {
+		return fmt.Errorf("%w: --output and --product flags can only be used with --get",
+			helmeterrors.ErrInvalidUsage)
 	}
 	return nil
 }
@@ -102,7 +156,8 @@ func (c *Config) validateFlags() error {
 func (c *Config) Complete(args []string) error {
 	// It should return an error if more than a single argument is informed.
 	if len(args) > 1 {
-		return fmt.Errorf("unexpected arguments: %v", args)
+		return fmt.Errorf("%w: unexpected arguments: %v",
+			helmeterrors.ErrInvalidUsage, args)
 	}
 	// It should inform a configuration file only for apply and update flags.
 	if (c.get || c.delete) && !c.create && len(args) > 0 {
@@ -124,23 +179,47 @@ func (c *Config) Complete(args []string) error {
 // Validate make sure all items are in place.
 func (c *Config) Validate() error {
 	if c.create && c.configPath == "" {
-		return fmt.Errorf("configuration file is not informed")
+		return fmt.Errorf("%w: configuration file is not informed",
+			helmeterrors.ErrInvalidUsage)
 	}
 	if err := c.validateFlags(); err != nil {
 		return err
 	}
-	return nil
+	if c.output == "" && len(c.products) == 0 {
+		return nil
+	}
+	var err error
+	c.out, err = printer.NewOutput(c.output)
+	return err
 }
 
 // runCreate runs create action, makes sure a new configuration is applied in the
 // cluster and update when using the --force flag.
 func (c *Config) runCreate() error {
+	ctx := c.cmd.Context()
+	opts := configOptions(c.appCtx, c.flags, c.expandEnv, c.setEnv)
+	if c.discover {
+		defaults, err := c.discoverDefaults(ctx)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, config.WithDefaults(defaults))
+	}
+
 	c.log().Debug("Loading configuration from file")
 	cfg, err := config.NewConfigFromFile(
-		c.runCtx.ChartFS, c.configPath, c.namespace, c.appCtx.IdentifierName())
+		c.runCtx.ChartFS, c.configPath, c.namespace, c.appCtx.IdentifierName(),
+		opts...)
 	if err != nil {
 		return err
 	}
+	if c.environment != "" {
+		c.log().Debug("Applying the environment overlay",
+			"environment", c.environment)
+		if err = cfg.ApplyEnvironment(c.environment); err != nil {
+			return err
+		}
+	}
 
 	// Ensuring the configuration is compatible with the Helm charts available for
 	// the installer, product associated charts and dependencies are verified.
@@ -166,11 +245,14 @@ func (c *Config) runCreate() error {
 			c.manager.Name(),
 			config.Selector,
 		)
-		fmt.Print(cfg.String())
+		redacted, err := c.manager.Redact(cfg)
+		if err != nil {
+			return err
+		}
+		fmt.Print(redacted.String())
 		return nil
 	}
 
-	ctx := c.cmd.Context()
 	c.log().Debug("Making sure the namespace is created")
 	if err = k8s.EnsureNamespace(
 		ctx,
@@ -195,6 +277,28 @@ func (c *Config) runCreate() error {
 	return err
 }
 
+// discoverDefaults inspects the cluster and returns the application defaults
+// with the defaults of the discovery rules on top, printing what's found.
+func (c *Config) discoverDefaults(ctx context.Context) (config.Defaults, error) {
+	c.log().Debug("Inspecting the cluster",
+		"rules", len(c.appCtx.DiscoveryRules))
+	discovered, defaults, err := config.DiscoverDefaults(
+		ctx, c.runCtx.Kube, c.appCtx.DiscoveryRules)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(c.cmd.OutOrStdout(), "Discovered %s\n", discovered)
+	for _, path := range slices.Sorted(maps.Keys(defaults)) {
+		fmt.Fprintf(c.cmd.OutOrStdout(), "  %s: %v\n", path, defaults[path])
+	}
+	merged := maps.Clone(config.Defaults(c.appCtx.ConfigDefaults))
+	if merged == nil {
+		merged = config.Defaults{}
+	}
+	maps.Copy(merged, defaults)
+	return merged, nil
+}
+
 // runDelete controls the deletion process.
 func (c *Config) runDelete() error {
 	if c.flags.DryRun {
@@ -221,9 +325,25 @@ func (c *Config) runGet() error {
 		}
 		return err
 	}
-	c.log().Debug("Formatting the configuration as string")
-	fmt.Print(cfg.String())
-	return nil
+	// Sensitive fields are stored in a Secret, and never displayed.
+	if cfg, err = c.manager.Redact(cfg); err != nil {
+		return err
+	}
+	if c.out == nil {
+		c.log().Debug("Formatting the configuration as string")
+		fmt.Print(cfg.String())
+		return nil
+	}
+	c.log().Debug("Formatting the configuration", "output", c.output,
+		"products", c.products)
+	if c.out.Table() {
+		return cfg.PrintTable(c.cmd.OutOrStdout(), c.products)
+	}
+	items, err := cfg.Items(c.products)
+	if err != nil {
+		return err
+	}
+	return c.out.Print(c.cmd.OutOrStdout(), items)
 }
 
 // Run runs the subcommand main action, checks which flags are enabled to interact
@@ -266,15 +386,49 @@ for the whole Kubernetes cluster.
 
 You can use the embedded executable configuration, or inform your own local
 configuration file path to "--create". Use "--force" to update existing
-configuration.
+configuration. The local file is merged on top of the embedded configuration and
+the application defaults, thus it only needs the values changed; products are
+merged by name. Use "config explain" to find out where a value comes from.
 
 The "--create" flag reflects the creation of a new configuration while, "--force"
 is meant to amend the cluster configuration and overwrite changes to installer's
 defaults.
 
+The configuration file may carry named overlays, on the "environments" section,
+patching the settings and product properties per environment. Use
+"--environment" together with "--create" to apply one of them, for instance
+"--environment prod".
+
+With "--discover" the cluster is inspected before creating the configuration:
+capacity, GPUs, storage classes, OpenShift Data Foundation and ingress domain.
+The application turns the attributes found into configuration defaults, layered
+with its own defaults, the local file still prevails. For instance:
+
+  $ %s config --create --discover --dry-run
+
+A single file may configure multiple clusters, hub and spokes, with one YAML
+document per cluster declaring the kubeconfig context on the "cluster" key. Use
+"--cluster" to select the context and its document, for instance "--cluster hub".
+
 This subcommand ensures a single cluster configuration is applied, identified and
 retrieved using a unique label selector.
-`, appCtx.Name)
+
+By default "--get" shows the raw configuration. Use "--output" for structured
+output, "json" and "yaml" wrap the configuration as {"items": [...]}, and
+"--product" to only show the informed products, for instance:
+
+  $ %s config --get --output json --product "Product A"
+  $ %s config --get -o jsonpath='{.items[0].settings}'
+
+Use "%s config diff" to compare a local configuration file with the cluster's,
+and "config backup" and "config restore" to keep a copy of the configuration
+before destructive operations. Single values are changed with "config set", partial
+documents merged with "config apply", and "config prune" removes the products
+and settings the installer doesn't know.
+
+Every flag can be informed as an environment variable as well, "config env" lists
+them with their precedence.
+`, appCtx.Name, appCtx.Name, appCtx.Name, appCtx.Name, appCtx.Name)
 
 	c := &Config{
 		cmd: &cobra.Command{
@@ -286,10 +440,25 @@ retrieved using a unique label selector.
 		appCtx:  appCtx,
 		runCtx:  runCtx,
 		flags:   f,
-		manager: config.NewConfigMapManager(runCtx.Kube, appCtx.Name),
+		manager: newConfigMapManager(appCtx, runCtx),
+		setEnv:  flags.EnvVars{},
 	}
 
 	c.PersistentFlags(c.cmd.PersistentFlags())
+	c.cmd.AddCommand(
+		api.NewRunner(NewConfigApply(appCtx, runCtx, f)).Cmd(),
+		api.NewRunner(NewConfigBackup(appCtx, runCtx, f)).Cmd(),
+		api.NewRunner(NewConfigDiff(appCtx, runCtx, f)).Cmd(),
+		api.NewRunner(NewConfigEdit(appCtx, runCtx, f)).Cmd(),
+		api.NewRunner(NewConfigEnv(appCtx, runCtx, f)).Cmd(),
+		api.NewRunner(NewConfigExplain(appCtx, runCtx, f)).Cmd(),
+		api.NewRunner(NewConfigPrune(appCtx, runCtx, f)).Cmd(),
+		api.NewRunner(NewConfigReconcile(appCtx, runCtx, f)).Cmd(),
+		api.NewRunner(NewConfigRestore(appCtx, runCtx, f)).Cmd(),
+		api.NewRunner(NewConfigSet(appCtx, runCtx, f)).Cmd(),
+		api.NewRunner(NewConfigSettings(appCtx, runCtx, f)).Cmd(),
+		api.NewRunner(NewConfigWatch(appCtx, runCtx, f)).Cmd(),
+	)
 
 	return c
 }
@@ -0,0 +1,190 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigApply represents the "config apply" subcommand, it merges a partial
+// configuration document into the cluster configuration.
+type ConfigApply struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager   *config.ConfigMapManager // cluster configuration manager
+	patchPath string                   // partial configuration file path
+	expandEnv bool                     // expand environment variables
+	setEnv    flags.EnvVars            // variables informed for the file
+	output    string                   // output format flag
+	out       *printer.Output          // output printer
+}
+
+var _ api.SubCommand = (*ConfigApply)(nil)
+
+const configApplyDesc = `
+Merges a partial configuration document into the cluster configuration, only the
+keys present on the document change. The document carries the application root
+key, like a configuration file, and is merged as the local file of "config
+--create" is merged on the defaults: objects are merged recursively, a null
+value removes the key, and products are merged by name. For instance, with
+patch.yaml:
+
+  %s:
+    settings:
+      crc: true
+    products:
+      - name: Product B
+        properties:
+          replicas: 3
+
+  $ %s config apply --file patch.yaml --dry-run
+  $ %s config apply --file patch.yaml
+
+The changes are printed as a unified diff. With --dry-run they're only
+previewed. The resulting configuration is validated, and the dependency topology
+resolved, before it's applied in the cluster. The fields protected by the
+application can't be changed, and sensitive fields are redacted on the diff.
+`
+
+// Cmd exposes the cobra instance.
+func (a *ConfigApply) Cmd() *cobra.Command {
+	return a.cmd
+}
+
+// log returns a decorated logger.
+func (a *ConfigApply) log() *slog.Logger {
+	return a.flags.LoggerWith(a.runCtx.Logger.With("file", a.patchPath))
+}
+
+// Complete asserts no arguments are informed.
+func (a *ConfigApply) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: unexpected arguments: %v",
+			helmeterrors.ErrInvalidUsage, args)
+	}
+	return nil
+}
+
+// Validate asserts the partial configuration file is informed, and the output
+// format is valid.
+func (a *ConfigApply) Validate() error {
+	if a.patchPath == "" {
+		return fmt.Errorf("%w: the partial configuration file is not informed, "+
+			"use --file", helmeterrors.ErrInvalidUsage)
+	}
+	var err error
+	a.out, err = printer.NewOutput(a.output)
+	return err
+}
+
+// Run merges the partial configuration on the cluster's, printing the changes,
+// and applies it unless in dry-run mode.
+func (a *ConfigApply) Run() error {
+	ctx := a.cmd.Context()
+	payload, err := a.runCtx.ChartFS.ReadFile(a.patchPath)
+	if err != nil {
+		return err
+	}
+	a.log().Debug("Retrieving the cluster configuration")
+	cfg, err := a.manager.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	// Keeping a copy of the configuration to show the changes.
+	current, err := cfg.DeepCopy()
+	if err != nil {
+		return err
+	}
+	if current, err = a.manager.Redact(current); err != nil {
+		return err
+	}
+	err = cfg.Patch(a.patchPath, payload,
+		envOptions(a.appCtx, a.expandEnv, a.setEnv)...)
+	if err != nil {
+		return err
+	}
+
+	a.log().Debug("Verifying installer Helm charts")
+	if err = resolveConfig(a.appCtx, a.runCtx, cfg); err != nil {
+		return err
+	}
+
+	patched, err := a.manager.Redact(cfg)
+	if err != nil {
+		return err
+	}
+	drift, err := config.Diff(patched, current)
+	if err != nil {
+		return err
+	}
+	if a.out.Table() {
+		fmt.Fprint(a.cmd.OutOrStdout(), drift.Unified)
+	} else if err = a.out.Print(a.cmd.OutOrStdout(), drift.Changes); err != nil {
+		return err
+	}
+	if len(drift.Changes) == 0 {
+		if a.out.Table() {
+			fmt.Fprintln(a.cmd.OutOrStdout(), "No configuration changes to apply")
+		}
+		return nil
+	}
+	if a.flags.DryRun {
+		a.log().Debug("[DRY-RUN] The configuration is not changed in the cluster")
+		return nil
+	}
+
+	a.log().Debug("Updating the configuration in the cluster")
+	if err = a.manager.Update(ctx, cfg); err != nil {
+		return err
+	}
+	if a.out.Table() {
+		fmt.Fprintf(a.cmd.OutOrStdout(),
+			"Configuration applied, %d field(s) changed\n", len(drift.Changes))
+	}
+	return nil
+}
+
+// NewConfigApply instantiates the "config apply" subcommand.
+func NewConfigApply(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ConfigApply {
+	a := &ConfigApply{
+		cmd: &cobra.Command{
+			Use:   "apply --file <path/to/patch.yaml>",
+			Short: "Merges a partial configuration into the cluster's",
+			Long: fmt.Sprintf(configApplyDesc,
+				appCtx.IdentifierName(), appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: newConfigMapManager(appCtx, runCtx),
+		setEnv:  flags.EnvVars{},
+	}
+	p := a.cmd.PersistentFlags()
+	p.StringVar(
+		&a.patchPath,
+		"file",
+		"",
+		"Partial configuration file merged into the cluster configuration",
+	)
+	flags.SetExpandEnvFlag(p, &a.expandEnv)
+	flags.SetSetEnvFlag(p, a.setEnv)
+	flags.SetClusterFlag(p, &f.KubeContext)
+	flags.SetOutputFlag(p, &a.output)
+	return a
+}
@@ -0,0 +1,214 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigBackup represents the "config backup" subcommand, it exports the cluster
+// configuration ConfigMap to a local file.
+type ConfigBackup struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager *config.ConfigMapManager // cluster configuration manager
+	path    string                   // backup file path
+}
+
+var _ api.SubCommand = (*ConfigBackup)(nil)
+
+const configBackupDesc = `
+Exports the cluster configuration ConfigMap to a local file, together with the
+ConfigMap resourceVersion and the backup timestamp. Take a backup before
+destructive operations, like disabling products or upgrading the installer, and
+use "config restore" to bring the configuration back.
+
+Without arguments the backup is written to "<app>-config-<timestamp>.yaml".
+`
+
+// Cmd exposes the cobra instance.
+func (b *ConfigBackup) Cmd() *cobra.Command {
+	return b.cmd
+}
+
+// log returns a decorated logger.
+func (b *ConfigBackup) log() *slog.Logger {
+	return b.flags.LoggerWith(b.runCtx.Logger.With("path", b.path))
+}
+
+// Complete uses the informed backup file, or a timestamped default.
+func (b *ConfigBackup) Complete(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("%w: unexpected arguments: %v",
+			helmeterrors.ErrInvalidUsage, args)
+	}
+	if len(args) == 1 {
+		b.path = args[0]
+	} else {
+		b.path = fmt.Sprintf("%s-config-%s.yaml",
+			b.appCtx.Name, time.Now().UTC().Format("20060102-150405"))
+	}
+	return nil
+}
+
+// Validate noop.
+func (b *ConfigBackup) Validate() error {
+	return nil
+}
+
+// Run exports the configuration ConfigMap.
+func (b *ConfigBackup) Run() error {
+	b.log().Debug("Retrieving the cluster configuration ConfigMap")
+	backup, err := b.manager.Backup(b.cmd.Context())
+	if err != nil {
+		return err
+	}
+	if err = backup.Save(b.path); err != nil {
+		return err
+	}
+	fmt.Fprintf(b.cmd.OutOrStdout(),
+		"Configuration %q/%q (resourceVersion %s) backed up to %q\n",
+		backup.ConfigMap.GetNamespace(), backup.ConfigMap.GetName(),
+		backup.ResourceVersion, b.path)
+	return nil
+}
+
+// NewConfigBackup instantiates the "config backup" subcommand.
+func NewConfigBackup(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ConfigBackup {
+	return &ConfigBackup{
+		cmd: &cobra.Command{
+			Use:          "backup [path/to/backup.yaml]",
+			Short:        "Exports the cluster configuration to a local file",
+			Long:         configBackupDesc,
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: newConfigMapManager(appCtx, runCtx),
+	}
+}
+
+// ConfigRestore represents the "config restore" subcommand, it applies the
+// configuration stored on a backup file.
+type ConfigRestore struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager *config.ConfigMapManager // cluster configuration manager
+	backup  *config.Backup           // backup to restore
+	path    string                   // backup file path
+	force   bool                     // replace the existing configuration
+}
+
+var _ api.SubCommand = (*ConfigRestore)(nil)
+
+const configRestoreDesc = `
+Restores the cluster configuration from a file created by "config backup". The
+configuration is validated before it's applied, and the namespace is created
+when missing.
+
+An existing cluster configuration is only replaced with --force, the fields
+protected by the application can't be changed by the restore.
+`
+
+// Cmd exposes the cobra instance.
+func (r *ConfigRestore) Cmd() *cobra.Command {
+	return r.cmd
+}
+
+// log returns a decorated logger.
+func (r *ConfigRestore) log() *slog.Logger {
+	return r.flags.LoggerWith(r.runCtx.Logger.With(
+		"path", r.path, "force", r.force))
+}
+
+// Complete loads the backup file informed.
+func (r *ConfigRestore) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expecting one backup file, got %d",
+			helmeterrors.ErrInvalidUsage, len(args))
+	}
+	r.path = args[0]
+	var err error
+	r.backup, err = config.LoadBackup(r.path)
+	return err
+}
+
+// Validate asserts the backup holds a valid configuration.
+func (r *ConfigRestore) Validate() error {
+	_, err := r.backup.Config(r.appCtx.IdentifierName())
+	return err
+}
+
+// Run applies the configuration stored on the backup.
+func (r *ConfigRestore) Run() error {
+	namespace := r.backup.ConfigMap.GetNamespace()
+	if r.flags.DryRun {
+		r.log().Debug("[DRY-RUN] Configuration is not restored in the cluster")
+		fmt.Fprintf(r.cmd.OutOrStdout(),
+			"[DRY-RUN] Restoring the ConfigMap %q/%q, backed up at %s "+
+				"(resourceVersion %s)\n",
+			namespace, r.manager.Name(),
+			r.backup.CreatedAt.Format(time.RFC3339), r.backup.ResourceVersion)
+		return nil
+	}
+
+	ctx := r.cmd.Context()
+	r.log().Debug("Making sure the namespace is created")
+	if err := k8s.EnsureNamespace(
+		ctx, r.log(), r.runCtx.Kube, namespace,
+	); err != nil {
+		return err
+	}
+	r.log().Debug("Restoring the configuration in the cluster")
+	if err := r.manager.Restore(ctx, r.backup, r.force); err != nil {
+		return err
+	}
+	fmt.Fprintf(r.cmd.OutOrStdout(),
+		"Configuration %q/%q restored from %q, backed up at %s\n",
+		namespace, r.manager.Name(), r.path,
+		r.backup.CreatedAt.Format(time.RFC3339))
+	return nil
+}
+
+// NewConfigRestore instantiates the "config restore" subcommand.
+func NewConfigRestore(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ConfigRestore {
+	r := &ConfigRestore{
+		cmd: &cobra.Command{
+			Use:          "restore <path/to/backup.yaml>",
+			Short:        "Restores the cluster configuration from a backup",
+			Long:         configRestoreDesc,
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: newConfigMapManager(appCtx, runCtx),
+	}
+	r.cmd.PersistentFlags().BoolVarP(&r.force, "force", "f", r.force,
+		"Replace the existing cluster configuration")
+	return r
+}
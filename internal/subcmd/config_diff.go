@@ -0,0 +1,130 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigDiff represents the "config diff" subcommand, it compares a local
+// configuration file with the configuration stored in the cluster.
+type ConfigDiff struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager    *config.ConfigMapManager // cluster configuration manager
+	configPath string                   // local configuration file path
+	expandEnv  bool                     // expand environment variables
+	setEnv     flags.EnvVars            // variables informed for the file
+	output     string                   // output format flag
+	out        *printer.Output          // output printer
+}
+
+var _ api.SubCommand = (*ConfigDiff)(nil)
+
+const configDiffDesc = `
+Compares a local configuration file with the configuration stored in the cluster,
+printing a unified diff of the cluster ("---") and local ("+++") payloads. Without
+arguments the embedded default configuration is compared. Sensitive fields are
+compared redacted, and a local file without version assumes the cluster's.
+
+With --output the changed fields are printed instead, each one with its path,
+local and cluster values, for instance "--output json".
+
+The command exits with non-zero status when the configurations differ, so CI
+pipelines can gate on configuration drift.
+`
+
+// Cmd exposes the cobra instance.
+func (d *ConfigDiff) Cmd() *cobra.Command {
+	return d.cmd
+}
+
+// log returns a decorated logger.
+func (d *ConfigDiff) log() *slog.Logger {
+	return d.flags.LoggerWith(d.runCtx.Logger.With(
+		"config-path", d.configPath, flags.OutputFlag, d.output))
+}
+
+// Complete uses the informed configuration file, or the embedded default.
+func (d *ConfigDiff) Complete(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("%w: unexpected arguments: %v",
+			helmeterrors.ErrInvalidUsage, args)
+	}
+	d.configPath = config.DefaultRelativeConfigPath
+	if len(args) == 1 {
+		d.configPath = args[0]
+	}
+	return nil
+}
+
+// Validate asserts the output format is valid.
+func (d *ConfigDiff) Validate() error {
+	var err error
+	d.out, err = printer.NewOutput(d.output)
+	return err
+}
+
+// Run compares the configurations, failing when they differ.
+func (d *ConfigDiff) Run() error {
+	d.log().Debug("Retrieving the cluster configuration")
+	cluster, err := d.manager.GetConfig(d.cmd.Context())
+	if err != nil {
+		return err
+	}
+	d.log().Debug("Loading configuration from file")
+	local, err := config.NewConfigFromFile(d.runCtx.ChartFS, d.configPath,
+		cluster.Namespace(), d.appCtx.IdentifierName(),
+		configOptions(d.appCtx, d.flags, d.expandEnv, d.setEnv)...)
+	if err != nil {
+		return err
+	}
+
+	drift, err := d.manager.Drift(d.cmd.Context(), local)
+	if err != nil {
+		return err
+	}
+	if d.out.Table() {
+		fmt.Fprint(d.cmd.OutOrStdout(), drift.Unified)
+	} else if err = d.out.Print(d.cmd.OutOrStdout(), drift.Changes); err != nil {
+		return err
+	}
+	return drift.Err()
+}
+
+// NewConfigDiff instantiates the "config diff" subcommand.
+func NewConfigDiff(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ConfigDiff {
+	d := &ConfigDiff{
+		cmd: &cobra.Command{
+			Use:          "diff [path/to/config.yaml]",
+			Short:        "Compares a local configuration with the cluster's",
+			Long:         configDiffDesc,
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: newConfigMapManager(appCtx, runCtx),
+		setEnv:  flags.EnvVars{},
+	}
+	flags.SetOutputFlag(d.cmd.PersistentFlags(), &d.output)
+	flags.SetExpandEnvFlag(d.cmd.PersistentFlags(), &d.expandEnv)
+	flags.SetSetEnvFlag(d.cmd.PersistentFlags(), d.setEnv)
+	flags.SetClusterFlag(d.cmd.PersistentFlags(), &f.KubeContext)
+	return d
+}
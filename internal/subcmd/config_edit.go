@@ -0,0 +1,218 @@
+package subcmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigEdit represents the "config edit" subcommand, it opens the cluster
+// configuration on the user's editor and applies the changes.
+type ConfigEdit struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager *config.ConfigMapManager // cluster configuration manager
+	editor  []string                 // editor command and arguments
+}
+
+var _ api.SubCommand = (*ConfigEdit)(nil)
+
+// defaultEditor the editor used when $EDITOR is not set.
+const defaultEditor = "vi"
+
+const configEditDesc = `
+Opens the cluster configuration on the editor set by $EDITOR, "vi" by default,
+and applies the changes when the editor exits. For instance:
+
+  $ EDITOR="code --wait" %s config edit
+
+The edited configuration is validated, and the dependency topology resolved,
+before it's applied in the cluster. The update fails when the configuration
+changed in the cluster meanwhile, instead of overwriting the other changes; the
+edited file is kept, so the changes are not lost, as it is when the edited
+configuration is invalid.
+
+Sensitive fields are shown redacted, keep the placeholder to preserve the stored
+value. The fields protected by the application can't be changed. With --dry-run
+the changes are shown as a unified diff, and not applied.
+`
+
+// Cmd exposes the cobra instance.
+func (e *ConfigEdit) Cmd() *cobra.Command {
+	return e.cmd
+}
+
+// log returns a decorated logger.
+func (e *ConfigEdit) log() *slog.Logger {
+	return e.flags.LoggerWith(e.runCtx.Logger.With(
+		"editor", strings.Join(e.editor, " ")))
+}
+
+// Complete resolves the editor command from the environment.
+func (e *ConfigEdit) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: unexpected arguments: %v",
+			helmeterrors.ErrInvalidUsage, args)
+	}
+	e.editor = strings.Fields(os.Getenv("EDITOR"))
+	if len(e.editor) == 0 {
+		e.editor = []string{defaultEditor}
+	}
+	return nil
+}
+
+// Validate noop.
+func (e *ConfigEdit) Validate() error {
+	return nil
+}
+
+// edit opens the file on the editor, attached to the terminal, and returns the
+// file contents after the editor exits.
+func (e *ConfigEdit) edit(path string) ([]byte, error) {
+	editor := exec.CommandContext(e.cmd.Context(),
+		e.editor[0], append(e.editor[1:], path)...)
+	editor.Stdin = e.cmd.InOrStdin()
+	editor.Stdout = e.cmd.OutOrStdout()
+	editor.Stderr = e.cmd.ErrOrStderr()
+	if err := editor.Run(); err != nil {
+		return nil, fmt.Errorf("editor %q failed: %w",
+			strings.Join(e.editor, " "), err)
+	}
+	return os.ReadFile(path)
+}
+
+// apply validates the edited configuration and applies it in the cluster, only
+// when the configuration is still on the informed resource version. The original
+// configuration is the redacted one.
+func (e *ConfigEdit) apply(
+	original *config.Config,
+	payload []byte,
+	resourceVersion string,
+) error {
+	cfg, err := config.NewConfigFromBytes(
+		payload, original.Namespace(), e.appCtx.IdentifierName())
+	if err != nil {
+		return err
+	}
+	e.log().Debug("Verifying installer Helm charts")
+	if err = resolveConfig(e.appCtx, e.runCtx, cfg); err != nil {
+		return err
+	}
+
+	if e.flags.DryRun {
+		e.log().Debug("[DRY-RUN] Only showing the configuration changes")
+		drift, err := config.Diff(cfg, original)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.cmd.OutOrStdout(),
+			"[DRY-RUN] Changing the ConfigMap %q/%q\n%s",
+			cfg.Namespace(), e.manager.Name(), drift.Unified)
+		return nil
+	}
+	e.log().Debug("Updating the configuration in the cluster",
+		"resource-version", resourceVersion)
+	return e.manager.UpdateVersion(e.cmd.Context(), cfg, resourceVersion)
+}
+
+// Run opens the configuration on the editor and applies the changes.
+func (e *ConfigEdit) Run() error {
+	e.log().Debug("Retrieving the cluster configuration")
+	cfg, resourceVersion, err := e.manager.GetConfigVersion(e.cmd.Context())
+	if err != nil {
+		return err
+	}
+	redacted, err := e.manager.Redact(cfg)
+	if err != nil {
+		return err
+	}
+	original, err := redacted.MarshalYAML()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp("", e.appCtx.Name+"-config-*.yaml")
+	if err != nil {
+		return err
+	}
+	path := f.Name()
+	_, err = f.Write(original)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+
+	e.log().Debug("Opening the configuration on the editor", "path", path)
+	edited, err := e.edit(path)
+	if err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	if bytes.Equal(original, edited) {
+		_ = os.Remove(path)
+		fmt.Fprintln(e.cmd.OutOrStdout(),
+			"Edit cancelled, no changes made to the configuration")
+		return nil
+	}
+
+	if err = e.apply(redacted, edited, resourceVersion); err != nil {
+		if errors.Is(err, config.ErrConfigConflict) {
+			// Telling the actor who changed the configuration meanwhile, the
+			// MCP tools or the command line.
+			if cm, cmErr := e.manager.GetConfigMap(e.cmd.Context()); cmErr == nil {
+				err = fmt.Errorf("%w, mutation #%d applied by %q", err,
+					config.ConfigMapSequence(cm),
+					config.ConfigMapMutatedBy(cm))
+			}
+			return fmt.Errorf("%w, your changes are kept on %q, run the "+
+				"command again to apply them on the current configuration",
+				err, path)
+		}
+		return fmt.Errorf("%w, your changes are kept on %q", err, path)
+	}
+	_ = os.Remove(path)
+	if !e.flags.DryRun {
+		fmt.Fprintf(e.cmd.OutOrStdout(),
+			"Configuration updated on the ConfigMap %q/%q\n",
+			cfg.Namespace(), e.manager.Name())
+	}
+	return nil
+}
+
+// NewConfigEdit instantiates the "config edit" subcommand.
+func NewConfigEdit(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ConfigEdit {
+	return &ConfigEdit{
+		cmd: &cobra.Command{
+			Use:          "edit",
+			Short:        "Edits the cluster configuration on $EDITOR",
+			Long:         fmt.Sprintf(configEditDesc, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: newConfigMapManager(appCtx, runCtx),
+	}
+}
@@ -0,0 +1,122 @@
+package subcmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigEnv represents the "config env" subcommand, it lists the environment
+// variables recognized by the application.
+type ConfigEnv struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	binder *flags.Binder   // flags environment binder
+	output string          // output format flag
+	out    *printer.Output // output printer
+}
+
+var _ api.SubCommand = (*ConfigEnv)(nil)
+
+const configEnvDesc = `
+Lists the environment variables recognized by the application. Every flag, of
+every subcommand, can be informed as an environment variable named after the
+application, the subcommand and the flag, the global flags only carry the
+application prefix. For instance:
+
+  $ %s_LOG_LEVEL=debug %s deploy
+  $ %s_DEPLOY_ADOPT=true %s deploy
+
+The precedence is the same for all subcommands: the flag informed on the command
+line, then the environment variable, then the flag default. Flags defaulting to
+the cluster configuration, like "scan --mode", only fall back to it when neither
+the flag nor the variable is informed.
+
+The variables read following their upstream conventions, like "KUBECONFIG", are
+listed last. Only whether a variable is set is shown, never its value.
+`
+
+// Cmd exposes the cobra instance.
+func (e *ConfigEnv) Cmd() *cobra.Command {
+	return e.cmd
+}
+
+// Complete asserts no arguments are informed.
+func (e *ConfigEnv) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: unexpected arguments: %v",
+			helmeterrors.ErrInvalidUsage, args)
+	}
+	return nil
+}
+
+// Validate asserts the output format is valid.
+func (e *ConfigEnv) Validate() error {
+	var err error
+	e.out, err = printer.NewOutput(e.output)
+	return err
+}
+
+// Run prints the environment variables of the whole command tree.
+func (e *ConfigEnv) Run() error {
+	vars := e.binder.Variables(e.cmd.Root())
+	if !e.out.Table() {
+		return e.out.Print(e.cmd.OutOrStdout(), vars)
+	}
+	table := tabwriter.NewWriter(e.cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "VARIABLE\tSET\tCOMMAND\tFLAG\tDESCRIPTION")
+	dash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	for _, v := range vars {
+		set := "no"
+		if v.Set {
+			set = "yes"
+		}
+		flag := v.Flag
+		if flag != "" {
+			flag = "--" + flag
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n",
+			v.Name, set, dash(v.Command), dash(flag), v.Description)
+	}
+	return table.Flush()
+}
+
+// NewConfigEnv instantiates the "config env" subcommand.
+func NewConfigEnv(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ConfigEnv {
+	binder := flags.NewBinder(appCtx.Name)
+	prefix := binder.Prefix()
+	e := &ConfigEnv{
+		cmd: &cobra.Command{
+			Use:   "env",
+			Short: "Lists the environment variables recognized",
+			Long: fmt.Sprintf(configEnvDesc,
+				prefix, appCtx.Name, prefix, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx: appCtx,
+		runCtx: runCtx,
+		flags:  f,
+		binder: binder,
+	}
+	flags.SetOutputFlag(e.cmd.PersistentFlags(), &e.output)
+	return e
+}
@@ -0,0 +1,143 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+	"text/tabwriter"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigExplain represents the "config explain" subcommand, it shows which
+// configuration layer the values come from.
+type ConfigExplain struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	key        string          // configuration field path
+	configPath string          // local configuration file path
+	expandEnv  bool            // expand environment variables
+	setEnv     flags.EnvVars   // variables informed for the file
+	output     string          // output format flag
+	out        *printer.Output // output printer
+}
+
+var _ api.SubCommand = (*ConfigExplain)(nil)
+
+const configExplainDesc = `
+Explains where the configuration values come from. The configuration is layered:
+the default configuration embedded on the installer ("framework"), the defaults
+set by the application ("application"), and the user's configuration file
+("user"), each one merged on top of the previous.
+
+The key is the field path relative to the application root key, products are
+identified by name; a key naming an object explains every field below it. For
+instance:
+
+  $ %s config explain settings.crc
+  $ %s config explain "products.Product A" config.yaml
+
+Without a configuration file only the default layers are explained. The cluster
+configuration isn't inspected, use "config diff" to compare it.
+`
+
+// Cmd exposes the cobra instance.
+func (e *ConfigExplain) Cmd() *cobra.Command {
+	return e.cmd
+}
+
+// log returns a decorated logger.
+func (e *ConfigExplain) log() *slog.Logger {
+	return e.flags.LoggerWith(e.runCtx.Logger.With(
+		"key", e.key, "config-path", e.configPath))
+}
+
+// Complete takes the key, and the informed configuration file or the embedded
+// default.
+func (e *ConfigExplain) Complete(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("%w: expecting <key> [path/to/config.yaml], got %d "+
+			"arguments", helmeterrors.ErrInvalidUsage, len(args))
+	}
+	e.key = args[0]
+	e.configPath = config.DefaultRelativeConfigPath
+	if len(args) == 2 {
+		e.configPath = args[1]
+	}
+	return nil
+}
+
+// Validate asserts the output format is valid.
+func (e *ConfigExplain) Validate() error {
+	var err error
+	e.out, err = printer.NewOutput(e.output)
+	return err
+}
+
+// Run loads the configuration layers and prints the origin of the fields.
+func (e *ConfigExplain) Run() error {
+	e.log().Debug("Loading the configuration layers")
+	cfg, err := config.NewConfigFromFile(e.runCtx.ChartFS, e.configPath,
+		e.appCtx.Namespace, e.appCtx.IdentifierName(),
+		configOptions(e.appCtx, e.flags, e.expandEnv, e.setEnv)...)
+	if err != nil {
+		return err
+	}
+	origins, err := cfg.Explain(e.key)
+	if err != nil {
+		return err
+	}
+	if !e.out.Table() {
+		return e.out.Print(e.cmd.OutOrStdout(), origins)
+	}
+	table := tabwriter.NewWriter(e.cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "PATH\tVALUE\tLAYER\tFRAMEWORK\tAPPLICATION\tUSER")
+	for _, o := range origins {
+		fmt.Fprintf(table, "%s\t%v\t%s", o.Path, o.Value, o.Layer)
+		for _, layer := range config.Layers {
+			value, ok := o.Layers[layer]
+			if !ok {
+				value = "-"
+			}
+			fmt.Fprintf(table, "\t%v", value)
+		}
+		fmt.Fprintln(table)
+	}
+	return table.Flush()
+}
+
+// NewConfigExplain instantiates the "config explain" subcommand.
+func NewConfigExplain(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ConfigExplain {
+	e := &ConfigExplain{
+		cmd: &cobra.Command{
+			Use:   "explain <key> [path/to/config.yaml]",
+			Short: "Shows which configuration layer a value comes from",
+			Long: fmt.Sprintf(configExplainDesc,
+				appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx: appCtx,
+		runCtx: runCtx,
+		flags:  f,
+		setEnv: flags.EnvVars{},
+	}
+	p := e.cmd.PersistentFlags()
+	flags.SetExpandEnvFlag(p, &e.expandEnv)
+	flags.SetSetEnvFlag(p, e.setEnv)
+	flags.SetClusterFlag(p, &f.KubeContext)
+	flags.SetOutputFlag(p, &e.output)
+	return e
+}
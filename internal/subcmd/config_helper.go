@@ -7,13 +7,117 @@ import (
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
 )
 
+// valuesContext invokes the application function to compute the values template
+// context, when informed.
+func valuesContext(
+	ctx context.Context,
+	fn api.ValuesContextFn,
+	runCtx *runcontext.RunContext,
+	cfg *config.Config,
+) (map[string]any, error) {
+	if fn == nil {
+		return nil, nil
+	}
+	values, err := fn(ctx, runCtx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("computing values template context: %w", err)
+	}
+	return values, nil
+}
+
+// newConfigMapManager instantiates the cluster configuration manager, using the
+// application configuration backend and enforcing its protected fields,
+// sensitive fields, known settings, configuration migrations and transforms.
+func newConfigMapManager(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+) *config.ConfigMapManager {
+	mgr := config.NewConfigMapManager(runCtx.Kube, appCtx.Name)
+	mgr.SetProtectedFields(appCtx.ProtectedConfig)
+	mgr.SetSensitiveFields(appCtx.SensitiveConfig)
+	mgr.SetBackend(appCtx.ConfigBackend)
+	mgr.SetSettings(appCtx.Settings)
+	mgr.SetMigrations(appCtx.ConfigMigrations)
+	mgr.SetTransforms(config.Transforms(appCtx.ConfigTransforms))
+	return mgr
+}
+
+// configOptions returns the options to load a local configuration file, the
+// document targeting the cluster selected with "--cluster" is loaded, and the
+// application transforms applied. The environment variables are expanded with
+// "--expand-env", or "--set-env", see envOptions.
+func configOptions(
+	appCtx *api.AppContext,
+	f *flags.Flags,
+	expandEnv bool,
+	setEnv flags.EnvVars,
+) []config.Option {
+	opts := []config.Option{
+		config.WithCluster(f.KubeContext),
+		config.WithTransforms(config.Transforms(appCtx.ConfigTransforms)),
+		config.WithDefaults(config.Defaults(appCtx.ConfigDefaults)),
+	}
+	return append(opts, envOptions(appCtx, expandEnv, setEnv)...)
+}
+
+// envOptions returns the options to expand the environment variables on a
+// local configuration file, restricted to the application allowlist, the
+// variables informed with "--set-env" take precedence and imply the expansion.
+func envOptions(
+	appCtx *api.AppContext,
+	expandEnv bool,
+	setEnv flags.EnvVars,
+) []config.Option {
+	opts := []config.Option{config.WithEnvAllowlist(appCtx.ConfigEnvAllow...)}
+	if expandEnv {
+		opts = append(opts, config.WithEnvExpansion())
+	}
+	if len(setEnv) > 0 {
+		opts = append(opts, config.WithEnvVars(setEnv))
+	}
+	return opts
+}
+
+// resolveConfig resolves the dependency topology of the installer charts for the
+// configuration, asserting it can be deployed before it's applied.
+func resolveConfig(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	cfg *config.Config,
+) error {
+	charts, err := runCtx.ChartFS.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	collection, err := resolver.NewCollection(appCtx, charts)
+	if err != nil {
+		return err
+	}
+	return resolver.NewResolver(cfg, collection, resolver.NewTopology()).Resolve()
+}
+
 // bootstrapConfig retrieves the cluster configuration.
 func bootstrapConfig(ctx context.Context, appCtx *api.AppContext, runCtx *runcontext.RunContext) (*config.Config, error) {
-	mgr := config.NewConfigMapManager(runCtx.Kube, appCtx.Name)
-	cfg, err := mgr.GetConfig(ctx)
+	cfg, err := newConfigMapManager(appCtx, runCtx).GetConfig(ctx)
+	return cfg, configNotFoundHint(appCtx, err)
+}
+
+// bootstrapMigratedConfig retrieves the cluster configuration, as
+// bootstrapConfig does, persisting the upgraded document when migrated. Only for
+// the commands changing the cluster, outside of dry-run.
+func bootstrapMigratedConfig(ctx context.Context, appCtx *api.AppContext, runCtx *runcontext.RunContext) (*config.Config, error) {
+	cfg, err := newConfigMapManager(appCtx, runCtx).GetConfigMigrated(ctx)
+	return cfg, configNotFoundHint(appCtx, err)
+}
+
+// configNotFoundHint hints about the "config" subcommand when the cluster
+// configuration can't be loaded, the error is returned as is.
+func configNotFoundHint(appCtx *api.AppContext, err error) error {
 	if err != nil {
 		fmt.Fprintf(os.Stderr, `
 Unable to find the configuration in the cluster, or the configuration is invalid.
@@ -23,5 +127,5 @@ configuration for the target cluster.
 	$ %s config --help
 		`, appCtx.Name, appCtx.Name)
 	}
-	return cfg, err
+	return err
 }
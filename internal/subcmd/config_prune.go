@@ -0,0 +1,166 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"text/tabwriter"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigPrune represents the "config prune" subcommand, it removes the products
+// and settings the installer doesn't know anymore from the cluster
+// configuration.
+type ConfigPrune struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager *config.ConfigMapManager // cluster configuration manager
+	output  string                   // output format flag
+	out     *printer.Output          // output printer
+}
+
+var _ api.SubCommand = (*ConfigPrune)(nil)
+
+const configPruneDesc = `
+Removes the orphaned entries from the cluster configuration: the products no
+chart on the installer declares anymore, for instance a product retired by a
+newer installer version, and, when the application registers its settings, the
+settings not registered.
+
+The orphaned entries are listed before being removed. With --dry-run they're
+only reported, and the command exits with non-zero status when any is found.
+The fields protected by the application can't be removed. For instance:
+
+  $ %s config prune --dry-run
+  $ %s config prune
+
+The same is done before deploying with "deploy --prune".
+`
+
+// Cmd exposes the cobra instance.
+func (p *ConfigPrune) Cmd() *cobra.Command {
+	return p.cmd
+}
+
+// log returns a decorated logger.
+func (p *ConfigPrune) log() *slog.Logger {
+	return p.flags.LoggerWith(p.runCtx.Logger.With("output", p.output))
+}
+
+// Complete asserts no arguments are informed.
+func (p *ConfigPrune) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: unexpected arguments: %v",
+			helmeterrors.ErrInvalidUsage, args)
+	}
+	return nil
+}
+
+// Validate asserts the output format is valid.
+func (p *ConfigPrune) Validate() error {
+	var err error
+	p.out, err = printer.NewOutput(p.output)
+	return err
+}
+
+// Run finds the orphaned configuration entries and removes them.
+func (p *ConfigPrune) Run() error {
+	ctx := p.cmd.Context()
+	p.log().Debug("Retrieving the cluster configuration")
+	cfg, err := p.manager.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	charts, err := p.runCtx.ChartFS.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	collection, err := resolver.NewCollection(p.appCtx, charts)
+	if err != nil {
+		return err
+	}
+	orphans := cfg.Orphans(collection.ProductNames(), p.appCtx.Settings)
+	switch {
+	case !p.out.Table():
+		if err = p.out.Print(p.cmd.OutOrStdout(), orphans); err != nil {
+			return err
+		}
+	case len(orphans) == 0:
+		fmt.Fprintln(p.cmd.OutOrStdout(), "No orphaned configuration found")
+	default:
+		printOrphans(p.cmd.OutOrStdout(), orphans)
+	}
+	if len(orphans) == 0 {
+		return nil
+	}
+	if p.flags.DryRun {
+		return fmt.Errorf("%w: %d entries", config.ErrOrphanedConfig, len(orphans))
+	}
+	if _, err = pruneConfig(ctx, p.manager, orphans); err != nil {
+		return err
+	}
+	if p.out.Table() {
+		fmt.Fprintf(p.cmd.OutOrStdout(),
+			"Configuration pruned, %d entries removed\n", len(orphans))
+	}
+	return nil
+}
+
+// printOrphans prints the orphaned configuration entries as a table.
+func printOrphans(w io.Writer, orphans []config.Orphan) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "PATH\tKIND\tREASON")
+	for _, o := range orphans {
+		fmt.Fprintf(table, "%s\t%s\t%s\n", o.Path, o.Kind, o.Reason)
+	}
+	table.Flush()
+}
+
+// pruneConfig removes the orphaned entries from the cluster configuration,
+// retrying on concurrent changes, and returns the pruned configuration.
+func pruneConfig(
+	ctx context.Context,
+	manager *config.ConfigMapManager,
+	orphans []config.Orphan,
+) (*config.Config, error) {
+	return manager.Mutate(ctx, func(cfg *config.Config) error {
+		return cfg.Prune(orphans)
+	})
+}
+
+// NewConfigPrune instantiates the "config prune" subcommand.
+func NewConfigPrune(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ConfigPrune {
+	p := &ConfigPrune{
+		cmd: &cobra.Command{
+			Use:          "prune",
+			Short:        "Removes unknown products and settings from the configuration",
+			Long:         fmt.Sprintf(configPruneDesc, appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: newConfigMapManager(appCtx, runCtx),
+	}
+	pf := p.cmd.PersistentFlags()
+	flags.SetClusterFlag(pf, &f.KubeContext)
+	flags.SetOutputFlag(pf, &p.output)
+	return p
+}
@@ -0,0 +1,256 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// ConfigReconcile represents the "config reconcile" subcommand, it re-applies a
+// local configuration file on the cluster when the stored configuration drifts.
+type ConfigReconcile struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager     *config.ConfigMapManager // cluster configuration manager
+	configPath  string                   // local configuration file path
+	environment string                   // environment overlay to apply
+	expandEnv   bool                     // expand environment variables
+	setEnv      flags.EnvVars            // variables informed for the file
+	watch       bool                     // keep watching the configuration
+	output      string                   // output format flag
+	out         *printer.Output          // output printer
+}
+
+var _ api.SubCommand = (*ConfigReconcile)(nil)
+
+const configReconcileDesc = `
+Reconciles the configuration stored in the cluster with a local configuration
+file, the expected state. Without arguments the embedded default configuration is
+expected. When the configurations differ, for instance after the ConfigMap is
+changed with "kubectl edit", the drift is printed, as "config diff" does, and the
+expected configuration is applied.
+
+With --watch the command keeps watching the ConfigMap and reconciles every
+out-of-band change, recreating the ConfigMap when deleted, until interrupted.
+With --dry-run the drift is only reported, and the command exits with non-zero
+status when the configurations differ.
+
+Sensitive fields are stored apart and aren't reconciled, the fields protected by
+the application can't be changed. For instance:
+
+  $ %s config reconcile config.yaml --watch
+  $ %s config reconcile --environment prod config.yaml --dry-run
+`
+
+// watchRetryInterval the interval before watching the configuration again, when
+// the watch is closed or fails.
+const watchRetryInterval = 5 * time.Second
+
+// Cmd exposes the cobra instance.
+func (r *ConfigReconcile) Cmd() *cobra.Command {
+	return r.cmd
+}
+
+// log returns a decorated logger.
+func (r *ConfigReconcile) log() *slog.Logger {
+	return r.flags.LoggerWith(r.runCtx.Logger.With(
+		"config-path", r.configPath,
+		"environment", r.environment,
+		"watch", r.watch,
+	))
+}
+
+// Complete uses the informed configuration file, or the embedded default.
+func (r *ConfigReconcile) Complete(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("%w: unexpected arguments: %v",
+			helmeterrors.ErrInvalidUsage, args)
+	}
+	r.configPath = config.DefaultRelativeConfigPath
+	if len(args) == 1 {
+		r.configPath = args[0]
+	}
+	return nil
+}
+
+// Validate asserts the output format is valid.
+func (r *ConfigReconcile) Validate() error {
+	var err error
+	r.out, err = printer.NewOutput(r.output)
+	return err
+}
+
+// expected loads the expected configuration for the namespace.
+func (r *ConfigReconcile) expected(namespace string) (*config.Config, error) {
+	cfg, err := config.NewConfigFromFile(r.runCtx.ChartFS, r.configPath,
+		namespace, r.appCtx.IdentifierName(), configOptions(r.appCtx, r.flags, r.expandEnv, r.setEnv)...)
+	if err != nil {
+		return nil, err
+	}
+	if r.environment != "" {
+		if err = cfg.ApplyEnvironment(r.environment); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// reconcile compares the configurations, printing the drift, and applies the
+// expected configuration unless in dry-run mode.
+func (r *ConfigReconcile) reconcile(
+	ctx context.Context,
+	namespace string,
+) (*config.Drift, error) {
+	expected, err := r.expected(namespace)
+	if err != nil {
+		return nil, err
+	}
+	var drift *config.Drift
+	if r.flags.DryRun {
+		drift, err = r.manager.Drift(ctx, expected)
+	} else {
+		drift, err = r.manager.Reconcile(ctx, expected)
+	}
+	if drift == nil || len(drift.Changes) == 0 {
+		return drift, err
+	}
+	if r.out.Table() {
+		fmt.Fprint(r.cmd.OutOrStdout(), drift.Unified)
+	} else if perr := r.out.Print(r.cmd.OutOrStdout(), drift.Changes); perr != nil {
+		return nil, perr
+	}
+	if err == nil && !r.flags.DryRun {
+		fmt.Fprintf(r.cmd.OutOrStdout(),
+			"Configuration reconciled, %d field(s) restored\n",
+			len(drift.Changes))
+	}
+	return drift, err
+}
+
+// recreate creates the expected configuration, after the ConfigMap is deleted.
+func (r *ConfigReconcile) recreate(ctx context.Context, namespace string) error {
+	if r.flags.DryRun {
+		r.log().Warn("[DRY-RUN] The configuration was deleted from the cluster")
+		return nil
+	}
+	expected, err := r.expected(namespace)
+	if err != nil {
+		return err
+	}
+	if err = r.manager.Create(ctx, expected); err != nil {
+		return err
+	}
+	fmt.Fprintln(r.cmd.OutOrStdout(), "Configuration deleted, recreated")
+	return nil
+}
+
+// watchEvents reconciles the configuration on every ConfigMap event, until the
+// watch is closed or the context is done.
+func (r *ConfigReconcile) watchEvents(
+	ctx context.Context,
+	namespace string,
+) error {
+	w, err := r.manager.Watch(ctx)
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+	for event := range w.ResultChan() {
+		switch event.Type {
+		case watch.Added, watch.Modified:
+			r.log().Debug("Configuration changed, reconciling",
+				"event", event.Type)
+			_, err = r.reconcile(ctx, namespace)
+		case watch.Deleted:
+			r.log().Debug("Configuration deleted, recreating")
+			err = r.recreate(ctx, namespace)
+		case watch.Error:
+			r.log().Warn("Watching the configuration", "event", event.Object)
+		}
+		if err != nil {
+			r.log().Error("Reconciling the configuration", "err", err)
+		}
+	}
+	return nil
+}
+
+// Run reconciles the configuration, and when enabled keeps watching it.
+func (r *ConfigReconcile) Run() error {
+	ctx := r.cmd.Context()
+	r.log().Debug("Retrieving the cluster configuration")
+	cluster, err := r.manager.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	namespace := cluster.Namespace()
+
+	drift, err := r.reconcile(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if !r.watch {
+		if r.flags.DryRun {
+			return drift.Err()
+		}
+		return nil
+	}
+
+	r.log().Info("Watching the cluster configuration")
+	for {
+		if err = r.watchEvents(ctx, namespace); err != nil {
+			r.log().Warn("Unable to watch the configuration", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchRetryInterval):
+			r.log().Debug("Watching the cluster configuration again")
+		}
+	}
+}
+
+// NewConfigReconcile instantiates the "config reconcile" subcommand.
+func NewConfigReconcile(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ConfigReconcile {
+	r := &ConfigReconcile{
+		cmd: &cobra.Command{
+			Use:   "reconcile [path/to/config.yaml]",
+			Short: "Re-applies a local configuration when the cluster's drifts",
+			Long: fmt.Sprintf(configReconcileDesc,
+				appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: newConfigMapManager(appCtx, runCtx),
+		setEnv:  flags.EnvVars{},
+	}
+	p := r.cmd.PersistentFlags()
+	p.BoolVar(&r.watch, "watch", r.watch,
+		"Keep watching the configuration, reconciling every change")
+	p.StringVarP(&r.environment, "environment", "e", r.environment,
+		"Environment overlay applied to the configuration file")
+	flags.SetExpandEnvFlag(p, &r.expandEnv)
+	flags.SetSetEnvFlag(p, r.setEnv)
+	flags.SetClusterFlag(p, &f.KubeContext)
+	flags.SetOutputFlag(p, &r.output)
+	return r
+}
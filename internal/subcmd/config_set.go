@@ -0,0 +1,147 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigSet represents the "config set" subcommand, it changes a single value of
+// the cluster configuration, identified by its path.
+type ConfigSet struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager *config.ConfigMapManager // cluster configuration manager
+	path    string                   // configuration path
+	value   any                      // value, coerced from the argument
+}
+
+var _ api.SubCommand = (*ConfigSet)(nil)
+
+const configSetDesc = `
+Changes a single value of the cluster configuration. The path is relative to the
+application root key, dot separated, and list items are selected by attribute
+or index, for instance:
+
+  $ %s config set settings.crc true
+  $ %s config set 'products[name=Product B].properties.replicas' 3
+  $ %s config set 'products[name=Product B].enabled=false'
+
+The value follows YAML typing: "3" is a number, "true" a boolean, "[a, b]" a
+list and "{key: value}" an object; quote it to keep a string, "'3'". Missing
+object keys are created.
+
+The resulting configuration is validated, and the dependency topology resolved,
+before it's applied in the cluster. The fields protected by the application
+can't be changed.
+`
+
+// Cmd exposes the cobra instance.
+func (s *ConfigSet) Cmd() *cobra.Command {
+	return s.cmd
+}
+
+// log returns a decorated logger.
+func (s *ConfigSet) log() *slog.Logger {
+	return s.flags.LoggerWith(s.runCtx.Logger.With(
+		"path", s.path, "value", s.value))
+}
+
+// Complete parses the path and value, informed as two arguments or as a single
+// "<path>=<value>" argument.
+func (s *ConfigSet) Complete(args []string) error {
+	var raw string
+	switch len(args) {
+	case 1:
+		var ok bool
+		if s.path, raw, ok = config.SplitAssignment(args[0]); !ok {
+			return fmt.Errorf("%w: expecting <path> <value> or <path>=<value>, got %q",
+				helmeterrors.ErrInvalidUsage, args[0])
+		}
+	case 2:
+		s.path, raw = args[0], args[1]
+	default:
+		return fmt.Errorf("%w: expecting <path> <value>, got %d arguments",
+			helmeterrors.ErrInvalidUsage, len(args))
+	}
+	var err error
+	s.value, err = config.CoerceValue(raw)
+	return err
+}
+
+// Validate noop.
+func (s *ConfigSet) Validate() error {
+	return nil
+}
+
+// Run changes the configuration value and applies it in the cluster, retrying
+// when the configuration is modified concurrently.
+func (s *ConfigSet) Run() error {
+	ctx := s.cmd.Context()
+	set := func(cfg *config.Config) error {
+		if err := cfg.SetPath(s.path, s.value); err != nil {
+			return err
+		}
+		s.log().Debug("Verifying installer Helm charts")
+		return resolveConfig(s.appCtx, s.runCtx, cfg)
+	}
+
+	if s.flags.DryRun {
+		s.log().Debug("Retrieving the cluster configuration")
+		cfg, err := s.manager.GetConfig(ctx)
+		if err != nil {
+			return err
+		}
+		if err = set(cfg); err != nil {
+			return err
+		}
+		s.log().Debug("[DRY-RUN] Only showing the configuration payload")
+		fmt.Fprintf(s.cmd.OutOrStdout(),
+			"[DRY-RUN] Setting %q to %v on the ConfigMap %q/%q\n",
+			s.path, s.value, cfg.Namespace(), s.manager.Name())
+		redacted, err := s.manager.Redact(cfg)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(s.cmd.OutOrStdout(), redacted.String())
+		return nil
+	}
+	s.log().Debug("Updating the configuration in the cluster")
+	if _, err := s.manager.Mutate(ctx, set); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.cmd.OutOrStdout(), "Configuration %q set to %v\n",
+		s.path, s.value)
+	return nil
+}
+
+// NewConfigSet instantiates the "config set" subcommand.
+func NewConfigSet(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ConfigSet {
+	return &ConfigSet{
+		cmd: &cobra.Command{
+			Use:   "set <path> <value>",
+			Short: "Changes a single value of the cluster configuration",
+			Long: fmt.Sprintf(configSetDesc,
+				appCtx.Name, appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: newConfigMapManager(appCtx, runCtx),
+	}
+}
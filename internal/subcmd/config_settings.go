@@ -0,0 +1,193 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+	"text/tabwriter"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigSettings represents the "config settings" subcommand, it lists the
+// settings known by the application, with their values in the cluster, and
+// changes a setting checking it against its registered type.
+type ConfigSettings struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager  *config.ConfigMapManager // cluster configuration manager
+	settings config.SettingRegistry   // settings known by the application
+	key      string                   // setting key
+	raw      string                   // setting value, as informed
+	value    any                      // setting value, coerced
+	output   string                   // output format flag
+	out      *printer.Output          // output printer
+}
+
+var _ api.SubCommand = (*ConfigSettings)(nil)
+
+const configSettingsDesc = `
+Lists the installer settings, the '.settings' in the cluster configuration, and
+changes a setting. The settings known by the application are listed with their
+type, allowed values, default and description, and only them can be changed; the
+value must match the setting type and allowed values. For instance:
+
+  $ %s config settings
+  $ %s config settings crc true
+  $ %s config settings ci.debug=false
+
+The value follows YAML typing, as "config set" does, string settings take the
+value verbatim. The fields protected by the application can't be changed.
+`
+
+// Cmd exposes the cobra instance.
+func (s *ConfigSettings) Cmd() *cobra.Command {
+	return s.cmd
+}
+
+// log returns a decorated logger.
+func (s *ConfigSettings) log() *slog.Logger {
+	return s.flags.LoggerWith(s.runCtx.Logger.With(
+		"key", s.key, "value", s.raw))
+}
+
+// Complete parses the setting key and value, informed as two arguments or as a
+// single "<key>=<value>" argument. Without arguments the settings are listed.
+func (s *ConfigSettings) Complete(args []string) error {
+	switch len(args) {
+	case 0:
+		return nil
+	case 1:
+		var ok bool
+		if s.key, s.raw, ok = config.SplitAssignment(args[0]); !ok {
+			return fmt.Errorf("%w: expecting <key> <value> or <key>=<value>, got %q",
+				helmeterrors.ErrInvalidUsage, args[0])
+		}
+	case 2:
+		s.key, s.raw = args[0], args[1]
+	default:
+		return fmt.Errorf("%w: expecting <key> <value>, got %d arguments",
+			helmeterrors.ErrInvalidUsage, len(args))
+	}
+	var err error
+	s.value, err = config.CoerceValue(s.raw)
+	return err
+}
+
+// Validate asserts the output format is valid, and the setting is known and its
+// value matches the registered type.
+func (s *ConfigSettings) Validate() error {
+	var err error
+	if s.out, err = printer.NewOutput(s.output); err != nil {
+		return err
+	}
+	if s.key == "" {
+		return nil
+	}
+	setting, err := s.settings.Resolve(s.key)
+	if err != nil || setting == nil {
+		return err
+	}
+	if setting.Type == config.SettingString {
+		s.value = s.raw
+	}
+	s.value, err = setting.Coerce(s.value)
+	return err
+}
+
+// list prints the settings and their values in the cluster, sensitive values
+// redacted.
+func (s *ConfigSettings) list(cfg *config.Config) error {
+	cfg, err := s.manager.Redact(cfg)
+	if err != nil {
+		return err
+	}
+	status := s.settings.Status(cfg)
+	if !s.out.Table() {
+		return s.out.Print(s.cmd.OutOrStdout(), status)
+	}
+	table := tabwriter.NewWriter(s.cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "KEY\tTYPE\tVALUE\tDEFAULT\tALLOWED\tDESCRIPTION")
+	dash := func(v any) any {
+		if v == nil || v == "" {
+			return "-"
+		}
+		return v
+	}
+	for _, st := range status {
+		var allowed any
+		if len(st.Allowed) > 0 {
+			allowed = st.Allowed
+		}
+		fmt.Fprintf(table, "%s\t%v\t%v\t%v\t%v\t%v\n",
+			st.Key, dash(string(st.Type)), dash(st.Value), dash(st.Default),
+			dash(allowed), dash(st.Description))
+	}
+	return table.Flush()
+}
+
+// Run lists the settings, or changes the informed setting in the cluster.
+func (s *ConfigSettings) Run() error {
+	ctx := s.cmd.Context()
+	s.log().Debug("Retrieving the cluster configuration")
+	cfg, err := s.manager.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if s.key == "" {
+		return s.list(cfg)
+	}
+
+	path := "settings." + s.key
+	if err = cfg.SetPath(path, s.value); err != nil {
+		return err
+	}
+	if s.flags.DryRun {
+		s.log().Debug("[DRY-RUN] Only showing the setting change")
+		fmt.Fprintf(s.cmd.OutOrStdout(),
+			"[DRY-RUN] Setting %q to %v on the ConfigMap %q/%q\n",
+			s.key, s.value, cfg.Namespace(), s.manager.Name())
+		return nil
+	}
+	s.log().Debug("Updating the configuration in the cluster")
+	if _, err = s.manager.Mutate(ctx, func(cfg *config.Config) error {
+		return cfg.SetPath(path, s.value)
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.cmd.OutOrStdout(), "Setting %q set to %v\n", s.key, s.value)
+	return nil
+}
+
+// NewConfigSettings instantiates the "config settings" subcommand.
+func NewConfigSettings(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ConfigSettings {
+	s := &ConfigSettings{
+		cmd: &cobra.Command{
+			Use:   "settings [key] [value]",
+			Short: "Lists and changes the installer settings",
+			Long: fmt.Sprintf(configSettingsDesc,
+				appCtx.Name, appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx:   appCtx,
+		runCtx:   runCtx,
+		flags:    f,
+		manager:  newConfigMapManager(appCtx, runCtx),
+		settings: appCtx.Settings,
+	}
+	flags.SetOutputFlag(s.cmd.PersistentFlags(), &s.output)
+	return s
+}
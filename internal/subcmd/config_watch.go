@@ -0,0 +1,155 @@
+package subcmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// ConfigWatch represents the "config watch" subcommand, it streams the changes
+// to the configuration stored in the cluster.
+type ConfigWatch struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager *config.ConfigMapManager // cluster configuration manager
+	tracker *config.ChangeTracker    // describes the configuration events
+	output  string                   // output format flag
+	out     *printer.Output          // output printer
+}
+
+var _ api.SubCommand = (*ConfigWatch)(nil)
+
+const configWatchDesc = `
+Watches the configuration stored in the cluster and prints every change, until
+interrupted. Each event shows when the configuration changed, who changed it, the
+field manager recorded on the resource's managedFields, and the configuration
+fields changed, compared with the previous event. Useful to follow concurrent
+changes by operators and the MCP server. For instance:
+
+  $ %s config watch
+  $ %s config watch --output=json
+
+Sensitive fields are stored apart, thus aren't shown. With structured output
+formats each event is printed as a single item list.
+`
+
+// Cmd exposes the cobra instance.
+func (w *ConfigWatch) Cmd() *cobra.Command {
+	return w.cmd
+}
+
+// log returns a decorated logger.
+func (w *ConfigWatch) log() *slog.Logger {
+	return w.flags.LoggerWith(w.runCtx.Logger.With("output", w.output))
+}
+
+// Complete asserts no arguments are informed.
+func (w *ConfigWatch) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: unexpected arguments: %v",
+			helmeterrors.ErrInvalidUsage, args)
+	}
+	return nil
+}
+
+// Validate asserts the output format is valid.
+func (w *ConfigWatch) Validate() error {
+	var err error
+	w.out, err = printer.NewOutput(w.output)
+	return err
+}
+
+// print prints the change event, as a human readable summary followed by the
+// changed fields, or with the structured output format.
+func (w *ConfigWatch) print(event *config.ChangeEvent) error {
+	out := w.cmd.OutOrStdout()
+	if !w.out.Table() {
+		return w.out.Print(out, []config.ChangeEvent{*event})
+	}
+	manager := event.Manager
+	if manager == "" {
+		manager = "unknown"
+	}
+	fmt.Fprintf(out, "%s %s %s/%s (resourceVersion %s) by %s\n",
+		event.Time.Format(time.RFC3339), event.Type, event.Namespace,
+		event.Name, event.ResourceVersion, manager)
+	for _, c := range event.Changes {
+		fmt.Fprintf(out, "  %s: %v -> %v\n", c.Path, c.From, c.To)
+	}
+	return nil
+}
+
+// watchEvents prints the configuration events, until the watch is closed or the
+// context is done.
+func (w *ConfigWatch) watchEvents(ctx context.Context) error {
+	watcher, err := w.manager.Watch(ctx)
+	if err != nil {
+		return err
+	}
+	defer watcher.Stop()
+	for e := range watcher.ResultChan() {
+		event, err := w.tracker.Track(e)
+		if err != nil {
+			w.log().Warn("Watching the configuration", "err", err)
+			continue
+		}
+		if err = w.print(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run watches the configuration, until interrupted.
+func (w *ConfigWatch) Run() error {
+	ctx := w.cmd.Context()
+	w.log().Info("Watching the cluster configuration")
+	for {
+		if err := w.watchEvents(ctx); err != nil {
+			w.log().Warn("Unable to watch the configuration", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchRetryInterval):
+			w.log().Debug("Watching the cluster configuration again")
+		}
+	}
+}
+
+// NewConfigWatch instantiates the "config watch" subcommand.
+func NewConfigWatch(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ConfigWatch {
+	w := &ConfigWatch{
+		cmd: &cobra.Command{
+			Use:   "watch",
+			Short: "Streams the changes to the cluster configuration",
+			Long: fmt.Sprintf(configWatchDesc,
+				appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: newConfigMapManager(appCtx, runCtx),
+	}
+	w.tracker = w.manager.ChangeTracker()
+	flags.SetOutputFlag(w.cmd.PersistentFlags(), &w.output)
+	return w
+}
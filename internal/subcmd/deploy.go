@@ -1,21 +1,41 @@
 package subcmd
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
+	"os/exec"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
 	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/constants"
+	"github.com/redhat-appstudio/helmet/internal/deployer"
 	"github.com/redhat-appstudio/helmet/internal/flags"
 	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/integration"
 	"github.com/redhat-appstudio/helmet/internal/integrations"
 	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/monitor"
+	"github.com/redhat-appstudio/helmet/internal/platform"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
+	"github.com/redhat-appstudio/helmet/internal/scan"
+	"github.com/redhat-appstudio/helmet/internal/snapshot"
+	"github.com/redhat-appstudio/helmet/internal/webhook"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"helm.sh/helm/v3/pkg/release"
 )
 
 // Deploy is the deploy subcommand.
@@ -31,8 +51,30 @@ type Deploy struct {
 	chartPath          string                    // single chart path
 	valuesTemplatePath string                    // values template file path
 	installerTarball   []byte                    // embedded installer tarball
+	valuesContextFn    api.ValuesContextFn       // values template context
+	retries            int                       // retry budget
+	retryTimeouts      bool                      // retry the timed out dependencies
+	keepGoing          bool                      // continue after failures
+	yes                bool                      // skip the upgrade confirmation
+	prune              bool                      // prune orphaned configuration
+	adopt              bool                      // adopt resources not owned by releases
+	adoptReleases      bool                      // adopt releases not deployed by the installer
+	snapshotPath       string                    // cluster snapshot to simulate against
+	rehearse           bool                      // rehearse the upgrades, in throwaway namespaces
+	skipVersionCheck   bool                      // warn about unsupported platforms only
+	platforms          *platform.Platforms       // supported cluster platforms
+	namespaceLabels    map[string]string         // product namespace labels
+	violationsPath     string                    // admission violations report
+	securityScan       string                    // security policy mode flag
+	policy             *scan.Policy              // security policy gate
+	monitorOpts        monitor.Options           // status check settings
+	history            *installer.History        // past deployment durations
+	executor           deployer.ExecutorOptions  // deploy executor
 }
 
+// retryDelay the wait before retrying a failed dependency deployment.
+var retryDelay = 10 * time.Second
+
 var _ api.SubCommand = (*Deploy)(nil)
 
 // Cmd exposes the cobra instance.
@@ -45,21 +87,47 @@ func (d *Deploy) log() *slog.Logger {
 	return d.flags.LoggerWith(d.runCtx.Logger.With(
 		"chart-path", d.chartPath,
 		flags.ValuesTemplateFlag, d.valuesTemplatePath,
+		"retries", d.retries,
+		"retry-timeouts", d.retryTimeouts,
+		"keep-going", d.keepGoing,
+		"yes", d.yes,
+		"prune", d.prune,
+		"against-snapshot", d.snapshotPath,
+		"rehearse", d.rehearse,
+		"skip-version-check", d.skipVersionCheck,
+		"emit-violations", d.violationsPath,
+		"security-scan", d.securityScan,
+		"status-check", d.monitorOpts.Strategy,
+		"poll-interval", d.monitorOpts.Interval,
+		"executor", d.executor.Name,
 	))
 }
 
 // Complete verifies the object is complete.
 func (d *Deploy) Complete(args []string) error {
 	var err error
+	if d.snapshotPath != "" {
+		if err = d.loadSnapshot(); err != nil {
+			return err
+		}
+	}
 	d.topologyBuilder, err = resolver.NewTopologyBuilder(
 		d.appCtx, d.runCtx.Logger, d.runCtx.ChartFS, d.manager)
 	if err != nil {
 		return err
 	}
-	d.cfg, err = bootstrapConfig(d.cmd.Context(), d.appCtx, d.runCtx)
+	// The migrated configuration is persisted, unless nothing is applied.
+	if d.flags.DryRun || d.snapshotPath != "" {
+		d.cfg, err = bootstrapConfig(d.cmd.Context(), d.appCtx, d.runCtx)
+	} else {
+		d.cfg, err = bootstrapMigratedConfig(d.cmd.Context(), d.appCtx, d.runCtx)
+	}
 	if err != nil {
 		return err
 	}
+	if d.platforms, err = platform.Load(d.runCtx.ChartFS); err != nil {
+		return err
+	}
 	if len(args) == 1 {
 		d.chartPath = args[0]
 	}
@@ -71,11 +139,40 @@ func (d *Deploy) Validate() error {
 	if d.topologyBuilder == nil {
 		panic("topology is nil")
 	}
-	return nil
+	if d.retries < 0 {
+		return fmt.Errorf("invalid --retries %d, must be zero or greater",
+			d.retries)
+	}
+	if d.rehearse && d.flags.DryRun {
+		return fmt.Errorf("--rehearse can't be combined with --dry-run")
+	}
+	if d.rehearse && d.snapshotPath != "" {
+		return fmt.Errorf("--rehearse can't be combined with --against-snapshot")
+	}
+	if err := d.validateExecutor(); err != nil {
+		return err
+	}
+	var err error
+	if d.namespaceLabels, err = installer.NamespaceLabels(d.cfg); err != nil {
+		return err
+	}
+	if d.policy, err = scan.NewPolicyFromConfig(d.cfg); err != nil {
+		return err
+	}
+	if d.securityScan != "" {
+		if d.policy.Mode, err = scan.ParseMode(d.securityScan); err != nil {
+			return err
+		}
+	}
+	return d.monitorOpts.Validate()
 }
 
 // Run deploys the enabled dependencies listed on the configuration.
 func (d *Deploy) Run() error {
+	if err := d.pruneOrphans(); err != nil {
+		return err
+	}
+
 	d.log().Debug("Reading values template file")
 	valuesTmpl, err := d.runCtx.ChartFS.ReadFile(d.valuesTemplatePath)
 	if err != nil {
@@ -116,50 +213,829 @@ subcommand to configure them. For example:
 		deps = append(deps, *dep)
 	}
 
+	valuesContext, err := valuesContext(
+		d.cmd.Context(), d.valuesContextFn, d.runCtx, d.cfg)
+	if err != nil {
+		return err
+	}
+
+	if d.snapshotPath != "" {
+		return d.simulate(deps, valuesTmpl, valuesContext)
+	}
+	if err = d.checkPlatform(); err != nil {
+		return err
+	}
+	if d.rehearse {
+		return d.rehearsal(deps, valuesTmpl, valuesContext)
+	}
+
+	d.warnExpiringTokens()
+	if err = d.confirmUpgrade(deps); err != nil {
+		return err
+	}
+	d.notify(config.WebhookEventStarted, deployScope(deps), nil)
+
+	runStart := time.Now()
+	d.history = installer.NewHistory(
+		d.runCtx.Kube, d.cfg.Namespace(), d.appCtx.Name)
+	// Emitted releases aren't deployed, the history is left untouched.
+	if !d.gitOps() {
+		if err = d.history.Load(d.cmd.Context()); err != nil {
+			d.log().Warn("Unable to read the deployment history", "err", err)
+		}
+	}
+
+	summary := installer.NewSummary(d.retries)
+	failed := map[string]bool{}
 	for index, dep := range deps {
-		fmt.Printf("\n\n%s\n", strings.Repeat("#", 60))
-		fmt.Printf(
-			"# [%d/%d] Deploying '%s' in '%s'.\n",
-			index+1,
-			len(deps),
-			dep.Name(),
-			dep.Namespace(),
-		)
-		fmt.Printf("%s\n", strings.Repeat("#", 60))
-
-		i := installer.NewInstaller(d.log(), d.flags, d.runCtx.Kube, &dep, d.installerTarball)
-
-		ctx := d.cmd.Context()
-		err := i.SetValues(ctx, d.cfg, string(valuesTmpl))
+		result := installer.Result{
+			Name:      dep.Name(),
+			Namespace: dep.Namespace(),
+			Expected:  d.history.Expected(dep.Name()),
+		}
+		if result.Err = d.skipReason(&dep, failed); result.Err != nil {
+			d.log().Warn("Skipping dependency", "dependency", dep.Name(),
+				"reason", result.Err)
+			result.Status = installer.StatusSkipped
+			failed[dep.Name()] = true
+			summary.Add(result)
+			continue
+		}
+
+		start := time.Now()
+		for {
+			result.Attempts++
+			result.Err = d.deployDependency(
+				index, len(deps), &dep, valuesTmpl, valuesContext)
+			if result.Err == nil ||
+				!installer.IsRetryable(result.Err, d.retryTimeouts) ||
+				!summary.Retry() {
+				break
+			}
+			d.log().Warn("Retrying the dependency deployment",
+				"dependency", dep.Name(),
+				"attempt", result.Attempts,
+				"failure", installer.ClassifyFailure(result.Err),
+				"err", result.Err,
+			)
+			// Waiting before retrying, unless the deployment is interrupted.
+			ctx := d.cmd.Context()
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryDelay):
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		result.Duration = time.Since(start)
+
+		switch {
+		case result.Err != nil:
+			d.log().Error("Dependency deployment failed",
+				"dependency", dep.Name(),
+				"failure", installer.ClassifyFailure(result.Err),
+				"err", result.Err,
+			)
+			result.Status = installer.StatusFailed
+			failed[dep.Name()] = true
+		case d.gitOps():
+			result.Status = installer.StatusEmitted
+		case result.Attempts > 1:
+			result.Status = installer.StatusRetried
+		default:
+			result.Status = installer.StatusDeployed
+		}
+		summary.Add(result)
+		d.history.Record(result)
+	}
+	if !d.gitOps() {
+		d.recordRun(runStart, topology, summary)
+		d.saveHistory()
+	}
+
+	summary.Print(d.cmd.OutOrStdout())
+	// The failure is notified first, failing to export the violations must not
+	// keep the webhooks from learning about it.
+	deployErr := summary.Err()
+	if deployErr != nil {
+		d.notify(config.WebhookEventFailed,
+			webhook.NewDependencies(summary.Results()), deployErr)
+	}
+	if err = d.emitViolations(summary.Violations()); err != nil {
+		return errors.Join(deployErr, err)
+	}
+	if deployErr != nil {
+		return deployErr
+	}
+	d.notify(config.WebhookEventCompleted,
+		webhook.NewDependencies(summary.Results()), nil)
+	// The GitOps controller deploys the releases, the console and monitoring
+	// registrations would point to nothing yet.
+	if d.gitOps() {
+		fmt.Printf("HelmReleases emitted!\n")
+		return nil
+	}
+	d.registerConsole(deps)
+	d.exportMonitoring(deps)
+	fmt.Printf("Deployment complete!\n")
+	return nil
+}
+
+// gitOps checks whether the releases are emitted for the GitOps controller,
+// instead of deployed.
+func (d *Deploy) gitOps() bool {
+	return d.executor.Name == deployer.ExecutorFlux
+}
+
+// recordRun records the deploy run on the history, with the releases of the
+// whole topology as they are once the run is done, for "status --at". Releases
+// which can't be read are recorded with an unknown status. Dry-runs aren't
+// recorded.
+func (d *Deploy) recordRun(
+	start time.Time,
+	topology *resolver.Topology,
+	summary *installer.Summary,
+) {
+	if d.flags.DryRun {
+		return
+	}
+	run := installer.Run{
+		ID:       installer.NewRunID(start),
+		Time:     start.UTC().Truncate(time.Second),
+		Releases: []installer.RunRelease{},
+	}
+	var err error
+	if run.ConfigHash, err = d.cfg.Hash(); err != nil {
+		d.log().Warn("Unable to hash the configuration", "err", err)
+	}
+	if err = summary.Err(); err != nil {
+		run.Error = err.Error()
+	}
+	results := map[string]installer.Status{}
+	for _, r := range summary.Results() {
+		results[r.Name] = r.Status
+	}
+	for _, dep := range topology.Dependencies() {
+		rel, err := d.deployedRelease(&dep)
+		r := installer.NewRunRelease(&dep, rel, results[dep.Name()])
 		if err != nil {
-			return err
+			d.log().Warn("Unable to read the release for the deployment history",
+				"dependency", dep.Name(), "err", err)
+			r.Status = "unknown"
+		}
+		run.Releases = append(run.Releases, r)
+	}
+	d.history.RecordRun(run)
+}
+
+// deployedRelease returns the dependency latest release, nil when the chart is
+// not installed.
+func (d *Deploy) deployedRelease(dep *resolver.Dependency) (*release.Release, error) {
+	hc, err := deployer.NewHelm(
+		d.log(), d.flags, d.runCtx.Kube, dep.Namespace(), dep.Chart())
+	if err != nil {
+		return nil, err
+	}
+	return hc.DeployedRelease()
+}
+
+// saveHistory persists the dependency durations for the upcoming deployments,
+// and the deploy run, failing to do so doesn't fail the deployment. Dry-run
+// durations aren't recorded.
+func (d *Deploy) saveHistory() {
+	if d.flags.DryRun {
+		return
+	}
+	if err := d.history.Save(d.cmd.Context()); err != nil {
+		d.log().Warn("Unable to save the deployment history", "err", err)
+		return
+	}
+	if run, err := d.history.GetRun(installer.LatestRun); err == nil {
+		fmt.Fprintf(d.cmd.OutOrStdout(),
+			"\nDeploy run %q recorded, inspect it with \"%s status --at %s\".\n",
+			run.ID, d.appCtx.Name, run.ID)
+	}
+}
+
+// emitViolations writes the admission violations as JSON, for the policy teams,
+// when the report path is informed. Otherwise, it hints about the report.
+func (d *Deploy) emitViolations(violations []installer.Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	if d.violationsPath == "" {
+		fmt.Fprintf(d.cmd.OutOrStdout(), `
+Resources were denied by admission policies, use "--emit-violations <file>" to
+export the violations as JSON for the policy owners.
+`)
+		return nil
+	}
+	payload, err := json.MarshalIndent(violations, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(d.violationsPath, payload, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(d.cmd.OutOrStdout(), "\nAdmission violations written to %q\n",
+		d.violationsPath)
+	return nil
+}
+
+// checkPlatform asserts the cluster platform versions are within the ranges the
+// installer supports, when declared. With --skip-version-check the deployment
+// proceeds regardless, warning about the unsupported platform.
+func (d *Deploy) checkPlatform() error {
+	if d.platforms.Empty() {
+		return nil
+	}
+	d.log().Debug("Checking the cluster platform versions",
+		"kubernetes", d.platforms.Kubernetes, "openshift", d.platforms.OpenShift)
+	versions, err := platform.ClusterVersions(d.cmd.Context(), d.runCtx.Kube)
+	if err == nil {
+		err = d.platforms.Check(versions)
+	}
+	if err == nil {
+		d.log().Debug("Cluster platform supported", "versions", versions)
+		return nil
+	}
+	if d.skipVersionCheck {
+		d.log().Warn("Deploying on an unsupported platform", "err", err)
+		fmt.Fprintf(d.cmd.OutOrStdout(), "WARNING: %s\n", err)
+		return nil
+	}
+	return fmt.Errorf(`%w
+
+The cluster platform is outside the versions supported by %s, use
+"--skip-version-check" to deploy regardless, at your own risk.`,
+		err, d.appCtx.Name)
+}
+
+// warnExpiringTokens warns about the integration tokens expired, or expiring
+// within the configured window, the deployment proceeds regardless.
+func (d *Deploy) warnExpiringTokens() {
+	now := time.Now()
+	expiring, err := d.manager.ExpiringTokens(d.cmd.Context(), d.cfg, now)
+	if err != nil {
+		d.log().Warn("Unable to check the integration tokens expiry", "err", err)
+		return
+	}
+	for _, e := range expiring {
+		d.log().Warn("Integration token expiring", "integration", e.Integration,
+			"expires-at", e.ExpiresAt, "expired", e.Expired(now))
+		fmt.Fprintf(d.cmd.OutOrStdout(), "WARNING: %s\n", e.Describe(now))
+	}
+}
+
+// releaseNotes collects the release notes of the dependencies whose chart
+// version changes, compared with the deployed releases. Releases that can't be
+// inspected are logged, and left out.
+func (d *Deploy) releaseNotes(deps resolver.Dependencies) installer.ReleaseNotes {
+	notes := installer.ReleaseNotes{}
+	for _, dep := range deps {
+		logger := d.log().With("dependency", dep.Name())
+		hc, err := deployer.NewHelm(
+			logger, d.flags, d.runCtx.Kube, dep.Namespace(), dep.Chart())
+		if err != nil {
+			logger.Warn("Unable to inspect the release", "err", err)
+			continue
+		}
+		deployed, err := hc.DeployedVersion()
+		if err != nil {
+			logger.Warn("Unable to read the deployed chart version", "err", err)
+			continue
 		}
-		if d.flags.Verbose {
-			i.PrintRawValues()
+		if upgrade := installer.NewUpgrade(&dep, deployed); upgrade != nil {
+			notes = append(notes, *upgrade)
 		}
+	}
+	return notes
+}
 
-		if err := i.RenderValues(); err != nil {
-			return err
+// confirmUpgrade prints the release notes of the dependencies upgraded, and asks
+// for confirmation when the input is a terminal, unless --yes or dry-run.
+func (d *Deploy) confirmUpgrade(deps resolver.Dependencies) error {
+	notes := d.releaseNotes(deps)
+	if len(notes) == 0 {
+		return nil
+	}
+	notes.Print(d.cmd.OutOrStdout())
+	if d.yes || d.flags.DryRun {
+		return nil
+	}
+	f, ok := d.cmd.InOrStdin().(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil
+	}
+	prompt := "Proceed with the upgrade? [y/N] "
+	if notes.HasBreakingChanges() {
+		prompt = "Proceed with the upgrade, including breaking changes? [y/N] "
+	}
+	fmt.Fprint(d.cmd.ErrOrStderr(), prompt)
+	answer, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return helmeterrors.Wrap(helmeterrors.ErrCancelled,
+		errors.New("upgrade cancelled, no changes were applied"))
+}
+
+// pruneOrphans reports the configuration products and settings the installer
+// doesn't know, and with --prune removes them from the cluster configuration,
+// unless on dry-run, or when the deployment isn't applied: rehearsals,
+// snapshot simulations and releases emitted for GitOps.
+func (d *Deploy) pruneOrphans() error {
+	orphans := d.cfg.Orphans(
+		d.topologyBuilder.GetCollection().ProductNames(), d.appCtx.Settings)
+	if len(orphans) == 0 {
+		return nil
+	}
+	if !d.prune {
+		for _, o := range orphans {
+			d.log().Warn("Orphaned configuration, use --prune to remove it",
+				"path", o.Path, "reason", o.Reason)
+		}
+		return nil
+	}
+	printOrphans(d.cmd.OutOrStdout(), orphans)
+	if d.flags.DryRun {
+		d.log().Warn("[DRY-RUN] The orphaned configuration is not removed")
+		return nil
+	}
+	if d.rehearse || d.snapshotPath != "" || d.gitOps() {
+		d.log().Warn("The orphaned configuration is only removed when " +
+			"deploying, not on rehearsals, snapshots or GitOps")
+		return nil
+	}
+	cfg, err := pruneConfig(d.cmd.Context(),
+		newConfigMapManager(d.appCtx, d.runCtx), orphans)
+	if err != nil {
+		return err
+	}
+	d.cfg = cfg
+	fmt.Fprintf(d.cmd.OutOrStdout(),
+		"Configuration pruned, %d entries removed\n\n", len(orphans))
+	return nil
+}
+
+// loadSnapshot replaces the cluster client by the recorded snapshot, the
+// configuration and integrations are read from it, offline.
+func (d *Deploy) loadSnapshot() error {
+	snap, err := snapshot.Load(d.snapshotPath)
+	if err != nil {
+		return err
+	}
+	kube, err := snap.Kube()
+	if err != nil {
+		return err
+	}
+	d.log().Debug("Simulating the deployment against the cluster snapshot",
+		"captured-at", snap.CapturedAt, "objects", len(snap.Objects))
+	d.runCtx = runcontext.NewRunContext(
+		kube, d.runCtx.ChartFS, d.runCtx.Logger)
+	manager := integrations.NewManager()
+	if err = manager.LoadModules(
+		d.appCtx.Name, d.runCtx, d.manager.GetModules(),
+	); err != nil {
+		return err
+	}
+	d.manager = manager
+	return nil
+}
+
+// simulate renders and validates the values of each dependency, without
+// deploying, printing the outcome per dependency.
+func (d *Deploy) simulate(
+	deps resolver.Dependencies,
+	valuesTmpl []byte,
+	valuesContext map[string]any,
+) error {
+	errs := []error{}
+	table := tabwriter.NewWriter(d.cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "Index\tDependency\tNamespace\tProduct\tResult")
+	for index, dep := range deps {
+		result := "ok"
+		if err := d.planDependency(&dep, valuesTmpl, valuesContext); err != nil {
+			result = string(installer.ClassifyFailure(err))
+			errs = append(errs, fmt.Errorf("%s: %w", dep.Name(), err))
 		}
-		if d.flags.Verbose {
-			i.PrintValues()
+		fmt.Fprintf(table, "%2d\t%s\t%s\t%s\t%s\n",
+			index+1, dep.Name(), dep.Namespace(), dep.ProductName(), result)
+	}
+	table.Flush()
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	fmt.Fprintf(d.cmd.OutOrStdout(),
+		"\nSimulation against %q complete, no changes were applied.\n",
+		d.snapshotPath)
+	return nil
+}
+
+// planDependency renders the values of the dependency and validates them against
+// the chart schema, as done before installing.
+func (d *Deploy) planDependency(
+	dep *resolver.Dependency,
+	valuesTmpl []byte,
+	valuesContext map[string]any,
+) error {
+	i := installer.NewInstaller(
+		d.log(), d.flags, d.runCtx.Kube, dep, d.installerTarball)
+	i.SetValuesContext(valuesContext)
+	i.SetValueRefAllowlist(d.appCtx.ValueReferences)
+	i.SetSensitiveFields(d.appCtx.SensitiveConfig)
+	i.SetManagedBy(d.appCtx.Name)
+	if err := i.SetValues(d.cmd.Context(), d.cfg, string(valuesTmpl)); err != nil {
+		return err
+	}
+	if err := i.RenderValues(d.cmd.Context()); err != nil {
+		return err
+	}
+	if d.flags.Verbose {
+		i.PrintValues()
+	}
+	return nil
+}
+
+// rehearsal installs the new chart versions of the dependencies upgraded into
+// throwaway namespaces, verifying them as a deployment does, and tears them down
+// afterwards. The real installation is left untouched.
+func (d *Deploy) rehearsal(
+	deps resolver.Dependencies,
+	valuesTmpl []byte,
+	valuesContext map[string]any,
+) error {
+	notes := d.releaseNotes(deps)
+	if len(notes) == 0 {
+		fmt.Fprintln(d.cmd.OutOrStdout(),
+			"No dependency changes its chart version, nothing to rehearse.")
+		return nil
+	}
+	upgraded := map[string]bool{}
+	for _, upgrade := range notes {
+		upgraded[upgrade.Name] = true
+	}
+	var rehearsed resolver.Dependencies
+	for _, dep := range deps {
+		if upgraded[dep.Name()] {
+			rehearsed = append(rehearsed, dep)
 		}
+	}
+	namespaces, err := installer.RehearsalNamespaces(rehearsed)
+	if err != nil {
+		return err
+	}
+	for i := range rehearsed {
+		rehearsed[i].SetNamespace(namespaces[rehearsed[i].Namespace()])
+	}
+	mapper, err := d.runCtx.Kube.RESTClientGetter("").ToRESTMapper()
+	if err != nil {
+		return err
+	}
 
-		if err = i.Install(ctx); err != nil {
-			return err
+	installed := []*installer.Installer{}
+	defer d.teardown(&installed, namespaces)
+
+	summary := installer.NewSummary(0)
+	failed := map[string]bool{}
+	for index, dep := range rehearsed {
+		result := installer.Result{Name: dep.Name(), Namespace: dep.Namespace()}
+		if result.Err = d.skipReason(&dep, failed); result.Err != nil {
+			result.Status = installer.StatusSkipped
+			failed[dep.Name()] = true
+			summary.Add(result)
+			continue
+		}
+		fmt.Printf("\n# [%d/%d] Rehearsing '%s' in '%s'.\n",
+			index+1, len(rehearsed), dep.Name(), dep.Namespace())
+
+		i := installer.NewInstaller(
+			d.log(), d.flags, d.runCtx.Kube, &dep, d.installerTarball)
+		i.SetValuesContext(valuesContext)
+		i.SetValueRefAllowlist(d.appCtx.ValueReferences)
+		i.SetManagedBy(d.appCtx.Name)
+		i.SetMonitorOptions(d.monitorOpts)
+		i.SetRehearsal(mapper)
+		installed = append(installed, i)
+
+		start := time.Now()
+		result.Attempts = 1
+		result.Err = d.rehearseDependency(i, valuesTmpl)
+		result.Duration = time.Since(start)
+		if result.Err != nil {
+			d.log().Error("Dependency rehearsal failed",
+				"dependency", dep.Name(),
+				"failure", installer.ClassifyFailure(result.Err),
+				"err", result.Err,
+			)
+			result.Status = installer.StatusFailed
+			failed[dep.Name()] = true
+		} else {
+			result.Status = installer.StatusDeployed
+		}
+		summary.Add(result)
+	}
+	summary.Print(d.cmd.OutOrStdout())
+	if err = summary.Err(); err != nil {
+		return err
+	}
+	fmt.Printf("Rehearsal complete, the real installation was not changed.\n")
+	return nil
+}
+
+// rehearseDependency renders the values and installs the dependency on its
+// rehearsal namespace.
+func (d *Deploy) rehearseDependency(i *installer.Installer, valuesTmpl []byte) error {
+	ctx := d.cmd.Context()
+	if err := i.SetValues(ctx, d.cfg, string(valuesTmpl)); err != nil {
+		return err
+	}
+	if err := i.RenderValues(ctx); err != nil {
+		return err
+	}
+	if d.flags.Verbose {
+		i.PrintValues()
+	}
+	return i.Install(ctx)
+}
+
+// teardown uninstalls the rehearsed releases, in reverse order, and deletes the
+// rehearsal namespaces. Failures are reported, the leftovers must be removed
+// by hand.
+func (d *Deploy) teardown(
+	installed *[]*installer.Installer,
+	namespaces map[string]string,
+) {
+	// The command context may be canceled already, the teardown still runs.
+	ctx := context.WithoutCancel(d.cmd.Context())
+	for idx := len(*installed) - 1; idx >= 0; idx-- {
+		if err := (*installed)[idx].Uninstall(ctx); err != nil {
+			d.log().Warn("Unable to uninstall the rehearsal release", "err", err)
+		}
+	}
+	for _, ns := range namespaces {
+		if err := installer.DeleteNamespace(ctx, d.runCtx.Kube, ns); err != nil {
+			d.log().Warn("Unable to delete the rehearsal namespace",
+				"namespace", ns, "err", err)
+		}
+	}
+}
+
+// deployScope returns the dependencies about to be deployed, as informed to the
+// webhooks when the deployment starts.
+func deployScope(deps resolver.Dependencies) []webhook.Dependency {
+	scope := make([]webhook.Dependency, 0, len(deps))
+	for _, dep := range deps {
+		scope = append(scope, webhook.Dependency{
+			Name:      dep.Name(),
+			Namespace: dep.Namespace(),
+		})
+	}
+	return scope
+}
+
+// chat returns the chat channel configured by the notification integration,
+// nil when it isn't configured.
+func (d *Deploy) chat() (*webhook.Chat, error) {
+	secret, err := d.manager.Secret(
+		d.cmd.Context(), d.cfg, integrations.Notification)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+	return webhook.NewChat(secret.Data)
+}
+
+// notify delivers the deploy event to the webhooks configured, and posts it to
+// the chat channel of the notification integration, skipped on dry-run.
+// Delivery failures are logged, they don't fail the deployment.
+func (d *Deploy) notify(
+	event string,
+	deps []webhook.Dependency,
+	deployErr error,
+) {
+	if d.flags.DryRun {
+		return
+	}
+	chat, err := d.chat()
+	if err != nil {
+		d.log().Warn("Notification integration unavailable", "err", err)
+	}
+	if chat == nil && len(d.cfg.Installer.Webhooks) == 0 {
+		return
+	}
+	notifier := webhook.NewNotifier(
+		d.log(), d.runCtx.Kube, d.appCtx.Name, d.cfg)
+	notifier.SetChat(chat)
+	if err := notifier.Notify(
+		d.cmd.Context(), event, deps, deployErr,
+	); err != nil {
+		d.log().Warn("Deploy event not delivered to all webhooks",
+			"event", event, "err", err)
+	}
+}
+
+// registerConsole registers the deployed products on the OpenShift console, when
+// enabled on the settings. Failures are logged, they don't fail the deployment.
+func (d *Deploy) registerConsole(deps resolver.Dependencies) {
+	if d.flags.DryRun || !installer.ConsoleEnabled(d.cfg) {
+		return
+	}
+	console := installer.NewConsole(d.log(), d.runCtx.Kube, d.appCtx.Name)
+	if !console.Supported() {
+		d.log().Info("OpenShift console not available, skipping registration")
+		return
+	}
+	for _, dep := range deps {
+		if dep.ProductName() == "" {
+			continue
+		}
+		logger := d.log().With("dependency", dep.Name())
+		hc, err := deployer.NewHelm(
+			logger, d.flags, d.runCtx.Kube, dep.Namespace(), dep.Chart())
+		if err != nil {
+			logger.Warn("Unable to inspect the release", "err", err)
+			continue
+		}
+		notes, err := hc.GetNotes()
+		if err != nil {
+			logger.Warn("Unable to read the release notes", "err", err)
+			continue
 		}
-		// Cleaning up temporary resources.
-		if err = k8s.RetryDeleteResources(
-			ctx,
-			d.runCtx.Kube,
-			d.cfg.Namespace(),
+		manifest, err := hc.GetManifest()
+		if err != nil {
+			logger.Warn("Unable to read the release manifest", "err", err)
+			continue
+		}
+		if err = console.Register(
+			d.cmd.Context(), dep.ProductName(), dep.Name(), notes, manifest,
 		); err != nil {
-			d.log().Debug(err.Error())
+			logger.Warn("Unable to register the product on the console",
+				"err", err)
 		}
-		fmt.Printf("%s\n", strings.Repeat("#", 60))
 	}
+}
 
-	fmt.Printf("Deployment complete!\n")
+// exportMonitoring exports the metrics endpoints annotated on the deployed
+// products to the Prometheus Operator, when enabled on the settings. Failures
+// are logged, they don't fail the deployment.
+func (d *Deploy) exportMonitoring(deps resolver.Dependencies) {
+	if d.flags.DryRun || !installer.MonitoringEnabled(d.cfg) {
+		return
+	}
+	monitoring := installer.NewMonitoring(
+		d.log(), d.runCtx.Kube, d.appCtx.Name)
+	if !monitoring.Supported() {
+		d.log().Info("Prometheus Operator not available, skipping monitoring")
+		return
+	}
+	for _, dep := range deps {
+		if dep.ProductName() == "" {
+			continue
+		}
+		logger := d.log().With("dependency", dep.Name())
+		hc, err := deployer.NewHelm(
+			logger, d.flags, d.runCtx.Kube, dep.Namespace(), dep.Chart())
+		if err != nil {
+			logger.Warn("Unable to inspect the release", "err", err)
+			continue
+		}
+		manifest, err := hc.GetManifest()
+		if err != nil {
+			logger.Warn("Unable to read the release manifest", "err", err)
+			continue
+		}
+		if err = monitoring.Export(
+			d.cmd.Context(), dep.Name(), dep.Namespace(), manifest,
+		); err != nil {
+			logger.Warn("Unable to export the product monitoring", "err", err)
+		}
+	}
+}
+
+// skipReason returns why the dependency must be skipped, given the dependencies
+// failed so far. Without --keep-going any failure skips the remaining
+// dependencies, otherwise only the ones depending on a failed dependency.
+func (d *Deploy) skipReason(
+	dep *resolver.Dependency,
+	failed map[string]bool,
+) error {
+	if len(failed) == 0 {
+		return nil
+	}
+	if !d.keepGoing {
+		return errors.New("a previous dependency failed")
+	}
+	for _, name := range dep.DependsOn() {
+		if failed[name] {
+			return fmt.Errorf("depends on %q, which failed", name)
+		}
+	}
+	return nil
+}
+
+// validateExecutor asserts the deploy executor is supported, and its settings
+// are consistent with the deployment mode.
+func (d *Deploy) validateExecutor() error {
+	if err := deployer.ValidateExecutor(d.executor.Name); err != nil {
+		return err
+	}
+	switch d.executor.Name {
+	case deployer.ExecutorHelm:
+		return nil
+	case deployer.ExecutorHelmBinary:
+		if d.snapshotPath != "" {
+			return fmt.Errorf("--executor=%s can't be combined with "+
+				"--against-snapshot", d.executor.Name)
+		}
+		binary := d.executor.HelmBinary
+		if binary == "" {
+			binary = deployer.DefaultHelmBinary
+		}
+		if _, err := exec.LookPath(binary); err != nil {
+			return fmt.Errorf("%w: --helm-binary: %w",
+				deployer.ErrInvalidExecutor, err)
+		}
+	case deployer.ExecutorFlux:
+		if _, err := deployer.ParseFluxSource(d.executor.FluxSource); err != nil {
+			return fmt.Errorf("--flux-source: %w", err)
+		}
+	}
+	if d.rehearse {
+		return fmt.Errorf("--rehearse requires --executor=%s",
+			deployer.ExecutorHelm)
+	}
+	return nil
+}
+
+// deployDependency performs a single attempt of deploying the dependency.
+func (d *Deploy) deployDependency(
+	index, total int,
+	dep *resolver.Dependency,
+	valuesTmpl []byte,
+	valuesContext map[string]any,
+) error {
+	fmt.Printf("\n\n%s\n", strings.Repeat("#", 60))
+	fmt.Printf(
+		"# [%d/%d] Deploying '%s' in '%s'.\n",
+		index+1,
+		total,
+		dep.Name(),
+		dep.Namespace(),
+	)
+	if expected := d.history.Expected(dep.Name()); expected > 0 {
+		fmt.Printf("# %s.\n", installer.DescribeExpected(dep.Name(), expected))
+	}
+	fmt.Printf("%s\n", strings.Repeat("#", 60))
+
+	i := installer.NewInstaller(d.log(), d.flags, d.runCtx.Kube, dep, d.installerTarball)
+	i.SetValuesContext(valuesContext)
+	i.SetValueRefAllowlist(d.appCtx.ValueReferences)
+	i.SetManagedBy(d.appCtx.Name)
+	i.SetNamespaceLabels(d.namespaceLabels)
+	i.SetSecurityPolicy(d.policy)
+	i.SetAdoptConflicts(d.adopt)
+	i.SetAdoptReleases(d.adoptReleases)
+	i.SetMonitorOptions(d.monitorOpts)
+	i.SetExecutor(d.executor)
+	i.SetReplicator(integration.NewReplicator(
+		d.log(), d.runCtx.Kube, d.cfg.Namespace()))
+
+	ctx := d.cmd.Context()
+	err := i.SetValues(ctx, d.cfg, string(valuesTmpl))
+	if err != nil {
+		return err
+	}
+	if d.flags.Verbose {
+		i.PrintRawValues()
+	}
+
+	if err := i.RenderValues(ctx); err != nil {
+		return err
+	}
+	if d.flags.Verbose {
+		i.PrintValues()
+	}
+
+	if err = i.Install(ctx); err != nil {
+		return err
+	}
+	if adoption := i.Adoption(); adoption != nil {
+		d.history.RecordAdoption(*adoption)
+	}
+	// Cleaning up temporary resources.
+	if err = k8s.RetryDeleteResources(
+		ctx,
+		d.runCtx.Kube,
+		d.cfg.Namespace(),
+	); err != nil {
+		d.log().Debug(err.Error())
+	}
+	fmt.Printf("%s\n", strings.Repeat("#", 60))
 	return nil
 }
 
@@ -170,6 +1046,7 @@ func NewDeploy(
 	f *flags.Flags,
 	manager *integrations.Manager,
 	installerTarball []byte,
+	valuesContextFn api.ValuesContextFn,
 ) api.SubCommand {
 	deployDesc := fmt.Sprintf(`
 Deploys the %s platform components.
@@ -186,9 +1063,103 @@ The platform configuration is rendered from the values template file
 The installer resources are embedded in the executable, these resources are
 employed by default.
 
+Failed dependencies are classified (render error, API rejection, timeout, hook
+failure), transient API errors are retried within the retry budget (--retries),
+timeouts only with --retry-timeouts. By default the first failure skips the
+remaining dependencies, with --keep-going only the dependencies depending on the
+failed one are skipped. A summary table is printed at the end of every
+deployment.
+
+Resources denied by admission webhooks (Gatekeeper, Kyverno) or policies are
+reported with the violated policy, use --emit-violations to write them to a JSON
+file for the policy teams.
+
+The rendered manifests are scanned against the security policy before each
+dependency is installed, as the "scan" subcommand does, when the '%s' setting
+or --security-scan mode is "warn" or "enforce". Enforced findings fail the
+dependency deployment.
+
+Resources on the rendered manifests which already exist on the cluster, but are
+not owned by the dependency release (created by hand, by another tool or by
+another release), are listed and fail the dependency deployment instead of being
+overwritten. With --adopt they're taken over by the release.
+
+Likewise, a Helm release named after the dependency chart which wasn't deployed
+by the installer fails the dependency deployment, instead of being upgraded in
+place. With --adopt-releases the release is taken over, once its chart is
+verified to be the dependency chart, and the adoption is recorded on the deploy
+history.
+
+On constrained clusters, like Single Node OpenShift or CRC, the Kubernetes API
+requests are throttled with the global --kube-qps and --kube-burst flags, shared
+by manifests applied and status checks. The namespaces created by the release
+are awaited polling every --poll-interval, or watched with --status-check=watch,
+keeping a single request open instead.
+
+Webhooks listed on the configuration ('%s.webhooks[]') are notified when the
+deployment starts, completes or fails, with a JSON payload signed using the
+HMAC secret referenced by the webhook. When the "notification" integration is
+configured, the events it subscribes to are posted to its Slack or Teams
+channel as well. Webhooks are not notified on dry-run.
+
+Integration tokens expired, or expiring within the '%s' setting window,
+are reported before deploying, the expiry is recorded by "%s integration"
+when the provider exposes it or with --expires.
+
+On OpenShift, when the '%s' setting is enabled, the deployed products are
+linked on the console application menu, using the first URL on the product
+release notes, and the ConsolePlugins shipped by the charts are enabled.
+
+When the '%s' setting is enabled, and the cluster runs the Prometheus
+Operator, the metrics endpoints annotated on the products Services and workloads
+are scraped with ServiceMonitors and PodMonitors, and alerted on by a baseline
+PrometheusRule per product. The endpoints are annotated with:
+'%s'.
+
+Before upgrading, the dependencies whose chart version changes are listed with
+the release notes and breaking changes shipped by the new chart versions, on the
+'%s' and '%s' annotations. On a terminal, the upgrade
+proceeds after confirmation, use --yes to skip it.
+
+Products on the configuration no chart declares anymore, and settings not
+registered by the application, are reported as orphaned before deploying. With
+--prune they're removed from the cluster configuration first, as "%s config
+prune" does, once the flags are validated; rehearsals, snapshot simulations and
+the "flux" executor only report them.
+
+With --against-snapshot the deployment is simulated offline against a cluster
+snapshot, recorded by "%s snapshot capture". The dependencies are resolved and
+their values rendered and validated against the chart schemas, nothing is
+applied and no cluster access is needed.
+
+The cluster Kubernetes and OpenShift versions are checked against the ranges
+declared on "%s", the deployment is refused on unsupported platforms.
+With --skip-version-check it proceeds, warning about it.
+
+With --rehearse the upgrades are rehearsed instead: the dependencies whose chart
+version changes are installed into throwaway namespaces, with generated names,
+verified by their chart tests and status checks, and torn down afterwards. The
+real installation is not touched. Cluster-scoped resources, CRDs included, and
+hooks are skipped, and the values still reference the real namespaces.
+
+The releases are deployed with the Helm SDK by default, "--executor" selects
+the deploy engine instead: "helm-binary" runs the external helm binary, on the
+PATH or "--helm-binary", for the environments mandating the Helm CLI, without
+the ownership labels applied by the SDK; "flux" emits a Flux HelmRelease per
+dependency into "--flux-output-dir", referencing the charts on "--flux-source",
+for the GitOps repository. Nothing is deployed: the cluster configuration and
+platform facts are still read, and the webhooks notified, but the releases are
+reported as "emitted", and the deployment history, console links and
+monitoring are left untouched.
+
 A single chart can be deployed by specifying its path. E.g.:
 	%s deploy charts/%s-openshift
-`, appCtx.Name, appCtx.IdentifierName(), appCtx.Name, appCtx.IdentifierName())
+`, appCtx.Name, appCtx.IdentifierName(), scan.Setting,
+		appCtx.IdentifierName(), integrations.ExpiryWarningSetting, appCtx.Name,
+		installer.ConsoleSetting, installer.MonitoringSetting,
+		annotations.MetricsPort, annotations.ReleaseNotes,
+		annotations.BreakingChanges, appCtx.Name, appCtx.Name,
+		constants.PlatformsFilename, appCtx.Name, appCtx.IdentifierName())
 
 	d := &Deploy{
 		cmd: &cobra.Command{
@@ -203,7 +1174,62 @@ A single chart can be deployed by specifying its path. E.g.:
 		manager:          manager,
 		chartPath:        "",
 		installerTarball: installerTarball,
+		valuesContextFn:  valuesContextFn,
+		retries:          2,
+		monitorOpts: monitor.Options{
+			Strategy: monitor.StrategyPoll,
+			Interval: monitor.DefaultPollInterval,
+		},
+		executor: deployer.ExecutorOptions{
+			Name:       appCtx.Executor,
+			HelmBinary: deployer.DefaultHelmBinary,
+			ChartsDir:  deployer.DefaultChartsDir,
+		},
+	}
+	if d.executor.Name == "" {
+		d.executor.Name = deployer.ExecutorHelm
 	}
-	flags.SetValuesTmplFlag(d.cmd.PersistentFlags(), &d.valuesTemplatePath)
+	p := d.cmd.PersistentFlags()
+	flags.SetValuesTmplFlag(p, &d.valuesTemplatePath)
+	flags.SetClusterFlag(p, &f.KubeContext)
+	p.Lookup(flags.ClusterFlag).Usage = "Target cluster, the kubeconfig context to deploy on"
+	p.IntVar(&d.retries, "retries", d.retries,
+		"Retry budget shared by all dependencies, only transient API errors are retried")
+	p.BoolVar(&d.retryTimeouts, "retry-timeouts", d.retryTimeouts,
+		"Retry the dependencies timing out as well, within the retry budget")
+	p.BoolVar(&d.keepGoing, "keep-going", d.keepGoing,
+		"Keep deploying dependencies not depending on a failed one")
+	p.BoolVarP(&d.yes, "yes", "y", d.yes,
+		"Upgrade the dependencies without asking for confirmation")
+	p.BoolVar(&d.prune, "prune", d.prune,
+		"Remove the products and settings the installer doesn't know from the configuration")
+	p.BoolVar(&d.adopt, "adopt", d.adopt,
+		"Adopt the existing resources not owned by the dependency release")
+	p.BoolVar(&d.adoptReleases, "adopt-releases", d.adoptReleases,
+		"Adopt the existing Helm releases not deployed by the installer")
+	p.StringVar(&d.snapshotPath, "against-snapshot", d.snapshotPath,
+		"Simulate the deployment offline against a cluster snapshot file")
+	p.BoolVar(&d.rehearse, "rehearse", d.rehearse,
+		"Rehearse the upgrades in throwaway namespaces, then tear them down")
+	p.BoolVar(&d.skipVersionCheck, "skip-version-check", d.skipVersionCheck,
+		"Deploy on cluster versions the installer doesn't support, warning only")
+	p.StringVar(&d.violationsPath, "emit-violations", d.violationsPath,
+		"Write the resources denied by admission policies to a JSON file")
+	p.StringVar(&d.securityScan, "security-scan", d.securityScan,
+		"Security policy mode, 'off', 'warn' or 'enforce', defaults to the configuration")
+	p.StringVar(&d.monitorOpts.Strategy, "status-check", d.monitorOpts.Strategy,
+		"Release namespaces status check, 'poll' or 'watch'")
+	p.DurationVar(&d.monitorOpts.Interval, "poll-interval", d.monitorOpts.Interval,
+		"Interval between release namespaces status checks")
+	p.StringVar(&d.executor.Name, "executor", d.executor.Name, fmt.Sprintf(
+		"Deploy executor, one of %s", strings.Join(deployer.Executors, ", ")))
+	p.StringVar(&d.executor.HelmBinary, "helm-binary", d.executor.HelmBinary,
+		"Path to the helm binary used by the helm-binary executor")
+	p.StringVar(&d.executor.FluxSource, "flux-source", d.executor.FluxSource,
+		"Flux source serving the charts, as <kind>/<namespace>/<name>")
+	p.StringVar(&d.executor.ChartsDir, "flux-charts-dir", d.executor.ChartsDir,
+		"Charts directory on the Flux GitRepository or Bucket source")
+	p.StringVar(&d.executor.OutputDir, "flux-output-dir", d.executor.OutputDir,
+		"Directory receiving the Flux HelmReleases, printed when empty")
 	return d
 }
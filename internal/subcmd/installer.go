@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 	"github.com/redhat-appstudio/helmet/internal/flags"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
 
@@ -49,10 +50,12 @@ func (i *Installer) Complete(_ []string) error {
 // Validate validates the informed flags are correct, and the conditions are met.
 func (i *Installer) Validate() error {
 	if i.list && i.extract != "" {
-		return fmt.Errorf("list and extract are mutually exclusive")
+		return fmt.Errorf("%w: list and extract are mutually exclusive",
+			helmeterrors.ErrInvalidUsage)
 	}
 	if !i.list && i.extract == "" {
-		return fmt.Errorf("either list or extract flags must be set")
+		return fmt.Errorf("%w: either list or extract flags must be set",
+			helmeterrors.ErrInvalidUsage)
 	}
 	if !i.list && i.extract != "" {
 		stat, err := os.Stat(i.extract)
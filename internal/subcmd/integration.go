@@ -3,15 +3,18 @@ package subcmd
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integration"
 	"github.com/redhat-appstudio/helmet/internal/integrations"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // disableProductForIntegration disables the product that provides the active
@@ -63,10 +66,59 @@ func disableProductForIntegration(
 	if err := cfg.SetProduct(productName, *spec); err != nil {
 		return err
 	}
-	return config.NewConfigMapManager(runCtx.Kube, appCtx.Name).
+	return newConfigMapManager(appCtx, runCtx).
 		Update(ctx, cfg)
 }
 
+// verifyFlag verifies the existing integration secret, instead of creating it.
+const verifyFlag = "verify"
+
+// verifying returns whether the command verifies the existing integration.
+func verifying(cmd *cobra.Command) bool {
+	verify, _ := cmd.Flags().GetBool(verifyFlag)
+	return verify
+}
+
+// decorateVerify adds the "--verify" flag to the integration command, when
+// informed the existing integration secret is verified against the provider
+// instead, thus the integration flags aren't required.
+func decorateVerify(
+	cmd *cobra.Command,
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	i *integration.Integration,
+) {
+	cmd.Flags().Bool(verifyFlag, false,
+		"Verify the existing integration secret connects and authenticates "+
+			"on the provider")
+
+	preRunE, runE := cmd.PreRunE, cmd.RunE
+	cmd.PreRunE = func(c *cobra.Command, args []string) error {
+		if !verifying(c) {
+			return preRunE(c, args)
+		}
+		c.Flags().VisitAll(func(f *pflag.Flag) {
+			delete(f.Annotations, cobra.BashCompOneRequiredFlag)
+		})
+		return nil
+	}
+	cmd.RunE = func(c *cobra.Command, args []string) error {
+		if !verifying(c) {
+			return runE(c, args)
+		}
+		cfg, err := bootstrapConfig(c.Context(), appCtx, runCtx)
+		if err != nil {
+			return err
+		}
+		if err = i.Verify(c.Context(), cfg); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(c.OutOrStdout(),
+			"Integration %q verified successfully\n", c.Name())
+		return err
+	}
+}
+
 func NewIntegration(
 	appCtx *api.AppContext,
 	runCtx *runcontext.RunContext,
@@ -86,7 +138,14 @@ func NewIntegration(
 			// IntegrationName used to register the module in Manager.
 			activeIntegration := integrations.IntegrationName(cmd.Name())
 
-			cfg, err := bootstrapConfig(ctx, appCtx, runCtx)
+			// Verifying doesn't change the integration secret, and the bundle
+			// commands, export and import, handle several integrations.
+			if verifying(cmd) ||
+				!slices.Contains(manager.IntegrationNames(), cmd.Name()) {
+				return nil
+			}
+
+			cfg, err := bootstrapMigratedConfig(ctx, appCtx, runCtx)
 			if err != nil {
 				return err
 			}
@@ -116,8 +175,13 @@ func NewIntegration(
 			childCmd.Use = mod.Name
 		}
 
+		decorateVerify(childCmd, appCtx, runCtx, wrapper)
 		cmd.AddCommand(childCmd)
 	}
+	cmd.AddCommand(
+		api.NewRunner(NewIntegrationExport(appCtx, runCtx, manager, f)).Cmd(),
+		api.NewRunner(NewIntegrationImport(appCtx, runCtx, manager, f)).Cmd(),
+	)
 
 	return cmd
 }
@@ -0,0 +1,96 @@
+package subcmd
+
+import (
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// IntegrationACR is the sub-command for the "integration acr",
+// responsible for creating and updating the ACR integration secret.
+type IntegrationACR struct {
+	cmd         *cobra.Command           // cobra command
+	appCtx      *api.AppContext          // application context
+	runCtx      *runcontext.RunContext   // run context (kube, logger, chartfs)
+	cfg         *config.Config           // installer configuration
+	integration *integration.Integration // integration instance
+}
+
+var _ api.SubCommand = &IntegrationACR{}
+
+// Cmd exposes the cobra instance.
+func (a *IntegrationACR) Cmd() *cobra.Command {
+	return a.cmd
+}
+
+// Complete loads the configuration and resolves the integration credential.
+func (a *IntegrationACR) Complete(_ []string) error {
+	var err error
+	if a.cfg, err = bootstrapConfig(a.cmd.Context(), a.appCtx, a.runCtx); err != nil {
+		return err
+	}
+	return a.integration.Complete()
+}
+
+// Validate checks if the required configuration is set.
+func (a *IntegrationACR) Validate() error {
+	return a.integration.Validate()
+}
+
+// Run creates or updates the ACR integration secret.
+func (a *IntegrationACR) Run() error {
+	return a.integration.Create(a.cmd.Context(), a.runCtx, a.cfg)
+}
+
+// NewIntegrationACR creates the sub-command for the "integration acr"
+// responsible to manage the integration with an Azure Container Registry.
+func NewIntegrationACR(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	i *integration.Integration,
+) *IntegrationACR {
+	a := &IntegrationACR{
+		cmd: &cobra.Command{
+			Use:   "acr [flags]",
+			Short: fmt.Sprintf("Integrates an Azure Container Registry into %s", appCtx.Name),
+			Long: fmt.Sprintf(`
+Manages the Azure Container Registry integration with %s by storing the
+credentials required by %s services to pull and push images.
+
+The credentials are stored in a Kubernetes Secret in the namespace
+configured for %s, as ".dockerconfigjson" for the registry host.
+
+Authenticate with a service principal, with the AcrPush role on the registry:
+
+  $ %s integration acr \
+	  --url "https://myregistry.azurecr.io" \
+	  --client-id "00000000-0000-0000-0000-000000000000" \
+	  --client-secret-stdin < client-secret.txt
+
+Or with a repository scoped token, its name and password:
+
+  $ %s integration acr \
+	  --url "https://myregistry.azurecr.io" \
+	  --token-name "pull-token" \
+	  --token "REDACTED"`,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+			),
+			SilenceUsage: true,
+		},
+
+		appCtx:      appCtx,
+		runCtx:      runCtx,
+		integration: i,
+	}
+	i.PersistentFlags(a.cmd)
+	return a
+}
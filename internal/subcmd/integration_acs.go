@@ -28,11 +28,13 @@ func (a *IntegrationACS) Cmd() *cobra.Command {
 	return a.cmd
 }
 
-// Complete loads the configuration from cluster.
+// Complete loads the configuration and resolves the integration credential.
 func (a *IntegrationACS) Complete(_ []string) error {
 	var err error
-	a.cfg, err = bootstrapConfig(a.cmd.Context(), a.appCtx, a.runCtx)
-	return err
+	if a.cfg, err = bootstrapConfig(a.cmd.Context(), a.appCtx, a.runCtx); err != nil {
+		return err
+	}
+	return a.integration.Complete()
 }
 
 // Validate checks if the required configuration is set.
@@ -28,11 +28,13 @@ func (b *IntegrationBitBucket) Cmd() *cobra.Command {
 	return b.cmd
 }
 
-// Complete is a no-op in this case.
+// Complete loads the configuration and resolves the integration credential.
 func (b *IntegrationBitBucket) Complete(_ []string) error {
 	var err error
-	b.cfg, err = bootstrapConfig(b.cmd.Context(), b.appCtx, b.runCtx)
-	return err
+	if b.cfg, err = bootstrapConfig(b.cmd.Context(), b.appCtx, b.runCtx); err != nil {
+		return err
+	}
+	return b.integration.Complete()
 }
 
 // Validate checks if the required configuration is set.
@@ -0,0 +1,330 @@
+package subcmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// IntegrationExport represents the "integration export" subcommand, it copies
+// the configured integration secrets to a local bundle file.
+type IntegrationExport struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager   *integrations.Manager // integrations manager
+	cfg       *config.Config        // installer configuration
+	path      string                // bundle file path
+	encryptTo []string              // recipients public key files
+}
+
+var _ api.SubCommand = (*IntegrationExport)(nil)
+
+const integrationExportDesc = `
+Exports the configured integration secrets to a bundle file, to import them on
+another cluster with "integration import", for instance promoting the staging
+integrations to production without entering every token again.
+
+The bundle holds the integration credentials. With --encrypt-to the bundle is
+encrypted with OpenPGP for the informed public keys, exported with:
+
+  $ gpg --export --armor <recipient> > recipient.asc
+
+Without arguments the bundle is written to "<app>-integrations-<timestamp>.yaml",
+or ".yaml.asc" when encrypted.
+`
+
+// Cmd exposes the cobra instance.
+func (e *IntegrationExport) Cmd() *cobra.Command {
+	return e.cmd
+}
+
+// log returns a decorated logger.
+func (e *IntegrationExport) log() *slog.Logger {
+	return e.flags.LoggerWith(e.runCtx.Logger.With(
+		"path", e.path, "encrypt-to", e.encryptTo))
+}
+
+// Complete uses the informed bundle file, or a timestamped default, and loads
+// the cluster configuration.
+func (e *IntegrationExport) Complete(args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("%w: unexpected arguments: %v",
+			helmeterrors.ErrInvalidUsage, args)
+	}
+	if len(args) == 1 {
+		e.path = args[0]
+	} else {
+		e.path = fmt.Sprintf("%s-integrations-%s.yaml",
+			e.appCtx.Name, time.Now().UTC().Format("20060102-150405"))
+		if len(e.encryptTo) > 0 {
+			e.path += ".asc"
+		}
+	}
+	var err error
+	e.cfg, err = bootstrapConfig(e.cmd.Context(), e.appCtx, e.runCtx)
+	return err
+}
+
+// Validate noop.
+func (e *IntegrationExport) Validate() error {
+	return nil
+}
+
+// Run exports the integration secrets to the bundle file.
+func (e *IntegrationExport) Run() error {
+	e.log().Debug("Reading the integration secrets")
+	bundle, err := e.manager.Export(e.cmd.Context(), e.cfg)
+	if err != nil {
+		return err
+	}
+	if len(bundle.Integrations) == 0 {
+		return fmt.Errorf("%w: no integrations configured on namespace %q",
+			helmeterrors.ErrPrerequisitesMissing, e.cfg.Namespace())
+	}
+	payload, err := bundle.Marshal()
+	if err != nil {
+		return err
+	}
+	if len(e.encryptTo) > 0 {
+		keyrings := make([]io.Reader, 0, len(e.encryptTo))
+		for _, path := range e.encryptTo {
+			keyring, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			keyrings = append(keyrings, bytes.NewReader(keyring))
+		}
+		e.log().Debug("Encrypting the bundle")
+		if payload, err = integrations.EncryptBundle(
+			payload, keyrings...); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(e.cmd.ErrOrStderr(), "WARNING: the bundle holds the "+
+			"integration credentials unencrypted, see --encrypt-to\n")
+	}
+	if err = os.WriteFile(e.path, payload, 0o600); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.cmd.OutOrStdout(), "Integrations exported to %q: %s\n",
+		e.path, strings.Join(bundle.Names(), ", "))
+	return nil
+}
+
+// NewIntegrationExport instantiates the "integration export" subcommand.
+func NewIntegrationExport(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	manager *integrations.Manager,
+	f *flags.Flags,
+) *IntegrationExport {
+	e := &IntegrationExport{
+		cmd: &cobra.Command{
+			Use:          "export [path/to/bundle.yaml]",
+			Short:        "Exports the integration secrets to a bundle file",
+			Long:         integrationExportDesc,
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: manager,
+	}
+	e.cmd.PersistentFlags().StringSliceVar(&e.encryptTo, "encrypt-to",
+		e.encryptTo, "Armored OpenPGP public key files to encrypt the bundle for")
+	return e
+}
+
+// IntegrationImport represents the "integration import" subcommand, it creates
+// the integration secrets stored on a bundle file.
+type IntegrationImport struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager         *integrations.Manager // integrations manager
+	cfg             *config.Config        // installer configuration
+	bundle          *integrations.Bundle  // bundle to import
+	path            string                // bundle file path
+	decryptKey      string                // private key file
+	passphraseStdin bool                  // read the key passphrase from stdin
+	force           bool                  // replace the existing secrets
+}
+
+var _ api.SubCommand = (*IntegrationImport)(nil)
+
+const integrationImportDesc = `
+Imports the integration secrets from a bundle file created by "integration
+export", on the installer namespace of the current cluster. The products
+providing the imported integrations are disabled, as when the integrations are
+created one by one.
+
+Encrypted bundles are decrypted with the OpenPGP private key informed by
+--decrypt-key, exported with:
+
+  $ gpg --export-secret-keys --armor <recipient> > recipient-private.asc
+
+When the private key is protected, the passphrase is read from STDIN with
+--passphrase-stdin, or prompted on a terminal.
+
+The bundle is validated before any secret is created. Integrations already
+configured are only replaced with --force.
+`
+
+// Cmd exposes the cobra instance.
+func (i *IntegrationImport) Cmd() *cobra.Command {
+	return i.cmd
+}
+
+// log returns a decorated logger.
+func (i *IntegrationImport) log() *slog.Logger {
+	return i.flags.LoggerWith(i.runCtx.Logger.With(
+		"path", i.path, "decrypt-key", i.decryptKey, "force", i.force))
+}
+
+// passphrase reads the private key passphrase from STDIN, or prompts for it
+// when the input is a terminal.
+func (i *IntegrationImport) passphrase() ([]byte, error) {
+	if i.passphraseStdin {
+		line, err := bufio.NewReader(i.cmd.InOrStdin()).ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		return bytes.TrimRight(line, "\r\n"), nil
+	}
+	f, ok := i.cmd.InOrStdin().(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil, fmt.Errorf("%w: the private key is protected, use "+
+			"--passphrase-stdin", integrations.ErrBundleKey)
+	}
+	fmt.Fprint(i.cmd.ErrOrStderr(), "passphrase: ")
+	secret, err := term.ReadPassword(int(f.Fd()))
+	fmt.Fprintln(i.cmd.ErrOrStderr())
+	return secret, err
+}
+
+// Complete loads the bundle file informed, decrypting it, and the cluster
+// configuration.
+func (i *IntegrationImport) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expecting one bundle file, got %d",
+			helmeterrors.ErrInvalidUsage, len(args))
+	}
+	i.path = args[0]
+	payload, err := os.ReadFile(i.path)
+	if err != nil {
+		return err
+	}
+	if integrations.BundleEncrypted(payload) {
+		if i.decryptKey == "" {
+			return fmt.Errorf("%w: the bundle is encrypted, use --decrypt-key",
+				helmeterrors.ErrInvalidUsage)
+		}
+		keyring, err := os.ReadFile(i.decryptKey)
+		if err != nil {
+			return err
+		}
+		i.log().Debug("Decrypting the bundle")
+		if payload, err = integrations.DecryptBundle(
+			payload, bytes.NewReader(keyring), i.passphrase); err != nil {
+			return err
+		}
+	}
+	if i.bundle, err = integrations.LoadBundle(payload); err != nil {
+		return err
+	}
+	i.cfg, err = bootstrapConfig(i.cmd.Context(), i.appCtx, i.runCtx)
+	return err
+}
+
+// Validate asserts the bundle carries integrations.
+func (i *IntegrationImport) Validate() error {
+	if len(i.bundle.Integrations) == 0 {
+		return fmt.Errorf("%w: no integrations on %q",
+			integrations.ErrInvalidBundle, i.path)
+	}
+	return nil
+}
+
+// Run creates the integration secrets, and disables the products providing them.
+func (i *IntegrationImport) Run() error {
+	names := strings.Join(i.bundle.Names(), ", ")
+	if i.flags.DryRun {
+		i.log().Debug("[DRY-RUN] Integrations are not imported in the cluster")
+		fmt.Fprintf(i.cmd.OutOrStdout(),
+			"[DRY-RUN] Importing the integrations %s, exported from %q at %s\n",
+			names, i.bundle.Namespace,
+			i.bundle.CreatedAt.Format(time.RFC3339))
+		return nil
+	}
+
+	ctx := i.cmd.Context()
+	i.log().Debug("Importing the integration secrets")
+	imported, err := i.manager.Import(ctx, i.cfg, i.bundle, i.force)
+	if err != nil {
+		if len(imported) > 0 {
+			i.log().Warn("Integrations partially imported",
+				"imported", imported)
+		}
+		return err
+	}
+	for _, name := range imported {
+		if err = disableProductForIntegration(ctx, i.appCtx, i.runCtx,
+			i.manager, i.cfg, integrations.IntegrationName(name)); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(i.cmd.OutOrStdout(),
+		"Integrations imported from %q on namespace %q: %s\n",
+		i.path, i.cfg.Namespace(), names)
+	return nil
+}
+
+// NewIntegrationImport instantiates the "integration import" subcommand.
+func NewIntegrationImport(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	manager *integrations.Manager,
+	f *flags.Flags,
+) *IntegrationImport {
+	i := &IntegrationImport{
+		cmd: &cobra.Command{
+			Use:          "import <path/to/bundle.yaml>",
+			Short:        "Imports the integration secrets from a bundle file",
+			Long:         integrationImportDesc,
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: manager,
+	}
+	p := i.cmd.PersistentFlags()
+	p.StringVar(&i.decryptKey, "decrypt-key", i.decryptKey,
+		"Armored OpenPGP private key file to decrypt the bundle")
+	p.BoolVar(&i.passphraseStdin, "passphrase-stdin", i.passphraseStdin,
+		"Reads the private key passphrase from STDIN")
+	p.BoolVarP(&i.force, "force", "f", i.force,
+		"Replace the integrations already configured")
+	return i
+}
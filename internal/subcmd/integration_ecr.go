@@ -0,0 +1,102 @@
+package subcmd
+
+import (
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// IntegrationECR is the sub-command for the "integration ecr", responsible for
+// creating and updating the AWS ECR integration secret.
+type IntegrationECR struct {
+	cmd         *cobra.Command           // cobra command
+	appCtx      *api.AppContext          // application context
+	runCtx      *runcontext.RunContext   // run context (kube, logger, chartfs)
+	cfg         *config.Config           // installer configuration
+	integration *integration.Integration // integration instance
+}
+
+var _ api.SubCommand = &IntegrationECR{}
+
+// Cmd exposes the cobra instance.
+func (e *IntegrationECR) Cmd() *cobra.Command {
+	return e.cmd
+}
+
+// Complete loads the configuration and resolves the integration credential.
+func (e *IntegrationECR) Complete(_ []string) error {
+	var err error
+	if e.cfg, err = bootstrapConfig(e.cmd.Context(), e.appCtx, e.runCtx); err != nil {
+		return err
+	}
+	return e.integration.Complete()
+}
+
+// Validate checks if the required configuration is set.
+func (e *IntegrationECR) Validate() error {
+	return e.integration.Validate()
+}
+
+// Run creates or updates the ECR integration secret.
+func (e *IntegrationECR) Run() error {
+	return e.integration.Create(e.cmd.Context(), e.runCtx, e.cfg)
+}
+
+// NewIntegrationECR creates the sub-command for the "integration ecr"
+// responsible to manage the integration with an AWS ECR private registry.
+func NewIntegrationECR(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	i *integration.Integration,
+) *IntegrationECR {
+	e := &IntegrationECR{
+		cmd: &cobra.Command{
+			Use: "ecr [flags]",
+			Short: fmt.Sprintf(
+				"Integrates an AWS ECR private registry into %s",
+				appCtx.Name,
+			),
+			Long: fmt.Sprintf(`
+Manages the AWS Elastic Container Registry integration with %s by storing
+the credentials required by %s services to pull and push images.
+
+The credentials are stored in a Kubernetes Secret in the namespace
+configured for %s.
+
+With an access key, a registry token is obtained from the ECR API and stored as
+".dockerconfigjson", after asserting the registry accepts it. ECR tokens expire
+after 12 hours, the expiry is reported by "deploy" and the MCP status tools;
+rerun the command with --force to refresh it:
+
+  $ %s integration ecr \
+	  --registry "123456789012.dkr.ecr.us-east-1.amazonaws.com" \
+	  --access-key-id "AKIA..." \
+	  --secret-access-key "REDACTED"
+
+With IRSA (IAM Roles for Service Accounts), the workloads assume the informed
+role to authenticate, only the registry reachability is asserted:
+
+  $ %s integration ecr \
+	  --registry "123456789012.dkr.ecr.us-east-1.amazonaws.com" \
+	  --role-arn "arn:aws:iam::123456789012:role/ecr-pull"`,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+			),
+			SilenceUsage: true,
+		},
+
+		appCtx:      appCtx,
+		runCtx:      runCtx,
+		integration: i,
+	}
+	i.PersistentFlags(e.cmd)
+	return e
+}
@@ -0,0 +1,100 @@
+package subcmd
+
+import (
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// IntegrationGAR is the sub-command for the "integration gar", responsible for
+// creating and updating the Google Artifact Registry integration secret.
+type IntegrationGAR struct {
+	cmd         *cobra.Command           // cobra command
+	appCtx      *api.AppContext          // application context
+	runCtx      *runcontext.RunContext   // run context (kube, logger, chartfs)
+	cfg         *config.Config           // installer configuration
+	integration *integration.Integration // integration instance
+}
+
+var _ api.SubCommand = &IntegrationGAR{}
+
+// Cmd exposes the cobra instance.
+func (g *IntegrationGAR) Cmd() *cobra.Command {
+	return g.cmd
+}
+
+// Complete loads the configuration and resolves the integration credential.
+func (g *IntegrationGAR) Complete(_ []string) error {
+	var err error
+	if g.cfg, err = bootstrapConfig(g.cmd.Context(), g.appCtx, g.runCtx); err != nil {
+		return err
+	}
+	return g.integration.Complete()
+}
+
+// Validate checks if the required configuration is set.
+func (g *IntegrationGAR) Validate() error {
+	return g.integration.Validate()
+}
+
+// Run creates or updates the GAR integration secret.
+func (g *IntegrationGAR) Run() error {
+	return g.integration.Create(g.cmd.Context(), g.runCtx, g.cfg)
+}
+
+// NewIntegrationGAR creates the sub-command for the "integration gar"
+// responsible to manage the integration with a Google Artifact Registry.
+func NewIntegrationGAR(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	i *integration.Integration,
+) *IntegrationGAR {
+	g := &IntegrationGAR{
+		cmd: &cobra.Command{
+			Use: "gar [flags]",
+			Short: fmt.Sprintf(
+				"Integrates a Google Artifact Registry into %s",
+				appCtx.Name,
+			),
+			Long: fmt.Sprintf(`
+Manages the Google Artifact Registry integration with %s by storing
+the credentials required by %s services to pull and push images.
+
+The credentials are stored in a Kubernetes Secret in the namespace
+configured for %s.
+
+With a service account JSON key, the registry credentials are stored as
+".dockerconfigjson", after asserting the registry accepts the key. The project
+defaults to the key's:
+
+  $ %s integration gar \
+	  --registry "us-central1-docker.pkg.dev" \
+	  --service-account-key-stdin < service-account.json
+
+With Workload Identity, the workloads impersonate the informed Google service
+account to authenticate, only the registry reachability is asserted:
+
+  $ %s integration gar \
+	  --registry "us-central1-docker.pkg.dev" \
+	  --service-account "puller@my-project.iam.gserviceaccount.com"`,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+			),
+			SilenceUsage: true,
+		},
+
+		appCtx:      appCtx,
+		runCtx:      runCtx,
+		integration: i,
+	}
+	i.PersistentFlags(g.cmd)
+	return g
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/integration"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
@@ -40,10 +41,12 @@ func (g *IntegrationGitHub) Complete(args []string) error {
 	}
 
 	if g.create && g.update {
-		return fmt.Errorf("cannot create and update at the same time")
+		return fmt.Errorf("%w: cannot create and update at the same time",
+			helmeterrors.ErrInvalidUsage)
 	}
 	if !g.create && !g.update {
-		return fmt.Errorf("either create or update must be set")
+		return fmt.Errorf("%w: either create or update must be set",
+			helmeterrors.ErrInvalidUsage)
 	}
 
 	if len(args) != 1 {
@@ -52,6 +55,9 @@ func (g *IntegrationGitHub) Complete(args []string) error {
 			len(args),
 		)
 	}
+	if err = g.integration.Complete(); err != nil {
+		return err
+	}
 	return g.integration.SetArgument(integration.GitHubAppName, args[0])
 }
 
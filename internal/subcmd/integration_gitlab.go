@@ -28,11 +28,13 @@ func (g *IntegrationGitLab) Cmd() *cobra.Command {
 	return g.cmd
 }
 
-// Complete is a no-op in this case.
+// Complete loads the configuration and resolves the integration credential.
 func (g *IntegrationGitLab) Complete(_ []string) error {
 	var err error
-	g.cfg, err = bootstrapConfig(g.cmd.Context(), g.appCtx, g.runCtx)
-	return err
+	if g.cfg, err = bootstrapConfig(g.cmd.Context(), g.appCtx, g.runCtx); err != nil {
+		return err
+	}
+	return g.integration.Complete()
 }
 
 // Validate checks if the required configuration is set.
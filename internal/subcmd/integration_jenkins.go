@@ -28,11 +28,13 @@ func (j *IntegrationJenkins) Cmd() *cobra.Command {
 	return j.cmd
 }
 
-// Complete is a no-op in this case.
+// Complete loads the configuration and resolves the integration credential.
 func (j *IntegrationJenkins) Complete(_ []string) error {
 	var err error
-	j.cfg, err = bootstrapConfig(j.cmd.Context(), j.appCtx, j.runCtx)
-	return err
+	if j.cfg, err = bootstrapConfig(j.cmd.Context(), j.appCtx, j.runCtx); err != nil {
+		return err
+	}
+	return j.integration.Complete()
 }
 
 // Validate checks if the required configuration is set.
@@ -0,0 +1,110 @@
+package subcmd
+
+import (
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// IntegrationKeycloak is the sub-command for the "integration keycloak",
+// responsible for creating and updating the Keycloak integration secret.
+type IntegrationKeycloak struct {
+	cmd         *cobra.Command           // cobra command
+	appCtx      *api.AppContext          // application context
+	runCtx      *runcontext.RunContext   // run context (kube, logger, chartfs)
+	cfg         *config.Config           // installer configuration
+	integration *integration.Integration // integration instance
+}
+
+var _ api.SubCommand = &IntegrationKeycloak{}
+
+// Cmd exposes the cobra instance.
+func (k *IntegrationKeycloak) Cmd() *cobra.Command {
+	return k.cmd
+}
+
+// Complete loads the configuration and resolves the integration credential.
+func (k *IntegrationKeycloak) Complete(_ []string) error {
+	var err error
+	if k.cfg, err = bootstrapConfig(k.cmd.Context(), k.appCtx, k.runCtx); err != nil {
+		return err
+	}
+	return k.integration.Complete()
+}
+
+// Validate checks if the required configuration is set.
+func (k *IntegrationKeycloak) Validate() error {
+	return k.integration.Validate()
+}
+
+// Run creates or updates the Keycloak integration secret, creating the client
+// on the realm when requested.
+func (k *IntegrationKeycloak) Run() error {
+	return k.integration.Create(k.cmd.Context(), k.runCtx, k.cfg)
+}
+
+// NewIntegrationKeycloak creates the sub-command for the "integration keycloak"
+// responsible to manage the integration with a Keycloak, or Red Hat Single
+// Sign-On, realm.
+func NewIntegrationKeycloak(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	i *integration.Integration,
+) *IntegrationKeycloak {
+	k := &IntegrationKeycloak{
+		cmd: &cobra.Command{
+			Aliases: []string{"rhsso"},
+			Use:     "keycloak [--create] [flags]",
+			Short: fmt.Sprintf(
+				"Integrates a Keycloak realm OIDC client into %s",
+				appCtx.Name,
+			),
+			Long: fmt.Sprintf(`
+Manages the Keycloak, or Red Hat Single Sign-On, integration with %s by
+storing the OIDC client credentials required by %s services to authenticate
+their users on the realm.
+
+The credentials are stored in a Kubernetes Secret in the namespace
+configured for %s, along with the realm issuer, discovered from the realm.
+The client credentials are verified against the realm before stored.
+
+Inform an existing confidential client of the realm:
+
+  $ %s integration keycloak \
+	  --url "https://sso.example.com" \
+	  --realm "developers" \
+	  --client-id "helmet" \
+	  --client-secret-stdin < client-secret.txt
+
+Or create the client on the realm, with the administrator credentials of the
+"master" realm, the client secret is generated by Keycloak. The redirect URIs
+default to the "externalURLs" setting callback:
+
+  $ %s integration keycloak --create \
+	  --url "https://sso.example.com" \
+	  --realm "developers" \
+	  --client-id "helmet" \
+	  --admin-username "admin" \
+	  --admin-password "REDACTED" \
+	  --redirect-uri "https://helmet.apps.example.com/callback"`,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+			),
+			SilenceUsage: true,
+		},
+
+		appCtx:      appCtx,
+		runCtx:      runCtx,
+		integration: i,
+	}
+	i.PersistentFlags(k.cmd)
+	return k
+}
@@ -28,11 +28,13 @@ func (n *IntegrationNexus) Cmd() *cobra.Command {
 	return n.cmd
 }
 
-// Complete is a no-op in this case.
+// Complete loads the configuration and resolves the integration credential.
 func (n *IntegrationNexus) Complete(_ []string) error {
 	var err error
-	n.cfg, err = bootstrapConfig(n.cmd.Context(), n.appCtx, n.runCtx)
-	return err
+	if n.cfg, err = bootstrapConfig(n.cmd.Context(), n.appCtx, n.runCtx); err != nil {
+		return err
+	}
+	return n.integration.Complete()
 }
 
 // Validate checks if the required configuration is set.
@@ -0,0 +1,96 @@
+package subcmd
+
+import (
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// IntegrationNotification is the sub-command for the "integration
+// notification", responsible for creating and updating the chat notification
+// integration secret.
+type IntegrationNotification struct {
+	cmd         *cobra.Command           // cobra command
+	appCtx      *api.AppContext          // application context
+	runCtx      *runcontext.RunContext   // run context (kube, logger, chartfs)
+	cfg         *config.Config           // installer configuration
+	integration *integration.Integration // integration instance
+}
+
+var _ api.SubCommand = &IntegrationNotification{}
+
+// Cmd exposes the cobra instance.
+func (j *IntegrationNotification) Cmd() *cobra.Command {
+	return j.cmd
+}
+
+// Complete loads the configuration and resolves the integration credential.
+func (j *IntegrationNotification) Complete(_ []string) error {
+	var err error
+	if j.cfg, err = bootstrapConfig(j.cmd.Context(), j.appCtx, j.runCtx); err != nil {
+		return err
+	}
+	return j.integration.Complete()
+}
+
+// Validate checks if the required configuration is set.
+func (j *IntegrationNotification) Validate() error {
+	return j.integration.Validate()
+}
+
+// Run creates or updates the chat notification integration secret.
+func (j *IntegrationNotification) Run() error {
+	return j.integration.Create(j.cmd.Context(), j.runCtx, j.cfg)
+}
+
+// NewIntegrationNotification creates the sub-command for the "integration
+// notification" responsible to manage the chat notification integration.
+func NewIntegrationNotification(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	i *integration.Integration,
+) *IntegrationNotification {
+	j := &IntegrationNotification{
+		cmd: &cobra.Command{
+			Use:     "notification [flags]",
+			Aliases: []string{"slack", "teams"},
+			Short: fmt.Sprintf(
+				"Integrates a Slack or Teams channel into %s",
+				appCtx.Name,
+			),
+			Long: fmt.Sprintf(`
+Manages the chat notification integration with %s by storing the incoming
+webhook of a Slack or Microsoft Teams channel, products consume the secret to
+post their notifications, for instance the pipeline notifications.
+
+%s posts the deploy events informed by --deploy-events to the channel as well,
+the completed and failed deployments by default, or none when empty:
+
+  $ %s integration notification --provider=slack \
+      --webhook-url-stdin --channel="#deployments" < webhook-url.txt
+  $ %s integration notification --provider=teams \
+      --webhook-url="<url>" --deploy-events=""
+
+The webhook URL carries its credentials, it's stored in a Kubernetes Secret in
+the namespace configured for %s.`,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+			),
+			SilenceUsage: true,
+		},
+
+		appCtx:      appCtx,
+		runCtx:      runCtx,
+		integration: i,
+	}
+	i.PersistentFlags(j.cmd)
+	return j
+}
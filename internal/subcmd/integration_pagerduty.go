@@ -0,0 +1,100 @@
+package subcmd
+
+import (
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// IntegrationPagerDuty is the sub-command for the "integration pagerduty",
+// responsible for creating and updating the PagerDuty integration secret.
+type IntegrationPagerDuty struct {
+	cmd         *cobra.Command           // cobra command
+	appCtx      *api.AppContext          // application context
+	runCtx      *runcontext.RunContext   // run context (kube, logger, chartfs)
+	cfg         *config.Config           // installer configuration
+	integration *integration.Integration // integration instance
+}
+
+var _ api.SubCommand = &IntegrationPagerDuty{}
+
+// Cmd exposes the cobra instance.
+func (p *IntegrationPagerDuty) Cmd() *cobra.Command {
+	return p.cmd
+}
+
+// Complete loads the configuration and resolves the integration credential.
+func (p *IntegrationPagerDuty) Complete(_ []string) error {
+	var err error
+	if p.cfg, err = bootstrapConfig(p.cmd.Context(), p.appCtx, p.runCtx); err != nil {
+		return err
+	}
+	return p.integration.Complete()
+}
+
+// Validate checks if the required configuration is set.
+func (p *IntegrationPagerDuty) Validate() error {
+	return p.integration.Validate()
+}
+
+// Run creates or updates the PagerDuty integration secret, once the REST API
+// token, when informed, authenticates.
+func (p *IntegrationPagerDuty) Run() error {
+	return p.integration.Create(p.cmd.Context(), p.runCtx, p.cfg)
+}
+
+// NewIntegrationPagerDuty creates the sub-command for the "integration
+// pagerduty" responsible to manage the integration with PagerDuty, alerting the
+// on-call responders of the products.
+func NewIntegrationPagerDuty(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	i *integration.Integration,
+) *IntegrationPagerDuty {
+	p := &IntegrationPagerDuty{
+		cmd: &cobra.Command{
+			Use: "pagerduty --routing-key=key [flags]",
+			Short: fmt.Sprintf(
+				"Integrates a PagerDuty service into %s",
+				appCtx.Name,
+			),
+			Long: fmt.Sprintf(`
+Manages the PagerDuty integration with %s by storing the routing key of a
+PagerDuty service, the Events API v2 integration key alerting-aware products
+trigger incidents with, and optionally a REST API token, for the products
+managing the incidents and on-call schedules.
+
+The configuration is stored in a Kubernetes Secret in the namespace
+configured for %s. The REST API token, when informed, is verified to
+authenticate before the secret is stored:
+
+  $ %s integration pagerduty \
+	  --routing-key "<integration key>" \
+	  --api-token-stdin < pagerduty-token.txt
+
+Accounts on the EU service region inform its endpoints:
+
+  $ %s integration pagerduty \
+	  --routing-key "<integration key>" \
+	  --api-url "https://api.eu.pagerduty.com" \
+	  --events-url "https://events.eu.pagerduty.com/v2/enqueue"`,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+			),
+			SilenceUsage: true,
+		},
+
+		appCtx:      appCtx,
+		runCtx:      runCtx,
+		integration: i,
+	}
+	i.PersistentFlags(p.cmd)
+	return p
+}
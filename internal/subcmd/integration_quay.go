@@ -28,11 +28,13 @@ func (q *IntegrationQuay) Cmd() *cobra.Command {
 	return q.cmd
 }
 
-// Complete is a no-op in this case.
+// Complete loads the configuration and resolves the integration credential.
 func (q *IntegrationQuay) Complete(_ []string) error {
 	var err error
-	q.cfg, err = bootstrapConfig(q.cmd.Context(), q.appCtx, q.runCtx)
-	return err
+	if q.cfg, err = bootstrapConfig(q.cmd.Context(), q.appCtx, q.runCtx); err != nil {
+		return err
+	}
+	return q.integration.Complete()
 }
 
 // Validate checks if the required configuration is set.
@@ -0,0 +1,106 @@
+package subcmd
+
+import (
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// IntegrationSigstore is the sub-command for the "integration sigstore",
+// responsible for creating and updating the Sigstore integration secret.
+type IntegrationSigstore struct {
+	cmd         *cobra.Command           // cobra command
+	appCtx      *api.AppContext          // application context
+	runCtx      *runcontext.RunContext   // run context (kube, logger, chartfs)
+	cfg         *config.Config           // installer configuration
+	integration *integration.Integration // integration instance
+}
+
+var _ api.SubCommand = &IntegrationSigstore{}
+
+// Cmd exposes the cobra instance.
+func (s *IntegrationSigstore) Cmd() *cobra.Command {
+	return s.cmd
+}
+
+// Complete loads the configuration and resolves the integration credential.
+func (s *IntegrationSigstore) Complete(_ []string) error {
+	var err error
+	if s.cfg, err = bootstrapConfig(s.cmd.Context(), s.appCtx, s.runCtx); err != nil {
+		return err
+	}
+	return s.integration.Complete()
+}
+
+// Validate checks if the required configuration is set, reading the key pair.
+func (s *IntegrationSigstore) Validate() error {
+	return s.integration.Validate()
+}
+
+// Run creates or updates the Sigstore integration secret, once the Rekor
+// transparency log responds.
+func (s *IntegrationSigstore) Run() error {
+	return s.integration.Create(s.cmd.Context(), s.runCtx, s.cfg)
+}
+
+// NewIntegrationSigstore creates the sub-command for the "integration sigstore"
+// responsible to manage the integration with the Sigstore services, signing the
+// supply-chain artifacts with cosign.
+func NewIntegrationSigstore(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	i *integration.Integration,
+) *IntegrationSigstore {
+	s := &IntegrationSigstore{
+		cmd: &cobra.Command{
+			Aliases: []string{"cosign"},
+			Use:     "sigstore --rekor-url=url [flags]",
+			Short: fmt.Sprintf(
+				"Integrates the Sigstore signing services into %s",
+				appCtx.Name,
+			),
+			Long: fmt.Sprintf(`
+Manages the Sigstore integration with %s by storing the cosign key material,
+or the keyless signing coordinates, used by the supply-chain products to sign
+and verify the artifacts, along with the Rekor transparency log recording the
+signatures.
+
+The configuration is stored in a Kubernetes Secret in the namespace
+configured for %s, the key pair under the same keys "cosign generate-key-pair"
+uses, so cosign reads the secret as is. The Rekor transparency log is verified
+to respond before the secret is stored.
+
+Inform a cosign key pair, the private key password read from STDIN:
+
+  $ %s integration sigstore \
+	  --rekor-url "https://rekor.sigstore.dev" \
+	  --private-key-file cosign.key \
+	  --public-key-file cosign.pub \
+	  --password-stdin < cosign-password.txt
+
+Or the Fulcio certificate authority, for keyless signing:
+
+  $ %s integration sigstore \
+	  --rekor-url "https://rekor.sigstore.dev" \
+	  --fulcio-url "https://fulcio.sigstore.dev" \
+	  --oidc-issuer "https://oauth2.sigstore.dev/auth"`,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+			),
+			SilenceUsage: true,
+		},
+
+		appCtx:      appCtx,
+		runCtx:      runCtx,
+		integration: i,
+	}
+	i.PersistentFlags(s.cmd)
+	return s
+}
@@ -135,8 +135,12 @@ func TestNewIntegration_CommandNameInvariant(t *testing.T) {
 		moduleNames[mod.Name] = true
 	}
 
-	// Every child command name must be present in moduleNames.
+	// Every child command name must be present in moduleNames, besides the
+	// bundle commands handling all integrations.
 	for _, child := range cmd.Commands() {
+		if child.Name() == "export" || child.Name() == "import" {
+			continue
+		}
 		g.Expect(moduleNames).To(gomega.HaveKey(child.Name()),
 			"child command %q does not match any module name", child.Name())
 	}
@@ -29,11 +29,13 @@ func (t *IntegrationTrustificationAuth) Cmd() *cobra.Command {
 	return t.cmd
 }
 
-// Complete is a no-op in this case.
+// Complete loads the configuration and resolves the integration credential.
 func (t *IntegrationTrustificationAuth) Complete(_ []string) error {
 	var err error
-	t.cfg, err = bootstrapConfig(t.cmd.Context(), t.appCtx, t.runCtx)
-	return err
+	if t.cfg, err = bootstrapConfig(t.cmd.Context(), t.appCtx, t.runCtx); err != nil {
+		return err
+	}
+	return t.integration.Complete()
 }
 
 // Validate checks if the required configuration is set.
@@ -0,0 +1,89 @@
+package subcmd
+
+import (
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// IntegrationVault is the sub-command for the "integration vault",
+// responsible for creating and updating the Vault integration secret.
+type IntegrationVault struct {
+	cmd         *cobra.Command           // cobra command
+	appCtx      *api.AppContext          // application context
+	runCtx      *runcontext.RunContext   // run context (kube, logger, chartfs)
+	cfg         *config.Config           // installer configuration
+	integration *integration.Integration // integration instance
+}
+
+var _ api.SubCommand = &IntegrationVault{}
+
+// Cmd exposes the cobra instance.
+func (v *IntegrationVault) Cmd() *cobra.Command {
+	return v.cmd
+}
+
+// Complete loads the configuration and resolves the integration credential.
+func (v *IntegrationVault) Complete(_ []string) error {
+	var err error
+	if v.cfg, err = bootstrapConfig(v.cmd.Context(), v.appCtx, v.runCtx); err != nil {
+		return err
+	}
+	return v.integration.Complete()
+}
+
+// Validate checks if the required configuration is set.
+func (v *IntegrationVault) Validate() error {
+	return v.integration.Validate()
+}
+
+// Run creates or updates the Vault integration secret.
+func (v *IntegrationVault) Run() error {
+	return v.integration.Create(v.cmd.Context(), v.runCtx, v.cfg)
+}
+
+// NewIntegrationVault creates the sub-command for the "integration vault"
+// responsible to manage the integration with HashiCorp Vault.
+func NewIntegrationVault(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	i *integration.Integration,
+) *IntegrationVault {
+	v := &IntegrationVault{
+		cmd: &cobra.Command{
+			Use: "vault [flags]",
+			Short: fmt.Sprintf(
+				"Integrates a HashiCorp Vault instance into %s",
+				appCtx.Name,
+			),
+			Long: fmt.Sprintf(`
+Manages the HashiCorp Vault integration with %s by storing the address, token
+and KV version 2 mount required by %s services to interact with Vault.
+
+The credentials are stored in a Kubernetes Secret in the namespace
+configured for %s.
+
+When the '%s' setting is "%s", the other integration secrets are
+written to the Vault KV mount instead, at "<mount>/<namespace>/<secret>", using
+these credentials; thus this integration must be configured first.`,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				integration.SecretBackendSetting,
+				integration.SecretBackendVault,
+			),
+			SilenceUsage: true,
+		},
+
+		appCtx:      appCtx,
+		runCtx:      runCtx,
+		integration: i,
+	}
+	i.PersistentFlags(v.cmd)
+	return v
+}
@@ -0,0 +1,93 @@
+package subcmd
+
+import (
+	"fmt"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// IntegrationXray is the sub-command for the "integration xray", responsible
+// for creating and updating the JFrog Xray integration secret.
+type IntegrationXray struct {
+	cmd         *cobra.Command           // cobra command
+	appCtx      *api.AppContext          // application context
+	runCtx      *runcontext.RunContext   // run context (kube, logger, chartfs)
+	cfg         *config.Config           // installer configuration
+	integration *integration.Integration // integration instance
+}
+
+var _ api.SubCommand = &IntegrationXray{}
+
+// Cmd exposes the cobra instance.
+func (x *IntegrationXray) Cmd() *cobra.Command {
+	return x.cmd
+}
+
+// Complete loads the configuration and resolves the integration credential.
+func (x *IntegrationXray) Complete(_ []string) error {
+	var err error
+	if x.cfg, err = bootstrapConfig(x.cmd.Context(), x.appCtx, x.runCtx); err != nil {
+		return err
+	}
+	return x.integration.Complete()
+}
+
+// Validate checks if the required configuration is set.
+func (x *IntegrationXray) Validate() error {
+	return x.integration.Validate()
+}
+
+// Run creates or updates the Xray integration secret, once the access token
+// authenticates.
+func (x *IntegrationXray) Run() error {
+	return x.integration.Create(x.cmd.Context(), x.runCtx, x.cfg)
+}
+
+// NewIntegrationXray creates the sub-command for the "integration xray"
+// responsible to manage the integration with JFrog Xray, scanning the artifacts
+// stored on Artifactory.
+func NewIntegrationXray(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	i *integration.Integration,
+) *IntegrationXray {
+	x := &IntegrationXray{
+		cmd: &cobra.Command{
+			Use: "xray --url=url [flags]",
+			Short: fmt.Sprintf(
+				"Integrates a JFrog Xray instance into %s",
+				appCtx.Name,
+			),
+			Long: fmt.Sprintf(`
+Manages the JFrog Xray integration with %s by storing the URL and access
+token required by %s services to scan artifacts with Xray. Artifact storage
+is managed by the "artifactory" integration, products requiring the scanning
+require "xray" instead, or both.
+
+The credentials are stored in a Kubernetes Secret in the namespace
+configured for %s. The access token is verified to authenticate before the
+secret is stored:
+
+  $ %s integration xray \
+	  --url "https://example.jfrog.io/xray" \
+	  --token-stdin < xray-token.txt`,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+				appCtx.Name,
+			),
+			SilenceUsage: true,
+		},
+
+		appCtx:      appCtx,
+		runCtx:      runCtx,
+		integration: i,
+	}
+	i.PersistentFlags(x.cmd)
+	return x
+}
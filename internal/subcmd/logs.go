@@ -0,0 +1,148 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/logs"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// Logs represents the "logs" subcommand, it prints the logs of the product
+// workloads pods, resolved from its deployed release.
+type Logs struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	collection *resolver.Collection // chart collection
+	cfg        *config.Config       // installer configuration
+	name       string               // product, or dependency, name
+	opts       logs.Options         // logs selection
+}
+
+var _ api.SubCommand = (*Logs)(nil)
+
+const logsDesc = `
+Prints the logs of the product workloads, saving the hunt for pod names after
+installation issues. The product, or the dependency, is resolved on the
+topology, and its deployed release inspected: the pods selected by its
+Deployments, StatefulSets and DaemonSets are the ones read, every container,
+init containers included, unless --container is informed.
+
+Each line is prefixed by its source, "[pod/container]", the logs are printed
+one container after the other, or aggregated as they come with --follow, until
+interrupted. For instance:
+
+  $ %s logs "Product A" --since=10m
+  $ %s logs "Product A" --container=api --tail=100 --follow
+`
+
+// Cmd exposes the cobra instance.
+func (l *Logs) Cmd() *cobra.Command {
+	return l.cmd
+}
+
+// log returns a decorated logger.
+func (l *Logs) log() *slog.Logger {
+	return l.flags.LoggerWith(l.runCtx.Logger.With(
+		"name", l.name,
+		"container", l.opts.Container,
+		"since", l.opts.Since,
+		"tail", l.opts.Tail,
+		"follow", l.opts.Follow,
+	))
+}
+
+// Complete loads the charts and the cluster configuration.
+func (l *Logs) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expected the product name", helmeterrors.ErrInvalidUsage)
+	}
+	l.name = args[0]
+
+	charts, err := l.runCtx.ChartFS.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	if l.collection, err = resolver.NewCollection(l.appCtx, charts); err != nil {
+		return err
+	}
+	l.cfg, err = bootstrapConfig(l.cmd.Context(), l.appCtx, l.runCtx)
+	return err
+}
+
+// Validate asserts the logs selection is valid.
+func (l *Logs) Validate() error {
+	if l.opts.Since < 0 {
+		return fmt.Errorf("%w: --since must not be negative",
+			helmeterrors.ErrInvalidUsage)
+	}
+	if l.opts.Tail < 0 {
+		return fmt.Errorf("%w: --tail must not be negative",
+			helmeterrors.ErrInvalidUsage)
+	}
+	return nil
+}
+
+// Run resolves the product workloads pods and prints their logs.
+func (l *Logs) Run() error {
+	topology := resolver.NewTopology()
+	err := resolver.NewResolver(l.cfg, l.collection, topology).Resolve()
+	if err != nil {
+		return err
+	}
+	ctx := l.cmd.Context()
+	dep, workloads, err := logs.Workloads(
+		l.log(), l.flags, l.runCtx.Kube, topology, l.name)
+	if err != nil {
+		return err
+	}
+	l.log().Debug("Reading the workloads logs",
+		"dependency", dep.Name(), "namespace", dep.Namespace(),
+		"workloads", len(workloads))
+
+	reader := logs.NewLogs(l.log(), l.runCtx.Kube, l.opts)
+	sources, err := reader.Sources(ctx, workloads)
+	if err != nil {
+		return err
+	}
+	return reader.Write(ctx, l.cmd.OutOrStdout(), sources)
+}
+
+// NewLogs instantiates the "logs" subcommand.
+func NewLogs(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *Logs {
+	l := &Logs{
+		cmd: &cobra.Command{
+			Use:          "logs <product> [flags]",
+			Short:        "Prints the logs of the product workloads",
+			Long:         fmt.Sprintf(logsDesc, appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx: appCtx,
+		runCtx: runCtx,
+		flags:  f,
+	}
+	p := l.cmd.PersistentFlags()
+	p.StringVarP(&l.opts.Container, "container", "c", l.opts.Container,
+		"Container name, every container of the pods by default")
+	p.DurationVar(&l.opts.Since, "since", l.opts.Since,
+		"Only the logs newer than the duration, e.g. 10m, every log by default")
+	p.Int64Var(&l.opts.Tail, "tail", l.opts.Tail,
+		"Last lines per container, every line by default")
+	p.BoolVarP(&l.opts.Follow, "follow", "f", l.opts.Follow,
+		"Streams the new lines of every container until interrupted")
+	return l
+}
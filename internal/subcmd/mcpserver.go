@@ -1,17 +1,23 @@
 package subcmd
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"strings"
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/framework/mcpserver"
 	"github.com/redhat-appstudio/helmet/internal/constants"
 	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/installer"
 	"github.com/redhat-appstudio/helmet/internal/integrations"
 	"github.com/redhat-appstudio/helmet/internal/mcptools"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 )
 
 // MCPServer is a subcommand for starting the MCP server.
@@ -23,7 +29,15 @@ type MCPServer struct {
 	flags           *flags.Flags
 	manager         *integrations.Manager    // integrations manager
 	mcpToolsBuilder mcptools.MCPToolsBuilder // builder function
+	toolFilter      mcptools.ToolFilter      // exposed tools filter
 	image           string                   // installer's container image
+	failTools       []string                 // tools failing on purpose, "name=error"
+	failures        map[string]string        // tool failures, by tool name
+
+	emitManifests      bool                // emit the deployment manifests
+	valuesTemplatePath string              // values template file path
+	installerTarball   []byte              // embedded installer tarball
+	valuesContextFn    api.ValuesContextFn // values template context
 }
 
 var _ api.SubCommand = (*MCPServer)(nil)
@@ -32,6 +46,17 @@ var _ api.SubCommand = (*MCPServer)(nil)
 func (m *MCPServer) PersistentFlags(cmd *cobra.Command) {
 	p := cmd.PersistentFlags()
 	p.StringVar(&m.image, "image", m.image, "container image for the installer\n")
+	p.BoolVar(&m.emitManifests, "emit-manifests", m.emitManifests,
+		"Print the in-cluster deployment manifests, with RBAC scoped to the "+
+			"catalog, and exit")
+	flags.SetValuesTmplFlag(p, &m.valuesTemplatePath)
+
+	// Failure injection for the end-to-end tests, not meant for users.
+	p.StringArrayVar(&m.failTools, "fail-tool", m.failTools,
+		"Makes the tool fail with the error message, formatted as 'name=error'")
+	if err := p.MarkHidden("fail-tool"); err != nil {
+		panic(err)
+	}
 }
 
 // Cmd exposes the cobra instance.
@@ -39,8 +64,17 @@ func (m *MCPServer) Cmd() *cobra.Command {
 	return m.cmd
 }
 
-// Complete implements api.SubCommand.
+// Complete parses the tool failures, informed as "name=error".
 func (m *MCPServer) Complete(_ []string) error {
+	m.failures = map[string]string{}
+	for _, failTool := range m.failTools {
+		name, message, ok := strings.Cut(failTool, "=")
+		if !ok || name == "" || message == "" {
+			return fmt.Errorf("invalid --fail-tool %q, expecting 'name=error'",
+				failTool)
+		}
+		m.failures[name] = message
+	}
 	return nil
 }
 
@@ -49,8 +83,91 @@ func (m *MCPServer) Validate() error {
 	return nil
 }
 
-// Run starts the MCP server.
+// emit renders the enabled dependencies, as the deployment would, to compute
+// the minimal RBAC rules, and prints the manifests to run the deployment job
+// in-cluster with them.
+func (m *MCPServer) emit() error {
+	ctx := m.cmd.Context()
+	logger := m.flags.LoggerWith(m.runCtx.Logger)
+
+	charts, err := m.runCtx.ChartFS.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	collection, err := resolver.NewCollection(m.appCtx, charts)
+	if err != nil {
+		return err
+	}
+	cfg, err := bootstrapConfig(ctx, m.appCtx, m.runCtx)
+	if err != nil {
+		return err
+	}
+	topology := resolver.NewTopology()
+	err = resolver.NewResolver(cfg, collection, topology).Resolve()
+	if err != nil {
+		return err
+	}
+
+	valuesTmpl, err := m.runCtx.ChartFS.ReadFile(m.valuesTemplatePath)
+	if err != nil {
+		return err
+	}
+	valuesContext, err := valuesContext(ctx, m.valuesContextFn, m.runCtx, cfg)
+	if err != nil {
+		return err
+	}
+	manifests := []string{}
+	for _, dep := range topology.Dependencies() {
+		logger.Debug("Rendering the dependency", "dependency", dep.Name())
+		i := installer.NewInstaller(
+			logger, m.flags, m.runCtx.Kube, &dep, m.installerTarball)
+		i.SetValuesContext(valuesContext)
+		i.SetValueRefAllowlist(m.appCtx.ValueReferences)
+		i.SetManagedBy(m.appCtx.Name)
+		if err = i.SetValues(ctx, cfg, string(valuesTmpl)); err != nil {
+			return err
+		}
+		if err = i.RenderValues(ctx); err != nil {
+			return err
+		}
+		manifest, err := i.Manifest(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", dep.Name(), err)
+		}
+		manifests = append(manifests, manifest)
+	}
+
+	// Without the cluster's REST mapping the resource names are guessed from the
+	// kinds, still valid for the conventional plurals.
+	mapper, err := m.runCtx.Kube.RESTClientGetter("").ToRESTMapper()
+	if err != nil {
+		logger.Debug("Unable to discover the cluster resources", "err", err)
+		mapper = nil
+	}
+	rules, err := installer.RBACRules(
+		cfg, m.appCtx.ConfigBackend, mapper, manifests...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", installer.ErrRender, err)
+	}
+
+	job := installer.NewJob(m.appCtx, m.runCtx.Kube)
+	objs := job.Manifests(rules, false, false, cfg.Namespace(), m.image)
+	w := m.cmd.OutOrStdout()
+	for _, obj := range objs {
+		payload, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "---\n%s", payload)
+	}
+	return nil
+}
+
+// Run starts the MCP server, or prints the deployment manifests.
 func (m *MCPServer) Run() error {
+	if m.emitManifests {
+		return m.emit()
+	}
 	toolsCtx := mcptools.NewMCPToolsContext(
 		m.appCtx,
 		m.runCtx,
@@ -65,14 +182,31 @@ func (m *MCPServer) Run() error {
 		return fmt.Errorf("failed to create MCP tools: %w", err)
 	}
 
+	// The static instructions are optional, used as preamble for the generated
+	// instructions.
 	instructions, err := m.runCtx.ChartFS.ReadFile(constants.InstructionsFilename)
-	if err != nil {
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		return fmt.Errorf("failed to read %s: %w",
 			constants.InstructionsFilename, err)
 	}
+	generator, err := mcptools.NewInstructions(toolsCtx, string(instructions))
+	if err != nil {
+		return fmt.Errorf("failed to create MCP instructions: %w", err)
+	}
 
 	s := mcpserver.NewMCPServer(m.appCtx, string(instructions))
 	s.AddTools(tools...)
+	if m.toolFilter != nil {
+		if err = s.FilterTools(m.toolFilter); err != nil {
+			return fmt.Errorf("failed to filter MCP tools: %w", err)
+		}
+	}
+	if len(m.failures) > 0 {
+		if err = s.InjectFailures(m.failures); err != nil {
+			return fmt.Errorf("failed to inject MCP tool failures: %w", err)
+		}
+	}
+	s.SetInstructionsFn(generator.Generate)
 
 	return s.Start()
 }
@@ -86,15 +220,25 @@ func NewMCPServer(
 	f *flags.Flags,
 	manager *integrations.Manager,
 	builder mcptools.MCPToolsBuilder,
+	toolFilter mcptools.ToolFilter,
 	image string,
+	installerTarball []byte,
+	valuesContextFn api.ValuesContextFn,
 ) *MCPServer {
 	m := &MCPServer{
 		cmd: &cobra.Command{
 			Use:   "mcp-server",
 			Short: "Starts the MCP server",
 			Long: fmt.Sprintf(`
-Starts the MCP server for the %s installer, using STDIO communication.`,
-				appCtx.Name,
+Starts the MCP server for the %s installer, using STDIO communication.
+
+With "--emit-manifests" the server doesn't start, instead it prints the
+manifests to run the deployment job in-cluster: the service account, a
+ClusterRole limited to the resources the enabled dependencies render, its
+binding, and the job. For instance:
+
+  $ %s mcp-server --emit-manifests | oc apply -f -`,
+				appCtx.Name, appCtx.Name,
 			),
 		},
 
@@ -103,7 +247,11 @@ Starts the MCP server for the %s installer, using STDIO communication.`,
 		flags:           f,
 		manager:         manager,
 		mcpToolsBuilder: builder,
+		toolFilter:      toolFilter,
 		image:           image,
+
+		installerTarball: installerTarball,
+		valuesContextFn:  valuesContextFn,
 	}
 
 	m.PersistentFlags(m.cmd)
@@ -19,25 +19,33 @@ func standardMCPTools(
 	toolsCtx mcptools.MCPToolsContext,
 ) ([]mcptools.Interface, error) {
 	cm := config.NewConfigMapManager(toolsCtx.Kube, toolsCtx.AppContext.Name)
+	cm.SetProtectedFields(toolsCtx.AppContext.ProtectedConfig)
+	cm.SetSensitiveFields(toolsCtx.AppContext.SensitiveConfig)
+	cm.SetBackend(toolsCtx.AppContext.ConfigBackend)
+	cm.SetSettings(toolsCtx.AppContext.Settings)
+	cm.SetMigrations(toolsCtx.AppContext.ConfigMigrations)
+	cm.SetTransforms(config.Transforms(toolsCtx.AppContext.ConfigTransforms))
+	cm.SetActor(config.ActorMCP)
 
-	// Config tools.
-	configTools, err := mcptools.NewConfigTools(
+	// Topology builder (shared dependency).
+	tb, err := resolver.NewTopologyBuilder(
 		toolsCtx.AppContext,
 		toolsCtx.Logger,
 		toolsCtx.ChartFS,
-		toolsCtx.Kube,
-		cm,
+		toolsCtx.IntegrationManager,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	// Topology builder (shared dependency).
-	tb, err := resolver.NewTopologyBuilder(
+	// Config tools.
+	configTools, err := mcptools.NewConfigTools(
 		toolsCtx.AppContext,
 		toolsCtx.Logger,
 		toolsCtx.ChartFS,
-		toolsCtx.IntegrationManager,
+		toolsCtx.Kube,
+		cm,
+		tb,
 	)
 	if err != nil {
 		return nil, err
@@ -48,7 +56,9 @@ func standardMCPTools(
 
 	// Status tool.
 	statusTool := mcptools.NewStatusTool(
-		toolsCtx.AppContext.IdentifierName(), cm, tb, job)
+		toolsCtx.AppContext.IdentifierName(), cm, tb, job,
+		toolsCtx.IntegrationManager, toolsCtx.Kube,
+		toolsCtx.AppContext.CapacityRules, toolsCtx.AppContext.Checkers)
 
 	// Integration tools, creates its own instance for metadata introspection.
 	integrationCmd := NewIntegration(
@@ -59,7 +69,7 @@ func standardMCPTools(
 	)
 	integrationTools := mcptools.NewIntegrationTools(
 		toolsCtx.AppContext.IdentifierName(), toolsCtx.AppContext.Name,
-		integrationCmd, cm, toolsCtx.IntegrationManager,
+		integrationCmd, cm, toolsCtx.IntegrationManager, tb,
 	)
 
 	// Deploy tools.
@@ -77,6 +87,16 @@ func standardMCPTools(
 		job,
 	)
 
+	// Logs tool.
+	logsTool := mcptools.NewLogsTool(
+		toolsCtx.AppContext.IdentifierName(),
+		toolsCtx.Logger,
+		toolsCtx.Flags,
+		toolsCtx.Kube,
+		cm,
+		tb,
+	)
+
 	// Topology tool
 	topologyTool := mcptools.NewTopologyTool(
 		toolsCtx.AppContext.IdentifierName(), toolsCtx.ChartFS, cm, tb)
@@ -87,6 +107,7 @@ func standardMCPTools(
 		integrationTools,
 		deployTools,
 		notesTool,
+		logsTool,
 		topologyTool,
 	}, nil
 }
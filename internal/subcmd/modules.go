@@ -11,6 +11,16 @@ import (
 )
 
 var (
+	ACRModule = api.IntegrationModule{
+		Name: string(integrations.ACR),
+		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
+			return integration.NewACR()
+		},
+		Command: func(appCtx *api.AppContext, runCtx *runcontext.RunContext, i *integration.Integration) api.SubCommand {
+			return NewIntegrationACR(appCtx, runCtx, i)
+		},
+	}
+
 	ACSModule = api.IntegrationModule{
 		Name: string(integrations.ACS),
 		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
@@ -51,6 +61,26 @@ var (
 		},
 	}
 
+	ECRModule = api.IntegrationModule{
+		Name: string(integrations.ECR),
+		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
+			return integration.NewECR()
+		},
+		Command: func(appCtx *api.AppContext, runCtx *runcontext.RunContext, i *integration.Integration) api.SubCommand {
+			return NewIntegrationECR(appCtx, runCtx, i)
+		},
+	}
+
+	GARModule = api.IntegrationModule{
+		Name: string(integrations.GAR),
+		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
+			return integration.NewGAR()
+		},
+		Command: func(appCtx *api.AppContext, runCtx *runcontext.RunContext, i *integration.Integration) api.SubCommand {
+			return NewIntegrationGAR(appCtx, runCtx, i)
+		},
+	}
+
 	GitHubModule = api.IntegrationModule{
 		Name: string(integrations.GitHub),
 		Init: func(l *slog.Logger, _ k8s.Interface) integration.Interface {
@@ -81,6 +111,16 @@ var (
 		},
 	}
 
+	KeycloakModule = api.IntegrationModule{
+		Name: string(integrations.Keycloak),
+		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
+			return integration.NewKeycloak()
+		},
+		Command: func(appCtx *api.AppContext, runCtx *runcontext.RunContext, i *integration.Integration) api.SubCommand {
+			return NewIntegrationKeycloak(appCtx, runCtx, i)
+		},
+	}
+
 	NexusModule = api.IntegrationModule{
 		Name: string(integrations.Nexus),
 		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
@@ -91,6 +131,26 @@ var (
 		},
 	}
 
+	NotificationModule = api.IntegrationModule{
+		Name: string(integrations.Notification),
+		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
+			return integration.NewNotification()
+		},
+		Command: func(appCtx *api.AppContext, runCtx *runcontext.RunContext, i *integration.Integration) api.SubCommand {
+			return NewIntegrationNotification(appCtx, runCtx, i)
+		},
+	}
+
+	PagerDutyModule = api.IntegrationModule{
+		Name: string(integrations.PagerDuty),
+		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
+			return integration.NewPagerDuty()
+		},
+		Command: func(appCtx *api.AppContext, runCtx *runcontext.RunContext, i *integration.Integration) api.SubCommand {
+			return NewIntegrationPagerDuty(appCtx, runCtx, i)
+		},
+	}
+
 	QuayModule = api.IntegrationModule{
 		Name: string(integrations.Quay),
 		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
@@ -101,6 +161,16 @@ var (
 		},
 	}
 
+	SigstoreModule = api.IntegrationModule{
+		Name: string(integrations.Sigstore),
+		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
+			return integration.NewSigstore()
+		},
+		Command: func(appCtx *api.AppContext, runCtx *runcontext.RunContext, i *integration.Integration) api.SubCommand {
+			return NewIntegrationSigstore(appCtx, runCtx, i)
+		},
+	}
+
 	TrustedArtifactSignerModule = api.IntegrationModule{
 		Name: string(integrations.TrustedArtifactSigner),
 		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
@@ -130,22 +200,51 @@ var (
 			return NewIntegrationTrustification(appCtx, runCtx, i)
 		},
 	}
+
+	VaultModule = api.IntegrationModule{
+		Name: string(integrations.Vault),
+		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
+			return integration.NewVault()
+		},
+		Command: func(appCtx *api.AppContext, runCtx *runcontext.RunContext, i *integration.Integration) api.SubCommand {
+			return NewIntegrationVault(appCtx, runCtx, i)
+		},
+	}
+
+	XrayModule = api.IntegrationModule{
+		Name: string(integrations.Xray),
+		Init: func(_ *slog.Logger, _ k8s.Interface) integration.Interface {
+			return integration.NewXray()
+		},
+		Command: func(appCtx *api.AppContext, runCtx *runcontext.RunContext, i *integration.Integration) api.SubCommand {
+			return NewIntegrationXray(appCtx, runCtx, i)
+		},
+	}
 )
 
 // StandardModules returns the list of standard integration modules.
 func StandardModules() []api.IntegrationModule {
 	return []api.IntegrationModule{
+		ACRModule,
 		ACSModule,
 		ArtifactoryModule,
 		AzureModule,
 		BitBucketModule,
+		ECRModule,
+		GARModule,
 		GitHubModule,
 		GitLabModule,
 		JenkinsModule,
+		KeycloakModule,
 		NexusModule,
+		NotificationModule,
+		PagerDutyModule,
 		QuayModule,
+		SigstoreModule,
 		TrustedArtifactSignerModule,
 		TrustificationAuthModule,
 		TrustificationModule,
+		VaultModule,
+		XrayModule,
 	}
 }
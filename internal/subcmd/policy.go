@@ -0,0 +1,41 @@
+package subcmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/policy"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+)
+
+// policyLookupTimeout bounds reading the cluster configuration for the policy,
+// commands not reaching the cluster must not wait on it.
+const policyLookupTimeout = 5 * time.Second
+
+// LoadPolicy returns the operations policy on the cluster configuration. When
+// the cluster configuration isn't available, before "config --create" or with
+// the cluster unreachable, the policy embedded on the installer configuration
+// file applies instead.
+func LoadPolicy(
+	ctx context.Context,
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+) (*policy.Policy, error) {
+	ctx, cancel := context.WithTimeout(ctx, policyLookupTimeout)
+	defer cancel()
+
+	cfg, err := newConfigMapManager(appCtx, runCtx).GetConfig(ctx)
+	if err == nil {
+		return policy.NewPolicyFromConfig(cfg)
+	}
+	runCtx.Logger.Debug("Using the embedded operations policy", "reason", err)
+	cfg, err = config.NewConfigDefault(
+		runCtx.ChartFS, appCtx.Namespace, appCtx.IdentifierName())
+	if err != nil {
+		runCtx.Logger.Debug("Embedded configuration not available", "err", err)
+		return &policy.Policy{}, nil
+	}
+	return policy.NewPolicyFromConfig(cfg)
+}
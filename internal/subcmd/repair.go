@@ -0,0 +1,348 @@
+package subcmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+	"github.com/redhat-appstudio/helmet/internal/monitor"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+	"github.com/redhat-appstudio/helmet/internal/scan"
+
+	"github.com/spf13/cobra"
+)
+
+// Repair represents the "repair" subcommand, it redeploys the unhealthy
+// dependency releases and their downstream dependents.
+type Repair struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+	cfg    *config.Config // installer configuration
+
+	manager            *integrations.Manager     // integration manager
+	topologyBuilder    *resolver.TopologyBuilder // topology builder
+	valuesTemplatePath string                    // values template file path
+	installerTarball   []byte                    // embedded installer tarball
+	valuesContextFn    api.ValuesContextFn       // values template context
+	check              bool                      // only report the health
+	namespaceLabels    map[string]string         // product namespace labels
+	policy             *scan.Policy              // security policy gate
+	history            *installer.History        // deployment history
+	output             string                    // output format flag
+	out                *printer.Output           // output printer
+}
+
+var _ api.SubCommand = (*Repair)(nil)
+
+const repairDesc = `
+Checks the health of the deployed dependency releases, and redeploys only the
+unhealthy ones, followed by their downstream dependents, the dependencies
+listing them on "depends-on", directly or transitively. A release is unhealthy
+when:
+
+  - It's not installed, or its status isn't "deployed", after a failed or
+    interrupted install or upgrade.
+  - It drifted, its manifests differ from the ones rendered with the current
+    configuration and values template.
+  - Its Deployments, StatefulSets or DaemonSets are missing from the cluster.
+
+The health of each dependency is printed first, with --check the command stops
+there, failing when any release is unhealthy. Otherwise, the dependencies are
+re-rendered and upgraded as "deploy" does, in topology order, the first failure
+skips the remaining ones. The repair is recorded on the deployment history
+ConfigMap, with the unhealthy dependencies found and the ones redeployed.
+
+  $ %s repair --check
+  $ %s repair
+`
+
+// Cmd exposes the cobra instance.
+func (r *Repair) Cmd() *cobra.Command {
+	return r.cmd
+}
+
+// log logger with contextual information.
+func (r *Repair) log() *slog.Logger {
+	return r.flags.LoggerWith(r.runCtx.Logger.With(
+		flags.ValuesTemplateFlag, r.valuesTemplatePath,
+		"check", r.check,
+	))
+}
+
+// Complete loads the topology builder and the cluster configuration.
+func (r *Repair) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: unexpected arguments: %v",
+			helmeterrors.ErrInvalidUsage, args)
+	}
+	var err error
+	r.topologyBuilder, err = resolver.NewTopologyBuilder(
+		r.appCtx, r.runCtx.Logger, r.runCtx.ChartFS, r.manager)
+	if err != nil {
+		return err
+	}
+	r.cfg, err = bootstrapConfig(r.cmd.Context(), r.appCtx, r.runCtx)
+	return err
+}
+
+// Validate asserts the settings applied on redeploy, and the output format, are
+// valid.
+func (r *Repair) Validate() error {
+	var err error
+	if r.namespaceLabels, err = installer.NamespaceLabels(r.cfg); err != nil {
+		return err
+	}
+	if r.policy, err = scan.NewPolicyFromConfig(r.cfg); err != nil {
+		return err
+	}
+	r.out, err = printer.NewOutput(r.output)
+	return err
+}
+
+// newInstaller instantiates the dependency installer with the values rendered,
+// configured as "deploy" does.
+func (r *Repair) newInstaller(
+	dep *resolver.Dependency,
+	valuesTmpl []byte,
+	valuesContext map[string]any,
+) (*installer.Installer, error) {
+	i := installer.NewInstaller(
+		r.log(), r.flags, r.runCtx.Kube, dep, r.installerTarball)
+	i.SetValuesContext(valuesContext)
+	i.SetValueRefAllowlist(r.appCtx.ValueReferences)
+	i.SetManagedBy(r.appCtx.Name)
+	i.SetNamespaceLabels(r.namespaceLabels)
+	i.SetSecurityPolicy(r.policy)
+	i.SetMonitorOptions(monitor.Options{
+		Strategy: monitor.StrategyPoll,
+		Interval: monitor.DefaultPollInterval,
+	})
+	i.SetReplicator(integration.NewReplicator(
+		r.log(), r.runCtx.Kube, r.cfg.Namespace()))
+	if err := i.SetValues(r.cmd.Context(), r.cfg, string(valuesTmpl)); err != nil {
+		return nil, err
+	}
+	if err := i.RenderValues(r.cmd.Context()); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// printHealth prints the health of the dependencies, on the output format.
+func (r *Repair) printHealth(health []installer.Health) error {
+	if !r.out.Table() {
+		return r.out.Print(r.cmd.OutOrStdout(), health)
+	}
+	table := tabwriter.NewWriter(r.cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "DEPENDENCY\tNAMESPACE\tHEALTH\tREASONS")
+	for _, h := range health {
+		status, reasons := "healthy", "-"
+		if !h.Healthy() {
+			status, reasons = "unhealthy", strings.Join(h.Reasons, "; ")
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\n",
+			h.Dependency, h.Namespace, status, reasons)
+	}
+	return table.Flush()
+}
+
+// Run checks the dependencies health, and redeploys the repair set.
+func (r *Repair) Run() error {
+	r.log().Debug("Reading values template file")
+	valuesTmpl, err := r.runCtx.ChartFS.ReadFile(r.valuesTemplatePath)
+	if err != nil {
+		return err
+	}
+	ctx := r.cmd.Context()
+	topology, err := r.topologyBuilder.Build(ctx, r.cfg)
+	if err != nil {
+		return err
+	}
+	deps := topology.Dependencies()
+	valuesContext, err := valuesContext(
+		ctx, r.valuesContextFn, r.runCtx, r.cfg)
+	if err != nil {
+		return err
+	}
+
+	health := []installer.Health{}
+	unhealthy := map[string][]string{}
+	for _, dep := range deps {
+		r.log().Debug("Checking the release health", "dependency", dep.Name())
+		i, err := r.newInstaller(&dep, valuesTmpl, valuesContext)
+		if err != nil {
+			return fmt.Errorf("%s: %w", dep.Name(), err)
+		}
+		h, err := i.Health(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", dep.Name(), err)
+		}
+		health = append(health, *h)
+		if !h.Healthy() {
+			unhealthy[h.Dependency] = h.Reasons
+		}
+	}
+	if err = r.printHealth(health); err != nil {
+		return err
+	}
+
+	selected := map[string]bool{}
+	for name := range unhealthy {
+		selected[name] = true
+	}
+	repair := installer.RepairSet(deps, selected)
+	if len(repair) == 0 {
+		fmt.Fprintln(r.cmd.ErrOrStderr(), "\nAll releases are healthy.")
+		return nil
+	}
+	names := make([]string, 0, len(repair))
+	for _, dep := range repair {
+		names = append(names, dep.Name())
+	}
+	fmt.Fprintf(r.cmd.ErrOrStderr(), "\nDependencies to redeploy: %s\n",
+		strings.Join(names, ", "))
+	if r.check {
+		return fmt.Errorf("%w: %d release(s) need to be repaired",
+			installer.ErrUnhealthy, len(unhealthy))
+	}
+
+	r.history = installer.NewHistory(
+		r.runCtx.Kube, r.cfg.Namespace(), r.appCtx.Name)
+	if err = r.history.Load(ctx); err != nil {
+		r.log().Warn("Unable to read the deployment history", "err", err)
+	}
+	record := installer.Repair{
+		Time:       time.Now().UTC().Truncate(time.Second),
+		Unhealthy:  unhealthy,
+		Redeployed: []string{},
+	}
+	summary := installer.NewSummary(0)
+	for index, dep := range repair {
+		result := installer.Result{
+			Name:      dep.Name(),
+			Namespace: dep.Namespace(),
+			Expected:  r.history.Expected(dep.Name()),
+		}
+		if summary.Err() != nil {
+			result.Status = installer.StatusSkipped
+			result.Err = errors.New("a previous dependency failed")
+			summary.Add(result)
+			continue
+		}
+		start := time.Now()
+		result.Attempts = 1
+		result.Err = r.redeploy(
+			index, len(repair), &dep, valuesTmpl, valuesContext)
+		result.Duration = time.Since(start)
+		if result.Err != nil {
+			result.Status = installer.StatusFailed
+		} else {
+			result.Status = installer.StatusDeployed
+			record.Redeployed = append(record.Redeployed, dep.Name())
+		}
+		summary.Add(result)
+		r.history.Record(result)
+	}
+	summary.Print(r.cmd.OutOrStdout())
+	err = summary.Err()
+	if err != nil {
+		record.Error = err.Error()
+	}
+	r.saveHistory(record)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Repair complete!\n")
+	return nil
+}
+
+// redeploy re-renders and upgrades the dependency.
+func (r *Repair) redeploy(
+	index, total int,
+	dep *resolver.Dependency,
+	valuesTmpl []byte,
+	valuesContext map[string]any,
+) error {
+	fmt.Printf("\n\n%s\n", strings.Repeat("#", 60))
+	fmt.Printf("# [%d/%d] Repairing '%s' in '%s'.\n",
+		index+1, total, dep.Name(), dep.Namespace())
+	fmt.Printf("%s\n", strings.Repeat("#", 60))
+
+	i, err := r.newInstaller(dep, valuesTmpl, valuesContext)
+	if err != nil {
+		return err
+	}
+	if r.flags.Verbose {
+		i.PrintValues()
+	}
+	ctx := r.cmd.Context()
+	if err = i.Install(ctx); err != nil {
+		return err
+	}
+	// Cleaning up temporary resources.
+	if err = k8s.RetryDeleteResources(
+		ctx, r.runCtx.Kube, r.cfg.Namespace(),
+	); err != nil {
+		r.log().Debug(err.Error())
+	}
+	fmt.Printf("%s\n", strings.Repeat("#", 60))
+	return nil
+}
+
+// saveHistory records the repair, and the durations, on the deployment history.
+// Failing to do so doesn't fail the repair, dry-runs aren't recorded.
+func (r *Repair) saveHistory(record installer.Repair) {
+	if r.flags.DryRun {
+		return
+	}
+	r.history.RecordRepair(record)
+	if err := r.history.Save(r.cmd.Context()); err != nil {
+		r.log().Warn("Unable to save the deployment history", "err", err)
+	}
+}
+
+// NewRepair instantiates the "repair" subcommand.
+func NewRepair(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+	manager *integrations.Manager,
+	installerTarball []byte,
+	valuesContextFn api.ValuesContextFn,
+) *Repair {
+	r := &Repair{
+		cmd: &cobra.Command{
+			Use:          "repair",
+			Short:        "Redeploys the unhealthy releases and their dependents",
+			Long:         fmt.Sprintf(repairDesc, appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx:           appCtx,
+		runCtx:           runCtx,
+		flags:            f,
+		manager:          manager,
+		installerTarball: installerTarball,
+		valuesContextFn:  valuesContextFn,
+	}
+	p := r.cmd.PersistentFlags()
+	flags.SetValuesTmplFlag(p, &r.valuesTemplatePath)
+	p.BoolVar(&r.check, "check", r.check,
+		"Only report the releases health, failing when any is unhealthy")
+	flags.SetOutputFlag(p, &r.output)
+	return r
+}
@@ -0,0 +1,139 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// Replicate is the replicate subcommand, it keeps the integration secrets
+// replicas in sync with the secrets on the installer namespace.
+type Replicate struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	cfg      *config.Config // installer configuration
+	watch    bool           // keep synchronizing on an interval
+	interval time.Duration  // synchronization interval
+}
+
+var _ api.SubCommand = (*Replicate)(nil)
+
+const replicateDesc = `
+Replicates the integration secrets into the namespaces listed on the secret
+annotation "%s", for instance by creating the
+integration with "--replicate-to". Replicas carry the source payload hash, they
+are updated when the source changes, propagating credentials rotation, and
+removed when the source is gone or no longer lists the namespace.
+
+Replicas are synchronized when integrations are created and before each
+dependency is deployed. With --watch the synchronization runs continuously,
+suitable for a Deployment using the installer image.
+`
+
+// Cmd exposes the cobra instance.
+func (r *Replicate) Cmd() *cobra.Command {
+	return r.cmd
+}
+
+// log returns a decorated logger.
+func (r *Replicate) log() *slog.Logger {
+	return r.flags.LoggerWith(r.runCtx.Logger.With(
+		"watch", r.watch, "interval", r.interval))
+}
+
+// Complete loads the cluster configuration, to find the installer namespace.
+func (r *Replicate) Complete(_ []string) error {
+	var err error
+	r.cfg, err = bootstrapConfig(r.cmd.Context(), r.appCtx, r.runCtx)
+	return err
+}
+
+// Validate asserts the interval is valid.
+func (r *Replicate) Validate() error {
+	if r.watch && r.interval <= 0 {
+		return fmt.Errorf("invalid --interval %s, must be greater than zero",
+			r.interval)
+	}
+	return nil
+}
+
+// Run synchronizes the replicas, once or continuously.
+func (r *Replicate) Run() error {
+	ctx := r.cmd.Context()
+	replicator := integration.NewReplicator(
+		r.log(), r.runCtx.Kube, r.cfg.Namespace())
+	if !r.watch {
+		results, err := replicator.Sync(ctx)
+		table := tabwriter.NewWriter(r.cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintf(table, "Secret\tNamespace\tAction\n")
+		for _, res := range results {
+			fmt.Fprintf(table, "%s\t%s\t%s\n",
+				res.Source, res.Namespace, res.Action)
+		}
+		table.Flush()
+		return err
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		results, err := replicator.Sync(ctx)
+		if err != nil {
+			r.log().Error("Replicating integration secrets", "err", err)
+		}
+		for _, res := range results {
+			if res.Action == integration.ReplicaUnchanged {
+				continue
+			}
+			r.log().Info("Integration secret replica synchronized",
+				"secret", res.Source,
+				"namespace", res.Namespace,
+				"action", res.Action,
+			)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NewReplicate instantiates the replicate subcommand.
+func NewReplicate(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *Replicate {
+	r := &Replicate{
+		cmd: &cobra.Command{
+			Use:          "replicate",
+			Short:        "Replicates integration secrets into product namespaces",
+			Long:         fmt.Sprintf(replicateDesc, annotations.ReplicateTo),
+			SilenceUsage: true,
+		},
+		appCtx:   appCtx,
+		runCtx:   runCtx,
+		flags:    f,
+		interval: time.Minute,
+	}
+	p := r.cmd.PersistentFlags()
+	p.BoolVar(&r.watch, "watch", r.watch,
+		"Keep synchronizing the replicas on the interval")
+	p.DurationVar(&r.interval, "interval", r.interval,
+		"Synchronization interval, used with --watch")
+	return r
+}
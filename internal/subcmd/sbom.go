@@ -0,0 +1,227 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+	"github.com/redhat-appstudio/helmet/internal/sbom"
+
+	"github.com/spf13/cobra"
+)
+
+// SBOMGenerate represents the "sbom generate" subcommand, it generates the
+// software bill of materials of the charts and images of the resolved topology.
+type SBOMGenerate struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	collection         *resolver.Collection // chart collection
+	cfg                *config.Config       // installer configuration
+	dependencies       []string             // dependencies to include
+	format             string               // format flag
+	offline            bool                 // offline flag
+	valuesTemplatePath string               // values template file path
+	installerTarball   []byte               // embedded installer tarball
+	valuesContextFn    api.ValuesContextFn  // values template context
+}
+
+var _ api.SubCommand = (*SBOMGenerate)(nil)
+
+const sbomGenerateDesc = `
+Generates the software bill of materials (SBOM) of the installed stack, the
+charts of the resolved topology and the container images of their workloads,
+for the compliance workflows following the installation.
+
+The images are extracted from the rendered manifests, hooks included, rendered
+client-side as "helm template" does. Each image is then inspected on its
+registry, recording its digest, and the license and source from the image
+labels; the registry credentials are read from the Docker configuration. Images
+not reachable are reported without them, and "--offline" skips the registries.
+The chart licenses, SPDX license expressions, come from the chart annotation:
+
+  %s
+
+The formats are:
+
+  - cyclonedx: a CycloneDX 1.5 JSON document, default.
+  - spdx: an SPDX 2.3 JSON document.
+  - licenses: a license report, the components and their licenses.
+
+All the enabled dependencies are included by default, or only the dependencies
+informed, for instance:
+
+  $ %s sbom generate > sbom.cdx.json
+  $ %s sbom generate --format=spdx --offline > sbom.spdx.json
+  $ %s sbom generate <dependency> --format=licenses
+`
+
+// Cmd exposes the cobra instance.
+func (s *SBOMGenerate) Cmd() *cobra.Command {
+	return s.cmd
+}
+
+// log returns a decorated logger.
+func (s *SBOMGenerate) log() *slog.Logger {
+	return s.flags.LoggerWith(s.runCtx.Logger.With(
+		"dependencies", s.dependencies,
+		"format", s.format,
+		"offline", s.offline,
+		flags.ValuesTemplateFlag, s.valuesTemplatePath,
+	))
+}
+
+// Complete loads the charts and the cluster configuration.
+func (s *SBOMGenerate) Complete(args []string) error {
+	s.dependencies = args
+
+	charts, err := s.runCtx.ChartFS.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	if s.collection, err = resolver.NewCollection(s.appCtx, charts); err != nil {
+		return err
+	}
+	s.cfg, err = bootstrapConfig(s.cmd.Context(), s.appCtx, s.runCtx)
+	return err
+}
+
+// Validate asserts the document format is valid.
+func (s *SBOMGenerate) Validate() error {
+	if !slices.Contains(sbom.Formats, s.format) {
+		return fmt.Errorf("%w: %q, expected one of %s", sbom.ErrInvalidFormat,
+			s.format, strings.Join(sbom.Formats, ", "))
+	}
+	return nil
+}
+
+// Run renders the dependencies values and manifests, collects their images,
+// inspects them on the registries, and writes the document.
+func (s *SBOMGenerate) Run() error {
+	topology := resolver.NewTopology()
+	if err := resolver.NewResolver(s.cfg, s.collection, topology).Resolve(); err != nil {
+		return err
+	}
+	deps := topology.Dependencies()
+	if len(s.dependencies) > 0 {
+		deps = resolver.Dependencies{}
+		for _, name := range s.dependencies {
+			dep, err := topology.GetDependency(name)
+			if err != nil {
+				return err
+			}
+			deps = append(deps, *dep)
+		}
+	}
+
+	s.log().Debug("Reading values template file")
+	valuesTmpl, err := s.runCtx.ChartFS.ReadFile(s.valuesTemplatePath)
+	if err != nil {
+		return err
+	}
+	valuesContext, err := valuesContext(
+		s.cmd.Context(), s.valuesContextFn, s.runCtx, s.cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := s.cmd.Context()
+	doc := sbom.NewDocument(s.appCtx.Name, s.appCtx.Version)
+	for _, dep := range deps {
+		s.log().Debug("Extracting the dependency images", "dependency", dep.Name())
+		i := installer.NewInstaller(
+			s.log(), s.flags, s.runCtx.Kube, &dep, s.installerTarball)
+		i.SetValuesContext(valuesContext)
+		i.SetValueRefAllowlist(s.appCtx.ValueReferences)
+		i.SetManagedBy(s.appCtx.Name)
+		if err = i.SetValues(ctx, s.cfg, string(valuesTmpl)); err != nil {
+			return err
+		}
+		if err = i.RenderValues(ctx); err != nil {
+			return err
+		}
+		images, err := i.Images(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", dep.Name(), err)
+		}
+		err = doc.AddChart(&sbom.Component{
+			Name:      dep.Name(),
+			Version:   dep.Chart().Metadata.Version,
+			Namespace: dep.Namespace(),
+			License:   dep.License(),
+		}, images)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !s.offline {
+		for _, image := range doc.Images {
+			s.log().Debug("Inspecting the image", "image", image.Reference)
+			if err = image.Inspect(ctx); err != nil {
+				s.log().Warn("Image metadata unavailable", "error", err)
+			}
+		}
+	}
+	return doc.Write(s.cmd.OutOrStdout(), s.format)
+}
+
+// NewSBOMGenerate instantiates the "sbom generate" subcommand.
+func NewSBOMGenerate(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+	installerTarball []byte,
+	valuesContextFn api.ValuesContextFn,
+) *SBOMGenerate {
+	s := &SBOMGenerate{
+		cmd: &cobra.Command{
+			Use:   "generate [dependency...]",
+			Short: "Generates the SBOM of the charts and images installed",
+			Long: fmt.Sprintf(sbomGenerateDesc,
+				annotations.License, appCtx.Name, appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx:           appCtx,
+		runCtx:           runCtx,
+		flags:            f,
+		format:           sbom.FormatCycloneDX,
+		installerTarball: installerTarball,
+		valuesContextFn:  valuesContextFn,
+	}
+	p := s.cmd.PersistentFlags()
+	p.StringVar(&s.format, "format", s.format, fmt.Sprintf(
+		"Document format, one of %s", strings.Join(sbom.Formats, ", ")))
+	p.BoolVar(&s.offline, "offline", s.offline,
+		"Skip the image registries, the digests and labels aren't recorded")
+	flags.SetValuesTmplFlag(p, &s.valuesTemplatePath)
+	return s
+}
+
+// NewSBOM creates the "sbom" subcommand, grouping the software bill of
+// materials subcommands.
+func NewSBOM(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+	installerTarball []byte,
+	valuesContextFn api.ValuesContextFn,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sbom",
+		Short: "Software bill of materials of the installed stack",
+	}
+	cmd.AddCommand(api.NewRunner(NewSBOMGenerate(
+		appCtx, runCtx, f, installerTarball, valuesContextFn)).Cmd())
+	return cmd
+}
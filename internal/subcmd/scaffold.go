@@ -0,0 +1,226 @@
+package subcmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/constants"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+	"github.com/redhat-appstudio/helmet/internal/scaffold"
+
+	"github.com/spf13/cobra"
+)
+
+// ScaffoldProduct represents the "scaffold product" subcommand, it generates the
+// files needed to add a new product to a installer directory.
+type ScaffoldProduct struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	product      scaffold.Product // product to scaffold
+	installerDir string           // installer directory
+	cfg          *config.Config   // installer configuration file
+}
+
+var _ api.SubCommand = (*ScaffoldProduct)(nil)
+
+const scaffoldProductDesc = `
+Generates the files needed to add a new product to the installer directory, the
+directory containing the "charts", "config.yaml" and "values.yaml.tpl":
+
+  - charts/<product>: Helm chart skeleton, with the product annotations.
+  - config.yaml: new product entry, enabled by default.
+  - values.yaml.tpl: product section, wiring the product configuration.
+
+In dry-run mode the generated content is only shown: the chart files, the
+section appended to "values.yaml.tpl" and the product entry added to
+"config.yaml".
+`
+
+// Cmd exposes the cobra instance.
+func (s *ScaffoldProduct) Cmd() *cobra.Command {
+	return s.cmd
+}
+
+// log returns a decorated logger.
+func (s *ScaffoldProduct) log() *slog.Logger {
+	return s.flags.LoggerWith(s.runCtx.Logger.With(
+		"product", s.product.Name,
+		"installer-dir", s.installerDir,
+	))
+}
+
+// Complete loads the installer configuration file, and sets defaults.
+func (s *ScaffoldProduct) Complete(_ []string) error {
+	if s.product.Namespace == "" {
+		s.product.Namespace = s.product.ChartName()
+	}
+	var err error
+	s.cfg, err = config.NewConfigFromFile(
+		chartfs.New(os.DirFS(s.installerDir)),
+		constants.ConfigFilename,
+		s.appCtx.Namespace,
+		s.appCtx.IdentifierName(),
+	)
+	return err
+}
+
+// Validate asserts the product is valid and not present in the installer yet.
+func (s *ScaffoldProduct) Validate() error {
+	if err := s.product.Validate(); err != nil {
+		return err
+	}
+	if _, err := s.cfg.GetProduct(s.product.Name); err == nil {
+		return fmt.Errorf("product %q already exists in %q",
+			s.product.Name, constants.ConfigFilename)
+	}
+	chartDir := filepath.Join(s.installerDir, "charts", s.product.ChartName())
+	if _, err := os.Stat(chartDir); err == nil {
+		return fmt.Errorf("chart directory %q already exists", chartDir)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// printFile prints the payload, with a header naming it, in dry-run mode.
+func (s *ScaffoldProduct) printFile(name string, payload []byte) {
+	fmt.Printf("#\n# %s\n#\n\n%s\n", name, payload)
+}
+
+// writeFile writes the payload on the installer directory.
+func (s *ScaffoldProduct) writeFile(name string, payload []byte) error {
+	s.log().Debug("Writing file", "file", name)
+	p := filepath.Join(s.installerDir, name)
+	if err := os.MkdirAll(filepath.Dir(p), dirMode); err != nil {
+		return err
+	}
+	return os.WriteFile(p, payload, 0o644)
+}
+
+// Run generates the product files. Every file is rendered before writing any,
+// a failure leaves the installer directory untouched.
+func (s *ScaffoldProduct) Run() error {
+	files, err := s.product.ChartFiles()
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	// The product section appended to the values template.
+	section, err := s.product.ValuesTemplate()
+	if err != nil {
+		return err
+	}
+	valuesTmpl, err := os.ReadFile(
+		filepath.Join(s.installerDir, constants.ValuesFilename))
+	if err != nil {
+		return err
+	}
+
+	// The product entry added to the installer configuration.
+	entry, err := s.product.ConfigEntry()
+	if err != nil {
+		return err
+	}
+	if err = s.product.AddToConfig(s.cfg); err != nil {
+		return err
+	}
+	payload, err := s.cfg.MarshalYAML()
+	if err != nil {
+		return err
+	}
+
+	// In dry-run mode only the generated content is shown, not the existing
+	// files it's added to.
+	if s.flags.DryRun {
+		for _, name := range names {
+			s.printFile(filepath.Join("charts", name), files[name])
+		}
+		s.printFile(constants.ValuesFilename+" (appended)", section)
+		s.printFile(constants.ConfigFilename+" (products entry)", entry)
+		return nil
+	}
+
+	for _, name := range names {
+		if err = s.writeFile(filepath.Join("charts", name), files[name]); err != nil {
+			return err
+		}
+	}
+	err = s.writeFile(constants.ValuesFilename, append(valuesTmpl, section...))
+	if err != nil {
+		return err
+	}
+	if err = s.writeFile(constants.ConfigFilename, payload); err != nil {
+		return err
+	}
+	fmt.Printf("Product %q scaffolded on chart %q.\n",
+		s.product.Name, s.product.ChartName())
+	return nil
+}
+
+// NewScaffoldProduct instantiates the "scaffold product" subcommand.
+func NewScaffoldProduct(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *ScaffoldProduct {
+	s := &ScaffoldProduct{
+		cmd: &cobra.Command{
+			Use:          "product --name <name>",
+			Short:        "Generates a new product chart and configuration",
+			Long:         scaffoldProductDesc,
+			SilenceUsage: true,
+		},
+		appCtx: appCtx,
+		runCtx: runCtx,
+		flags:  f,
+	}
+	p := s.cmd.PersistentFlags()
+	p.StringVar(&s.product.Name, "name", "", "Product name")
+	p.StringVar(&s.product.Namespace, "namespace", "",
+		"Product namespace, defaults to the chart name")
+	p.IntVar(&s.product.Weight, "weight", 0, "Chart weight")
+	p.StringSliceVar(&s.product.DependsOn, "depends-on", nil,
+		"Charts the product depends on")
+	p.StringSliceVar(&s.product.IntegrationsProvided, "integrations-provided",
+		nil, "Integrations provided by the product")
+	p.StringVar(&s.product.IntegrationsRequired, "integrations-required", "",
+		"CEL expression with the integrations required by the product")
+	p.StringVar(&s.installerDir, "installer-dir", ".",
+		"Installer directory, with charts, config and values template")
+	if err := s.cmd.MarkPersistentFlagRequired("name"); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// NewScaffold instantiates the "scaffold" command, grouping the generators to
+// extend the installer.
+func NewScaffold(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scaffold",
+		Short: "Generates installer resources",
+	}
+	cmd.AddCommand(api.NewRunner(NewScaffoldProduct(appCtx, runCtx, f)).Cmd())
+	return cmd
+}
@@ -0,0 +1,206 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/redhat-appstudio/helmet/api"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+	"github.com/redhat-appstudio/helmet/internal/scan"
+
+	"github.com/spf13/cobra"
+)
+
+// Scan represents the "scan" subcommand, it applies the security policy on the
+// rendered manifests of the dependencies, without deploying.
+type Scan struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	collection         *resolver.Collection // chart collection
+	cfg                *config.Config       // installer configuration
+	policy             *scan.Policy         // security policy
+	dependencies       []string             // dependencies to scan
+	mode               string               // mode flag
+	rules              []string             // rules flag
+	valuesTemplatePath string               // values template file path
+	installerTarball   []byte               // embedded installer tarball
+	valuesContextFn    api.ValuesContextFn  // values template context
+	output             string               // output format flag
+	out                *printer.Output      // output printer
+}
+
+var _ api.SubCommand = (*Scan)(nil)
+
+const scanDesc = `
+Scans the rendered manifests of the dependencies against the embedded security
+policy, without deploying. The manifests, hooks included, are rendered
+client-side, as "helm template" does. The rules are:
+
+  - privileged: containers running privileged, or allowing privilege
+    escalation.
+  - host-path: pods mounting volumes from the node filesystem.
+  - resource-limits: containers without CPU or memory limits.
+
+The mode and rules default to the "%s" setting, the flags take precedence. In
+"enforce" mode findings fail the command, in "warn" mode they're only reported.
+The same policy gates the "deploy" subcommand, when its mode isn't "off".
+
+All the enabled dependencies are scanned by default, or only the dependencies
+informed, for instance:
+
+  $ %s scan --mode=enforce
+  $ %s scan <dependency> --rules=privileged,host-path --output=json
+`
+
+// Cmd exposes the cobra instance.
+func (s *Scan) Cmd() *cobra.Command {
+	return s.cmd
+}
+
+// log returns a decorated logger.
+func (s *Scan) log() *slog.Logger {
+	return s.flags.LoggerWith(s.runCtx.Logger.With(
+		"dependencies", s.dependencies,
+		"mode", s.mode,
+		"rules", s.rules,
+		flags.ValuesTemplateFlag, s.valuesTemplatePath,
+	))
+}
+
+// Complete loads the charts and the cluster configuration.
+func (s *Scan) Complete(args []string) error {
+	s.dependencies = args
+
+	charts, err := s.runCtx.ChartFS.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	if s.collection, err = resolver.NewCollection(s.appCtx, charts); err != nil {
+		return err
+	}
+	s.cfg, err = bootstrapConfig(s.cmd.Context(), s.appCtx, s.runCtx)
+	return err
+}
+
+// Validate asserts the policy and output format are valid, the flags take
+// precedence over the configuration.
+func (s *Scan) Validate() error {
+	var err error
+	if s.policy, err = scan.NewPolicyFromConfig(s.cfg); err != nil {
+		return err
+	}
+	if s.policy.Mode == scan.ModeOff {
+		s.policy.Mode = scan.ModeWarn
+	}
+	if s.mode != "" {
+		if s.policy.Mode, err = scan.ParseMode(s.mode); err != nil {
+			return err
+		}
+	}
+	if len(s.rules) > 0 {
+		if s.policy.Rules, err = scan.ParseRules(s.rules); err != nil {
+			return err
+		}
+	}
+	s.out, err = printer.NewOutput(s.output)
+	return err
+}
+
+// Run renders the dependencies values and manifests, and scans them.
+func (s *Scan) Run() error {
+	topology := resolver.NewTopology()
+	if err := resolver.NewResolver(s.cfg, s.collection, topology).Resolve(); err != nil {
+		return err
+	}
+	deps := topology.Dependencies()
+	if len(s.dependencies) > 0 {
+		deps = resolver.Dependencies{}
+		for _, name := range s.dependencies {
+			dep, err := topology.GetDependency(name)
+			if err != nil {
+				return err
+			}
+			deps = append(deps, *dep)
+		}
+	}
+
+	s.log().Debug("Reading values template file")
+	valuesTmpl, err := s.runCtx.ChartFS.ReadFile(s.valuesTemplatePath)
+	if err != nil {
+		return err
+	}
+	valuesContext, err := valuesContext(
+		s.cmd.Context(), s.valuesContextFn, s.runCtx, s.cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := s.cmd.Context()
+	findings := []scan.Finding{}
+	for _, dep := range deps {
+		s.log().Debug("Scanning the dependency", "dependency", dep.Name())
+		i := installer.NewInstaller(
+			s.log(), s.flags, s.runCtx.Kube, &dep, s.installerTarball)
+		i.SetValuesContext(valuesContext)
+		i.SetValueRefAllowlist(s.appCtx.ValueReferences)
+		i.SetManagedBy(s.appCtx.Name)
+		if err = i.SetValues(ctx, s.cfg, string(valuesTmpl)); err != nil {
+			return err
+		}
+		if err = i.RenderValues(ctx); err != nil {
+			return err
+		}
+		found, err := i.Scan(ctx, s.policy)
+		if err != nil {
+			return fmt.Errorf("%s: %w", dep.Name(), err)
+		}
+		findings = append(findings, found...)
+	}
+
+	if s.out.Table() {
+		scan.PrintReport(s.cmd.OutOrStdout(), findings)
+	} else if err = s.out.Print(s.cmd.OutOrStdout(), findings); err != nil {
+		return err
+	}
+	return s.policy.Enforce(findings)
+}
+
+// NewScan instantiates the "scan" subcommand.
+func NewScan(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+	installerTarball []byte,
+	valuesContextFn api.ValuesContextFn,
+) *Scan {
+	s := &Scan{
+		cmd: &cobra.Command{
+			Use:   "scan [dependency...]",
+			Short: "Scans the rendered manifests against the security policy",
+			Long: fmt.Sprintf(scanDesc,
+				scan.Setting, appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx:           appCtx,
+		runCtx:           runCtx,
+		flags:            f,
+		installerTarball: installerTarball,
+		valuesContextFn:  valuesContextFn,
+	}
+	p := s.cmd.PersistentFlags()
+	p.StringVar(&s.mode, "mode", s.mode,
+		"Policy mode, 'warn' or 'enforce', defaults to the configuration")
+	p.StringSliceVar(&s.rules, "rules", s.rules,
+		"Rules to apply, defaults to the configuration or all rules")
+	flags.SetValuesTmplFlag(p, &s.valuesTemplatePath)
+	flags.SetOutputFlag(p, &s.output)
+	return s
+}
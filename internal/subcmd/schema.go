@@ -0,0 +1,134 @@
+package subcmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// SchemaPrint represents the "schema print" subcommand, it prints the JSON
+// Schema of the configuration file.
+type SchemaPrint struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	collection *resolver.Collection // chart collection
+}
+
+var _ api.SubCommand = (*SchemaPrint)(nil)
+
+const schemaPrintDesc = `
+Prints the JSON Schema of the %s configuration file, describing the settings
+registered by %s, the products shipped by the charts, and each product's
+properties, as declared by the product chart.
+
+Editors use the schema to validate and complete the configuration files kept
+in Git, before they reach "config apply". The schema doesn't need the cluster,
+store it next to the configuration, for instance:
+
+  $ %s schema print > config.schema.json
+
+And reference it on the configuration file, for editors relying on the YAML
+language server:
+
+  # yaml-language-server: $schema=./config.schema.json
+`
+
+// Cmd exposes the cobra instance.
+func (s *SchemaPrint) Cmd() *cobra.Command {
+	return s.cmd
+}
+
+// log returns a decorated logger.
+func (s *SchemaPrint) log() *slog.Logger {
+	return s.flags.LoggerWith(s.runCtx.Logger)
+}
+
+// Complete loads the charts.
+func (s *SchemaPrint) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: unexpected arguments %v",
+			helmeterrors.ErrInvalidUsage, args)
+	}
+	charts, err := s.runCtx.ChartFS.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	s.collection, err = resolver.NewCollection(s.appCtx, charts)
+	return err
+}
+
+// Validate implements api.SubCommand, there's nothing to validate.
+func (s *SchemaPrint) Validate() error {
+	return nil
+}
+
+// Run prints the configuration schema, with the properties schema of each
+// product.
+func (s *SchemaPrint) Run() error {
+	products := map[string]config.PropertiesSchema{}
+	for _, name := range s.collection.ProductNames() {
+		d, err := s.collection.GetProductDependency(name)
+		if err != nil {
+			return err
+		}
+		if products[name], err = d.PropertiesSchema(); err != nil {
+			return err
+		}
+	}
+	s.log().Debug("Printing the configuration schema", "products", len(products))
+	schema := config.JSONSchema(
+		s.appCtx.IdentifierName(), s.appCtx.Settings, products)
+	payload, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(s.cmd.OutOrStdout(), string(payload))
+	return err
+}
+
+// NewSchemaPrint instantiates the "schema print" subcommand.
+func NewSchemaPrint(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *SchemaPrint {
+	return &SchemaPrint{
+		cmd: &cobra.Command{
+			Use:   "print",
+			Short: "Prints the configuration file JSON Schema",
+			Long: fmt.Sprintf(schemaPrintDesc,
+				appCtx.Name, appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx: appCtx,
+		runCtx: runCtx,
+		flags:  f,
+	}
+}
+
+// NewSchema creates the "schema" subcommand, grouping the configuration schema
+// subcommands.
+func NewSchema(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Configuration file schema",
+	}
+	cmd.AddCommand(api.NewRunner(NewSchemaPrint(appCtx, runCtx, f)).Cmd())
+	return cmd
+}
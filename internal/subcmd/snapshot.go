@@ -0,0 +1,139 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/integrations"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+	"github.com/redhat-appstudio/helmet/internal/snapshot"
+
+	"github.com/spf13/cobra"
+)
+
+// SnapshotCapture represents the "snapshot capture" subcommand, it records the
+// cluster objects relevant for planning the deployment.
+type SnapshotCapture struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	manager    *integrations.Manager // integrations manager
+	output     string                // snapshot file path
+	secretData bool                  // record the integration secrets data
+}
+
+var _ api.SubCommand = (*SnapshotCapture)(nil)
+
+const snapshotCaptureDesc = `
+Records the cluster objects relevant for planning the deployment on a YAML file:
+the installer configuration, the integration secrets, the installer and product
+namespaces, and the OpenShift attributes exposed to the values template.
+
+The integration secrets data is redacted, only their presence is recorded,
+unless --secret-data is informed.
+
+The snapshot is used by "deploy --against-snapshot" to reproduce the deployment
+planning offline, without cluster access.
+`
+
+// Cmd exposes the cobra instance.
+func (s *SnapshotCapture) Cmd() *cobra.Command {
+	return s.cmd
+}
+
+// log returns a decorated logger.
+func (s *SnapshotCapture) log() *slog.Logger {
+	return s.flags.LoggerWith(s.runCtx.Logger.With(
+		"output", s.output, "secret-data", s.secretData))
+}
+
+// Complete noop.
+func (s *SnapshotCapture) Complete(_ []string) error {
+	return nil
+}
+
+// Validate asserts the output file is informed.
+func (s *SnapshotCapture) Validate() error {
+	if s.output == "" {
+		return fmt.Errorf("%w: missing --output file",
+			helmeterrors.ErrInvalidUsage)
+	}
+	return nil
+}
+
+// Run captures the snapshot and writes it on the output file.
+func (s *SnapshotCapture) Run() error {
+	secrets := []string{}
+	for _, name := range s.manager.IntegrationNames() {
+		secrets = append(secrets, integrations.SecretName(s.appCtx.Name, name))
+	}
+	s.log().Debug("Capturing the cluster snapshot")
+	cm := config.NewConfigMapManager(s.runCtx.Kube, s.appCtx.Name)
+	cm.SetBackend(s.appCtx.ConfigBackend)
+	snap, err := snapshot.Capture(
+		s.cmd.Context(),
+		s.runCtx.Kube,
+		cm,
+		secrets,
+		s.secretData,
+	)
+	if err != nil {
+		return err
+	}
+	if err = snap.Save(s.output); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.cmd.OutOrStdout(), "Snapshot with %d objects written to %q\n",
+		len(snap.Objects), s.output)
+	return nil
+}
+
+// NewSnapshotCapture instantiates the "snapshot capture" subcommand.
+func NewSnapshotCapture(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+	manager *integrations.Manager,
+) *SnapshotCapture {
+	s := &SnapshotCapture{
+		cmd: &cobra.Command{
+			Use:          "capture",
+			Short:        "Records the cluster objects relevant for the deployment",
+			Long:         snapshotCaptureDesc,
+			SilenceUsage: true,
+		},
+		appCtx:  appCtx,
+		runCtx:  runCtx,
+		flags:   f,
+		manager: manager,
+		output:  fmt.Sprintf("%s-snapshot.yaml", appCtx.Name),
+	}
+	p := s.cmd.PersistentFlags()
+	p.StringVarP(&s.output, "output", "o", s.output, "Snapshot file path")
+	p.BoolVar(&s.secretData, "secret-data", s.secretData,
+		"Record the integration secrets data, redacted by default")
+	return s
+}
+
+// NewSnapshot instantiates the "snapshot" command, grouping the cluster snapshot
+// helpers for offline deployment simulation.
+func NewSnapshot(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+	manager *integrations.Manager,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Cluster snapshots for offline deployment simulation",
+	}
+	cmd.AddCommand(
+		api.NewRunner(NewSnapshotCapture(appCtx, runCtx, f, manager)).Cmd())
+	return cmd
+}
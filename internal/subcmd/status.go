@@ -0,0 +1,194 @@
+package subcmd
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// Status represents the "status" subcommand, it lists the deploy runs recorded
+// on the deployment history, or shows the installation as it was after one.
+type Status struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	cfg    *config.Config  // installer configuration
+	at     string          // deploy run ID
+	output string          // output format flag
+	out    *printer.Output // output printer
+}
+
+var _ api.SubCommand = (*Status)(nil)
+
+const statusDesc = `
+Shows the installation as it was after a previous deploy run, to triage the
+regressions introduced by a later change. Each "deploy" records its run on the
+deployment history, identified by its start time, with the configuration hash
+and the releases of every dependency once the run is done: chart version,
+release revision and status, and the outcome of the dependencies deployed.
+
+Without --at the recorded runs are listed, most recent first. With --at the run
+releases are printed, followed by the releases changed since, up to the latest
+run, and whether the configuration changed. The latest %d runs are kept, and
+"--at=latest" refers to the most recent. For instance:
+
+  $ %s status
+  $ %s status --at=20260102-030405
+  $ %s status --at=latest --output=json
+`
+
+// Cmd exposes the cobra instance.
+func (s *Status) Cmd() *cobra.Command {
+	return s.cmd
+}
+
+// log returns a decorated logger.
+func (s *Status) log() *slog.Logger {
+	return s.flags.LoggerWith(s.runCtx.Logger.With(
+		"at", s.at, flags.OutputFlag, s.output))
+}
+
+// Complete loads the cluster configuration.
+func (s *Status) Complete(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: unexpected arguments: %v",
+			helmeterrors.ErrInvalidUsage, args)
+	}
+	var err error
+	s.cfg, err = bootstrapConfig(s.cmd.Context(), s.appCtx, s.runCtx)
+	return err
+}
+
+// Validate asserts the output format is valid.
+func (s *Status) Validate() error {
+	var err error
+	s.out, err = printer.NewOutput(s.output)
+	return err
+}
+
+// printRun prints the run, its releases and the changes since, up to the latest
+// run.
+func (s *Status) printRun(w io.Writer, run, latest *installer.Run) error {
+	hash, err := s.cfg.Hash()
+	if err != nil {
+		return err
+	}
+	configuration := "current configuration"
+	if run.ConfigHash != hash {
+		configuration = "differs from the current configuration"
+	}
+	outcome := run.Outcome()
+	if run.Error != "" {
+		outcome += ": " + run.Error
+	}
+
+	header := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(header, "Run:\t%s\n", run.ID)
+	fmt.Fprintf(header, "Time:\t%s\n", run.Time.Format(time.RFC3339))
+	fmt.Fprintf(header, "Config:\t%s (%s)\n",
+		installer.ShortHash(run.ConfigHash), configuration)
+	fmt.Fprintf(header, "Outcome:\t%s\n\n", outcome)
+	if err = header.Flush(); err != nil {
+		return err
+	}
+	run.PrintReleases(w)
+
+	if run.ID == latest.ID {
+		fmt.Fprintf(w, "\nThis is the latest deploy run.\n")
+		return nil
+	}
+	changes := installer.DiffRuns(run, latest)
+	if run.ConfigHash != latest.ConfigHash {
+		fmt.Fprintf(w, "\nThe configuration changed up to the latest run %q, "+
+			"%s.\n", latest.ID, installer.ShortHash(latest.ConfigHash))
+	}
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "\nNo releases changed up to the latest run %q.\n",
+			latest.ID)
+		return nil
+	}
+	fmt.Fprintf(w, "\nReleases changed up to the latest run %q:\n\n", latest.ID)
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "Dependency\tBefore\tAfter\n")
+	for _, c := range changes {
+		fmt.Fprintf(table, "%s\t%s\t%s\n", c.Dependency, c.Before, c.After)
+	}
+	return table.Flush()
+}
+
+// Run reads the deployment history, listing the runs or showing the informed
+// one.
+func (s *Status) Run() error {
+	s.log().Debug("Reading the deployment history")
+	history := installer.NewHistory(
+		s.runCtx.Kube, s.cfg.Namespace(), s.appCtx.Name)
+	if err := history.Load(s.cmd.Context()); err != nil {
+		return err
+	}
+	w := s.cmd.OutOrStdout()
+	runs := history.Runs()
+	if s.at == "" {
+		if !s.out.Table() {
+			return s.out.Print(w, runs)
+		}
+		if len(runs) == 0 {
+			fmt.Fprintf(w, "No deploy runs recorded yet, run %q first.\n",
+				s.appCtx.Name+" deploy")
+			return nil
+		}
+		installer.PrintRuns(w, runs)
+		return nil
+	}
+
+	run, err := history.GetRun(s.at)
+	if err != nil {
+		return err
+	}
+	if !s.out.Table() {
+		return s.out.Print(w, run.Releases)
+	}
+	latest, err := history.GetRun(installer.LatestRun)
+	if err != nil {
+		return err
+	}
+	return s.printRun(w, run, latest)
+}
+
+// NewStatus instantiates the "status" subcommand.
+func NewStatus(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *Status {
+	s := &Status{
+		cmd: &cobra.Command{
+			Use:   "status [--at=run] [flags]",
+			Short: "Shows the installation as it was after a deploy run",
+			Long: fmt.Sprintf(statusDesc, installer.RunsSize,
+				appCtx.Name, appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx: appCtx,
+		runCtx: runCtx,
+		flags:  f,
+	}
+	p := s.cmd.PersistentFlags()
+	p.StringVar(&s.at, "at", s.at,
+		"Deploy run ID, or \"latest\", lists the recorded runs when empty")
+	flags.SetOutputFlag(p, &s.output)
+	return s
+}
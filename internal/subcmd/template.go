@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
 	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/flags"
 	"github.com/redhat-appstudio/helmet/internal/installer"
@@ -27,6 +28,7 @@ type Template struct {
 	namespace          string              // dependency namespace
 	dep                resolver.Dependency // chart to render
 	installerTarball   []byte              // embedded installer tarball
+	valuesContextFn    api.ValuesContextFn // values template context
 }
 
 var _ api.SubCommand = (*Template)(nil)
@@ -43,7 +45,8 @@ func (t *Template) Complete(args []string) error {
 	t.flags.DryRun = true
 
 	if len(args) != 1 {
-		return fmt.Errorf("expecting one chart, got %d", len(args))
+		return fmt.Errorf("%w: expecting one chart, got %d",
+			helmeterrors.ErrInvalidUsage, len(args))
 	}
 
 	hc, err := t.runCtx.ChartFS.GetChartFiles(args[0])
@@ -64,10 +67,12 @@ func (t *Template) Validate() error {
 		return nil
 	}
 	if !t.flags.DryRun {
-		return fmt.Errorf("template command is only available in dry-run mode")
+		return fmt.Errorf("%w: template command is only available in dry-run mode",
+			helmeterrors.ErrInvalidUsage)
 	}
 	if t.dep.Chart() == nil {
-		return fmt.Errorf("missing chart path")
+		return fmt.Errorf("%w: missing chart path",
+			helmeterrors.ErrInvalidUsage)
 	}
 	return nil
 }
@@ -80,6 +85,13 @@ func (t *Template) Run() error {
 	}
 
 	i := installer.NewInstaller(t.runCtx.Logger, t.flags, t.runCtx.Kube, &t.dep, t.installerTarball)
+	valuesContext, err := valuesContext(
+		t.cmd.Context(), t.valuesContextFn, t.runCtx, t.cfg)
+	if err != nil {
+		return err
+	}
+	i.SetValuesContext(valuesContext)
+	i.SetValueRefAllowlist(t.appCtx.ValueReferences)
 
 	if err = i.SetValues(
 		t.cmd.Context(),
@@ -90,7 +102,7 @@ func (t *Template) Run() error {
 	}
 
 	// Rendering the global values.
-	if err = i.RenderValues(); err != nil {
+	if err = i.RenderValues(t.cmd.Context()); err != nil {
 		return err
 	}
 	// Show the rendered global values, what's passed into very chart.
@@ -114,6 +126,7 @@ func NewTemplate(
 	runCtx *runcontext.RunContext,
 	f *flags.Flags,
 	installerTarball []byte,
+	valuesContextFn api.ValuesContextFn,
 ) *Template {
 	templateDesc := fmt.Sprintf(`
 The Template subcommand is used to render the values template file and,
@@ -163,6 +176,7 @@ Examples:
 		showManifests:    true,
 		namespace:        "default",
 		installerTarball: installerTarball,
+		valuesContextFn:  valuesContextFn,
 	}
 
 	p := t.cmd.PersistentFlags()
@@ -1,10 +1,13 @@
 package subcmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/redhat-appstudio/helmet/api"
 	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/printer"
 	"github.com/redhat-appstudio/helmet/internal/resolver"
 	"github.com/redhat-appstudio/helmet/internal/runcontext"
 
@@ -20,6 +23,27 @@ type Topology struct {
 
 	collection *resolver.Collection // chart collection
 	cfg        *config.Config       // installer configuration
+	output     string               // output format flag
+	detail     bool                 // show the chart annotations in detail
+	out        *printer.Output      // output printer
+}
+
+// topologyItem a dependency in the topology, as printed by the output formats.
+type topologyItem struct {
+	Index                int      `json:"index"`
+	Dependency           string   `json:"dependency"`
+	Namespace            string   `json:"namespace"`
+	Product              string   `json:"product,omitempty"`
+	DependsOn            []string `json:"dependsOn,omitempty"`
+	Weight               int      `json:"weight"`
+	ProvidedIntegrations []string `json:"providedIntegrations,omitempty"`
+	RequiredIntegrations string   `json:"requiredIntegrations,omitempty"`
+
+	// Detailed listing, the chart annotations driving the resolution.
+	NamespaceRule    string   `json:"namespaceRule,omitempty"`
+	NamespacePolicy  string   `json:"namespacePolicy,omitempty"`
+	ProductRequires  []string `json:"productRequires,omitempty"`
+	ProductConflicts []string `json:"productConflicts,omitempty"`
 }
 
 var _ api.SubCommand = (*Topology)(nil)
@@ -35,6 +59,15 @@ and Helm charts. It will output a table with the following columns:
   - Depends-On: comma-separated list of charts the chart depends on.
   - Provided-Integrations: comma-separated integrations provided by the chart.
   - Required-Integrations: CEL expressions with the required integrations.
+
+With --detail, each chart is listed with the annotations driving the resolution,
+including how its namespace is chosen, the namespace policy and the products it
+requires or conflicts with, to audit the chart annotations.
+
+Scripts can extract exactly the fields they need with --output, for instance:
+
+  $ %s topology -o jsonpath='{.items[*].dependency}'
+  $ %s topology -o custom-columns=NAME:.dependency,NS:.namespace
 `
 
 // Cmd exposes the cobra instance.
@@ -57,22 +90,54 @@ func (t *Topology) Complete(_ []string) error {
 	return nil
 }
 
-// Validate validates the command.
+// Validate asserts the output format is valid.
 func (t *Topology) Validate() error {
-	return nil
+	var err error
+	t.out, err = printer.NewOutput(t.output)
+	return err
 }
 
 // Run resolves the dependency graph.
 func (t *Topology) Run() error {
 	// Resolving the dependency topology based on the installer configuration and
 	// Helm charts.
-	r := resolver.NewResolver(t.cfg, t.collection, resolver.NewTopology())
+	topology := resolver.NewTopology()
+	r := resolver.NewResolver(t.cfg, t.collection, topology)
 	if err := r.Resolve(); err != nil {
 		return err
 	}
 	// Printing the resolved dependency to the standard output.
-	r.Print(os.Stdout)
-	return nil
+	if t.out.Table() {
+		if t.detail {
+			r.PrintDetail(os.Stdout)
+		} else {
+			r.Print(os.Stdout)
+		}
+		return nil
+	}
+	items := []topologyItem{}
+	for i, d := range topology.Dependencies() {
+		weight, _ := d.Weight()
+		item := topologyItem{
+			Index:                i + 1,
+			Dependency:           d.Name(),
+			Namespace:            d.Namespace(),
+			Product:              d.ProductName(),
+			DependsOn:            d.DependsOn(),
+			Weight:               weight,
+			ProvidedIntegrations: d.IntegrationsProvided(),
+			RequiredIntegrations: d.IntegrationsRequired(),
+		}
+		if t.detail {
+			policy, _ := d.NamespacePolicy()
+			item.NamespaceRule = d.NamespaceRule()
+			item.NamespacePolicy = string(policy)
+			item.ProductRequires = d.ProductRequires()
+			item.ProductConflicts = d.ProductConflicts()
+		}
+		items = append(items, item)
+	}
+	return t.out.Print(os.Stdout, items)
 }
 
 // NewTopology instantiates a new Topology subcommand.
@@ -84,11 +149,15 @@ func NewTopology(
 		cmd: &cobra.Command{
 			Use:          "topology",
 			Short:        "Shows the installer topology",
-			Long:         topologyDesc,
+			Long:         fmt.Sprintf(topologyDesc, appCtx.Name, appCtx.Name),
 			SilenceUsage: true,
 		},
 		appCtx: appCtx,
 		runCtx: runCtx,
 	}
+	p := t.cmd.PersistentFlags()
+	flags.SetOutputFlag(p, &t.output)
+	p.BoolVar(&t.detail, "detail", t.detail,
+		"Show the chart annotations driving the resolution")
 	return t
 }
@@ -0,0 +1,213 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+	"text/tabwriter"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/resolver"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// ValuesExplain represents the "values explain" subcommand, it shows the
+// provenance of a dependency's final Helm value.
+type ValuesExplain struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	collection         *resolver.Collection // chart collection
+	cfg                *config.Config       // installer configuration
+	dependency         string               // dependency name
+	key                string               // values key, dot separated
+	valuesTemplatePath string               // values template file path
+	installerTarball   []byte               // embedded installer tarball
+	valuesContextFn    api.ValuesContextFn  // values template context
+	output             string               // output format flag
+	out                *printer.Output      // output printer
+}
+
+var _ api.SubCommand = (*ValuesExplain)(nil)
+
+const valuesExplainDesc = `
+Shows where the final value of a key, on the Helm values of a dependency, comes
+from. The value is searched on each layer, from the lowest to the highest
+precedence:
+
+  - chart default: the chart's, and its subcharts', "values.yaml".
+  - sizing profile: the chart's values preset for the selected sizing profile.
+  - installer template: the rendered values template (--values-template).
+  - product properties: the properties of the dependency's product, consumed by
+    the values template.
+
+The key is dot separated, for instance "--key storage.class". Use it when
+debugging why a product rendered unexpectedly, for instance:
+
+  $ %s values explain <dependency> --key path.to.key
+`
+
+// Cmd exposes the cobra instance.
+func (v *ValuesExplain) Cmd() *cobra.Command {
+	return v.cmd
+}
+
+// log returns a decorated logger.
+func (v *ValuesExplain) log() *slog.Logger {
+	return v.flags.LoggerWith(v.runCtx.Logger.With(
+		"dependency", v.dependency,
+		"key", v.key,
+		flags.ValuesTemplateFlag, v.valuesTemplatePath,
+	))
+}
+
+// Complete loads the charts and the cluster configuration.
+func (v *ValuesExplain) Complete(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: expecting one dependency, got %d",
+			helmeterrors.ErrInvalidUsage, len(args))
+	}
+	v.dependency = args[0]
+
+	charts, err := v.runCtx.ChartFS.GetAllCharts()
+	if err != nil {
+		return err
+	}
+	if v.collection, err = resolver.NewCollection(v.appCtx, charts); err != nil {
+		return err
+	}
+	v.cfg, err = bootstrapConfig(v.cmd.Context(), v.appCtx, v.runCtx)
+	return err
+}
+
+// Validate asserts the key and output format are valid.
+func (v *ValuesExplain) Validate() error {
+	if v.key == "" {
+		return fmt.Errorf("%w: missing --key", helmeterrors.ErrInvalidUsage)
+	}
+	var err error
+	v.out, err = printer.NewOutput(v.output)
+	return err
+}
+
+// Run renders the dependency values and explains the key.
+func (v *ValuesExplain) Run() error {
+	topology := resolver.NewTopology()
+	if err := resolver.NewResolver(v.cfg, v.collection, topology).Resolve(); err != nil {
+		return err
+	}
+	dep, err := topology.GetDependency(v.dependency)
+	if err != nil {
+		return err
+	}
+
+	v.log().Debug("Reading values template file")
+	valuesTmpl, err := v.runCtx.ChartFS.ReadFile(v.valuesTemplatePath)
+	if err != nil {
+		return err
+	}
+	valuesContext, err := valuesContext(
+		v.cmd.Context(), v.valuesContextFn, v.runCtx, v.cfg)
+	if err != nil {
+		return err
+	}
+
+	i := installer.NewInstaller(
+		v.log(), v.flags, v.runCtx.Kube, dep, v.installerTarball)
+	i.SetValuesContext(valuesContext)
+	i.SetValueRefAllowlist(v.appCtx.ValueReferences)
+	if err = i.SetValues(v.cmd.Context(), v.cfg, string(valuesTmpl)); err != nil {
+		return err
+	}
+	if err = i.RenderValues(v.cmd.Context()); err != nil {
+		return err
+	}
+	v.log().Debug("Explaining the values key")
+	e, err := i.Explain(v.cmd.Context(), v.cfg, string(valuesTmpl), v.key)
+	if err != nil {
+		return err
+	}
+
+	if !v.out.Table() {
+		return v.out.Print(v.cmd.OutOrStdout(), []*installer.Explanation{e})
+	}
+	return printExplanation(v.cmd, e)
+}
+
+// printExplanation prints the final value and its layers as a table.
+func printExplanation(cmd *cobra.Command, e *installer.Explanation) error {
+	source := e.Source
+	if source == "" {
+		source = "not set"
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Key:\t%s\n", e.Key)
+	fmt.Fprintf(w, "Value:\t%v\n", e.Value)
+	fmt.Fprintf(w, "Source:\t%s\n", source)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "LAYER\tSET\tVALUE")
+	for _, layer := range e.Layers {
+		value := "-"
+		if layer.Set {
+			value = fmt.Sprintf("%v", layer.Value)
+		}
+		fmt.Fprintf(w, "%s\t%t\t%s\n", layer.Layer, layer.Set, value)
+	}
+	return w.Flush()
+}
+
+// NewValuesExplain instantiates the "values explain" subcommand.
+func NewValuesExplain(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+	installerTarball []byte,
+	valuesContextFn api.ValuesContextFn,
+) *ValuesExplain {
+	v := &ValuesExplain{
+		cmd: &cobra.Command{
+			Use:          "explain <dependency>",
+			Short:        "Shows the provenance of a dependency's Helm value",
+			Long:         fmt.Sprintf(valuesExplainDesc, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx:           appCtx,
+		runCtx:           runCtx,
+		flags:            f,
+		installerTarball: installerTarball,
+		valuesContextFn:  valuesContextFn,
+	}
+	p := v.cmd.PersistentFlags()
+	p.StringVar(&v.key, "key", v.key,
+		"Values key to explain, dot separated, for instance 'storage.class'")
+	flags.SetValuesTmplFlag(p, &v.valuesTemplatePath)
+	flags.SetOutputFlag(p, &v.output)
+	return v
+}
+
+// NewValues creates the "values" subcommand, grouping the Helm values
+// troubleshooting subcommands.
+func NewValues(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+	installerTarball []byte,
+	valuesContextFn api.ValuesContextFn,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "values",
+		Short: "Helm values troubleshooting",
+	}
+	cmd.AddCommand(api.NewRunner(NewValuesExplain(
+		appCtx, runCtx, f, installerTarball, valuesContextFn,
+	)).Cmd())
+	return cmd
+}
@@ -0,0 +1,147 @@
+package subcmd
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/redhat-appstudio/helmet/api"
+	helmeterrors "github.com/redhat-appstudio/helmet/api/errors"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/flags"
+	"github.com/redhat-appstudio/helmet/internal/printer"
+	"github.com/redhat-appstudio/helmet/internal/readiness"
+	"github.com/redhat-appstudio/helmet/internal/runcontext"
+
+	"github.com/spf13/cobra"
+)
+
+// Verify represents the "verify" subcommand, it runs the verification checks
+// registered by the application against the cluster.
+type Verify struct {
+	cmd    *cobra.Command // cobra command
+	appCtx *api.AppContext
+	runCtx *runcontext.RunContext
+	flags  *flags.Flags
+
+	cfg      *config.Config      // installer configuration
+	checkers []api.CheckerModule // checks to run
+	names    []string            // checks informed
+	output   string              // output format flag
+	out      *printer.Output     // output printer
+}
+
+var _ api.SubCommand = (*Verify)(nil)
+
+const verifyDesc = `
+Runs the product verification checks registered by %s against the cluster,
+validating the deployed products are working as expected. Each check reports
+whether it passed, and the details, the command fails when any check fails.
+
+All the registered checks are run by default, in registration order, or only
+the checks informed, for instance:
+
+  $ %s verify
+  $ %s verify <check> --output=json
+
+Release health, the release status and workloads, is checked by "%s repair
+--check" instead.
+`
+
+// Cmd exposes the cobra instance.
+func (v *Verify) Cmd() *cobra.Command {
+	return v.cmd
+}
+
+// log returns a decorated logger.
+func (v *Verify) log() *slog.Logger {
+	return v.flags.LoggerWith(v.runCtx.Logger.With("checks", v.names))
+}
+
+// Complete loads the cluster configuration, and selects the checks to run.
+func (v *Verify) Complete(args []string) error {
+	v.names = args
+	v.checkers = v.appCtx.Checkers
+	if len(v.names) > 0 {
+		v.checkers = []api.CheckerModule{}
+		for _, m := range v.appCtx.Checkers {
+			if slices.Contains(v.names, m.Name) {
+				v.checkers = append(v.checkers, m)
+			}
+		}
+	}
+	var err error
+	v.cfg, err = bootstrapConfig(v.cmd.Context(), v.appCtx, v.runCtx)
+	return err
+}
+
+// Validate asserts the informed checks are registered, and the output format.
+func (v *Verify) Validate() error {
+	for _, name := range v.names {
+		if !slices.ContainsFunc(v.checkers, func(m api.CheckerModule) bool {
+			return m.Name == name
+		}) {
+			return fmt.Errorf("%w: unknown check %q",
+				helmeterrors.ErrInvalidUsage, name)
+		}
+	}
+	var err error
+	v.out, err = printer.NewOutput(v.output)
+	return err
+}
+
+// printChecks prints the checks outcome, on the output format.
+func (v *Verify) printChecks(checks readiness.Checks) error {
+	if !v.out.Table() {
+		return v.out.Print(v.cmd.OutOrStdout(), checks)
+	}
+	table := tabwriter.NewWriter(v.cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "CHECK\tRESULT\tMESSAGE")
+	for _, c := range checks {
+		result := "passed"
+		if !c.Passed {
+			result = "failed"
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\n", c.Name, result, c.Message)
+	}
+	return table.Flush()
+}
+
+// Run runs the checks, failing when any of them fails.
+func (v *Verify) Run() error {
+	if len(v.checkers) == 0 {
+		fmt.Fprintf(v.cmd.ErrOrStderr(),
+			"No verification checks are registered by %s.\n", v.appCtx.Name)
+		return nil
+	}
+	v.log().Debug("Running the verification checks")
+	checks := readiness.RunChecks(
+		v.cmd.Context(), v.runCtx.Kube, v.cfg, v.checkers)
+	if err := v.printChecks(checks); err != nil {
+		return err
+	}
+	return checks.Err()
+}
+
+// NewVerify instantiates the "verify" subcommand.
+func NewVerify(
+	appCtx *api.AppContext,
+	runCtx *runcontext.RunContext,
+	f *flags.Flags,
+) *Verify {
+	v := &Verify{
+		cmd: &cobra.Command{
+			Use:   "verify [check...]",
+			Short: "Runs the product verification checks",
+			Long: fmt.Sprintf(verifyDesc,
+				appCtx.Name, appCtx.Name, appCtx.Name, appCtx.Name),
+			SilenceUsage: true,
+		},
+		appCtx: appCtx,
+		runCtx: runCtx,
+		flags:  f,
+	}
+	flags.SetOutputFlag(v.cmd.PersistentFlags(), &v.output)
+	return v
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/integration"
+)
+
+// Chat the chat channel receiving the deploy events as human readable
+// messages, configured by the notification integration.
+type Chat struct {
+	Provider string   // chat provider, slack or teams
+	URL      string   // incoming webhook URL
+	Channel  string   // channel posted to
+	Events   []string // deploy events posted
+}
+
+// Subscribed returns true when the event is posted to the channel.
+func (c *Chat) Subscribed(event string) bool {
+	return slices.Contains(c.Events, event)
+}
+
+// Text summarizes the deploy event payload as a chat message.
+func (c *Chat) Text(payload *Payload) string {
+	var b strings.Builder
+	switch payload.Event {
+	case config.WebhookEventStarted:
+		fmt.Fprintf(&b, "%s: deploying %d dependencies on %q.",
+			payload.App, len(payload.Dependencies), payload.Namespace)
+		return b.String()
+	case config.WebhookEventCompleted:
+		fmt.Fprintf(&b, "%s: deployment completed on %q.",
+			payload.App, payload.Namespace)
+	default:
+		fmt.Fprintf(&b, "%s: deployment failed on %q: %s",
+			payload.App, payload.Namespace, payload.Error)
+	}
+	for _, dep := range payload.Dependencies {
+		fmt.Fprintf(&b, "\n- %s (%s)", dep.Name, dep.Namespace)
+		details := slices.DeleteFunc(
+			[]string{dep.Status, dep.Duration, dep.Error},
+			func(s string) bool { return s == "" },
+		)
+		if len(details) > 0 {
+			fmt.Fprintf(&b, ": %s", strings.Join(details, ", "))
+		}
+	}
+	return b.String()
+}
+
+// Message returns the provider message posting the deploy event. Slack takes
+// the text itself, Teams takes the text on an Adaptive Card.
+func (c *Chat) Message(payload *Payload) ([]byte, error) {
+	text := c.Text(payload)
+	if c.Provider == integration.NotificationTeams {
+		return json.Marshal(map[string]any{
+			"type": "message",
+			"attachments": []any{map[string]any{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]any{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body": []any{map[string]any{
+						"type": "TextBlock",
+						"text": strings.ReplaceAll(text, "\n", "\n\n"),
+						"wrap": true,
+					}},
+				},
+			}},
+		})
+	}
+	message := map[string]any{"text": text}
+	if c.Channel != "" {
+		message["channel"] = c.Channel
+	}
+	return json.Marshal(message)
+}
+
+// post posts the deploy event message to the channel.
+func (n *Notifier) post(ctx context.Context, payload *Payload) error {
+	body, err := n.chat.Message(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, n.chat.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %q", res.Status)
+	}
+	return nil
+}
+
+// NewChat instantiates the chat channel from the notification integration
+// secret data.
+func NewChat(data map[string][]byte) (*Chat, error) {
+	c := &Chat{
+		Provider: string(data[integration.NotificationProviderKey]),
+		URL:      string(data[integration.NotificationWebhookURLKey]),
+		Channel:  string(data[integration.NotificationChannelKey]),
+	}
+	if !slices.Contains(integration.NotificationProviders, c.Provider) {
+		return nil, fmt.Errorf("invalid notification provider %q", c.Provider)
+	}
+	if c.URL == "" {
+		return nil, fmt.Errorf("notification webhook URL not found")
+	}
+	events := string(data[integration.NotificationDeployEventsKey])
+	if events != "" {
+		c.Events = strings.Split(events, ",")
+	}
+	return c, nil
+}
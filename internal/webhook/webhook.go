@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/installer"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// EventHeader HTTP header carrying the deploy event name.
+	EventHeader = "X-Helmet-Event"
+	// SignatureHeader HTTP header carrying the payload signature, formatted as
+	// "sha256=<hex HMAC-SHA256 of the body>".
+	SignatureHeader = "X-Helmet-Signature-256"
+)
+
+// DefaultTimeout default timeout for a single webhook delivery.
+const DefaultTimeout = 10 * time.Second
+
+// ErrDelivery the webhook endpoint could not be notified.
+var ErrDelivery = errors.New("webhook delivery failed")
+
+// Dependency the deployment outcome of a single dependency, on the payload.
+type Dependency struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Status    string `json:"status,omitempty"`
+	Attempts  int    `json:"attempts,omitempty"`
+	Failure   string `json:"failure,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Duration  string `json:"duration,omitempty"`
+}
+
+// Payload the JSON document posted to the webhooks.
+type Payload struct {
+	Event        string       `json:"event"`
+	App          string       `json:"app"`
+	Namespace    string       `json:"namespace"`
+	Timestamp    time.Time    `json:"timestamp"`
+	Dependencies []Dependency `json:"dependencies"`
+	Error        string       `json:"error,omitempty"`
+}
+
+// Notifier delivers the deploy events to the webhooks configured.
+type Notifier struct {
+	logger    *slog.Logger     // application logger
+	kube      k8s.Interface    // kubernetes client
+	client    *http.Client     // http client
+	appName   string           // application name
+	namespace string           // installer's namespace
+	webhooks  []config.Webhook // configured webhooks
+	chat      *Chat            // chat channel, optional
+}
+
+// SetHTTPClient overwrites the HTTP client used to deliver the events.
+func (n *Notifier) SetHTTPClient(client *http.Client) {
+	n.client = client
+}
+
+// SetChat sets the chat channel receiving the deploy events subscribed.
+func (n *Notifier) SetChat(chat *Chat) {
+	n.chat = chat
+}
+
+// Sign returns the payload signature using the secret, as sent on the
+// SignatureHeader.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewDependencies converts the deployment results into payload dependencies.
+func NewDependencies(results []installer.Result) []Dependency {
+	deps := make([]Dependency, 0, len(results))
+	for _, r := range results {
+		dep := Dependency{
+			Name:      r.Name,
+			Namespace: r.Namespace,
+			Status:    string(r.Status),
+			Attempts:  r.Attempts,
+			Failure:   string(r.Class()),
+			Duration:  r.Duration.Round(time.Second).String(),
+		}
+		if r.Err != nil {
+			dep.Error = r.Err.Error()
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+// secret reads the HMAC secret referenced by the webhook.
+func (n *Notifier) secret(
+	ctx context.Context,
+	ref *config.WebhookSecretRef,
+) ([]byte, error) {
+	secret, err := k8s.GetSecret(ctx, n.kube, types.NamespacedName{
+		Namespace: n.namespace,
+		Name:      ref.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	data, ok := secret.Data[ref.GetKey()]
+	if !ok || len(data) == 0 {
+		return nil, fmt.Errorf("secret %s/%s: key %q not found",
+			n.namespace, ref.Name, ref.GetKey())
+	}
+	return data, nil
+}
+
+// deliver posts the payload body to a single webhook.
+func (n *Notifier) deliver(
+	ctx context.Context,
+	webhook *config.Webhook,
+	event string,
+	body []byte,
+) error {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	if webhook.SecretRef != nil {
+		secret, err := n.secret(ctx, webhook.SecretRef)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %q", res.Status)
+	}
+	return nil
+}
+
+// Notify delivers the event to every webhook subscribing to it, and posts it
+// to the chat channel when subscribed. The
+// dependencies describe the deployment scope, or its outcome on the final
+// events, and the error explains a failed deployment. Delivery failures don't
+// stop the remaining webhooks, they are returned combined.
+func (n *Notifier) Notify(
+	ctx context.Context,
+	event string,
+	deps []Dependency,
+	deployErr error,
+) error {
+	payload := Payload{
+		Event:        event,
+		App:          n.appName,
+		Namespace:    n.namespace,
+		Timestamp:    time.Now().UTC(),
+		Dependencies: deps,
+	}
+	if deployErr != nil {
+		payload.Error = deployErr.Error()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range n.webhooks {
+		webhook := &n.webhooks[i]
+		if !webhook.Subscribed(event) {
+			continue
+		}
+		logger := n.logger.With("webhook", webhook.Name, "event", event)
+		logger.Debug("Delivering the deploy event")
+		if err := n.deliver(ctx, webhook, event, body); err != nil {
+			logger.Warn("Failed to deliver the deploy event", "err", err)
+			errs = append(errs, fmt.Errorf("%w: %q: %w",
+				ErrDelivery, webhook.Name, err))
+		}
+	}
+	if n.chat != nil && n.chat.Subscribed(event) {
+		logger := n.logger.With("chat", n.chat.Provider, "event", event)
+		logger.Debug("Posting the deploy event")
+		if err := n.post(ctx, &payload); err != nil {
+			logger.Warn("Failed to post the deploy event", "err", err)
+			errs = append(errs, fmt.Errorf("%w: %s: %w",
+				ErrDelivery, n.chat.Provider, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// NewNotifier instantiates the notifier with the webhooks configured.
+func NewNotifier(
+	logger *slog.Logger,
+	kube k8s.Interface,
+	appName string,
+	cfg *config.Config,
+) *Notifier {
+	return &Notifier{
+		logger:    logger,
+		kube:      kube,
+		client:    &http.Client{Timeout: DefaultTimeout},
+		appName:   appName,
+		namespace: cfg.Namespace(),
+		webhooks:  cfg.Installer.Webhooks,
+	}
+}
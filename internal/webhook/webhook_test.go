@@ -0,0 +1,185 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/redhat-appstudio/helmet/internal/chartfs"
+	"github.com/redhat-appstudio/helmet/internal/config"
+	"github.com/redhat-appstudio/helmet/internal/k8s"
+
+	o "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNotifier(t *testing.T) {
+	g := o.NewWithT(t)
+	ctx := context.Background()
+
+	type delivery struct {
+		event     string
+		signature string
+		body      []byte
+	}
+	var deliveries []delivery
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			deliveries = append(deliveries, delivery{
+				event:     r.Header.Get(EventHeader),
+				signature: r.Header.Get(SignatureHeader),
+				body:      body,
+			})
+			if r.URL.Path == "/fail" {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		},
+	))
+	defer server.Close()
+
+	cfs := chartfs.New(os.DirFS("../../test"))
+	cfg, err := config.NewConfigFromFile(
+		cfs, "config.yaml", "test-namespace", "helmet_ex")
+	g.Expect(err).To(o.Succeed())
+	cfg.Installer.Webhooks = []config.Webhook{{
+		Name:      "cmdb",
+		URL:       server.URL + "/cmdb",
+		SecretRef: &config.WebhookSecretRef{Name: "cmdb-webhook"},
+	}, {
+		Name:   "ticketing",
+		URL:    server.URL + "/fail",
+		Events: []string{config.WebhookEventFailed},
+	}}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cmdb-webhook",
+			Namespace: "test-namespace",
+		},
+		Data: map[string][]byte{"secret": []byte("s3cr3t")},
+	}
+	n := NewNotifier(slog.New(slog.NewTextHandler(io.Discard, nil)),
+		k8s.NewFakeKube(secret), "helmet-ex", cfg)
+
+	deps := []Dependency{{Name: "helmet-foundation", Namespace: "default"}}
+
+	t.Run("Signed", func(t *testing.T) {
+		deliveries = nil
+		err := n.Notify(ctx, config.WebhookEventStarted, deps, nil)
+		g.Expect(err).To(o.Succeed())
+		g.Expect(deliveries).To(o.HaveLen(1))
+
+		d := deliveries[0]
+		g.Expect(d.event).To(o.Equal(config.WebhookEventStarted))
+		g.Expect(d.signature).To(o.Equal(Sign([]byte("s3cr3t"), d.body)))
+
+		var payload Payload
+		g.Expect(json.Unmarshal(d.body, &payload)).To(o.Succeed())
+		g.Expect(payload.Event).To(o.Equal(config.WebhookEventStarted))
+		g.Expect(payload.App).To(o.Equal("helmet-ex"))
+		g.Expect(payload.Namespace).To(o.Equal("test-namespace"))
+		g.Expect(payload.Dependencies).To(o.Equal(deps))
+		g.Expect(payload.Error).To(o.BeEmpty())
+	})
+
+	t.Run("DeliveryFailure", func(t *testing.T) {
+		deliveries = nil
+		err := n.Notify(ctx, config.WebhookEventFailed, deps,
+			errors.New("deploy failed"))
+		g.Expect(err).To(o.MatchError(ErrDelivery))
+		g.Expect(deliveries).To(o.HaveLen(2))
+		g.Expect(deliveries[1].signature).To(o.BeEmpty())
+
+		var payload Payload
+		g.Expect(json.Unmarshal(deliveries[0].body, &payload)).To(o.Succeed())
+		g.Expect(payload.Error).To(o.Equal("deploy failed"))
+	})
+
+	t.Run("Chat", func(t *testing.T) {
+		deliveries = nil
+		chat, err := NewChat(map[string][]byte{
+			"provider":      []byte("slack"),
+			"webhook-url":   []byte(server.URL + "/slack"),
+			"channel":       []byte("#deployments"),
+			"deploy-events": []byte("deploy.completed"),
+		})
+		g.Expect(err).To(o.Succeed())
+		n.SetChat(chat)
+		defer n.SetChat(nil)
+
+		g.Expect(n.Notify(ctx, config.WebhookEventStarted, deps, nil)).
+			To(o.Succeed())
+		g.Expect(deliveries).To(o.HaveLen(1))
+
+		deliveries = nil
+		g.Expect(n.Notify(ctx, config.WebhookEventCompleted, deps, nil)).
+			To(o.Succeed())
+		g.Expect(deliveries).To(o.HaveLen(2))
+		g.Expect(deliveries[1].event).To(o.BeEmpty())
+		g.Expect(string(deliveries[1].body)).To(o.MatchJSON(`{
+			"channel": "#deployments",
+			"text": "helmet-ex: deployment completed on \"test-namespace\".\n` +
+			`- helmet-foundation (default)"
+		}`))
+	})
+}
+
+func TestChat(t *testing.T) {
+	g := o.NewWithT(t)
+
+	_, err := NewChat(map[string][]byte{"provider": []byte("irc")})
+	g.Expect(err).To(o.HaveOccurred())
+	_, err = NewChat(map[string][]byte{"provider": []byte("slack")})
+	g.Expect(err).To(o.HaveOccurred())
+
+	chat, err := NewChat(map[string][]byte{
+		"provider":    []byte("teams"),
+		"webhook-url": []byte("https://example.webhook.office.com/webhookb2"),
+	})
+	g.Expect(err).To(o.Succeed())
+	g.Expect(chat.Subscribed(config.WebhookEventFailed)).To(o.BeFalse())
+
+	payload := &Payload{
+		Event:     config.WebhookEventFailed,
+		App:       "helmet-ex",
+		Namespace: "test-namespace",
+		Dependencies: []Dependency{{
+			Name:      "helmet-foundation",
+			Namespace: "default",
+			Status:    "failed",
+			Duration:  "1m0s",
+			Error:     "timed out",
+		}},
+		Error: "deploy failed",
+	}
+	g.Expect(chat.Text(payload)).To(o.Equal(
+		"helmet-ex: deployment failed on \"test-namespace\": deploy failed\n" +
+			"- helmet-foundation (default): failed, 1m0s, timed out"))
+
+	body, err := chat.Message(payload)
+	g.Expect(err).To(o.Succeed())
+	var message struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Body []struct {
+					Text string `json:"text"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	g.Expect(json.Unmarshal(body, &message)).To(o.Succeed())
+	g.Expect(message.Type).To(o.Equal("message"))
+	g.Expect(message.Attachments).To(o.HaveLen(1))
+	g.Expect(message.Attachments[0].Content.Body[0].Text).
+		To(o.ContainSubstring("deploy failed\n\n- helmet-foundation"))
+}
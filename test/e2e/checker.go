@@ -1,17 +1,13 @@
 package e2e
 
-import "context"
+import "github.com/redhat-appstudio/helmet/api"
 
-// Checker defines the interface for cluster state validation components.
-type Checker interface {
-	Check(ctx context.Context) Result
-}
+// Checker defines the interface for cluster state validation components. It's
+// the same interface applications register with api.WithCheckers.
+type Checker = api.Checker
 
 // Result represents the outcome of a checker validation.
-type Result struct {
-	Passed  bool   // true if validation succeeded
-	Message string // descriptive message (error details if Passed=false)
-}
+type Result = api.CheckResult
 
 // NewResult creates a successful result with an optional message.
 func NewResult(message string) Result {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/redhat-appstudio/helmet/internal/annotations"
+	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/internal/constants"
 	"gopkg.in/yaml.v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,18 +41,19 @@ func (c *ConfigChecker) Check(ctx context.Context) Result {
 		)
 	}
 
-	// Verify config.yaml data key exists and is non-empty.
-	configData, ok := cm.Data[constants.ConfigFilename]
-	if !ok || configData == "" {
+	// Verify config.yaml payload exists and is non-empty, either plain or
+	// compressed.
+	configData, err := config.ConfigMapPayload(cm)
+	if err != nil {
 		return NewFailedResult(
-			fmt.Errorf("ConfigMap %q has no %q data",
-				cmName, constants.ConfigFilename),
+			fmt.Errorf("ConfigMap %q has no %q data: %w",
+				cmName, constants.ConfigFilename, err),
 		)
 	}
 
 	// Parse YAML to verify product definitions exist.
 	var parsed map[string]any
-	if err := yaml.Unmarshal([]byte(configData), &parsed); err != nil {
+	if err := yaml.Unmarshal(configData, &parsed); err != nil {
 		return NewFailedResult(
 			fmt.Errorf("failed to parse %s from ConfigMap %q: %w",
 				constants.ConfigFilename, cmName, err),
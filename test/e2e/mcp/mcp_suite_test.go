@@ -47,10 +47,10 @@ var _ = BeforeSuite(func(ctx context.Context) {
 	By("performing MCP initialize handshake")
 	Expect(client.Initialize(ctx)).To(Succeed())
 
-	By("verifying all 13 tools are registered")
+	By("verifying all 20 tools are registered")
 	tools, err := client.ListTools(ctx)
 	Expect(err).NotTo(HaveOccurred())
-	Expect(tools).To(HaveLen(13))
+	Expect(tools).To(HaveLen(20))
 })
 
 var _ = AfterSuite(func() {
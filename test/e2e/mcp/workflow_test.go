@@ -13,6 +13,7 @@ import (
 	"helm.sh/helm/v3/pkg/release"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/redhat-appstudio/helmet/internal/config"
 	"github.com/redhat-appstudio/helmet/test/e2e"
 )
 
@@ -132,7 +133,9 @@ func phaseConfiguration(
 		Get(ctx, "helmet-ex-config", metav1.GetOptions{})
 	Expect(err).NotTo(HaveOccurred())
 
-	configYAML := cm.Data["config.yaml"]
+	payload, err := config.ConfigMapPayload(cm)
+	Expect(err).NotTo(HaveOccurred())
+	configYAML := string(payload)
 	Expect(configYAML).To(ContainSubstring("crc: true"))
 
 	products := configYAMLProducts(configYAML)
@@ -177,6 +180,13 @@ func phaseIntegrations(ctx context.Context, mc *e2e.MCPClient, r *e2e.Runner) {
 		map[string]any{"names": []string{"acs", "quay"}})
 	Expect(result.Text()).To(ContainSubstring("OVERWRITE_ME"))
 
+	By("describing integration capabilities via MCP")
+	result = mc.CallTool(ctx, "helmet_ex_integration_describe",
+		map[string]any{"names": []string{"acs", "quay"}})
+	Expect(result.IsError).To(BeFalse())
+	Expect(result.Text()).To(ContainSubstring("`--endpoint`"))
+	Expect(result.Text()).To(ContainSubstring("Required by:"))
+
 	By("configuring acs integration via CLI")
 	Expect(r.Integration(ctx, "acs",
 		"--force",
@@ -298,4 +308,30 @@ var _ = Describe("MCP Installer Workflow", func() {
 			phaseDeploy(ctx, client)
 			phasePostDeployValidation(ctx, client, sharedCtx)
 		})
+
+	It("reports the injected tool failures to the assistant",
+		func(ctx context.Context) {
+			By("starting an MCP server failing the status and deploy tools")
+			failing, err := runner.StartMCPServer(
+				context.Background(), e2e.MCPTestImage(),
+				"--fail-tool=helmet_ex_status=simulated API server outage",
+				"--fail-tool=helmet_ex_deploy=simulated deploy failure",
+			)
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(func() { _ = failing.Shutdown() })
+			Expect(failing.Initialize(ctx)).To(Succeed())
+
+			By("asserting the failing tools report the injected errors")
+			result := failing.CallTool(ctx, "helmet_ex_status", nil)
+			Expect(result.IsError).To(BeTrue())
+			Expect(result.Text()).To(Equal("simulated API server outage"))
+			result = failing.CallTool(ctx, "helmet_ex_deploy", nil)
+			Expect(result.IsError).To(BeTrue())
+			Expect(result.Text()).To(Equal("simulated deploy failure"))
+
+			By("asserting the other tools are not affected")
+			result = failing.CallTool(ctx, "helmet_ex_integration_list", nil)
+			Expect(result.IsError).To(BeFalse())
+			Expect(result.Text()).To(ContainSubstring("acs"))
+		})
 })
@@ -91,12 +91,13 @@ func (r *Runner) Deploy(ctx context.Context) error {
 // StartMCPServer launches the MCP server as a long-lived subprocess and returns a
 // client connected to its STDIO pipes. The Runner's projectRoot and binaryPath
 // are reused; the image argument is passed as --image to the mcp-server
-// subcommand.
+// subcommand, followed by the extra arguments, like "--fail-tool".
 func (r *Runner) StartMCPServer(
 	ctx context.Context,
 	image string,
+	args ...string,
 ) (*MCPClient, error) {
-	cmd := r.newCmd(ctx, "mcp-server", "--image", image)
+	cmd := r.newCmd(ctx, append([]string{"mcp-server", "--image", image}, args...)...)
 	cmd.Stderr = io.Discard
 
 	stdin, err := cmd.StdinPipe()